@@ -0,0 +1,102 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+// Service reads account credit balance and usage.
+type Service struct {
+	apiClient *api.Client
+}
+
+func NewService(apiClient *api.Client) *Service {
+	return &Service{apiClient: apiClient}
+}
+
+// Balance is the normalized remaining credit.
+type Balance struct {
+	Credits  float64 `json:"credits"`
+	Currency string  `json:"currency"`
+}
+
+// UsageItem is one normalized usage row.
+type UsageItem struct {
+	Date    string  `json:"date"`
+	Model   string  `json:"model"`
+	Tasks   int     `json:"tasks"`
+	Credits float64 `json:"credits"`
+}
+
+// Usage summarizes spending over a period.
+type Usage struct {
+	Days    int         `json:"days"`
+	Total   float64     `json:"total"`
+	Items   []UsageItem `json:"items"`
+	Balance *Balance    `json:"balance,omitempty"`
+}
+
+// Balance returns the remaining credit for the authenticated account or project.
+func (s *Service) Balance(ctx context.Context, headers map[string]string) (Balance, error) {
+	var resp api.BalanceResponse
+	if err := s.apiClient.PostJSON(ctx, "/Account/Balance", map[string]interface{}{}, headers, &resp); err != nil {
+		return Balance{}, err
+	}
+	if !resp.Result && len(resp.Errors) > 0 {
+		return Balance{}, fmt.Errorf("balance failed: %w", api.ResponseError(resp.Errors))
+	}
+	credits, err := toFloat(resp.Balance)
+	if err != nil {
+		return Balance{}, fmt.Errorf("parse balance: %w", err)
+	}
+	return Balance{Credits: credits, Currency: resp.Currency}, nil
+}
+
+// Usage returns per-day, per-model spending for the last days.
+func (s *Service) Usage(ctx context.Context, days int, headers map[string]string) (Usage, error) {
+	if days <= 0 {
+		days = 30
+	}
+	var resp api.UsageResponse
+	body := map[string]interface{}{"days": strconv.Itoa(days)}
+	if err := s.apiClient.PostJSON(ctx, "/Account/Usage", body, headers, &resp); err != nil {
+		return Usage{}, err
+	}
+	if !resp.Result && len(resp.Errors) > 0 {
+		return Usage{}, fmt.Errorf("usage failed: %w", api.ResponseError(resp.Errors))
+	}
+	out := Usage{Days: days, Items: make([]UsageItem, 0, len(resp.Usage))}
+	for _, row := range resp.Usage {
+		credits, _ := toFloat(row.Spent)
+		tasks, _ := toFloat(row.TaskCount)
+		out.Items = append(out.Items, UsageItem{
+			Date:    row.Date,
+			Model:   strings.Trim(row.SlugOwner+"/"+row.SlugProject, "/"),
+			Tasks:   int(tasks),
+			Credits: credits,
+		})
+		out.Total += credits
+	}
+	return out, nil
+}
+
+// toFloat accepts the API's mix of numeric and string-encoded numbers.
+func toFloat(v interface{}) (float64, error) {
+	switch t := v.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return t, nil
+	case string:
+		if strings.TrimSpace(t) == "" {
+			return 0, nil
+		}
+		return strconv.ParseFloat(strings.TrimSpace(t), 64)
+	default:
+		return 0, fmt.Errorf("unexpected numeric value %v", v)
+	}
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// CachedResponse is a response body kept with the validators the server sent for it.
+type CachedResponse struct {
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"lastModified,omitempty"`
+	Body         json.RawMessage `json:"body"`
+}
+
+// ResponseCache stores responses of conditional requests under a key derived
+// from the endpoint and request body.
+type ResponseCache interface {
+	Get(key string) (CachedResponse, bool)
+	Put(key string, r CachedResponse)
+}
+
+// SetResponseCache makes PostJSONCached revalidate stored responses with
+// If-None-Match and If-Modified-Since; nil turns caching off.
+func (c *Client) SetResponseCache(cache ResponseCache) {
+	c.cache = cache
+}
+
+// ResponseCacheKey is the key a POST of payload to path is cached under.
+func ResponseCacheKey(path string, payload []byte) string {
+	sum := sha256.Sum256(append([]byte(path+"\n"), payload...))
+	return hex.EncodeToString(sum[:])
+}
+
+// PostJSONCached is PostJSON for responses that rarely change. A response
+// with an ETag or Last-Modified is stored, and when the server answers a
+// later identical request with 304 Not Modified, the stored body is decoded
+// into out instead. The key ignores headers, so only use it for responses
+// that do not depend on who asks.
+func (c *Client) PostJSONCached(ctx context.Context, path string, body interface{}, headers map[string]string, out interface{}) error {
+	if c.cache == nil {
+		return c.PostJSON(ctx, path, body, headers, out)
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal request body: %w", err)
+	}
+	key := ResponseCacheKey(path, payload)
+	cached, ok := c.cache.Get(key)
+	if ok {
+		validators := map[string]string{}
+		if cached.ETag != "" {
+			validators["If-None-Match"] = cached.ETag
+		}
+		if cached.LastModified != "" {
+			validators["If-Modified-Since"] = cached.LastModified
+		}
+		headers = replaceHeaders(headers, validators)
+	}
+
+	resp, bodyBytes, err := c.postPayload(ctx, path, payload, headers)
+	if err != nil {
+		return err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotModified && ok:
+		bodyBytes = cached.Body
+	case resp.StatusCode == http.StatusNotModified:
+		// Nothing was cached, so there is no body to reuse.
+		return &Error{Kind: ErrServer, Status: resp.StatusCode, Message: "not modified, but no cached response to reuse"}
+	default:
+		if err := checkStatus(resp, bodyBytes); err != nil {
+			return err
+		}
+	}
+	if out != nil {
+		if err := json.Unmarshal(bodyBytes, out); err != nil {
+			return fmt.Errorf("decode response json: %w; body=%s", err, string(bodyBytes))
+		}
+	}
+	if resp.StatusCode == http.StatusOK {
+		etag, modified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+		if (etag != "" || modified != "") && json.Valid(bodyBytes) {
+			c.cache.Put(key, CachedResponse{ETag: etag, LastModified: modified, Body: bodyBytes})
+		}
+	}
+	return nil
+}
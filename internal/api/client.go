@@ -1,27 +1,91 @@
 package api
 
 import (
+	"bufio"
 	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
+
+	"github.com/wiro-ai/wiro-cli/internal/log"
+	"github.com/wiro-ai/wiro-cli/internal/metrics"
+	"github.com/wiro-ai/wiro-cli/internal/telemetry"
 )
 
 const defaultBaseURL = "https://api.wiro.ai/v1"
 
 // Client wraps HTTP operations against Wiro API.
 type Client struct {
-	baseURL    string
-	httpClient *http.Client
+	baseURL          string
+	httpClient       *http.Client
+	downloadClient   *http.Client
+	maxResponseBytes int64
+	refreshToken     TokenRefresher
+	resign           Resigner
+	limiter          *rateLimiter
+	cache            ResponseCache
+}
+
+// TokenRefresher returns a replacement bearer token after the server rejected
+// the one in rejected (the request's headers); ok is false when none is available.
+type TokenRefresher func(ctx context.Context, rejected map[string]string) (token string, ok bool)
+
+// Resigner returns fresh signature headers after the server rejected the
+// signature in rejected; serverDate is the response's Date header.
+type Resigner func(rejected map[string]string, serverDate string) (signed map[string]string, ok bool)
+
+// SetResigner makes requests whose signature or nonce was rejected retry once
+// with the headers fn returns.
+func (c *Client) SetResigner(fn Resigner) {
+	c.resign = fn
 }
 
+type noRefreshKey struct{}
+
+// WithoutTokenRefresh marks ctx so a 401 is returned as is instead of refreshing.
+func WithoutTokenRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRefreshKey{}, true)
+}
+
+// SetTokenRefresher makes requests rejected with 401 under bearer auth retry
+// once with the token fn returns.
+func (c *Client) SetTokenRefresher(fn TokenRefresher) {
+	c.refreshToken = fn
+}
+
+const (
+	downloadAttempts      = 3
+	downloadHeaderTimeout = 60 * time.Second
+
+	// DefaultMaxResponseBytes caps decoded API response bodies (not file downloads).
+	DefaultMaxResponseBytes = 32 << 20
+)
+
+// SetMaxResponseBytes changes the API response size cap; n <= 0 restores the default.
+func (c *Client) SetMaxResponseBytes(n int64) {
+	if n <= 0 {
+		n = DefaultMaxResponseBytes
+	}
+	c.maxResponseBytes = n
+}
+
+// ProgressFunc receives the bytes transferred so far and the total size (-1 when unknown).
+type ProgressFunc func(done, total int64)
+
 // MultipartValue represents one multipart item (file or scalar value).
 type MultipartValue struct {
 	FilePath string
@@ -33,12 +97,42 @@ func NewClient(baseURL string) *Client {
 	if strings.TrimSpace(baseURL) == "" {
 		baseURL = defaultBaseURL
 	}
-	return &Client{
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = downloadHeaderTimeout
+	c := &Client{
 		baseURL: strings.TrimRight(baseURL, "/"),
 		httpClient: &http.Client{
 			Timeout: 45 * time.Second,
 		},
+		maxResponseBytes: DefaultMaxResponseBytes,
+		limiter:          newRateLimiter(),
 	}
+	// Downloads can be large, so they are bounded by ctx and header timeout instead of a total timeout.
+	c.downloadClient = &http.Client{Transport: transport, CheckRedirect: c.checkDownloadRedirect}
+	return c
+}
+
+// authHeaders are the credentials a request may carry. Go only drops
+// Authorization and Cookie on a redirect to another host.
+var authHeaders = []string{"Authorization", "Cookie", "x-api-key", "x-nonce", "x-signature"}
+
+// checkDownloadRedirect keeps credentials off redirects that leave Wiro, since
+// output URLs often redirect to third-party storage.
+func (c *Client) checkDownloadRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	if !c.isWiroHost(req.URL.Hostname()) {
+		for _, h := range authHeaders {
+			req.Header.Del(h)
+		}
+	}
+	return nil
+}
+
+// BaseURL returns the API endpoint requests are sent to.
+func (c *Client) BaseURL() string {
+	return c.baseURL
 }
 
 func (c *Client) endpoint(path string) string {
@@ -57,74 +151,382 @@ func (c *Client) PostJSON(ctx context.Context, path string, body interface{}, he
 	if err != nil {
 		return fmt.Errorf("marshal request body: %w", err)
 	}
+	resp, bodyBytes, err := c.postPayload(ctx, path, payload, headers)
+	if err != nil {
+		return err
+	}
+	if err := checkStatus(resp, bodyBytes); err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(bodyBytes, out); err != nil {
+		return fmt.Errorf("decode response json: %w; body=%s", err, string(bodyBytes))
+	}
+	return nil
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(path), bytes.NewReader(payload))
+// postPayload POSTs an encoded JSON body and returns the response with its body.
+func (c *Client) postPayload(ctx context.Context, path string, payload []byte, headers map[string]string) (*http.Response, []byte, error) {
+	resp, bodyBytes, err := c.send(ctx, headers, func(headers map[string]string) (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(path), bytes.NewReader(payload))
+		if err != nil {
+			return nil, fmt.Errorf("create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		return req, nil
+	})
 	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+		return nil, nil, fmt.Errorf("do request: %w", err)
 	}
-	req.Header.Set("Content-Type", "application/json")
-	for k, v := range headers {
-		req.Header.Set(k, v)
+	return resp, bodyBytes, nil
+}
+
+// checkStatus turns an error status into a StatusError.
+func checkStatus(resp *http.Response, body []byte) error {
+	if resp.StatusCode >= 400 {
+		return StatusError(resp.StatusCode, body)
 	}
+	return nil
+}
+
+// PostMultipart sends multipart/form-data POST and decodes response into out.
+func (c *Client) PostMultipart(ctx context.Context, path string, values map[string][]MultipartValue, headers map[string]string, out interface{}) error {
+	return c.PostMultipartProgress(ctx, path, values, headers, nil, out)
+}
 
-	resp, err := c.httpClient.Do(req)
+// PostMultipartProgress is PostMultipart with upload progress reported to onProgress.
+func (c *Client) PostMultipartProgress(ctx context.Context, path string, values map[string][]MultipartValue, headers map[string]string, onProgress ProgressFunc, out interface{}) error {
+	buf, contentType, err := BuildMultipartPayload(values)
 	if err != nil {
-		return fmt.Errorf("do request: %w", err)
+		return err
 	}
-	defer resp.Body.Close()
 
-	bodyBytes, err := io.ReadAll(resp.Body)
+	resp, bodyBytes, err := c.send(ctx, headers, func(headers map[string]string) (*http.Request, error) {
+		var body io.Reader = bytes.NewReader(buf)
+		if onProgress != nil {
+			body = &progressReader{r: body, total: int64(len(buf)), fn: onProgress}
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(path), body)
+		if err != nil {
+			return nil, fmt.Errorf("create request: %w", err)
+		}
+		req.ContentLength = int64(len(buf))
+		req.Header.Set("Content-Type", contentType)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		return req, nil
+	})
 	if err != nil {
-		return fmt.Errorf("read response body: %w", err)
+		return fmt.Errorf("do multipart request: %w", err)
 	}
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("http %d: %s", resp.StatusCode, string(bodyBytes))
+		return StatusError(resp.StatusCode, bodyBytes)
 	}
 	if out == nil {
 		return nil
 	}
 	if err := json.Unmarshal(bodyBytes, out); err != nil {
-		return fmt.Errorf("decode response json: %w; body=%s", err, string(bodyBytes))
+		return fmt.Errorf("decode multipart response json: %w; body=%s", err, string(bodyBytes))
 	}
 	return nil
 }
 
-// PostMultipart sends multipart/form-data POST and decodes response into out.
-func (c *Client) PostMultipart(ctx context.Context, path string, values map[string][]MultipartValue, headers map[string]string, out interface{}) error {
-	buf, contentType, err := BuildMultipartPayload(values)
+// send builds and executes a request with headers. A 401 under bearer auth is
+// retried once with a refreshed token when a TokenRefresher is set, and a
+// rejected signature is retried once with headers from the Resigner.
+func (c *Client) send(ctx context.Context, headers map[string]string, build func(map[string]string) (*http.Request, error)) (*http.Response, []byte, error) {
+	req, resp, body, err := c.doThrottled(ctx, headers, build)
 	if err != nil {
-		return err
+		return resp, body, err
+	}
+	var retry map[string]string
+	switch authModeFromHeaders(headers) {
+	case "bearer":
+		if resp.StatusCode != http.StatusUnauthorized || c.refreshToken == nil {
+			return resp, body, nil
+		}
+		if skip, _ := ctx.Value(noRefreshKey{}).(bool); skip {
+			return resp, body, nil
+		}
+		token, ok := c.refreshToken(ctx, headers)
+		if !ok {
+			return resp, body, nil
+		}
+		retry = replaceHeaders(headers, map[string]string{"Authorization": "Bearer " + token})
+		log.Verbosef("http: retrying %s with a refreshed token", req.URL.Path)
+	case "signature":
+		if c.resign == nil || !signatureRejected(resp.StatusCode, body) {
+			return resp, body, nil
+		}
+		signed, ok := c.resign(headers, resp.Header.Get("Date"))
+		if !ok {
+			return resp, body, nil
+		}
+		retry = replaceHeaders(headers, signed)
+		log.Verbosef("http: retrying %s with a fresh signature", req.URL.Path)
+	default:
+		return resp, body, nil
 	}
+	_, resp, body, err = c.doThrottled(ctx, retry, build)
+	return resp, body, err
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint(path), bytes.NewReader(buf))
-	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+// doThrottled waits for the rate limiter and runs the request. A 429 pauses
+// every request of the client for Retry-After (or a growing backoff) and the
+// request is sent again, up to rateLimitAttempts times.
+func (c *Client) doThrottled(ctx context.Context, headers map[string]string, build func(map[string]string) (*http.Request, error)) (*http.Request, *http.Response, []byte, error) {
+	for attempt := 1; ; attempt++ {
+		if err := c.limiter.wait(ctx); err != nil {
+			return nil, nil, nil, err
+		}
+		req, err := build(headers)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		resp, body, err := c.do(req, headers)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || attempt >= rateLimitAttempts {
+			return req, resp, body, err
+		}
+		delay := retryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if delay <= 0 {
+			delay = time.Duration(attempt) * time.Second
+		}
+		c.limiter.pause(delay)
+		log.Verbosef("http: %s rate limited; pausing requests for %s", req.URL.Path, delay)
 	}
-	req.Header.Set("Content-Type", contentType)
+}
+
+// replaceHeaders copies headers with every key in repl (case-insensitively) replaced.
+func replaceHeaders(headers, repl map[string]string) map[string]string {
+	out := make(map[string]string, len(headers)+len(repl))
 	for k, v := range headers {
-		req.Header.Set(k, v)
+		keep := true
+		for r := range repl {
+			if strings.EqualFold(k, r) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			out[k] = v
+		}
 	}
+	for k, v := range repl {
+		out[k] = v
+	}
+	return out
+}
+
+// signatureRejected reports whether a response rejects the request's signature
+// or nonce: an auth status, or an error envelope that mentions either.
+func signatureRejected(status int, body []byte) bool {
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		return true
+	}
+	var env GenericResponse
+	if json.Unmarshal(body, &env) != nil || env.Result {
+		return false
+	}
+	for _, e := range env.Errors {
+		msg := strings.ToLower(e.Message)
+		if strings.Contains(msg, "signature") || strings.Contains(msg, "nonce") {
+			return true
+		}
+	}
+	return false
+}
+
+// do executes req, reads the full body, and traces the exchange when logging
+// or telemetry is enabled.
+func (c *Client) do(req *http.Request, headers map[string]string) (resp *http.Response, bodyBytes []byte, err error) {
+	started := time.Now()
+	log.Debugf("http -> %s %s auth=%s headers[%s]", req.Method, req.URL.Path, authModeFromHeaders(headers), log.RedactHeaders(headers))
+	ctx, span := telemetry.StartSpan(req.Context(), req.Method+" "+req.URL.Path, telemetry.KindClient,
+		telemetry.String("http.request.method", req.Method),
+		telemetry.String("url.path", req.URL.Path),
+		telemetry.String("server.address", req.URL.Hostname()))
+	if tp := telemetry.Traceparent(ctx); tp != "" {
+		req.Header.Set("traceparent", tp)
+	}
+	defer func() {
+		if err != nil {
+			metrics.APIRequests.Inc("error")
+			span.End(err)
+			return
+		}
+		metrics.APIRequests.Inc(strconv.Itoa(resp.StatusCode))
+		span.SetAttributes(telemetry.Int("http.response.status_code", int64(resp.StatusCode)))
+		if resp.StatusCode >= 400 {
+			span.End(StatusError(resp.StatusCode, nil))
+			return
+		}
+		span.End(nil)
+	}()
 
-	resp, err := c.httpClient.Do(req)
+	// Set explicitly so deflate is accepted too; decoding is then ours to do.
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	resp, err = c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("do multipart request: %w", err)
+		log.Verbosef("http <- %s %s error=%v duration=%s", req.Method, req.URL.Path, err, time.Since(started).Round(time.Millisecond))
+		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
-	bodyBytes, err := io.ReadAll(resp.Body)
+	bodyBytes, err = readBody(resp, c.maxResponseBytes)
 	if err != nil {
-		return fmt.Errorf("read multipart response body: %w", err)
+		return nil, nil, err
 	}
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("http %d: %s", resp.StatusCode, string(bodyBytes))
+	phase := log.PhaseAPI
+	if ct := req.Header.Get("Content-Type"); strings.HasPrefix(ct, "multipart/") || ct == "application/octet-stream" {
+		// Multipart requests and upload chunks carry input files, so their time is mostly upload.
+		phase = log.PhaseUpload
 	}
-	if out == nil {
-		return nil
+	log.Record(phase, req.Method+" "+req.URL.Path, time.Since(started))
+	log.Verbosef("http <- %s %s status=%d bytes=%d duration=%s", req.Method, req.URL.Path, resp.StatusCode, len(bodyBytes), time.Since(started).Round(time.Millisecond))
+	if resp.StatusCode >= 400 || !bytes.Contains(bodyBytes, []byte(`"result":true`)) {
+		log.Debugf("http <- body: %s", truncateForLog(bodyBytes, 600))
 	}
-	if err := json.Unmarshal(bodyBytes, out); err != nil {
-		return fmt.Errorf("decode multipart response json: %w; body=%s", err, string(bodyBytes))
+	return resp, bodyBytes, nil
+}
+
+func authModeFromHeaders(headers map[string]string) string {
+	has := func(name string) bool {
+		for k := range headers {
+			if strings.EqualFold(k, name) {
+				return true
+			}
+		}
+		return false
 	}
-	return nil
+	switch {
+	case has("x-signature"):
+		return "signature"
+	case has("Authorization"):
+		return "bearer"
+	case has("x-api-key"):
+		return "apikey-only"
+	default:
+		return "none"
+	}
+}
+
+func truncateForLog(b []byte, max int) string {
+	if len(b) <= max {
+		return string(b)
+	}
+	// Back off to a rune boundary so the log line stays valid UTF-8.
+	for max > 0 && !utf8.RuneStart(b[max]) {
+		max--
+	}
+	return string(b[:max]) + "...(truncated)"
+}
+
+// Download GETs a file URL with retries and returns the open response; the caller closes the body.
+// Auth headers are only attached for Wiro hosts, and dropped on redirects to
+// other hosts, so credentials never reach third-party storage.
+func (c *Client) Download(ctx context.Context, fileURL string, headers map[string]string) (*http.Response, error) {
+	return c.DownloadFrom(ctx, fileURL, headers, 0)
+}
+
+// DownloadFrom is Download starting at byte offset, for resuming a partial file.
+// The server may ignore the range and answer 200 with the whole file.
+func (c *Client) DownloadFrom(ctx context.Context, fileURL string, headers map[string]string, offset int64) (*http.Response, error) {
+	var lastErr error
+	for attempt := 1; attempt <= downloadAttempts; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("create download request: %w", err)
+		}
+		if c.isWiroHost(req.URL.Hostname()) {
+			for k, v := range headers {
+				req.Header.Set(k, v)
+			}
+		}
+		if offset > 0 {
+			req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		}
+		started := time.Now()
+		resp, err := c.downloadClient.Do(req)
+		switch {
+		case err != nil:
+			lastErr = err
+		case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+			resp.Body.Close()
+			lastErr = StatusError(resp.StatusCode, nil)
+		case resp.StatusCode >= 400:
+			resp.Body.Close()
+			return nil, StatusError(resp.StatusCode, nil)
+		default:
+			log.Verbosef("download <- GET %s status=%d attempt=%d duration=%s", req.URL.Path, resp.StatusCode, attempt, time.Since(started).Round(time.Millisecond))
+			return resp, nil
+		}
+		log.Verbosef("download <- GET %s error=%v attempt=%d", req.URL.Path, lastErr, attempt)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if attempt < downloadAttempts {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Duration(attempt) * time.Second):
+			}
+		}
+	}
+	return nil, lastErr
+}
+
+// Head fetches a file URL's size and content type without downloading it.
+// size is -1 when the server does not report Content-Length.
+func (c *Client) Head(ctx context.Context, fileURL string, headers map[string]string) (size int64, contentType string, err error) {
+	resp, err := c.head(ctx, fileURL, headers)
+	if err != nil {
+		return -1, "", err
+	}
+	return resp.ContentLength, resp.Header.Get("Content-Type"), nil
+}
+
+// HeadDigest fetches a file URL's size and content validators (ETag, Content-MD5).
+func (c *Client) HeadDigest(ctx context.Context, fileURL string, headers map[string]string) (size int64, etag, contentMD5 string, err error) {
+	resp, err := c.head(ctx, fileURL, headers)
+	if err != nil {
+		return -1, "", "", err
+	}
+	return resp.ContentLength, resp.Header.Get("ETag"), resp.Header.Get("Content-MD5"), nil
+}
+
+func (c *Client) head(ctx context.Context, fileURL string, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, fileURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create head request: %w", err)
+	}
+	if c.isWiroHost(req.URL.Hostname()) {
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+	}
+	resp, err := c.downloadClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, StatusError(resp.StatusCode, nil)
+	}
+	return resp, nil
+}
+
+func (c *Client) isWiroHost(host string) bool {
+	host = strings.ToLower(host)
+	if u, err := url.Parse(c.baseURL); err == nil && strings.EqualFold(u.Hostname(), host) {
+		return true
+	}
+	return host == "wiro.ai" || strings.HasSuffix(host, ".wiro.ai")
 }
 
 // BuildMultipartPayload builds multipart bytes for scalar and file fields.
@@ -167,3 +569,64 @@ func addFilePart(w *multipart.Writer, fieldName, filePath string) error {
 	}
 	return nil
 }
+
+// progressReader reports cumulative reads to fn.
+type progressReader struct {
+	r     io.Reader
+	done  int64
+	total int64
+	fn    ProgressFunc
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	if n > 0 {
+		p.done += int64(n)
+		p.fn(p.done, p.total)
+	}
+	return n, err
+}
+
+// ErrResponseTooLarge is returned when a decoded API response exceeds the size cap.
+var ErrResponseTooLarge = errors.New("response body too large")
+
+// readBody decodes gzip/deflate content and reads at most limit bytes of the result,
+// so neither a runaway endpoint nor a compression bomb can exhaust memory.
+func readBody(resp *http.Response, limit int64) ([]byte, error) {
+	var body io.Reader = resp.Body
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip", "x-gzip":
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("decode gzip response: %w", err)
+		}
+		defer zr.Close()
+		body = zr
+	case "deflate":
+		body = deflateReader(resp.Body)
+	}
+	if limit <= 0 {
+		limit = DefaultMaxResponseBytes
+	}
+	data, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, limit)
+	}
+	return data, nil
+}
+
+// deflateReader handles both zlib-wrapped deflate (per the RFC) and the raw
+// deflate streams some servers send instead.
+func deflateReader(r io.Reader) io.Reader {
+	br := bufio.NewReader(r)
+	head, err := br.Peek(2)
+	if err == nil && head[0]&0x0f == 8 && (uint16(head[0])<<8|uint16(head[1]))%31 == 0 {
+		if zr, err := zlib.NewReader(br); err == nil {
+			return zr
+		}
+	}
+	return flate.NewReader(br)
+}
@@ -2,11 +2,18 @@ package api
 
 import (
 	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
 	"io"
 	"mime"
 	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -81,3 +88,269 @@ func TestBuildMultipartPayload_FileAndURLMix(t *testing.T) {
 		t.Fatalf("unexpected part parsing: seenFile=%v seenURL=%v seenPrompt=%v", seenFile, seenURL, seenPrompt)
 	}
 }
+
+func TestDownload_RetriesAndScopesAuthHeaders(t *testing.T) {
+	var calls int
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		gotAuth = r.Header.Get("Authorization")
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	headers := map[string]string{"Authorization": "Bearer t"}
+	trusted := NewClient(srv.URL)
+	resp, err := trusted.Download(context.Background(), srv.URL+"/file.png", headers)
+	if err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	resp.Body.Close()
+	if calls != 2 {
+		t.Fatalf("expected one retry, got %d calls", calls)
+	}
+	if gotAuth != "Bearer t" {
+		t.Fatalf("auth header should be sent to the API host, got %q", gotAuth)
+	}
+
+	other := NewClient("https://api.example.test/v1")
+	resp, err = other.Download(context.Background(), srv.URL+"/file.png", headers)
+	if err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	resp.Body.Close()
+	if gotAuth != "" {
+		t.Fatalf("auth header must not leak to foreign hosts, got %q", gotAuth)
+	}
+}
+
+func TestDownload_DropsAuthHeadersOnForeignRedirect(t *testing.T) {
+	var leaked []string
+	storage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, h := range authHeaders {
+			if r.Header.Get(h) != "" {
+				leaked = append(leaked, h)
+			}
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer storage.Close()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-signature") != "sig" {
+			t.Errorf("API host should get the signature, headers = %v", r.Header)
+		}
+		http.Redirect(w, r, storage.URL+"/bucket/file.png", http.StatusFound)
+	}))
+	defer api.Close()
+
+	// Both servers listen on 127.0.0.1; "localhost" makes the API another host.
+	apiURL := strings.Replace(api.URL, "127.0.0.1", "localhost", 1)
+	c := NewClient(apiURL)
+	headers := map[string]string{"x-api-key": "k", "x-nonce": "1", "x-signature": "sig", "Authorization": "Bearer t"}
+	resp, err := c.Download(context.Background(), apiURL+"/File/out.png", headers)
+	if err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	resp.Body.Close()
+	if len(leaked) > 0 {
+		t.Fatalf("credentials sent to storage: %v", leaked)
+	}
+}
+
+func TestErrorClassification(t *testing.T) {
+	cases := []struct {
+		err  error
+		want error
+	}{
+		{StatusError(401, []byte(`{"result":false,"errors":[{"code":0,"message":"Invalid token"}]}`)), ErrUnauthorized},
+		{StatusError(429, nil), ErrRateLimited},
+		{StatusError(503, []byte("bad gateway")), ErrServer},
+		{ResponseError([]APIError{{Code: float64(0), Message: "Insufficient balance"}}), ErrInsufficientCredit},
+		{ResponseError([]APIError{{Code: "x", Message: "Tool not found"}}), ErrModelNotFound},
+		{ResponseError([]APIError{{Message: "Task not found"}}), ErrTaskNotFound},
+	}
+	for i, tc := range cases {
+		if !errors.Is(tc.err, tc.want) {
+			t.Fatalf("case %d: %v is not %v", i, tc.err, tc.want)
+		}
+	}
+}
+
+func TestPostMultipartProgress_ReportsUpload(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		_, _ = w.Write([]byte(`{"result":true}`))
+	}))
+	defer srv.Close()
+
+	var last, total int64
+	c := NewClient(srv.URL)
+	values := map[string][]MultipartValue{"prompt": {{Value: "hello"}}}
+	err := c.PostMultipartProgress(context.Background(), "/Run/a/b", values, nil, func(done, size int64) {
+		last, total = done, size
+	}, nil)
+	if err != nil {
+		t.Fatalf("post: %v", err)
+	}
+	if total <= 0 || last != total {
+		t.Fatalf("progress ended at %d/%d", last, total)
+	}
+}
+
+func TestPostJSON_DecodesCompressionAndCapsSize(t *testing.T) {
+	payload := []byte(`{"result":true,"total":3}`)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/gzip":
+			w.Header().Set("Content-Encoding", "gzip")
+			zw := gzip.NewWriter(w)
+			_, _ = zw.Write(payload)
+			_ = zw.Close()
+		case "/deflate":
+			w.Header().Set("Content-Encoding", "deflate")
+			zw := zlib.NewWriter(w)
+			_, _ = zw.Write(payload)
+			_ = zw.Close()
+		default:
+			_, _ = w.Write(bytes.Repeat([]byte("x"), 2048))
+		}
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	for _, path := range []string{"/gzip", "/deflate"} {
+		var out struct {
+			Total int `json:"total"`
+		}
+		if err := c.PostJSON(context.Background(), path, map[string]string{}, nil, &out); err != nil || out.Total != 3 {
+			t.Fatalf("%s: out=%+v err=%v", path, out, err)
+		}
+	}
+
+	c.SetMaxResponseBytes(1024)
+	if err := c.PostJSON(context.Background(), "/big", map[string]string{}, nil, nil); !errors.Is(err, ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge, got %v", err)
+	}
+}
+
+func TestPostJSON_RefreshesRejectedBearerToken(t *testing.T) {
+	var seen []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("Authorization"))
+		if r.Header.Get("Authorization") != "Bearer fresh" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"result":false,"errors":[{"code":401,"message":"token expired"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"result":true}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	refreshes := 0
+	c.SetTokenRefresher(func(ctx context.Context, rejected map[string]string) (string, bool) {
+		refreshes++
+		return "fresh", true
+	})
+	var out GenericResponse
+	if err := c.PostJSON(context.Background(), "/x", map[string]string{}, map[string]string{"Authorization": "Bearer stale"}, &out); err != nil {
+		t.Fatalf("PostJSON: %v", err)
+	}
+	if refreshes != 1 || len(seen) != 2 || seen[1] != "Bearer fresh" {
+		t.Fatalf("refreshes=%d seen=%q", refreshes, seen)
+	}
+
+	// Signature auth is never retried, and WithoutTokenRefresh disables the retry.
+	err := c.PostJSON(context.Background(), "/x", nil, map[string]string{"x-api-key": "k", "x-signature": "s"}, nil)
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("signature err = %v", err)
+	}
+	err = c.PostJSON(WithoutTokenRefresh(context.Background()), "/x", nil, map[string]string{"Authorization": "Bearer stale"}, nil)
+	if !errors.Is(err, ErrUnauthorized) || refreshes != 1 {
+		t.Fatalf("no-refresh err = %v refreshes=%d", err, refreshes)
+	}
+}
+
+func TestPostJSON_ResignsRejectedSignature(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-nonce") != "2" {
+			_, _ = w.Write([]byte(`{"result":false,"errors":[{"code":0,"message":"Invalid signature or nonce"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"result":true}`))
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	c.SetResigner(func(rejected map[string]string, serverDate string) (map[string]string, bool) {
+		if serverDate == "" {
+			t.Error("missing server date")
+		}
+		return map[string]string{"x-api-key": rejected["x-api-key"], "x-nonce": "2", "x-signature": "s2"}, true
+	})
+	var out GenericResponse
+	headers := map[string]string{"x-api-key": "k", "x-nonce": "1", "x-signature": "s1"}
+	if err := c.PostJSON(context.Background(), "/x", nil, headers, &out); err != nil || !out.Result {
+		t.Fatalf("PostJSON: err=%v result=%v", err, out.Result)
+	}
+}
+
+type memoryCache map[string]CachedResponse
+
+func (m memoryCache) Get(key string) (CachedResponse, bool) { r, ok := m[key]; return r, ok }
+func (m memoryCache) Put(key string, r CachedResponse)      { m[key] = r }
+
+func TestPostJSONCached_RevalidatesWithETag(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = io.WriteString(w, `{"result":true,"name":"flux"}`)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	cache := memoryCache{}
+	c.SetResponseCache(cache)
+	body := map[string]string{"slugowner": "wiro", "slugproject": "flux"}
+	for i := 0; i < 2; i++ {
+		var out struct {
+			Name string `json:"name"`
+		}
+		if err := c.PostJSONCached(context.Background(), "/Tool/Detail", body, nil, &out); err != nil {
+			t.Fatalf("request %d: %v", i+1, err)
+		}
+		if out.Name != "flux" {
+			t.Fatalf("request %d decoded %q", i+1, out.Name)
+		}
+	}
+	if requests != 2 {
+		t.Fatalf("server saw %d requests, want 2", requests)
+	}
+	if len(cache) != 1 {
+		t.Fatalf("cache has %d entries, want 1", len(cache))
+	}
+}
+
+func TestPostJSONCached_NotModifiedWithoutCache(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer srv.Close()
+
+	c := NewClient(srv.URL)
+	c.SetResponseCache(memoryCache{})
+	var out GenericResponse
+	err := c.PostJSONCached(context.Background(), "/Tool/Detail", nil, nil, &out)
+	if !errors.Is(err, ErrServer) || !strings.Contains(err.Error(), "no cached response") {
+		t.Fatalf("err = %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Sentinel error kinds callers can match with errors.Is.
+var (
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrInsufficientCredit = errors.New("insufficient credit")
+	ErrModelNotFound      = errors.New("model not found")
+	ErrTaskNotFound       = errors.New("task not found")
+	ErrRateLimited        = errors.New("rate limited")
+	ErrInvalidInput       = errors.New("invalid input")
+	ErrServer             = errors.New("server error")
+)
+
+// Error is a classified API failure; it unwraps to one of the sentinel kinds.
+type Error struct {
+	Kind    error
+	Status  int
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	msg := strings.TrimSpace(e.Message)
+	if msg == "" && e.Kind != nil {
+		msg = e.Kind.Error()
+	}
+	if e.Status > 0 {
+		msg = fmt.Sprintf("http %d: %s", e.Status, msg)
+	}
+	if e.Code != "" && e.Code != "0" {
+		msg += " (code=" + e.Code + ")"
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// CodeString normalizes the loosely typed code field.
+func (e APIError) CodeString() string {
+	switch v := e.Code.(type) {
+	case nil:
+		return ""
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	default:
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+}
+
+// ResponseError converts an envelope errors array into a typed error.
+func ResponseError(errs []APIError) error {
+	if len(errs) == 0 {
+		return &Error{Kind: ErrServer, Message: "request failed without error details"}
+	}
+	first := errs[0]
+	messages := make([]string, 0, len(errs))
+	for _, e := range errs {
+		if m := strings.TrimSpace(e.Message); m != "" {
+			messages = append(messages, m)
+		}
+	}
+	return &Error{
+		Kind:    classify(0, first.CodeString(), first.Message),
+		Code:    first.CodeString(),
+		Message: strings.Join(messages, "; "),
+	}
+}
+
+// StatusError builds a typed error from an HTTP error response, using the envelope when present.
+func StatusError(status int, body []byte) error {
+	var env GenericResponse
+	message := strings.TrimSpace(string(body))
+	code := ""
+	if json.Unmarshal(body, &env) == nil && len(env.Errors) > 0 {
+		message = env.Errors[0].Message
+		code = env.Errors[0].CodeString()
+	}
+	return &Error{Kind: classify(status, code, message), Status: status, Code: code, Message: message}
+}
+
+// classify maps status codes, API error codes, and message wording to a sentinel kind.
+func classify(status int, code, message string) error {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusPaymentRequired:
+		return ErrInsufficientCredit
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	switch code {
+	case "401", "403":
+		return ErrUnauthorized
+	case "402":
+		return ErrInsufficientCredit
+	case "404":
+		return ErrModelNotFound
+	case "429":
+		return ErrRateLimited
+	}
+
+	msg := strings.ToLower(message)
+	switch {
+	case containsAny(msg, "unauthorized", "not authorized", "invalid token", "token expired", "signature", "authentication", "forbidden"):
+		return ErrUnauthorized
+	case containsAny(msg, "credit", "balance", "insufficient", "payment"):
+		return ErrInsufficientCredit
+	case containsAny(msg, "rate limit", "too many requests"):
+		return ErrRateLimited
+	case containsAny(msg, "task") && containsAny(msg, "not found", "doesn't exist", "does not exist"):
+		return ErrTaskNotFound
+	case containsAny(msg, "not found", "doesn't exist", "does not exist"):
+		return ErrModelNotFound
+	case containsAny(msg, "required", "invalid", "must be"):
+		return ErrInvalidInput
+	}
+	if status >= 500 {
+		return ErrServer
+	}
+	if status == http.StatusNotFound {
+		return ErrModelNotFound
+	}
+	if status >= 400 {
+		return ErrInvalidInput
+	}
+	return ErrServer
+}
+
+func containsAny(s string, needles ...string) bool {
+	for _, n := range needles {
+		if strings.Contains(s, n) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// rateLimitAttempts is how many times a request answered with 429 is sent in total.
+	rateLimitAttempts = 4
+	// maxRetryAfter caps how long a single Retry-After may pause requests.
+	maxRetryAfter = 2 * time.Minute
+)
+
+// rateLimiter is a token bucket shared by every request of a Client. A 429
+// pauses it so all workers back off together, not just the one that was refused.
+type rateLimiter struct {
+	mu          sync.Mutex
+	rate        float64 // tokens per second; 0 disables the bucket
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+	now         func() time.Time
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{now: time.Now}
+}
+
+func (l *rateLimiter) set(rps float64, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if rps < 0 {
+		rps = 0
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	l.rate = rps
+	l.burst = float64(burst)
+	l.tokens = l.burst
+	l.last = l.now()
+}
+
+// wait blocks until a request may be sent or ctx ends.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	for {
+		delay := l.reserve()
+		if delay <= 0 {
+			return nil
+		}
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// reserve takes a token and returns 0, or returns how long to wait before trying again.
+func (l *rateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if l.rate <= 0 {
+		return 0
+	}
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// pause holds every request for d, extending any pause already in effect.
+func (l *rateLimiter) pause(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until := l.now().Add(d); until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+}
+
+// SetRateLimit limits API requests to rps per second with bursts of up to
+// burst requests; rps <= 0 removes the limit. 429 backoff applies either way.
+func (c *Client) SetRateLimit(rps float64, burst int) {
+	c.limiter.set(rps, burst)
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(v string, now time.Time) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0
+	}
+	var d time.Duration
+	if secs, err := strconv.Atoi(v); err == nil {
+		d = time.Duration(secs) * time.Second
+	} else if t, err := http.ParseTime(v); err == nil {
+		d = t.Sub(now)
+	}
+	if d < 0 {
+		return 0
+	}
+	if d > maxRetryAfter {
+		return maxRetryAfter
+	}
+	return d
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_TokenBucket(t *testing.T) {
+	now := time.Unix(1000, 0)
+	l := newRateLimiter()
+	l.now = func() time.Time { return now }
+	if d := l.reserve(); d != 0 {
+		t.Fatalf("unlimited limiter should not wait, got %v", d)
+	}
+
+	l.set(2, 2)
+	if l.reserve() != 0 || l.reserve() != 0 {
+		t.Fatal("burst of 2 should pass immediately")
+	}
+	if d := l.reserve(); d != 500*time.Millisecond {
+		t.Fatalf("third request wait = %v, want 500ms", d)
+	}
+	now = now.Add(500 * time.Millisecond)
+	if d := l.reserve(); d != 0 {
+		t.Fatalf("token should have refilled, wait = %v", d)
+	}
+
+	l.pause(3 * time.Second)
+	if d := l.reserve(); d != 3*time.Second {
+		t.Fatalf("paused wait = %v", d)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if d := retryAfter("7", now); d != 7*time.Second {
+		t.Fatalf("seconds = %v", d)
+	}
+	if d := retryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now); d != 30*time.Second {
+		t.Fatalf("date = %v", d)
+	}
+	if d := retryAfter("86400", now); d != maxRetryAfter {
+		t.Fatalf("cap = %v", d)
+	}
+	if d := retryAfter("soon", now); d != 0 {
+		t.Fatalf("invalid = %v", d)
+	}
+}
+
+func TestPostJSON_RetriesAfter429(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"result":true}`))
+	}))
+	defer srv.Close()
+
+	var out GenericResponse
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := NewClient(srv.URL).PostJSON(ctx, "/x", map[string]string{}, nil, &out); err != nil {
+		t.Fatalf("PostJSON: %v", err)
+	}
+	if !out.Result || calls.Load() != 2 {
+		t.Fatalf("result=%v calls=%d", out.Result, calls.Load())
+	}
+}
@@ -31,6 +31,26 @@ type AuthSigninVerifyResponse struct {
 	User  map[string]any `json:"user"`
 }
 
+// AuthDeviceCodeResponse starts a device login (RFC 8628 style).
+type AuthDeviceCodeResponse struct {
+	GenericResponse
+	DeviceCode              string `json:"devicecode"`
+	UserCode                string `json:"usercode"`
+	VerificationURI         string `json:"verificationuri"`
+	VerificationURIComplete string `json:"verificationuricomplete"`
+	ExpiresIn               int    `json:"expiresin"`
+	Interval                int    `json:"interval"`
+}
+
+// AuthDeviceTokenResponse is one poll of a device login. Status is "pending",
+// "slow_down", "approved", "denied", or "expired".
+type AuthDeviceTokenResponse struct {
+	GenericResponse
+	Status string         `json:"status"`
+	Token  string         `json:"token"`
+	User   map[string]any `json:"user"`
+}
+
 type Project struct {
 	ID           string   `json:"id"`
 	UUID         string   `json:"uuid"`
@@ -49,9 +69,17 @@ type ProjectListResponse struct {
 	Projects []Project `json:"project"`
 }
 
+// ProjectSecretResponse carries a newly issued project API secret.
+type ProjectSecretResponse struct {
+	GenericResponse
+	APISecret string `json:"apisecret"`
+}
+
 type ToolOption struct {
 	Text  string      `json:"text"`
 	Value interface{} `json:"value"`
+	// Cover is the preview image of a selectwithcover option.
+	Cover string `json:"cover,omitempty"`
 }
 
 type ToolParameterItem struct {
@@ -73,6 +101,9 @@ type ToolParameterItem struct {
 	Options        []ToolOption `json:"options"`
 	Note           string       `json:"note"`
 	MaxInputLenght int          `json:"maxinputlenght"`
+	// ShowIf limits the field to runs where other fields have given values,
+	// e.g. "mode=inpaint"; see model.ParseConditions.
+	ShowIf interface{} `json:"showif,omitempty"`
 }
 
 type ToolParameterGroup struct {
@@ -145,6 +176,15 @@ type Task struct {
 	EndTime           string          `json:"endtime"`
 	ParametersRaw     json.RawMessage `json:"parameters"`
 	Outputs           []TaskOutput    `json:"outputs"`
+	// SlugOwner and SlugProject identify the model when the API includes them (task lists).
+	SlugOwner   string `json:"slugowner,omitempty"`
+	SlugProject string `json:"slugproject,omitempty"`
+	// GPUType and WorkerID report the hardware that ran the task when the API includes them.
+	GPUType  string `json:"gputype,omitempty"`
+	WorkerID string `json:"workerid,omitempty"`
+	// Priority and QueuePosition describe where a queued task waits, when reported.
+	Priority      string      `json:"priority,omitempty"`
+	QueuePosition json.Number `json:"queueposition,omitempty"`
 }
 
 type TaskDetailResponse struct {
@@ -152,3 +192,38 @@ type TaskDetailResponse struct {
 	Total    string `json:"total"`
 	TaskList []Task `json:"tasklist"`
 }
+
+type BalanceResponse struct {
+	GenericResponse
+	Balance  interface{} `json:"balance"`
+	Currency string      `json:"currency"`
+}
+
+type UsageRow struct {
+	Date        string      `json:"date"`
+	SlugOwner   string      `json:"slugowner"`
+	SlugProject string      `json:"slugproject"`
+	TaskCount   interface{} `json:"taskcount"`
+	Spent       interface{} `json:"spent"`
+}
+
+type UsageResponse struct {
+	GenericResponse
+	Usage []UsageRow `json:"usage"`
+}
+
+// UploadInitResponse opens a chunked upload session. Uploaded lists chunk
+// indexes the server already holds for the same file, so an interrupted
+// upload resumes where it stopped.
+type UploadInitResponse struct {
+	GenericResponse
+	UploadID  string `json:"uploadid"`
+	ChunkSize int64  `json:"chunksize"`
+	Uploaded  []int  `json:"uploaded"`
+}
+
+// UploadCompleteResponse finalizes a chunked upload with the file's URL.
+type UploadCompleteResponse struct {
+	GenericResponse
+	URL string `json:"url"`
+}
@@ -0,0 +1,279 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/log"
+	"github.com/wiro-ai/wiro-cli/internal/metrics"
+	"github.com/wiro-ai/wiro-cli/internal/telemetry"
+)
+
+const (
+	// DefaultChunkSize is used when the server does not pick a chunk size.
+	DefaultChunkSize = 8 << 20
+	// DefaultUploadParallel is how many chunks are sent at once.
+	DefaultUploadParallel = 4
+
+	chunkAttempts = 4
+)
+
+// ErrChunkedUploadUnsupported means the server has no chunked upload
+// endpoints, so files have to be sent inside the run request.
+var ErrChunkedUploadUnsupported = errors.New("chunked upload not supported by the server")
+
+// chunkRetryDelay is the first backoff between chunk attempts; a var so tests can shorten it.
+var chunkRetryDelay = time.Second
+
+// ChunkedUploadOptions tunes UploadFileChunked.
+type ChunkedUploadOptions struct {
+	// ChunkSize is the requested chunk size; the server's choice wins.
+	ChunkSize int64
+	// Parallel is how many chunks are in flight at once.
+	Parallel int
+	// OnProgress receives bytes uploaded so far, including resumed chunks.
+	OnProgress ProgressFunc
+	// SHA256 is the file's hex digest when the caller already computed it.
+	SHA256 string
+}
+
+// UploadFileChunked uploads a file through a chunked session (init, parallel
+// chunk PUTs with retry, complete) and returns its storage URL. The session
+// is keyed by the file's SHA256, so rerunning after a failure skips chunks the
+// server already has.
+func (c *Client) UploadFileChunked(ctx context.Context, path string, headers map[string]string, opts ChunkedUploadOptions) (string, error) {
+	ctx, span := telemetry.StartSpan(ctx, "upload", telemetry.KindInternal, telemetry.String("wiro.upload.file", filepath.Base(path)))
+	fileURL, err := c.uploadFileChunked(ctx, span, path, headers, opts)
+	if err != nil {
+		metrics.Uploads.Inc("error")
+	} else {
+		metrics.Uploads.Inc("ok")
+	}
+	span.End(err)
+	return fileURL, err
+}
+
+func (c *Client) uploadFileChunked(ctx context.Context, span *telemetry.Span, path string, headers map[string]string, opts ChunkedUploadOptions) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open upload file: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("stat upload file: %w", err)
+	}
+	size := info.Size()
+	sum := opts.SHA256
+	if sum == "" {
+		if sum, err = fileSHA256(f); err != nil {
+			return "", err
+		}
+	}
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	contentType := mime.TypeByExtension(filepath.Ext(path))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	var initResp UploadInitResponse
+	err = c.PostJSON(ctx, "/File/Upload/Init", map[string]interface{}{
+		"filename":    filepath.Base(path),
+		"size":        size,
+		"sha256":      sum,
+		"contenttype": contentType,
+		"chunksize":   chunkSize,
+	}, headers, &initResp)
+	var apiErr *Error
+	if errors.As(err, &apiErr) && (apiErr.Status == http.StatusNotFound || apiErr.Status == http.StatusMethodNotAllowed) {
+		return "", fmt.Errorf("init upload: %w (%v)", ErrChunkedUploadUnsupported, err)
+	}
+	if err != nil {
+		return "", fmt.Errorf("init upload: %w", err)
+	}
+	if !initResp.Result || initResp.UploadID == "" {
+		return "", fmt.Errorf("init upload: %w", ResponseError(initResp.Errors))
+	}
+	if initResp.ChunkSize > 0 {
+		chunkSize = initResp.ChunkSize
+	}
+
+	chunks := int((size + chunkSize - 1) / chunkSize)
+	have := map[int]bool{}
+	for _, i := range initResp.Uploaded {
+		have[i] = true
+	}
+	var done atomic.Int64
+	report := func(n int64) {
+		v := done.Add(n)
+		if opts.OnProgress != nil {
+			opts.OnProgress(v, size)
+		}
+	}
+	var pending []int
+	for i := 0; i < chunks; i++ {
+		if have[i] {
+			report(chunkLen(i, chunkSize, size))
+			continue
+		}
+		pending = append(pending, i)
+	}
+	span.SetAttributes(telemetry.Int("wiro.upload.size", size), telemetry.Int("wiro.upload.chunks", int64(chunks)), telemetry.Int("wiro.upload.chunks_resumed", int64(chunks-len(pending))))
+	if len(have) > 0 {
+		log.Verbosef("upload: resuming %s, %d of %d chunks already uploaded", filepath.Base(path), chunks-len(pending), chunks)
+	}
+
+	if err := c.putChunks(ctx, f, initResp.UploadID, pending, chunkSize, size, headers, opts.Parallel, report); err != nil {
+		return "", err
+	}
+
+	var complete UploadCompleteResponse
+	err = c.PostJSON(ctx, "/File/Upload/Complete", map[string]interface{}{
+		"uploadid": initResp.UploadID,
+		"chunks":   chunks,
+		"sha256":   sum,
+	}, headers, &complete)
+	if err != nil {
+		return "", fmt.Errorf("complete upload: %w", err)
+	}
+	if !complete.Result || complete.URL == "" {
+		return "", fmt.Errorf("complete upload: %w", ResponseError(complete.Errors))
+	}
+	return complete.URL, nil
+}
+
+// putChunks sends chunks with up to parallel requests in flight and stops at the first failure.
+func (c *Client) putChunks(ctx context.Context, f *os.File, uploadID string, pending []int, chunkSize, size int64, headers map[string]string, parallel int, report func(int64)) error {
+	if parallel <= 0 {
+		parallel = DefaultUploadParallel
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for w := 0; w < parallel && w < len(pending); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := c.putChunk(ctx, f, uploadID, i, chunkSize, size, headers); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				report(chunkLen(i, chunkSize, size))
+			}
+		}()
+	}
+feed:
+	for _, i := range pending {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// putChunk PUTs one chunk, retrying network errors, 429, and 5xx with backoff.
+func (c *Client) putChunk(ctx context.Context, f *os.File, uploadID string, index int, chunkSize, size int64, headers map[string]string) error {
+	offset := int64(index) * chunkSize
+	length := chunkLen(index, chunkSize, size)
+	endpoint := c.endpoint("/File/Upload/Chunk") + "?uploadid=" + url.QueryEscape(uploadID) + "&index=" + strconv.Itoa(index)
+	delay := chunkRetryDelay
+	var lastErr error
+	for attempt := 1; attempt <= chunkAttempts; attempt++ {
+		resp, body, err := c.send(ctx, headers, func(headers map[string]string) (*http.Request, error) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, io.NewSectionReader(f, offset, length))
+			if err != nil {
+				return nil, fmt.Errorf("create request: %w", err)
+			}
+			req.ContentLength = length
+			req.Header.Set("Content-Type", "application/octet-stream")
+			req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+length-1, size))
+			for k, v := range headers {
+				req.Header.Set(k, v)
+			}
+			return req, nil
+		})
+		switch {
+		case err != nil:
+			lastErr = err
+		case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+			lastErr = StatusError(resp.StatusCode, body)
+		case resp.StatusCode >= 400:
+			return fmt.Errorf("upload chunk %d: %w", index, StatusError(resp.StatusCode, body))
+		default:
+			metrics.UploadBytes.Add(float64(length))
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.Verbosef("upload: chunk %d attempt %d failed: %v", index, attempt, lastErr)
+		if attempt < chunkAttempts {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+	}
+	return fmt.Errorf("upload chunk %d: %w", index, lastErr)
+}
+
+func chunkLen(index int, chunkSize, size int64) int64 {
+	n := size - int64(index)*chunkSize
+	if n > chunkSize {
+		n = chunkSize
+	}
+	if n < 0 {
+		n = 0
+	}
+	return n
+}
+
+// FileSHA256 returns the hex SHA256 of the file at path.
+func FileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("open upload file: %w", err)
+	}
+	defer f.Close()
+	return fileSHA256(f)
+}
+
+func fileSHA256(f *os.File) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, io.NewSectionReader(f, 0, 1<<62)); err != nil {
+		return "", fmt.Errorf("hash upload file: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,98 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUploadFileChunked_ResumesAndRetries(t *testing.T) {
+	prev := chunkRetryDelay
+	chunkRetryDelay = time.Millisecond
+	defer func() { chunkRetryDelay = prev }()
+
+	data := bytes.Repeat([]byte("0123456789"), 25) // 250 bytes, 3 chunks of 100
+	path := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	var mu sync.Mutex
+	got := map[int][]byte{}
+	failed := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/File/Upload/Init":
+			_ = json.NewEncoder(w).Encode(UploadInitResponse{GenericResponse: GenericResponse{Result: true}, UploadID: "u1", ChunkSize: 100, Uploaded: []int{0}})
+		case "/File/Upload/Chunk":
+			idx, _ := strconv.Atoi(r.URL.Query().Get("index"))
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			defer mu.Unlock()
+			if idx == 2 && !failed {
+				failed = true
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			got[idx] = body
+			_, _ = w.Write([]byte(`{"result":true}`))
+		case "/File/Upload/Complete":
+			_ = json.NewEncoder(w).Encode(UploadCompleteResponse{GenericResponse: GenericResponse{Result: true}, URL: "https://cdn.wiro.ai/u1/big.bin"})
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	var last int64
+	c := NewClient(srv.URL)
+	url, err := c.UploadFileChunked(context.Background(), path, nil, ChunkedUploadOptions{
+		OnProgress: func(done, total int64) {
+			mu.Lock()
+			last = done
+			mu.Unlock()
+		},
+	})
+	if err != nil {
+		t.Fatalf("UploadFileChunked: %v", err)
+	}
+	if url != "https://cdn.wiro.ai/u1/big.bin" {
+		t.Fatalf("url = %q", url)
+	}
+	if _, resent := got[0]; resent {
+		t.Fatal("chunk 0 was already on the server and should be skipped")
+	}
+	if !bytes.Equal(got[1], data[100:200]) || !bytes.Equal(got[2], data[200:]) {
+		t.Fatalf("unexpected chunk bodies: %q %q", got[1], got[2])
+	}
+	if !failed || last != int64(len(data)) {
+		t.Fatalf("retry=%v progress=%d", failed, last)
+	}
+}
+
+func TestUploadFileChunked_ClientErrorFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(path, []byte("abc"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/File/Upload/Init" {
+			_ = json.NewEncoder(w).Encode(UploadInitResponse{GenericResponse: GenericResponse{Result: true}, UploadID: "u2"})
+			return
+		}
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+	}))
+	defer srv.Close()
+	if _, err := NewClient(srv.URL).UploadFileChunked(context.Background(), path, nil, ChunkedUploadOptions{}); err == nil {
+		t.Fatal("expected a 4xx chunk response to fail the upload")
+	}
+}
@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+// Device login outcomes.
+var (
+	ErrDeviceDenied  = errors.New("device login was denied in the browser")
+	ErrDeviceExpired = errors.New("device code expired before it was approved")
+)
+
+const (
+	defaultDeviceInterval = 5 * time.Second
+	slowDownStep          = 5 * time.Second
+)
+
+// DeviceCode is a pending device login.
+type DeviceCode struct {
+	DeviceCode string
+	UserCode   string
+	// VerificationURL is where the user enters UserCode; CompleteURL already carries it.
+	VerificationURL string
+	CompleteURL     string
+	ExpiresAt       time.Time
+	Interval        time.Duration
+}
+
+// StartDeviceLogin requests a device code for browser sign-in.
+func (s *Service) StartDeviceLogin(ctx context.Context) (DeviceCode, error) {
+	var resp api.AuthDeviceCodeResponse
+	if err := s.apiClient.PostJSON(ctx, "/Auth/Device/Code", map[string]interface{}{"client": "wiro-cli"}, nil, &resp); err != nil {
+		return DeviceCode{}, err
+	}
+	if !resp.Result && len(resp.Errors) > 0 {
+		return DeviceCode{}, fmt.Errorf("device login failed: %w", api.ResponseError(resp.Errors))
+	}
+	if strings.TrimSpace(resp.DeviceCode) == "" || strings.TrimSpace(resp.VerificationURI) == "" {
+		return DeviceCode{}, errors.New("device login response is missing the code or verification url")
+	}
+	dc := DeviceCode{
+		DeviceCode:      resp.DeviceCode,
+		UserCode:        resp.UserCode,
+		VerificationURL: resp.VerificationURI,
+		CompleteURL:     resp.VerificationURIComplete,
+		Interval:        time.Duration(resp.Interval) * time.Second,
+	}
+	if dc.Interval <= 0 {
+		dc.Interval = defaultDeviceInterval
+	}
+	if resp.ExpiresIn > 0 {
+		dc.ExpiresAt = time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+	}
+	return dc, nil
+}
+
+// PollDeviceLogin waits until the device code is approved and returns the
+// bearer token. It backs off when the server asks it to slow down.
+func (s *Service) PollDeviceLogin(ctx context.Context, dc DeviceCode) (string, error) {
+	interval := dc.Interval
+	if interval <= 0 {
+		interval = defaultDeviceInterval
+	}
+	if !dc.ExpiresAt.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, dc.ExpiresAt)
+		defer cancel()
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return "", ErrDeviceExpired
+			}
+			return "", ctx.Err()
+		case <-time.After(interval):
+		}
+		var resp api.AuthDeviceTokenResponse
+		err := s.apiClient.PostJSON(ctx, "/Auth/Device/Token", map[string]interface{}{"devicecode": dc.DeviceCode}, nil, &resp)
+		if err != nil {
+			if ctx.Err() != nil {
+				continue
+			}
+			return "", err
+		}
+		switch strings.ToLower(strings.TrimSpace(resp.Status)) {
+		case "pending", "authorization_pending":
+		case "slow_down":
+			interval += slowDownStep
+		case "denied", "access_denied":
+			return "", ErrDeviceDenied
+		case "expired", "expired_token":
+			return "", ErrDeviceExpired
+		default:
+			if strings.TrimSpace(resp.Token) != "" {
+				return resp.Token, nil
+			}
+			if len(resp.Errors) > 0 {
+				return "", fmt.Errorf("device login failed: %w", api.ResponseError(resp.Errors))
+			}
+			return "", fmt.Errorf("device login: unexpected status %q", resp.Status)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+func TestDeviceLogin(t *testing.T) {
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/Auth/Device/Code":
+			_, _ = w.Write([]byte(`{"result":true,"devicecode":"dev-1","usercode":"ABCD-1234","verificationuri":"https://example.com/device","expiresin":60,"interval":1}`))
+		case "/Auth/Device/Token":
+			polls++
+			if polls < 3 {
+				_, _ = w.Write([]byte(`{"result":true,"status":"pending"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"result":true,"status":"approved","token":"tok-1"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc := NewServiceWithStore(api.NewClient(srv.URL), newMemoryStore())
+	dc, err := svc.StartDeviceLogin(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dc.UserCode != "ABCD-1234" || dc.Interval != time.Second || dc.ExpiresAt.IsZero() {
+		t.Fatalf("device code = %+v", dc)
+	}
+	dc.Interval = 10 * time.Millisecond
+	token, err := svc.PollDeviceLogin(context.Background(), dc)
+	if err != nil || token != "tok-1" || polls != 3 {
+		t.Fatalf("token=%q err=%v polls=%d", token, err, polls)
+	}
+}
+
+func TestDeviceLoginDenied(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"result":false,"status":"denied"}`))
+	}))
+	defer srv.Close()
+
+	svc := NewServiceWithStore(api.NewClient(srv.URL), newMemoryStore())
+	_, err := svc.PollDeviceLogin(context.Background(), DeviceCode{DeviceCode: "d", Interval: time.Millisecond})
+	if !errors.Is(err, ErrDeviceDenied) {
+		t.Fatalf("err = %v, want ErrDeviceDenied", err)
+	}
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// fingerprintEdge is how many characters of the secret are shown at each end.
+// Secrets shorter than fingerprintMinLen show none, so at most a quarter of the
+// secret is ever revealed.
+const (
+	fingerprintEdge   = 4
+	fingerprintMinLen = 32
+)
+
+// Fingerprint identifies a project secret without revealing it: the first and
+// last few characters and an HMAC-SHA256 of the secret keyed by the API key,
+// e.g. "ab12…yz89 3f9c2e1a". Computing it again from the secret you meant to
+// store, or running `wiro auth status` on another machine, confirms a match.
+func Fingerprint(apiKey, apiSecret string) string {
+	apiSecret = strings.TrimSpace(apiSecret)
+	if apiSecret == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, []byte(strings.TrimSpace(apiKey)))
+	mac.Write([]byte(apiSecret))
+	sum := hex.EncodeToString(mac.Sum(nil))[:8]
+	if len(apiSecret) < fingerprintMinLen {
+		return sum
+	}
+	return apiSecret[:fingerprintEdge] + "…" + apiSecret[len(apiSecret)-fingerprintEdge:] + " " + sum
+}
+
+// ProjectSecretFingerprint is the Fingerprint of the stored secret for apiKey,
+// or "" when none is stored.
+func (s *Service) ProjectSecretFingerprint(apiKey string) string {
+	secret, err := s.store.GetProjectSecret(apiKey)
+	if err != nil {
+		return ""
+	}
+	return Fingerprint(apiKey, secret)
+}
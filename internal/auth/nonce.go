@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// skewThreshold is the clock difference beyond which nonces are shifted to the
+// server's clock. Server Date headers have one-second resolution.
+const skewThreshold = 5 * time.Second
+
+// nonceSource issues millisecond nonces that strictly increase within the
+// process, so two requests in the same instant never share one.
+type nonceSource struct {
+	mu     sync.Mutex
+	last   int64
+	offset time.Duration
+}
+
+func (n *nonceSource) next(now time.Time) string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	ms := now.Add(n.offset).UnixMilli()
+	if ms <= n.last {
+		ms = n.last + 1
+	}
+	n.last = ms
+	return strconv.FormatInt(ms, 10)
+}
+
+func (n *nonceSource) setOffset(d time.Duration) {
+	n.mu.Lock()
+	n.offset = d
+	n.mu.Unlock()
+}
+
+// ClockSkew returns how far the server clock is ahead of the local one, from a
+// response Date header; ok is false when the header is missing or unparsable.
+func ClockSkew(serverDate string, now time.Time) (time.Duration, bool) {
+	t, err := time.Parse(time.RFC1123, strings.TrimSpace(serverDate))
+	if err != nil {
+		return 0, false
+	}
+	return t.Sub(now), true
+}
+
+// Resign signs rejected signature headers again with a fresh nonce. When the
+// server clock (from its Date header) differs by more than a few seconds, later
+// nonces follow the server clock and a warning is printed.
+func (s *Service) Resign(rejected map[string]string, serverDate string) (map[string]string, error) {
+	apiKey := rejected["x-api-key"]
+	secret := s.secretFor(apiKey)
+	if apiKey == "" || secret == "" {
+		return nil, errors.New("no api secret to sign with")
+	}
+	if skew, ok := ClockSkew(serverDate, time.Now()); ok && (skew > skewThreshold || skew < -skewThreshold) {
+		s.nonces.setOffset(skew)
+		fmt.Fprintf(os.Stderr, "warning: local clock differs from the server by %s; signing with the server's time (sync your clock to fix this)\n", skew.Round(time.Second))
+	}
+	nonce := s.nonceFn()
+	return map[string]string{
+		"x-api-key":   apiKey,
+		"x-nonce":     nonce,
+		"x-signature": ComputeSignature(apiKey, secret, nonce),
+	}, nil
+}
+
+// secretFor returns the API secret for apiKey from the environment or the keychain.
+func (s *Service) secretFor(apiKey string) string {
+	if env := s.EnvCredentials(); env.APIKey == apiKey && env.APISecret != "" {
+		return env.APISecret
+	}
+	secret, err := s.store.GetProjectSecret(apiKey)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(secret)
+}
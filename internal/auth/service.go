@@ -7,11 +7,13 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 	"time"
 
 	"github.com/wiro-ai/wiro-cli/internal/api"
 	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/log"
 	"github.com/wiro-ai/wiro-cli/internal/secure"
 )
 
@@ -24,16 +26,70 @@ const (
 	HeaderModeAPIKey    HeaderMode = "apikey-only"
 )
 
+// Environment variables that override stored credentials, for CI.
+const (
+	EnvAPIKey    = "WIRO_API_KEY"
+	EnvAPISecret = "WIRO_API_SECRET"
+	EnvToken     = "WIRO_TOKEN"
+)
+
+// Credential sources reported in HeaderResult.Source.
+const (
+	SourceEnv    = "env"
+	SourceStored = "stored"
+)
+
 // HeaderResult returns selected headers and strategy.
 type HeaderResult struct {
 	Mode    HeaderMode
 	Headers map[string]string
+	// Source tells whether headers came from environment variables or stored credentials.
+	Source string
+}
+
+// EnvCredentials holds credentials supplied through environment variables.
+type EnvCredentials struct {
+	APIKey    string
+	APISecret string
+	Token     string
 }
 
+// Mode returns the auth mode the environment selects, or "" when it supplies nothing usable.
+// Precedence: key+secret (signature) > token (bearer) > key alone (api-key only).
+func (e EnvCredentials) Mode() HeaderMode {
+	switch {
+	case e.APIKey != "" && e.APISecret != "":
+		return HeaderModeSignature
+	case e.Token != "":
+		return HeaderModeBearer
+	case e.APIKey != "":
+		return HeaderModeAPIKey
+	default:
+		return ""
+	}
+}
+
+// Vars lists the variables that are set, for status output.
+func (e EnvCredentials) Vars() []string {
+	var vars []string
+	if e.APIKey != "" {
+		vars = append(vars, EnvAPIKey)
+	}
+	if e.APISecret != "" {
+		vars = append(vars, EnvAPISecret)
+	}
+	if e.Token != "" {
+		vars = append(vars, EnvToken)
+	}
+	return vars
+}
+
+// credentialStore keeps bearer tokens per account (email; "" is the token
+// stored before accounts were tracked) and API secrets per project.
 type credentialStore interface {
-	SetBearerToken(token string) error
-	GetBearerToken() (string, error)
-	DeleteBearerToken() error
+	SetBearerToken(account, token string) error
+	GetBearerToken(account string) (string, error)
+	DeleteBearerToken(account string) error
 	SetProjectSecret(apiKey, secret string) error
 	GetProjectSecret(apiKey string) (string, error)
 	DeleteProjectSecret(apiKey string) error
@@ -41,16 +97,16 @@ type credentialStore interface {
 
 type keychainStore struct{}
 
-func (keychainStore) SetBearerToken(token string) error {
-	return secure.SetBearerToken(token)
+func (keychainStore) SetBearerToken(account, token string) error {
+	return secure.SetBearerToken(account, token)
 }
 
-func (keychainStore) GetBearerToken() (string, error) {
-	return secure.GetBearerToken()
+func (keychainStore) GetBearerToken(account string) (string, error) {
+	return secure.GetBearerToken(account)
 }
 
-func (keychainStore) DeleteBearerToken() error {
-	return secure.DeleteBearerToken()
+func (keychainStore) DeleteBearerToken(account string) error {
+	return secure.DeleteBearerToken(account)
 }
 
 func (keychainStore) SetProjectSecret(apiKey, secret string) error {
@@ -70,6 +126,10 @@ type Service struct {
 	apiClient *api.Client
 	store     credentialStore
 	nonceFn   func() string
+	getenv    func(string) string
+	nonces    *nonceSource
+	// account is the signed-in account bearer tokens are read for by default.
+	account string
 }
 
 func NewService(apiClient *api.Client) *Service {
@@ -80,15 +140,39 @@ func NewServiceWithStore(apiClient *api.Client, store credentialStore) *Service
 	if store == nil {
 		store = keychainStore{}
 	}
+	nonces := &nonceSource{}
 	return &Service{
 		apiClient: apiClient,
 		store:     store,
 		nonceFn: func() string {
-			return fmt.Sprintf("%d", time.Now().Unix())
+			return nonces.next(time.Now())
 		},
+		getenv: os.Getenv,
+		nonces: nonces,
 	}
 }
 
+// EnvCredentials reads WIRO_API_KEY / WIRO_API_SECRET / WIRO_TOKEN.
+func (s *Service) EnvCredentials() EnvCredentials {
+	return EnvCredentials{
+		APIKey:    strings.TrimSpace(s.getenv(EnvAPIKey)),
+		APISecret: strings.TrimSpace(s.getenv(EnvAPISecret)),
+		Token:     strings.TrimSpace(s.getenv(EnvToken)),
+	}
+}
+
+// UseAccount makes email the active account: its token is the one saved,
+// loaded, and refreshed unless a project is bound to another account.
+func (s *Service) UseAccount(email string) {
+	s.account = strings.TrimSpace(email)
+}
+
+// Account returns the active account, "" for the token stored before
+// accounts were tracked.
+func (s *Service) Account() string {
+	return s.account
+}
+
 // Login requests sign-in by email/password or one-time code mode.
 func (s *Service) Login(ctx context.Context, email, password string) (api.AuthSigninResponse, error) {
 	email = strings.TrimSpace(email)
@@ -126,26 +210,58 @@ func (s *Service) Verify(ctx context.Context, verifyToken, code, authCode string
 	return resp, nil
 }
 
-// SaveBearerToken stores token in keychain.
+// SaveBearerToken stores the active account's token in keychain.
 func (s *Service) SaveBearerToken(token string) error {
+	return s.SaveAccountToken(s.account, token)
+}
+
+// SaveAccountToken stores the token of account in keychain.
+func (s *Service) SaveAccountToken(account, token string) error {
 	if strings.TrimSpace(token) == "" {
 		return errors.New("token is empty")
 	}
-	return s.store.SetBearerToken(token)
+	return s.store.SetBearerToken(strings.TrimSpace(account), token)
 }
 
-// LoadBearerToken returns token if available.
+// LoadBearerToken returns the active account's token if available. WIRO_TOKEN
+// wins over the keychain.
 func (s *Service) LoadBearerToken() string {
-	tok, err := s.store.GetBearerToken()
+	if tok := strings.TrimSpace(s.getenv(EnvToken)); tok != "" {
+		return tok
+	}
+	return s.AccountToken(s.account)
+}
+
+// BearerTokenFor returns the token requests for project fall back to: that of
+// the account the project is bound to, else the active account's. WIRO_TOKEN
+// wins over both.
+func (s *Service) BearerTokenFor(project *config.ProjectProfile) string {
+	if project == nil || strings.TrimSpace(project.Account) == "" {
+		return s.LoadBearerToken()
+	}
+	if tok := strings.TrimSpace(s.getenv(EnvToken)); tok != "" {
+		return tok
+	}
+	return s.AccountToken(project.Account)
+}
+
+// AccountToken returns the keychain token of account, ignoring the environment.
+func (s *Service) AccountToken(account string) string {
+	tok, err := s.store.GetBearerToken(strings.TrimSpace(account))
 	if err != nil {
 		return ""
 	}
-	return tok
+	return strings.TrimSpace(tok)
 }
 
-// Logout removes stored bearer token.
+// Logout removes the active account's stored bearer token.
 func (s *Service) Logout() error {
-	if err := s.store.DeleteBearerToken(); err != nil {
+	return s.LogoutAccount(s.account)
+}
+
+// LogoutAccount removes the stored bearer token of account.
+func (s *Service) LogoutAccount(account string) error {
+	if err := s.store.DeleteBearerToken(strings.TrimSpace(account)); err != nil {
 		// Ignore "item not found"-style errors from backend specifics.
 		return nil
 	}
@@ -171,15 +287,116 @@ func (s *Service) DeleteProjectSecret(apiKey string) error {
 	return nil
 }
 
+// ProjectSecret returns the keychain secret of apiKey, or "" when none is stored.
+func (s *Service) ProjectSecret(apiKey string) string {
+	secret, err := s.store.GetProjectSecret(apiKey)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(secret)
+}
+
 // HasProjectSecret checks whether a project secret exists.
 func (s *Service) HasProjectSecret(apiKey string) bool {
 	secret, err := s.store.GetProjectSecret(apiKey)
 	return err == nil && strings.TrimSpace(secret) != ""
 }
 
+// Credentials are the secrets kept in the keychain, as moved between machines
+// by `wiro auth export` and `wiro auth import`.
+type Credentials struct {
+	// BearerToken is the single token of exports made before accounts were
+	// exported one by one; it is restored as the active account's token.
+	BearerToken string `json:"bearerToken,omitempty"`
+	// AccountTokens maps account emails to their bearer tokens; "" is the
+	// token stored before accounts were tracked by email.
+	AccountTokens map[string]string `json:"accountTokens,omitempty"`
+	// ProjectSecrets maps project API keys to their API secrets.
+	ProjectSecrets map[string]string `json:"projectSecrets,omitempty"`
+}
+
+// StoredCredentials reads the bearer tokens of accounts and the secrets of
+// apiKeys from the keychain; the environment is ignored. Accounts and keys
+// without a stored value are left out.
+func (s *Service) StoredCredentials(accounts, apiKeys []string) Credentials {
+	var c Credentials
+	for _, account := range accounts {
+		tok := s.AccountToken(account)
+		if tok == "" {
+			continue
+		}
+		if c.AccountTokens == nil {
+			c.AccountTokens = map[string]string{}
+		}
+		c.AccountTokens[strings.TrimSpace(account)] = tok
+	}
+	for _, key := range apiKeys {
+		secret, err := s.store.GetProjectSecret(key)
+		if err != nil || strings.TrimSpace(secret) == "" {
+			continue
+		}
+		if c.ProjectSecrets == nil {
+			c.ProjectSecrets = map[string]string{}
+		}
+		c.ProjectSecrets[key] = strings.TrimSpace(secret)
+	}
+	return c
+}
+
+// RestoreCredentials writes c to the keychain, replacing stored values. Each
+// account's token is stored under the same account.
+func (s *Service) RestoreCredentials(c Credentials) error {
+	if c.BearerToken != "" {
+		if err := s.store.SetBearerToken(s.account, c.BearerToken); err != nil {
+			return fmt.Errorf("store bearer token: %w", err)
+		}
+	}
+	for account, tok := range c.AccountTokens {
+		if err := s.store.SetBearerToken(account, tok); err != nil {
+			return fmt.Errorf("store bearer token of account %q: %w", account, err)
+		}
+	}
+	for key, secret := range c.ProjectSecrets {
+		if err := s.SaveProjectSecret(key, secret); err != nil {
+			return fmt.Errorf("store secret of %s: %w", key, err)
+		}
+	}
+	return nil
+}
+
 // BuildHeaders decides request auth headers for a selected project.
+// Credentials from the environment take precedence over the project and keychain.
 func (s *Service) BuildHeaders(project *config.ProjectProfile) (HeaderResult, error) {
-	bearer := s.LoadBearerToken()
+	defer log.Time(log.PhaseAuth, "resolve credentials")()
+	if res, ok := s.envHeaders(); ok {
+		return res, nil
+	}
+	res, err := s.storedHeaders(project)
+	res.Source = SourceStored
+	return res, err
+}
+
+func (s *Service) envHeaders() (HeaderResult, bool) {
+	env := s.EnvCredentials()
+	switch env.Mode() {
+	case HeaderModeSignature:
+		nonce := s.nonceFn()
+		return HeaderResult{Mode: HeaderModeSignature, Source: SourceEnv, Headers: map[string]string{
+			"x-api-key":   env.APIKey,
+			"x-nonce":     nonce,
+			"x-signature": ComputeSignature(env.APIKey, env.APISecret, nonce),
+		}}, true
+	case HeaderModeBearer:
+		return HeaderResult{Mode: HeaderModeBearer, Source: SourceEnv, Headers: map[string]string{"Authorization": "Bearer " + env.Token}}, true
+	case HeaderModeAPIKey:
+		return HeaderResult{Mode: HeaderModeAPIKey, Source: SourceEnv, Headers: map[string]string{"x-api-key": env.APIKey}}, true
+	default:
+		return HeaderResult{}, false
+	}
+}
+
+func (s *Service) storedHeaders(project *config.ProjectProfile) (HeaderResult, error) {
+	bearer := s.BearerTokenFor(project)
 
 	if project == nil {
 		if bearer != "" {
@@ -239,6 +456,17 @@ func (s *Service) trySignature(apiKey string) (map[string]string, bool) {
 	}, true
 }
 
+// SignatureHeaders signs a request with apiKey and apiSecret directly, ignoring
+// the environment and keychain, to check a secret before it is stored.
+func (s *Service) SignatureHeaders(apiKey, apiSecret string) map[string]string {
+	nonce := s.nonceFn()
+	return map[string]string{
+		"x-api-key":   apiKey,
+		"x-nonce":     nonce,
+		"x-signature": ComputeSignature(apiKey, apiSecret, nonce),
+	}
+}
+
 // ComputeSignature returns lower-hex HMAC-SHA256(apiSecret+nonce, key=apiKey).
 func ComputeSignature(apiKey, apiSecret, nonce string) string {
 	mac := hmac.New(sha256.New, []byte(apiKey))
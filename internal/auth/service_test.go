@@ -3,35 +3,38 @@ package auth
 import (
 	"crypto/hmac"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/wiro-ai/wiro-cli/internal/config"
 )
 
 type memoryStore struct {
-	bearer string
+	bearer map[string]string
 	secret map[string]string
 }
 
 func newMemoryStore() *memoryStore {
-	return &memoryStore{secret: map[string]string{}}
+	return &memoryStore{bearer: map[string]string{}, secret: map[string]string{}}
 }
 
-func (m *memoryStore) SetBearerToken(token string) error {
-	m.bearer = token
+func (m *memoryStore) SetBearerToken(account, token string) error {
+	m.bearer[account] = token
 	return nil
 }
 
-func (m *memoryStore) GetBearerToken() (string, error) {
-	if m.bearer == "" {
+func (m *memoryStore) GetBearerToken(account string) (string, error) {
+	if m.bearer[account] == "" {
 		return "", errNotFound
 	}
-	return m.bearer, nil
+	return m.bearer[account], nil
 }
 
-func (m *memoryStore) DeleteBearerToken() error {
-	m.bearer = ""
+func (m *memoryStore) DeleteBearerToken(account string) error {
+	delete(m.bearer, account)
 	return nil
 }
 
@@ -89,7 +92,7 @@ func TestBuildHeaders_SignaturePreferred(t *testing.T) {
 
 func TestBuildHeaders_SignatureMissingSecretFallsBackToBearer(t *testing.T) {
 	store := newMemoryStore()
-	_ = store.SetBearerToken("bearer-token")
+	_ = store.SetBearerToken("", "bearer-token")
 	svc := NewServiceWithStore(nil, store)
 
 	res, err := svc.BuildHeaders(&config.ProjectProfile{APIKey: "p-key", AuthMethodHint: "signature"})
@@ -166,7 +169,7 @@ func TestBuildHeaders_SignatureRequiresSecretOrBearer(t *testing.T) {
 
 func TestBuildHeaders_NoProjectUsesBearer(t *testing.T) {
 	store := newMemoryStore()
-	_ = store.SetBearerToken("token")
+	_ = store.SetBearerToken("", "token")
 	svc := NewServiceWithStore(nil, store)
 
 	res, err := svc.BuildHeaders(nil)
@@ -188,3 +191,208 @@ type errSentinel string
 func (e errSentinel) Error() string {
 	return string(e)
 }
+
+func TestBuildHeaders_EnvPrecedence(t *testing.T) {
+	store := newMemoryStore()
+	_ = store.SetProjectSecret("p-key", "p-secret")
+	_ = store.SetBearerToken("", "stored-token")
+	svc := NewServiceWithStore(nil, store)
+	svc.nonceFn = func() string { return "12345" }
+	project := &config.ProjectProfile{APIKey: "p-key", AuthMethodHint: "signature"}
+
+	env := map[string]string{}
+	svc.getenv = func(k string) string { return env[k] }
+
+	env[EnvAPIKey] = "env-key"
+	res, err := svc.BuildHeaders(project)
+	if err != nil || res.Mode != HeaderModeAPIKey || res.Source != SourceEnv || res.Headers["x-api-key"] != "env-key" {
+		t.Fatalf("key only: %#v %v", res, err)
+	}
+
+	env[EnvToken] = "env-token"
+	res, _ = svc.BuildHeaders(project)
+	if res.Mode != HeaderModeBearer || res.Headers["Authorization"] != "Bearer env-token" {
+		t.Fatalf("token should beat key alone: %#v", res)
+	}
+
+	env[EnvAPISecret] = "env-secret"
+	res, _ = svc.BuildHeaders(project)
+	if res.Mode != HeaderModeSignature || res.Headers["x-signature"] != ComputeSignature("env-key", "env-secret", "12345") {
+		t.Fatalf("key+secret should win: %#v", res)
+	}
+
+	env = map[string]string{}
+	res, _ = svc.BuildHeaders(project)
+	if res.Source != SourceStored || res.Headers["x-api-key"] != "p-key" {
+		t.Fatalf("stored credentials expected without env: %#v", res)
+	}
+}
+
+func TestBearerTokenPerAccount(t *testing.T) {
+	store := newMemoryStore()
+	svc := NewServiceWithStore(nil, store)
+	env := map[string]string{}
+	svc.getenv = func(k string) string { return env[k] }
+
+	svc.UseAccount("a@example.com")
+	if err := svc.SaveBearerToken("token-a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.SaveAccountToken("b@example.com", "token-b"); err != nil {
+		t.Fatal(err)
+	}
+	if got := svc.LoadBearerToken(); got != "token-a" {
+		t.Fatalf("active token = %q", got)
+	}
+	bound := &config.ProjectProfile{APIKey: "p-key", AuthMethodHint: "signature", Account: "b@example.com"}
+	res, err := svc.BuildHeaders(bound)
+	if err != nil || res.Headers["Authorization"] != "Bearer token-b" {
+		t.Fatalf("bound project should use its account: %#v %v", res, err)
+	}
+	res, _ = svc.BuildHeaders(&config.ProjectProfile{APIKey: "p-key", AuthMethodHint: "signature"})
+	if res.Headers["Authorization"] != "Bearer token-a" {
+		t.Fatalf("unbound project should use the active account: %#v", res)
+	}
+
+	env[EnvToken] = "env-token"
+	if got := svc.BearerTokenFor(bound); got != "env-token" {
+		t.Fatalf("WIRO_TOKEN should win over the bound account: %q", got)
+	}
+	delete(env, EnvToken)
+
+	if err := svc.Logout(); err != nil {
+		t.Fatal(err)
+	}
+	if svc.LoadBearerToken() != "" || svc.AccountToken("b@example.com") != "token-b" {
+		t.Fatalf("logout should only remove the active account: %#v", store.bearer)
+	}
+}
+
+func TestTokenEmail(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"1","email":"a@example.com"}`))
+	if email, ok := TokenEmail("eyJhbGciOiJIUzI1NiJ9." + payload + ".sig"); !ok || email != "a@example.com" {
+		t.Fatalf("TokenEmail = %q, %v", email, ok)
+	}
+	if _, ok := TokenEmail("opaque-token"); ok {
+		t.Fatal("opaque token should have no email")
+	}
+}
+
+func TestTokenExpiry(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"1","exp":1767225600}`))
+	exp, ok := TokenExpiry("eyJhbGciOiJIUzI1NiJ9." + payload + ".sig")
+	if !ok || !exp.Equal(time.Unix(1767225600, 0)) {
+		t.Fatalf("TokenExpiry = %v, %v", exp, ok)
+	}
+	if _, ok := TokenExpiry("opaque-token"); ok {
+		t.Fatal("opaque token should have no expiry")
+	}
+}
+
+func TestNonceSourceIncreases(t *testing.T) {
+	var n nonceSource
+	now := time.UnixMilli(1_700_000_000_000)
+	a, b := n.next(now), n.next(now)
+	if a != "1700000000000" || b != "1700000000001" {
+		t.Fatalf("nonces = %s, %s", a, b)
+	}
+	n.setOffset(10 * time.Second)
+	if got := n.next(now); got != "1700000010000" {
+		t.Fatalf("offset nonce = %s", got)
+	}
+}
+
+func TestResignFollowsServerClock(t *testing.T) {
+	store := newMemoryStore()
+	store.secret["p-key"] = "p-secret"
+	svc := NewServiceWithStore(nil, store)
+	svc.getenv = func(string) string { return "" }
+
+	server := time.Now().Add(2 * time.Minute).UTC().Format(time.RFC1123)
+	signed, err := svc.Resign(map[string]string{"x-api-key": "p-key", "x-nonce": "1"}, server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if svc.nonces.offset < time.Minute {
+		t.Fatalf("offset = %s, want about 2m", svc.nonces.offset)
+	}
+	if signed["x-signature"] != ComputeSignature("p-key", "p-secret", signed["x-nonce"]) {
+		t.Fatalf("signature does not match nonce: %v", signed)
+	}
+	if _, err := svc.Resign(map[string]string{"x-api-key": "other"}, ""); err == nil {
+		t.Fatal("expected an error without a secret")
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	long := "abcd0123456789012345678901234567wxyz"
+	fp := Fingerprint("key-1", long)
+	if !strings.HasPrefix(fp, "abcd…wxyz ") || len(fp) != len("abcd…wxyz ")+8 {
+		t.Fatalf("fingerprint = %q", fp)
+	}
+	if strings.Contains(fp, "0123") {
+		t.Fatalf("fingerprint reveals the middle of the secret: %q", fp)
+	}
+	if Fingerprint("key-2", long) == fp {
+		t.Fatal("fingerprint should depend on the api key")
+	}
+	if got := Fingerprint("key-1", "short-secret"); len(got) != 8 || strings.Contains(got, "shor") {
+		t.Fatalf("short secret fingerprint = %q", got)
+	}
+	if Fingerprint("key-1", "  ") != "" {
+		t.Fatal("empty secret should have no fingerprint")
+	}
+
+	store := newMemoryStore()
+	svc := NewServiceWithStore(nil, store)
+	if svc.ProjectSecretFingerprint("key-1") != "" {
+		t.Fatal("no stored secret should have no fingerprint")
+	}
+	store.secret["key-1"] = long
+	if got := svc.ProjectSecretFingerprint("key-1"); got != fp {
+		t.Fatalf("stored fingerprint = %q, want %q", got, fp)
+	}
+}
+
+func TestStoredCredentialsRoundTrip(t *testing.T) {
+	src := newMemoryStore()
+	src.bearer[""] = "tok"
+	src.bearer["a@example.com"] = "tok-a"
+	src.bearer["b@example.com"] = "tok-b"
+	src.secret["k1"] = "s1"
+	from := NewServiceWithStore(nil, src)
+	from.UseAccount("a@example.com")
+	from.getenv = func(string) string { return "env-value" }
+	creds := from.StoredCredentials([]string{"", "a@example.com", "b@example.com", "c@example.com"}, []string{"k1", "k2"})
+	if len(creds.AccountTokens) != 3 || creds.AccountTokens["b@example.com"] != "tok-b" || creds.BearerToken != "" {
+		t.Fatalf("credentials = %+v", creds)
+	}
+	if len(creds.ProjectSecrets) != 1 || creds.ProjectSecrets["k1"] != "s1" {
+		t.Fatalf("secrets = %+v", creds.ProjectSecrets)
+	}
+
+	dst := newMemoryStore()
+	to := NewServiceWithStore(nil, dst)
+	to.UseAccount("other@example.com")
+	if err := to.RestoreCredentials(creds); err != nil {
+		t.Fatal(err)
+	}
+	if dst.bearer[""] != "tok" || dst.bearer["a@example.com"] != "tok-a" || dst.bearer["b@example.com"] != "tok-b" || dst.secret["k1"] != "s1" {
+		t.Fatalf("restored store = %+v", dst)
+	}
+	if _, ok := dst.bearer["other@example.com"]; ok {
+		t.Fatal("account tokens should not be stored under the active account")
+	}
+
+	// Exports from before accounts were exported carry one token for the
+	// active account.
+	legacy := newMemoryStore()
+	to = NewServiceWithStore(nil, legacy)
+	to.UseAccount("other@example.com")
+	if err := to.RestoreCredentials(Credentials{BearerToken: "old"}); err != nil {
+		t.Fatal(err)
+	}
+	if legacy.bearer["other@example.com"] != "old" {
+		t.Fatalf("legacy restore = %+v", legacy.bearer)
+	}
+}
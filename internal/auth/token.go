@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+// ErrTokenFromEnv is returned when asked to refresh a token supplied through WIRO_TOKEN.
+var ErrTokenFromEnv = errors.New("WIRO_TOKEN cannot be refreshed; set a new token in the environment")
+
+// tokenClaims are the JWT claims the CLI reads.
+type tokenClaims struct {
+	Exp   json.Number `json:"exp"`
+	Email string      `json:"email"`
+}
+
+// parseClaims decodes the payload of a JWT. ok is false for opaque tokens.
+func parseClaims(token string) (tokenClaims, bool) {
+	var claims tokenClaims
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return claims, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return claims, false
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return claims, false
+	}
+	return claims, true
+}
+
+// TokenEmail reads the email claim when token is a JWT that carries one.
+func TokenEmail(token string) (string, bool) {
+	claims, ok := parseClaims(token)
+	email := strings.TrimSpace(claims.Email)
+	return email, ok && email != ""
+}
+
+// TokenExpiry reads the exp claim when token is a JWT. ok is false for opaque tokens.
+func TokenExpiry(token string) (time.Time, bool) {
+	claims, ok := parseClaims(token)
+	if !ok || claims.Exp == "" {
+		return time.Time{}, false
+	}
+	exp, err := claims.Exp.Float64()
+	if err != nil || exp <= 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(exp), 0), true
+}
+
+// RefreshBearerToken exchanges the active account's stored bearer token for a new one and stores it.
+func (s *Service) RefreshBearerToken(ctx context.Context) (string, error) {
+	return s.RefreshAccountToken(ctx, s.account)
+}
+
+// RefreshAccountToken exchanges the stored bearer token of account for a new one and stores it.
+func (s *Service) RefreshAccountToken(ctx context.Context, account string) (string, error) {
+	if strings.TrimSpace(s.getenv(EnvToken)) != "" {
+		return "", ErrTokenFromEnv
+	}
+	current, err := s.store.GetBearerToken(account)
+	if err != nil || strings.TrimSpace(current) == "" {
+		return "", errors.New("no stored bearer token to refresh")
+	}
+	var resp api.AuthSigninVerifyResponse
+	// A rejected refresh must not trigger another refresh.
+	ctx = api.WithoutTokenRefresh(ctx)
+	headers := map[string]string{"Authorization": "Bearer " + current}
+	if err := s.apiClient.PostJSON(ctx, "/Auth/RefreshToken", map[string]interface{}{}, headers, &resp); err != nil {
+		return "", err
+	}
+	if !resp.Result && len(resp.Errors) > 0 {
+		return "", api.ResponseError(resp.Errors)
+	}
+	if strings.TrimSpace(resp.Token) == "" {
+		return "", errors.New("token refresh returned no token")
+	}
+	if err := s.store.SetBearerToken(account, resp.Token); err != nil {
+		return "", err
+	}
+	return resp.Token, nil
+}
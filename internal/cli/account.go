@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/log"
+	"github.com/wiro-ai/wiro-cli/internal/model"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+)
+
+func accountCommand(ctx context.Context, app *App, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: wiro account <balance|usage> ...")
+	}
+	sub := strings.TrimSpace(args[0])
+	switch sub {
+	case "balance":
+		return accountBalanceCommand(ctx, app, args[1:])
+	case "usage":
+		return accountUsageCommand(ctx, app, args[1:])
+	case "--help", "-h", "help":
+		output.Println("Usage: wiro account <balance|usage> ...")
+		return nil
+	default:
+		return fmt.Errorf("unknown account command %q", sub)
+	}
+}
+
+func accountBalanceCommand(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("account balance", flag.ContinueOnError)
+	var projectSelector string
+	var asJSON bool
+	fs.StringVar(&projectSelector, "project", "", "Project name or API key for auth context")
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if len(fs.Args()) != 0 {
+		return errors.New("usage: wiro account balance [--project <name|apikey>] [--json]")
+	}
+
+	headers, err := resolveRequestHeaders(app, projectSelector)
+	if err != nil {
+		return err
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	balance, err := app.AccountSvc.Balance(timeoutCtx, headers)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return output.PrintJSON(balance)
+	}
+	output.Printf("Balance: %s\n", formatCredits(balance.Credits, balance.Currency))
+	return nil
+}
+
+func accountUsageCommand(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("account usage", flag.ContinueOnError)
+	var projectSelector string
+	var days int
+	var asJSON bool
+	fs.StringVar(&projectSelector, "project", "", "Project name or API key for auth context")
+	fs.IntVar(&days, "days", 30, "Number of days to include")
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if len(fs.Args()) != 0 {
+		return errors.New("usage: wiro account usage [--days N] [--project <name|apikey>] [--json]")
+	}
+
+	headers, err := resolveRequestHeaders(app, projectSelector)
+	if err != nil {
+		return err
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	usage, err := app.AccountSvc.Usage(timeoutCtx, days, headers)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return output.PrintJSON(usage)
+	}
+	output.Printf("Usage (last %d days): %s\n", usage.Days, formatCredits(usage.Total, ""))
+	for _, item := range usage.Items {
+		output.Printf("- %s %s tasks=%d credits=%s\n", item.Date, item.Model, item.Tasks, formatCredits(item.Credits, ""))
+	}
+	return nil
+}
+
+func formatCredits(v float64, currency string) string {
+	s := fmt.Sprintf("%.4f", v)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if currency != "" {
+		return s + " " + currency
+	}
+	return s
+}
+
+// checkCreditBeforeRun warns when the estimated price exceeds the remaining balance.
+// Balance lookups are best-effort: failures never block a run.
+func checkCreditBeforeRun(ctx context.Context, app *App, detail *api.ToolDetail, inputs map[string][]api.MultipartValue, headers map[string]string) error {
+	if !warnCreditShortfall(ctx, app, detail, inputs, headers) || !isInteractiveSession() {
+		return nil
+	}
+	proceed, err := promptConfirm("Submit anyway?", false)
+	if err != nil {
+		return err
+	}
+	if !proceed {
+		return errors.New("run aborted: estimated cost exceeds remaining credit")
+	}
+	return nil
+}
+
+// warnCreditShortfall warns, without prompting, when the estimated price exceeds
+// the remaining balance and reports whether it did.
+func warnCreditShortfall(ctx context.Context, app *App, detail *api.ToolDetail, inputs map[string][]api.MultipartValue, headers map[string]string) bool {
+	estimate, ok := model.EstimatePrice(detail, inputs)
+	if !ok {
+		return false
+	}
+	balanceCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	balance, err := app.AccountSvc.Balance(balanceCtx, headers)
+	if err != nil {
+		log.Verbosef("credit check skipped: %v", err)
+		return false
+	}
+	if estimate <= balance.Credits {
+		return false
+	}
+	output.Warnf("estimated cost %s exceeds remaining credit %s", formatCredits(estimate, ""), formatCredits(balance.Credits, balance.Currency))
+	return true
+}
@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/auth"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	projectsvc "github.com/wiro-ai/wiro-cli/internal/project"
+)
+
+const authSwitchUsage = "usage: wiro auth switch <email> [--project <name|apikey>] | wiro auth switch --project <name|apikey> --unbind"
+
+// accountStatus is one signed-in account in wiro auth accounts.
+type accountStatus struct {
+	// Email is empty for the token stored before accounts were tracked.
+	Email          string `json:"email"`
+	Active         bool   `json:"active"`
+	LoggedIn       bool   `json:"loggedIn"`
+	TokenExpiresAt string `json:"tokenExpiresAt,omitempty"`
+	TokenExpired   bool   `json:"tokenExpired,omitempty"`
+	// Projects are the local projects bound to the account.
+	Projects []string `json:"projects,omitempty"`
+}
+
+// signedInAccounts lists the accounts with a recorded token, plus the token
+// stored before accounts were tracked when one is still in the keychain.
+func signedInAccounts(app *App) []accountStatus {
+	emails := app.State.AccountEmails()
+	if app.AuthSvc.AccountToken("") != "" {
+		emails = append([]string{""}, emails...)
+	}
+	out := make([]accountStatus, 0, len(emails))
+	for _, email := range emails {
+		a := accountStatus{
+			Email:          email,
+			Active:         email == app.AuthSvc.Account(),
+			LoggedIn:       app.AuthSvc.AccountToken(email) != "",
+			TokenExpiresAt: app.State.Token(email).ExpiresAt,
+		}
+		if exp, ok := accountTokenExpiry(app.State.Token(email)); ok {
+			a.TokenExpired = time.Now().After(exp)
+		}
+		for _, p := range app.Config.Projects {
+			if email != "" && p.Account == email {
+				a.Projects = append(a.Projects, p.Name)
+			}
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+func authAccountsCommand(app *App, args []string) error {
+	fs := flag.NewFlagSet("auth accounts", flag.ContinueOnError)
+	var asJSON bool
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if len(fs.Args()) != 0 {
+		return errors.New("usage: wiro auth accounts [--json]")
+	}
+	accounts := signedInAccounts(app)
+	if asJSON {
+		return output.PrintJSON(accounts)
+	}
+	if len(accounts) == 0 {
+		output.Println("No accounts signed in; run `wiro auth login`.")
+		return nil
+	}
+	for _, a := range accounts {
+		mark := "  "
+		if a.Active {
+			mark = "* "
+		}
+		name := a.Email
+		if name == "" {
+			name = "token signed in before accounts were tracked"
+			if email, ok := auth.TokenEmail(app.AuthSvc.AccountToken("")); ok {
+				name = email + ", signed in before accounts were tracked"
+			}
+		}
+		var notes []string
+		switch {
+		case !a.LoggedIn:
+			notes = append(notes, "token missing; run `wiro auth login`")
+		case a.TokenExpired:
+			notes = append(notes, "token expired")
+		}
+		if len(a.Projects) > 0 {
+			notes = append(notes, "projects: "+strings.Join(a.Projects, ", "))
+		}
+		if len(notes) > 0 {
+			name += " (" + strings.Join(notes, "; ") + ")"
+		}
+		output.Println(mark + name)
+	}
+	return nil
+}
+
+// authSwitchCommand changes the active account, or with --project binds one
+// project to an account so it keeps using that account's token.
+func authSwitchCommand(app *App, args []string) error {
+	fs := flag.NewFlagSet("auth switch", flag.ContinueOnError)
+	var selector string
+	var unbind bool
+	fs.StringVar(&selector, "project", "", "Bind this project to the account instead of switching")
+	fs.BoolVar(&unbind, "unbind", false, "With --project, bind the project back to the active account")
+	var rest []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		rest, args = append(rest, args[0]), args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	rest = append(rest, fs.Args()...)
+	if unbind {
+		if selector == "" || len(rest) != 0 {
+			return errors.New(authSwitchUsage)
+		}
+	} else if err := requireArgs(rest, 1, authSwitchUsage); err != nil {
+		return err
+	}
+
+	email := ""
+	if !unbind {
+		email = strings.TrimSpace(rest[0])
+		if _, ok := app.State.Accounts[email]; !ok || app.AuthSvc.AccountToken(email) == "" {
+			return fmt.Errorf("account %q is not signed in; run `wiro auth login --email %s`", email, email)
+		}
+	}
+	if selector != "" {
+		profile := projectsvc.ResolveSelected(app.Config, selector)
+		if profile == nil {
+			return fmt.Errorf("project %q not found in local config", selector)
+		}
+		profile.Account = email
+		if err := app.SaveConfig(); err != nil {
+			return err
+		}
+		if unbind {
+			output.Printf("%s now uses the active account.\n", profile.Name)
+		} else {
+			output.Printf("%s now uses %s.\n", profile.Name, email)
+		}
+		return nil
+	}
+	if err := app.switchAccount(email); err != nil {
+		return err
+	}
+	output.Printf("Active account: %s\n", email)
+	return nil
+}
@@ -0,0 +1,163 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+)
+
+const aliasSetUsage = "usage: wiro alias set <name> <owner/model> [--set key=value ...]"
+
+// modelAliases is the config's alias table, consulted by parseModelArg.
+var modelAliases map[string]config.ModelAlias
+
+var aliasName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// aliasEntry is one row of `wiro alias ls --json`.
+type aliasEntry struct {
+	Name  string   `json:"name"`
+	Model string   `json:"model"`
+	Set   []string `json:"set,omitempty"`
+}
+
+func aliasCommand(app *App, args []string) error {
+	usage := "usage: wiro alias <set|ls|rm> ..."
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	sub := strings.TrimSpace(args[0])
+	switch sub {
+	case "set":
+		return aliasSetCommand(app, args[1:])
+	case "ls", "list":
+		return aliasListCommand(app, args[1:])
+	case "rm", "remove":
+		return aliasRemoveCommand(app, args[1:])
+	case "--help", "-h", "help":
+		output.Println("Usage: wiro alias <set|ls|rm> ...")
+		return nil
+	default:
+		return fmt.Errorf("unknown alias command %q", sub)
+	}
+}
+
+func aliasSetCommand(app *App, args []string) error {
+	fs := flag.NewFlagSet("alias set", flag.ContinueOnError)
+	var setVals stringSlice
+	fs.Var(&setVals, "set", "Default field value (key=value) applied when the alias is used. Repeatable")
+
+	var rest []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		rest, args = append(rest, args[0]), args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	rest = append(rest, fs.Args()...)
+	if err := requireArgs(rest, 2, aliasSetUsage); err != nil {
+		return err
+	}
+	name, model := strings.TrimSpace(rest[0]), strings.TrimSpace(rest[1])
+	if !aliasName.MatchString(name) {
+		return fmt.Errorf("alias name %q may only contain letters, digits, '.', '_', and '-'", name)
+	}
+	// Aliases point at models, not at other aliases, so expansion never loops.
+	if _, _, err := splitModel(model); err != nil {
+		return err
+	}
+	if _, err := parseKeyValuePairs(setVals); err != nil {
+		return err
+	}
+
+	if app.Config.Aliases == nil {
+		app.Config.Aliases = map[string]config.ModelAlias{}
+	}
+	_, replaced := app.Config.Aliases[name]
+	app.Config.Aliases[name] = config.ModelAlias{Model: model, Set: setVals}
+	if err := app.SaveConfig(); err != nil {
+		return err
+	}
+	modelAliases = app.Config.Aliases
+	verb := "Added"
+	if replaced {
+		verb = "Updated"
+	}
+	output.Printf("%s alias %s -> %s\n", verb, name, model)
+	return nil
+}
+
+func aliasListCommand(app *App, args []string) error {
+	fs := flag.NewFlagSet("alias ls", flag.ContinueOnError)
+	var asJSON bool
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() > 0 {
+		return errors.New("usage: wiro alias ls [--json]")
+	}
+	names := make([]string, 0, len(app.Config.Aliases))
+	for name := range app.Config.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	entries := make([]aliasEntry, 0, len(names))
+	for _, name := range names {
+		a := app.Config.Aliases[name]
+		entries = append(entries, aliasEntry{Name: name, Model: a.Model, Set: a.Set})
+	}
+	if asJSON {
+		return output.PrintJSON(entries)
+	}
+	if len(entries) == 0 {
+		output.Println("No aliases. Add one with `wiro alias set <name> <owner/model>`.")
+		return nil
+	}
+	table := output.NewTable("ALIAS", "MODEL", "DEFAULTS")
+	for _, e := range entries {
+		table.Row(e.Name, e.Model, output.Dash(strings.Join(e.Set, " ")))
+	}
+	return table.Print()
+}
+
+func aliasRemoveCommand(app *App, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: wiro alias rm <name...>")
+	}
+	for _, name := range args {
+		if _, ok := app.Config.Aliases[name]; !ok {
+			return fmt.Errorf("alias %q not found (see `wiro alias ls`)", name)
+		}
+	}
+	for _, name := range args {
+		delete(app.Config.Aliases, name)
+	}
+	if err := app.SaveConfig(); err != nil {
+		return err
+	}
+	for _, name := range args {
+		output.Printf("Removed alias %s\n", name)
+	}
+	return nil
+}
+
+// withAliasDefaults puts an alias's --set values before the command line's,
+// dropping those the command line sets again.
+func withAliasDefaults(defaults, set, setFile, setURL []string) []string {
+	if len(defaults) == 0 {
+		return set
+	}
+	return append(overrideKeys(defaults, set, setFile, setURL), set...)
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/config"
+)
+
+func TestExpandModelArgAlias(t *testing.T) {
+	saved := modelAliases
+	defer func() { modelAliases = saved }()
+	modelAliases = map[string]config.ModelAlias{
+		"sdxl": {Model: "stability/sdxl", Set: []string{"steps=30", "guidance=7"}},
+	}
+
+	owner, slug, defaults, err := expandModelArg("sdxl")
+	if err != nil || owner != "stability" || slug != "sdxl" {
+		t.Fatalf("expandModelArg(sdxl) = %q, %q, %v", owner, slug, err)
+	}
+	if !reflect.DeepEqual(defaults, []string{"steps=30", "guidance=7"}) {
+		t.Fatalf("defaults = %q", defaults)
+	}
+	if owner, slug, err := parseModelArg("wiro/example"); err != nil || owner != "wiro" || slug != "example" {
+		t.Fatalf("parseModelArg(wiro/example) = %q, %q, %v", owner, slug, err)
+	}
+	if _, _, err := parseModelArg("unknown"); err == nil {
+		t.Fatal("unknown alias was accepted")
+	}
+
+	got := withAliasDefaults(defaults, []string{"prompt=fox", "steps=50"}, nil, nil)
+	want := []string{"guidance=7", "prompt=fox", "steps=50"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("withAliasDefaults = %q, want %q", got, want)
+	}
+}
@@ -1,23 +1,42 @@
 package cli
 
 import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/wiro-ai/wiro-cli/internal/account"
 	"github.com/wiro-ai/wiro-cli/internal/api"
 	"github.com/wiro-ai/wiro-cli/internal/auth"
 	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/log"
 	"github.com/wiro-ai/wiro-cli/internal/model"
+	"github.com/wiro-ai/wiro-cli/internal/output"
 	"github.com/wiro-ai/wiro-cli/internal/project"
 	"github.com/wiro-ai/wiro-cli/internal/task"
+	"github.com/wiro-ai/wiro-cli/internal/workspace"
 )
 
 // App wires services and persisted config/state.
 type App struct {
 	APIClient  *api.Client
+	AccountSvc *account.Service
 	AuthSvc    *auth.Service
 	ProjectSvc *project.Service
 	ModelSvc   *model.Service
 	TaskSvc    *task.Service
 	Config     config.Config
 	State      config.State
+	Workspace  *workspace.Workspace
+	// WorkspaceConfig holds the defaults the workspace's .wiro.yaml pins.
+	WorkspaceConfig workspace.Config
+
+	dedupeOnce  sync.Once
+	dedupeIndex *output.DedupeIndex
+
+	// refreshMu serializes bearer token refreshes across concurrent requests.
+	refreshMu    sync.Mutex
+	reloginTried bool
 }
 
 func NewApp() (*App, error) {
@@ -29,18 +48,43 @@ func NewApp() (*App, error) {
 	if err != nil {
 		return nil, err
 	}
-	apiClient := api.NewClient("")
+	// A broken parent directory should not block commands that never touch outputs.
+	ws, _ := workspace.Discover()
+	wsCfg, err := ws.Config()
+	if err != nil {
+		output.Warnf("ignoring workspace config: %v", err)
+		wsCfg = workspace.Config{}
+	}
+	apiClient := api.NewClient(cfg.APIBaseURL)
+	apiClient.SetMaxResponseBytes(int64(cfg.Preferences.MaxResponseMB) << 20)
+	apiClient.SetRateLimit(cfg.Preferences.RequestsPerSecond, cfg.Preferences.RequestBurst)
+	apiClient.SetResponseCache(&responseCache{})
 	authSvc := auth.NewService(apiClient)
+	authSvc.UseAccount(cfg.ActiveAccount)
 
-	return &App{
+	app := &App{
 		APIClient:  apiClient,
+		AccountSvc: account.NewService(apiClient),
 		AuthSvc:    authSvc,
 		ProjectSvc: project.NewService(apiClient, authSvc),
 		ModelSvc:   model.NewService(apiClient),
 		TaskSvc:    task.NewService(apiClient),
 		Config:     cfg,
 		State:      st,
-	}, nil
+		Workspace:  ws,
+
+		WorkspaceConfig: wsCfg,
+	}
+	apiClient.SetTokenRefresher(app.refreshBearer)
+	apiClient.SetResigner(func(rejected map[string]string, serverDate string) (map[string]string, bool) {
+		signed, err := authSvc.Resign(rejected, serverDate)
+		if err != nil {
+			log.Verbosef("auth: re-sign: %v", err)
+			return nil, false
+		}
+		return signed, true
+	})
+	return app, nil
 }
 
 func (a *App) SaveConfig() error {
@@ -50,3 +94,43 @@ func (a *App) SaveConfig() error {
 func (a *App) SaveState() error {
 	return config.SaveState(a.State)
 }
+
+// ResolveOutputDir anchors an output directory at the active workspace root.
+func (a *App) ResolveOutputDir(dir string) string {
+	return a.Workspace.ResolvePath(dir)
+}
+
+// WorkspaceName is what {workspace} names in output layouts: the workspace
+// root's directory name, else the current directory's.
+func (a *App) WorkspaceName() string {
+	if a.Workspace != nil {
+		return filepath.Base(a.Workspace.Root)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	return filepath.Base(wd)
+}
+
+// OutputDirDefault is the output directory used without --output-dir: the
+// workspace's outputDir, else preferences.outputDirDefault.
+func (a *App) OutputDirDefault() string {
+	if a.WorkspaceConfig.OutputDir != "" {
+		return a.WorkspaceConfig.OutputDir
+	}
+	return a.Config.Preferences.OutputDirDefault
+}
+
+// DedupeIndex returns the shared download index under <config>/outputs-index.json,
+// or nil when the config dir is unavailable.
+func (a *App) DedupeIndex() *output.DedupeIndex {
+	a.dedupeOnce.Do(func() {
+		dir, err := config.Dir()
+		if err != nil {
+			return
+		}
+		a.dedupeIndex = output.OpenDedupeIndex(filepath.Join(dir, "outputs-index.json"))
+	})
+	return a.dedupeIndex
+}
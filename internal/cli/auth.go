@@ -8,13 +8,15 @@ import (
 	"strings"
 	"time"
 
+	"github.com/wiro-ai/wiro-cli/internal/auth"
 	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/log"
 	"github.com/wiro-ai/wiro-cli/internal/output"
 )
 
 func authCommand(ctx context.Context, app *App, args []string) error {
 	if len(args) == 0 {
-		return errors.New("usage: wiro auth <login|verify|set|status|logout> ...")
+		return errors.New("usage: wiro auth <login|verify|set|status|accounts|switch|logout|export|import> ...")
 	}
 	sub := strings.TrimSpace(args[0])
 	switch sub {
@@ -26,10 +28,18 @@ func authCommand(ctx context.Context, app *App, args []string) error {
 		return authSetCommand(app, args[1:])
 	case "status":
 		return authStatusCommand(app, args[1:])
+	case "accounts":
+		return authAccountsCommand(app, args[1:])
+	case "switch":
+		return authSwitchCommand(app, args[1:])
 	case "logout":
 		return authLogoutCommand(app, args[1:])
+	case "export":
+		return authExportCommand(app, args[1:])
+	case "import":
+		return authImportCommand(app, args[1:])
 	case "--help", "-h", "help":
-		fmt.Println("Usage: wiro auth <login|verify|set|status|logout> ...")
+		output.Println("Usage: wiro auth <login|verify|set|status|accounts|switch|logout|export|import> ...")
 		return nil
 	default:
 		return fmt.Errorf("unknown auth command %q", sub)
@@ -40,9 +50,11 @@ func authLoginCommand(ctx context.Context, app *App, args []string) error {
 	fs := flag.NewFlagSet("auth login", flag.ContinueOnError)
 	var email string
 	var password string
-	var asJSON bool
+	var asJSON, device, noBrowser bool
 	fs.StringVar(&email, "email", "", "Email address")
 	fs.StringVar(&password, "password", "", "Password (optional; leave empty for one-time code flow)")
+	fs.BoolVar(&device, "device", false, "Sign in in the browser with a device code")
+	fs.BoolVar(&noBrowser, "no-browser", false, "With --device, print the URL instead of opening a browser")
 	fs.BoolVar(&asJSON, "json", false, "JSON output")
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
@@ -51,7 +63,13 @@ func authLoginCommand(ctx context.Context, app *App, args []string) error {
 		return err
 	}
 	if len(fs.Args()) != 0 {
-		return errors.New("usage: wiro auth login [--email <email>] [--password <password>] [--json]")
+		return errors.New("usage: wiro auth login [--email <email>] [--password <password>] [--device [--no-browser]] [--json]")
+	}
+	if device {
+		if email != "" || password != "" {
+			return errors.New("--device cannot be combined with --email or --password")
+		}
+		return authDeviceLogin(ctx, app, noBrowser || !isInteractiveSession(), asJSON)
 	}
 
 	if strings.TrimSpace(email) == "" {
@@ -86,14 +104,15 @@ func authLoginCommand(ctx context.Context, app *App, args []string) error {
 
 	if strings.TrimSpace(resp.VerifyToken) != "" || resp.EmailVerifyRequired == 1 || resp.PhoneVerifyRequired == 1 || resp.TwoFactorRequired == 1 {
 		app.State.PendingVerifyToken = resp.VerifyToken
+		app.State.PendingVerifyEmail = strings.TrimSpace(email)
 		if err := app.SaveState(); err != nil {
 			return err
 		}
-		fmt.Println("Verification required.")
+		output.Println("Verification required.")
 		if strings.TrimSpace(resp.VerifyToken) != "" {
-			fmt.Printf("Run: wiro auth verify %s <code> [--authcode <2fa>]\n", resp.VerifyToken)
+			output.Printf("Run: wiro auth verify %s <code> [--authcode <2fa>]\n", resp.VerifyToken)
 		} else {
-			fmt.Println("Run: wiro auth verify <verifytoken> <code> [--authcode <2fa>]")
+			output.Println("Run: wiro auth verify <verifytoken> <code> [--authcode <2fa>]")
 		}
 		return nil
 	}
@@ -101,14 +120,49 @@ func authLoginCommand(ctx context.Context, app *App, args []string) error {
 	if strings.TrimSpace(resp.Token) == "" {
 		return errors.New("login succeeded but token is empty")
 	}
-	if err := app.AuthSvc.SaveBearerToken(resp.Token); err != nil {
+	if err := app.storeBearerToken(email, resp.Token); err != nil {
 		return err
 	}
-	app.State.PendingVerifyToken = ""
-	if err := app.SaveState(); err != nil {
+	output.Printf("Login successful as %s. Bearer token stored in keychain.\n", app.AuthSvc.Account())
+	return nil
+}
+
+// authDeviceLogin signs in through the browser: it shows a verification URL and
+// code, then polls until the user approves and stores the bearer token.
+func authDeviceLogin(ctx context.Context, app *App, noBrowser, asJSON bool) error {
+	startCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	dc, err := app.AuthSvc.StartDeviceLogin(startCtx)
+	cancel()
+	if err != nil {
+		return err
+	}
+	// Instructions go to stderr so --json keeps stdout machine-readable.
+	output.Alertf("Open %s and enter code %s\n", dc.VerificationURL, dc.UserCode)
+	if !noBrowser {
+		target := dc.CompleteURL
+		if target == "" {
+			target = dc.VerificationURL
+		}
+		if err := openPath(target); err != nil {
+			log.Verbosef("auth: open browser: %v", err)
+		}
+	}
+	if !dc.ExpiresAt.IsZero() {
+		output.Notef("Waiting for approval (code expires in %s)...\n", time.Until(dc.ExpiresAt).Round(time.Second))
+	} else {
+		output.Notef("Waiting for approval...\n")
+	}
+	token, err := app.AuthSvc.PollDeviceLogin(ctx, dc)
+	if err != nil {
 		return err
 	}
-	fmt.Println("Login successful. Bearer token stored in keychain.")
+	if err := app.storeBearerToken("", token); err != nil {
+		return err
+	}
+	if asJSON {
+		return output.PrintJSON(map[string]interface{}{"loggedIn": true, "account": app.AuthSvc.Account(), "tokenExpiresAt": app.State.Token(app.AuthSvc.Account()).ExpiresAt})
+	}
+	output.Println("Login successful. Bearer token stored in keychain.")
 	return nil
 }
 
@@ -145,14 +199,10 @@ func authVerifyCommand(ctx context.Context, app *App, args []string) error {
 	if strings.TrimSpace(resp.Token) == "" {
 		return errors.New("verify succeeded but token is empty")
 	}
-	if err := app.AuthSvc.SaveBearerToken(resp.Token); err != nil {
+	if err := app.storeBearerToken(app.State.PendingVerifyEmail, resp.Token); err != nil {
 		return err
 	}
-	app.State.PendingVerifyToken = ""
-	if err := app.SaveState(); err != nil {
-		return err
-	}
-	fmt.Println("Verification successful. Bearer token stored in keychain.")
+	output.Println("Verification successful. Bearer token stored in keychain.")
 	return nil
 }
 
@@ -189,6 +239,13 @@ func authSetCommand(app *App, args []string) error {
 	}
 
 	if strings.TrimSpace(apiSecret) != "" {
+		ok, err := confirmNewSecret(app, apiKey, apiSecret)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errors.New("secret not saved")
+		}
 		if err := app.AuthSvc.SaveProjectSecret(apiKey, apiSecret); err != nil {
 			return err
 		}
@@ -207,7 +264,7 @@ func authSetCommand(app *App, args []string) error {
 	if err := app.SaveConfig(); err != nil {
 		return err
 	}
-	fmt.Printf("Project credentials saved for %s (%s).\n", profile.Name, profile.APIKey)
+	output.Printf("Project credentials saved for %s (%s).\n", profile.Name, profile.APIKey)
 	return nil
 }
 
@@ -230,57 +287,159 @@ func authStatusCommand(app *App, args []string) error {
 		APIKey         string `json:"apiKey"`
 		AuthMethodHint string `json:"authMethodHint"`
 		HasSecret      bool   `json:"hasSecret"`
+		// SecretFingerprint identifies the stored secret; see auth.Fingerprint.
+		SecretFingerprint string `json:"secretFingerprint,omitempty"`
 	}
 	type statusOut struct {
+		CredentialSource   string          `json:"credentialSource"`
+		EnvAuthMode        string          `json:"envAuthMode,omitempty"`
+		EnvVars            []string        `json:"envVars,omitempty"`
 		LoggedIn           bool            `json:"loggedIn"`
+		Account            string          `json:"account,omitempty"`
+		TokenIssuedAt      string          `json:"tokenIssuedAt,omitempty"`
+		TokenExpiresAt     string          `json:"tokenExpiresAt,omitempty"`
+		TokenExpired       bool            `json:"tokenExpired,omitempty"`
 		PendingVerifyToken bool            `json:"pendingVerifyToken"`
 		DefaultProject     string          `json:"defaultProject"`
 		Projects           []projectStatus `json:"projects"`
 	}
 
+	env := app.AuthSvc.EnvCredentials()
 	out := statusOut{
+		CredentialSource:   auth.SourceStored,
+		EnvAuthMode:        string(env.Mode()),
+		EnvVars:            env.Vars(),
 		LoggedIn:           app.AuthSvc.LoadBearerToken() != "",
 		PendingVerifyToken: strings.TrimSpace(app.State.PendingVerifyToken) != "",
 		DefaultProject:     app.Config.DefaultProject,
 		Projects:           make([]projectStatus, 0, len(app.Config.Projects)),
 	}
+	if out.LoggedIn && env.Token == "" {
+		tok := app.State.Token(app.AuthSvc.Account())
+		out.Account = app.AuthSvc.Account()
+		out.TokenIssuedAt = tok.IssuedAt
+		out.TokenExpiresAt = tok.ExpiresAt
+		if exp, ok := app.tokenExpiry(); ok {
+			out.TokenExpired = time.Now().After(exp)
+		}
+	} else if env.Token != "" {
+		if exp, ok := auth.TokenExpiry(env.Token); ok {
+			out.TokenExpiresAt = exp.UTC().Format(time.RFC3339)
+			out.TokenExpired = time.Now().After(exp)
+		}
+	}
 	for _, p := range app.Config.Projects {
 		out.Projects = append(out.Projects, projectStatus{
 			Name:           p.Name,
 			APIKey:         p.APIKey,
 			AuthMethodHint: p.AuthMethodHint,
 			HasSecret:      app.AuthSvc.HasProjectSecret(p.APIKey),
+			// The fingerprint is of the stored secret, which env credentials override.
+			SecretFingerprint: app.AuthSvc.ProjectSecretFingerprint(p.APIKey),
 		})
 	}
 
+	if out.EnvAuthMode != "" {
+		out.CredentialSource = auth.SourceEnv
+	}
+
 	if asJSON {
 		return output.PrintJSON(out)
 	}
-	fmt.Printf("Logged in: %v\n", out.LoggedIn)
-	fmt.Printf("Pending verify token: %v\n", out.PendingVerifyToken)
-	fmt.Printf("Default project: %s\n", out.DefaultProject)
+	if out.CredentialSource == auth.SourceEnv {
+		output.Printf("Credentials: environment (%s, %s); stored credentials are ignored\n", strings.Join(out.EnvVars, "+"), out.EnvAuthMode)
+	} else {
+		output.Println("Credentials: stored (keychain/config)")
+	}
+	if out.Account != "" {
+		output.Printf("Logged in: %v (%s)\n", out.LoggedIn, out.Account)
+	} else {
+		output.Printf("Logged in: %v\n", out.LoggedIn)
+	}
+	if out.TokenExpiresAt != "" {
+		exp, _ := time.Parse(time.RFC3339, out.TokenExpiresAt)
+		if out.TokenExpired {
+			output.Printf("Token expired: %s (run `wiro auth login`)\n", exp.Local().Format(time.RFC1123))
+		} else {
+			output.Printf("Token expires: %s (in %s)\n", exp.Local().Format(time.RFC1123), time.Until(exp).Round(time.Minute))
+		}
+	} else if out.TokenIssuedAt != "" {
+		output.Printf("Token issued: %s (expiry unknown)\n", out.TokenIssuedAt)
+	}
+	output.Printf("Pending verify token: %v\n", out.PendingVerifyToken)
+	output.Printf("Default project: %s\n", out.DefaultProject)
 	if len(out.Projects) == 0 {
-		fmt.Println("Projects: none")
+		output.Println("Projects: none")
 		return nil
 	}
-	fmt.Println("Projects:")
+	output.Println("Projects:")
 	for _, p := range out.Projects {
-		fmt.Printf("- %s (%s) auth=%s secret=%v\n", p.Name, p.APIKey, p.AuthMethodHint, p.HasSecret)
+		if p.SecretFingerprint != "" {
+			output.Printf("- %s (%s) auth=%s secret=%s\n", p.Name, p.APIKey, p.AuthMethodHint, p.SecretFingerprint)
+			continue
+		}
+		output.Printf("- %s (%s) auth=%s secret=%v\n", p.Name, p.APIKey, p.AuthMethodHint, p.HasSecret)
 	}
 	return nil
 }
 
 func authLogoutCommand(app *App, args []string) error {
-	if len(args) != 0 {
-		return errors.New("usage: wiro auth logout")
+	if len(args) > 1 {
+		return errors.New("usage: wiro auth logout [email]")
+	}
+	account := app.AuthSvc.Account()
+	if len(args) == 1 {
+		account = strings.TrimSpace(args[0])
+		if _, ok := app.State.Accounts[account]; !ok {
+			return fmt.Errorf("account %q is not signed in; see `wiro auth accounts`", account)
+		}
 	}
-	if err := app.AuthSvc.Logout(); err != nil {
+	if err := app.AuthSvc.LogoutAccount(account); err != nil {
 		return err
 	}
 	app.State.PendingVerifyToken = ""
+	app.State.PendingVerifyEmail = ""
+	app.State.ForgetToken(account)
 	if err := app.SaveState(); err != nil {
 		return err
 	}
-	fmt.Println("Logged out.")
+	if account != app.AuthSvc.Account() {
+		output.Printf("Logged out %s.\n", account)
+		return nil
+	}
+	// Another signed-in account takes over rather than leaving none active.
+	next := ""
+	if remaining := app.State.AccountEmails(); len(remaining) > 0 {
+		next = remaining[0]
+	}
+	if err := app.switchAccount(next); err != nil {
+		return err
+	}
+	output.Println("Logged out.")
+	if next != "" {
+		output.Printf("Active account: %s\n", next)
+	}
 	return nil
 }
+
+// confirmNewSecret shows the fingerprint of a secret about to be stored for a
+// project that has none yet and, on a terminal, asks to confirm it, so a wrong
+// paste is caught before it is saved. Replacing a stored secret is not asked.
+func confirmNewSecret(app *App, apiKey, apiSecret string) (bool, error) {
+	if app.AuthSvc.HasProjectSecret(apiKey) {
+		return true, nil
+	}
+	output.Printf("Secret fingerprint: %s\n", auth.Fingerprint(apiKey, apiSecret))
+	if !isInteractiveSession() {
+		return true, nil
+	}
+	return promptConfirm("Save this secret?", true)
+}
+
+// authLabel describes the chosen auth mode and where the credentials came from.
+func authLabel(res auth.HeaderResult) string {
+	if res.Source == auth.SourceEnv {
+		return string(res.Mode) + " (env)"
+	}
+	return string(res.Mode)
+}
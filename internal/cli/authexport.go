@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/auth"
+	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	"github.com/wiro-ai/wiro-cli/internal/secure"
+	"github.com/wiro-ai/wiro-cli/internal/term"
+)
+
+const (
+	authExportUsage = "usage: wiro auth export --encrypted [-o <file>]"
+	authImportUsage = "usage: wiro auth import <file|-> [--preferences]"
+
+	// envPassphrase supplies the export passphrase without a prompt.
+	envPassphrase = "WIRO_EXPORT_PASSPHRASE"
+	// minPassphraseLen is the shortest passphrase export accepts.
+	minPassphraseLen = 10
+)
+
+// credentialBundle is the plaintext of an export. It only exists in memory.
+type credentialBundle struct {
+	ExportedAt  string           `json:"exportedAt"`
+	CLIVersion  string           `json:"cliVersion"`
+	Config      config.Config    `json:"config"`
+	Credentials auth.Credentials `json:"credentials"`
+	// Accounts describes the exported account tokens, keyed like
+	// Credentials.AccountTokens.
+	Accounts map[string]config.AccountToken `json:"accounts,omitempty"`
+}
+
+// authExportCommand writes the config and keychain secrets as one
+// passphrase-encrypted file for moving to another machine.
+func authExportCommand(app *App, args []string) error {
+	fs := flag.NewFlagSet("auth export", flag.ContinueOnError)
+	var encrypted bool
+	var outPath string
+	fs.BoolVar(&encrypted, "encrypted", false, "Encrypt the export with a passphrase (required)")
+	fs.StringVar(&outPath, "o", "", "Write to this file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if len(fs.Args()) != 0 {
+		return errors.New(authExportUsage)
+	}
+	if !encrypted {
+		return errors.New("exports contain API secrets and are only written encrypted; pass --encrypted")
+	}
+	if outPath == "" && isTerminalFile(os.Stdout) {
+		return errors.New("refusing to print the export to the terminal; pass -o <file> or redirect stdout")
+	}
+
+	keys := make([]string, 0, len(app.Config.Projects))
+	for _, p := range app.Config.Projects {
+		keys = append(keys, p.APIKey)
+	}
+	creds := app.AuthSvc.StoredCredentials(append([]string{""}, app.State.AccountEmails()...), keys)
+	accounts := map[string]config.AccountToken{}
+	for account := range creds.AccountTokens {
+		accounts[account] = app.State.Token(account)
+	}
+	plaintext, err := json.Marshal(credentialBundle{
+		ExportedAt:  time.Now().UTC().Format(time.RFC3339),
+		CLIVersion:  cliVersion(),
+		Config:      app.Config,
+		Credentials: creds,
+		Accounts:    accounts,
+	})
+	if err != nil {
+		return err
+	}
+	passphrase, err := readPassphrase(true)
+	if err != nil {
+		return err
+	}
+	sealed, err := secure.Seal(plaintext, passphrase)
+	if err != nil {
+		return err
+	}
+	sealed = append(sealed, '\n')
+	if outPath == "" {
+		_, err = os.Stdout.Write(sealed)
+		return err
+	}
+	if err := os.WriteFile(outPath, sealed, 0o600); err != nil {
+		return err
+	}
+	output.Notef("Exported %d project(s) to %s. Import it with: wiro auth import %s\n", len(app.Config.Projects), outPath, outPath)
+	return nil
+}
+
+// authImportCommand restores an export into the local config and keychain.
+// Projects and aliases are merged in; preferences and the API URL are only
+// taken with --preferences, since paths in them may not exist here.
+func authImportCommand(app *App, args []string) error {
+	fs := flag.NewFlagSet("auth import", flag.ContinueOnError)
+	var withPrefs bool
+	fs.BoolVar(&withPrefs, "preferences", false, "Also replace preferences and apiBaseUrl with the exported ones")
+	var rest []string
+	for len(args) > 0 && (!strings.HasPrefix(args[0], "-") || args[0] == "-") {
+		rest, args = append(rest, args[0]), args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	rest = append(rest, fs.Args()...)
+	if err := requireArgs(rest, 1, authImportUsage); err != nil {
+		return err
+	}
+
+	var data []byte
+	var err error
+	if rest[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(rest[0])
+	}
+	if err != nil {
+		return err
+	}
+	passphrase, err := readPassphrase(false)
+	if err != nil {
+		return err
+	}
+	plaintext, err := secure.Open(data, passphrase)
+	if err != nil {
+		return err
+	}
+	var bundle credentialBundle
+	if err := json.Unmarshal(plaintext, &bundle); err != nil {
+		return fmt.Errorf("decode export: %w", err)
+	}
+
+	if err := app.AuthSvc.RestoreCredentials(bundle.Credentials); err != nil {
+		return err
+	}
+	mergeImportedConfig(&app.Config, bundle.Config, withPrefs)
+	mergeImportedAccounts(&app.State, bundle)
+	if err := app.SaveConfig(); err != nil {
+		return err
+	}
+	if err := app.SaveState(); err != nil {
+		return err
+	}
+	app.AuthSvc.UseAccount(app.Config.ActiveAccount)
+	output.Printf("Imported %d project(s) and %d secret(s) exported %s.\n", len(bundle.Config.Projects), len(bundle.Credentials.ProjectSecrets), bundle.ExportedAt)
+	if n := len(bundle.Credentials.AccountTokens); n > 0 {
+		output.Printf("Sign-in restored for %d account(s); check them with `wiro auth accounts`.\n", n)
+	} else if bundle.Credentials.BearerToken != "" {
+		output.Println("Account sign-in restored; check it with `wiro auth status`.")
+	}
+	return nil
+}
+
+// mergeImportedConfig adds the projects, aliases, and favorites of src to dst,
+// and takes its active account when none is set here.
+// Projects already here are updated by API key and aliases replaced by name.
+func mergeImportedConfig(dst *config.Config, src config.Config, withPrefs bool) {
+	for _, p := range src.Projects {
+		dst.UpsertProject(p)
+	}
+	if dst.DefaultProject == "" {
+		dst.DefaultProject = src.DefaultProject
+	}
+	if dst.ActiveAccount == "" {
+		dst.ActiveAccount = src.ActiveAccount
+	}
+	for name, alias := range src.Aliases {
+		if dst.Aliases == nil {
+			dst.Aliases = map[string]config.ModelAlias{}
+		}
+		dst.Aliases[name] = alias
+	}
+	for _, fav := range src.Favorites {
+		dst.AddFavorite(fav)
+	}
+	if withPrefs {
+		dst.Preferences = src.Preferences
+		dst.APIBaseURL = src.APIBaseURL
+	}
+}
+
+// mergeImportedAccounts records what the export knew about each restored
+// account token, so the accounts are listed and projects bound to them keep
+// working.
+func mergeImportedAccounts(dst *config.State, bundle credentialBundle) {
+	for account := range bundle.Credentials.AccountTokens {
+		dst.SetToken(account, bundle.Accounts[account])
+	}
+}
+
+// readPassphrase takes the passphrase from $WIRO_EXPORT_PASSPHRASE or a hidden
+// prompt, asking twice when choosing a new one.
+func readPassphrase(confirm bool) (string, error) {
+	passphrase, fromEnv := os.LookupEnv(envPassphrase)
+	if !fromEnv {
+		if !isTerminalFile(os.Stdin) {
+			return "", fmt.Errorf("no terminal to ask for the passphrase; set %s", envPassphrase)
+		}
+		var err error
+		if passphrase, err = promptPassphrase("Passphrase"); err != nil {
+			return "", err
+		}
+	}
+	if confirm && len(passphrase) < minPassphraseLen {
+		return "", fmt.Errorf("passphrase must be at least %d characters", minPassphraseLen)
+	}
+	if confirm && !fromEnv {
+		again, err := promptPassphrase("Repeat passphrase")
+		if err != nil {
+			return "", err
+		}
+		if again != passphrase {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
+
+// promptPassphrase reads a line from the terminal on stdin without echo. The
+// prompt goes to stderr, since stdout may be the export itself.
+func promptPassphrase(message string) (string, error) {
+	output.Alertf("%s: ", message)
+	if restore, err := term.DisableEcho(); err == nil {
+		defer func() {
+			restore()
+			output.Alertf("\n")
+		}()
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
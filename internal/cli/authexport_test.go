@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/auth"
+	"github.com/wiro-ai/wiro-cli/internal/config"
+)
+
+func TestMergeImportedConfig(t *testing.T) {
+	dst := config.Config{
+		Projects:    []config.ProjectProfile{{Name: "local", APIKey: "k1", Budget: &config.Budget{TasksPerDay: 5}}},
+		Preferences: config.Preferences{OutputDirDefault: "/home/me/out"},
+		Favorites:   []string{"wiro/flux"},
+	}
+	src := config.Config{
+		DefaultProject: "k2",
+		Projects:       []config.ProjectProfile{{Name: "renamed", APIKey: "k1", AuthMethodHint: "signature"}, {Name: "new", APIKey: "k2"}},
+		Preferences:    config.Preferences{OutputDirDefault: "/Users/me/out"},
+		APIBaseURL:     "https://staging.example.com/v1",
+		Aliases:        map[string]config.ModelAlias{"flux": {Model: "wiro/flux"}},
+		Favorites:      []string{"wiro/flux", "wiro/whisper"},
+	}
+
+	mergeImportedConfig(&dst, src, false)
+	if len(dst.Projects) != 2 || dst.Projects[0].Name != "renamed" || dst.Projects[0].Budget == nil {
+		t.Fatalf("projects = %+v", dst.Projects)
+	}
+	if dst.DefaultProject != "k2" || dst.Aliases["flux"].Model != "wiro/flux" || len(dst.Favorites) != 2 {
+		t.Fatalf("config = %+v", dst)
+	}
+	if dst.Preferences.OutputDirDefault != "/home/me/out" || dst.APIBaseURL != "" {
+		t.Fatalf("preferences taken without --preferences: %+v", dst)
+	}
+
+	mergeImportedConfig(&dst, src, true)
+	if dst.Preferences.OutputDirDefault != "/Users/me/out" || dst.APIBaseURL != src.APIBaseURL {
+		t.Fatalf("preferences not taken: %+v", dst)
+	}
+}
+
+func TestMergeImportedAccounts(t *testing.T) {
+	bundle := credentialBundle{
+		Config: config.Config{
+			ActiveAccount: "a@example.com",
+			Projects:      []config.ProjectProfile{{APIKey: "k1", Account: "b@example.com"}},
+		},
+		Credentials: auth.Credentials{AccountTokens: map[string]string{"a@example.com": "tok-a", "b@example.com": "tok-b"}},
+		Accounts:    map[string]config.AccountToken{"a@example.com": {IssuedAt: "2026-01-02T00:00:00Z"}},
+	}
+	var cfg config.Config
+	var st config.State
+	mergeImportedConfig(&cfg, bundle.Config, false)
+	mergeImportedAccounts(&st, bundle)
+	if cfg.ActiveAccount != "a@example.com" || cfg.Projects[0].Account != "b@example.com" {
+		t.Fatalf("config = %+v", cfg)
+	}
+	if got := st.AccountEmails(); len(got) != 2 || got[1] != "b@example.com" {
+		t.Fatalf("accounts = %v", got)
+	}
+	if st.Token("a@example.com").IssuedAt != "2026-01-02T00:00:00Z" {
+		t.Fatalf("token metadata = %+v", st.Accounts)
+	}
+
+	cfg.ActiveAccount = "me@example.com"
+	mergeImportedConfig(&cfg, bundle.Config, false)
+	if cfg.ActiveAccount != "me@example.com" {
+		t.Fatal("import should keep the local active account")
+	}
+}
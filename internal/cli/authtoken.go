@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/auth"
+	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/log"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+)
+
+// storeBearerToken signs in the account token belongs to and makes it the
+// active account. email names the account; when empty it is read from the
+// token, else the active account is replaced. The token stored before
+// accounts were tracked is dropped once a named account signs in over it.
+func (a *App) storeBearerToken(email, token string) error {
+	account := strings.TrimSpace(email)
+	if account == "" {
+		account, _ = auth.TokenEmail(token)
+	}
+	if account == "" {
+		account = a.AuthSvc.Account()
+	}
+	if err := a.recordToken(account, token); err != nil {
+		return err
+	}
+	if prev := a.AuthSvc.Account(); prev == "" && account != "" {
+		_ = a.AuthSvc.LogoutAccount("")
+		a.State.ForgetToken("")
+	}
+	a.State.PendingVerifyToken = ""
+	a.State.PendingVerifyEmail = ""
+	if err := a.SaveState(); err != nil {
+		return err
+	}
+	return a.switchAccount(account)
+}
+
+// recordToken saves the token of account to the keychain and records when it
+// was issued and, for JWTs, when it expires. State is saved by the caller.
+func (a *App) recordToken(account, token string) error {
+	if err := a.AuthSvc.SaveAccountToken(account, token); err != nil {
+		return err
+	}
+	t := config.AccountToken{IssuedAt: time.Now().UTC().Format(time.RFC3339)}
+	if exp, ok := auth.TokenExpiry(token); ok {
+		t.ExpiresAt = exp.UTC().Format(time.RFC3339)
+	}
+	a.State.SetToken(account, t)
+	return nil
+}
+
+// switchAccount makes account the active one and saves the config.
+func (a *App) switchAccount(account string) error {
+	a.AuthSvc.UseAccount(account)
+	if a.Config.ActiveAccount == account {
+		return nil
+	}
+	a.Config.ActiveAccount = account
+	return a.SaveConfig()
+}
+
+// tokenExpiry returns the active account's token expiry, if known.
+func (a *App) tokenExpiry() (time.Time, bool) {
+	return accountTokenExpiry(a.State.Token(a.AuthSvc.Account()))
+}
+
+func accountTokenExpiry(t config.AccountToken) (time.Time, bool) {
+	raw := strings.TrimSpace(t.ExpiresAt)
+	if raw == "" {
+		return time.Time{}, false
+	}
+	exp, err := time.Parse(time.RFC3339, raw)
+	return exp, err == nil
+}
+
+// rejectedAccount finds the signed-in account whose token the rejected
+// request carried, falling back to the active account.
+func (a *App) rejectedAccount(rejected map[string]string) string {
+	for _, account := range a.State.AccountEmails() {
+		if tok := a.AuthSvc.AccountToken(account); tok != "" && "Bearer "+tok == rejected["Authorization"] {
+			return account
+		}
+	}
+	return a.AuthSvc.Account()
+}
+
+// refreshBearer is the API client's TokenRefresher. It reuses a token another
+// request already refreshed, then tries the refresh endpoint, and finally, on a
+// terminal, offers to sign in again once per process.
+func (a *App) refreshBearer(ctx context.Context, rejected map[string]string) (string, bool) {
+	a.refreshMu.Lock()
+	defer a.refreshMu.Unlock()
+	if a.AuthSvc.EnvCredentials().Token != "" {
+		output.Warnf("WIRO_TOKEN was rejected; it may have expired")
+		return "", false
+	}
+	account := a.rejectedAccount(rejected)
+	if current := a.AuthSvc.AccountToken(account); current != "" && "Bearer "+current != rejected["Authorization"] {
+		return current, true
+	}
+
+	token, err := a.AuthSvc.RefreshAccountToken(ctx, account)
+	if err == nil {
+		saveErr := a.recordToken(account, token)
+		if saveErr == nil {
+			saveErr = a.SaveState()
+		}
+		if saveErr != nil {
+			log.Verbosef("auth: save refreshed token: %v", saveErr)
+		}
+		output.Notef("Session token refreshed.\n")
+		return token, true
+	}
+	log.Verbosef("auth: token refresh failed: %v", err)
+
+	expired := "was rejected"
+	if exp, ok := accountTokenExpiry(a.State.Token(account)); ok && time.Now().After(exp) {
+		expired = "expired at " + exp.Local().Format(time.RFC1123)
+	}
+	if a.reloginTried || !isInteractiveSession() {
+		output.Warnf("your session token %s; run `wiro auth login`", expired)
+		return "", false
+	}
+	a.reloginTried = true
+	output.Alertf("Your session token %s.\n", expired)
+	again, err := promptConfirm("Sign in again now?", true)
+	if err != nil || !again {
+		return "", false
+	}
+	token, err = a.relogin(ctx)
+	if err != nil {
+		output.Warnf("sign-in failed: %v", err)
+		return "", false
+	}
+	return token, true
+}
+
+// relogin runs the login and verification prompts and stores the new token.
+func (a *App) relogin(ctx context.Context) (string, error) {
+	email, err := promptInput("Email", "")
+	if err != nil {
+		return "", err
+	}
+	password, err := promptPassword("Password (leave blank for one-time code)")
+	if err != nil {
+		return "", err
+	}
+	loginCtx, cancel := context.WithTimeout(ctx, 40*time.Second)
+	defer cancel()
+	resp, err := a.AuthSvc.Login(loginCtx, email, password)
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(resp.Token)
+	if strings.TrimSpace(resp.VerifyToken) != "" {
+		code, err := promptInput("Verification code", "")
+		if err != nil {
+			return "", err
+		}
+		authCode := ""
+		if resp.TwoFactorRequired == 1 {
+			if authCode, err = promptInput("2FA code", ""); err != nil {
+				return "", err
+			}
+		}
+		verify, err := a.AuthSvc.Verify(loginCtx, resp.VerifyToken, code, authCode)
+		if err != nil {
+			return "", err
+		}
+		token = strings.TrimSpace(verify.Token)
+	}
+	if token == "" {
+		return "", errors.New("sign-in returned no token")
+	}
+	if err := a.storeBearerToken(email, token); err != nil {
+		return "", err
+	}
+	return token, nil
+}
@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/history"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	projectsvc "github.com/wiro-ai/wiro-cli/internal/project"
+)
+
+// errBudgetExceeded is returned when a blocking project budget would be exceeded.
+var errBudgetExceeded = errors.New("project budget exceeded")
+
+// budgetUsage is what a project submitted today according to run history.
+type budgetUsage struct {
+	Tasks   int     `json:"tasks"`
+	Credits float64 `json:"credits"`
+}
+
+// usageSince sums history entries of profile created at or after since.
+func usageSince(entries []history.Entry, profile *config.ProjectProfile, since time.Time) budgetUsage {
+	var u budgetUsage
+	for _, e := range entries {
+		if e.Project == "" || (e.Project != profile.Name && e.Project != profile.APIKey) {
+			continue
+		}
+		created, err := time.Parse(time.RFC3339, e.CreatedAt)
+		if err != nil || created.Before(since) {
+			continue
+		}
+		u.Tasks++
+		u.Credits += e.EstimatedCost
+	}
+	return u
+}
+
+// startOfDay returns local midnight of t's day.
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// projectUsageToday reads today's usage for profile from the history store.
+func projectUsageToday(profile *config.ProjectProfile) (budgetUsage, error) {
+	store, err := historyStore()
+	if err != nil {
+		return budgetUsage{}, err
+	}
+	entries, err := store.List()
+	if err != nil {
+		return budgetUsage{}, err
+	}
+	return usageSince(entries, profile, startOfDay(time.Now())), nil
+}
+
+// budgetViolations describes each limit one more submission costing estimate would break.
+func budgetViolations(b config.Budget, used budgetUsage, estimate float64) []string {
+	var out []string
+	if b.TasksPerDay > 0 && used.Tasks+1 > b.TasksPerDay {
+		out = append(out, fmt.Sprintf("%d of %d tasks already submitted today", used.Tasks, b.TasksPerDay))
+	}
+	if b.CreditsPerDay > 0 && used.Credits+estimate > b.CreditsPerDay {
+		out = append(out, fmt.Sprintf("%s estimated credits used today, this run adds %s (limit %s)",
+			formatCredits(used.Credits, ""), formatCredits(estimate, ""), formatCredits(b.CreditsPerDay, "")))
+	}
+	return out
+}
+
+// checkBudget warns, or with a blocking budget fails, when submitting one more
+// task for profile would exceed its daily budget.
+func checkBudget(profile *config.ProjectProfile, estimate float64) error {
+	if profile == nil || profile.Budget.IsZero() {
+		return nil
+	}
+	used, err := projectUsageToday(profile)
+	if err != nil {
+		output.Warnf("budget check skipped: %v", err)
+		return nil
+	}
+	violations := budgetViolations(*profile.Budget, used, estimate)
+	if len(violations) == 0 {
+		return nil
+	}
+	msg := fmt.Sprintf("project %s: %s", historyProject(profile), strings.Join(violations, "; "))
+	if profile.Budget.Block {
+		return fmt.Errorf("%w: %s", errBudgetExceeded, msg)
+	}
+	output.Warnf("budget exceeded for %s", msg)
+	return nil
+}
+
+func projectBudgetCommand(app *App, args []string) error {
+	fs := flag.NewFlagSet("project budget", flag.ContinueOnError)
+	var tasks int
+	var credits float64
+	var block, warn, clear, asJSON bool
+	fs.IntVar(&tasks, "tasks-per-day", 0, "Maximum tasks submitted per day (0 = no cap)")
+	fs.Float64Var(&credits, "credits-per-day", 0, "Maximum estimated credits submitted per day (0 = no cap)")
+	fs.BoolVar(&block, "block", false, "Refuse submissions over budget")
+	fs.BoolVar(&warn, "warn", false, "Only warn when over budget (default)")
+	fs.BoolVar(&clear, "clear", false, "Remove the budget")
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	usage := "usage: wiro project budget [name|apikey] [--tasks-per-day N] [--credits-per-day X] [--block|--warn] [--clear]"
+
+	var selector string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		selector, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() > 0 {
+		return errors.New(usage)
+	}
+	if block && warn {
+		return errors.New("--block and --warn cannot be used together")
+	}
+	if tasks < 0 || credits < 0 {
+		return errors.New("budget limits must not be negative")
+	}
+	profile := projectsvc.ResolveSelected(app.Config, selector)
+	if profile == nil {
+		if selector != "" {
+			return fmt.Errorf("project %q not found in local config", selector)
+		}
+		return errors.New("no default project selected; pass a project name or run `wiro project use <name|apikey>`")
+	}
+
+	changed := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name != "json" {
+			changed = true
+		}
+	})
+	if changed {
+		if clear {
+			profile.Budget = nil
+		} else {
+			b := config.Budget{}
+			if profile.Budget != nil {
+				b = *profile.Budget
+			}
+			fs.Visit(func(f *flag.Flag) {
+				switch f.Name {
+				case "tasks-per-day":
+					b.TasksPerDay = tasks
+				case "credits-per-day":
+					b.CreditsPerDay = credits
+				case "block":
+					b.Block = true
+				case "warn":
+					b.Block = false
+				}
+			})
+			profile.Budget = &b
+			if b.IsZero() {
+				profile.Budget = nil
+			}
+		}
+		if err := app.SaveConfig(); err != nil {
+			return err
+		}
+	}
+
+	used, err := projectUsageToday(profile)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return output.PrintJSON(map[string]interface{}{
+			"project": historyProject(profile),
+			"budget":  profile.Budget,
+			"today":   used,
+		})
+	}
+	output.Printf("Project: %s\n", displayProject(profile))
+	if profile.Budget.IsZero() {
+		output.Println("Budget: none")
+	} else {
+		b := profile.Budget
+		mode := "warn"
+		if b.Block {
+			mode = "block"
+		}
+		if b.TasksPerDay > 0 {
+			output.Printf("Tasks per day: %d\n", b.TasksPerDay)
+		}
+		if b.CreditsPerDay > 0 {
+			output.Printf("Credits per day: %s\n", formatCredits(b.CreditsPerDay, ""))
+		}
+		output.Printf("When exceeded: %s\n", mode)
+	}
+	output.Printf("Today: %d task(s), %s estimated credits\n", used.Tasks, formatCredits(used.Credits, ""))
+	return nil
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/history"
+)
+
+func TestUsageSince(t *testing.T) {
+	now := time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) string { return now.Add(d).Format(time.RFC3339) }
+	entries := []history.Entry{
+		{Project: "team", CreatedAt: at(-time.Hour), EstimatedCost: 1.5},
+		{Project: "key-1", CreatedAt: at(-2 * time.Hour), EstimatedCost: 2},
+		{Project: "team", CreatedAt: at(-20 * time.Hour), EstimatedCost: 9},
+		{Project: "other", CreatedAt: at(-time.Hour), EstimatedCost: 9},
+		{Project: "", CreatedAt: at(-time.Hour), EstimatedCost: 9},
+	}
+	profile := &config.ProjectProfile{Name: "team", APIKey: "key-1"}
+	got := usageSince(entries, profile, startOfDay(now))
+	if got.Tasks != 2 || got.Credits != 3.5 {
+		t.Fatalf("usage = %+v, want 2 tasks, 3.5 credits", got)
+	}
+}
+
+func TestBudgetViolations(t *testing.T) {
+	b := config.Budget{TasksPerDay: 3, CreditsPerDay: 10}
+	if v := budgetViolations(b, budgetUsage{Tasks: 2, Credits: 8}, 2); len(v) != 0 {
+		t.Fatalf("unexpected violations at the limit: %v", v)
+	}
+	v := budgetViolations(b, budgetUsage{Tasks: 3, Credits: 9}, 1.5)
+	if len(v) != 2 || !strings.Contains(v[0], "3 of 3 tasks") || !strings.Contains(v[1], "limit 10") {
+		t.Fatalf("violations = %v", v)
+	}
+	if v := budgetViolations(config.Budget{CreditsPerDay: 5}, budgetUsage{Tasks: 100}, 0); len(v) != 0 {
+		t.Fatalf("task count checked without a task cap: %v", v)
+	}
+}
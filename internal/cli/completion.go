@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/output"
+)
+
+// completionTree lists top-level commands and their subcommands for shell completion.
+var completionTree = map[string][]string{
+	"run":        nil,
+	"init":       nil,
+	"task":       {"detail", "outputs", "download", "wait", "events", "cancel", "kill"},
+	"watch":      nil,
+	"upload":     nil,
+	"queue":      {"add", "start", "status", "ls", "rm"},
+	"history":    {"ls", "show", "rerun", "sync"},
+	"stats":      {"models"},
+	"config":     {"get", "set", "unset", "list", "edit"},
+	"tui":        nil,
+	"model":      {"search", "categories", "inspect", "schema-diff", "run-spec", "fav"},
+	"preset":     {"import", "ls", "rm"},
+	"alias":      {"set", "ls", "rm"},
+	"project":    {"ls", "use", "budget", "notify", "whitelist", "keys"},
+	"auth":       {"login", "verify", "set", "status", "accounts", "switch", "logout", "export", "import"},
+	"account":    {"balance", "usage"},
+	"daemon":     {"start", "stop", "status", "jobs"},
+	"serve":      nil,
+	"mcp":        {"serve"},
+	"webhook":    {"test"},
+	"doctor":     nil,
+	"completion": {"bash", "zsh", "fish"},
+	"help":       nil,
+}
+
+func completionCommand(args []string) error {
+	usage := "usage: wiro completion <bash|zsh|fish> [--install]"
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New(usage)
+	}
+	shell := strings.TrimSpace(args[0])
+	if shell == "--help" || shell == "-h" || shell == "help" {
+		output.Println(usage)
+		return nil
+	}
+	if len(args) == 2 {
+		if args[1] != "--install" {
+			return errors.New(usage)
+		}
+		path, err := installCompletion(shell)
+		if err != nil {
+			return err
+		}
+		output.Printf("Completion installed: %s\n", path)
+		if hint := completionHint(shell); hint != "" {
+			output.Println(hint)
+		}
+		return nil
+	}
+	script, err := completionScript(shell)
+	if err != nil {
+		return err
+	}
+	output.Print(script)
+	return nil
+}
+
+func completionCommandNames() []string {
+	names := make([]string, 0, len(completionTree))
+	for name := range completionTree {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func completionScript(shell string) (string, error) {
+	names := completionCommandNames()
+	var b strings.Builder
+	switch shell {
+	case "bash":
+		b.WriteString("# bash completion for wiro\n_wiro() {\n  local cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+		b.WriteString("  if [ \"$COMP_CWORD\" -eq 1 ]; then\n")
+		fmt.Fprintf(&b, "    COMPREPLY=($(compgen -W %q -- \"$cur\"))\n    return\n  fi\n", strings.Join(names, " "))
+		b.WriteString("  if [ \"$COMP_CWORD\" -eq 2 ]; then\n    case \"${COMP_WORDS[1]}\" in\n")
+		for _, name := range names {
+			if subs := completionTree[name]; len(subs) > 0 {
+				fmt.Fprintf(&b, "      %s) COMPREPLY=($(compgen -W %q -- \"$cur\")) ;;\n", name, strings.Join(subs, " "))
+			}
+		}
+		b.WriteString("    esac\n  fi\n}\ncomplete -o default -F _wiro wiro\n")
+	case "zsh":
+		b.WriteString("#compdef wiro\n_wiro() {\n  if (( CURRENT == 2 )); then\n")
+		fmt.Fprintf(&b, "    compadd -- %s\n    return\n  fi\n", strings.Join(names, " "))
+		b.WriteString("  if (( CURRENT == 3 )); then\n    case \"${words[2]}\" in\n")
+		for _, name := range names {
+			if subs := completionTree[name]; len(subs) > 0 {
+				fmt.Fprintf(&b, "      %s) compadd -- %s ;;\n", name, strings.Join(subs, " "))
+			}
+		}
+		b.WriteString("    esac\n  fi\n  _files\n}\ncompdef _wiro wiro\n")
+	case "fish":
+		b.WriteString("# fish completion for wiro\ncomplete -c wiro -f\n")
+		fmt.Fprintf(&b, "complete -c wiro -n '__fish_use_subcommand' -a '%s'\n", strings.Join(names, " "))
+		for _, name := range names {
+			if subs := completionTree[name]; len(subs) > 0 {
+				fmt.Fprintf(&b, "complete -c wiro -n '__fish_seen_subcommand_from %s' -a '%s'\n", name, strings.Join(subs, " "))
+			}
+		}
+	default:
+		return "", fmt.Errorf("unsupported shell %q (use bash, zsh, or fish)", shell)
+	}
+	return b.String(), nil
+}
+
+// completionPath returns the per-user location each shell loads completions from.
+func completionPath(shell string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	switch shell {
+	case "bash":
+		dataHome := os.Getenv("XDG_DATA_HOME")
+		if strings.TrimSpace(dataHome) == "" {
+			dataHome = filepath.Join(home, ".local", "share")
+		}
+		return filepath.Join(dataHome, "bash-completion", "completions", "wiro"), nil
+	case "zsh":
+		return filepath.Join(home, ".zfunc", "_wiro"), nil
+	case "fish":
+		return filepath.Join(home, ".config", "fish", "completions", "wiro.fish"), nil
+	default:
+		return "", fmt.Errorf("unsupported shell %q (use bash, zsh, or fish)", shell)
+	}
+}
+
+func installCompletion(shell string) (string, error) {
+	script, err := completionScript(shell)
+	if err != nil {
+		return "", err
+	}
+	path, err := completionPath(shell)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("create completion dir: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(script), 0o644); err != nil {
+		return "", fmt.Errorf("write completion: %w", err)
+	}
+	return path, nil
+}
+
+func completionHint(shell string) string {
+	if shell == "zsh" {
+		return "Add `fpath=(~/.zfunc $fpath); autoload -U compinit; compinit` to ~/.zshrc if it is not there yet."
+	}
+	return "Open a new shell to load it."
+}
+
+// detectShell guesses the user's shell from $SHELL.
+func detectShell() string {
+	switch filepath.Base(strings.TrimSpace(os.Getenv("SHELL"))) {
+	case "bash":
+		return "bash"
+	case "zsh":
+		return "zsh"
+	case "fish":
+		return "fish"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompletionScript_CoversCommands(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		script, err := completionScript(shell)
+		if err != nil {
+			t.Fatalf("%s: %v", shell, err)
+		}
+		for _, want := range []string{"queue", "inspect", "balance"} {
+			if !strings.Contains(script, want) {
+				t.Fatalf("%s completion missing %q", shell, want)
+			}
+		}
+	}
+	if _, err := completionScript("powershell"); err == nil {
+		t.Fatalf("expected unsupported shell error")
+	}
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+)
+
+const configUsage = "usage: wiro config <get|set|unset|list|edit> ..."
+
+func configCommand(app *App, args []string) error {
+	if len(args) == 0 {
+		return errors.New(configUsage)
+	}
+	sub := strings.TrimSpace(args[0])
+	switch sub {
+	case "get":
+		if err := requireArgs(args[1:], 1, "usage: wiro config get <key>"); err != nil {
+			return err
+		}
+		v, err := app.Config.Get(args[1])
+		if err != nil {
+			return err
+		}
+		output.Println(v)
+		return nil
+	case "set":
+		if err := requireArgs(args[1:], 2, "usage: wiro config set <key> <value>"); err != nil {
+			return err
+		}
+		if err := app.Config.Set(args[1], args[2]); err != nil {
+			return err
+		}
+		if err := app.SaveConfig(); err != nil {
+			return err
+		}
+		v, _ := app.Config.Get(args[1])
+		output.Printf("%s = %s\n", args[1], v)
+		return nil
+	case "unset":
+		if err := requireArgs(args[1:], 1, "usage: wiro config unset <key>"); err != nil {
+			return err
+		}
+		if err := app.Config.Unset(args[1]); err != nil {
+			return err
+		}
+		if err := app.SaveConfig(); err != nil {
+			return err
+		}
+		v, _ := app.Config.Get(args[1])
+		output.Printf("%s reset to default (%s)\n", args[1], output.Dash(v))
+		return nil
+	case "list", "ls":
+		return configListCommand(app, args[1:])
+	case "edit":
+		if len(args) != 1 {
+			return errors.New("usage: wiro config edit")
+		}
+		return configEditCommand(app)
+	case "--help", "-h", "help":
+		output.Println("Usage: wiro config <get|set|unset|list|edit> ...")
+		return nil
+	default:
+		return fmt.Errorf("unknown config command %q", sub)
+	}
+}
+
+func configListCommand(app *App, args []string) error {
+	fs := flag.NewFlagSet("config list", flag.ContinueOnError)
+	var asJSON bool
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() > 0 {
+		return errors.New("usage: wiro config list [--json]")
+	}
+	keys := config.Keys()
+	if asJSON {
+		values := make(map[string]string, len(keys))
+		for _, k := range keys {
+			values[k.Name], _ = app.Config.Get(k.Name)
+		}
+		return output.PrintJSON(values)
+	}
+	t := output.NewTable("KEY", "TYPE", "VALUE")
+	for _, k := range keys {
+		v, _ := app.Config.Get(k.Name)
+		t.Row(k.Name, k.Type, output.Dash(v))
+	}
+	return t.Print()
+}
+
+// configEditCommand opens config.json in $VISUAL or $EDITOR and checks the result.
+func configEditCommand(app *App) error {
+	path, err := config.ConfigPath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		if err := app.SaveConfig(); err != nil {
+			return err
+		}
+	}
+	editor := strings.Fields(os.Getenv("VISUAL"))
+	if len(editor) == 0 {
+		editor = strings.Fields(os.Getenv("EDITOR"))
+	}
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+		if runtime.GOOS == "windows" {
+			editor = []string{"notepad"}
+		}
+	}
+	cmd := exec.Command(editor[0], append(editor[1:], path)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("run editor %s: %w", editor[0], err)
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("%w; fix it with `wiro config edit`", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("%w; fix it with `wiro config edit`", err)
+	}
+	app.Config = cfg
+	output.Printf("Saved %s\n", path)
+	return nil
+}
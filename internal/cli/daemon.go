@@ -0,0 +1,614 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/jsonout"
+	"github.com/wiro-ai/wiro-cli/internal/model"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	"github.com/wiro-ai/wiro-cli/internal/queue"
+)
+
+const daemonUsage = "usage: wiro daemon <start|stop|status|jobs> ..."
+
+const (
+	// daemonRetries is how often the daemon resumes a submitted job whose
+	// watch or download failed, waiting daemonRetryDelay, then twice as long.
+	daemonRetries    = 3
+	daemonRetryDelay = 15 * time.Second
+	// daemonIdlePoll bounds how long idle workers wait before looking at the
+	// job file again.
+	daemonIdlePoll = 5 * time.Second
+	// daemonStartWait is how long `wiro daemon start` waits for the socket.
+	daemonStartWait = 10 * time.Second
+)
+
+// errDaemonNotRunning is returned when nothing answers on the daemon socket.
+var errDaemonNotRunning = errors.New("the daemon is not running; start it with `wiro daemon start`")
+
+// daemonStatus is GET /v1/status.
+type daemonStatus struct {
+	PID       int                  `json:"pid"`
+	Version   string               `json:"version"`
+	StartedAt string               `json:"startedAt"`
+	Socket    string               `json:"socket"`
+	Parallel  int                  `json:"parallel"`
+	Jobs      map[queue.Status]int `json:"jobs"`
+}
+
+// daemonPaths are the daemon's files below <config>/daemon.
+type daemonPaths struct {
+	Socket string
+	Jobs   string
+	Log    string
+}
+
+func resolveDaemonPaths() (daemonPaths, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return daemonPaths{}, err
+	}
+	dir = filepath.Join(dir, "daemon")
+	return daemonPaths{
+		Socket: filepath.Join(dir, "daemon.sock"),
+		Jobs:   filepath.Join(dir, "jobs.json"),
+		Log:    filepath.Join(dir, "daemon.log"),
+	}, nil
+}
+
+func daemonCommand(ctx context.Context, app *App, args []string) error {
+	if len(args) == 0 {
+		return errors.New(daemonUsage)
+	}
+	sub := strings.TrimSpace(args[0])
+	switch sub {
+	case "start":
+		return daemonStartCommand(ctx, app, args[1:])
+	case "stop":
+		return daemonStopCommand(ctx, args[1:])
+	case "status":
+		return daemonStatusCommand(ctx, args[1:])
+	case "jobs":
+		return daemonJobsCommand(ctx, args[1:])
+	case "--help", "-h", "help":
+		output.Println("Usage: " + strings.TrimPrefix(daemonUsage, "usage: "))
+		return nil
+	default:
+		return fmt.Errorf("unknown daemon command %q", sub)
+	}
+}
+
+func daemonStartCommand(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("daemon start", flag.ContinueOnError)
+	var parallel int
+	var foreground bool
+	var metricsAddr string
+	timeout, err := app.Config.Preferences.WatchTimeoutDuration()
+	if err != nil {
+		return err
+	}
+	fs.IntVar(&parallel, "parallel", 2, "Number of jobs to run at the same time")
+	fs.DurationVar(&timeout, "timeout", timeout, "Stop watching each job after this long (0 = no limit)")
+	fs.BoolVar(&foreground, "foreground", false, "Run in this process instead of the background, e.g. under systemd or launchd")
+	fs.StringVar(&metricsAddr, "metrics-addr", "", "Serve Prometheus metrics on this address while running (e.g. :9464)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if len(fs.Args()) != 0 {
+		return errors.New("usage: wiro daemon start [--parallel N] [--timeout <duration>] [--foreground] [--metrics-addr :9464]")
+	}
+	if parallel < 1 {
+		return errors.New("--parallel must be at least 1")
+	}
+	paths, err := resolveDaemonPaths()
+	if err != nil {
+		return err
+	}
+	if foreground {
+		if err := startMetrics(ctx, metricsAddr); err != nil {
+			return err
+		}
+		return serveDaemon(ctx, app, paths, jobRunner{timeout: timeout, label: "daemon", retries: daemonRetries, retryDelay: daemonRetryDelay}, parallel)
+	}
+
+	if st, err := getDaemonStatus(ctx, paths); err == nil {
+		output.Printf("Daemon already running (pid %d).\n", st.PID)
+		return nil
+	}
+	st, err := spawnDaemon(ctx, paths, append(args, "--foreground"))
+	if err != nil {
+		return err
+	}
+	output.Printf("Daemon started (pid %d).\n", st.PID)
+	output.Printf("Socket: %s\n", paths.Socket)
+	output.Printf("Log: %s\n", paths.Log)
+	return nil
+}
+
+// spawnDaemon starts `wiro daemon start <args>` in the background, logging to
+// paths.Log, and waits until it answers on the socket.
+func spawnDaemon(ctx context.Context, paths daemonPaths, args []string) (daemonStatus, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return daemonStatus{}, fmt.Errorf("find wiro executable: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(paths.Log), 0o700); err != nil {
+		return daemonStatus{}, fmt.Errorf("create daemon dir: %w", err)
+	}
+	logFile, err := os.OpenFile(paths.Log, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return daemonStatus{}, fmt.Errorf("open daemon log: %w", err)
+	}
+	defer logFile.Close()
+	cmd := exec.Command(exe, append([]string{"daemon", "start"}, args...)...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.SysProcAttr = detachedProcAttr()
+	if err := cmd.Start(); err != nil {
+		return daemonStatus{}, fmt.Errorf("start daemon: %w", err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	deadline := time.Now().Add(daemonStartWait)
+	for time.Now().Before(deadline) {
+		select {
+		case err := <-exited:
+			return daemonStatus{}, fmt.Errorf("daemon exited during startup (%v); see %s", err, paths.Log)
+		case <-ctx.Done():
+			return daemonStatus{}, ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+		if st, err := getDaemonStatus(ctx, paths); err == nil {
+			return st, nil
+		}
+	}
+	return daemonStatus{}, fmt.Errorf("daemon did not answer within %s; see %s", daemonStartWait, paths.Log)
+}
+
+// ensureDaemon returns the running daemon's status, starting it with default
+// settings when nothing answers.
+func ensureDaemon(ctx context.Context, paths daemonPaths) (daemonStatus, error) {
+	if st, err := getDaemonStatus(ctx, paths); err == nil {
+		return st, nil
+	}
+	st, err := spawnDaemon(ctx, paths, []string{"--foreground"})
+	if err != nil {
+		return daemonStatus{}, err
+	}
+	output.Notef("Started the daemon (pid %d); its log is %s\n", st.PID, paths.Log)
+	return st, nil
+}
+
+// daemonServer is the state behind the daemon's HTTP API.
+type daemonServer struct {
+	store    *queue.Store
+	wake     chan struct{}
+	started  time.Time
+	socket   string
+	parallel int
+	stop     context.CancelFunc
+}
+
+// serveDaemon answers on the unix socket and works jobs until ctx ends or a
+// client asks it to stop. Jobs still running then keep their task and are
+// resumed by the next start.
+func serveDaemon(ctx context.Context, app *App, paths daemonPaths, runner jobRunner, parallel int) error {
+	if err := os.MkdirAll(filepath.Dir(paths.Socket), 0o700); err != nil {
+		return fmt.Errorf("create daemon dir: %w", err)
+	}
+	if st, err := getDaemonStatus(ctx, paths); err == nil {
+		return fmt.Errorf("a daemon is already running (pid %d)", st.PID)
+	}
+	// Nothing answers, so a leftover socket is from a daemon that died.
+	_ = os.Remove(paths.Socket)
+	ln, err := net.Listen("unix", paths.Socket)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", paths.Socket, err)
+	}
+	defer os.Remove(paths.Socket)
+	// Anyone who can reach the socket can spend the account's credits.
+	if err := os.Chmod(paths.Socket, 0o600); err != nil {
+		ln.Close()
+		return fmt.Errorf("restrict daemon socket: %w", err)
+	}
+
+	runner.store = queue.NewStoreAt(paths.Jobs)
+	resumable, err := runner.store.Recover()
+	if err != nil {
+		ln.Close()
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	d := &daemonServer{store: runner.store, wake: make(chan struct{}, 1), started: time.Now(), socket: paths.Socket, parallel: parallel, stop: cancel}
+	srv := &http.Server{Handler: d.handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelShutdown()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			output.Printf("[daemon] API stopped: %v\n", err)
+			cancel()
+		}
+	}()
+	output.Printf("[daemon] pid %d listening on %s (parallel %d, %d job(s) to resume)\n", os.Getpid(), paths.Socket, parallel, len(resumable))
+
+	var resumeMu sync.Mutex
+	next := func() (*queue.Job, error) {
+		resumeMu.Lock()
+		if len(resumable) > 0 {
+			job := resumable[0]
+			resumable = resumable[1:]
+			resumeMu.Unlock()
+			return &job, nil
+		}
+		resumeMu.Unlock()
+		return runner.store.Claim()
+	}
+	var wg sync.WaitGroup
+	for w := 0; w < parallel; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				job, err := next()
+				if err != nil {
+					output.Printf("[daemon] %v\n", err)
+				}
+				if job == nil {
+					select {
+					case <-ctx.Done():
+					case <-d.wake:
+					case <-time.After(daemonIdlePoll):
+					}
+					continue
+				}
+				_ = runner.run(ctx, app, *job)
+			}
+		}()
+	}
+	wg.Wait()
+	output.Println("[daemon] stopped")
+	return nil
+}
+
+func (d *daemonServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/status", func(w http.ResponseWriter, r *http.Request) {
+		q, err := d.store.Load()
+		if err != nil {
+			writeHTTPError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeHTTPJSON(w, http.StatusOK, daemonStatus{
+			PID:       os.Getpid(),
+			Version:   cliVersion(),
+			StartedAt: d.started.UTC().Format(time.RFC3339),
+			Socket:    d.socket,
+			Parallel:  d.parallel,
+			Jobs:      q.Counts(),
+		})
+	})
+	mux.HandleFunc("GET /v1/jobs", func(w http.ResponseWriter, r *http.Request) {
+		q, err := d.store.Load()
+		if err != nil {
+			writeHTTPError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeHTTPJSON(w, http.StatusOK, q.Jobs)
+	})
+	mux.HandleFunc("GET /v1/jobs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		q, err := d.store.Load()
+		if err != nil {
+			writeHTTPError(w, http.StatusInternalServerError, err)
+			return
+		}
+		for _, j := range q.Jobs {
+			if j.ID == r.PathValue("id") {
+				writeHTTPJSON(w, http.StatusOK, j)
+				return
+			}
+		}
+		writeHTTPError(w, http.StatusNotFound, fmt.Errorf("job %q not found", r.PathValue("id")))
+	})
+	mux.HandleFunc("POST /v1/jobs", func(w http.ResponseWriter, r *http.Request) {
+		var job queue.Job
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&job); err != nil {
+			writeHTTPError(w, http.StatusBadRequest, fmt.Errorf("decode job: %w", err))
+			return
+		}
+		if job.Owner == "" || job.Model == "" {
+			writeHTTPError(w, http.StatusBadRequest, errors.New("job needs owner and model"))
+			return
+		}
+		job.TaskID, job.TaskToken, job.Outputs, job.Error = "", "", nil, ""
+		added, err := d.store.Add(job)
+		if err != nil {
+			writeHTTPError(w, http.StatusInternalServerError, err)
+			return
+		}
+		output.Printf("[daemon] job %s added: %s/%s\n", added.ID, added.Owner, added.Model)
+		select {
+		case d.wake <- struct{}{}:
+		default:
+		}
+		writeHTTPJSON(w, http.StatusCreated, added)
+	})
+	mux.HandleFunc("POST /v1/shutdown", func(w http.ResponseWriter, r *http.Request) {
+		writeHTTPJSON(w, http.StatusAccepted, map[string]bool{"stopping": true})
+		output.Println("[daemon] stop requested")
+		d.stop()
+	})
+	return mux
+}
+
+// writeHTTPJSON answers a local API request (daemon, gateway) with v as JSON.
+func writeHTTPJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeHTTPError(w http.ResponseWriter, status int, err error) {
+	writeHTTPJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// daemonRequest calls the daemon API over its socket and decodes the reply into out.
+func daemonRequest(ctx context.Context, paths daemonPaths, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, "http://wiro-daemon"+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", paths.Socket)
+			},
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return errDaemonNotRunning
+		}
+		return fmt.Errorf("daemon: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+		if apiErr.Error == "" {
+			apiErr.Error = resp.Status
+		}
+		return fmt.Errorf("daemon: %s", apiErr.Error)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func getDaemonStatus(ctx context.Context, paths daemonPaths) (daemonStatus, error) {
+	var st daemonStatus
+	err := daemonRequest(ctx, paths, http.MethodGet, "/v1/status", nil, &st)
+	return st, err
+}
+
+// submitToDaemon hands job to the daemon, starting it if needed.
+func submitToDaemon(ctx context.Context, job queue.Job) (queue.Job, error) {
+	paths, err := resolveDaemonPaths()
+	if err != nil {
+		return queue.Job{}, err
+	}
+	if _, err := ensureDaemon(ctx, paths); err != nil {
+		return queue.Job{}, err
+	}
+	var added queue.Job
+	err = daemonRequest(ctx, paths, http.MethodPost, "/v1/jobs", job, &added)
+	return added, err
+}
+
+// detachRun checks a run's inputs and hands it to the daemon as a job.
+func detachRun(ctx context.Context, app *App, opts runOptions) error {
+	switch {
+	case len(opts.Sweep) > 0:
+		return errors.New("--detach is not supported with --sweep; queue the runs with `wiro queue add` instead")
+	case opts.JSONEvents || opts.PrintPaths:
+		return errors.New("--detach cannot be combined with --json-events or --print-paths")
+	case opts.CallbackURL != "":
+		return errors.New("--detach and --callback-url both hand off the result; use one")
+	case opts.Media.Enabled():
+		return errors.New("--detach does not support --max-side, --format, or --strip-exif")
+	case opts.Notify.given():
+		return errors.New("--detach uses the project's notify settings; drop --notify, --bell, and --notify-cmd")
+	case opts.Owner == "" || opts.Model == "":
+		return errors.New("--detach needs the model argument, e.g. wiro run owner/model --detach")
+	}
+	if key := strings.TrimSpace(opts.SetStdin); key != "" {
+		val, err := readStdinValue()
+		if err != nil {
+			return err
+		}
+		opts.Set = append(opts.Set, key+"="+val)
+	}
+	// The daemon runs from another directory, so pin relative paths now.
+	setFile, err := absoluteFileSets(opts.SetFile)
+	if err != nil {
+		return err
+	}
+	outputDir := app.ResolveOutputDir(opts.OutputDir)
+	if abs, err := filepath.Abs(outputDir); err == nil {
+		outputDir = abs
+	}
+	job := queue.Job{
+		Project:       opts.Project,
+		Owner:         opts.Owner,
+		Model:         opts.Model,
+		Set:           opts.Set,
+		SetFile:       setFile,
+		SetURL:        opts.SetURL,
+		OutputDir:     outputDir,
+		OutputIndex:   opts.Outputs.Indexes,
+		OutputMatch:   opts.Outputs.Patterns,
+		Git:           opts.Git,
+		SecretFields:  opts.SecretFields,
+		NameTemplate:  opts.NameTemplate,
+		Workspace:     app.WorkspaceName(),
+		RequireGPU:    opts.RequireGPU,
+		Priority:      opts.Priority,
+		Timeout:       opts.Timeout.String(),
+		Dedupe:        &opts.Dedupe,
+		Reupload:      opts.Reupload,
+		NoBudgetCheck: opts.NoBudgetCheck,
+	}
+	// Missing or invalid fields, and a cost over --max-cost, are reported here
+	// rather than in the daemon log.
+	inputs, detail, err := jobInputs(ctx, app, runJob{Owner: job.Owner, Model: job.Model, Set: job.Set, SetFile: job.SetFile, SetURL: job.SetURL})
+	if err != nil {
+		return err
+	}
+	estimate, hasEstimate := model.EstimatePrice(detail, inputs)
+	if err := checkMaxCost(estimate, hasEstimate, opts.MaxCost); err != nil {
+		return err
+	}
+	added, err := submitToDaemon(ctx, job)
+	if err != nil {
+		return err
+	}
+	if opts.JSON {
+		return jsonout.Print(added)
+	}
+	output.Printf("Handed off to the daemon as job %s (%s/%s).\n", added.ID, added.Owner, added.Model)
+	output.Printf("Outputs go to %s. Follow it with `wiro daemon jobs`.\n", added.OutputDir)
+	return nil
+}
+
+func daemonStopCommand(ctx context.Context, args []string) error {
+	if len(args) != 0 {
+		return errors.New("usage: wiro daemon stop")
+	}
+	paths, err := resolveDaemonPaths()
+	if err != nil {
+		return err
+	}
+	st, err := getDaemonStatus(ctx, paths)
+	if err != nil {
+		return err
+	}
+	if err := daemonRequest(ctx, paths, http.MethodPost, "/v1/shutdown", nil, nil); err != nil {
+		return err
+	}
+	output.Printf("Daemon (pid %d) is stopping.", st.PID)
+	if n := st.Jobs[queue.StatusRunning] + st.Jobs[queue.StatusPending]; n > 0 {
+		output.Printf(" %d unfinished job(s) continue on the next `wiro daemon start`.", n)
+	}
+	output.Println()
+	return nil
+}
+
+func daemonStatusCommand(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("daemon status", flag.ContinueOnError)
+	var asJSON bool
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if len(fs.Args()) != 0 {
+		return errors.New("usage: wiro daemon status [--json]")
+	}
+	paths, err := resolveDaemonPaths()
+	if err != nil {
+		return err
+	}
+	st, err := getDaemonStatus(ctx, paths)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return output.PrintJSON(st)
+	}
+	output.Printf("Daemon: running (pid %d, version %s)\n", st.PID, st.Version)
+	output.Printf("Since: %s\n", st.StartedAt)
+	output.Printf("Socket: %s\n", st.Socket)
+	output.Printf("Parallel: %d\n", st.Parallel)
+	for _, s := range []queue.Status{queue.StatusPending, queue.StatusRunning, queue.StatusDone, queue.StatusFailed} {
+		output.Printf("%s: %d\n", s, st.Jobs[s])
+	}
+	return nil
+}
+
+func daemonJobsCommand(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("daemon jobs", flag.ContinueOnError)
+	var asJSON bool
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if len(fs.Args()) != 0 {
+		return errors.New("usage: wiro daemon jobs [--json]")
+	}
+	paths, err := resolveDaemonPaths()
+	if err != nil {
+		return err
+	}
+	var jobs []queue.Job
+	if err := daemonRequest(ctx, paths, http.MethodGet, "/v1/jobs", nil, &jobs); err != nil {
+		return err
+	}
+	if asJSON {
+		return output.PrintJSON(jobs)
+	}
+	if len(jobs) == 0 {
+		output.Println("The daemon has no jobs.")
+		return nil
+	}
+	t := output.NewTable("ID", "STATUS", "MODEL", "TASK", "FILES", "ERROR")
+	t.StyleColumn("STATUS", output.Std().Status)
+	t.StyleColumn("ERROR", output.Std().Red)
+	for _, j := range jobs {
+		t.Row(j.ID, string(j.Status), j.Owner+"/"+j.Model, output.Dash(j.TaskID), fmt.Sprint(len(j.Outputs)), short(j.Error, 80))
+	}
+	return t.Print()
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/queue"
+)
+
+func TestDaemonAPI(t *testing.T) {
+	stopped := false
+	d := &daemonServer{
+		store: queue.NewStoreAt(filepath.Join(t.TempDir(), "jobs.json")),
+		wake:  make(chan struct{}, 1),
+		stop:  func() { stopped = true },
+	}
+	srv := httptest.NewServer(d.handler())
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/v1/jobs", "application/json", strings.NewReader(`{"owner":"wiro","model":"flux","set":["prompt=a fox"],"taskId":"99"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var added queue.Job
+	_ = json.NewDecoder(resp.Body).Decode(&added)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated || added.ID != "1" || added.Status != queue.StatusPending || added.TaskID != "" {
+		t.Fatalf("POST /v1/jobs = %d %+v", resp.StatusCode, added)
+	}
+	select {
+	case <-d.wake:
+	default:
+		t.Fatal("adding a job should wake a worker")
+	}
+
+	resp, err = http.Post(srv.URL+"/v1/jobs", "application/json", strings.NewReader(`{"owner":"wiro"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("a job without a model = %d, want 400", resp.StatusCode)
+	}
+
+	resp, err = http.Get(srv.URL + "/v1/jobs/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got queue.Job
+	_ = json.NewDecoder(resp.Body).Decode(&got)
+	resp.Body.Close()
+	if got.Model != "flux" {
+		t.Fatalf("GET /v1/jobs/1 = %+v", got)
+	}
+
+	resp, err = http.Get(srv.URL + "/v1/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var st daemonStatus
+	_ = json.NewDecoder(resp.Body).Decode(&st)
+	resp.Body.Close()
+	if st.Jobs[queue.StatusPending] != 1 {
+		t.Fatalf("status jobs = %v", st.Jobs)
+	}
+
+	resp, err = http.Post(srv.URL+"/v1/shutdown", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if !stopped {
+		t.Fatal("POST /v1/shutdown should stop the daemon")
+	}
+}
+
+func TestDaemonRequestWithoutDaemon(t *testing.T) {
+	paths := daemonPaths{Socket: filepath.Join(t.TempDir(), "missing.sock")}
+	if _, err := getDaemonStatus(context.Background(), paths); !errors.Is(err, errDaemonNotRunning) {
+		t.Fatalf("err = %v, want errDaemonNotRunning", err)
+	}
+}
+
+func TestDetachRunRejectsNotifyFlags(t *testing.T) {
+	opts := runOptions{Owner: "wiro", Model: "flux", Notify: notifyFlags{bell: true, set: map[string]bool{"bell": true}}}
+	err := detachRun(context.Background(), &App{}, opts)
+	if err == nil || !strings.Contains(err.Error(), "notify") {
+		t.Fatalf("err = %v, want a notify flag error", err)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package cli
+
+import "syscall"
+
+// detachedProcAttr starts the daemon in its own session, so it outlives the
+// terminal that started it.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package cli
+
+import "syscall"
+
+// Process creation flags from the Windows API.
+const (
+	createNewProcessGroup = 0x00000200
+	detachedProcess       = 0x00000008
+)
+
+// detachedProcAttr starts the daemon without a console, so it outlives the
+// terminal that started it.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{CreationFlags: createNewProcessGroup | detachedProcess, HideWindow: true}
+}
@@ -0,0 +1,334 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	"github.com/wiro-ai/wiro-cli/internal/secure"
+	"github.com/wiro-ai/wiro-cli/internal/task"
+)
+
+// Doctor check outcomes.
+const (
+	doctorOK   = "ok"
+	doctorWarn = "warn"
+	doctorFail = "fail"
+)
+
+// doctorCheck is one diagnostic result of `wiro doctor`.
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+	Hint   string `json:"hint,omitempty"`
+}
+
+// proxyEnvVars are the proxy settings Go's HTTP client honors.
+var proxyEnvVars = []string{"HTTPS_PROXY", "HTTP_PROXY", "NO_PROXY"}
+
+func doctorCommand(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	var asJSON bool
+	var timeout time.Duration
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	fs.DurationVar(&timeout, "timeout", 10*time.Second, "Timeout for each network check")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() > 0 {
+		return errors.New("usage: wiro doctor [--timeout 10s] [--json]")
+	}
+
+	var checks []doctorCheck
+	checks = append(checks, doctorConfigChecks(app)...)
+	checks = append(checks, doctorProxyCheck(app))
+	checks = append(checks, doctorConnectivityChecks(ctx, app, timeout)...)
+	checks = append(checks, doctorKeychainCheck())
+	checks = append(checks, doctorCredentialChecks(ctx, app, timeout)...)
+
+	failed := 0
+	for _, c := range checks {
+		if c.Status == doctorFail {
+			failed++
+		}
+	}
+	if asJSON {
+		if err := output.PrintJSON(checks); err != nil {
+			return err
+		}
+	} else {
+		printDoctorChecks(checks)
+	}
+	if failed > 0 {
+		return fmt.Errorf("doctor found %d failing check(s)", failed)
+	}
+	return nil
+}
+
+func printDoctorChecks(checks []doctorCheck) {
+	warned := 0
+	for _, c := range checks {
+		mark := "ok  "
+		switch c.Status {
+		case doctorWarn:
+			mark = "WARN"
+			warned++
+		case doctorFail:
+			mark = "FAIL"
+		}
+		output.Printf("[%s] %s: %s\n", mark, c.Name, c.Detail)
+	}
+	var todo []doctorCheck
+	for _, c := range checks {
+		if c.Status != doctorOK && c.Hint != "" {
+			todo = append(todo, c)
+		}
+	}
+	if len(todo) == 0 {
+		if warned == 0 {
+			output.Println("\nAll checks passed.")
+		}
+		return
+	}
+	output.Println("\nTo fix:")
+	for _, c := range todo {
+		output.Printf("  - %s: %s\n", c.Name, c.Hint)
+	}
+}
+
+// doctorConfigChecks validates the config and checks that files holding
+// credentials are private to the user.
+func doctorConfigChecks(app *App) []doctorCheck {
+	var checks []doctorCheck
+	path, err := config.ConfigPath()
+	if err != nil {
+		return []doctorCheck{{Name: "config", Status: doctorFail, Detail: err.Error()}}
+	}
+	switch _, err := os.Stat(path); {
+	case errors.Is(err, os.ErrNotExist):
+		checks = append(checks, doctorCheck{Name: "config", Status: doctorWarn, Detail: path + " does not exist yet", Hint: "run `wiro init` to set up a project"})
+	case err != nil:
+		checks = append(checks, doctorCheck{Name: "config", Status: doctorFail, Detail: err.Error()})
+	default:
+		if err := app.Config.Validate(); err != nil {
+			checks = append(checks, doctorCheck{Name: "config", Status: doctorFail, Detail: err.Error(), Hint: "fix the value with `wiro config set` or `wiro config unset`"})
+		} else {
+			checks = append(checks, doctorCheck{Name: "config", Status: doctorOK, Detail: path})
+		}
+	}
+
+	files := []string{path, filepath.Join(filepath.Dir(path), "state.json")}
+	if secure.Backend() == "file" {
+		if p, err := secure.SecretsPath(); err == nil {
+			files = append(files, p)
+		}
+	}
+	for _, f := range files {
+		if c, ok := doctorPermissionCheck(f); ok {
+			checks = append(checks, c)
+		}
+	}
+	return checks
+}
+
+// doctorPermissionCheck flags files readable by other users; ok is false when
+// there is nothing to check.
+func doctorPermissionCheck(path string) (doctorCheck, bool) {
+	if runtime.GOOS == "windows" {
+		return doctorCheck{}, false
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return doctorCheck{}, false
+	}
+	name := "permissions " + filepath.Base(path)
+	if mode := info.Mode().Perm(); mode&0o077 != 0 {
+		return doctorCheck{
+			Name:   name,
+			Status: doctorWarn,
+			Detail: fmt.Sprintf("%s is %04o; other users can read it", path, mode),
+			Hint:   "run `chmod 600 " + path + "`",
+		}, true
+	}
+	return doctorCheck{Name: name, Status: doctorOK, Detail: fmt.Sprintf("%04o", info.Mode().Perm())}, true
+}
+
+// doctorProxyCheck reports the proxy API requests go through.
+func doctorProxyCheck(app *App) doctorCheck {
+	var set []string
+	for _, name := range proxyEnvVars {
+		for _, v := range []string{name, strings.ToLower(name)} {
+			if val := strings.TrimSpace(os.Getenv(v)); val != "" {
+				set = append(set, v+"="+redactProxy(val))
+			}
+		}
+	}
+	req, err := http.NewRequest(http.MethodGet, app.APIClient.BaseURL(), nil)
+	if err != nil {
+		return doctorCheck{Name: "proxy", Status: doctorFail, Detail: err.Error()}
+	}
+	proxy, err := http.ProxyFromEnvironment(req)
+	if err != nil {
+		return doctorCheck{Name: "proxy", Status: doctorFail, Detail: err.Error(), Hint: "fix the proxy URL in " + strings.Join(proxyEnvVars, "/")}
+	}
+	detail := "none (direct connection)"
+	if proxy != nil {
+		detail = "API requests use " + redactProxy(proxy.String())
+	}
+	if len(set) > 0 {
+		detail += "; " + strings.Join(set, " ")
+	}
+	return doctorCheck{Name: "proxy", Status: doctorOK, Detail: detail}
+}
+
+// redactProxy hides a password in a proxy URL.
+func redactProxy(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "xxxxx")
+	}
+	return u.String()
+}
+
+// doctorConnectivityChecks reaches the API and socket hosts over HTTPS. Any HTTP
+// response counts as reachable; DNS, TLS, proxy, and timeout errors do not.
+func doctorConnectivityChecks(ctx context.Context, app *App, timeout time.Duration) []doctorCheck {
+	client := &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	targets := []struct{ name, url string }{
+		{"api host", app.APIClient.BaseURL()},
+		{"socket host", task.SocketURL()},
+	}
+	checks := make([]doctorCheck, 0, len(targets))
+	for _, t := range targets {
+		host, elapsed, err := probeHost(ctx, client, t.url)
+		if err != nil {
+			checks = append(checks, doctorCheck{
+				Name:   t.name,
+				Status: doctorFail,
+				Detail: err.Error(),
+				Hint:   "check the network, firewall, and proxy settings (" + strings.Join(proxyEnvVars, ", ") + ")",
+			})
+			continue
+		}
+		checks = append(checks, doctorCheck{Name: t.name, Status: doctorOK, Detail: fmt.Sprintf("%s reachable in %s", host, elapsed.Round(time.Millisecond))})
+	}
+	return checks
+}
+
+// probeHost sends a GET to the root of rawURL's host; ws and wss map to http and https.
+func probeHost(ctx context.Context, client *http.Client, rawURL string) (string, time.Duration, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", 0, err
+	}
+	scheme := u.Scheme
+	switch scheme {
+	case "wss":
+		scheme = "https"
+	case "ws":
+		scheme = "http"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+"://"+u.Host+"/", nil)
+	if err != nil {
+		return "", 0, err
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return u.Host, 0, err
+	}
+	resp.Body.Close()
+	return u.Host, time.Since(start), nil
+}
+
+// doctorKeychainCheck round-trips a throwaway secret through the secret store.
+func doctorKeychainCheck() doctorCheck {
+	backend := secure.Backend()
+	if backend == "file" {
+		if p, err := secure.SecretsPath(); err == nil {
+			backend = "file " + p
+		}
+	}
+	if err := secure.Probe(); err != nil {
+		return doctorCheck{Name: "secret store", Status: doctorFail, Detail: backend + ": " + err.Error(), Hint: "make the config directory writable, or use WIRO_API_KEY/WIRO_API_SECRET or WIRO_TOKEN"}
+	}
+	return doctorCheck{Name: "secret store", Status: doctorOK, Detail: backend}
+}
+
+// doctorCredentialChecks calls /Account/Balance once per auth mode in use:
+// environment credentials, the stored bearer token, and each saved project.
+func doctorCredentialChecks(ctx context.Context, app *App, timeout time.Duration) []doctorCheck {
+	if env := app.AuthSvc.EnvCredentials(); env.Mode() != "" {
+		res, err := app.AuthSvc.BuildHeaders(nil)
+		name := fmt.Sprintf("credentials env (%s)", env.Mode())
+		if err != nil {
+			return []doctorCheck{{Name: name, Status: doctorFail, Detail: err.Error()}}
+		}
+		c := doctorCredentialCheck(ctx, app, name, res.Headers, timeout)
+		if c.Status == doctorOK && (len(app.Config.Projects) > 0 || app.AuthSvc.LoadBearerToken() != "") {
+			c.Detail += "; stored credentials are ignored while " + strings.Join(env.Vars(), ", ") + " are set"
+		}
+		return []doctorCheck{c}
+	}
+
+	var checks []doctorCheck
+	if token := app.AuthSvc.LoadBearerToken(); token != "" {
+		c := doctorCredentialCheck(ctx, app, "credentials account (bearer)", map[string]string{"Authorization": "Bearer " + token}, timeout)
+		if exp, ok := app.tokenExpiry(); ok && time.Now().After(exp) && c.Status != doctorOK {
+			c.Detail += " (token expired " + exp.Local().Format(time.RFC3339) + ")"
+		}
+		checks = append(checks, c)
+	}
+	for i := range app.Config.Projects {
+		p := &app.Config.Projects[i]
+		res, err := app.AuthSvc.BuildHeaders(p)
+		name := "credentials project " + p.Name
+		if err != nil {
+			checks = append(checks, doctorCheck{Name: name, Status: doctorFail, Detail: err.Error(), Hint: "store the secret with `wiro auth set --api-key " + p.APIKey + " --api-secret <secret>`"})
+			continue
+		}
+		checks = append(checks, doctorCredentialCheck(ctx, app, fmt.Sprintf("%s (%s)", name, res.Mode), res.Headers, timeout))
+	}
+	if len(checks) == 0 {
+		checks = append(checks, doctorCheck{Name: "credentials", Status: doctorFail, Detail: "no stored or environment credentials", Hint: "run `wiro auth login` or `wiro auth set --api-key <key> --api-secret <secret>`"})
+	}
+	return checks
+}
+
+func doctorCredentialCheck(ctx context.Context, app *App, name string, headers map[string]string, timeout time.Duration) doctorCheck {
+	// Never refresh or prompt for a new login while diagnosing.
+	c, cancel := context.WithTimeout(api.WithoutTokenRefresh(ctx), timeout)
+	defer cancel()
+	balance, err := app.AccountSvc.Balance(c, headers)
+	if err != nil {
+		check := doctorCheck{Name: name, Status: doctorFail, Detail: err.Error()}
+		if errors.Is(err, api.ErrUnauthorized) {
+			check.Hint = "the credentials were rejected; run `wiro auth login` or `wiro auth set` again"
+		}
+		return check
+	}
+	return doctorCheck{Name: name, Status: doctorOK, Detail: "valid, balance " + formatCredits(balance.Credits, balance.Currency)}
+}
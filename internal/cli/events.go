@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/task"
+)
+
+// jsonEvent is one line of `wiro run --json-events` output.
+type jsonEvent struct {
+	Source    string      `json:"source"`
+	Type      string      `json:"type"`
+	Timestamp string      `json:"timestamp"`
+	Text      string      `json:"text,omitempty"`
+	Payload   interface{} `json:"payload,omitempty"`
+	// Paths lists downloaded files on the final task event.
+	Paths []string `json:"paths,omitempty"`
+}
+
+// eventWriter writes NDJSON events; watch callbacks arrive from several goroutines.
+type eventWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	now func() time.Time
+}
+
+func newEventWriter(w io.Writer) *eventWriter {
+	return &eventWriter{enc: json.NewEncoder(w), now: time.Now}
+}
+
+func newStdoutEventWriter() *eventWriter {
+	return newEventWriter(os.Stdout)
+}
+
+func (e *eventWriter) emit(ev jsonEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	ev.Timestamp = e.now().UTC().Format(time.RFC3339Nano)
+	_ = e.enc.Encode(ev)
+}
+
+// watchEvent forwards a watch event with its raw payload.
+func (e *eventWriter) watchEvent(ev task.WatchEvent) {
+	var payload interface{}
+	if len(ev.Raw) > 0 {
+		payload = ev.Raw
+	}
+	e.emit(jsonEvent{Source: ev.Source, Type: ev.Type, Text: ev.Text, Payload: payload})
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/task"
+)
+
+func TestEventWriter_NDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	w := newEventWriter(&buf)
+	w.now = func() time.Time { return time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC) }
+
+	w.watchEvent(task.WatchEvent{Source: "ws", Type: "task_output", Text: `"hi"`, Raw: map[string]interface{}{"type": "task_output"}})
+	w.watchEvent(task.WatchEvent{Source: "system", Type: "connection", Text: task.ConnStateLive})
+	w.emit(jsonEvent{Source: "system", Type: "task", Payload: &api.Task{ID: "9", Status: "task_postprocess_end"}, Paths: []string{"out/a.png"}})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", buf.String())
+	}
+	var first map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("line 1: %v", err)
+	}
+	if first["source"] != "ws" || first["type"] != "task_output" || first["timestamp"] != "2024-05-01T12:00:00Z" || first["payload"] == nil {
+		t.Fatalf("unexpected event %v", first)
+	}
+	if strings.Contains(lines[1], "payload") {
+		t.Fatalf("events without raw data should omit payload: %s", lines[1])
+	}
+	var last jsonEvent
+	if err := json.Unmarshal([]byte(lines[2]), &last); err != nil {
+		t.Fatalf("line 3: %v", err)
+	}
+	if last.Type != "task" || len(last.Paths) != 1 {
+		t.Fatalf("unexpected final event %+v", last)
+	}
+}
+
+func TestTranscriptRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := newEventWriter(&buf)
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return at }
+	tr := &transcript{w: w}
+	tr.watchEvent(task.WatchEvent{Source: "ws", Type: "task_queue", Raw: map[string]interface{}{"queueposition": 2}})
+	at = at.Add(4 * time.Second)
+	tr.watchEvent(task.WatchEvent{Source: "ws", Type: "task_start"})
+	at = at.Add(90 * time.Second)
+	tr.watchEvent(task.WatchEvent{Source: "poll", Type: "task_start"})
+	w.emit(jsonEvent{Source: "system", Type: "task", Payload: &api.Task{ID: "9", Status: "task_postprocess_end"}})
+	buf.WriteString(`{"source":"ws","ty`) // cut off by a killed watch
+
+	events, err := readTranscript(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 4 {
+		t.Fatalf("read %d events", len(events))
+	}
+	var out strings.Builder
+	printTranscript(&out, events)
+	got := out.String()
+	for _, want := range []string{
+		"+0.0s  [ws] task_queue (queue position 2)\n",
+		"+4.0s  [ws] task_start  (4.0s after task_queue)\n",
+		"+1m34s  [poll] task_start\n",
+		"[final] task_postprocess_end\n",
+		"Phases: queue 4.0s, run 1m30s\n",
+		"Total 1m34s\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("missing %q in:\n%s", want, got)
+		}
+	}
+
+	var nilTranscript *transcript
+	nilTranscript.watchEvent(task.WatchEvent{Type: "task_start"})
+	nilTranscript.finish(nil, nil)
+}
+
+func TestTaskPhasesFromTimestamps(t *testing.T) {
+	got := phaseSummary(taskPhases(&api.Task{ID: "7", CreateTime: "1700000000", StartTime: "1700000012", EndTime: "1700000100"}, t.TempDir()))
+	if got != "queue 12.0s, run 1m28s" {
+		t.Fatalf("phases = %q", got)
+	}
+	if got := taskPhases(&api.Task{ID: "7", CreateTime: "1700000000"}, t.TempDir()); got != nil {
+		t.Fatalf("unstarted task has phases %v", got)
+	}
+}
@@ -0,0 +1,188 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/log"
+	"github.com/wiro-ai/wiro-cli/internal/model"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+)
+
+const favUsage = "usage: wiro model fav <add|rm|ls> [owner/model ...]"
+
+func modelFavCommand(ctx context.Context, app *App, args []string) error {
+	if len(args) == 0 {
+		return errors.New(favUsage)
+	}
+	switch sub := strings.TrimSpace(args[0]); sub {
+	case "add":
+		return modelFavAddCommand(ctx, app, args[1:])
+	case "rm", "remove":
+		return modelFavRemoveCommand(app, args[1:])
+	case "ls", "list":
+		return modelFavListCommand(app, args[1:])
+	case "--help", "-h", "help":
+		output.Println("Usage: " + strings.TrimPrefix(favUsage, "usage: "))
+		return nil
+	default:
+		return fmt.Errorf("unknown fav command %q", sub)
+	}
+}
+
+// modelFavAddCommand stars models after checking that they exist. Aliases
+// are starred as the model they point at.
+func modelFavAddCommand(ctx context.Context, app *App, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: wiro model fav add <owner/model...>")
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	for _, arg := range args {
+		owner, slug, err := parseModelArg(arg)
+		if err != nil {
+			return err
+		}
+		if _, err := app.ModelSvc.Detail(timeoutCtx, owner, slug); err != nil {
+			return err
+		}
+		name := owner + "/" + slug
+		if !app.Config.AddFavorite(name) {
+			output.Printf("%s is already a favorite.\n", name)
+			continue
+		}
+		output.Printf("Added %s to favorites.\n", name)
+	}
+	return app.SaveConfig()
+}
+
+func modelFavRemoveCommand(app *App, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: wiro model fav rm <owner/model...>")
+	}
+	for _, arg := range args {
+		owner, slug, err := parseModelArg(arg)
+		if err != nil {
+			return err
+		}
+		if !app.Config.RemoveFavorite(owner + "/" + slug) {
+			return fmt.Errorf("%s/%s is not a favorite", owner, slug)
+		}
+	}
+	if err := app.SaveConfig(); err != nil {
+		return err
+	}
+	output.Printf("Removed %d favorite(s).\n", len(args))
+	return nil
+}
+
+func modelFavListCommand(app *App, args []string) error {
+	fs := flag.NewFlagSet("model fav ls", flag.ContinueOnError)
+	var asJSON bool
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() > 0 {
+		return errors.New("usage: wiro model fav ls [--json]")
+	}
+	favs := app.Config.Favorites
+	if favs == nil {
+		favs = []string{}
+	}
+	if asJSON {
+		return output.PrintJSON(favs)
+	}
+	if len(favs) == 0 {
+		output.Println("No favorites. Add one with `wiro model fav add <owner/model>`.")
+		return nil
+	}
+	for _, f := range favs {
+		output.Println(f)
+	}
+	return nil
+}
+
+// favoriteSummaries loads the favorites matching opts' query, category, tag,
+// and owner, in the order they were starred. Favorites that cannot be loaded
+// are skipped and reported in failed.
+func favoriteSummaries(ctx context.Context, app *App, opts model.ListOptions) (out []api.ToolSummary, failed []string) {
+	for _, fav := range app.Config.Favorites {
+		owner, slug, err := splitModel(fav)
+		if err != nil {
+			continue
+		}
+		d, err := app.ModelSvc.Detail(ctx, owner, slug)
+		if err != nil {
+			log.Verbosef("favorite %s: %v", fav, err)
+			failed = append(failed, fav)
+			continue
+		}
+		t := api.ToolSummary{
+			ID:          d.ID,
+			Title:       d.Title,
+			SlugOwner:   d.SlugOwner,
+			SlugProject: d.SlugProject,
+			Description: d.Description,
+			Image:       d.Image,
+			Categories:  d.Categories,
+			Tags:        d.Tags,
+		}
+		if favoriteMatches(t, opts) {
+			out = append(out, t)
+		}
+	}
+	return out, failed
+}
+
+func favoriteMatches(t api.ToolSummary, opts model.ListOptions) bool {
+	if q := strings.ToLower(strings.TrimSpace(opts.Query)); q != "" {
+		text := strings.ToLower(t.SlugOwner + "/" + t.SlugProject + " " + t.Title + " " + t.Description)
+		if !strings.Contains(text, q) {
+			return false
+		}
+	}
+	if o := strings.TrimSpace(opts.Owner); o != "" && !strings.EqualFold(o, t.SlugOwner) {
+		return false
+	}
+	if c := strings.TrimSpace(opts.Category); c != "" && !hasTerm(model.Terms(t.Categories), c) {
+		return false
+	}
+	if tag := strings.TrimSpace(opts.Tag); tag != "" && !hasTerm(model.Terms(t.Tags), tag) {
+		return false
+	}
+	return true
+}
+
+func hasTerm(terms []string, want string) bool {
+	for _, t := range terms {
+		if strings.EqualFold(t, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// withFavoritesFirst puts favs ahead of the tools that are not among them.
+func withFavoritesFirst(favs, tools []api.ToolSummary) []api.ToolSummary {
+	seen := map[string]bool{}
+	out := make([]api.ToolSummary, 0, len(favs)+len(tools))
+	for _, t := range favs {
+		seen[strings.ToLower(t.SlugOwner+"/"+t.SlugProject)] = true
+		out = append(out, t)
+	}
+	for _, t := range tools {
+		if !seen[strings.ToLower(t.SlugOwner+"/"+t.SlugProject)] {
+			out = append(out, t)
+		}
+	}
+	return out
+}
@@ -3,16 +3,20 @@ package cli
 import (
 	"bufio"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"regexp"
-	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/wiro-ai/wiro-cli/internal/api"
 	"github.com/wiro-ai/wiro-cli/internal/model"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	"github.com/wiro-ai/wiro-cli/internal/runspec"
+	"github.com/wiro-ai/wiro-cli/internal/term"
 )
 
 type parameterInputKind string
@@ -40,7 +44,129 @@ func (s *stringSlice) Set(value string) error {
 	return nil
 }
 
+// outputSelection collects --output-index/--output-match flags.
+type outputSelection struct {
+	indexes  stringSlice
+	patterns stringSlice
+}
+
+func (s *outputSelection) register(fs *flag.FlagSet) {
+	fs.Var(&s.indexes, "output-index", "Download only this output (1-based, e.g. 2 or 1,3). Repeatable")
+	fs.Var(&s.patterns, "output-match", "Download only outputs whose name matches this glob (e.g. '*.mp4'). Repeatable")
+}
+
+func (s *outputSelection) filter() (output.OutputFilter, error) {
+	f := output.OutputFilter{Patterns: s.patterns}
+	for _, raw := range s.indexes {
+		for _, part := range strings.Split(raw, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			n, err := strconv.Atoi(part)
+			if err != nil {
+				return output.OutputFilter{}, fmt.Errorf("invalid --output-index %q", raw)
+			}
+			f.Indexes = append(f.Indexes, n)
+		}
+	}
+	return f, f.Validate()
+}
+
+// paramFiles holds --set-json and --set-yaml parameter files.
+type paramFiles struct {
+	jsonPaths stringSlice
+	yamlPaths stringSlice
+	// fields maps each field id the files set to the last file setting it.
+	fields map[string]string
+}
+
+func (p *paramFiles) register(fs *flag.FlagSet) {
+	fs.Var(&p.jsonPaths, "set-json", "Read field values from a JSON file of field ids to values. Repeatable")
+	fs.Var(&p.yamlPaths, "set-yaml", "Read field values from a YAML file of field ids to values. Repeatable")
+}
+
+// merge puts the parameter files' values before set, setFile, and setURL,
+// dropping those the flags set again. A later file overrides an earlier one.
+func (p *paramFiles) merge(set, setFile, setURL []string) ([]string, []string, []string, error) {
+	var fileSet, fileSetFile, fileSetURL []string
+	load := func(path, format string) error {
+		s, f, u, err := runspec.LoadParams(path, format)
+		if err != nil {
+			return err
+		}
+		if p.fields == nil {
+			p.fields = map[string]string{}
+		}
+		for _, kv := range append(append(append([]string{}, s...), f...), u...) {
+			k, _, _ := strings.Cut(kv, "=")
+			p.fields[k] = path
+		}
+		fileSet = append(overrideKeys(fileSet, s, f, u), s...)
+		fileSetFile = append(overrideKeys(fileSetFile, s, f, u), f...)
+		fileSetURL = append(overrideKeys(fileSetURL, s, f, u), u...)
+		return nil
+	}
+	for _, path := range p.jsonPaths {
+		if err := load(path, "json"); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	for _, path := range p.yamlPaths {
+		if err := load(path, "yaml"); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return append(overrideKeys(fileSet, set, setFile, setURL), set...),
+		append(overrideKeys(fileSetFile, set, setFile, setURL), setFile...),
+		append(overrideKeys(fileSetURL, set, setFile, setURL), setURL...),
+		nil
+}
+
+// checkParamFields rejects parameter file fields that are not in the model
+// schema; a typo there would otherwise be sent and silently ignored.
+func checkParamFields(items []api.ToolParameterItem, fields map[string]string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	known := make(map[string]bool, len(items))
+	for _, item := range items {
+		known[item.ID] = true
+	}
+	var unknown []string
+	for id, path := range fields {
+		if !known[id] {
+			unknown = append(unknown, fmt.Sprintf("%q (%s)", id, path))
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+	sort.Strings(unknown)
+	return fmt.Errorf("parameter file sets unknown field(s) %s; see `wiro model inspect` for field ids", strings.Join(unknown, ", "))
+}
+
+// parseModelArg splits owner/model, expanding an alias from `wiro alias set`.
 func parseModelArg(arg string) (owner, slug string, err error) {
+	owner, slug, _, err = expandModelArg(arg)
+	return owner, slug, err
+}
+
+// expandModelArg is parseModelArg that also returns the alias's --set defaults.
+func expandModelArg(arg string) (owner, slug string, defaults []string, err error) {
+	arg = strings.TrimSpace(arg)
+	if alias, ok := modelAliases[arg]; ok && !strings.Contains(arg, "/") {
+		owner, slug, err := splitModel(alias.Model)
+		return owner, slug, alias.Set, err
+	}
+	owner, slug, err = splitModel(arg)
+	if err != nil && !strings.Contains(arg, "/") {
+		err = fmt.Errorf("model must be in owner/model format or an alias (see `wiro alias ls`), got %q", arg)
+	}
+	return owner, slug, nil, err
+}
+
+func splitModel(arg string) (owner, slug string, err error) {
 	parts := strings.Split(strings.TrimSpace(arg), "/")
 	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
 		return "", "", fmt.Errorf("model must be in owner/model format, got %q", arg)
@@ -62,6 +188,26 @@ func parseKeyValuePairs(values []string) (map[string][]string, error) {
 	return out, nil
 }
 
+// readStdinValue reads all of piped stdin as a single field value.
+func readStdinValue() (string, error) {
+	in, err := os.Stdin.Stat()
+	if err != nil {
+		return "", fmt.Errorf("stat stdin: %w", err)
+	}
+	if (in.Mode() & os.ModeCharDevice) != 0 {
+		return "", errors.New("--set-stdin expects piped input (e.g. echo \"a cat\" | wiro run owner/model --set-stdin prompt)")
+	}
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("read stdin: %w", err)
+	}
+	val := strings.TrimRight(string(data), "\r\n")
+	if strings.TrimSpace(val) == "" {
+		return "", errors.New("stdin is empty")
+	}
+	return val, nil
+}
+
 func mergeParamSources(textSets, fileSets, urlSets map[string][]string) map[string][]api.MultipartValue {
 	out := map[string][]api.MultipartValue{}
 	for k, vals := range textSets {
@@ -92,166 +238,223 @@ func buildInteractiveInputs(items []api.ToolParameterItem, preset map[string][]a
 		if _, ok := result[item.ID]; ok {
 			continue
 		}
-
-		label := item.Label
-		if strings.TrimSpace(label) == "" {
-			label = item.ID
+		// Fields come in schema order, so the answers a showif rule reads
+		// are already in result.
+		if !model.Visible(item, items, result) {
+			continue
+		}
+		vals, err := promptField(item)
+		if err != nil {
+			return nil, err
 		}
+		if len(vals) > 0 {
+			result[item.ID] = vals
+		}
+	}
 
-		switch mapParameterKind(item.Type) {
-		case paramText:
-			def := defaultString(item.DefaultValue)
-			if isPromptField(item) {
-				def = ""
-			}
-			val, err := promptInput(fmt.Sprintf("%s (%s)", label, item.ID), def)
-			if err != nil {
-				return nil, err
-			}
-			if strings.TrimSpace(val) == "" && (item.Required || isPromptField(item)) {
-				return nil, fmt.Errorf("required field %q is empty", item.ID)
-			}
-			if strings.TrimSpace(val) != "" {
-				result[item.ID] = []api.MultipartValue{{Value: val}}
-			}
-		case paramNumber:
-			ans, err := promptInput(fmt.Sprintf("%s (%s)", label, item.ID), defaultString(item.DefaultValue))
-			if err != nil {
-				return nil, err
+	if err := validateRequired(items, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// promptField asks for the value of one field. It returns nil when the field
+// is left empty, which is an error for required fields.
+func promptField(item api.ToolParameterItem) ([]api.MultipartValue, error) {
+	label := item.Label
+	if strings.TrimSpace(label) == "" {
+		label = item.ID
+	}
+
+	switch mapParameterKind(item.Type) {
+	case paramText:
+		def := defaultString(item.DefaultValue)
+		if isPromptField(item) {
+			def = ""
+		}
+		var val string
+		var err error
+		if model.IsSecret(item) {
+			val, err = promptPassword(fmt.Sprintf("%s (%s, hidden)", label, item.ID))
+		} else {
+			val, err = promptInput(fmt.Sprintf("%s (%s)", label, item.ID), def)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(val) == "" && (item.Required || isPromptField(item)) {
+			return nil, fmt.Errorf("required field %q is empty", item.ID)
+		}
+		if strings.TrimSpace(val) != "" {
+			return []api.MultipartValue{{Value: val}}, nil
+		}
+	case paramNumber:
+		ans, err := promptInput(fmt.Sprintf("%s (%s)", label, item.ID), defaultString(item.DefaultValue))
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(ans) == "" && item.Required {
+			return nil, fmt.Errorf("required field %q is empty", item.ID)
+		}
+		if strings.TrimSpace(ans) != "" {
+			if _, err := strconv.Atoi(ans); err != nil {
+				return nil, fmt.Errorf("field %q expects number", item.ID)
 			}
-			if strings.TrimSpace(ans) == "" && item.Required {
-				return nil, fmt.Errorf("required field %q is empty", item.ID)
+			return []api.MultipartValue{{Value: ans}}, nil
+		}
+	case paramFloat:
+		ans, err := promptInput(fmt.Sprintf("%s (%s)", label, item.ID), defaultString(item.DefaultValue))
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(ans) == "" && item.Required {
+			return nil, fmt.Errorf("required field %q is empty", item.ID)
+		}
+		if strings.TrimSpace(ans) != "" {
+			if _, err := strconv.ParseFloat(ans, 64); err != nil {
+				return nil, fmt.Errorf("field %q expects float", item.ID)
 			}
-			if strings.TrimSpace(ans) != "" {
-				if _, err := strconv.Atoi(ans); err != nil {
-					return nil, fmt.Errorf("field %q expects number", item.ID)
-				}
-				result[item.ID] = []api.MultipartValue{{Value: ans}}
+			return []api.MultipartValue{{Value: ans}}, nil
+		}
+	case paramCheckbox:
+		def := strings.EqualFold(defaultString(item.DefaultValue), "true") || defaultString(item.DefaultValue) == "1"
+		ans, err := promptConfirm(fmt.Sprintf("%s (%s)", label, item.ID), def)
+		if err != nil {
+			return nil, err
+		}
+		if ans {
+			return []api.MultipartValue{{Value: "true"}}, nil
+		}
+	case paramSelect:
+		if len(item.Options) == 0 {
+			return nil, nil
+		}
+		opts := make([]string, 0, len(item.Options))
+		covers := make([]string, 0, len(item.Options))
+		toVal := map[int]string{}
+		defaultIdx := 0
+		def := defaultString(item.DefaultValue)
+		for i, opt := range item.Options {
+			val := fmt.Sprint(opt.Value)
+			text := strings.TrimSpace(opt.Text)
+			if text == "" {
+				text = val
+			}
+			d := fmt.Sprintf("%s -> %s", text, val)
+			opts = append(opts, d)
+			covers = append(covers, strings.TrimSpace(opt.Cover))
+			toVal[i] = val
+			if def != "" && val == def {
+				defaultIdx = i
 			}
-		case paramFloat:
-			ans, err := promptInput(fmt.Sprintf("%s (%s)", label, item.ID), defaultString(item.DefaultValue))
-			if err != nil {
-				return nil, err
+		}
+		idx, err := promptSelectLinked(fmt.Sprintf("%s (%s)", label, item.ID), opts, covers, defaultIdx)
+		if err != nil {
+			return nil, err
+		}
+		return []api.MultipartValue{{Value: toVal[idx]}}, nil
+	case paramCombineFile:
+		def := defaultArrayCSV(item.DefaultValue)
+		if strings.TrimSpace(def) != "" {
+			defCount := len(splitCSV(def))
+			if defCount > 0 {
+				output.Printf("Model sample inputs available (%d item(s)); type \"sample\" to use them.\n", defCount)
+			} else {
+				output.Println("Model sample input available; type \"sample\" to use it.")
 			}
-			if strings.TrimSpace(ans) == "" && item.Required {
+		}
+		ans, err := promptInput(
+			fmt.Sprintf("%s (%s) comma-separated file paths or URLs", label, item.ID),
+			"",
+		)
+		if err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(strings.TrimSpace(ans), "sample") && strings.TrimSpace(def) != "" {
+			ans = def
+		}
+		values := splitCSV(ans)
+		if len(values) == 0 {
+			if item.Required {
 				return nil, fmt.Errorf("required field %q is empty", item.ID)
 			}
-			if strings.TrimSpace(ans) != "" {
-				if _, err := strconv.ParseFloat(ans, 64); err != nil {
-					return nil, fmt.Errorf("field %q expects float", item.ID)
-				}
-				result[item.ID] = []api.MultipartValue{{Value: ans}}
-			}
-		case paramCheckbox:
-			def := strings.EqualFold(defaultString(item.DefaultValue), "true") || defaultString(item.DefaultValue) == "1"
-			ans, err := promptConfirm(fmt.Sprintf("%s (%s)", label, item.ID), def)
-			if err != nil {
-				return nil, err
-			}
-			if ans {
-				result[item.ID] = []api.MultipartValue{{Value: "true"}}
-			}
-		case paramSelect:
-			if len(item.Options) == 0 {
-				continue
-			}
-			opts := make([]string, 0, len(item.Options))
-			toVal := map[int]string{}
-			defaultIdx := 0
-			def := defaultString(item.DefaultValue)
-			for i, opt := range item.Options {
-				val := fmt.Sprint(opt.Value)
-				text := strings.TrimSpace(opt.Text)
-				if text == "" {
-					text = val
-				}
-				d := fmt.Sprintf("%s -> %s", text, val)
-				opts = append(opts, d)
-				toVal[i] = val
-				if def != "" && val == def {
-					defaultIdx = i
-				}
-			}
-			idx, err := promptSelect(fmt.Sprintf("%s (%s)", label, item.ID), opts, defaultIdx)
-			if err != nil {
-				return nil, err
-			}
-			result[item.ID] = []api.MultipartValue{{Value: toVal[idx]}}
-		case paramCombineFile:
-			def := defaultArrayCSV(item.DefaultValue)
-			if strings.TrimSpace(def) != "" {
-				defCount := len(splitCSV(def))
-				if defCount > 0 {
-					fmt.Printf("Model sample inputs available (%d item(s)); type \"sample\" to use them.\n", defCount)
-				} else {
-					fmt.Println("Model sample input available; type \"sample\" to use it.")
-				}
-			}
-			ans, err := promptInput(
-				fmt.Sprintf("%s (%s) comma-separated file paths or URLs", label, item.ID),
-				"",
-			)
-			if err != nil {
-				return nil, err
-			}
-			if strings.EqualFold(strings.TrimSpace(ans), "sample") && strings.TrimSpace(def) != "" {
-				ans = def
-			}
-			values := splitCSV(ans)
-			if len(values) == 0 {
-				if item.Required {
-					return nil, fmt.Errorf("required field %q is empty", item.ID)
-				}
+			return nil, nil
+		}
+		if item.MaxInputLenght > 0 && len(values) > item.MaxInputLenght {
+			return nil, fmt.Errorf("field %q accepts max %d entries", item.ID, item.MaxInputLenght)
+		}
+		parts := make([]api.MultipartValue, 0, len(values))
+		for _, v := range values {
+			if looksURL(v) {
+				parts = append(parts, api.MultipartValue{Value: v})
 				continue
 			}
-			if item.MaxInputLenght > 0 && len(values) > item.MaxInputLenght {
-				return nil, fmt.Errorf("field %q accepts max %d entries", item.ID, item.MaxInputLenght)
+			if _, err := os.Stat(v); err == nil {
+				parts = append(parts, api.MultipartValue{FilePath: v})
+			} else {
+				return nil, fmt.Errorf("file not found for %q value %q", item.ID, v)
 			}
-			parts := make([]api.MultipartValue, 0, len(values))
-			for _, v := range values {
-				if looksURL(v) {
-					parts = append(parts, api.MultipartValue{Value: v})
-					continue
-				}
-				if _, err := os.Stat(v); err == nil {
-					parts = append(parts, api.MultipartValue{FilePath: v})
-				} else {
-					return nil, fmt.Errorf("file not found for %q value %q", item.ID, v)
-				}
-			}
-			result[item.ID] = parts
-		case paramRaw:
-			fallthrough
-		default:
-			ans, err := promptInput(fmt.Sprintf("%s (%s, raw)", label, item.ID), defaultString(item.DefaultValue))
-			if err != nil {
-				return nil, err
-			}
-			if strings.TrimSpace(ans) == "" {
-				if item.Required {
-					return nil, fmt.Errorf("required field %q is empty", item.ID)
-				}
-				continue
+		}
+		return parts, nil
+	case paramRaw:
+		fallthrough
+	default:
+		ans, err := promptInput(fmt.Sprintf("%s (%s, raw)", label, item.ID), defaultString(item.DefaultValue))
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(ans) == "" {
+			if item.Required {
+				return nil, fmt.Errorf("required field %q is empty", item.ID)
 			}
-			result[item.ID] = []api.MultipartValue{{Value: ans}}
+			return nil, nil
 		}
+		return []api.MultipartValue{{Value: ans}}, nil
 	}
+	return nil, nil
+}
 
-	if err := validateRequired(items, result); err != nil {
-		return nil, err
+func buildNonInteractiveInputs(items []api.ToolParameterItem, preset map[string][]api.MultipartValue) (map[string][]api.MultipartValue, error) {
+	return checkNonInteractiveInputs(items, items, preset)
+}
+
+// checkNonInteractiveInputs validates preset values against schema and requires every
+// required field in items, reporting all missing and invalid fields in one error.
+func checkNonInteractiveInputs(schema, items []api.ToolParameterItem, preset map[string][]api.MultipartValue) (map[string][]api.MultipartValue, error) {
+	required := make([]api.ToolParameterItem, 0, len(items))
+	for _, item := range items {
+		if item.Required || isPromptField(item) {
+			required = append(required, item)
+		}
 	}
-	return result, nil
+	return model.CheckInputs(schema, preset, required)
 }
 
-func buildNonInteractiveInputs(items []api.ToolParameterItem, preset map[string][]api.MultipartValue) (map[string][]api.MultipartValue, error) {
-	result := map[string][]api.MultipartValue{}
+// quickInputs splits items for --quick: it returns the fields to prompt (marked Quick,
+// prompt fields, and required fields without a default) and fills the others' defaults into preset.
+func quickInputs(items []api.ToolParameterItem, preset map[string][]api.MultipartValue) ([]api.ToolParameterItem, map[string][]api.MultipartValue) {
+	filled := make(map[string][]api.MultipartValue, len(preset))
 	for k, v := range preset {
-		result[k] = append(result[k], v...)
+		filled[k] = v
 	}
-	if err := validateRequired(items, result); err != nil {
-		return nil, err
+	prompted := make([]api.ToolParameterItem, 0, len(items))
+	for _, item := range items {
+		def := strings.TrimSpace(defaultString(item.DefaultValue))
+		if item.Quick || isPromptField(item) || (item.Required && def == "") {
+			prompted = append(prompted, item)
+			continue
+		}
+		if _, ok := filled[item.ID]; ok || def == "" {
+			continue
+		}
+		switch mapParameterKind(item.Type) {
+		case paramCombineFile, paramCheckbox:
+			// Sample files and unchecked boxes are left to the server default.
+		default:
+			filled[item.ID] = []api.MultipartValue{{Value: def}}
+		}
 	}
-	return result, nil
+	return prompted, filled
 }
 
 func mapParameterKind(paramType string) parameterInputKind {
@@ -275,7 +478,7 @@ func mapParameterKind(paramType string) parameterInputKind {
 
 func validateRequired(items []api.ToolParameterItem, values map[string][]api.MultipartValue) error {
 	for _, item := range items {
-		if !item.Required && !isPromptField(item) {
+		if !item.Required && !isPromptField(item) || !model.Visible(item, items, values) {
 			continue
 		}
 		vals, ok := values[item.ID]
@@ -379,15 +582,9 @@ func selectModelInteractive(models []api.ToolSummary) (*api.ToolSummary, error)
 	return &picked, nil
 }
 
+// short trims v and cuts it to max columns, ending in "..." when cut.
 func short(v string, max int) string {
-	v = strings.TrimSpace(v)
-	if len(v) <= max {
-		return v
-	}
-	if max <= 3 {
-		return v[:max]
-	}
-	return v[:max-3] + "..."
+	return term.Truncate(strings.TrimSpace(v), max, "...")
 }
 
 func modelItems(detail *api.ToolDetail, includeAdvanced bool) []api.ToolParameterItem {
@@ -395,26 +592,24 @@ func modelItems(detail *api.ToolDetail, includeAdvanced bool) []api.ToolParamete
 }
 
 func isInteractiveSession() bool {
-	in, err := os.Stdin.Stat()
-	if err != nil {
-		return false
-	}
-	if (in.Mode() & os.ModeCharDevice) == 0 {
-		return false
-	}
-	out, err := os.Stdout.Stat()
+	return isTerminalFile(os.Stdin) && isTerminalFile(os.Stdout)
+}
+
+// isTerminalFile reports whether f is a character device such as a terminal.
+func isTerminalFile(f *os.File) bool {
+	info, err := f.Stat()
 	if err != nil {
 		return false
 	}
-	return (out.Mode() & os.ModeCharDevice) != 0
+	return (info.Mode() & os.ModeCharDevice) != 0
 }
 
 func promptInput(message, def string) (string, error) {
 	reader := bufio.NewReader(os.Stdin)
 	if def != "" {
-		fmt.Printf("%s [%s]: ", message, def)
+		output.Printf("%s [%s]: ", message, def)
 	} else {
-		fmt.Printf("%s: ", message)
+		output.Printf("%s: ", message)
 	}
 	line, err := reader.ReadString('\n')
 	if err != nil {
@@ -434,17 +629,15 @@ func promptPassword(message string) (string, error) {
 	if !isInteractiveSession() {
 		return promptInput(message, "")
 	}
-	state, err := sttyState()
+	output.Printf("%s: ", message)
+	restore, err := term.DisableEcho()
 	if err != nil {
-		return promptInput(message, "")
-	}
-	fmt.Printf("%s: ", message)
-	if err := stty("-echo"); err != nil {
+		output.Println()
 		return promptInput(message, "")
 	}
 	defer func() {
-		_ = stty(strings.TrimSpace(state))
-		fmt.Println()
+		restore()
+		output.Println()
 	}()
 
 	reader := bufio.NewReader(os.Stdin)
@@ -466,7 +659,7 @@ func promptSecret(message string) (string, error) {
 	}
 
 	// Some terminals block paste under hidden input. Visible fallback keeps setup unblocked.
-	fmt.Println("No input captured in hidden mode. Switching to visible input fallback.")
+	output.Println("No input captured in hidden mode. Switching to visible input fallback.")
 	return promptInput(message+" (visible fallback)", "")
 }
 
@@ -493,6 +686,14 @@ func promptConfirm(message string, def bool) (bool, error) {
 }
 
 func promptSelect(message string, options []string, defaultIdx int) (int, error) {
+	return promptSelectLinked(message, options, nil, defaultIdx)
+}
+
+// promptSelectLinked is promptSelect with a preview URL per option, such as
+// the covers of a selectwithcover field; "" means none. Options become
+// clickable on terminals that render OSC 8 links, and other numbered menus
+// print the URL.
+func promptSelectLinked(message string, options, links []string, defaultIdx int) (int, error) {
 	if len(options) == 0 {
 		return 0, errors.New("no options")
 	}
@@ -500,17 +701,33 @@ func promptSelect(message string, options []string, defaultIdx int) (int, error)
 		defaultIdx = 0
 	}
 	if isInteractiveSession() {
-		if idx, err := promptSelectArrows(message, options, defaultIdx); err == nil {
+		if idx, err := promptSelectArrows(message, options, links, defaultIdx); err == nil {
 			return idx, nil
 		}
 	}
-	return promptSelectNumeric(message, options, defaultIdx)
+	return promptSelectNumeric(message, options, links, defaultIdx)
+}
+
+// optionLink returns the link of option i, or "" when it has none.
+func optionLink(links []string, i int) string {
+	if i < len(links) {
+		return links[i]
+	}
+	return ""
 }
 
-func promptSelectNumeric(message string, options []string, defaultIdx int) (int, error) {
-	fmt.Println(message)
+func promptSelectNumeric(message string, options, links []string, defaultIdx int) (int, error) {
+	output.Println(message)
+	hyperlinks := isInteractiveSession() && term.HyperlinksSupported()
 	for i, option := range options {
-		fmt.Printf("  %d) %s\n", i+1, option)
+		switch link := optionLink(links, i); {
+		case link == "":
+		case hyperlinks:
+			option = term.Hyperlink(link, option)
+		default:
+			option += "  " + link
+		}
+		output.Printf("  %d) %s\n", i+1, option)
 	}
 	defLabel := strconv.Itoa(defaultIdx + 1)
 	ans, err := promptInput("Select option number", defLabel)
@@ -524,168 +741,82 @@ func promptSelectNumeric(message string, options []string, defaultIdx int) (int,
 	return idx - 1, nil
 }
 
-func promptSelectArrows(message string, options []string, defaultIdx int) (int, error) {
-	if runtime.GOOS == "windows" {
-		return 0, errors.New("arrow prompt is not available on windows")
-	}
-	state, err := sttyState()
+func promptSelectArrows(message string, options, links []string, defaultIdx int) (int, error) {
+	restore, err := term.MakeRaw()
 	if err != nil {
 		return 0, err
 	}
-	if err := stty("raw", "-echo", "min", "1", "time", "0"); err != nil {
-		return 0, err
-	}
-	defer func() {
-		_ = stty(strings.TrimSpace(state))
-	}()
+	defer restore()
 
-	selected := defaultIdx
+	list := term.List{Selected: defaultIdx}
 	reader := bufio.NewReader(os.Stdin)
-	width := terminalWidth()
-	title := fitMenuLine(message, width-1)
+	width := term.Width()
+	title := term.Fit(message, width-1)
+	hyperlinks := term.HyperlinksSupported()
 	displayOptions := make([]string, 0, len(options))
-	for _, option := range options {
-		displayOptions = append(displayOptions, fitMenuLine(option, width-4))
+	for i, option := range options {
+		option = term.Fit(option, width-4)
+		// Linked after fitting, so the escape does not count toward the width.
+		if link := optionLink(links, i); link != "" && hyperlinks {
+			option = term.Hyperlink(link, option)
+		}
+		displayOptions = append(displayOptions, option)
 	}
 	lines := len(displayOptions) + 1
 	rendered := false
 
-	render := func() {
+	clear := func() {
 		if rendered {
 			for i := 0; i < lines; i++ {
-				fmt.Print("\033[1A\033[2K")
+				output.Print("\033[1A\033[2K")
 			}
 		}
-		fmt.Print("\r\033[2K")
-		fmt.Printf("%s (↑/↓ + Enter, j/k)\n", title)
+	}
+	render := func() {
+		clear()
+		output.Print("\r\033[2K")
+		output.Printf("%s (↑/↓ + Enter, j/k)\r\n", title)
 		for i, option := range displayOptions {
 			prefix := "  "
-			if i == selected {
+			if i == list.Selected {
 				prefix = "> "
 			}
-			fmt.Print("\r\033[2K")
-			fmt.Printf("%s%s\n", prefix, option)
+			output.Print("\r\033[2K")
+			output.Printf("%s%s\r\n", prefix, option)
 		}
 		rendered = true
 	}
 
 	render()
 	for {
-		b, readErr := reader.ReadByte()
+		key, readErr := term.ReadKey(reader)
 		if readErr != nil {
 			return 0, readErr
 		}
-		switch b {
-		case '\r', '\n':
-			if rendered {
-				for i := 0; i < lines; i++ {
-					fmt.Print("\033[1A\033[2K")
-				}
-			}
-			choiceWidth := width - len(title) - 2
+		switch {
+		case key.Type == term.KeyEnter:
+			clear()
+			choiceWidth := width - term.StringWidth(title) - 2
 			if choiceWidth < 20 {
 				choiceWidth = 20
 			}
-			fmt.Printf("%s: %s\n", title, fitMenuLine(options[selected], choiceWidth))
-			return selected, nil
-		case 3:
+			output.Printf("%s: %s\r\n", title, term.Fit(options[list.Selected], choiceWidth))
+			return list.Selected, nil
+		case key.Type == term.KeyCtrlC:
 			return 0, errors.New("interrupted")
-		case 'k', 'K':
-			selected = (selected - 1 + len(options)) % len(options)
+		case key.Type == term.KeyUp, key.Type == term.KeyRune && (key.Rune == 'k' || key.Rune == 'K'):
+			list.Move(-1, len(options), true)
 			render()
-		case 'j', 'J':
-			selected = (selected + 1) % len(options)
+		case key.Type == term.KeyDown, key.Type == term.KeyRune && (key.Rune == 'j' || key.Rune == 'J'):
+			list.Move(1, len(options), true)
 			render()
-		case 27:
-			b2, err := reader.ReadByte()
-			if err != nil {
-				return 0, err
-			}
-			if b2 != '[' {
-				continue
-			}
-			b3, err := reader.ReadByte()
-			if err != nil {
-				return 0, err
-			}
-			switch b3 {
-			case 'A':
-				selected = (selected - 1 + len(options)) % len(options)
-				render()
-			case 'B':
-				selected = (selected + 1) % len(options)
+		case key.Type == term.KeyRune && key.Rune >= '1' && key.Rune <= '9':
+			if candidate := int(key.Rune - '1'); candidate < len(options) {
+				list.Selected = candidate
 				render()
 			}
-		default:
-			if b >= '1' && b <= '9' {
-				candidate := int(b - '1')
-				if candidate >= 0 && candidate < len(options) {
-					selected = candidate
-					render()
-				}
-			}
-		}
-	}
-}
-
-func fitMenuLine(s string, width int) string {
-	s = strings.ReplaceAll(s, "\n", " ")
-	s = strings.ReplaceAll(s, "\r", " ")
-	s = strings.Join(strings.Fields(s), " ")
-	if width < 8 {
-		width = 8
-	}
-	runes := []rune(s)
-	if len(runes) <= width {
-		return s
-	}
-	return string(runes[:width-3]) + "..."
-}
-
-func terminalWidth() int {
-	if raw := strings.TrimSpace(os.Getenv("COLUMNS")); raw != "" {
-		if n, err := strconv.Atoi(raw); err == nil && n >= 40 {
-			return n
-		}
-	}
-	if runtime.GOOS != "windows" {
-		cmd := exec.Command("stty", "size")
-		cmd.Stdin = os.Stdin
-		out, err := cmd.Output()
-		if err == nil {
-			parts := strings.Fields(strings.TrimSpace(string(out)))
-			if len(parts) == 2 {
-				if n, parseErr := strconv.Atoi(parts[1]); parseErr == nil && n >= 40 {
-					return n
-				}
-			}
 		}
 	}
-	return 100
-}
-
-func sttyState() (string, error) {
-	if runtime.GOOS == "windows" {
-		return "", errors.New("stty unavailable")
-	}
-	cmd := exec.Command("stty", "-g")
-	cmd.Stdin = os.Stdin
-	out, err := cmd.Output()
-	if err != nil {
-		return "", err
-	}
-	return strings.TrimSpace(string(out)), nil
-}
-
-func stty(args ...string) error {
-	if runtime.GOOS == "windows" {
-		return errors.New("stty unavailable")
-	}
-	cmd := exec.Command("stty", args...)
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
 }
 
 func sanitizePromptLine(line string) string {
@@ -1,6 +1,9 @@
 package cli
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/wiro-ai/wiro-cli/internal/api"
@@ -58,3 +61,136 @@ func TestBuildNonInteractiveInputs_PromptAlwaysRequired(t *testing.T) {
 		t.Fatalf("expected prompt to be required")
 	}
 }
+
+func TestBuildNonInteractiveInputs_ReportsAllProblems(t *testing.T) {
+	items := []api.ToolParameterItem{
+		{ID: "prompt", Type: "text"},
+		{ID: "steps", Type: "number", Required: true, MinValue: "1", MaxValue: "50"},
+		{ID: "size", Type: "select", Required: true, Options: []api.ToolOption{{Value: "512"}, {Value: "1024"}}},
+		{ID: "scale", Type: "float", MaxValue: "2"},
+	}
+	_, err := buildNonInteractiveInputs(items, map[string][]api.MultipartValue{
+		"scale": {{Value: "9"}},
+	})
+	if err == nil {
+		t.Fatalf("expected validation error")
+	}
+	msg := err.Error()
+	for _, want := range []string{"prompt: required", "steps: required field is missing (expects integer 1..50)", "size: required field is missing (expects one of 512, 1024)", "scale: must be <= 2"} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("error %q does not mention %q", msg, want)
+		}
+	}
+}
+
+func TestQuickInputs_PromptsQuickAndFillsDefaults(t *testing.T) {
+	items := []api.ToolParameterItem{
+		{ID: "prompt", Type: "textarea"},
+		{ID: "size", Type: "select", Quick: true, DefaultValue: "1024"},
+		{ID: "steps", Type: "number", DefaultValue: float64(30)},
+		{ID: "seed", Type: "number", Required: true},
+		{ID: "hd", Type: "checkbox", DefaultValue: "true"},
+	}
+	prompted, filled := quickInputs(items, map[string][]api.MultipartValue{})
+	ids := []string{}
+	for _, item := range prompted {
+		ids = append(ids, item.ID)
+	}
+	if strings.Join(ids, ",") != "prompt,size,seed" {
+		t.Fatalf("prompted = %v", ids)
+	}
+	if len(filled["steps"]) != 1 || filled["steps"][0].Value != "30" {
+		t.Fatalf("steps default not filled: %#v", filled)
+	}
+	if _, ok := filled["hd"]; ok {
+		t.Fatalf("checkbox default should be left to the server")
+	}
+}
+
+func TestInputsFromParameters_Shapes(t *testing.T) {
+	cases := []string{
+		`{"prompt":"a red fox","steps":30}`,
+		`"{\"prompt\":\"a red fox\",\"steps\":30}"`,
+		`[{"name":"prompt","value":"a red fox"},{"key":"steps","value":30}]`,
+	}
+	for _, raw := range cases {
+		got := inputsFromParameters([]byte(raw))
+		if promptFromInputs(got) != "a red fox" || len(got["steps"]) != 1 || got["steps"][0].Value != "30" {
+			t.Fatalf("inputsFromParameters(%s) = %#v", raw, got)
+		}
+	}
+	if got := inputsFromParameters(nil); len(got) != 0 {
+		t.Fatalf("expected empty inputs, got %#v", got)
+	}
+}
+
+func TestWithRunHints_AddsFieldsWithoutMutating(t *testing.T) {
+	inputs := map[string][]api.MultipartValue{"prompt": {{Value: "a cat"}}}
+	if got := withRunHints(inputs, " ", ""); len(got) != 1 {
+		t.Fatalf("empty hints should leave inputs alone: %v", got)
+	}
+	got := withRunHints(inputs, "A100", "high")
+	if v := got[gpuHintField]; len(v) != 1 || v[0].Value != "a100" {
+		t.Fatalf("gpu hint = %v", v)
+	}
+	if v := got[priorityHintField]; len(v) != 1 || v[0].Value != "high" {
+		t.Fatalf("priority hint = %v", v)
+	}
+	if _, ok := inputs[gpuHintField]; ok {
+		t.Fatal("input map was modified")
+	}
+}
+
+func TestParsePriority(t *testing.T) {
+	if p, err := parsePriority(" HIGH "); err != nil || p != "high" {
+		t.Fatalf("parsePriority = %q, %v", p, err)
+	}
+	if p, err := parsePriority(""); err != nil || p != "" {
+		t.Fatalf("empty priority = %q, %v", p, err)
+	}
+	if _, err := parsePriority("urgent"); err == nil {
+		t.Fatal("expected error for unknown priority")
+	}
+}
+
+func TestQueueInfo(t *testing.T) {
+	if got := queueInfo(map[string]interface{}{"status": "task_queue", "priority": "high", "queueposition": "3"}); got != "priority high, queue position 3" {
+		t.Fatalf("queueInfo = %q", got)
+	}
+	nested := map[string]interface{}{"type": "task_queue", "message": map[string]interface{}{"queueposition": float64(2)}}
+	if got := queueInfo(nested); got != "queue position 2" {
+		t.Fatalf("nested queueInfo = %q", got)
+	}
+	if got := queueInfo(map[string]interface{}{"status": "task_start"}); got != "" {
+		t.Fatalf("queueInfo without fields = %q", got)
+	}
+}
+
+func TestParamFiles_FlagsOverrideFiles(t *testing.T) {
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "params.json")
+	yamlPath := filepath.Join(dir, "params.yaml")
+	if err := os.WriteFile(jsonPath, []byte(`{"prompt": "from json", "steps": 20, "image": "url:https://cdn/a.png"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(yamlPath, []byte("steps: 30\nseed: 7\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p := paramFiles{jsonPaths: stringSlice{jsonPath}, yamlPaths: stringSlice{yamlPath}}
+	set, setFile, setURL, err := p.merge([]string{"prompt=from flag"}, []string{"image=./local.png"}, nil)
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if strings.Join(set, "|") != "seed=7|steps=30|prompt=from flag" {
+		t.Errorf("set = %q", set)
+	}
+	if len(setFile) != 1 || setFile[0] != "image=./local.png" || len(setURL) != 0 {
+		t.Errorf("setFile = %q, setURL = %q", setFile, setURL)
+	}
+
+	items := []api.ToolParameterItem{{ID: "prompt"}, {ID: "steps"}, {ID: "image"}}
+	err = checkParamFields(items, p.fields)
+	if err == nil || !strings.Contains(err.Error(), `"seed"`) || strings.Contains(err.Error(), `"steps"`) {
+		t.Fatalf("checkParamFields err = %v, want only seed reported", err)
+	}
+}
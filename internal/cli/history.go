@@ -0,0 +1,514 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/auth"
+	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/gitinfo"
+	"github.com/wiro-ai/wiro-cli/internal/history"
+	"github.com/wiro-ai/wiro-cli/internal/jsonout"
+	"github.com/wiro-ai/wiro-cli/internal/log"
+	"github.com/wiro-ai/wiro-cli/internal/model"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	"github.com/wiro-ai/wiro-cli/internal/storage"
+	"github.com/wiro-ai/wiro-cli/internal/task"
+)
+
+func historyCommand(ctx context.Context, app *App, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: wiro history <ls|show|rerun|sync> ...")
+	}
+	sub := strings.TrimSpace(args[0])
+	switch sub {
+	case "ls", "list":
+		return historyListCommand(args[1:])
+	case "sync":
+		return historySyncCommand(ctx, app, args[1:])
+	case "show":
+		return historyShowCommand(args[1:])
+	case "rerun":
+		return historyRerunCommand(ctx, app, args[1:])
+	case "--help", "-h", "help":
+		output.Println("Usage: wiro history <ls|show|rerun|sync> ...")
+		return nil
+	default:
+		return fmt.Errorf("unknown history command %q", sub)
+	}
+}
+
+func historyStore() (*history.Store, error) {
+	st, err := localStorage()
+	if err != nil {
+		return nil, err
+	}
+	return history.OpenStore(st), nil
+}
+
+// storageBackend is preferences.storage, set when the config loads.
+var storageBackend string
+
+var (
+	storageMu   sync.Mutex
+	openStorage = map[string]storage.Storage{}
+)
+
+// localStorage returns the configured storage backend under the config dir,
+// opening it once per process.
+func localStorage() (storage.Storage, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	storageMu.Lock()
+	defer storageMu.Unlock()
+	key := storageBackend + "\x00" + dir
+	if st, ok := openStorage[key]; ok {
+		return st, nil
+	}
+	st, err := storage.Open(storageBackend, dir)
+	if err != nil {
+		return nil, err
+	}
+	openStorage[key] = st
+	return st, nil
+}
+
+func closeStorage() {
+	storageMu.Lock()
+	defer storageMu.Unlock()
+	for key, st := range openStorage {
+		_ = st.Close()
+		delete(openStorage, key)
+	}
+}
+
+// recordRun adds a submitted run to history. History is best effort and never fails a run.
+func recordRun(e history.Entry) {
+	store, err := historyStore()
+	if err == nil {
+		_, err = store.Add(e)
+	}
+	if err != nil {
+		log.Verbosef("history: record run: %v", err)
+	}
+}
+
+// finishRun stores the outcome of a recorded run.
+func finishRun(taskID string, finalTask *api.Task, paths []string, runErr error) {
+	store, err := historyStore()
+	if err == nil {
+		err = store.UpdateTask(taskID, func(e *history.Entry) {
+			if finalTask != nil {
+				e.Status = finalTask.Status
+				if d, ok := history.TaskDuration(finalTask.StartTime, finalTask.EndTime); ok {
+					e.DurationSeconds = d.Seconds()
+				}
+			}
+			if len(paths) > 0 {
+				e.Outputs = paths
+			}
+			if runErr != nil {
+				e.Error = runErr.Error()
+			}
+		})
+	}
+	if err != nil {
+		log.Verbosef("history: update task %s: %v", taskID, err)
+	}
+}
+
+// presentSecrets lists, sorted, the secret fields that have a value in inputs.
+func presentSecrets(inputs map[string][]api.MultipartValue, secret map[string]bool) []string {
+	var out []string
+	for k := range secret {
+		if len(inputs[k]) > 0 {
+			out = append(out, k)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// inputsToFlags converts built inputs back into --set/--set-file values for replay.
+func inputsToFlags(inputs map[string][]api.MultipartValue) (set, setFile []string) {
+	keys := make([]string, 0, len(inputs))
+	for k := range inputs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range inputs[k] {
+			if v.FilePath != "" {
+				setFile = append(setFile, k+"="+v.FilePath)
+			} else {
+				set = append(set, k+"="+v.Value)
+			}
+		}
+	}
+	return set, setFile
+}
+
+func historyListCommand(args []string) error {
+	fs := flag.NewFlagSet("history ls", flag.ContinueOnError)
+	var asJSON bool
+	var limit int
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	fs.IntVar(&limit, "limit", 20, "Number of runs to show (0 = all)")
+	showSchema := jsonSchemaFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *showSchema {
+		return printJSONSchema("history ls")
+	}
+	store, err := historyStore()
+	if err != nil {
+		return err
+	}
+	entries, err := store.List()
+	if err != nil {
+		return err
+	}
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	if asJSON {
+		return jsonout.Print(entries)
+	}
+	if len(entries) == 0 {
+		output.Println("No runs recorded yet.")
+		return nil
+	}
+	t := output.NewTable("#", "TIME", "MODEL", "STATUS", "TASK", "SUMMARY")
+	t.StyleColumn("STATUS", output.Std().Status)
+	for _, e := range entries {
+		status := e.Status
+		if status == "" {
+			status = "submitted"
+		}
+		t.Row(strconv.Itoa(e.ID), e.CreatedAt, e.Owner+"/"+e.Model, status, e.TaskID, short(e.Summary, 50))
+	}
+	return t.Print()
+}
+
+func historyShowCommand(args []string) error {
+	fs := flag.NewFlagSet("history show", flag.ContinueOnError)
+	var asJSON bool
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	showSchema := jsonSchemaFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *showSchema {
+		return printJSONSchema("history show")
+	}
+	e, err := historyEntryArg(fs.Args(), "usage: wiro history show <n>")
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return jsonout.Print(e)
+	}
+	output.Printf("Run: %d\n", e.ID)
+	output.Printf("Time: %s\n", e.CreatedAt)
+	output.Printf("Model: %s/%s\n", e.Owner, e.Model)
+	if e.Project != "" {
+		output.Printf("Project: %s\n", e.Project)
+	}
+	output.Printf("Task ID: %s\n", e.TaskID)
+	if e.Status != "" {
+		output.Printf("Status: %s\n", e.Status)
+	}
+	if e.Source != "" {
+		output.Printf("Source: %s\n", e.Source)
+	}
+	if len(e.SecretFields) > 0 {
+		output.Printf("Secret fields (not recorded): %s\n", strings.Join(e.SecretFields, ", "))
+	}
+	if e.OutputDir != "" {
+		output.Printf("Output dir: %s\n", e.OutputDir)
+	}
+	if e.Git != nil {
+		output.Printf("Git: %s\n", e.Git)
+	}
+	if len(e.Set)+len(e.SetFile)+len(e.SetURL) > 0 {
+		output.Println("Inputs:")
+		for _, kv := range e.Set {
+			output.Printf("- %s\n", short(kv, 200))
+		}
+		for _, kv := range e.SetFile {
+			output.Printf("- %s (file)\n", kv)
+		}
+		for _, kv := range e.SetURL {
+			output.Printf("- %s (url)\n", kv)
+		}
+	}
+	if len(e.Outputs) > 0 {
+		output.Println("Outputs:")
+		for _, p := range e.Outputs {
+			output.Printf("- %s\n", p)
+		}
+	}
+	if e.Error != "" {
+		output.Printf("Error: %s\n", e.Error)
+	}
+	return nil
+}
+
+func historyRerunCommand(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("history rerun", flag.ContinueOnError)
+	var outputDir string
+	var asJSON, gitContext bool
+	var selection outputSelection
+	var setVals stringSlice
+	fs.StringVar(&outputDir, "output-dir", "", "Directory to save outputs (default: the original run's)")
+	var nameTemplate string
+	fs.StringVar(&nameTemplate, "name-template", "", "Output layout below --output-dir (default preferences.outputNameTemplate)")
+	fs.Var(&setVals, "set", "Replace a recorded field value (key=value), e.g. a masked secret. Repeatable")
+	selection.register(fs)
+	fs.BoolVar(&gitContext, "git-context", false, "Record the current git commit, branch, and dirty state")
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	showSchema := jsonSchemaFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *showSchema {
+		return printJSONSchema("history rerun")
+	}
+	e, err := historyEntryArg(fs.Args(), "usage: wiro history rerun <n> [--output-dir <path>]")
+	if err != nil {
+		return err
+	}
+	filter, err := selection.filter()
+	if err != nil {
+		return err
+	}
+	if _, err := parseKeyValuePairs(setVals); err != nil {
+		return err
+	}
+	set := append(overrideKeys(e.Set, setVals), setVals...)
+	setURL := overrideKeys(e.SetURL, setVals)
+	if set, setURL, err = fillSecrets(e, set, setURL); err != nil {
+		return err
+	}
+	if outputDir == "" {
+		outputDir = e.OutputDir
+	}
+	if outputDir == "" {
+		outputDir = app.OutputDirDefault()
+	}
+	timeout, err := app.Config.Preferences.WatchTimeoutDuration()
+	if err != nil {
+		return err
+	}
+	naming, err := outputNaming(app, nameTemplate)
+	if err != nil {
+		return err
+	}
+
+	var git *gitinfo.Info
+	if gitContext {
+		git = detectGitContext(ctx)
+	}
+
+	if !asJSON {
+		output.Infof("Re-running #%d: %s/%s\n", e.ID, e.Owner, e.Model)
+	}
+	result, err := executeRunJob(ctx, app, runJob{
+		Project:      e.Project,
+		Owner:        e.Owner,
+		Model:        e.Model,
+		Set:          set,
+		SetFile:      e.SetFile,
+		SetURL:       setURL,
+		OutputDir:    outputDir,
+		SecretFields: e.SecretFields,
+		Timeout:      timeout,
+		Outputs:      filter,
+		Dedupe:       app.Config.Preferences.DedupeOutputs,
+		Git:          git,
+		Naming:       naming,
+	}, runJobHooks{
+		OnSubmitted: func(resp api.RunResponse) {
+			if !asJSON {
+				output.Infof("Task started: taskid=%s\n", resp.TaskID)
+			}
+		},
+		OnEvent: func(ev task.WatchEvent) {
+			if !asJSON {
+				printWatchEvent(ev)
+			}
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return jsonout.Print(result.Task)
+	}
+	output.PrintTask(result.Task)
+	if len(result.Paths) > 0 {
+		output.Println("Downloaded files:")
+		for _, p := range result.Paths {
+			output.Printf("- %s\n", p)
+		}
+	}
+	return nil
+}
+
+// fillSecrets asks for each secret field of e still holding the mask, since
+// history never stores those values. Without a terminal they must come from --set.
+func fillSecrets(e history.Entry, set, setURL []string) ([]string, []string, error) {
+	var missing []string
+	for _, field := range e.SecretFields {
+		for _, kv := range append(append([]string{}, set...), setURL...) {
+			if key, val, _ := strings.Cut(kv, "="); strings.TrimSpace(key) == field && val == model.SecretMask {
+				missing = append(missing, field)
+				break
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return set, setURL, nil
+	}
+	if !isInteractiveSession() {
+		return nil, nil, fmt.Errorf("history entry #%d has secret field(s) %s; pass them with --set key=value", e.ID, strings.Join(missing, ", "))
+	}
+	var filled []string
+	for _, field := range missing {
+		val, err := promptPassword(fmt.Sprintf("%s (secret, hidden)", field))
+		if err != nil {
+			return nil, nil, err
+		}
+		if strings.TrimSpace(val) == "" {
+			return nil, nil, fmt.Errorf("secret field %q is empty", field)
+		}
+		filled = append(filled, field+"="+val)
+	}
+	// A prompted value may replace a masked URL input; --set carries either.
+	return append(overrideKeys(set, filled), filled...), overrideKeys(setURL, filled), nil
+}
+
+func historySyncCommand(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("history sync", flag.ContinueOnError)
+	var projectSelector string
+	var limit int
+	var asJSON bool
+	fs.StringVar(&projectSelector, "project", "", "Project name or API key for auth context")
+	fs.IntVar(&limit, "limit", 200, "Recent remote tasks to import")
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	showSchema := jsonSchemaFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *showSchema {
+		return printJSONSchema("history sync")
+	}
+	if fs.NArg() > 0 || limit < 1 {
+		return errors.New("usage: wiro history sync [--project <name|apikey>] [--limit N] [--json]")
+	}
+	headers, err := resolveRequestHeaders(app, projectSelector)
+	if err != nil {
+		return err
+	}
+	tasks, err := app.TaskSvc.List(ctx, limit, headers)
+	if err != nil {
+		return err
+	}
+	project := historyProject(activeProfile(app, projectSelector))
+	remote := make([]history.Entry, 0, len(tasks))
+	for _, t := range tasks {
+		if e, ok := remoteHistoryEntry(t, project); ok {
+			remote = append(remote, e)
+		}
+	}
+	store, err := historyStore()
+	if err != nil {
+		return err
+	}
+	added, updated, err := store.Merge(remote)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return jsonout.Print(historySyncResult{Fetched: len(tasks), Added: added, Updated: updated})
+	}
+	output.Printf("Fetched %d remote task(s): %d added to history, %d updated.\n", len(tasks), added, updated)
+	return nil
+}
+
+// remoteHistoryEntry records a task from the account's task list. Tasks whose
+// model the list does not name cannot be rerun and are skipped.
+func remoteHistoryEntry(t api.Task, project string) (history.Entry, bool) {
+	if t.ID == "" || t.SlugOwner == "" || t.SlugProject == "" {
+		return history.Entry{}, false
+	}
+	inputs := inputsFromParameters(t.ParametersRaw)
+	set, _ := inputsToFlags(inputs)
+	e := history.Entry{
+		Project:   project,
+		Owner:     t.SlugOwner,
+		Model:     t.SlugProject,
+		Set:       set,
+		Summary:   promptFromInputs(inputs),
+		TaskID:    t.ID,
+		TaskToken: t.SocketAccessToken,
+		Status:    t.Status,
+		Source:    history.SourceRemote,
+	}
+	if created, ok := history.TaskTime(t.CreateTime); ok {
+		e.CreatedAt = created.UTC().Format(time.RFC3339)
+	}
+	if d, ok := history.TaskDuration(t.StartTime, t.EndTime); ok {
+		e.DurationSeconds = d.Seconds()
+	}
+	return e, true
+}
+
+func historyEntryArg(rest []string, usage string) (history.Entry, error) {
+	if err := requireArgs(rest, 1, usage); err != nil {
+		return history.Entry{}, err
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(rest[0], "#"))
+	if err != nil || id < 1 {
+		return history.Entry{}, fmt.Errorf("invalid history entry %q (see `wiro history ls`)", rest[0])
+	}
+	store, err := historyStore()
+	if err != nil {
+		return history.Entry{}, err
+	}
+	return store.Get(id)
+}
+
+// historyProject names the project so a rerun resolves it again; env-pinned runs record none.
+func historyProject(profile *config.ProjectProfile) string {
+	if profile == nil || profile.Name == auth.EnvAPIKey {
+		return ""
+	}
+	if profile.Name != "" {
+		return profile.Name
+	}
+	return profile.APIKey
+}
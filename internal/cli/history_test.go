@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/history"
+	"github.com/wiro-ai/wiro-cli/internal/model"
+)
+
+func TestInputsToFlags_RoundTrip(t *testing.T) {
+	inputs := map[string][]api.MultipartValue{
+		"steps":  {{Value: "30"}},
+		"prompt": {{Value: "a=b cat"}},
+		"image":  {{FilePath: "/tmp/in.png"}},
+	}
+	set, setFile := inputsToFlags(inputs)
+	if want := []string{"prompt=a=b cat", "steps=30"}; !reflect.DeepEqual(set, want) {
+		t.Fatalf("set = %#v, want %#v", set, want)
+	}
+	if want := []string{"image=/tmp/in.png"}; !reflect.DeepEqual(setFile, want) {
+		t.Fatalf("setFile = %#v, want %#v", setFile, want)
+	}
+
+	text, err := parseKeyValuePairs(set)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	files, err := parseKeyValuePairs(setFile)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := mergeParamSources(text, files, nil); !reflect.DeepEqual(got, inputs) {
+		t.Fatalf("round trip = %#v", got)
+	}
+}
+
+func TestFillSecrets(t *testing.T) {
+	e := history.Entry{
+		ID:           4,
+		Set:          []string{"prompt=a fox", "apiKey=" + model.SecretMask},
+		SetURL:       []string{"webhook=" + model.SecretMask},
+		SecretFields: []string{"apiKey", "webhook"},
+	}
+	if got := presentSecrets(map[string][]api.MultipartValue{"apiKey": {{Value: "k"}}}, map[string]bool{"apiKey": true, "unused": true}); !reflect.DeepEqual(got, []string{"apiKey"}) {
+		t.Fatalf("presentSecrets = %v", got)
+	}
+
+	// Tests run without a terminal, so masked values must come from --set.
+	_, _, err := fillSecrets(e, e.Set, e.SetURL)
+	if err == nil || !strings.Contains(err.Error(), "apiKey, webhook") {
+		t.Fatalf("expected missing secrets error, got %v", err)
+	}
+
+	setVals := []string{"apiKey=k", "webhook=https://hook"}
+	set, setURL, err := fillSecrets(e, append(overrideKeys(e.Set, setVals), setVals...), overrideKeys(e.SetURL, setVals))
+	if err != nil {
+		t.Fatalf("fillSecrets: %v", err)
+	}
+	if want := []string{"prompt=a fox", "apiKey=k", "webhook=https://hook"}; !reflect.DeepEqual(set, want) || len(setURL) != 0 {
+		t.Fatalf("set = %v, setURL = %v", set, setURL)
+	}
+}
@@ -0,0 +1,156 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+)
+
+func initCommand(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	var force bool
+	fs.BoolVar(&force, "force", false, "Reconfigure even if credentials already exist")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if len(fs.Args()) != 0 {
+		return errors.New("usage: wiro init [--force]")
+	}
+	if !isInteractiveSession() {
+		return errors.New("wiro init needs an interactive terminal; use `wiro auth set` or `wiro auth login` in scripts")
+	}
+	if isConfigured(app) && !force {
+		output.Println("wiro is already set up. Run `wiro init --force` to reconfigure.")
+		return nil
+	}
+
+	output.Println("Wiro setup")
+	output.Println()
+	if err := initCredentials(ctx, app); err != nil {
+		return err
+	}
+	output.Println()
+	if err := initProject(ctx, app); err != nil {
+		return err
+	}
+	output.Println()
+	if err := initPreferences(app); err != nil {
+		return err
+	}
+	if err := app.SaveConfig(); err != nil {
+		return err
+	}
+	output.Println()
+	if err := initCompletion(); err != nil {
+		output.Printf("Skipped shell completion: %v\n", err)
+	}
+	output.Println()
+	output.Println("Setup complete. Try `wiro run` or `wiro model search`.")
+	return nil
+}
+
+func isConfigured(app *App) bool {
+	return len(app.Config.Projects) > 0 || app.AuthSvc.LoadBearerToken() != ""
+}
+
+func initCredentials(ctx context.Context, app *App) error {
+	idx, err := promptSelect("How do you want to authenticate?", []string{
+		"Sign in with email (bearer token)",
+		"Project API key + secret",
+	}, 0)
+	if err != nil {
+		return err
+	}
+	if idx == 1 {
+		return promptAPIKeySetup(app)
+	}
+
+	if err := authLoginCommand(ctx, app, nil); err != nil {
+		return err
+	}
+	if app.AuthSvc.LoadBearerToken() != "" {
+		return nil
+	}
+	token := strings.TrimSpace(app.State.PendingVerifyToken)
+	if token == "" {
+		return errors.New("login did not complete")
+	}
+	code, err := promptInput("Verification code", "")
+	if err != nil {
+		return err
+	}
+	authCode, err := promptInput("2FA code (blank if not enabled)", "")
+	if err != nil {
+		return err
+	}
+	verifyArgs := []string{token, strings.TrimSpace(code)}
+	if strings.TrimSpace(authCode) != "" {
+		verifyArgs = append([]string{"--authcode", strings.TrimSpace(authCode)}, verifyArgs...)
+	}
+	return authVerifyCommand(ctx, app, verifyArgs)
+}
+
+func initProject(ctx context.Context, app *App) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	projects, err := app.ProjectSvc.ListHybrid(timeoutCtx, app.Config)
+	if err != nil || len(projects) == 0 {
+		output.Println("No projects to choose from yet; you can pick one later with `wiro project use`.")
+		return nil
+	}
+	picked, err := selectProjectInteractive(projects)
+	if err != nil {
+		return err
+	}
+	app.Config.DefaultProject = picked.APIKey
+	app.Config.UpsertProject(config.ProjectProfile{
+		Name:           picked.Name,
+		APIKey:         picked.APIKey,
+		AuthMethodHint: picked.AuthMethod,
+	})
+	output.Printf("Default project: %s (%s)\n", picked.Name, picked.APIKey)
+	return nil
+}
+
+func initPreferences(app *App) error {
+	dir, err := promptInput("Default output directory", app.Config.Preferences.OutputDirDefault)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(dir) != "" {
+		app.Config.Preferences.OutputDirDefault = strings.TrimSpace(dir)
+	}
+	watch, err := promptConfirm("Watch task progress by default?", app.Config.Preferences.WatchDefault)
+	if err != nil {
+		return err
+	}
+	app.Config.Preferences.WatchDefault = watch
+	return nil
+}
+
+func initCompletion() error {
+	shell := detectShell()
+	if shell == "" {
+		return errors.New("could not detect your shell; run `wiro completion <bash|zsh|fish> --install` later")
+	}
+	install, err := promptConfirm(fmt.Sprintf("Install %s completion?", shell), true)
+	if err != nil || !install {
+		return err
+	}
+	path, err := installCompletion(shell)
+	if err != nil {
+		return err
+	}
+	output.Printf("Completion installed: %s\n", path)
+	output.Println(completionHint(shell))
+	return nil
+}
@@ -0,0 +1,313 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/gitinfo"
+	"github.com/wiro-ai/wiro-cli/internal/history"
+	"github.com/wiro-ai/wiro-cli/internal/log"
+	"github.com/wiro-ai/wiro-cli/internal/mediaprep"
+	"github.com/wiro-ai/wiro-cli/internal/model"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	projectsvc "github.com/wiro-ai/wiro-cli/internal/project"
+	"github.com/wiro-ai/wiro-cli/internal/task"
+)
+
+// runJob is a fully specified run that needs no prompts (queue, batch, replays).
+type runJob struct {
+	Project   string
+	Owner     string
+	Model     string
+	Set       []string
+	SetFile   []string
+	SetURL    []string
+	OutputDir string
+	// Timeout limits watching; zero means until the task finishes.
+	Timeout time.Duration
+	// TaskToken resumes watching an already submitted task instead of submitting again.
+	TaskToken string
+	// TaskID resumes by polling when the task was submitted without a socket token.
+	TaskID string
+	// Outputs limits which outputs are downloaded.
+	Outputs output.OutputFilter
+	// Dedupe reuses identical files already downloaded.
+	Dedupe bool
+	// NoBudgetCheck skips the project budget check before submission.
+	NoBudgetCheck bool
+	// MaxCost refuses submission when the estimate is higher; zero means no cap.
+	MaxCost float64
+	// CreditCheck warns when the estimate exceeds the remaining balance.
+	CreditCheck bool
+	// Git is recorded in the history entry and manifest when set.
+	Git *gitinfo.Info
+	// RequireGPU is the --require-gpu scheduling hint.
+	RequireGPU string
+	// Priority is the --priority scheduling hint.
+	Priority string
+	// SecretFields are masked in history and the manifest, with the fields
+	// the schema marks secret.
+	SecretFields []string
+	// Naming lays out the downloaded files; the zero value is one folder per task.
+	Naming output.NameTemplate
+	// Notify overrides the project's notify settings; nil uses them as they are.
+	Notify *notifyFlags
+	// Media preprocesses local file inputs before upload.
+	Media mediaprep.Options
+	// Reupload sends file inputs even when the upload cache has their content.
+	Reupload bool
+}
+
+// runJobHooks receives progress from executeRunJob; all fields are optional.
+type runJobHooks struct {
+	OnSubmitted func(resp api.RunResponse)
+	OnEvent     func(ev task.WatchEvent)
+}
+
+// runJobResult is the outcome of a completed job.
+type runJobResult struct {
+	Task  *api.Task
+	Paths []string
+}
+
+// executeRunJob submits (or resumes) a job, watches it to completion, and downloads outputs.
+func executeRunJob(ctx context.Context, app *App, job runJob, hooks runJobHooks) (runJobResult, error) {
+	profile := projectsvc.ResolveSelected(app.Config, job.Project)
+	if job.Project != "" && profile == nil {
+		return runJobResult{}, fmt.Errorf("project %q not found in local config", job.Project)
+	}
+	headerResult, err := app.AuthSvc.BuildHeaders(profile)
+	if err != nil {
+		return runJobResult{}, err
+	}
+	inputs, detail, err := jobInputs(ctx, app, job)
+	if err != nil {
+		return runJobResult{}, err
+	}
+
+	var items []api.ToolParameterItem
+	if detail != nil {
+		items = modelItems(detail, true)
+	}
+	secret := model.SecretFields(items, job.SecretFields)
+	recorded := model.MaskInputs(inputs, secret)
+
+	token, taskID := job.TaskToken, job.TaskID
+	var reuse *uploadReuse
+	if !job.submitted() {
+		estimate, hasEstimate := model.EstimatePrice(detail, inputs)
+		if err := checkMaxCost(estimate, hasEstimate, job.MaxCost); err != nil {
+			return runJobResult{}, err
+		}
+		if !job.NoBudgetCheck {
+			if err := checkBudget(profile, estimate); err != nil {
+				return runJobResult{}, err
+			}
+		}
+		if job.CreditCheck {
+			warnCreditShortfall(ctx, app, detail, inputs, headerResult.Headers)
+		}
+		uploads, cleanupUploads, err := prepareUploads(ctx, inputs, job.Media)
+		if err != nil {
+			return runJobResult{}, err
+		}
+		if !job.Reupload {
+			reuse = newUploadReuse(profile)
+			uploads = reuse.apply(ctx, app, uploads, headerResult.Headers)
+		}
+		submitCtx, cancelSubmit := context.WithTimeout(ctx, defaultSubmitTimeout)
+		resp, err := app.TaskSvc.Run(submitCtx, job.Owner, job.Model, withRunHints(uploads, job.RequireGPU, job.Priority), headerResult.Headers, task.RunOptions{})
+		cancelSubmit()
+		cleanupUploads()
+		if err != nil {
+			return runJobResult{}, err
+		}
+		if hooks.OnSubmitted != nil {
+			hooks.OnSubmitted(resp)
+		}
+		token, taskID = resp.SocketAccessToken, resp.TaskID
+		project := job.Project
+		if project == "" {
+			project = historyProject(profile)
+		}
+		recordRun(history.Entry{
+			Project:      project,
+			Owner:        job.Owner,
+			Model:        job.Model,
+			Set:          model.MaskFlags(job.Set, secret),
+			SetFile:      job.SetFile,
+			SetURL:       model.MaskFlags(job.SetURL, secret),
+			SecretFields: presentSecrets(inputs, secret),
+			Summary:      promptFromInputs(recorded),
+			TaskID:       resp.TaskID,
+			TaskToken:    resp.SocketAccessToken,
+			OutputDir:    app.ResolveOutputDir(job.OutputDir),
+			Git:          job.Git,
+			// Budgets count the estimate; zero when the model publishes no price.
+			EstimatedCost: estimate,
+		})
+	}
+
+	inflight.add(inflightTask{TaskID: taskID, Model: job.Owner + "/" + job.Model, OutputDir: app.ResolveOutputDir(job.OutputDir)})
+	watchCtx, cancel := watchContext(ctx, job.Timeout)
+	defer cancel()
+	stopWatchTimer := log.Time(log.PhaseWatch, "task "+taskID)
+	tr := openTranscript(app, job.OutputDir, taskID)
+	finalTask, err := app.TaskSvc.WatchTask(watchCtx, token, headerResult.Headers, task.WatchOptions{
+		TaskID: taskID,
+		OnEvent: func(ev task.WatchEvent) {
+			tr.watchEvent(ev)
+			if hooks.OnEvent != nil {
+				hooks.OnEvent(ev)
+			}
+		},
+	})
+	stopWatchTimer()
+	if err == nil && finalTask == nil {
+		err = errors.New("watch completed without final task")
+	}
+	tr.finish(finalTask, err)
+	if err != nil {
+		finishRun(taskID, nil, nil, err)
+		// After SIGTERM the task stays listed so the flush reports it as still running.
+		if ctx.Err() == nil {
+			inflight.done(taskID)
+		}
+		return runJobResult{}, err
+	}
+	checkHardware(finalTask, job.RequireGPU)
+	reuse.learn(finalTask)
+	paths, _, err := saveTaskOutputs(ctx, app, finalTask, job.OutputDir, runDownloadOptions(app, job.Outputs, job.Dedupe, job.Naming), job.Owner+"/"+job.Model, recorded, headerResult.Headers, job.Git)
+	finishRun(taskID, finalTask, paths, err)
+	inflight.done(taskID)
+	notifyFinished(job.Notify.apply(profile), finalTask, job.Owner+"/"+job.Model, paths)
+	if err != nil {
+		return runJobResult{Task: finalTask}, err
+	}
+	return runJobResult{Task: finalTask, Paths: paths}, nil
+}
+
+// submitted reports whether the job refers to a task that was already submitted.
+func (j runJob) submitted() bool {
+	return j.TaskToken != "" || j.TaskID != ""
+}
+
+// jobInputs builds and validates multipart inputs for a job against the model schema.
+// The model detail is nil for already submitted jobs.
+func jobInputs(ctx context.Context, app *App, job runJob) (map[string][]api.MultipartValue, *api.ToolDetail, error) {
+	setText, err := parseKeyValuePairs(job.Set)
+	if err != nil {
+		return nil, nil, err
+	}
+	setFile, err := parseKeyValuePairs(job.SetFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	setURL, err := parseKeyValuePairs(job.SetURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	preset := mergeParamSources(setText, setFile, setURL)
+	if job.submitted() {
+		// Already submitted; inputs only feed output naming and the manifest.
+		return preset, nil, nil
+	}
+	detail, err := app.ModelSvc.Detail(ctx, job.Owner, job.Model)
+	if err != nil {
+		return nil, nil, err
+	}
+	warnSchemaDrift(job.Owner+"/"+job.Model, detail)
+	inputs, err := checkNonInteractiveInputs(modelItems(detail, true), modelItems(detail, false), preset)
+	if err != nil {
+		return nil, nil, err
+	}
+	return inputs, detail, nil
+}
+
+// saveTaskOutputs downloads outputs and writes the manifest next to them.
+// dl carries per-call options (filter, overwrite); client and headers are filled in.
+// git, when set, is recorded in the manifest.
+func saveTaskOutputs(ctx context.Context, app *App, finalTask *api.Task, outputDir string, dl output.DownloadOptions, modelName string, inputs map[string][]api.MultipartValue, headers map[string]string, git *gitinfo.Info) ([]string, string, error) {
+	defer log.Time(log.PhaseDownload, "task "+finalTask.ID)()
+	outputDir = app.ResolveOutputDir(outputDir)
+	dl.Client = app.APIClient
+	dl.Headers = headers
+	var before output.DedupeStats
+	if dl.Dedupe != nil {
+		before = dl.Dedupe.Stats()
+	}
+	files, err := output.DownloadOutputFiles(ctx, finalTask, outputDir, promptFromInputs(inputs), dl)
+	paths := output.DownloadedPaths(files)
+	if dl.Dedupe != nil {
+		if saveErr := dl.Dedupe.Save(); saveErr != nil {
+			log.Verbosef("dedupe: save index: %v", saveErr)
+		}
+		printDedupeStats(before, dl.Dedupe.Stats())
+	}
+	if err != nil {
+		return paths, "", err
+	}
+	if len(paths) == 0 {
+		return paths, "", nil
+	}
+	inputsHash, err := output.HashInputs(inputs)
+	if err != nil {
+		return paths, "", err
+	}
+	manifest := output.BuildManifest(finalTask, modelName, inputsHash, files)
+	manifest.Seed = model.SeedValue(inputs)
+	manifest.Git = git
+	manifestPath, err := output.WriteManifest(dl.Naming.Dir(outputDir, finalTask), dl.Naming, manifest)
+	if err != nil {
+		return paths, "", err
+	}
+	return paths, manifestPath, nil
+}
+
+// runDownloadOptions builds per-run download options.
+func runDownloadOptions(app *App, filter output.OutputFilter, dedupe bool, naming output.NameTemplate) output.DownloadOptions {
+	dl := output.DownloadOptions{Filter: filter, Naming: naming}
+	if dedupe {
+		dl.Dedupe = app.DedupeIndex()
+	}
+	return dl
+}
+
+// outputNaming parses a --name-template value; empty uses
+// preferences.outputNameTemplate. {workspace} names the current workspace.
+func outputNaming(app *App, raw string) (output.NameTemplate, error) {
+	if strings.TrimSpace(raw) == "" {
+		raw = app.Config.Preferences.OutputNameTemplate
+	}
+	t, err := output.ParseNameTemplate(raw)
+	if err != nil {
+		return output.NameTemplate{}, err
+	}
+	return t.WithWorkspace(app.WorkspaceName()), nil
+}
+
+// detectGitContext captures the current checkout for --git-context. Outside a
+// repository it warns and returns nil so the run goes ahead without it.
+func detectGitContext(ctx context.Context) *gitinfo.Info {
+	info, err := gitinfo.Detect(ctx, "")
+	if err != nil {
+		output.Warnf("--git-context: %v; not recording code state", err)
+		return nil
+	}
+	return info
+}
+
+// printDedupeStats reports to stderr what dedupe saved between two snapshots.
+func printDedupeStats(before, after output.DedupeStats) {
+	reused := after.Reused - before.Reused
+	verified := after.Verified - before.Verified
+	if reused == 0 && verified == 0 {
+		return
+	}
+	output.Notef("Dedupe: reused %d file(s), saved %s; verified %d of %d download(s)\n",
+		reused, output.FormatBytes(after.BytesSaved-before.BytesSaved), verified, after.Downloaded-before.Downloaded)
+}
@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/history"
+	"github.com/wiro-ai/wiro-cli/internal/jsonout"
+	"github.com/wiro-ai/wiro-cli/internal/model"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	"github.com/wiro-ai/wiro-cli/internal/queue"
+)
+
+// jsonDocuments are the versioned --json outputs, by command. Their schemas
+// are pinned by testdata/jsonschema, so a struct change that alters one fails
+// the tests until the file is regenerated (go test ./internal/cli -update-schemas)
+// and, unless fields were only added, jsonout.Version is bumped.
+var jsonDocuments = map[string]jsonout.Document{
+	"run": {
+		Description: "The submission, then the final task when watching. With --sweep, one result per combination. With --detach, the daemon job.",
+		Values:      []interface{}{api.RunResponse{}, api.Task{}, []sweepResult{}, queue.Job{}},
+	},
+	"task detail":   {Description: "The task detail response.", Values: []interface{}{api.TaskDetailResponse{}}},
+	"task outputs":  {Description: "One entry per task output.", Values: []interface{}{[]output.OutputInfo{}}},
+	"task download": {Description: "The downloaded files and the manifest.", Values: []interface{}{taskDownloadResult{}}},
+	"task wait":     {Description: "The final task, and the downloaded files with --download.", Values: []interface{}{taskWaitResult{}}},
+	"task cancel":   {Description: "The cancel response.", Values: []interface{}{api.TaskDetailResponse{}}},
+	"task kill":     {Description: "The kill response.", Values: []interface{}{api.TaskDetailResponse{}}},
+	"model search":  {Description: "One entry per matching model.", Values: []interface{}{[]api.ToolSummary{}}},
+	"model categories": {
+		Description: "Category and tag counts; tags are null without --tags.",
+		Values:      []interface{}{model.Taxonomy{}},
+	},
+	"model inspect":     {Description: "The model with its parameters.", Values: []interface{}{api.ToolDetail{}}},
+	"model schema-diff": {Description: "Changes since the stored schema snapshot.", Values: []interface{}{schemaDiffResult{}}},
+	"project ls":        {Description: "One entry per project.", Values: []interface{}{[]api.Project{}}},
+	"project keys rotate": {
+		Description: "The rotation outcome; apiSecret only with --show-secret.",
+		Values:      []interface{}{keysRotateResult{}},
+	},
+	"history ls":    {Description: "Recorded runs, newest first.", Values: []interface{}{[]history.Entry{}}},
+	"history show":  {Description: "One recorded run.", Values: []interface{}{history.Entry{}}},
+	"history rerun": {Description: "The final task of the rerun.", Values: []interface{}{api.Task{}}},
+	"history sync":  {Description: "Counts of imported remote tasks.", Values: []interface{}{historySyncResult{}}},
+}
+
+// taskDownloadResult is the --json output of wiro task download.
+type taskDownloadResult struct {
+	TaskID   string   `json:"taskId"`
+	Paths    []string `json:"paths"`
+	Manifest string   `json:"manifest"`
+}
+
+// schemaDiffResult is the --json output of wiro model schema-diff.
+type schemaDiffResult struct {
+	Model string `json:"model"`
+	// Baseline is false when no snapshot existed and this run stored the first.
+	Baseline bool                 `json:"baseline"`
+	Changes  []model.SchemaChange `json:"changes"`
+}
+
+// historySyncResult is the --json output of wiro history sync.
+type historySyncResult struct {
+	Fetched int `json:"fetched"`
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+}
+
+// jsonSchemaFlag registers --json-schema, which prints the schema of the
+// command's --json output instead of running it.
+func jsonSchemaFlag(fs *flag.FlagSet) *bool {
+	return fs.Bool("json-schema", false, "Print the JSON Schema of --json output and exit")
+}
+
+func printJSONSchema(command string) error {
+	doc, ok := jsonDocuments[command]
+	if !ok {
+		return fmt.Errorf("no JSON schema for %q", command)
+	}
+	return output.PrintJSON(jsonout.SchemaFor("wiro "+command+" --json", doc))
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/jsonout"
+)
+
+var updateSchemas = flag.Bool("update-schemas", false, "rewrite testdata/jsonschema from the current types")
+
+// TestJSONSchemasFrozen fails when a struct change alters a --json document.
+// Regenerate with -update-schemas, and bump jsonout.Version unless the change
+// only adds fields.
+func TestJSONSchemasFrozen(t *testing.T) {
+	for command, doc := range jsonDocuments {
+		got, err := json.MarshalIndent(jsonout.SchemaFor("wiro "+command+" --json", doc), "", "  ")
+		if err != nil {
+			t.Fatalf("%s: %v", command, err)
+		}
+		got = append(got, '\n')
+		path := filepath.Join("testdata", "jsonschema", strings.ReplaceAll(command, " ", "-")+".json")
+		if *updateSchemas {
+			if err := os.WriteFile(path, got, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("%s: %v (run with -update-schemas to create it)", command, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("--json schema of %q changed; bump jsonout.Version unless fields were only added, then run go test ./internal/cli -update-schemas", command)
+		}
+	}
+}
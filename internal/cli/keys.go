@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/jsonout"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	projectsvc "github.com/wiro-ai/wiro-cli/internal/project"
+)
+
+const keysRotateUsage = "usage: wiro project keys rotate [--project name|apikey] [--revoke-old] [--json [--show-secret]]"
+
+// keysRotateResult is the --json output of wiro project keys rotate.
+type keysRotateResult struct {
+	Project string `json:"project"`
+	APIKey  string `json:"apiKey"`
+	// Verified is true once a request signed with the new secret succeeded.
+	Verified bool `json:"verified"`
+	// OldRevoked is true when the previous secret was revoked.
+	OldRevoked bool   `json:"oldRevoked"`
+	RotatedAt  string `json:"rotatedAt"`
+	// APISecret is the new secret, only with --show-secret.
+	APISecret string `json:"apiSecret,omitempty"`
+}
+
+func projectKeysCommand(ctx context.Context, app *App, args []string) error {
+	if len(args) == 0 {
+		return errors.New(keysRotateUsage)
+	}
+	switch sub := strings.TrimSpace(args[0]); sub {
+	case "rotate":
+		return projectKeysRotateCommand(ctx, app, args[1:])
+	case "--help", "-h", "help":
+		output.Println("Usage: " + strings.TrimPrefix(keysRotateUsage, "usage: "))
+		return nil
+	default:
+		return fmt.Errorf("unknown keys command %q", sub)
+	}
+}
+
+// projectKeysRotateCommand issues a new API secret, checks that it signs
+// requests, stores it in the keychain, and optionally revokes the old one.
+// A secret that fails the check is revoked again and never stored.
+func projectKeysRotateCommand(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("project keys rotate", flag.ContinueOnError)
+	var selector string
+	var revokeOld, asJSON, showSecret bool
+	fs.StringVar(&selector, "project", "", "Project name or API key (default: selected project)")
+	fs.BoolVar(&revokeOld, "revoke-old", false, "Revoke the previous secret without asking")
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	fs.BoolVar(&showSecret, "show-secret", false, "Include the new secret in --json output")
+	showSchema := jsonSchemaFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *showSchema {
+		return printJSONSchema("project keys rotate")
+	}
+	if len(fs.Args()) != 0 {
+		return errors.New(keysRotateUsage)
+	}
+	if showSecret && !asJSON {
+		return errors.New("--show-secret only applies to --json output")
+	}
+
+	profile := projectsvc.ResolveSelected(app.Config, selector)
+	if profile == nil {
+		if selector != "" {
+			return fmt.Errorf("project %q not found in local config", selector)
+		}
+		return errors.New("no default project selected; pass --project or run `wiro project use <name|apikey>`")
+	}
+	old := app.AuthSvc.ProjectSecret(profile.APIKey)
+	say := func(format string, a ...interface{}) {
+		if !asJSON {
+			output.Printf(format, a...)
+		}
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+	secret, err := app.ProjectSvc.NewSecret(timeoutCtx, profile)
+	if err != nil {
+		return err
+	}
+	say("Issued a new secret for %s.\n", profile.Name)
+	if err := app.ProjectSvc.VerifySecret(timeoutCtx, profile, secret); err != nil {
+		if revokeErr := app.ProjectSvc.RevokeSecret(timeoutCtx, profile, secret); revokeErr != nil {
+			output.Warnf("could not revoke the unverified secret: %v", revokeErr)
+		}
+		return fmt.Errorf("%w; the keychain still holds the old secret", err)
+	}
+	say("Verified a request signed with it.\n")
+	if err := app.AuthSvc.SaveProjectSecret(profile.APIKey, secret); err != nil {
+		return fmt.Errorf("store new secret: %w", err)
+	}
+	profile.AuthMethodHint = "signature"
+	if err := app.SaveConfig(); err != nil {
+		return err
+	}
+	say("Stored it in the keychain.\n")
+
+	result := keysRotateResult{
+		Project:   profile.Name,
+		APIKey:    profile.APIKey,
+		Verified:  true,
+		RotatedAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	if showSecret {
+		result.APISecret = secret
+	}
+	if old != "" && old != secret {
+		if !revokeOld && !asJSON && isInteractiveSession() {
+			if revokeOld, err = promptConfirm("Revoke the old secret now? Anything still using it stops working", false); err != nil {
+				return err
+			}
+		}
+		if revokeOld {
+			if err := app.ProjectSvc.RevokeSecret(timeoutCtx, profile, old); err != nil {
+				return fmt.Errorf("the new secret is in use, but revoking the old one failed: %w", err)
+			}
+			result.OldRevoked = true
+			say("Revoked the old secret.\n")
+		} else {
+			say("The old secret stays valid until it is revoked in the dashboard; update anything that still uses it.\n")
+		}
+	}
+	if asJSON {
+		return jsonout.Print(result)
+	}
+	return nil
+}
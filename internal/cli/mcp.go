@@ -0,0 +1,326 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/mcp"
+	"github.com/wiro-ai/wiro-cli/internal/model"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+)
+
+const mcpServeUsage = "usage: wiro mcp serve [--model owner/model ...] [--project <name|apikey>] [--output-dir <path>] [--timeout <duration>]"
+
+func mcpCommand(ctx context.Context, app *App, args []string) error {
+	if len(args) == 0 {
+		return errors.New(mcpServeUsage)
+	}
+	switch strings.TrimSpace(args[0]) {
+	case "serve":
+		return mcpServeCommand(ctx, app, args[1:])
+	case "--help", "-h", "help":
+		output.Println("Usage: " + strings.TrimPrefix(mcpServeUsage, "usage: "))
+		return nil
+	default:
+		return fmt.Errorf("unknown mcp command %q", args[0])
+	}
+}
+
+// mcpServeCommand serves Wiro models as MCP tools on stdin and stdout. Stdout
+// carries only protocol messages; everything else goes to stderr.
+func mcpServeCommand(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("mcp serve", flag.ContinueOnError)
+	var modelVals stringSlice
+	tools := &mcpTools{app: app}
+	var nameTemplate string
+	timeout, err := app.Config.Preferences.WatchTimeoutDuration()
+	if err != nil {
+		return err
+	}
+	fs.Var(&modelVals, "model", "Expose this model or alias as a tool. Repeatable (default: models you have run before)")
+	fs.StringVar(&tools.project, "project", "", "Project name or API key the tools run with")
+	fs.StringVar(&tools.outputDir, "output-dir", app.OutputDirDefault(), "Directory to save outputs")
+	fs.StringVar(&nameTemplate, "name-template", "", "Output layout below --output-dir (default preferences.outputNameTemplate)")
+	fs.DurationVar(&timeout, "timeout", timeout, "Stop watching a task after this long (0 = no limit)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if len(fs.Args()) != 0 {
+		return errors.New(mcpServeUsage)
+	}
+	tools.timeout = timeout
+	if tools.naming, err = outputNaming(app, nameTemplate); err != nil {
+		return err
+	}
+	if abs, err := filepath.Abs(app.ResolveOutputDir(tools.outputDir)); err == nil {
+		tools.outputDir = abs
+	}
+	for _, v := range modelVals {
+		owner, slug, defaults, err := expandModelArg(v)
+		if err != nil {
+			return err
+		}
+		tools.models = append(tools.models, mcpModel{owner: owner, slug: slug, defaults: defaults})
+	}
+	if len(tools.models) == 0 {
+		cache, err := schemaCache()
+		if err != nil {
+			return err
+		}
+		names, err := cache.Models()
+		if err != nil {
+			return err
+		}
+		for _, name := range names {
+			if owner, slug, err := splitModel(name); err == nil {
+				tools.models = append(tools.models, mcpModel{owner: owner, slug: slug})
+			}
+		}
+	}
+	if len(tools.models) == 0 {
+		return errors.New("no models to serve: pass --model owner/model, or run a model once so it is remembered")
+	}
+
+	output.Notef("wiro MCP server: %d model(s) on stdio\n", len(tools.models))
+	srv := &mcp.Server{Name: "wiro", Version: cliVersion(), Handler: tools}
+	return srv.Serve(ctx, os.Stdin, os.Stdout)
+}
+
+// mcpModel is a model served as a tool, with the --set defaults of its alias.
+type mcpModel struct {
+	owner, slug string
+	defaults    []string
+}
+
+func (m mcpModel) name() string { return m.owner + "/" + m.slug }
+
+// mcpTools runs Wiro models for tools/call.
+type mcpTools struct {
+	app       *App
+	project   string
+	outputDir string
+	naming    output.NameTemplate
+	timeout   time.Duration
+	models    []mcpModel
+
+	mu      sync.Mutex
+	details map[string]*api.ToolDetail
+}
+
+// mcpToolResult is the structured content of a tools/call result.
+type mcpToolResult struct {
+	TaskID  string   `json:"taskId"`
+	Status  string   `json:"status"`
+	Model   string   `json:"model"`
+	Outputs []string `json:"outputs"`
+	Paths   []string `json:"paths,omitempty"`
+}
+
+var toolNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// mcpToolName turns owner/model into a tool name, which MCP limits to
+// letters, digits, "_" and "-", at most 64 characters.
+func mcpToolName(owner, slug string) string {
+	name := toolNameUnsafe.ReplaceAllString(owner, "_") + "__" + toolNameUnsafe.ReplaceAllString(slug, "_")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// detail returns the model's parameters, fetched once per server.
+func (t *mcpTools) detail(ctx context.Context, m mcpModel) (*api.ToolDetail, error) {
+	t.mu.Lock()
+	d := t.details[m.name()]
+	t.mu.Unlock()
+	if d != nil {
+		return d, nil
+	}
+	d, err := t.app.ModelSvc.Detail(ctx, m.owner, m.slug)
+	if err != nil {
+		return nil, err
+	}
+	t.mu.Lock()
+	if t.details == nil {
+		t.details = map[string]*api.ToolDetail{}
+	}
+	t.details[m.name()] = d
+	t.mu.Unlock()
+	return d, nil
+}
+
+func (t *mcpTools) ListTools(ctx context.Context) ([]mcp.Tool, error) {
+	var tools []mcp.Tool
+	for _, m := range t.models {
+		d, err := t.detail(ctx, m)
+		if err != nil {
+			// One unreachable model should not hide the others.
+			output.Warnf("%s: %v; not served", m.name(), err)
+			continue
+		}
+		tools = append(tools, mcpToolFor(m, d))
+	}
+	return tools, nil
+}
+
+// mcpToolFor describes a model as a tool. Fields an alias presets are left
+// to the alias and not required.
+func mcpToolFor(m mcpModel, d *api.ToolDetail) mcp.Tool {
+	schema := model.InputSchema(m.name(), d)
+	delete(schema, "$schema")
+	if len(m.defaults) > 0 {
+		preset := map[string]bool{}
+		for _, kv := range m.defaults {
+			if k, _, ok := strings.Cut(kv, "="); ok {
+				preset[k] = true
+			}
+		}
+		if required, ok := schema["required"].([]string); ok {
+			kept := required[:0]
+			for _, id := range required {
+				if !preset[id] {
+					kept = append(kept, id)
+				}
+			}
+			schema["required"] = kept
+		}
+	}
+	desc := strings.TrimSpace(d.Description)
+	if desc != "" {
+		desc += "\n\n"
+	}
+	desc += fmt.Sprintf("Runs %s on Wiro and returns the output URLs. File inputs take an https URL or a local path.", m.name())
+	return mcp.Tool{Name: mcpToolName(m.owner, m.slug), Title: strings.TrimSpace(d.Title), Description: desc, InputSchema: schema}
+}
+
+func (t *mcpTools) CallTool(ctx context.Context, name string, args map[string]interface{}) (mcp.CallResult, error) {
+	var m *mcpModel
+	for i := range t.models {
+		if mcpToolName(t.models[i].owner, t.models[i].slug) == name {
+			m = &t.models[i]
+			break
+		}
+	}
+	if m == nil {
+		return mcp.CallResult{}, fmt.Errorf("unknown tool %q", name)
+	}
+	d, err := t.detail(ctx, *m)
+	if err != nil {
+		return mcp.CallResult{}, err
+	}
+	set, setFile, setURL, err := mcpArguments(modelItems(d, true), args)
+	if err != nil {
+		return mcp.CallResult{}, err
+	}
+	output.Notef("mcp: running %s\n", m.name())
+	result, err := executeRunJob(ctx, t.app, runJob{
+		Project:   t.project,
+		Owner:     m.owner,
+		Model:     m.slug,
+		Set:       withAliasDefaults(m.defaults, set, setFile, setURL),
+		SetFile:   setFile,
+		SetURL:    setURL,
+		OutputDir: t.outputDir,
+		Timeout:   t.timeout,
+		Dedupe:    t.app.Config.Preferences.DedupeOutputs,
+		Naming:    t.naming,
+	}, runJobHooks{})
+	if err != nil && result.Task == nil {
+		return mcp.CallResult{}, err
+	}
+	res := mcpToolResult{TaskID: result.Task.ID, Status: result.Task.Status, Model: m.name(), Outputs: []string{}, Paths: result.Paths}
+	for _, o := range result.Task.Outputs {
+		res.Outputs = append(res.Outputs, o.URL)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Task %s finished with status %s.\n", res.TaskID, res.Status)
+	for _, u := range res.Outputs {
+		fmt.Fprintf(&b, "Output: %s\n", u)
+	}
+	for _, p := range res.Paths {
+		fmt.Fprintf(&b, "Saved: %s\n", p)
+	}
+	if err != nil {
+		fmt.Fprintf(&b, "Download failed: %v\n", err)
+	}
+	out := mcp.TextResult(strings.TrimSpace(b.String()), err != nil || res.Status != "task_postprocess_end")
+	out.StructuredContent = res
+	return out, nil
+}
+
+// mcpArguments converts tool arguments to --set, --set-file, and --set-url
+// values. File fields take a URL or a local path; lists repeat the field.
+func mcpArguments(items []api.ToolParameterItem, args map[string]interface{}) (set, setFile, setURL []string, err error) {
+	fileField := map[string]bool{}
+	for _, item := range items {
+		if strings.EqualFold(strings.TrimSpace(item.Type), "combinefileinput") {
+			fileField[item.ID] = true
+		}
+	}
+	keys := make([]string, 0, len(args))
+	for k := range args {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		values, err := argumentStrings(args[k])
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("argument %q: %w", k, err)
+		}
+		for _, v := range values {
+			switch {
+			case !fileField[k]:
+				set = append(set, k+"="+v)
+			case strings.HasPrefix(v, "https://") || strings.HasPrefix(v, "http://"):
+				setURL = append(setURL, k+"="+v)
+			default:
+				abs, err := filepath.Abs(v)
+				if err != nil {
+					return nil, nil, nil, fmt.Errorf("argument %q: %w", k, err)
+				}
+				setFile = append(setFile, k+"="+abs)
+			}
+		}
+	}
+	return set, setFile, setURL, nil
+}
+
+// argumentStrings flattens a JSON argument to CLI values.
+func argumentStrings(v interface{}) ([]string, error) {
+	switch x := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []string{x}, nil
+	case bool:
+		return []string{strconv.FormatBool(x)}, nil
+	case float64:
+		return []string{strconv.FormatFloat(x, 'f', -1, 64)}, nil
+	case []interface{}:
+		var out []string
+		for _, e := range x {
+			vals, err := argumentStrings(e)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, vals...)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unsupported value %v", v)
+	}
+}
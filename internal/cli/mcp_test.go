@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+func TestMCPToolName(t *testing.T) {
+	if got := mcpToolName("wiro", "flux.1-dev"); got != "wiro__flux_1-dev" {
+		t.Fatalf("mcpToolName = %q", got)
+	}
+}
+
+func TestMCPArguments(t *testing.T) {
+	items := []api.ToolParameterItem{{ID: "prompt", Type: "textarea"}, {ID: "image", Type: "combinefileinput"}}
+	set, setFile, setURL, err := mcpArguments(items, map[string]interface{}{
+		"prompt": "a fox",
+		"steps":  float64(30),
+		"hd":     true,
+		"image":  []interface{}{"https://example.com/a.png", "b.png"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	abs, _ := filepath.Abs("b.png")
+	if !reflect.DeepEqual(set, []string{"hd=true", "prompt=a fox", "steps=30"}) ||
+		!reflect.DeepEqual(setURL, []string{"image=https://example.com/a.png"}) ||
+		!reflect.DeepEqual(setFile, []string{"image=" + abs}) {
+		t.Fatalf("set=%v setFile=%v setURL=%v", set, setFile, setURL)
+	}
+	if _, _, _, err := mcpArguments(items, map[string]interface{}{"prompt": map[string]interface{}{}}); err == nil {
+		t.Fatal("an object argument should be rejected")
+	}
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/mediaprep"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+)
+
+// mediaFlags are the input preprocessing flags of wiro run.
+type mediaFlags struct {
+	opts mediaprep.Options
+}
+
+func (m *mediaFlags) register(fs *flag.FlagSet) {
+	fs.IntVar(&m.opts.MaxSide, "max-side", 0, "Scale image and video inputs down so neither side exceeds this many pixels")
+	fs.StringVar(&m.opts.Format, "format", "", "Re-encode image inputs as jpeg or png")
+	fs.BoolVar(&m.opts.StripEXIF, "strip-exif", false, "Remove EXIF and other metadata from image and video inputs")
+}
+
+func (m *mediaFlags) options() (mediaprep.Options, error) {
+	return m.opts.Normalize()
+}
+
+// prepareUploads runs the local file inputs through mediaprep and returns the
+// inputs to submit. Processed copies live in a temporary directory that
+// cleanup removes; inputs itself is left as the user gave it, for history.
+func prepareUploads(ctx context.Context, inputs map[string][]api.MultipartValue, o mediaprep.Options) (map[string][]api.MultipartValue, func(), error) {
+	noop := func() {}
+	if !o.Enabled() {
+		return inputs, noop, nil
+	}
+	tmp, err := os.MkdirTemp("", "wiro-prep-")
+	if err != nil {
+		return nil, noop, err
+	}
+	cleanup := func() { _ = os.RemoveAll(tmp) }
+	out := make(map[string][]api.MultipartValue, len(inputs))
+	for k, values := range inputs {
+		prepared := make([]api.MultipartValue, len(values))
+		for i, v := range values {
+			prepared[i] = v
+			if v.FilePath == "" {
+				continue
+			}
+			// Each file gets its own directory so equal base names cannot collide.
+			dir, err := os.MkdirTemp(tmp, "")
+			if err != nil {
+				cleanup()
+				return nil, noop, err
+			}
+			res, err := mediaprep.Prepare(ctx, v.FilePath, dir, o)
+			if err != nil {
+				cleanup()
+				return nil, noop, fmt.Errorf("prepare %s: %w", k, err)
+			}
+			if res.Note != "" {
+				output.Notef("%s: %s (%s)\n", k, res.Note, v.FilePath)
+			}
+			prepared[i].FilePath = res.Path
+		}
+		out[k] = prepared
+	}
+	return out, cleanup, nil
+}
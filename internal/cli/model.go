@@ -5,24 +5,38 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/jsonout"
+	"github.com/wiro-ai/wiro-cli/internal/log"
+	"github.com/wiro-ai/wiro-cli/internal/model"
 	"github.com/wiro-ai/wiro-cli/internal/output"
 )
 
 func modelCommand(ctx context.Context, app *App, args []string) error {
 	if len(args) == 0 {
-		return errors.New("usage: wiro model <search|inspect> ...")
+		return errors.New("usage: wiro model <search|categories|inspect|schema-diff|run-spec|fav> ...")
 	}
 	sub := strings.TrimSpace(args[0])
 	switch sub {
 	case "search":
 		return modelSearchCommand(ctx, app, args[1:])
+	case "categories":
+		return modelCategoriesCommand(ctx, app, args[1:])
 	case "inspect":
 		return modelInspectCommand(ctx, app, args[1:])
+	case "schema-diff":
+		return modelSchemaDiffCommand(ctx, app, args[1:])
+	case "run-spec":
+		return modelRunSpecCommand(ctx, app, args[1:])
+	case "fav", "favorites":
+		return modelFavCommand(ctx, app, args[1:])
 	case "--help", "-h", "help":
-		fmt.Println("Usage: wiro model <search|inspect> ...")
+		output.Println("Usage: wiro model <search|categories|inspect|schema-diff|run-spec|fav> ...")
 		return nil
 	default:
 		return fmt.Errorf("unknown model command %q", sub)
@@ -31,50 +45,115 @@ func modelCommand(ctx context.Context, app *App, args []string) error {
 
 func modelSearchCommand(ctx context.Context, app *App, args []string) error {
 	fs := flag.NewFlagSet("model search", flag.ContinueOnError)
-	var asJSON bool
-	var limit int
+	var asJSON, favorites bool
+	opts := model.ListOptions{}
 	fs.BoolVar(&asJSON, "json", false, "JSON output")
-	fs.IntVar(&limit, "limit", 40, "Result limit")
+	fs.BoolVar(&favorites, "favorites", false, "List matching favorites first (see wiro model fav)")
+	fs.IntVar(&opts.Limit, "limit", 40, "Result limit")
+	fs.StringVar(&opts.Category, "category", "", "Only models in this category (see wiro model categories)")
+	fs.StringVar(&opts.Tag, "tag", "", "Only models with this tag")
+	fs.StringVar(&opts.Owner, "owner", "", "Only models published by this owner")
+	showSchema := jsonSchemaFlag(fs)
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
 			return nil
 		}
 		return err
 	}
+	if *showSchema {
+		return printJSONSchema("model search")
+	}
 	rest := fs.Args()
-	query := ""
 	if len(rest) > 1 {
-		return errors.New("usage: wiro model search [query]")
+		return errors.New("usage: wiro model search [query] [--category <c>] [--tag <t>] [--owner <o>] [--favorites]")
 	}
 	if len(rest) == 1 {
-		query = rest[0]
+		opts.Query = rest[0]
 	}
 
 	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
-	tools, err := app.ModelSvc.List(timeoutCtx, query, limit)
+	tools, err := app.ModelSvc.List(timeoutCtx, opts)
 	if err != nil {
 		return err
 	}
+	if favorites {
+		favs, failed := favoriteSummaries(timeoutCtx, app, opts)
+		if len(failed) > 0 {
+			output.Warnf("could not load favorite(s) %s", strings.Join(failed, ", "))
+		}
+		tools = withFavoritesFirst(favs, tools)
+	}
 	if asJSON {
-		return output.PrintJSON(tools)
+		return jsonout.Print(tools)
 	}
 	output.PrintTools(tools)
 	return nil
 }
 
+func modelCategoriesCommand(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("model categories", flag.ContinueOnError)
+	var asJSON, withTags bool
+	var limit int
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	fs.BoolVar(&withTags, "tags", false, "Also list tags")
+	fs.IntVar(&limit, "limit", 500, "Number of models to scan")
+	showSchema := jsonSchemaFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *showSchema {
+		return printJSONSchema("model categories")
+	}
+	if fs.NArg() > 0 {
+		return errors.New("usage: wiro model categories [--tags] [--json]")
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	tax, err := app.ModelSvc.Taxonomy(timeoutCtx, limit)
+	if err != nil {
+		return err
+	}
+	if !withTags {
+		tax.Tags = nil
+	}
+	if asJSON {
+		return jsonout.Print(tax)
+	}
+	if len(tax.Categories) == 0 {
+		output.Println("No categories found.")
+	}
+	output.PrintTerms("Categories", tax.Categories)
+	if withTags {
+		output.PrintTerms("Tags", tax.Tags)
+	}
+	return nil
+}
+
 func modelInspectCommand(ctx context.Context, app *App, args []string) error {
 	fs := flag.NewFlagSet("model inspect", flag.ContinueOnError)
-	var asJSON bool
+	var asJSON, example bool
 	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	fs.BoolVar(&example, "example", false, "Print a ready-to-run wiro run command (with --json-schema: a JSON Schema of the inputs)")
+	showSchema := jsonSchemaFlag(fs)
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
 			return nil
 		}
 		return err
 	}
+	if *showSchema && !example {
+		return printJSONSchema("model inspect")
+	}
+	if example && asJSON {
+		return errors.New("--example cannot be combined with --json; use --example --json-schema for a machine-readable description of the inputs")
+	}
 	rest := fs.Args()
-	if err := requireArgs(rest, 1, "usage: wiro model inspect <owner/model>"); err != nil {
+	if err := requireArgs(rest, 1, "usage: wiro model inspect <owner/model> [--example [--json-schema]] [--json]"); err != nil {
 		return err
 	}
 	owner, slug, err := parseModelArg(rest[0])
@@ -88,9 +167,162 @@ func modelInspectCommand(ctx context.Context, app *App, args []string) error {
 	if err != nil {
 		return err
 	}
-	if asJSON {
-		return output.PrintJSON(detail)
+	switch {
+	case example && *showSchema:
+		return output.PrintJSON(model.InputSchema(owner+"/"+slug, detail))
+	case example:
+		return printModelExample(os.Stdout, rest[0], model.Example(detail))
+	case asJSON:
+		return jsonout.Print(detail)
 	}
 	output.PrintToolDetail(detail)
 	return nil
 }
+
+// printModelExample writes a copy-pasteable wiro run command for values, and
+// a note on stderr when placeholders need replacing first.
+func printModelExample(w io.Writer, modelArg string, values []model.ExampleValue) error {
+	args := []string{"wiro", "run", shellQuote(modelArg)}
+	placeholders := 0
+	for _, v := range values {
+		flagName := "--set"
+		switch {
+		case v.URL:
+			flagName = "--set-url"
+		case v.File:
+			flagName = "--set-file"
+		}
+		args = append(args, flagName, shellQuote(v.Field+"="+v.Value))
+		if v.Source == model.SourcePlaceholder {
+			placeholders++
+		}
+	}
+	if _, err := fmt.Fprintln(w, strings.Join(args, " ")); err != nil {
+		return err
+	}
+	if placeholders > 0 {
+		output.Notef("Replace the %d <placeholder> value(s) before running.\n", placeholders)
+	}
+	return nil
+}
+
+// shellQuote quotes s for POSIX shells when it contains anything but plain
+// word characters.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("@%+=:,./_-", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func modelSchemaDiffCommand(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("model schema-diff", flag.ContinueOnError)
+	var asJSON, update bool
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	fs.BoolVar(&update, "update", false, "Store the current schema as the new baseline")
+	showSchema := jsonSchemaFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *showSchema {
+		return printJSONSchema("model schema-diff")
+	}
+	rest := fs.Args()
+	if err := requireArgs(rest, 1, "usage: wiro model schema-diff <owner/model> [--update] [--json]"); err != nil {
+		return err
+	}
+	owner, slug, err := parseModelArg(rest[0])
+	if err != nil {
+		return err
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	detail, err := app.ModelSvc.Detail(timeoutCtx, owner, slug)
+	if err != nil {
+		return err
+	}
+	cache, err := schemaCache()
+	if err != nil {
+		return err
+	}
+	name := owner + "/" + slug
+	cur := model.SnapshotFromDetail(name, detail)
+	prev, err := cache.Load(name)
+	if err != nil {
+		return err
+	}
+	var changes []model.SchemaChange
+	if prev != nil {
+		changes = model.DiffSchemas(*prev, cur)
+	}
+	if update || prev == nil {
+		if err := cache.Save(cur); err != nil {
+			return err
+		}
+	}
+
+	if asJSON {
+		return jsonout.Print(schemaDiffResult{Model: name, Baseline: prev != nil, Changes: changes})
+	}
+	if prev == nil {
+		output.Printf("No cached schema for %s; saved the current one as the baseline.\n", name)
+		return nil
+	}
+	if len(changes) == 0 {
+		output.Printf("Schema for %s is unchanged since %s.\n", name, prev.FetchedAt)
+		return nil
+	}
+	output.Printf("Schema for %s changed since %s:\n", name, prev.FetchedAt)
+	printSchemaChanges(os.Stdout, changes)
+	if !update {
+		output.Println("Run with --update to accept these changes as the new baseline.")
+	}
+	return nil
+}
+
+func schemaCache() (*model.SchemaCache, error) {
+	st, err := localStorage()
+	if err != nil {
+		return nil, err
+	}
+	return model.OpenSchemaCache(st), nil
+}
+
+func printSchemaChanges(w io.Writer, changes []model.SchemaChange) {
+	for _, c := range changes {
+		mark := " "
+		if c.Breaking {
+			mark = "!"
+		}
+		fmt.Fprintf(w, "%s %-8s %s: %s\n", mark, c.Kind, c.Field, c.Detail)
+	}
+}
+
+// warnSchemaDrift compares the model schema with the one seen on the previous run,
+// prints a warning with the diff to stderr, and records the current schema.
+func warnSchemaDrift(name string, detail *api.ToolDetail) {
+	cache, err := schemaCache()
+	if err != nil {
+		return
+	}
+	cur := model.SnapshotFromDetail(name, detail)
+	prev, err := cache.Load(name)
+	if err != nil {
+		log.Verbosef("schema cache: %v", err)
+	}
+	if prev != nil {
+		if changes := model.DiffSchemas(*prev, cur); len(changes) > 0 {
+			output.Warnf("parameter schema for %s changed since %s (! = may break existing invocations):", name, prev.FetchedAt)
+			printSchemaChanges(os.Stderr, changes)
+		}
+	}
+	if err := cache.Save(cur); err != nil {
+		log.Verbosef("schema cache: %v", err)
+	}
+}
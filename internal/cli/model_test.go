@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/model"
+)
+
+func TestPrintModelExample(t *testing.T) {
+	var b strings.Builder
+	err := printModelExample(&b, "wiro/flux", []model.ExampleValue{
+		{Field: "prompt", Value: "it's a fox", Source: model.SourceSample},
+		{Field: "image", Value: "https://cdn.example/a.png", URL: true, Source: model.SourceSample},
+		{Field: "steps", Value: "30", Source: model.SourceDefault},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `wiro run wiro/flux --set 'prompt=it'\''s a fox' --set-url image=https://cdn.example/a.png --set steps=30` + "\n"
+	if b.String() != want {
+		t.Fatalf("got  %q\nwant %q", b.String(), want)
+	}
+}
+
+func TestFavoritesFirst(t *testing.T) {
+	flux := api.ToolSummary{SlugOwner: "wiro", SlugProject: "flux", Title: "Flux", Categories: []string{"image"}}
+	whisper := api.ToolSummary{SlugOwner: "wiro", SlugProject: "whisper", Title: "Whisper", Categories: []string{"audio"}}
+	sdxl := api.ToolSummary{SlugOwner: "stability", SlugProject: "sdxl"}
+
+	if !favoriteMatches(flux, model.ListOptions{Query: "FLU", Category: "Image"}) {
+		t.Fatal("flux should match query and category")
+	}
+	if favoriteMatches(whisper, model.ListOptions{Category: "image"}) || favoriteMatches(flux, model.ListOptions{Owner: "stability"}) {
+		t.Fatal("category and owner filters should exclude")
+	}
+
+	got := withFavoritesFirst([]api.ToolSummary{whisper}, []api.ToolSummary{flux, sdxl, whisper})
+	var names []string
+	for _, m := range got {
+		names = append(names, m.SlugProject)
+	}
+	if strings.Join(names, ",") != "whisper,flux,sdxl" {
+		t.Fatalf("order = %v", names)
+	}
+}
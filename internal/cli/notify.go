@@ -0,0 +1,164 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/notify"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	projectsvc "github.com/wiro-ai/wiro-cli/internal/project"
+)
+
+// notifyFlags are --notify, --bell, and --notify-cmd. Flags given on the
+// command line override the project's notify settings one by one, so
+// --notify=false silences a project that enables desktop notifications.
+type notifyFlags struct {
+	desktop bool
+	bell    bool
+	command string
+	set     map[string]bool
+}
+
+func (f *notifyFlags) register(fs *flag.FlagSet) {
+	fs.BoolVar(&f.desktop, "notify", false, "Show a desktop notification when the task finishes")
+	fs.BoolVar(&f.bell, "bell", false, "Ring the terminal bell when the task finishes")
+	fs.StringVar(&f.command, "notify-cmd", "", "Run this shell command with the final task as JSON on stdin when the task finishes")
+}
+
+// parsed records which flags were given; call it after fs.Parse.
+func (f *notifyFlags) parsed(fs *flag.FlagSet) {
+	f.set = map[string]bool{}
+	fs.Visit(func(fl *flag.Flag) { f.set[fl.Name] = true })
+}
+
+// given reports whether any notify flag was on the command line.
+func (f *notifyFlags) given() bool {
+	return f.set["notify"] || f.set["bell"] || f.set["notify-cmd"]
+}
+
+// apply returns the profile's notify settings with the given flags applied.
+// A nil f uses the profile's settings as they are.
+func (f *notifyFlags) apply(profile *config.ProjectProfile) config.Notify {
+	var n config.Notify
+	if profile != nil && profile.Notify != nil {
+		n = *profile.Notify
+	}
+	if f == nil {
+		return n
+	}
+	if f.set["notify"] {
+		n.Desktop = f.desktop
+	}
+	if f.set["bell"] {
+		n.Bell = f.bell
+	}
+	if f.set["notify-cmd"] {
+		n.Command = f.command
+	}
+	return n
+}
+
+// notifyFinished reports a finished task; failures are warnings, since the
+// task itself is done.
+func notifyFinished(n config.Notify, t *api.Task, model string, paths []string) {
+	if n.IsZero() || t == nil {
+		return
+	}
+	if err := notify.Send(n, notify.Event{Task: t, Model: model, Paths: paths}); err != nil {
+		output.Warnf("%v", err)
+	}
+}
+
+func projectNotifyCommand(app *App, args []string) error {
+	fs := flag.NewFlagSet("project notify", flag.ContinueOnError)
+	var desktop, bell, clear, test, asJSON bool
+	var command string
+	fs.BoolVar(&desktop, "desktop", false, "Show a desktop notification when a watched task finishes")
+	fs.BoolVar(&bell, "bell", false, "Ring the terminal bell when a watched task finishes")
+	fs.StringVar(&command, "cmd", "", "Shell command run with the final task as JSON on stdin (empty to remove)")
+	fs.BoolVar(&clear, "clear", false, "Turn off all notifications")
+	fs.BoolVar(&test, "test", false, "Send a test notification with the saved settings")
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	usage := "usage: wiro project notify [name|apikey] [--desktop[=false]] [--bell[=false]] [--cmd <command>] [--clear] [--test]"
+
+	var selector string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		selector, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() > 0 {
+		return errors.New(usage)
+	}
+	profile := projectsvc.ResolveSelected(app.Config, selector)
+	if profile == nil {
+		if selector != "" {
+			return fmt.Errorf("project %q not found in local config", selector)
+		}
+		return errors.New("no default project selected; pass a project name or run `wiro project use <name|apikey>`")
+	}
+
+	changed := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name != "json" && f.Name != "test" {
+			changed = true
+		}
+	})
+	if changed {
+		n := config.Notify{}
+		if profile.Notify != nil && !clear {
+			n = *profile.Notify
+		}
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "desktop":
+				n.Desktop = desktop
+			case "bell":
+				n.Bell = bell
+			case "cmd":
+				n.Command = strings.TrimSpace(command)
+			}
+		})
+		profile.Notify = &n
+		if n.IsZero() {
+			profile.Notify = nil
+		}
+		if err := app.SaveConfig(); err != nil {
+			return err
+		}
+	}
+	if test {
+		if profile.Notify.IsZero() {
+			return errors.New("no notifications are enabled for this project")
+		}
+		sample := &api.Task{ID: "0", Status: "task_postprocess_end"}
+		if err := notify.Send(*profile.Notify, notify.Event{Task: sample, Model: "wiro/test"}); err != nil {
+			return err
+		}
+	}
+
+	if asJSON {
+		return output.PrintJSON(map[string]interface{}{
+			"project": historyProject(profile),
+			"notify":  profile.Notify,
+		})
+	}
+	output.Printf("Project: %s\n", displayProject(profile))
+	if profile.Notify.IsZero() {
+		output.Println("Notifications: none")
+		return nil
+	}
+	n := profile.Notify
+	output.Printf("Desktop: %v\n", n.Desktop)
+	output.Printf("Bell: %v\n", n.Bell)
+	output.Printf("Command: %s\n", output.Dash(n.Command))
+	return nil
+}
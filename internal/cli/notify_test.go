@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/config"
+)
+
+func TestNotifyFlagsApply(t *testing.T) {
+	profile := &config.ProjectProfile{Notify: &config.Notify{Desktop: true, Command: "say done"}}
+
+	var unset *notifyFlags
+	if got := unset.apply(profile); got != *profile.Notify {
+		t.Fatalf("nil flags = %+v, want the profile settings", got)
+	}
+
+	var f notifyFlags
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	f.register(fs)
+	if err := fs.Parse([]string{"--notify=false", "--bell"}); err != nil {
+		t.Fatal(err)
+	}
+	f.parsed(fs)
+	want := config.Notify{Bell: true, Command: "say done"}
+	if got := f.apply(profile); got != want {
+		t.Fatalf("apply = %+v, want %+v", got, want)
+	}
+	if got := f.apply(nil); got != (config.Notify{Bell: true}) {
+		t.Fatalf("apply without profile = %+v", got)
+	}
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/history"
+	"github.com/wiro-ai/wiro-cli/internal/task"
+)
+
+// phaseSummary formats phase times as "queue 4.0s, run 1m30s", or "" when
+// there are none.
+func phaseSummary(phases []task.PhaseTime) string {
+	parts := make([]string, 0, len(phases))
+	for _, p := range phases {
+		parts = append(parts, string(p.Phase)+" "+formatElapsed(p.Duration))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// transcriptTimeline replays a stored transcript's status events.
+func transcriptTimeline(events []transcriptEvent) *task.Timeline {
+	tl := task.NewTimeline()
+	for _, ev := range events {
+		status := ev.Type
+		if ev.Source == "system" && ev.Type == "task" {
+			var t api.Task
+			if json.Unmarshal(ev.Payload, &t) != nil {
+				continue
+			}
+			status = t.Status
+		}
+		tl.Observe(status, ev.At)
+	}
+	return tl
+}
+
+// taskPhases breaks down where a finished task's time went. The transcript
+// kept when the task was watched below outputDir has every transition; without
+// one, the API's create, start, and end times give the queue and run phases.
+func taskPhases(t *api.Task, outputDir string) []task.PhaseTime {
+	if f, err := os.Open(transcriptPath(outputDir, t.ID)); err == nil {
+		events, _ := readTranscript(f)
+		f.Close()
+		if len(events) > 0 {
+			if phases := transcriptTimeline(events).Phases(events[len(events)-1].At); len(phases) > 0 {
+				return phases
+			}
+		}
+	}
+	created, okCreated := history.TaskTime(t.CreateTime)
+	started, okStarted := history.TaskTime(t.StartTime)
+	if !okStarted {
+		return nil
+	}
+	var phases []task.PhaseTime
+	if okCreated && !started.Before(created) {
+		phases = append(phases, task.PhaseTime{Phase: task.PhaseQueue, Duration: started.Sub(created)})
+	}
+	if d, ok := history.TaskDuration(t.StartTime, t.EndTime); ok {
+		phases = append(phases, task.PhaseTime{Phase: task.PhaseRun, Duration: d})
+	}
+	return phases
+}
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/model"
+	"github.com/wiro-ai/wiro-cli/internal/term"
+)
+
+// errPickerCancelled is returned when a picker is left without a choice.
+var errPickerCancelled = errors.New("selection cancelled")
+
+// modelPageSize is how many models the browser fetches per /Tool/List page.
+const modelPageSize = 50
+
+// maxPagesPerKey bounds how many pages load between two key presses, so a
+// filter that matches little does not stall input while the catalog loads.
+const maxPagesPerKey = 3
+
+// runPicker shows p full-screen until an item is chosen, returning its index
+// in the order added. more loads the next page of items and reports whether
+// further pages exist; it runs whenever the picker runs short of matches, and
+// items it returns along with an error are still shown.
+// Keys are read on this goroutine, so nothing is left reading stdin after it
+// returns and later prompts see all input.
+func runPicker(ctx context.Context, p *term.Picker, more func(context.Context) ([]term.PickerItem, bool, error)) (int, error) {
+	restore, err := term.MakeRaw()
+	if err != nil {
+		return 0, err
+	}
+	defer restore()
+	screen := term.NewScreen(os.Stdout)
+	screen.Enter()
+	defer screen.Exit()
+
+	reader := bufio.NewReader(os.Stdin)
+	p.More = true
+	for {
+		width, height := term.Size()
+		for pages := 0; pages < maxPagesPerKey && p.WantsMore(height); pages++ {
+			p.Loading = true
+			screen.Draw(p.Render(width, height), width, height)
+			items, hasMore, err := more(ctx)
+			p.Loading = false
+			p.Add(items...)
+			if err != nil {
+				p.More = false
+				p.Status = "loading failed: " + err.Error()
+				break
+			}
+			p.More = hasMore
+		}
+		screen.Draw(p.Render(width, height), width, height)
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		k, err := term.ReadKey(reader)
+		if err != nil {
+			return 0, err
+		}
+		switch p.HandleKey(k, height) {
+		case term.PickerChosen:
+			idx, _ := p.Selected()
+			return idx, nil
+		case term.PickerCancelled:
+			return 0, errPickerCancelled
+		}
+	}
+}
+
+// browseModels lets the user pick from the whole public catalog: favorites
+// come first, typing fuzzy-filters the loaded models, categories narrow them,
+// and further /Tool/List pages load as the list runs short.
+func browseModels(ctx context.Context, app *App) (*api.ToolSummary, error) {
+	var models []api.ToolSummary
+	seen := map[string]bool{}
+	start := 0
+	p := term.NewPicker("Select model")
+	p.Status = "Filter by name; descriptions match exact text."
+	add := func(page []api.ToolSummary, pinned bool) []term.PickerItem {
+		items := make([]term.PickerItem, 0, len(page))
+		for _, m := range page {
+			name := m.SlugOwner + "/" + m.SlugProject
+			if seen[strings.ToLower(name)] {
+				continue
+			}
+			seen[strings.ToLower(name)] = true
+			models = append(models, m)
+			items = append(items, term.PickerItem{
+				Label:  name,
+				Detail: m.Description,
+				Facets: model.Terms(m.Categories),
+				Pinned: pinned,
+			})
+		}
+		return items
+	}
+	more := func(ctx context.Context) ([]term.PickerItem, bool, error) {
+		var items []term.PickerItem
+		if start == 0 && len(app.Config.Favorites) > 0 {
+			favs, failed := favoriteSummaries(ctx, app, model.ListOptions{})
+			if len(failed) > 0 {
+				p.Status = "Could not load favorite(s) " + strings.Join(failed, ", ")
+			}
+			items = add(favs, true)
+		}
+		page, total, err := app.ModelSvc.ListPage(ctx, model.ListOptions{Start: start, Limit: modelPageSize})
+		if err != nil {
+			return items, false, err
+		}
+		start += len(page)
+		p.Total = total
+		items = append(items, add(page, false)...)
+		hasMore := len(page) == modelPageSize && (total == 0 || start < total)
+		return items, hasMore, nil
+	}
+	idx, err := runPicker(ctx, p, more)
+	if err != nil {
+		return nil, err
+	}
+	picked := models[idx]
+	return &picked, nil
+}
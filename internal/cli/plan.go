@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/model"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	"github.com/wiro-ai/wiro-cli/internal/preset"
+	"github.com/wiro-ai/wiro-cli/internal/runspec"
+)
+
+// errRunDeclined is returned when the user answers no at the review step.
+var errRunDeclined = errors.New("run cancelled")
+
+// runPlan is what a prompted run is about to submit.
+type runPlan struct {
+	Model   string
+	Project string
+	Auth    string
+	Detail  *api.ToolDetail
+	Inputs  map[string][]api.MultipartValue
+	// Secret fields are shown as hidden and left out of saved presets.
+	Secret map[string]bool
+}
+
+// planRow is one field of the review table.
+type planRow struct {
+	Field string
+	Value string
+	// Size is the local file bytes the field uploads.
+	Size int64
+}
+
+// rows lists the inputs in schema order, then any fields the schema lacks.
+func (p *runPlan) rows() []planRow {
+	seen := map[string]bool{}
+	var ids []string
+	for _, item := range modelItems(p.Detail, true) {
+		if _, ok := p.Inputs[item.ID]; ok && !seen[item.ID] {
+			seen[item.ID] = true
+			ids = append(ids, item.ID)
+		}
+	}
+	var extra []string
+	for id := range p.Inputs {
+		if !seen[id] {
+			extra = append(extra, id)
+		}
+	}
+	sort.Strings(extra)
+
+	rows := make([]planRow, 0, len(p.Inputs))
+	for _, id := range append(ids, extra...) {
+		row := planRow{Field: id}
+		values := make([]string, 0, len(p.Inputs[id]))
+		for _, v := range p.Inputs[id] {
+			switch {
+			case p.Secret[id]:
+				values = append(values, "(hidden)")
+			case v.FilePath != "":
+				values = append(values, v.FilePath)
+				if st, err := os.Stat(v.FilePath); err == nil {
+					row.Size += st.Size()
+				}
+			default:
+				values = append(values, v.Value)
+			}
+		}
+		row.Value = strings.Join(values, ", ")
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+func (p *runPlan) render(w io.Writer) error {
+	rows := p.rows()
+	t := output.NewTable("FIELD", "VALUE", "UPLOAD")
+	var upload int64
+	files := 0
+	for _, r := range rows {
+		size := ""
+		if r.Size > 0 {
+			size = output.FormatBytes(r.Size)
+			upload += r.Size
+			files++
+		}
+		t.Row(r.Field, r.Value, size)
+	}
+	fmt.Fprintf(w, "Model: %s\n", p.Model)
+	fmt.Fprintf(w, "Project: %s\n", p.Project)
+	fmt.Fprintf(w, "Auth: %s\n", p.Auth)
+	if err := t.Render(w); err != nil {
+		return err
+	}
+	if files > 0 {
+		fmt.Fprintf(w, "Upload: %s in %d field(s)\n", output.FormatBytes(upload), files)
+	}
+	if estimate, ok := model.EstimatePrice(p.Detail, p.Inputs); ok {
+		fmt.Fprintf(w, "Estimated cost: %s\n", formatCredits(estimate, ""))
+	}
+	return nil
+}
+
+// reviewRun shows the plan and loops until the user confirms or declines,
+// letting them re-enter fields or save the inputs as a preset on the way.
+func reviewRun(p *runPlan) error {
+	for {
+		output.Println()
+		if err := p.render(os.Stdout); err != nil {
+			return err
+		}
+		ans, err := promptInput("Submit? [y]es, [e]dit <field>, [s]ave <preset>, [n]o", "y")
+		if err != nil {
+			return err
+		}
+		verb, arg, _ := strings.Cut(strings.TrimSpace(ans), " ")
+		arg = strings.TrimSpace(arg)
+		switch strings.ToLower(verb) {
+		case "y", "yes":
+			return nil
+		case "n", "no", "q", "quit":
+			return errRunDeclined
+		case "e", "edit":
+			if err := p.edit(arg); err != nil {
+				output.Warnf("%v", err)
+			}
+		case "s", "save":
+			if err := p.save(arg); err != nil {
+				output.Warnf("%v", err)
+			}
+		default:
+			output.Warnf("unknown choice %q", ans)
+		}
+	}
+}
+
+// edit prompts for field again; a field left empty is removed from the run.
+func (p *runPlan) edit(field string) error {
+	if field == "" {
+		var err error
+		if field, err = promptInput("Field", ""); err != nil {
+			return err
+		}
+	}
+	item, ok := findItem(modelItems(p.Detail, true), field)
+	if !ok {
+		return fmt.Errorf("model has no field %q", field)
+	}
+	vals, err := promptField(item)
+	if err != nil {
+		return err
+	}
+	if len(vals) == 0 {
+		delete(p.Inputs, item.ID)
+		return nil
+	}
+	checked, err := model.ValidateValues([]api.ToolParameterItem{item}, map[string][]api.MultipartValue{item.ID: vals})
+	if err != nil {
+		return err
+	}
+	p.Inputs[item.ID] = checked[item.ID]
+	return nil
+}
+
+// findItem matches field against item ids, then labels, ignoring case.
+func findItem(items []api.ToolParameterItem, field string) (api.ToolParameterItem, bool) {
+	for _, item := range items {
+		if strings.EqualFold(item.ID, field) {
+			return item, true
+		}
+	}
+	for _, item := range items {
+		if strings.EqualFold(strings.TrimSpace(item.Label), field) {
+			return item, true
+		}
+	}
+	return api.ToolParameterItem{}, false
+}
+
+// save installs the inputs as a local preset for `wiro run --preset`. Secret
+// fields are left out so they are never written to disk.
+func (p *runPlan) save(name string) error {
+	if name == "" {
+		var err error
+		if name, err = promptInput("Preset name", ""); err != nil {
+			return err
+		}
+	}
+	dir, err := preset.Dir()
+	if err != nil {
+		return err
+	}
+	inputs := make(map[string][]api.MultipartValue, len(p.Inputs))
+	for k, v := range p.Inputs {
+		if !p.Secret[k] {
+			inputs[k] = v
+		}
+	}
+	spec, err := runspec.Build(p.Model, inputs, fileFields(modelItems(p.Detail, true)), dir, cliVersion())
+	if err != nil {
+		return err
+	}
+	path, err := preset.Save(dir, name, spec, false)
+	if errors.Is(err, preset.ErrExists) {
+		replace, askErr := promptConfirm(fmt.Sprintf("Preset %s exists. Replace it?", name), false)
+		if askErr != nil || !replace {
+			return askErr
+		}
+		path, err = preset.Save(dir, name, spec, true)
+	}
+	if err != nil {
+		return err
+	}
+	output.Printf("Saved preset %s (%s); run it with: wiro run --preset %s\n", name, path, name)
+	return nil
+}
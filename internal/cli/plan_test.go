@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+func TestRunPlanRows(t *testing.T) {
+	dir := t.TempDir()
+	img := filepath.Join(dir, "in.png")
+	if err := os.WriteFile(img, make([]byte, 2048), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	plan := &runPlan{
+		Model: "wiro/portrait",
+		Detail: &api.ToolDetail{Parameters: []api.ToolParameterGroup{{Items: []api.ToolParameterItem{
+			{ID: "prompt", Type: "textarea"},
+			{ID: "image", Type: "combinefileinput"},
+			{ID: "token", Type: "password"},
+		}}}},
+		Inputs: map[string][]api.MultipartValue{
+			"token":  {{Value: "s3cret"}},
+			"zeta":   {{Value: "1"}},
+			"image":  {{FilePath: img}, {Value: "https://example.com/b.png"}},
+			"prompt": {{Value: "a lighthouse"}},
+		},
+		Secret: map[string]bool{"token": true},
+	}
+	rows := plan.rows()
+	var fields []string
+	for _, r := range rows {
+		fields = append(fields, r.Field)
+	}
+	if got := strings.Join(fields, ","); got != "prompt,image,token,zeta" {
+		t.Fatalf("row order = %s", got)
+	}
+	if rows[1].Size != 2048 || rows[1].Value != img+", https://example.com/b.png" {
+		t.Fatalf("image row = %+v", rows[1])
+	}
+	if rows[2].Value != "(hidden)" {
+		t.Fatalf("secret shown: %+v", rows[2])
+	}
+
+	var b strings.Builder
+	if err := plan.render(&b); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(b.String(), "s3cret") || !strings.Contains(b.String(), "Upload: 2.0 KiB in 1 field(s)") {
+		t.Fatalf("render:\n%s", b.String())
+	}
+}
+
+func TestFindItem(t *testing.T) {
+	items := []api.ToolParameterItem{{ID: "steps", Label: "Inference Steps"}}
+	if item, ok := findItem(items, "STEPS"); !ok || item.ID != "steps" {
+		t.Fatalf("by id: %+v %v", item, ok)
+	}
+	if item, ok := findItem(items, "inference steps"); !ok || item.ID != "steps" {
+		t.Fatalf("by label: %+v %v", item, ok)
+	}
+	if _, ok := findItem(items, "seed"); ok {
+		t.Fatal("found a missing field")
+	}
+}
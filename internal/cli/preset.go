@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	"github.com/wiro-ai/wiro-cli/internal/preset"
+)
+
+const presetImportUsage = "usage: wiro preset import <url|owner/repo> [--ref <branch|tag>] [--force] [--json]"
+
+func presetCommand(ctx context.Context, args []string) error {
+	usage := "usage: wiro preset <import|ls|rm> ..."
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	sub := strings.TrimSpace(args[0])
+	switch sub {
+	case "import":
+		return presetImportCommand(ctx, args[1:])
+	case "ls", "list":
+		return presetListCommand(args[1:])
+	case "rm", "remove":
+		return presetRemoveCommand(args[1:])
+	case "--help", "-h", "help":
+		output.Println("Usage: wiro preset <import|ls|rm> ...")
+		return nil
+	default:
+		return fmt.Errorf("unknown preset command %q", sub)
+	}
+}
+
+func presetImportCommand(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("preset import", flag.ContinueOnError)
+	var ref string
+	var force, asJSON bool
+	fs.StringVar(&ref, "ref", "", "Branch or tag of a git repository")
+	fs.BoolVar(&force, "force", false, "Replace installed presets with the same name")
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+
+	var rest []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		rest, args = append(rest, args[0]), args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if err := requireArgs(append(rest, fs.Args()...), 1, presetImportUsage); err != nil {
+		return err
+	}
+	src := strings.TrimSpace(append(rest, fs.Args()...)[0])
+
+	dir, err := preset.Dir()
+	if err != nil {
+		return err
+	}
+	files, err := preset.Fetch(ctx, src, ref)
+	if err != nil {
+		return err
+	}
+	// Validate everything before installing anything, so a bad file in a shared
+	// repository does not leave half of it imported.
+	installed := make([]preset.Preset, 0, len(files))
+	for _, f := range files {
+		spec, err := preset.Validate(f)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.Origin, err)
+		}
+		if !force {
+			if _, err := preset.Path(dir, f.Name); err == nil {
+				return fmt.Errorf("%w: %s (use --force to replace it)", preset.ErrExists, f.Name)
+			}
+		}
+		installed = append(installed, preset.Preset{Name: f.Name, Model: spec.Model, Origin: f.Origin})
+	}
+	for i, f := range files {
+		path, err := preset.Install(dir, f, true)
+		if err != nil {
+			return err
+		}
+		installed[i].Path = path
+	}
+
+	if asJSON {
+		return output.PrintJSON(installed)
+	}
+	output.Printf("Imported %d preset(s) from %s:\n", len(installed), src)
+	for _, p := range installed {
+		output.Printf("  %s (%s)\n", p.Name, p.Model)
+	}
+	output.Printf("Run one with: wiro run --preset %s\n", installed[0].Name)
+	return nil
+}
+
+func presetListCommand(args []string) error {
+	fs := flag.NewFlagSet("preset ls", flag.ContinueOnError)
+	var asJSON bool
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() > 0 {
+		return errors.New("usage: wiro preset ls [--json]")
+	}
+	dir, err := preset.Dir()
+	if err != nil {
+		return err
+	}
+	presets, err := preset.List(dir)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		if presets == nil {
+			presets = []preset.Preset{}
+		}
+		return output.PrintJSON(presets)
+	}
+	if len(presets) == 0 {
+		output.Println("No presets installed. Import some with `wiro preset import <url|owner/repo>`.")
+		return nil
+	}
+	table := output.NewTable("NAME", "MODEL", "ORIGIN")
+	for _, p := range presets {
+		table.Row(p.Name, output.Dash(p.Model), output.Dash(p.Origin))
+	}
+	return table.Print()
+}
+
+func presetRemoveCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: wiro preset rm <name...>")
+	}
+	dir, err := preset.Dir()
+	if err != nil {
+		return err
+	}
+	for _, name := range args {
+		path, err := preset.Path(dir, name)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		output.Printf("Removed preset %s\n", name)
+	}
+	return nil
+}
+
+// presetSpecPath resolves --preset to the installed spec file.
+func presetSpecPath(name string) (string, error) {
+	dir, err := preset.Dir()
+	if err != nil {
+		return "", err
+	}
+	return preset.Path(dir, name)
+}
@@ -9,12 +9,13 @@ import (
 	"time"
 
 	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/jsonout"
 	"github.com/wiro-ai/wiro-cli/internal/output"
 )
 
 func projectCommand(ctx context.Context, app *App, args []string) error {
 	if len(args) == 0 {
-		return errors.New("usage: wiro project <ls|use> ...")
+		return errors.New("usage: wiro project <ls|use|budget|notify|whitelist|keys> ...")
 	}
 	sub := strings.TrimSpace(args[0])
 	switch sub {
@@ -22,8 +23,16 @@ func projectCommand(ctx context.Context, app *App, args []string) error {
 		return projectListCommand(ctx, app, args[1:])
 	case "use":
 		return projectUseCommand(ctx, app, args[1:])
+	case "budget":
+		return projectBudgetCommand(app, args[1:])
+	case "notify":
+		return projectNotifyCommand(app, args[1:])
+	case "whitelist":
+		return projectWhitelistCommand(ctx, app, args[1:])
+	case "keys":
+		return projectKeysCommand(ctx, app, args[1:])
 	case "--help", "-h", "help":
-		fmt.Println("Usage: wiro project <ls|use> ...")
+		output.Println("Usage: wiro project <ls|use|budget|notify|whitelist|keys> ...")
 		return nil
 	default:
 		return fmt.Errorf("unknown project command %q", sub)
@@ -34,12 +43,16 @@ func projectListCommand(ctx context.Context, app *App, args []string) error {
 	fs := flag.NewFlagSet("project ls", flag.ContinueOnError)
 	var asJSON bool
 	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	showSchema := jsonSchemaFlag(fs)
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
 			return nil
 		}
 		return err
 	}
+	if *showSchema {
+		return printJSONSchema("project ls")
+	}
 	if len(fs.Args()) != 0 {
 		return errors.New("usage: wiro project ls")
 	}
@@ -51,7 +64,7 @@ func projectListCommand(ctx context.Context, app *App, args []string) error {
 		return err
 	}
 	if asJSON {
-		return output.PrintJSON(projects)
+		return jsonout.Print(projects)
 	}
 	output.PrintProjects(projects)
 	return nil
@@ -104,6 +117,6 @@ func projectUseCommand(ctx context.Context, app *App, args []string) error {
 	if err := app.SaveConfig(); err != nil {
 		return err
 	}
-	fmt.Printf("Default project set: %s (%s)\n", chosenName, chosenKey)
+	output.Printf("Default project set: %s (%s)\n", chosenName, chosenKey)
 	return nil
 }
@@ -0,0 +1,460 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	"github.com/wiro-ai/wiro-cli/internal/queue"
+)
+
+func queueCommand(ctx context.Context, app *App, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: wiro queue <add|start|status|ls|rm> ...")
+	}
+	sub := strings.TrimSpace(args[0])
+	switch sub {
+	case "add":
+		return queueAddCommand(ctx, app, args[1:])
+	case "start":
+		return queueStartCommand(ctx, app, args[1:])
+	case "status":
+		return queueStatusCommand(args[1:])
+	case "ls", "list":
+		return queueListCommand(args[1:])
+	case "rm", "remove":
+		return queueRemoveCommand(args[1:])
+	case "--help", "-h", "help":
+		output.Println("Usage: wiro queue <add|start|status|ls|rm> ...")
+		return nil
+	default:
+		return fmt.Errorf("unknown queue command %q", sub)
+	}
+}
+
+func queueStore() (*queue.Store, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	return queue.NewStore(dir), nil
+}
+
+func queueAddCommand(ctx context.Context, app *App, args []string) error {
+	job := queue.Job{OutputDir: app.OutputDirDefault()}
+	var setVals, setFileVals, setURLVals stringSlice
+	var selection outputSelection
+	var params paramFiles
+	var gitContext bool
+	var secretVals stringSlice
+
+	fs := flag.NewFlagSet("queue add", flag.ContinueOnError)
+	fs.StringVar(&job.Project, "project", "", "Project name or API key")
+	fs.StringVar(&job.OutputDir, "output-dir", app.OutputDirDefault(), "Directory to save outputs")
+	fs.Var(&setVals, "set", "Set field value (key=value). Repeatable")
+	fs.Var(&setFileVals, "set-file", "Set file input (key=/path/file). Repeatable")
+	fs.Var(&setURLVals, "set-url", "Set URL input (key=https://...). Repeatable")
+	params.register(fs)
+	selection.register(fs)
+	fs.BoolVar(&gitContext, "git-context", false, "Record the current git commit, branch, and dirty state")
+	fs.Var(&secretVals, "secret-field", "Mask this field in history and manifests. Repeatable")
+	fs.StringVar(&job.NameTemplate, "name-template", "", "Output layout below --output-dir (default preferences.outputNameTemplate)")
+
+	var aliasSet []string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		owner, model, defaults, err := expandModelArg(args[0])
+		if err != nil {
+			return err
+		}
+		job.Owner, job.Model, aliasSet = owner, model, defaults
+		args = args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if rest := fs.Args(); len(rest) > 0 {
+		if job.Owner != "" || len(rest) > 1 {
+			return errors.New("usage: wiro queue add <owner/model> [--set key=value ...]")
+		}
+		owner, model, defaults, err := expandModelArg(rest[0])
+		if err != nil {
+			return err
+		}
+		job.Owner, job.Model, aliasSet = owner, model, defaults
+	}
+	if job.Owner == "" {
+		return errors.New("usage: wiro queue add <owner/model> [--set key=value ...]")
+	}
+	set, setFile, setURL, err := params.merge(setVals, setFileVals, setURLVals)
+	if err != nil {
+		return err
+	}
+	setVals, setFileVals, setURLVals = set, setFile, setURL
+	if _, err := parseKeyValuePairs(append(append(append([]string{}, setVals...), setFileVals...), setURLVals...)); err != nil {
+		return err
+	}
+
+	// Queue workers may run from another directory, so pin relative paths now.
+	fileVals, err := absoluteFileSets(setFileVals)
+	if err != nil {
+		return err
+	}
+	if abs, err := filepath.Abs(app.ResolveOutputDir(job.OutputDir)); err == nil {
+		job.OutputDir = abs
+	}
+	job.Set = withAliasDefaults(aliasSet, setVals, setFileVals, setURLVals)
+	job.SetFile = fileVals
+	job.SetURL = setURLVals
+	job.Workspace = app.WorkspaceName()
+	if _, err := output.ParseNameTemplate(job.NameTemplate); err != nil {
+		return err
+	}
+	filter, err := selection.filter()
+	if err != nil {
+		return err
+	}
+	job.OutputIndex, job.OutputMatch = filter.Indexes, filter.Patterns
+	job.SecretFields = secretVals
+	if gitContext {
+		job.Git = detectGitContext(ctx)
+	}
+
+	store, err := queueStore()
+	if err != nil {
+		return err
+	}
+	added, err := store.Add(job)
+	if err != nil {
+		return err
+	}
+	output.Printf("Queued job %s: %s/%s\n", added.ID, added.Owner, added.Model)
+	return nil
+}
+
+func absoluteFileSets(values []string) ([]string, error) {
+	out := make([]string, 0, len(values))
+	for _, kv := range values {
+		idx := strings.Index(kv, "=")
+		if idx <= 0 {
+			return nil, fmt.Errorf("invalid --set-file format %q (expected key=/path/file)", kv)
+		}
+		abs, err := filepath.Abs(kv[idx+1:])
+		if err != nil {
+			return nil, fmt.Errorf("resolve %q: %w", kv[idx+1:], err)
+		}
+		out = append(out, kv[:idx+1]+abs)
+	}
+	return out, nil
+}
+
+func queueStartCommand(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("queue start", flag.ContinueOnError)
+	var parallel int
+	var metricsAddr string
+	timeout, err := app.Config.Preferences.WatchTimeoutDuration()
+	if err != nil {
+		return err
+	}
+	fs.IntVar(&parallel, "parallel", 1, "Number of jobs to run at the same time")
+	fs.DurationVar(&timeout, "timeout", timeout, "Stop watching each job after this long (0 = no limit)")
+	fs.StringVar(&metricsAddr, "metrics-addr", "", "Serve Prometheus metrics on this address while running (e.g. :9464)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if len(fs.Args()) != 0 {
+		return errors.New("usage: wiro queue start [--parallel N] [--timeout <duration>]")
+	}
+	if parallel < 1 {
+		return errors.New("--parallel must be at least 1")
+	}
+	if err := startMetrics(ctx, metricsAddr); err != nil {
+		return err
+	}
+
+	store, err := queueStore()
+	if err != nil {
+		return err
+	}
+	unlock, err := store.Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	resumable, err := store.Recover()
+	if err != nil {
+		return err
+	}
+	var resumeMu sync.Mutex
+	next := func() (*queue.Job, error) {
+		resumeMu.Lock()
+		if len(resumable) > 0 {
+			job := resumable[0]
+			resumable = resumable[1:]
+			resumeMu.Unlock()
+			return &job, nil
+		}
+		resumeMu.Unlock()
+		return store.Claim()
+	}
+
+	runner := jobRunner{store: store, timeout: timeout, label: "queue"}
+	var failed int
+	var failedMu sync.Mutex
+	var wg sync.WaitGroup
+	for w := 0; w < parallel; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if ctx.Err() != nil {
+					return
+				}
+				job, err := next()
+				if err != nil {
+					output.Printf("[queue] %v\n", err)
+					return
+				}
+				if job == nil {
+					return
+				}
+				if err := runner.run(ctx, app, *job); err != nil {
+					failedMu.Lock()
+					failed++
+					failedMu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	q, err := store.Load()
+	if err != nil {
+		return err
+	}
+	counts := q.Counts()
+	output.Printf("Queue finished: done=%d failed=%d pending=%d\n", counts[queue.StatusDone], counts[queue.StatusFailed], counts[queue.StatusPending])
+	if failed > 0 {
+		return fmt.Errorf("%d queued job(s) failed", failed)
+	}
+	return nil
+}
+
+// jobRunner works stored jobs for `wiro queue start` and the daemon.
+type jobRunner struct {
+	store   *queue.Store
+	timeout time.Duration
+	// label prefixes progress lines, e.g. "queue".
+	label string
+	// retries is how many times a submitted job whose watch or download
+	// failed is resumed before it is marked failed.
+	retries int
+	// retryDelay is the wait before the first retry; it doubles after each.
+	retryDelay time.Duration
+}
+
+func (r jobRunner) run(ctx context.Context, app *App, job queue.Job) error {
+	if job.TaskToken != "" || job.TaskID != "" {
+		output.Infof("[%s] job %s resuming task %s (%s/%s)\n", r.label, job.ID, job.TaskID, job.Owner, job.Model)
+	} else {
+		output.Infof("[%s] job %s starting %s/%s\n", r.label, job.ID, job.Owner, job.Model)
+	}
+
+	// preferences.outputNameTemplate may have changed since the job was added.
+	naming, err := outputNaming(app, job.NameTemplate)
+	if job.Workspace != "" {
+		naming = naming.WithWorkspace(job.Workspace)
+	}
+	timeout := r.timeout
+	if job.Timeout != "" && err == nil {
+		timeout, err = time.ParseDuration(job.Timeout)
+	}
+	dedupe := app.Config.Preferences.DedupeOutputs
+	if job.Dedupe != nil {
+		dedupe = *job.Dedupe
+	}
+	var result runJobResult
+	delay := r.retryDelay
+	for attempt := 0; err == nil; attempt++ {
+		result, err = executeRunJob(ctx, app, runJob{
+			Project:       job.Project,
+			Owner:         job.Owner,
+			Model:         job.Model,
+			Set:           job.Set,
+			SetFile:       job.SetFile,
+			SetURL:        job.SetURL,
+			OutputDir:     job.OutputDir,
+			TaskToken:     job.TaskToken,
+			TaskID:        job.TaskID,
+			Timeout:       timeout,
+			Outputs:       output.OutputFilter{Indexes: job.OutputIndex, Patterns: job.OutputMatch},
+			Dedupe:        dedupe,
+			NoBudgetCheck: job.NoBudgetCheck,
+			Git:           job.Git,
+			SecretFields:  job.SecretFields,
+			Naming:        naming,
+			RequireGPU:    job.RequireGPU,
+			Priority:      job.Priority,
+			Reupload:      job.Reupload,
+		}, runJobHooks{
+			OnSubmitted: func(resp api.RunResponse) {
+				job.TaskID, job.TaskToken = resp.TaskID, resp.SocketAccessToken
+				_ = r.store.Set(job.ID, func(j *queue.Job) {
+					j.TaskID = resp.TaskID
+					j.TaskToken = resp.SocketAccessToken
+				})
+				output.Infof("[%s] job %s submitted taskid=%s\n", r.label, job.ID, resp.TaskID)
+			},
+		})
+		// Only a submitted task is resumed; submitting again would pay twice.
+		if err == nil || ctx.Err() != nil || attempt >= r.retries || (job.TaskToken == "" && job.TaskID == "") {
+			break
+		}
+		output.Infof("[%s] job %s: %v; retrying in %s\n", r.label, job.ID, err, delay)
+		select {
+		case <-ctx.Done():
+		case <-time.After(delay):
+		}
+		delay *= 2
+		err = nil
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			// Interrupted: leave the job running so the next start resumes it.
+			return err
+		}
+		_ = r.store.Set(job.ID, func(j *queue.Job) {
+			j.Status = queue.StatusFailed
+			j.Error = err.Error()
+		})
+		output.Printf("[%s] job %s failed: %v\n", r.label, job.ID, err)
+		return err
+	}
+
+	status := queue.StatusDone
+	errText := ""
+	if result.Task != nil && result.Task.Status != "task_postprocess_end" {
+		status = queue.StatusFailed
+		errText = "task ended with status " + result.Task.Status
+	}
+	_ = r.store.Set(job.ID, func(j *queue.Job) {
+		j.Status = status
+		j.Error = errText
+		j.Outputs = result.Paths
+		if result.Task != nil {
+			j.TaskID = result.Task.ID
+		}
+	})
+	output.Printf("[%s] job %s %s (%d file(s))\n", r.label, job.ID, status, len(result.Paths))
+	if status == queue.StatusFailed {
+		return errors.New(errText)
+	}
+	return nil
+}
+
+func queueStatusCommand(args []string) error {
+	if len(args) != 0 {
+		return errors.New("usage: wiro queue status")
+	}
+	store, err := queueStore()
+	if err != nil {
+		return err
+	}
+	q, err := store.Load()
+	if err != nil {
+		return err
+	}
+	counts := q.Counts()
+	output.Printf("Jobs: %d\n", len(q.Jobs))
+	for _, st := range []queue.Status{queue.StatusPending, queue.StatusRunning, queue.StatusDone, queue.StatusFailed} {
+		output.Printf("%s: %d\n", st, counts[st])
+	}
+	return nil
+}
+
+func queueListCommand(args []string) error {
+	fs := flag.NewFlagSet("queue ls", flag.ContinueOnError)
+	var asJSON bool
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if len(fs.Args()) != 0 {
+		return errors.New("usage: wiro queue ls [--json]")
+	}
+	store, err := queueStore()
+	if err != nil {
+		return err
+	}
+	q, err := store.Load()
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return output.PrintJSON(q.Jobs)
+	}
+	if len(q.Jobs) == 0 {
+		output.Println("Queue is empty.")
+		return nil
+	}
+	t := output.NewTable("ID", "STATUS", "MODEL", "TASK", "ERROR")
+	t.StyleColumn("STATUS", output.Std().Status)
+	t.StyleColumn("ERROR", output.Std().Red)
+	for _, j := range q.Jobs {
+		t.Row(j.ID, string(j.Status), j.Owner+"/"+j.Model, output.Dash(j.TaskID), short(j.Error, 80))
+	}
+	return t.Print()
+}
+
+func queueRemoveCommand(args []string) error {
+	fs := flag.NewFlagSet("queue rm", flag.ContinueOnError)
+	var done, failedOnly, all bool
+	fs.BoolVar(&done, "done", false, "Remove finished jobs")
+	fs.BoolVar(&failedOnly, "failed", false, "Remove failed jobs")
+	fs.BoolVar(&all, "all", false, "Remove every job")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	ids := map[string]bool{}
+	for _, id := range fs.Args() {
+		ids[strings.TrimSpace(id)] = true
+	}
+	if len(ids) == 0 && !done && !failedOnly && !all {
+		return errors.New("usage: wiro queue rm <id...> | --done | --failed | --all")
+	}
+
+	store, err := queueStore()
+	if err != nil {
+		return err
+	}
+	removed, err := store.Remove(func(j queue.Job) bool {
+		return all || ids[j.ID] ||
+			(done && j.Status == queue.StatusDone) ||
+			(failedOnly && j.Status == queue.StatusFailed)
+	})
+	if err != nil {
+		return err
+	}
+	output.Printf("Removed %d job(s).\n", removed)
+	return nil
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/log"
+	"github.com/wiro-ai/wiro-cli/internal/storage"
+)
+
+// responseCache keeps API responses in the responses collection of local
+// storage. It is loaded on first use, after the storage backend is known,
+// and, like the other caches, never fails a request.
+type responseCache struct {
+	once    sync.Once
+	mu      sync.Mutex
+	entries map[string]api.CachedResponse
+}
+
+type responseRecord struct {
+	Key string `json:"key"`
+	api.CachedResponse
+}
+
+func (c *responseCache) load() {
+	c.once.Do(func() {
+		c.entries = map[string]api.CachedResponse{}
+		st, err := localStorage()
+		if err != nil {
+			return
+		}
+		records, err := st.Records(storage.Responses)
+		if err != nil {
+			return
+		}
+		for _, raw := range records {
+			var r responseRecord
+			if json.Unmarshal(raw, &r) == nil && r.Key != "" {
+				c.entries[r.Key] = r.CachedResponse
+			}
+		}
+	})
+}
+
+func (c *responseCache) Get(key string) (api.CachedResponse, bool) {
+	c.load()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, ok := c.entries[key]
+	return r, ok
+}
+
+func (c *responseCache) Put(key string, r api.CachedResponse) {
+	c.load()
+	c.mu.Lock()
+	prev, ok := c.entries[key]
+	c.entries[key] = r
+	c.mu.Unlock()
+	if ok && prev.ETag == r.ETag && prev.LastModified == r.LastModified {
+		// Unchanged validators mean the stored body is current.
+		return
+	}
+	st, err := localStorage()
+	if err != nil {
+		return
+	}
+	data, err := json.Marshal(responseRecord{Key: key, CachedResponse: r})
+	if err != nil {
+		return
+	}
+	if err := st.Put(storage.Responses, key, data); err != nil {
+		log.Verbosef("cache: %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/history"
+)
+
+// resultStatusError is the result line's status when the final task is unknown.
+const resultStatusError = "error"
+
+// resultLine is the one-line summary printed when a watched run ends, for
+// scraping CI logs without --json:
+//
+//	RESULT task=123 status=task_postprocess_end duration=93s outputs=4 dir=/path
+//
+// duration is the task's own run time when the API reports it, else the time
+// since submission. dir is omitted when nothing was saved, and error is added
+// when saving outputs failed.
+func resultLine(t *api.Task, paths []string, manifestPath string, elapsed time.Duration, err error) string {
+	if d, ok := history.TaskDuration(t.StartTime, t.EndTime); ok {
+		elapsed = d
+	}
+	fields := []string{
+		"task=" + resultValue(t.ID),
+		"status=" + resultValue(t.Status),
+		fmt.Sprintf("duration=%ds", int64(elapsed.Round(time.Second)/time.Second)),
+		fmt.Sprintf("outputs=%d", len(paths)),
+	}
+	if manifestPath != "" {
+		fields = append(fields, "dir="+resultValue(filepath.Dir(manifestPath)))
+	}
+	if err != nil {
+		fields = append(fields, "error="+strconv.Quote(err.Error()))
+	}
+	return "RESULT " + strings.Join(fields, " ")
+}
+
+// watchErrorResultLine is the result line of a run whose watch failed, so the
+// final status is unknown: status=error and the watch error.
+func watchErrorResultLine(taskID string, elapsed time.Duration, err error) string {
+	return resultLine(&api.Task{ID: taskID, Status: resultStatusError}, nil, "", elapsed, err)
+}
+
+// resultValue quotes values that would break key=value splitting.
+func resultValue(s string) string {
+	if s == "" {
+		return `""`
+	}
+	if strings.ContainsAny(s, " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
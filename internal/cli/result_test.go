@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+func TestResultLine(t *testing.T) {
+	task := &api.Task{ID: "123", Status: "task_postprocess_end"}
+	manifest := filepath.Join("out", "fox", "manifest.json")
+	got := resultLine(task, []string{"a.png", "b.png"}, manifest, 93400*time.Millisecond, nil)
+	want := "RESULT task=123 status=task_postprocess_end duration=93s outputs=2 dir=" + filepath.Join("out", "fox")
+	if got != want {
+		t.Fatalf("resultLine = %q, want %q", got, want)
+	}
+
+	got = resultLine(task, nil, "", 2*time.Second, errors.New(`disk "full"`))
+	want = `RESULT task=123 status=task_postprocess_end duration=2s outputs=0 error="disk \"full\""`
+	if got != want {
+		t.Fatalf("resultLine = %q, want %q", got, want)
+	}
+	got = watchErrorResultLine("123", 5*time.Second, errors.New("watch timed out"))
+	want = `RESULT task=123 status=error duration=5s outputs=0 error="watch timed out"`
+	if got != want {
+		t.Fatalf("watchErrorResultLine = %q, want %q", got, want)
+	}
+	if got := resultValue("my dir"); got != `"my dir"` {
+		t.Fatalf("resultValue = %s", got)
+	}
+}
@@ -4,23 +4,176 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/log"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	"github.com/wiro-ai/wiro-cli/internal/telemetry"
 )
 
+// globalOptions are flags accepted anywhere on the command line.
+type globalOptions struct {
+	Verbose bool
+	Debug   bool
+	LogFile bool
+	LogPath string
+	Wide    bool
+	Quiet   bool
+	NoColor bool
+}
+
 // Execute runs CLI root command.
 func Execute() error {
+	argv, globals := parseGlobalFlags(os.Args[1:])
+	output.SetWide(globals.Wide)
+	output.SetQuiet(globals.Quiet)
+	output.SetColor(globals.NoColor)
+	closeLog, err := setupLogging(globals)
+	if err != nil {
+		return err
+	}
+	defer closeLog()
+	stopTelemetry, err := telemetry.Setup(Version)
+	if err != nil {
+		output.Warnf("telemetry disabled: %v", err)
+	}
+	defer stopTelemetry()
+
 	app, err := NewApp()
 	if err != nil {
 		return err
 	}
-	ctx := context.Background()
-	return dispatch(ctx, app, os.Args[1:])
+	modelAliases = app.Config.Aliases
+	storageBackend = app.Config.Preferences.Storage
+	defer closeStorage()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	terminated, stopSignals := notifyTerminate(app, cancel)
+	ctx, span := telemetry.StartSpan(ctx, "wiro "+commandName(argv), telemetry.KindInternal)
+	err = dispatch(ctx, app, argv)
+	span.End(err)
+	stopSignals()
+	log.PrintTimingSummary()
+	if terminated() {
+		return ErrTerminated
+	}
+	if err != nil {
+		return withHint(err)
+	}
+	return nil
+}
+
+// commandName is the command and, when it has them, subcommand argv runs,
+// e.g. "task wait", for naming the invocation's trace. Arguments are left out
+// so names stay few and never carry inputs.
+func commandName(argv []string) string {
+	if len(argv) == 0 {
+		return "run"
+	}
+	name := argv[0]
+	subs, ok := completionTree[name]
+	if !ok {
+		return "unknown"
+	}
+	if len(argv) > 1 {
+		for _, sub := range subs {
+			if sub == argv[1] {
+				return name + " " + sub
+			}
+		}
+	}
+	return name
+}
+
+// withHint appends an actionable next step for well-known API error kinds.
+func withHint(err error) error {
+	hint := ""
+	switch {
+	case errors.Is(err, api.ErrUnauthorized):
+		hint = "check credentials with `wiro auth status`, then run `wiro auth login` or `wiro auth set --api-key <key> --api-secret <secret>`"
+	case errors.Is(err, api.ErrInsufficientCredit):
+		hint = "your account is out of credits; top up in the Wiro dashboard and retry"
+	case errors.Is(err, api.ErrModelNotFound):
+		hint = "check the model slug with `wiro model search <query>`"
+	case errors.Is(err, api.ErrTaskNotFound):
+		hint = "check the task id, or pass --project for the project that started it"
+	case errors.Is(err, api.ErrRateLimited):
+		hint = "too many requests; wait a moment and retry"
+	case errors.Is(err, errBudgetExceeded):
+		hint = "check today's usage with `wiro project budget`; raise or --clear the limit, or pass --no-budget-check"
+	case errors.Is(err, api.ErrInvalidInput):
+		hint = "see accepted inputs with `wiro model inspect <owner/model>`"
+	}
+	if hint == "" {
+		return err
+	}
+	return fmt.Errorf("%w\nhint: %s", err, hint)
+}
+
+// parseGlobalFlags strips global flags from argv; everything after "--" is left untouched.
+func parseGlobalFlags(argv []string) ([]string, globalOptions) {
+	var g globalOptions
+	out := make([]string, 0, len(argv))
+	for i, arg := range argv {
+		if arg == "--" {
+			out = append(out, argv[i:]...)
+			break
+		}
+		switch {
+		case arg == "--verbose":
+			g.Verbose = true
+		case arg == "--debug":
+			g.Debug = true
+		case arg == "--wide":
+			g.Wide = true
+		case arg == "--quiet":
+			g.Quiet = true
+		case arg == "--no-color":
+			g.NoColor = true
+		case arg == "--log-file":
+			g.LogFile = true
+		case strings.HasPrefix(arg, "--log-file="):
+			g.LogFile = true
+			g.LogPath = strings.TrimPrefix(arg, "--log-file=")
+		default:
+			out = append(out, arg)
+		}
+	}
+	return out, g
+}
+
+func setupLogging(g globalOptions) (func(), error) {
+	switch {
+	case g.Debug:
+		log.SetLevel(log.LevelDebug)
+	case g.Verbose:
+		log.SetLevel(log.LevelVerbose)
+	case g.LogFile:
+		log.SetLevel(log.LevelDebug)
+		log.SetOutput(io.Discard)
+	}
+	if !g.LogFile {
+		return func() {}, nil
+	}
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	path, err := log.OpenFile(g.LogPath, dir)
+	if err != nil {
+		return nil, err
+	}
+	log.Debugf("logging to %s", path)
+	return func() { _ = log.Close() }, nil
 }
 
 func dispatch(ctx context.Context, app *App, argv []string) error {
 	if len(argv) == 0 {
-		return runInteractive(ctx, app, runOptions{Watch: app.Config.Preferences.WatchDefault, OutputDir: app.Config.Preferences.OutputDirDefault})
+		return runCommand(ctx, app, nil)
 	}
 
 	cmd := strings.TrimSpace(argv[0])
@@ -29,12 +182,46 @@ func dispatch(ctx context.Context, app *App, argv []string) error {
 		return runCommand(ctx, app, argv[1:])
 	case "task":
 		return taskCommand(ctx, app, argv[1:])
+	case "watch":
+		return watchCommand(ctx, app, argv[1:])
+	case "queue":
+		return queueCommand(ctx, app, argv[1:])
+	case "history":
+		return historyCommand(ctx, app, argv[1:])
+	case "stats":
+		return statsCommand(argv[1:])
+	case "config":
+		return configCommand(app, argv[1:])
+	case "upload":
+		return uploadCommand(ctx, app, argv[1:])
+	case "tui":
+		return tuiCommand(ctx, app, argv[1:])
 	case "model":
 		return modelCommand(ctx, app, argv[1:])
+	case "preset":
+		return presetCommand(ctx, argv[1:])
+	case "alias":
+		return aliasCommand(app, argv[1:])
 	case "project":
 		return projectCommand(ctx, app, argv[1:])
 	case "auth":
 		return authCommand(ctx, app, argv[1:])
+	case "account":
+		return accountCommand(ctx, app, argv[1:])
+	case "init":
+		return initCommand(ctx, app, argv[1:])
+	case "serve":
+		return serveCommand(ctx, app, argv[1:])
+	case "mcp":
+		return mcpCommand(ctx, app, argv[1:])
+	case "daemon":
+		return daemonCommand(ctx, app, argv[1:])
+	case "webhook":
+		return webhookCommand(ctx, app, argv[1:])
+	case "doctor":
+		return doctorCommand(ctx, app, argv[1:])
+	case "completion":
+		return completionCommand(argv[1:])
 	case "help", "-h", "--help":
 		printRootHelp()
 		return nil
@@ -48,25 +235,88 @@ func rootHelpText() string {
 
 Usage:
   wiro
+  wiro init [--force]
   wiro run [owner/model] [flags]
-  wiro task detail <taskid|tasktoken>
-  wiro task cancel <taskid>
-  wiro task kill <taskid>
-  wiro model search [query]
-  wiro model inspect <owner/model>
+  wiro task detail [taskid|tasktoken|@last]
+  wiro task outputs [taskid|tasktoken|@last] [--json]
+  wiro task download [taskid|tasktoken|@last] [--output-dir <path>] [--overwrite]
+  wiro upload <file> [file ...] [--project <name|apikey>] [--force] [--json]
+  wiro task wait [taskid|tasktoken|@last] [--timeout <duration>] [--poll-interval <duration>] [--download] [--json]
+  wiro task events <taskid|@last> [--output-dir <path>]
+  wiro task cancel <taskid|@last>
+  wiro task kill <taskid|@last>
+  wiro watch <taskid|tasktoken|@last ...> | --all [--json]
+  wiro watch --account [--interval 10s] [--json] [--metrics-addr :9464]
+  wiro queue add <owner/model> [--set key=value ...]
+  wiro queue start [--parallel N] [--timeout <duration>] [--metrics-addr :9464]
+  wiro queue status
+  wiro queue ls
+  wiro queue rm <id...> | --done | --failed | --all
+  wiro daemon start [--parallel N] [--foreground]
+  wiro daemon stop
+  wiro daemon status [--json]
+  wiro daemon jobs [--json]
+  wiro history ls [--limit N] [--json]
+  wiro history show <n>
+  wiro history rerun <n> [--set key=value] [--output-dir <path>]
+  wiro history sync [--limit N] [--json]
+  wiro stats models [--days N] [--limit N] [--json]
+  wiro config list [--json]
+  wiro config get <key>
+  wiro config set <key> <value>
+  wiro config unset <key>
+  wiro config edit
+  wiro tui [--project <name|apikey>] [--query <text>]
+  wiro model search [query] [--category <c>] [--tag <t>] [--owner <o>] [--favorites]
+  wiro model categories [--tags] [--json]
+  wiro model inspect <owner/model> [--example [--json-schema]] [--json]
+  wiro model schema-diff <owner/model> [--update]
+  wiro model run-spec export <owner/model> [--set ...] [--from-history N] [-o run.yaml]
+  wiro model run-spec import <file>
+  wiro model fav <add|rm|ls> [owner/model ...]
+  wiro preset import <url|owner/repo> [--ref <branch|tag>] [--force]
+  wiro preset ls [--json]
+  wiro preset rm <name...>
+  wiro alias set <name> <owner/model> [--set key=value ...]
+  wiro alias ls [--json]
+  wiro alias rm <name...>
   wiro project ls
   wiro project use <name|apikey>
-  wiro auth login
+  wiro project budget [name|apikey] [--tasks-per-day N] [--credits-per-day X] [--block|--warn] [--clear]
+  wiro project notify [name|apikey] [--desktop] [--bell] [--cmd <command>] [--clear] [--test]
+  wiro project whitelist <ls|add|rm> [ip|cidr ...] [--project name|apikey] [--force]
+  wiro project keys rotate [--project name|apikey] [--revoke-old] [--json [--show-secret]]
+  wiro auth login [--device]
   wiro auth verify <verifytoken> <code> [--authcode <2fa>]
   wiro auth set --api-key <key> [--api-secret <secret>] [--name <project-name>]
   wiro auth status
-  wiro auth logout
+  wiro auth accounts [--json]
+  wiro auth switch <email> [--project <name|apikey>]
+  wiro auth logout [email]
+  wiro auth export --encrypted [-o <file>]
+  wiro auth import <file> [--preferences]
+  wiro account balance
+  wiro account usage [--days N]
+  wiro serve [--addr 127.0.0.1:8787] [--project <name|apikey>] [--token <token>]
+  wiro mcp serve [--model owner/model ...] [--project <name|apikey>] [--output-dir <path>]
+  wiro webhook test <url> [--task <taskid|@last>] [--status succeeded|failed|cancelled] [--print]
+  wiro doctor [--timeout 10s] [--json]
+  wiro completion <bash|zsh|fish> [--install]
+
+Global flags:
+  --verbose             Print API calls with status and timing to stderr
+  --debug               Trace requests, redacted headers, and WebSocket frames
+  --log-file[=<path>]   Also write the trace to a file (default <config>/logs/wiro.log)
+  --wide                Do not truncate or wrap long text and table cells
+  --quiet               Hide progress bars and status messages; print only results
+  --no-color            Do not color output (also set by NO_COLOR)
 
-Run 'wiro <command> --help' for command-specific flags.`)
+Run 'wiro <command> --help' for command-specific flags. Commands with --json
+also take --json-schema, which prints the schema of their JSON output.`)
 }
 
 func printRootHelp() {
-	fmt.Println(rootHelpText())
+	output.Println(rootHelpText())
 }
 
 func requireArgs(got []string, n int, usage string) error {
@@ -0,0 +1,17 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseGlobalFlags(t *testing.T) {
+	argv, g := parseGlobalFlags([]string{"--debug", "run", "owner/model", "--set", "prompt=hi", "--log-file=/tmp/x.log", "--", "--verbose"})
+	want := []string{"run", "owner/model", "--set", "prompt=hi", "--", "--verbose"}
+	if !reflect.DeepEqual(argv, want) {
+		t.Fatalf("argv = %#v, want %#v", argv, want)
+	}
+	if !g.Debug || g.Verbose || !g.LogFile || g.LogPath != "/tmp/x.log" {
+		t.Fatalf("unexpected globals: %#v", g)
+	}
+}
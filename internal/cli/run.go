@@ -9,22 +9,75 @@ import (
 	"time"
 
 	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/auth"
 	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/gitinfo"
+	"github.com/wiro-ai/wiro-cli/internal/history"
+	"github.com/wiro-ai/wiro-cli/internal/jsonout"
+	"github.com/wiro-ai/wiro-cli/internal/log"
+	"github.com/wiro-ai/wiro-cli/internal/mediaprep"
+	"github.com/wiro-ai/wiro-cli/internal/model"
 	"github.com/wiro-ai/wiro-cli/internal/output"
 	"github.com/wiro-ai/wiro-cli/internal/task"
 )
 
+// defaultSubmitTimeout bounds input upload and submission. Watching is limited
+// separately by --timeout / preferences.watchTimeout.
+const defaultSubmitTimeout = 10 * time.Minute
+
 type runOptions struct {
-	Project   string
-	Watch     bool
-	OutputDir string
-	Set       []string
-	SetFile   []string
-	SetURL    []string
-	Advanced  bool
-	JSON      bool
-	Owner     string
-	Model     string
+	Project       string
+	Watch         bool
+	OutputDir     string
+	Set           []string
+	SetFile       []string
+	SetURL        []string
+	SetStdin      string
+	Advanced      bool
+	Quick         bool
+	NoCreditCheck bool
+	NoBudgetCheck bool
+	MaxCost       float64
+	Timeout       time.Duration
+	Sweep         []string
+	Outputs       output.OutputFilter
+	Dedupe        bool
+	NameTemplate  string
+	Naming        output.NameTemplate
+	Notify        notifyFlags
+	GitContext    bool
+	Git           *gitinfo.Info
+	SweepParallel int
+	JSON          bool
+	PrintPaths    bool
+	Owner         string
+	Model         string
+	// Spec is a run-spec file whose values seed Set, SetFile, and SetURL.
+	Spec string
+	// NoPrompt runs without prompts even on a terminal, as for --spec.
+	NoPrompt bool
+	// JSONEvents streams submission, watch events, and the final task as NDJSON on stdout.
+	JSONEvents bool
+	// RequireGPU asks the scheduler for an accelerator class such as a100.
+	RequireGPU string
+	// Priority is high, normal, or low; empty leaves the account default.
+	Priority string
+	// ParamFields maps fields set by --set-json/--set-yaml to their file, so
+	// ids that are not in the model schema are reported.
+	ParamFields map[string]string
+	// SecretFields are masked in history and manifests (--secret-field), on top
+	// of fields the schema marks secret.
+	SecretFields []string
+	// CallbackURL is where the API posts the finished task (--callback-url).
+	CallbackURL string
+	// Detach hands the run to the daemon instead of watching here.
+	Detach bool
+	// Yes submits a prompted run without the review step.
+	Yes bool
+	// Media preprocesses local file inputs before upload.
+	Media mediaprep.Options
+	// Reupload sends file inputs even when the upload cache has their content.
+	Reupload bool
 }
 
 func runCommand(ctx context.Context, app *App, args []string) error {
@@ -38,39 +91,95 @@ func runCommand(ctx context.Context, app *App, args []string) error {
 
 	opts := runOptions{
 		Watch:     app.Config.Preferences.WatchDefault,
-		OutputDir: app.Config.Preferences.OutputDirDefault,
+		OutputDir: app.OutputDirDefault(),
+	}
+	timeout, err := app.Config.Preferences.WatchTimeoutDuration()
+	if err != nil {
+		return err
 	}
-	var setVals, setFileVals, setURLVals stringSlice
+	opts.Timeout = timeout
+	var setVals, setFileVals, setURLVals, sweepVals, secretVals stringSlice
+	var selection outputSelection
+	var params paramFiles
+	var seeds seedFlags
+	var media mediaFlags
 
 	fs := flag.NewFlagSet("run", flag.ContinueOnError)
 	fs.SetOutput(flag.CommandLine.Output())
 	fs.StringVar(&opts.Project, "project", "", "Project name or API key")
 	fs.BoolVar(&opts.Watch, "watch", app.Config.Preferences.WatchDefault, "Watch task progress")
-	fs.StringVar(&opts.OutputDir, "output-dir", app.Config.Preferences.OutputDirDefault, "Directory to save outputs")
+	fs.StringVar(&opts.OutputDir, "output-dir", app.OutputDirDefault(), "Directory to save outputs")
 	fs.Var(&setVals, "set", "Set field value (key=value). Repeatable")
 	fs.Var(&setFileVals, "set-file", "Set file input (key=/path/file). Repeatable")
 	fs.Var(&setURLVals, "set-url", "Set URL input (key=https://...). Repeatable")
+	fs.StringVar(&opts.SetStdin, "set-stdin", "", "Read the value of this field from stdin")
+	params.register(fs)
 	fs.BoolVar(&opts.Advanced, "advanced", false, "Prompt advanced model fields")
+	fs.BoolVar(&opts.Quick, "quick", false, "Prompt only quick fields; use defaults for the rest")
+	fs.BoolVar(&opts.NoCreditCheck, "no-credit-check", false, "Skip the remaining-credit check before submission")
+	fs.BoolVar(&opts.NoBudgetCheck, "no-budget-check", false, "Submit even when the project's daily budget is exceeded")
+	fs.Float64Var(&opts.MaxCost, "max-cost", 0, "Abort when the estimated cost exceeds this many credits")
+	fs.DurationVar(&opts.Timeout, "timeout", opts.Timeout, "Stop watching after this long, e.g. 90m (0 = no limit)")
+	fs.Var(&secretVals, "secret-field", "Treat this field as secret: hidden when prompted, masked in history and manifests. Repeatable")
+	fs.Var(&sweepVals, "sweep", "Run once per value (key=v1,v2,...). Repeatable; combinations are multiplied")
+	fs.IntVar(&opts.SweepParallel, "sweep-parallel", 3, "Sweep runs in flight at once")
+	fs.StringVar(&seeds.Seed, "seed", "", "Set the model's seed field; with --seed-sweep, the first of sequential seeds")
+	fs.IntVar(&seeds.Sweep, "seed-sweep", 0, "Run N times with sequential seeds from --seed, or random seeds")
+	fs.StringVar(&seeds.Reuse, "reuse-seed", "", "Use the seed of an earlier task (id, token, or @last)")
+	selection.register(fs)
+	media.register(fs)
+	fs.BoolVar(&opts.Reupload, "reupload", false, "Upload file inputs even when an identical file was uploaded before")
+	opts.Notify.register(fs)
+	fs.BoolVar(&opts.Dedupe, "dedupe", app.Config.Preferences.DedupeOutputs, "Reuse identical, already downloaded files")
+	fs.StringVar(&opts.NameTemplate, "name-template", "", "Output layout below --output-dir, e.g. {model}/{date}/{taskid}-{index}{ext} (default preferences.outputNameTemplate)")
+	fs.BoolVar(&opts.GitContext, "git-context", false, "Record the git commit, branch, and dirty state in history and the manifest")
 	fs.BoolVar(&opts.JSON, "json", false, "JSON output")
+	fs.BoolVar(&opts.PrintPaths, "print-paths", false, "Print only downloaded file paths on stdout")
+	fs.BoolVar(&opts.JSONEvents, "json-events", false, "Stream watch events and the final task as NDJSON on stdout")
+	fs.StringVar(&opts.Spec, "spec", "", "Run the spec file written by `wiro model run-spec export`")
+	var presetName string
+	fs.StringVar(&presetName, "preset", "", "Run a preset installed with `wiro preset import`")
+	fs.StringVar(&opts.RequireGPU, "require-gpu", "", "Request a GPU class for the task, e.g. a100 (a scheduling hint)")
+	fs.StringVar(&opts.Priority, "priority", "", "Queue priority among this account's tasks: high, normal, or low")
+	fs.BoolVar(&opts.Yes, "yes", false, "Submit prompted runs without the review step")
+	fs.BoolVar(&opts.Detach, "detach", false, "Hand the run to the background daemon and return (see wiro daemon)")
+	fs.StringVar(&opts.CallbackURL, "callback-url", "", "Have the API post the finished task to this URL; the CLI then does not watch unless --watch is given")
 
 	// Support the documented shape: `wiro run owner/model --flags ...`
+	var aliasSet []string
 	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
-		if owner, model, err := parseModelArg(args[0]); err == nil {
+		if owner, model, defaults, err := expandModelArg(args[0]); err == nil {
 			opts.Owner = owner
 			opts.Model = model
+			aliasSet = defaults
 			args = args[1:]
 		}
 	}
 
+	showSchema := jsonSchemaFlag(fs)
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
 			return nil
 		}
 		return err
 	}
-	opts.Set = setVals
-	opts.SetFile = setFileVals
-	opts.SetURL = setURLVals
+	if *showSchema {
+		return printJSONSchema("run")
+	}
+	opts.Notify.parsed(fs)
+	// Individual flags override the parameter files for the same field.
+	if opts.Set, opts.SetFile, opts.SetURL, err = params.merge(setVals, setFileVals, setURLVals); err != nil {
+		return err
+	}
+	opts.ParamFields = params.fields
+	opts.Sweep = sweepVals
+	opts.SecretFields = secretVals
+	if opts.Outputs, err = selection.filter(); err != nil {
+		return err
+	}
+	if opts.Media, err = media.options(); err != nil {
+		return err
+	}
 
 	rest := fs.Args()
 	if len(rest) > 0 {
@@ -80,21 +189,101 @@ func runCommand(ctx context.Context, app *App, args []string) error {
 		if len(rest) > 1 {
 			return errors.New("run accepts at most one model argument")
 		}
-		owner, model, err := parseModelArg(rest[0])
+		owner, model, defaults, err := expandModelArg(rest[0])
 		if err != nil {
 			return err
 		}
 		opts.Owner = owner
 		opts.Model = model
+		aliasSet = defaults
 	}
+	opts.Set = withAliasDefaults(aliasSet, opts.Set, opts.SetFile, opts.SetURL)
 
-	runCtx, cancel := context.WithTimeout(ctx, 20*time.Minute)
-	defer cancel()
-	return runInteractive(runCtx, app, opts)
+	if presetName != "" {
+		if opts.Spec != "" {
+			return errors.New("--preset and --spec cannot be combined")
+		}
+		path, err := presetSpecPath(presetName)
+		if err != nil {
+			return err
+		}
+		opts.Spec = path
+	}
+	if opts.Spec != "" {
+		if opts.Model != "" {
+			return errors.New("--spec and --preset already name the model; drop the model argument")
+		}
+		job, _, err := loadRunSpec(opts.Spec)
+		if err != nil {
+			return err
+		}
+		opts.Owner, opts.Model = job.Owner, job.Model
+		// Flags on the command line override the spec's values for the same field.
+		set, setFile, setURL := opts.Set, opts.SetFile, opts.SetURL
+		opts.Set = append(overrideKeys(job.Set, set, setFile, setURL), set...)
+		opts.SetFile = append(overrideKeys(job.SetFile, set, setFile, setURL), setFile...)
+		opts.SetURL = append(overrideKeys(job.SetURL, set, setFile, setURL), setURL...)
+		opts.Advanced = true
+		opts.NoPrompt = true
+	}
+	if opts.Spec == "" {
+		if err := applyWorkspaceDefaults(app, &opts); err != nil {
+			return err
+		}
+	}
+
+	if err := applySeedFlags(ctx, app, &opts, seeds); err != nil {
+		return err
+	}
+	if opts.Timeout < 0 {
+		return errors.New("--timeout must not be negative")
+	}
+	if opts.Naming, err = outputNaming(app, opts.NameTemplate); err != nil {
+		return err
+	}
+	if opts.Priority, err = parsePriority(opts.Priority); err != nil {
+		return err
+	}
+	if opts.CallbackURL != "" {
+		if opts.CallbackURL, err = parseCallbackURL(opts.CallbackURL); err != nil {
+			return err
+		}
+		// The server reports the result, so watching is opt-in.
+		opts.Watch = false
+		fs.Visit(func(f *flag.Flag) {
+			if f.Name == "watch" {
+				opts.Watch = f.Value.String() == "true"
+			}
+		})
+	}
+	if opts.JSONEvents {
+		if opts.JSON || opts.PrintPaths {
+			return errors.New("--json-events cannot be combined with --json or --print-paths")
+		}
+		if len(opts.Sweep) > 0 {
+			return errors.New("--json-events is not supported with --sweep")
+		}
+		// Prompts would interleave with the event stream.
+		opts.NoPrompt = true
+	}
+	if opts.GitContext {
+		opts.Git = detectGitContext(ctx)
+	}
+	if opts.Detach {
+		return detachRun(ctx, app, opts)
+	}
+	if len(opts.Sweep) > 0 {
+		axes, err := parseSweeps(opts.Sweep)
+		if err != nil {
+			return err
+		}
+		return runSweep(ctx, app, opts, axes, opts.SweepParallel)
+	}
+	return runInteractive(ctx, app, opts)
 }
 
 func printRunHelp() {
-	fmt.Println(strings.TrimSpace(`Usage:
+	output.Println(strings.TrimSpace(`Usage:
   wiro run [owner/model] [flags]
 
 Flags:
@@ -104,8 +293,41 @@ Flags:
   --set key=value
   --set-file key=/path/to/file
   --set-url key=https://...
+  --set-stdin key
+  --set-json <file> (field values from a JSON file; --set wins)
+  --set-yaml <file> (field values from a YAML file; --set wins)
+  --secret-field key (repeatable; hidden when prompted, masked in history and manifests)
   --advanced
-  --json`))
+  --quick
+  --yes (submit prompted runs without reviewing the inputs first)
+  --no-credit-check
+  --no-budget-check (submit even when the project's daily budget is exceeded)
+  --max-cost <credits>
+  --timeout <duration> (default none; e.g. 90m)
+  --sweep key=v1,v2 (repeatable; one run per combination)
+  --sweep-parallel N (default 3)
+  --seed N (set the model's seed field)
+  --seed-sweep N (N runs with seeds counting up from --seed, or random seeds)
+  --reuse-seed <taskid|@last> (use an earlier task's seed and, without a model argument, its model)
+  --output-index N[,N...] (download only these outputs)
+  --output-match <glob> (download only matching outputs, e.g. '*.mp4')
+  --max-side <px> (scale image and video inputs down before upload; video needs ffmpeg)
+  --format jpeg|png (re-encode image inputs before upload)
+  --strip-exif (remove metadata from image and video inputs before upload)
+  --reupload (send file inputs even when an identical file was uploaded before)
+  --dedupe (reuse identical files already downloaded)
+  --notify, --bell, --notify-cmd <command> (report the finished task; default from wiro project notify)
+  --name-template <template> (output layout, e.g. {model}/{date}/{taskid}-{index}{ext})
+  --git-context (record git commit, branch, and dirty state)
+  --spec <file> (run a spec from wiro model run-spec export)
+  --preset <name> (run a spec installed with wiro preset import)
+  --require-gpu <type> (scheduling hint, e.g. a100)
+  --priority high|normal|low (queue order among this account's tasks)
+  --callback-url <url> (the API posts the finished task there; no watching unless --watch)
+  --detach (hand the run to wiro daemon, starting it if needed, and return)
+  --json
+  --json-events (NDJSON watch events, ending with the final task)
+  --print-paths`))
 }
 
 func runInteractive(ctx context.Context, app *App, opts runOptions) error {
@@ -127,6 +349,12 @@ func runInteractive(ctx context.Context, app *App, opts runOptions) error {
 	if err != nil {
 		return err
 	}
+	warnSchemaDrift(owner+"/"+slug, detail)
+	model.MarkSecret(detail, opts.SecretFields)
+	secret := model.SecretFields(modelItems(detail, true), opts.SecretFields)
+	if err := checkParamFields(modelItems(detail, true), opts.ParamFields); err != nil {
+		return err
+	}
 
 	setText, err := parseKeyValuePairs(opts.Set)
 	if err != nil {
@@ -140,10 +368,24 @@ func runInteractive(ctx context.Context, app *App, opts runOptions) error {
 	if err != nil {
 		return err
 	}
+	if key := strings.TrimSpace(opts.SetStdin); key != "" {
+		if _, exists := setText[key]; exists {
+			return fmt.Errorf("field %q is set by both --set and --set-stdin", key)
+		}
+		val, err := readStdinValue()
+		if err != nil {
+			return err
+		}
+		setText[key] = []string{val}
+	}
 	preset := mergeParamSources(setText, setFile, setURL)
 
+	if opts.Quick && opts.Advanced {
+		return errors.New("--quick and --advanced cannot be used together")
+	}
+	prompting := isInteractiveSession() && !opts.NoPrompt
 	includeAdvanced := opts.Advanced
-	if !includeAdvanced && hasAdvancedFields(detail) && isInteractiveSession() {
+	if !includeAdvanced && !opts.Quick && hasAdvancedFields(detail) && prompting {
 		openAdvanced, askErr := promptConfirm("Open advanced fields?", false)
 		if askErr != nil {
 			return askErr
@@ -153,15 +395,25 @@ func runInteractive(ctx context.Context, app *App, opts runOptions) error {
 
 	items := modelItems(detail, includeAdvanced)
 	var inputs map[string][]api.MultipartValue
-	if isInteractiveSession() {
-		inputs, err = buildInteractiveInputs(items, preset)
+	if prompting {
+		preset, err = model.ValidateValues(modelItems(detail, true), preset)
+		if err != nil {
+			return err
+		}
+		if opts.Quick {
+			var prompted []api.ToolParameterItem
+			prompted, preset = quickInputs(items, preset)
+			inputs, err = buildInteractiveInputs(prompted, preset)
+		} else {
+			inputs, err = buildInteractiveInputs(items, preset)
+		}
 		if err != nil {
 			return err
 		}
 	} else {
-		inputs, err = buildNonInteractiveInputs(items, preset)
+		inputs, err = checkNonInteractiveInputs(modelItems(detail, true), items, preset)
 		if err != nil {
-			return fmt.Errorf("non-interactive run requires all required fields via --set/--set-file/--set-url: %w", err)
+			return fmt.Errorf("non-interactive run needs every required field via --set/--set-file/--set-url: %w", err)
 		}
 	}
 
@@ -175,63 +427,222 @@ func runInteractive(ctx context.Context, app *App, opts runOptions) error {
 		}
 	}
 
-	if !opts.JSON {
-		fmt.Printf("Project: %s\n", displayProject(selectedProfile))
-		fmt.Printf("Model: %s/%s\n", owner, slug)
-		fmt.Printf("Inputs: %d fields\n", len(inputs))
-		fmt.Printf("Auth: %s\n", headerResult.Mode)
+	// Prompted runs end with a review of everything about to be submitted.
+	reviewed := prompting && !opts.Yes
+	if reviewed {
+		plan := &runPlan{
+			Model:   owner + "/" + slug,
+			Project: displayProject(selectedProfile),
+			Auth:    authLabel(headerResult),
+			Detail:  detail,
+			Inputs:  inputs,
+			Secret:  secret,
+		}
+		if err := reviewRun(plan); err != nil {
+			return err
+		}
+		inputs = plan.Inputs
 	}
 
-	resp, err := app.TaskSvc.Run(ctx, owner, slug, inputs, headerResult.Headers)
+	estimate, hasEstimate := model.EstimatePrice(detail, inputs)
+	if err := checkMaxCost(estimate, hasEstimate, opts.MaxCost); err != nil {
+		return err
+	}
+
+	if !opts.NoBudgetCheck {
+		if err := checkBudget(selectedProfile, estimate); err != nil {
+			return err
+		}
+	}
+
+	if !opts.NoCreditCheck {
+		if err := checkCreditBeforeRun(ctx, app, detail, inputs, headerResult.Headers); err != nil {
+			return err
+		}
+	}
+
+	human := !opts.JSON && !opts.PrintPaths && !opts.JSONEvents
+	var events *eventWriter
+	if opts.JSONEvents {
+		events = newStdoutEventWriter()
+	}
+	if human && !reviewed {
+		output.Infof("Project: %s\n", displayProject(selectedProfile))
+		output.Infof("Model: %s/%s\n", owner, slug)
+		output.Infof("Inputs: %d fields\n", len(inputs))
+		output.Infof("Auth: %s\n", authLabel(headerResult))
+		if hasEstimate {
+			output.Infof("Estimated cost: %s\n", formatCredits(estimate, ""))
+		}
+	}
+
+	uploads, cleanupUploads, err := prepareUploads(ctx, inputs, opts.Media)
+	if err != nil {
+		return err
+	}
+	defer cleanupUploads()
+	var reuse *uploadReuse
+	if !opts.Reupload {
+		reuse = newUploadReuse(selectedProfile)
+		uploads = reuse.apply(ctx, app, uploads, headerResult.Headers)
+	}
+	submitCtx, cancelSubmit := context.WithTimeout(ctx, defaultSubmitTimeout)
+	uploadBar := output.NewProgress("Uploading inputs")
+	resp, err := app.TaskSvc.Run(submitCtx, owner, slug, withRunHints(uploads, opts.RequireGPU, opts.Priority), headerResult.Headers, task.RunOptions{OnUploadProgress: uploadBar.Update, CallbackURL: opts.CallbackURL})
+	submitted := time.Now()
+	uploadBar.Done()
+	cancelSubmit()
 	if err != nil {
 		return err
 	}
 	if opts.JSON {
-		_ = output.PrintJSON(resp)
-	} else {
-		fmt.Printf("Task started: taskid=%s token=%s\n", resp.TaskID, resp.SocketAccessToken)
+		_ = jsonout.Print(resp)
+	} else if events != nil {
+		events.emit(jsonEvent{Source: "system", Type: "submitted", Payload: resp})
+	} else if human {
+		output.Infof("Task started: taskid=%s token=%s\n", resp.TaskID, resp.SocketAccessToken)
+		if opts.CallbackURL != "" {
+			output.Infof("Callback: the finished task will be posted to %s\n", opts.CallbackURL)
+		}
 	}
 
-	app.State.LastTaskID = resp.TaskID
-	app.State.LastTaskToken = resp.SocketAccessToken
+	app.State.SetLastTask(config.TaskContext(selectedProfile), config.RecentTask{
+		TaskID:      resp.TaskID,
+		TaskToken:   resp.SocketAccessToken,
+		Model:       owner + "/" + slug,
+		SubmittedAt: time.Now().UTC().Format(time.RFC3339),
+	})
 	_ = app.SaveState()
+	// Secret values are submitted but never recorded.
+	recorded := model.MaskInputs(inputs, secret)
+	replaySet, replayFiles := inputsToFlags(recorded)
+	recordRun(history.Entry{
+		Project:      historyProject(selectedProfile),
+		Owner:        owner,
+		Model:        slug,
+		Set:          replaySet,
+		SetFile:      replayFiles,
+		SecretFields: presentSecrets(inputs, secret),
+		Summary:      promptFromInputs(recorded),
+		TaskID:       resp.TaskID,
+		TaskToken:    resp.SocketAccessToken,
+		OutputDir:    app.ResolveOutputDir(opts.OutputDir),
+		Git:          opts.Git,
+		// Zero when the model publishes no price.
+		EstimatedCost: estimate,
+	})
 
 	if !opts.Watch {
 		return nil
 	}
+	inflight.add(inflightTask{TaskID: resp.TaskID, Model: owner + "/" + slug, OutputDir: app.ResolveOutputDir(opts.OutputDir)})
+	tr := openTranscript(app, opts.OutputDir, resp.TaskID)
+	timeline := task.NewTimeline()
 
-	watchCtx, cancel := context.WithCancel(ctx)
+	watchCtx, cancel := watchContext(ctx, opts.Timeout)
 	defer cancel()
-	if !opts.JSON {
-		fmt.Println("Watching task... (WebSocket + polling fallback)")
+	if human {
+		output.Infoln("Watching task... (WebSocket + polling fallback)")
+	}
+	watch := func(ctx context.Context) (*api.Task, error) {
+		return app.TaskSvc.WatchTask(ctx, resp.SocketAccessToken, headerResult.Headers, task.WatchOptions{
+			TaskID: resp.TaskID,
+			OnEvent: func(ev task.WatchEvent) {
+				tr.watchEvent(ev)
+				timeline.Observe(ev.Type, time.Now())
+				if events != nil {
+					events.watchEvent(ev)
+				}
+				if !human {
+					return
+				}
+				printWatchEvent(ev)
+			},
+		})
+	}
+	var finalTask *api.Task
+	stopWatchTimer := log.Time(log.PhaseWatch, "task "+resp.TaskID)
+	if human && prompting {
+		finalTask, err = watchWithKeys(watchCtx, app, resp.TaskID, headerResult.Headers, watch)
+	} else {
+		finalTask, err = watch(watchCtx)
+	}
+	stopWatchTimer()
+	if errors.Is(err, errDetached) {
+		tr.finish(nil, nil)
+		output.Printf("Detached; task %s keeps running. Check it with `wiro task detail %s`.\n", resp.TaskID, resp.TaskID)
+		return nil
 	}
-	finalTask, err := app.TaskSvc.WatchTask(watchCtx, resp.SocketAccessToken, headerResult.Headers, func(ev task.WatchEvent) {
-		if opts.JSON {
-			return
-		}
-		printWatchEvent(ev)
-	})
 	if err != nil {
+		err = watchTimeoutError(ctx, err, opts.Timeout, resp.TaskID)
+		finishRun(resp.TaskID, nil, nil, err)
+		if ctx.Err() == nil {
+			inflight.done(resp.TaskID)
+		}
+		tr.finish(nil, err)
+		if events != nil {
+			events.emit(jsonEvent{Source: "system", Type: "error", Text: err.Error()})
+		}
+		if human {
+			output.Println(watchErrorResultLine(resp.TaskID, time.Since(submitted), err))
+		}
 		return err
 	}
 	if finalTask == nil {
-		return errors.New("watch completed without final task")
+		err = errors.New("watch completed without final task")
+		tr.finish(nil, err)
+		if human {
+			output.Println(watchErrorResultLine(resp.TaskID, time.Since(submitted), err))
+		}
+		return err
 	}
+	tr.finish(finalTask, nil)
+
+	timeline.Observe(finalTask.Status, time.Now())
 
 	if opts.JSON {
-		_ = output.PrintJSON(finalTask)
-	} else {
+		_ = jsonout.Print(finalTask)
+	} else if human {
 		output.PrintTask(finalTask)
+		if phases := phaseSummary(timeline.Phases(time.Now())); phases != "" {
+			output.Printf("Phases: %s\n", phases)
+		}
 	}
+	checkHardware(finalTask, opts.RequireGPU)
+	reuse.learn(finalTask)
 
-	paths, err := output.DownloadOutputs(finalTask, opts.OutputDir, promptFromInputs(inputs))
+	dl := runDownloadOptions(app, opts.Outputs, opts.Dedupe, opts.Naming)
+	dl.OnProgress = output.DownloadProgressBars()
+	paths, manifestPath, err := saveTaskOutputs(ctx, app, finalTask, opts.OutputDir, dl, owner+"/"+slug, recorded, headerResult.Headers, opts.Git)
+	finishRun(resp.TaskID, finalTask, paths, err)
+	inflight.done(resp.TaskID)
+	notifyFinished(opts.Notify.apply(selectedProfile), finalTask, owner+"/"+slug, paths)
+	if human {
+		// Printed last, after the download list, so it is easy to find.
+		result := resultLine(finalTask, paths, manifestPath, time.Since(submitted), err)
+		defer output.Println(result)
+	}
+	if events != nil {
+		// The final task closes the stream even when downloads fail.
+		final := jsonEvent{Source: "system", Type: "task", Payload: finalTask, Paths: paths}
+		if err != nil {
+			final.Text = err.Error()
+		}
+		events.emit(final)
+	}
 	if err != nil {
 		return err
 	}
-	if len(paths) > 0 && !opts.JSON {
-		fmt.Println("Downloaded files:")
+	if len(paths) > 0 && human {
+		output.Println("Downloaded files:")
 		for _, p := range paths {
-			fmt.Printf("- %s\n", p)
+			output.Printf("- %s\n", p)
+		}
+		output.Printf("Manifest: %s\n", manifestPath)
+	}
+	if opts.PrintPaths {
+		for _, p := range paths {
+			output.Println(p)
 		}
 	}
 	return nil
@@ -252,7 +663,58 @@ func promptFromInputs(values map[string][]api.MultipartValue) string {
 	return ""
 }
 
+// applyWorkspaceDefaults runs the workspace's pinned model when no model is
+// named and puts its params under the flags. With a pinned model the params
+// only apply to that model; without one they apply to every run.
+func applyWorkspaceDefaults(app *App, opts *runOptions) error {
+	cfg := app.WorkspaceConfig
+	if cfg.Model != "" {
+		owner, model, defaults, err := expandModelArg(cfg.Model)
+		if err != nil {
+			return fmt.Errorf("%s: model: %w", app.Workspace.File, err)
+		}
+		if opts.Model == "" {
+			opts.Owner, opts.Model = owner, model
+			opts.Set = withAliasDefaults(defaults, opts.Set, opts.SetFile, opts.SetURL)
+		}
+		if opts.Owner != owner || opts.Model != model {
+			return nil
+		}
+	}
+	set, setFile, setURL, err := app.Workspace.ParamValues(cfg)
+	if err != nil || len(set)+len(setFile)+len(setURL) == 0 {
+		return err
+	}
+	set = overrideKeys(set, opts.Set, opts.SetFile, opts.SetURL)
+	setFile = overrideKeys(setFile, opts.Set, opts.SetFile, opts.SetURL)
+	setURL = overrideKeys(setURL, opts.Set, opts.SetFile, opts.SetURL)
+	if cfg.Model != "" {
+		// Fields the pinned model lacks are reported like --set-yaml ones.
+		if opts.ParamFields == nil {
+			opts.ParamFields = map[string]string{}
+		}
+		for _, kv := range append(append(append([]string{}, set...), setFile...), setURL...) {
+			k, _, _ := strings.Cut(kv, "=")
+			opts.ParamFields[k] = app.Workspace.File
+		}
+	}
+	opts.Set = append(set, opts.Set...)
+	opts.SetFile = append(setFile, opts.SetFile...)
+	opts.SetURL = append(setURL, opts.SetURL...)
+	return nil
+}
+
 func resolveProject(ctx context.Context, app *App, selected string) (*api.Project, *config.ProjectProfile, error) {
+	// A key from the environment pins the project; nothing is read from or saved to config.
+	if env := app.AuthSvc.EnvCredentials(); env.APIKey != "" && strings.TrimSpace(selected) == "" {
+		chosen := &api.Project{Name: auth.EnvAPIKey, APIKey: env.APIKey, AuthMethod: string(env.Mode())}
+		return chosen, &config.ProjectProfile{Name: chosen.Name, APIKey: chosen.APIKey, AuthMethodHint: chosen.AuthMethod}, nil
+	}
+	// The workspace pins a project for everyone in it without changing the user's default.
+	pinned := false
+	if strings.TrimSpace(selected) == "" && app.WorkspaceConfig.Project != "" {
+		selected, pinned = app.WorkspaceConfig.Project, true
+	}
 	projects, err := app.ProjectSvc.ListHybrid(ctx, app.Config)
 	if err != nil {
 		if len(app.Config.Projects) == 0 {
@@ -272,6 +734,9 @@ func resolveProject(ctx context.Context, app *App, selected string) (*api.Projec
 				break
 			}
 		}
+		if chosen == nil && pinned {
+			return nil, nil, fmt.Errorf("project %q pinned by %s not found; add it with `wiro auth login` or pass --project", selected, app.Workspace.File)
+		}
 		if chosen == nil {
 			return nil, nil, fmt.Errorf("project %q not found", selected)
 		}
@@ -315,7 +780,9 @@ func resolveProject(ctx context.Context, app *App, selected string) (*api.Projec
 		if chosen.Name != "" {
 			profile.Name = chosen.Name
 		}
-		app.Config.DefaultProject = chosen.APIKey
+		if !pinned {
+			app.Config.DefaultProject = chosen.APIKey
+		}
 		_ = app.SaveConfig()
 	}
 	return chosen, profile, nil
@@ -329,15 +796,24 @@ func resolveModel(ctx context.Context, app *App, owner, slug string) (string, st
 		return "", "", errors.New("model argument is required in non-interactive mode: wiro run <owner/model>")
 	}
 
+	picked, err := browseModels(ctx, app)
+	switch {
+	case err == nil:
+		return picked.SlugOwner, picked.SlugProject, nil
+	case errors.Is(err, errPickerCancelled), ctx.Err() != nil:
+		return "", "", err
+	}
+	// Without raw terminal mode, search once and pick from a plain list.
+	log.Verbosef("model browser: %v", err)
 	query, err := promptInput("Model search query (blank for popular)", "")
 	if err != nil {
 		return "", "", err
 	}
-	models, err := app.ModelSvc.List(ctx, query, 40)
+	models, err := app.ModelSvc.List(ctx, model.ListOptions{Query: query, Limit: 40})
 	if err != nil {
 		return "", "", err
 	}
-	picked, err := selectModelInteractive(models)
+	picked, err = selectModelInteractive(models)
 	if err != nil {
 		return "", "", err
 	}
@@ -366,6 +842,13 @@ func displayProject(p *config.ProjectProfile) string {
 }
 
 func printWatchEvent(ev task.WatchEvent) {
+	for _, line := range watchEventLines(ev) {
+		output.Infoln(line)
+	}
+}
+
+// watchEventLines formats a watch event as display lines; uninteresting events yield none.
+func watchEventLines(ev task.WatchEvent) []string {
 	prefix := "[watch]"
 	switch ev.Source {
 	case "ws":
@@ -376,14 +859,61 @@ func printWatchEvent(ev task.WatchEvent) {
 		prefix = "[system]"
 	}
 	if strings.TrimSpace(ev.Type) == "" {
-		return
+		return nil
+	}
+	if ev.Type == "connection" {
+		return []string{fmt.Sprintf("[status] %s", connectionLabel(ev.Text))}
 	}
-	fmt.Printf("%s %s\n", prefix, ev.Type)
-	if ev.Type == "warning" || ev.Type == "task_output" || ev.Type == "task_error" {
+	lines := []string{fmt.Sprintf("%s %s", prefix, ev.Type)}
+	if q := queueInfo(ev.Raw); q != "" {
+		lines[0] += " (" + q + ")"
+	}
+	if ev.Type == "warning" || ev.Type == "resume" || ev.Type == "task_output" || ev.Type == "task_error" {
 		if t := strings.TrimSpace(ev.Text); t != "" {
-			fmt.Printf("  %s\n", short(t, 180))
+			lines = append(lines, fmt.Sprintf("  %s", short(t, 180)))
 		}
 	}
+	return lines
+}
+
+// queueInfo formats the priority and queue position an event reports, at the
+// top level or inside its message.
+func queueInfo(raw map[string]interface{}) string {
+	if raw == nil {
+		return ""
+	}
+	fields := []map[string]interface{}{raw}
+	if msg, ok := raw["message"].(map[string]interface{}); ok {
+		fields = append(fields, msg)
+	}
+	var priority, position string
+	for _, m := range fields {
+		if v := strings.TrimSpace(fmt.Sprint(m["priority"])); m["priority"] != nil && v != "" && priority == "" {
+			priority = v
+		}
+		if v := strings.TrimSpace(fmt.Sprint(m["queueposition"])); m["queueposition"] != nil && v != "" && position == "" {
+			position = v
+		}
+	}
+	var parts []string
+	if priority != "" {
+		parts = append(parts, "priority "+priority)
+	}
+	if position != "" {
+		parts = append(parts, "queue position "+position)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func connectionLabel(state string) string {
+	switch state {
+	case task.ConnStateLive:
+		return "live (ws)"
+	case task.ConnStateDegraded:
+		return "degraded (polling)"
+	default:
+		return state
+	}
 }
 
 func tryRecoverMissingProjectSecret(app *App, profile *config.ProjectProfile, buildErr error) error {
@@ -398,7 +928,7 @@ func tryRecoverMissingProjectSecret(app *App, profile *config.ProjectProfile, bu
 		return buildErr
 	}
 
-	fmt.Printf("Project %s requires API secret.\n", profile.APIKey)
+	output.Printf("Project %s requires API secret.\n", profile.APIKey)
 	secret, err := promptSecret("API Secret for selected project")
 	if err != nil {
 		return err
@@ -407,6 +937,13 @@ func tryRecoverMissingProjectSecret(app *App, profile *config.ProjectProfile, bu
 	if secret == "" {
 		return buildErr
 	}
+	ok, err := confirmNewSecret(app, profile.APIKey, secret)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return buildErr
+	}
 
 	if err := app.AuthSvc.SaveProjectSecret(profile.APIKey, secret); err != nil {
 		return err
@@ -414,7 +951,22 @@ func tryRecoverMissingProjectSecret(app *App, profile *config.ProjectProfile, bu
 	profile.AuthMethodHint = "signature"
 	app.Config.UpsertProject(*profile)
 	_ = app.SaveConfig()
-	fmt.Println("API secret saved. Continuing...")
+	output.Infoln("API secret saved. Continuing...")
+	return nil
+}
+
+// checkMaxCost fails when the estimate is over --max-cost; zero disables the cap.
+func checkMaxCost(estimate float64, hasEstimate bool, maxCost float64) error {
+	if maxCost <= 0 {
+		return nil
+	}
+	if !hasEstimate {
+		output.Warnf("model does not publish a price; --max-cost not enforced")
+		return nil
+	}
+	if estimate > maxCost {
+		return fmt.Errorf("estimated cost %s exceeds --max-cost %s", formatCredits(estimate, ""), formatCredits(maxCost, ""))
+	}
 	return nil
 }
 
@@ -422,14 +974,23 @@ func ensureFirstRunSetup(app *App) error {
 	if len(app.Config.Projects) > 0 {
 		return nil
 	}
-	if app.AuthSvc.LoadBearerToken() != "" {
+	if app.AuthSvc.LoadBearerToken() != "" || app.AuthSvc.EnvCredentials().Mode() != "" {
 		return nil
 	}
 	if !isInteractiveSession() {
 		return errors.New("no credentials found. run `wiro auth set --api-key <key> --api-secret <secret>` first")
 	}
 
-	fmt.Println("First-time setup")
+	output.Println("First-time setup (run `wiro init` for the full wizard)")
+	if err := promptAPIKeySetup(app); err != nil {
+		return err
+	}
+	output.Infoln("Credentials saved. Continuing with project/model selection...")
+	return nil
+}
+
+// promptAPIKeySetup asks for a project key pair and stores it as a local project.
+func promptAPIKeySetup(app *App) error {
 	apiKey, err := promptInput("API Key", "")
 	if err != nil {
 		return err
@@ -444,6 +1005,13 @@ func ensureFirstRunSetup(app *App) error {
 	if strings.TrimSpace(apiSecret) == "" {
 		return errors.New("api secret is required")
 	}
+	ok, err := confirmNewSecret(app, apiKey, apiSecret)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("secret not saved; run the setup again to re-enter it")
+	}
 	name, err := promptInput("Project name (optional)", "default")
 	if err != nil {
 		return err
@@ -464,9 +1032,78 @@ func ensureFirstRunSetup(app *App) error {
 	if strings.TrimSpace(app.Config.DefaultProject) == "" {
 		app.Config.DefaultProject = apiKey
 	}
-	if err := app.SaveConfig(); err != nil {
-		return err
+	return app.SaveConfig()
+}
+
+// watchContext limits watching to timeout; zero means until the task finishes.
+func watchContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
 	}
-	fmt.Println("Credentials saved. Continuing with project/model selection...")
-	return nil
+	return context.WithTimeout(ctx, timeout)
+}
+
+// watchTimeoutError explains that only the watch stopped, not the task.
+// Run parameters that carry scheduling hints rather than model inputs.
+const (
+	gpuHintField      = "gputype"
+	priorityHintField = "priority"
+)
+
+// taskPriorities are the accepted --priority values.
+var taskPriorities = []string{"high", "normal", "low"}
+
+// parsePriority validates a --priority value; empty leaves the account default.
+func parsePriority(v string) (string, error) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if v == "" {
+		return "", nil
+	}
+	for _, p := range taskPriorities {
+		if v == p {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("invalid --priority %q (use %s)", v, strings.Join(taskPriorities, ", "))
+}
+
+// withRunHints returns inputs plus the --require-gpu and --priority hints that
+// are set; inputs is not modified.
+func withRunHints(inputs map[string][]api.MultipartValue, gpu, priority string) map[string][]api.MultipartValue {
+	hints := map[string]string{
+		gpuHintField:      strings.ToLower(strings.TrimSpace(gpu)),
+		priorityHintField: strings.ToLower(strings.TrimSpace(priority)),
+	}
+	out := make(map[string][]api.MultipartValue, len(inputs)+len(hints))
+	for k, v := range inputs {
+		out[k] = v
+	}
+	for k, v := range hints {
+		if v != "" {
+			out[k] = []api.MultipartValue{{Value: v}}
+		}
+	}
+	return out
+}
+
+// checkHardware warns when a task ran on other hardware than --require-gpu asked for.
+func checkHardware(t *api.Task, want string) {
+	want = strings.TrimSpace(want)
+	if want == "" || t == nil {
+		return
+	}
+	if strings.TrimSpace(t.GPUType) == "" {
+		log.Verbosef("task %s: the API did not report which GPU ran it", t.ID)
+		return
+	}
+	if !strings.EqualFold(t.GPUType, want) {
+		output.Warnf("requested GPU %s but task %s ran on %s", want, t.ID, t.GPUType)
+	}
+}
+
+func watchTimeoutError(ctx context.Context, err error, timeout time.Duration, taskID string) error {
+	if timeout > 0 && errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		return fmt.Errorf("stopped watching after %s; task %s keeps running (check it with `wiro task detail %s`): %w", timeout, taskID, taskID, err)
+	}
+	return err
 }
@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	"github.com/wiro-ai/wiro-cli/internal/runspec"
+)
+
+func modelRunSpecCommand(ctx context.Context, app *App, args []string) error {
+	usage := "usage: wiro model run-spec <export|import> ..."
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	switch strings.TrimSpace(args[0]) {
+	case "export":
+		return runSpecExportCommand(ctx, app, args[1:])
+	case "import":
+		return runSpecImportCommand(ctx, app, args[1:])
+	case "--help", "-h", "help":
+		output.Println("Usage: wiro model run-spec <export|import> ...")
+		return nil
+	default:
+		return fmt.Errorf("unknown run-spec command %q", args[0])
+	}
+}
+
+func runSpecExportCommand(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("model run-spec export", flag.ContinueOnError)
+	var setVals, setFileVals, setURLVals stringSlice
+	var outPath string
+	var fromHistory int
+	fs.Var(&setVals, "set", "Set field value (key=value). Repeatable")
+	fs.Var(&setFileVals, "set-file", "Set file input (key=/path/file). Repeatable")
+	fs.Var(&setURLVals, "set-url", "Set URL input (key=https://...). Repeatable")
+	fs.IntVar(&fromHistory, "from-history", 0, "Start from the inputs of this history entry")
+	fs.StringVar(&outPath, "output", "", "Write the spec to this file instead of stdout")
+	fs.StringVar(&outPath, "o", "", "Shorthand for --output")
+	usage := "usage: wiro model run-spec export <owner/model> [--set/--set-file/--set-url ...] [--from-history N] [-o run.yaml]"
+
+	var modelArg string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		modelArg, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() > 0 {
+		if modelArg != "" || fs.NArg() > 1 {
+			return errors.New(usage)
+		}
+		modelArg = fs.Arg(0)
+	}
+
+	job := runJob{Set: setVals, SetFile: setFileVals, SetURL: setURLVals}
+	if fromHistory > 0 {
+		entry, err := historyEntryArg([]string{fmt.Sprint(fromHistory)}, usage)
+		if err != nil {
+			return err
+		}
+		job.Owner, job.Model = entry.Owner, entry.Model
+		// Flags given now override the recorded values.
+		set, setFile, setURL := []string(setVals), []string(setFileVals), []string(setURLVals)
+		job.Set = append(overrideKeys(entry.Set, set, setFile, setURL), set...)
+		job.SetFile = append(overrideKeys(entry.SetFile, set, setFile, setURL), setFile...)
+		job.SetURL = append(overrideKeys(entry.SetURL, set, setFile, setURL), setURL...)
+	}
+	if modelArg != "" {
+		owner, slug, err := parseModelArg(modelArg)
+		if err != nil {
+			return err
+		}
+		if job.Model != "" && (owner != job.Owner || slug != job.Model) {
+			return fmt.Errorf("history entry #%d ran %s/%s, not %s", fromHistory, job.Owner, job.Model, modelArg)
+		}
+		job.Owner, job.Model = owner, slug
+	}
+	if job.Model == "" {
+		return errors.New(usage)
+	}
+
+	inputs, detail, err := jobInputs(ctx, app, job)
+	if err != nil {
+		return err
+	}
+	items := modelItems(detail, true)
+	inputs = withSchemaDefaults(items, inputs)
+
+	baseDir := "."
+	if outPath != "" {
+		baseDir = filepath.Dir(outPath)
+	}
+	spec, err := runspec.Build(job.Owner+"/"+job.Model, inputs, fileFields(items), baseDir, cliVersion())
+	if err != nil {
+		return err
+	}
+	if outPath == "" {
+		data, err := runspec.Encode(spec)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := runspec.Save(outPath, spec); err != nil {
+		return err
+	}
+	output.Printf("Wrote %s (%d params, %d file fields)\n", outPath, len(spec.Params), len(spec.Files))
+	output.Printf("Run it with: wiro run --spec %s\n", outPath)
+	return nil
+}
+
+func runSpecImportCommand(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("model run-spec import", flag.ContinueOnError)
+	var asJSON bool
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if err := requireArgs(fs.Args(), 1, "usage: wiro model run-spec import <file> [--json]"); err != nil {
+		return err
+	}
+	path := fs.Arg(0)
+	job, spec, err := loadRunSpec(path)
+	if err != nil {
+		return err
+	}
+	// Validate against the model's current schema without submitting.
+	if _, _, err := jobInputs(ctx, app, job); err != nil {
+		return fmt.Errorf("spec %s no longer matches %s: %w", path, spec.Model, err)
+	}
+	if asJSON {
+		return output.PrintJSON(map[string]interface{}{
+			"spec":  spec,
+			"valid": true,
+		})
+	}
+	output.Printf("Spec: %s (version %d", path, spec.SpecVersion)
+	if spec.CLIVersion != "" {
+		output.Printf(", exported by wiro %s", spec.CLIVersion)
+	}
+	output.Println(")")
+	output.Printf("Model: %s\n", spec.Model)
+	for _, kv := range append(append(append([]string{}, job.Set...), job.SetURL...), job.SetFile...) {
+		output.Printf("  %s\n", kv)
+	}
+	output.Println("Files match their recorded hashes; inputs are valid for the current schema.")
+	output.Printf("Run it with: wiro run --spec %s\n", path)
+	return nil
+}
+
+// loadRunSpec reads a spec file and resolves it into a job. Relative file paths
+// resolve against the spec's directory, and changed files are rejected.
+func loadRunSpec(path string) (runJob, *runspec.Spec, error) {
+	spec, err := runspec.Load(path)
+	if err != nil {
+		return runJob{}, nil, err
+	}
+	set, setFile, setURL, err := spec.Flags(filepath.Dir(path))
+	if err != nil {
+		return runJob{}, nil, fmt.Errorf("spec %s: %w", path, err)
+	}
+	owner, slug, err := parseModelArg(spec.Model)
+	if err != nil {
+		return runJob{}, nil, err
+	}
+	if cur := cliVersion(); spec.CLIVersion != "" && spec.CLIVersion != cur {
+		output.Warnf("%s was exported by wiro %s; this is %s", path, spec.CLIVersion, cur)
+	}
+	return runJob{Owner: owner, Model: slug, Set: set, SetFile: setFile, SetURL: setURL}, spec, nil
+}
+
+// withSchemaDefaults fills the schema default of every unset field so a spec
+// stays reproducible when a model's defaults change. Files and checkboxes are
+// left to the server, as with --quick.
+func withSchemaDefaults(items []api.ToolParameterItem, inputs map[string][]api.MultipartValue) map[string][]api.MultipartValue {
+	out := make(map[string][]api.MultipartValue, len(inputs))
+	for k, v := range inputs {
+		out[k] = v
+	}
+	for _, item := range items {
+		if _, ok := out[item.ID]; ok {
+			continue
+		}
+		def := strings.TrimSpace(defaultString(item.DefaultValue))
+		if def == "" {
+			continue
+		}
+		switch mapParameterKind(item.Type) {
+		case paramCombineFile, paramCheckbox:
+		default:
+			out[item.ID] = []api.MultipartValue{{Value: def}}
+		}
+	}
+	return out
+}
+
+// fileFields names the fields that take files.
+func fileFields(items []api.ToolParameterItem) map[string]bool {
+	out := map[string]bool{}
+	for _, item := range items {
+		if mapParameterKind(item.Type) == paramCombineFile {
+			out[item.ID] = true
+		}
+	}
+	return out
+}
+
+// overrideKeys drops the key=value pairs in base whose key is set in any of
+// overrides; callers append the overrides themselves.
+func overrideKeys(base []string, overrides ...[]string) []string {
+	replaced := map[string]bool{}
+	for _, list := range overrides {
+		for _, kv := range list {
+			k, _, _ := strings.Cut(kv, "=")
+			replaced[strings.TrimSpace(k)] = true
+		}
+	}
+	if len(replaced) == 0 {
+		return base
+	}
+	out := make([]string, 0, len(base))
+	for _, kv := range base {
+		k, _, _ := strings.Cut(kv, "=")
+		if !replaced[strings.TrimSpace(k)] {
+			out = append(out, kv)
+		}
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/model"
+	"github.com/wiro-ai/wiro-cli/internal/task"
+)
+
+// seedFlags are the --seed, --seed-sweep, and --reuse-seed options of wiro run.
+type seedFlags struct {
+	Seed  string
+	Sweep int
+	Reuse string
+}
+
+func (s *seedFlags) set() bool {
+	return s.Seed != "" || s.Sweep != 0 || s.Reuse != ""
+}
+
+// applySeedFlags finds the model's seed field and sets it: one seed with
+// --seed or --reuse-seed, or a sweep axis of --seed-sweep seeds, sequential
+// from --seed or random.
+func applySeedFlags(ctx context.Context, app *App, opts *runOptions, flags seedFlags) error {
+	if !flags.set() {
+		return nil
+	}
+	if flags.Seed != "" && flags.Reuse != "" {
+		return errors.New("--seed and --reuse-seed cannot be combined")
+	}
+	if flags.Sweep < 0 {
+		return errors.New("--seed-sweep must be at least 1")
+	}
+	start := strings.TrimSpace(flags.Seed)
+	if flags.Reuse != "" {
+		seed, taskModel, err := taskSeed(ctx, app, opts.Project, flags.Reuse)
+		if err != nil {
+			return err
+		}
+		if opts.Model == "" && taskModel != "" {
+			opts.Owner, opts.Model, _ = strings.Cut(taskModel, "/")
+		}
+		start = seed
+	}
+	if opts.Owner == "" || opts.Model == "" {
+		return errors.New("--seed, --seed-sweep, and --reuse-seed need a model argument")
+	}
+
+	detailCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	detail, err := app.ModelSvc.Detail(detailCtx, opts.Owner, opts.Model)
+	cancel()
+	if err != nil {
+		return err
+	}
+	item, ok := model.SeedField(modelItems(detail, true))
+	if !ok {
+		return fmt.Errorf("%s/%s has no seed parameter", opts.Owner, opts.Model)
+	}
+	for _, kv := range opts.Sweep {
+		if k, _, _ := strings.Cut(kv, "="); strings.TrimSpace(k) == item.ID {
+			return fmt.Errorf("field %q is set by both --sweep and the seed flags", item.ID)
+		}
+	}
+	// The seed flags override a seed from --set, a spec, or defaults.
+	opts.Set = overrideKeys(opts.Set, []string{item.ID + "="})
+
+	if flags.Sweep > 0 {
+		seeds, err := model.Seeds(item, start, flags.Sweep)
+		if err != nil {
+			return err
+		}
+		opts.Sweep = append(opts.Sweep, item.ID+"="+strings.Join(seeds, ","))
+		return nil
+	}
+	if _, err := model.ParseSeed(item, start); err != nil {
+		return err
+	}
+	opts.Set = append(opts.Set, item.ID+"="+start)
+	return nil
+}
+
+// taskSeed reads the seed and model of an earlier task (an id, token, or @last).
+func taskSeed(ctx context.Context, app *App, projectSelector, ref string) (string, string, error) {
+	target, err := taskTarget(app, []string{ref}, projectSelector)
+	if err != nil {
+		return "", "", err
+	}
+	headers, err := resolveRequestHeaders(app, projectSelector)
+	if err != nil {
+		return "", "", err
+	}
+	detailCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	resp, err := app.TaskSvc.Detail(detailCtx, target, headers)
+	cancel()
+	if err != nil {
+		return "", "", err
+	}
+	if len(resp.TaskList) == 0 {
+		return "", "", fmt.Errorf("task %s not found", ref)
+	}
+	t := resp.TaskList[0]
+	seed := model.SeedValue(inputsFromParameters(t.ParametersRaw))
+	if seed == "" {
+		return "", "", fmt.Errorf("task %s did not record a seed", t.ID)
+	}
+	return seed, task.ModelName(t), nil
+}
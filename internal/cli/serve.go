@@ -0,0 +1,261 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/metrics"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	"github.com/wiro-ai/wiro-cli/internal/task"
+)
+
+// maxGatewayUpload bounds the form and files of one POST /run request.
+const maxGatewayUpload = 512 << 20
+
+// gateway proxies local HTTP requests to the Wiro API with the CLI's credentials.
+type gateway struct {
+	app     *App
+	project string
+	// token must be sent as "Authorization: Bearer <token>".
+	token string
+}
+
+func serveCommand(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	g := &gateway{app: app}
+	var addr string
+	fs.StringVar(&addr, "addr", "127.0.0.1:8787", "Address to listen on")
+	fs.StringVar(&g.project, "project", "", "Project name or API key whose credentials sign the requests")
+	fs.StringVar(&g.token, "token", os.Getenv("WIRO_SERVE_TOKEN"), "Bearer token every request must send (default $WIRO_SERVE_TOKEN, else a random one printed at startup)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if len(fs.Args()) != 0 {
+		return errors.New("usage: wiro serve [--addr 127.0.0.1:8787] [--project <name|apikey>] [--token <token>]")
+	}
+	// Fail now rather than on the first request when credentials are missing.
+	if _, err := resolveRequestHeaders(app, g.project); err != nil {
+		return err
+	}
+	// A token is always required: without one, any web page could have the
+	// browser post a form to the gateway.
+	generated := g.token == ""
+	if generated {
+		buf := make([]byte, 16)
+		if _, err := rand.Read(buf); err != nil {
+			return err
+		}
+		g.token = hex.EncodeToString(buf)
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen on %s: %w", addr, err)
+	}
+	srv := &http.Server{Handler: g.handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	output.Notef("Gateway: http://%s (POST /run/{owner}/{model}, GET /task/{id}, GET /metrics)\n", ln.Addr())
+	if generated {
+		output.Alertf("Token: %s (send \"Authorization: Bearer <token>\"; set --token or WIRO_SERVE_TOKEN to keep one)\n", g.token)
+	}
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (g *gateway) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /run/{owner}/{model}", g.run)
+	mux.HandleFunc("GET /task/{id}", g.taskDetail)
+	mux.Handle("GET /metrics", metrics.Handler())
+	return g.authorize(mux)
+}
+
+func (g *gateway) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Browsers send Origin; scripts and notebooks do not. Refusing it
+		// keeps web pages from using the gateway even with a leaked token.
+		if r.Header.Get("Origin") != "" {
+			writeHTTPError(w, http.StatusForbidden, errors.New("requests from web pages are not accepted"))
+			return
+		}
+		got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if g.token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(g.token)) != 1 {
+			writeHTTPError(w, http.StatusUnauthorized, errors.New("missing or wrong bearer token"))
+			return
+		}
+		start := time.Now()
+		next.ServeHTTP(w, r)
+		output.Notef("%s %s (%s)\n", r.Method, r.URL.Path, time.Since(start).Round(time.Millisecond))
+	})
+}
+
+// run submits a task. The body is multipart/form-data, with file parts passed
+// through as uploads, or a JSON object or URL-encoded form of field values.
+// With ?wait=true the response is the final task instead of the submission.
+func (g *gateway) run(w http.ResponseWriter, r *http.Request) {
+	headers, err := resolveRequestHeaders(g.app, g.project)
+	if err != nil {
+		writeHTTPError(w, http.StatusInternalServerError, err)
+		return
+	}
+	tmp, err := os.MkdirTemp("", "wiro-serve-")
+	if err != nil {
+		writeHTTPError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer os.RemoveAll(tmp)
+	values, err := gatewayInputs(r, tmp)
+	if err != nil {
+		writeHTTPError(w, http.StatusBadRequest, err)
+		return
+	}
+	owner, slug := r.PathValue("owner"), r.PathValue("model")
+	resp, err := g.app.TaskSvc.Run(r.Context(), owner, slug, values, headers, task.RunOptions{})
+	if err != nil {
+		writeHTTPError(w, gatewayStatus(err), err)
+		return
+	}
+	if r.URL.Query().Get("wait") != "true" {
+		writeHTTPJSON(w, http.StatusOK, resp)
+		return
+	}
+	final, err := g.app.TaskSvc.WatchTask(r.Context(), resp.SocketAccessToken, headers, task.WatchOptions{TaskID: resp.TaskID})
+	if err != nil {
+		writeHTTPError(w, gatewayStatus(err), err)
+		return
+	}
+	writeHTTPJSON(w, http.StatusOK, final)
+}
+
+func (g *gateway) taskDetail(w http.ResponseWriter, r *http.Request) {
+	headers, err := resolveRequestHeaders(g.app, g.project)
+	if err != nil {
+		writeHTTPError(w, http.StatusInternalServerError, err)
+		return
+	}
+	resp, err := g.app.TaskSvc.Detail(r.Context(), r.PathValue("id"), headers)
+	if err != nil {
+		writeHTTPError(w, gatewayStatus(err), err)
+		return
+	}
+	writeHTTPJSON(w, http.StatusOK, resp)
+}
+
+// gatewayInputs reads the run fields of r. Uploaded files are saved below
+// dir under their own names, which the API sees.
+func gatewayInputs(r *http.Request, dir string) (map[string][]api.MultipartValue, error) {
+	r.Body = http.MaxBytesReader(nil, r.Body, maxGatewayUpload)
+	values := map[string][]api.MultipartValue{}
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		var fields map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+			return nil, fmt.Errorf("decode JSON body: %w", err)
+		}
+		for k, v := range fields {
+			vals, err := argumentStrings(v)
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %w", k, err)
+			}
+			for _, s := range vals {
+				values[k] = append(values[k], api.MultipartValue{Value: s})
+			}
+		}
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			return nil, fmt.Errorf("read form: %w", err)
+		}
+		for k, vals := range r.MultipartForm.Value {
+			for _, s := range vals {
+				values[k] = append(values[k], api.MultipartValue{Value: s})
+			}
+		}
+		for k, files := range r.MultipartForm.File {
+			for i, fh := range files {
+				path, err := saveFormFile(fh, filepath.Join(dir, fmt.Sprintf("%s-%d", k, i)))
+				if err != nil {
+					return nil, fmt.Errorf("file %q: %w", k, err)
+				}
+				values[k] = append(values[k], api.MultipartValue{FilePath: path})
+			}
+		}
+	case "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return nil, fmt.Errorf("read form: %w", err)
+		}
+		for k, vals := range r.PostForm {
+			for _, s := range vals {
+				values[k] = append(values[k], api.MultipartValue{Value: s})
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported content type %q (use multipart/form-data, application/json, or a URL-encoded form)", mediaType)
+	}
+	if len(values) == 0 {
+		return nil, errors.New("no fields in the request body")
+	}
+	return values, nil
+}
+
+// saveFormFile copies an uploaded part to dir, keeping its file name.
+func saveFormFile(fh *multipart.FileHeader, dir string) (string, error) {
+	src, err := fh.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, output.SafeName(filepath.Base(fh.Filename)))
+	dst, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return "", err
+	}
+	return path, dst.Close()
+}
+
+// gatewayStatus maps API errors to the HTTP status the gateway answers with.
+func gatewayStatus(err error) int {
+	switch {
+	case errors.Is(err, api.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, api.ErrInsufficientCredit):
+		return http.StatusPaymentRequired
+	case errors.Is(err, api.ErrModelNotFound), errors.Is(err, api.ErrTaskNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusBadGateway
+	}
+}
@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/auth"
+	"github.com/wiro-ai/wiro-cli/internal/task"
+)
+
+func TestGatewayRunPassesFilesThrough(t *testing.T) {
+	t.Setenv(auth.EnvAPIKey, "key-1")
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/Run/wiro/flux" || r.Header.Get("x-api-key") != "key-1" {
+			t.Errorf("upstream got %s with key %q", r.URL.Path, r.Header.Get("x-api-key"))
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("parse form: %v", err)
+		}
+		if got := r.FormValue("prompt"); got != "a fox" {
+			t.Errorf("prompt = %q", got)
+		}
+		fh := r.MultipartForm.File["image"]
+		if len(fh) != 1 || fh[0].Filename != "cat.png" {
+			t.Errorf("image parts = %v", fh)
+		} else {
+			f, _ := fh[0].Open()
+			data, _ := io.ReadAll(f)
+			f.Close()
+			if string(data) != "PNG" {
+				t.Errorf("image content = %q", data)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(api.RunResponse{GenericResponse: api.GenericResponse{Result: true}, TaskID: "5"})
+	}))
+	defer upstream.Close()
+
+	g := &gateway{app: &App{AuthSvc: auth.NewService(nil), TaskSvc: task.NewService(api.NewClient(upstream.URL))}, token: "s3cret"}
+	srv := httptest.NewServer(g.handler())
+	defer srv.Close()
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	_ = mw.WriteField("prompt", "a fox")
+	part, _ := mw.CreateFormFile("image", "cat.png")
+	_, _ = part.Write([]byte("PNG"))
+	_ = mw.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/run/wiro/flux", bytes.NewReader(body.Bytes()))
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("without the token = %d, want 401", resp.StatusCode)
+	}
+
+	req, _ = http.NewRequest(http.MethodPost, srv.URL+"/run/wiro/flux", bytes.NewReader(body.Bytes()))
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer s3cret")
+	req.Header.Set("Origin", "https://evil.example")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("with Origin = %d, want 403", resp.StatusCode)
+	}
+
+	req, _ = http.NewRequest(http.MethodPost, srv.URL+"/run/wiro/flux", bytes.NewReader(body.Bytes()))
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer s3cret")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got api.RunResponse
+	_ = json.NewDecoder(resp.Body).Decode(&got)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || got.TaskID != "5" {
+		t.Fatalf("POST /run = %d %+v", resp.StatusCode, got)
+	}
+
+	req, _ = http.NewRequest(http.MethodPost, srv.URL+"/run/wiro/flux", strings.NewReader("prompt"))
+	req.Header.Set("Content-Type", "text/plain")
+	req.Header.Set("Authorization", "Bearer s3cret")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("text body = %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestGatewayWithoutTokenRefusesAll(t *testing.T) {
+	g := &gateway{app: &App{}}
+	srv := httptest.NewServer(g.handler())
+	defer srv.Close()
+	resp, err := http.Post(srv.URL+"/run/wiro/flux", "application/x-www-form-urlencoded", strings.NewReader("prompt=a+fox"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("POST /run without a token configured = %d, want 401", resp.StatusCode)
+	}
+}
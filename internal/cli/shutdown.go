@@ -0,0 +1,150 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/history"
+	"github.com/wiro-ai/wiro-cli/internal/log"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+)
+
+// ErrTerminated is returned by Execute after SIGTERM; main exits with code 143.
+var ErrTerminated = errors.New("terminated by SIGTERM")
+
+// ExitError ends the process with Code instead of the usual 1, for commands
+// whose exit status carries a result, such as `wiro task wait`.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string { return e.Err.Error() }
+
+func (e *ExitError) Unwrap() error { return e.Err }
+
+// terminateGrace is how long a command may take to unwind after SIGTERM
+// before the process exits anyway.
+const terminateGrace = 10 * time.Second
+
+// inflightTask is a submitted task whose watch and downloads have not finished.
+type inflightTask struct {
+	TaskID    string
+	Model     string
+	OutputDir string
+}
+
+// inflightTasks records tasks that would keep running remotely if the CLI stopped.
+type inflightTasks struct {
+	mu    sync.Mutex
+	tasks map[string]inflightTask
+}
+
+var inflight = &inflightTasks{tasks: map[string]inflightTask{}}
+
+func (i *inflightTasks) add(t inflightTask) {
+	if t.TaskID == "" {
+		return
+	}
+	i.mu.Lock()
+	i.tasks[t.TaskID] = t
+	i.mu.Unlock()
+}
+
+// done forgets a task once its outcome is recorded.
+func (i *inflightTasks) done(taskID string) {
+	i.mu.Lock()
+	delete(i.tasks, taskID)
+	i.mu.Unlock()
+}
+
+func (i *inflightTasks) snapshot() []inflightTask {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	out := make([]inflightTask, 0, len(i.tasks))
+	for _, t := range i.tasks {
+		out = append(out, t)
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].TaskID < out[b].TaskID })
+	return out
+}
+
+// notifyTerminate cancels the command context on SIGTERM. The returned flush
+// reports whether SIGTERM arrived and, if so, records in-flight tasks and
+// prints how to reattach. If the command does not return within
+// terminateGrace, the process flushes and exits on its own.
+func notifyTerminate(app *App, cancel context.CancelFunc) (flush func() bool, stop func()) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM)
+	var terminated atomic.Bool
+	var once sync.Once
+	doFlush := func() { once.Do(func() { flushInflight(app) }) }
+	quit := make(chan struct{})
+	go func() {
+		select {
+		case <-sigCh:
+		case <-quit:
+			return
+		}
+		terminated.Store(true)
+		log.Verbosef("received SIGTERM; stopping")
+		cancel()
+		select {
+		case <-time.After(terminateGrace):
+			doFlush()
+			os.Exit(143)
+		case <-quit:
+		}
+	}()
+	flush = func() bool {
+		if !terminated.Load() {
+			return false
+		}
+		doFlush()
+		return true
+	}
+	stop = func() {
+		signal.Stop(sigCh)
+		close(quit)
+	}
+	return flush, stop
+}
+
+// flushInflight marks interrupted runs in history, saves state, and tells the
+// user how to pick the tasks up again.
+func flushInflight(app *App) {
+	tasks := inflight.snapshot()
+	if store, err := historyStore(); err == nil {
+		for _, t := range tasks {
+			err := store.UpdateTask(t.TaskID, func(e *history.Entry) {
+				e.Error = "interrupted by SIGTERM; the task may still be running"
+			})
+			if err != nil {
+				log.Verbosef("history: update task %s: %v", t.TaskID, err)
+			}
+		}
+	}
+	if err := app.SaveState(); err != nil {
+		log.Verbosef("save state: %v", err)
+	}
+	if len(tasks) == 0 {
+		return
+	}
+	output.Alertf("Received SIGTERM; %d task(s) may still be running remotely:\n", len(tasks))
+	for _, t := range tasks {
+		output.Alertf("  %s  %s\n", t.TaskID, t.Model)
+		output.Alertf("    status:    wiro task detail %s\n", t.TaskID)
+		if t.OutputDir != "" {
+			output.Alertf("    outputs:   wiro task download %s --output-dir %q\n", t.TaskID, t.OutputDir)
+		} else {
+			output.Alertf("    outputs:   wiro task download %s\n", t.TaskID)
+		}
+	}
+}
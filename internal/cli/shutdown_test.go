@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/history"
+)
+
+func TestFlushInflight_MarksInterruptedRuns(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmp, ".config"))
+
+	store, err := historyStore()
+	if err != nil {
+		t.Fatalf("history store: %v", err)
+	}
+	if _, err := store.Add(history.Entry{Owner: "o", Model: "m", TaskID: "77"}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := store.Add(history.Entry{Owner: "o", Model: "m", TaskID: "78"}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+
+	inflight.add(inflightTask{TaskID: "77", Model: "o/m"})
+	inflight.add(inflightTask{TaskID: "78", Model: "o/m"})
+	inflight.done("78")
+	defer inflight.done("77")
+	if got := inflight.snapshot(); len(got) != 1 || got[0].TaskID != "77" {
+		t.Fatalf("unexpected in-flight tasks %+v", got)
+	}
+
+	flushInflight(&App{})
+	entries, err := store.List()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	for _, e := range entries {
+		interrupted := strings.Contains(e.Error, "SIGTERM")
+		if interrupted != (e.TaskID == "77") {
+			t.Fatalf("task %s error = %q", e.TaskID, e.Error)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/history"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+)
+
+func statsCommand(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: wiro stats models ...")
+	}
+	sub := strings.TrimSpace(args[0])
+	switch sub {
+	case "models":
+		return statsModelsCommand(args[1:])
+	case "--help", "-h", "help":
+		output.Println("Usage: wiro stats models [--days N] [--limit N] [--json]")
+		return nil
+	default:
+		return fmt.Errorf("unknown stats command %q", sub)
+	}
+}
+
+func statsModelsCommand(args []string) error {
+	fs := flag.NewFlagSet("stats models", flag.ContinueOnError)
+	var asJSON bool
+	var days, limit int
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	fs.IntVar(&days, "days", 0, "Only count runs from the last N days (0 = all history)")
+	fs.IntVar(&limit, "limit", 20, "Number of models to show (0 = all)")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if fs.NArg() > 0 {
+		return errors.New("usage: wiro stats models [--days N] [--limit N] [--json]")
+	}
+	if days < 0 {
+		return errors.New("--days must not be negative")
+	}
+	store, err := historyStore()
+	if err != nil {
+		return err
+	}
+	entries, err := store.List()
+	if err != nil {
+		return err
+	}
+	var since time.Time
+	if days > 0 {
+		since = time.Now().AddDate(0, 0, -days)
+	}
+	stats := history.ModelStats(entries, since)
+	if limit > 0 && len(stats) > limit {
+		stats = stats[:limit]
+	}
+	if asJSON {
+		return output.PrintJSON(stats)
+	}
+	if len(stats) == 0 {
+		output.Println("No runs recorded yet.")
+		return nil
+	}
+	t := output.NewTable("MODEL", "RUNS", "FAILED", "FAIL RATE", "AVG TIME", "LAST USED")
+	for _, s := range stats {
+		avg := "-"
+		if s.AvgSeconds > 0 {
+			avg = time.Duration(s.AvgSeconds * float64(time.Second)).Round(time.Second).String()
+		}
+		rate := "-"
+		if s.Succeeded+s.Failed > 0 {
+			rate = fmt.Sprintf("%.0f%%", s.FailureRate*100)
+		}
+		t.Row(s.Model, strconv.Itoa(s.Runs), strconv.Itoa(s.Failed), rate, avg, lastUsed(s.LastUsed))
+	}
+	return t.Print()
+}
+
+// lastUsed renders an RFC3339 timestamp as a local date plus how long ago it was.
+func lastUsed(ts string) string {
+	t, err := time.Parse(time.RFC3339, ts)
+	if err != nil {
+		return output.Dash(ts)
+	}
+	days := int(time.Since(t).Hours() / 24)
+	ago := "today"
+	switch {
+	case days == 1:
+		ago = "1 day ago"
+	case days > 1:
+		ago = fmt.Sprintf("%d days ago", days)
+	}
+	return t.Local().Format("2006-01-02") + " (" + ago + ")"
+}
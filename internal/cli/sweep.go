@@ -0,0 +1,262 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/jsonout"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+)
+
+// maxSweepRuns guards against an accidental cartesian explosion.
+const maxSweepRuns = 256
+
+// sweepAxis is one --sweep key with the values to try.
+type sweepAxis struct {
+	Key    string
+	Values []string
+}
+
+// sweepCombo is one point of the cartesian product, in axis order.
+type sweepCombo []sweepValue
+
+type sweepValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// parseSweeps parses repeated `key=v1,v2,...` flags. Repeating a key adds values to it.
+func parseSweeps(values []string) ([]sweepAxis, error) {
+	var axes []sweepAxis
+	index := map[string]int{}
+	for _, raw := range values {
+		idx := strings.Index(raw, "=")
+		if idx <= 0 {
+			return nil, fmt.Errorf("invalid --sweep format %q (expected key=value1,value2)", raw)
+		}
+		key := strings.TrimSpace(raw[:idx])
+		var vals []string
+		for _, v := range strings.Split(raw[idx+1:], ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				vals = append(vals, v)
+			}
+		}
+		if len(vals) == 0 {
+			return nil, fmt.Errorf("--sweep %s has no values", key)
+		}
+		if i, ok := index[key]; ok {
+			axes[i].Values = append(axes[i].Values, vals...)
+			continue
+		}
+		index[key] = len(axes)
+		axes = append(axes, sweepAxis{Key: key, Values: vals})
+	}
+	return axes, nil
+}
+
+// expandSweeps returns the cartesian product of all axes; the last axis varies fastest.
+func expandSweeps(axes []sweepAxis) []sweepCombo {
+	combos := []sweepCombo{{}}
+	for _, axis := range axes {
+		next := make([]sweepCombo, 0, len(combos)*len(axis.Values))
+		for _, c := range combos {
+			for _, v := range axis.Values {
+				combo := append(append(sweepCombo{}, c...), sweepValue{Key: axis.Key, Value: v})
+				next = append(next, combo)
+			}
+		}
+		combos = next
+	}
+	return combos
+}
+
+// Label renders the combination as `key=value key2=value2`.
+func (c sweepCombo) Label() string {
+	parts := make([]string, 0, len(c))
+	for _, v := range c {
+		parts = append(parts, v.Key+"="+v.Value)
+	}
+	return strings.Join(parts, " ")
+}
+
+// dirName is the per-combination output subdirectory; the index keeps truncated names unique.
+func (c sweepCombo) dirName(index, total int) string {
+	width := len(fmt.Sprint(total))
+	parts := []string{fmt.Sprintf("%0*d", width, index+1)}
+	for _, v := range c {
+		parts = append(parts, v.Key+"-"+compactRunes(v.Value, 40))
+	}
+	return output.SafeName(strings.Join(parts, "_"))
+}
+
+func compactRunes(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
+
+// sweepResult is the outcome of one combination.
+type sweepResult struct {
+	Params    []sweepValue `json:"params"`
+	OutputDir string       `json:"outputDir"`
+	TaskID    string       `json:"taskId,omitempty"`
+	Status    string       `json:"status,omitempty"`
+	Outputs   []string     `json:"outputs,omitempty"`
+	Error     string       `json:"error,omitempty"`
+}
+
+// runSweep submits one task per sweep combination with bounded concurrency and
+// saves each combination's outputs in its own subdirectory of opts.OutputDir.
+func runSweep(ctx context.Context, app *App, opts runOptions, axes []sweepAxis, parallel int) error {
+	if parallel < 1 {
+		return errors.New("--sweep-parallel must be at least 1")
+	}
+	for _, axis := range axes {
+		for _, kv := range opts.Set {
+			if strings.TrimSpace(strings.SplitN(kv, "=", 2)[0]) == axis.Key {
+				return fmt.Errorf("field %q is set by both --set and --sweep", axis.Key)
+			}
+		}
+	}
+	combos := expandSweeps(axes)
+	if len(combos) > maxSweepRuns {
+		return fmt.Errorf("--sweep expands to %d runs (limit %d)", len(combos), maxSweepRuns)
+	}
+	if err := ensureFirstRunSetup(app); err != nil {
+		return err
+	}
+	if _, _, err := resolveProject(ctx, app, opts.Project); err != nil {
+		return err
+	}
+	owner, slug, err := resolveModel(ctx, app, opts.Owner, opts.Model)
+	if err != nil {
+		return err
+	}
+	set := append([]string{}, opts.Set...)
+	if key := strings.TrimSpace(opts.SetStdin); key != "" {
+		val, err := readStdinValue()
+		if err != nil {
+			return err
+		}
+		set = append(set, key+"="+val)
+	}
+
+	human := !opts.JSON && !opts.PrintPaths
+	if human {
+		output.Infof("Sweep: %s/%s, %d runs, %d at a time\n", owner, slug, len(combos), parallel)
+	}
+
+	results := make([]sweepResult, len(combos))
+	for i, combo := range combos {
+		results[i] = sweepResult{Params: combo, Error: "not started"}
+	}
+	var printMu sync.Mutex
+	logf := func(format string, args ...interface{}) {
+		if !human {
+			return
+		}
+		printMu.Lock()
+		defer printMu.Unlock()
+		output.Infof(format, args...)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < parallel; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				combo := combos[i]
+				tag := fmt.Sprintf("[sweep %d/%d]", i+1, len(combos))
+				job := runJob{
+					Project:       opts.Project,
+					Owner:         owner,
+					Model:         slug,
+					Set:           append([]string{}, set...),
+					SetFile:       opts.SetFile,
+					SetURL:        opts.SetURL,
+					OutputDir:     filepath.Join(opts.OutputDir, combo.dirName(i, len(combos))),
+					Timeout:       opts.Timeout,
+					Outputs:       opts.Outputs,
+					Dedupe:        opts.Dedupe,
+					Naming:        opts.Naming,
+					Notify:        &opts.Notify,
+					Git:           opts.Git,
+					NoBudgetCheck: opts.NoBudgetCheck,
+					MaxCost:       opts.MaxCost,
+					CreditCheck:   !opts.NoCreditCheck,
+					RequireGPU:    opts.RequireGPU,
+					Priority:      opts.Priority,
+					SecretFields:  opts.SecretFields,
+					Media:         opts.Media,
+					Reupload:      opts.Reupload,
+				}
+				for _, v := range combo {
+					job.Set = append(job.Set, v.Key+"="+v.Value)
+				}
+				res := sweepResult{Params: combo, OutputDir: app.ResolveOutputDir(job.OutputDir)}
+				logf("%s %s starting\n", tag, combo.Label())
+				out, err := executeRunJob(ctx, app, job, runJobHooks{
+					OnSubmitted: func(resp api.RunResponse) {
+						res.TaskID = resp.TaskID
+						logf("%s %s submitted taskid=%s\n", tag, combo.Label(), resp.TaskID)
+					},
+				})
+				if out.Task != nil {
+					res.Status = out.Task.Status
+				}
+				res.Outputs = out.Paths
+				if err != nil {
+					res.Error = err.Error()
+					logf("%s %s failed: %v\n", tag, combo.Label(), err)
+				} else {
+					logf("%s %s done (%d files) -> %s\n", tag, combo.Label(), len(out.Paths), res.OutputDir)
+				}
+				results[i] = res
+			}
+		}()
+	}
+	for i := range combos {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	switch {
+	case opts.JSON:
+		_ = jsonout.Print(results)
+	case opts.PrintPaths:
+		for _, r := range results {
+			for _, p := range r.Outputs {
+				output.Println(p)
+			}
+		}
+	default:
+		output.Printf("Sweep finished: done=%d failed=%d\n", len(results)-failed, failed)
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d sweep run(s) failed", failed, len(results))
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/account"
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/auth"
+	"github.com/wiro-ai/wiro-cli/internal/model"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	"github.com/wiro-ai/wiro-cli/internal/task"
+)
+
+func TestExpandSweeps_CartesianProduct(t *testing.T) {
+	axes, err := parseSweeps([]string{"seed=1,2", "prompt=a cat, a dog", "seed=3"})
+	if err != nil {
+		t.Fatalf("parseSweeps: %v", err)
+	}
+	combos := expandSweeps(axes)
+	if len(combos) != 6 {
+		t.Fatalf("expected 6 combinations, got %d", len(combos))
+	}
+	if got := combos[0].Label(); got != "seed=1 prompt=a cat" {
+		t.Fatalf("first combo = %q", got)
+	}
+	if got := combos[5].Label(); got != "seed=3 prompt=a dog" {
+		t.Fatalf("last combo = %q", got)
+	}
+	if dir := combos[1].dirName(1, 6); dir != "2_seed-1_prompt-a dog" {
+		t.Fatalf("dirName = %q", dir)
+	}
+}
+
+func TestParseSweeps_Errors(t *testing.T) {
+	for _, in := range []string{"seed", "=1,2", "seed= , "} {
+		if _, err := parseSweeps([]string{in}); err == nil || !strings.Contains(err.Error(), "sweep") {
+			t.Fatalf("parseSweeps(%q) err = %v", in, err)
+		}
+	}
+}
+
+func TestRunSweep_MaxCost(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmp, ".config"))
+	t.Setenv(auth.EnvAPIKey, "key-1")
+
+	var runs atomic.Int32
+	var submittedSteps atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/Tool/Detail":
+			_ = json.NewEncoder(w).Encode(api.ToolDetailResponse{
+				GenericResponse: api.GenericResponse{Result: true},
+				Tools: []api.ToolDetail{{
+					SlugOwner:    "acme",
+					SlugProject:  "gen",
+					Parameters:   []api.ToolParameterGroup{{Items: []api.ToolParameterItem{{ID: "steps", Type: "text"}}}},
+					DynamicPrice: `[{"inputs":{},"price":0.1,"priceMethod":"` + model.PricePerStep + `"}]`,
+				}},
+			})
+		case strings.HasPrefix(r.URL.Path, "/Run/"):
+			runs.Add(1)
+			submittedSteps.Store(r.FormValue("steps"))
+			http.Error(w, "stop here", http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client := api.NewClient(srv.URL)
+	authSvc := auth.NewService(client)
+	app := &App{
+		APIClient:  client,
+		AccountSvc: account.NewService(client),
+		AuthSvc:    authSvc,
+		ModelSvc:   model.NewService(client),
+		TaskSvc:    task.NewService(client),
+	}
+	std := output.Std()
+	var out bytes.Buffer
+	prevOut, prevErr := std.Out, std.Err
+	std.Out, std.Err = &out, io.Discard
+	defer func() { std.Out, std.Err = prevOut, prevErr }()
+
+	opts := runOptions{Owner: "acme", Model: "gen", OutputDir: tmp, MaxCost: 3, NoCreditCheck: true}
+	axes := []sweepAxis{{Key: "steps", Values: []string{"20", "50"}}}
+	if err := runSweep(context.Background(), app, opts, axes, 1); err == nil {
+		t.Fatal("sweep succeeded")
+	}
+	if n := runs.Load(); n != 1 {
+		t.Fatalf("submitted %d runs, want only the one under --max-cost", n)
+	}
+	if got := submittedSteps.Load(); got != "20" {
+		t.Fatalf("submitted steps=%v, want 20", got)
+	}
+	if !strings.Contains(out.String(), "steps=50 failed: estimated cost 5 exceeds --max-cost 3") {
+		t.Fatalf("output does not report the cap:\n%s", out.String())
+	}
+}
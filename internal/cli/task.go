@@ -2,30 +2,44 @@ package cli
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/auth"
+	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/jsonout"
 	"github.com/wiro-ai/wiro-cli/internal/output"
 	projectsvc "github.com/wiro-ai/wiro-cli/internal/project"
+	"github.com/wiro-ai/wiro-cli/internal/task"
 )
 
 func taskCommand(ctx context.Context, app *App, args []string) error {
 	if len(args) == 0 {
-		return errors.New("usage: wiro task <detail|cancel|kill> ...")
+		return errors.New("usage: wiro task <detail|outputs|download|wait|events|cancel|kill> ...")
 	}
 	sub := strings.TrimSpace(args[0])
 	switch sub {
 	case "detail":
 		return taskDetailCommand(ctx, app, args[1:])
+	case "outputs":
+		return taskOutputsCommand(ctx, app, args[1:])
+	case "download":
+		return taskDownloadCommand(ctx, app, args[1:])
+	case "wait":
+		return taskWaitCommand(ctx, app, args[1:])
+	case "events":
+		return taskEventsCommand(app, args[1:])
 	case "cancel":
 		return taskCancelCommand(ctx, app, args[1:])
 	case "kill":
 		return taskKillCommand(ctx, app, args[1:])
 	case "--help", "-h", "help":
-		fmt.Println("Usage: wiro task <detail|cancel|kill> ...")
+		output.Println("Usage: wiro task <detail|outputs|download|wait|events|cancel|kill> ...")
 		return nil
 	default:
 		return fmt.Errorf("unknown task command %q", sub)
@@ -38,27 +52,73 @@ func taskDetailCommand(ctx context.Context, app *App, args []string) error {
 	var asJSON bool
 	fs.StringVar(&projectSelector, "project", "", "Project name or API key for auth context")
 	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	showSchema := jsonSchemaFlag(fs)
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
 			return nil
 		}
 		return err
 	}
+	if *showSchema {
+		return printJSONSchema("task detail")
+	}
 	rest := fs.Args()
 	if len(rest) > 1 {
-		return errors.New("usage: wiro task detail <taskid|tasktoken>")
+		return errors.New("usage: wiro task detail [taskid|tasktoken|@last]")
+	}
+
+	target, err := taskTarget(app, rest, projectSelector)
+	if err != nil {
+		return err
 	}
 
-	target := ""
-	if len(rest) == 1 {
-		target = rest[0]
-	} else if app.State.LastTaskToken != "" {
-		target = app.State.LastTaskToken
-	} else if app.State.LastTaskID != "" {
-		target = app.State.LastTaskID
+	headers, err := resolveRequestHeaders(app, projectSelector)
+	if err != nil {
+		return err
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	resp, err := app.TaskSvc.Detail(timeoutCtx, target, headers)
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return jsonout.Print(resp)
 	}
-	if target == "" {
-		return errors.New("task id/token is required")
+	if len(resp.TaskList) == 0 {
+		return errors.New("task not found")
+	}
+	t := &resp.TaskList[0]
+	output.PrintTask(t)
+	if phases := phaseSummary(taskPhases(t, app.ResolveOutputDir(app.OutputDirDefault()))); phases != "" {
+		output.Printf("Phases: %s\n", phases)
+	}
+	return nil
+}
+
+func taskOutputsCommand(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("task outputs", flag.ContinueOnError)
+	var projectSelector string
+	var asJSON bool
+	fs.StringVar(&projectSelector, "project", "", "Project name or API key for auth context")
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	showSchema := jsonSchemaFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *showSchema {
+		return printJSONSchema("task outputs")
+	}
+	rest := fs.Args()
+	if len(rest) > 1 {
+		return errors.New("usage: wiro task outputs [taskid|tasktoken|@last]")
+	}
+	target, err := taskTarget(app, rest, projectSelector)
+	if err != nil {
+		return err
 	}
 
 	headers, err := resolveRequestHeaders(app, projectSelector)
@@ -71,30 +131,233 @@ func taskDetailCommand(ctx context.Context, app *App, args []string) error {
 	if err != nil {
 		return err
 	}
+	if len(resp.TaskList) == 0 {
+		return errors.New("task not found")
+	}
+	infos := output.InspectOutputs(timeoutCtx, &resp.TaskList[0], output.DownloadOptions{
+		Client:  app.APIClient,
+		Headers: headers,
+	})
 	if asJSON {
-		return output.PrintJSON(resp)
+		return jsonout.Print(infos)
+	}
+	if len(infos) == 0 {
+		output.Printf("Task %s has no outputs (status %s).\n", resp.TaskList[0].ID, resp.TaskList[0].Status)
+		return nil
+	}
+	output.PrintOutputs(infos)
+	return nil
+}
+
+func taskDownloadCommand(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("task download", flag.ContinueOnError)
+	var projectSelector, outputDir string
+	var overwrite, asJSON, printPaths bool
+	var selection outputSelection
+	fs.StringVar(&projectSelector, "project", "", "Project name or API key for auth context")
+	fs.StringVar(&outputDir, "output-dir", app.Config.Preferences.OutputDirDefault, "Directory to save outputs")
+	fs.BoolVar(&overwrite, "overwrite", false, "Download again even if files already exist")
+	var dedupe bool
+	fs.BoolVar(&dedupe, "dedupe", app.Config.Preferences.DedupeOutputs, "Reuse identical, already downloaded files")
+	var nameTemplate string
+	fs.StringVar(&nameTemplate, "name-template", "", "Output layout below --output-dir (default preferences.outputNameTemplate)")
+	selection.register(fs)
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	fs.BoolVar(&printPaths, "print-paths", false, "Print only downloaded file paths on stdout")
+	showSchema := jsonSchemaFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *showSchema {
+		return printJSONSchema("task download")
+	}
+	rest := fs.Args()
+	if len(rest) > 1 {
+		return errors.New("usage: wiro task download [taskid|tasktoken|@last] [--output-dir <path>] [--overwrite]")
+	}
+	target, err := taskTarget(app, rest, projectSelector)
+	if err != nil {
+		return err
+	}
+	filter, err := selection.filter()
+	if err != nil {
+		return err
+	}
+	naming, err := outputNaming(app, nameTemplate)
+	if err != nil {
+		return err
+	}
+
+	headers, err := resolveRequestHeaders(app, projectSelector)
+	if err != nil {
+		return err
+	}
+	detailCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	resp, err := app.TaskSvc.Detail(detailCtx, target, headers)
+	cancel()
+	if err != nil {
+		return err
 	}
 	if len(resp.TaskList) == 0 {
 		return errors.New("task not found")
 	}
-	output.PrintTask(&resp.TaskList[0])
+	t := &resp.TaskList[0]
+	if len(t.Outputs) == 0 {
+		if !task.IsTerminal(t.Status) {
+			return fmt.Errorf("task %s has no outputs yet (status %s)", t.ID, t.Status)
+		}
+		return fmt.Errorf("task %s finished without outputs (status %s)", t.ID, t.Status)
+	}
+
+	dl := runDownloadOptions(app, filter, dedupe, naming)
+	dl.Overwrite = overwrite
+	dl.OnProgress = output.DownloadProgressBars()
+	paths, manifestPath, err := saveTaskOutputs(ctx, app, t, outputDir, dl, task.ModelName(*t), inputsFromParameters(t.ParametersRaw), headers, nil)
+	if err != nil {
+		return err
+	}
+	switch {
+	case asJSON:
+		return jsonout.Print(taskDownloadResult{TaskID: t.ID, Paths: paths, Manifest: manifestPath})
+	case printPaths:
+		for _, p := range paths {
+			output.Println(p)
+		}
+	default:
+		output.Println("Downloaded files:")
+		for _, p := range paths {
+			output.Printf("- %s\n", p)
+		}
+		output.Printf("Manifest: %s\n", manifestPath)
+	}
 	return nil
 }
 
+// inputsFromParameters recovers run inputs from a task's stored parameters so
+// re-downloads get the same prompt-based names. It accepts an object, a JSON
+// string holding one, or a list of {name|key, value} entries.
+func inputsFromParameters(raw json.RawMessage) map[string][]api.MultipartValue {
+	out := map[string][]api.MultipartValue{}
+	if len(raw) == 0 {
+		return out
+	}
+	var encoded string
+	if json.Unmarshal(raw, &encoded) == nil {
+		raw = json.RawMessage(encoded)
+	}
+	var obj map[string]interface{}
+	if json.Unmarshal(raw, &obj) == nil {
+		for k, v := range obj {
+			out[k] = append(out[k], api.MultipartValue{Value: parameterString(v)})
+		}
+		return out
+	}
+	var list []map[string]interface{}
+	if json.Unmarshal(raw, &list) == nil {
+		for _, entry := range list {
+			key, _ := entry["name"].(string)
+			if key == "" {
+				key, _ = entry["key"].(string)
+			}
+			if key != "" {
+				out[key] = append(out[key], api.MultipartValue{Value: parameterString(entry["value"])})
+			}
+		}
+	}
+	return out
+}
+
+func parameterString(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	case float64, bool:
+		return fmt.Sprint(x)
+	default:
+		b, _ := json.Marshal(x)
+		return string(b)
+	}
+}
+
+// lastTaskRef names the most recent task of the active project.
+const lastTaskRef = "@last"
+
+// taskTarget picks the task id/token argument. With no argument, or @last, it is
+// the last task submitted in the active auth context, so switching projects
+// never points a command at another project's task.
+func taskTarget(app *App, rest []string, projectSelector string) (string, error) {
+	if len(rest) == 1 && rest[0] != lastTaskRef {
+		return rest[0], nil
+	}
+	t, err := lastTask(app, projectSelector)
+	if err != nil {
+		if len(rest) == 0 {
+			return "", errors.New("task id/token is required")
+		}
+		return "", err
+	}
+	if t.TaskToken != "" {
+		return t.TaskToken, nil
+	}
+	return t.TaskID, nil
+}
+
+// taskIDArg resolves a cancel or kill argument, which must be a task id.
+func taskIDArg(app *App, arg, projectSelector string) (string, error) {
+	if arg != lastTaskRef {
+		return arg, nil
+	}
+	t, err := lastTask(app, projectSelector)
+	if err != nil {
+		return "", err
+	}
+	return t.TaskID, nil
+}
+
+func lastTask(app *App, projectSelector string) (config.RecentTask, error) {
+	t, ok := app.State.LastTask(config.TaskContext(activeProfile(app, projectSelector)))
+	if !ok {
+		return config.RecentTask{}, errors.New("no task has been run in this project yet; pass a task id")
+	}
+	return t, nil
+}
+
+// activeProfile is the project commands run under: --project, then WIRO_API_KEY,
+// then the default project.
+func activeProfile(app *App, projectSelector string) *config.ProjectProfile {
+	if env := app.AuthSvc.EnvCredentials(); env.APIKey != "" && strings.TrimSpace(projectSelector) == "" {
+		return &config.ProjectProfile{Name: auth.EnvAPIKey, APIKey: env.APIKey}
+	}
+	return projectsvc.ResolveSelected(app.Config, projectSelector)
+}
+
 func taskCancelCommand(ctx context.Context, app *App, args []string) error {
 	fs := flag.NewFlagSet("task cancel", flag.ContinueOnError)
 	var projectSelector string
 	var asJSON bool
 	fs.StringVar(&projectSelector, "project", "", "Project name or API key for auth context")
 	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	showSchema := jsonSchemaFlag(fs)
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
 			return nil
 		}
 		return err
 	}
+	if *showSchema {
+		return printJSONSchema("task cancel")
+	}
 	rest := fs.Args()
-	if err := requireArgs(rest, 1, "usage: wiro task cancel <taskid>"); err != nil {
+	if err := requireArgs(rest, 1, "usage: wiro task cancel <taskid|@last>"); err != nil {
+		return err
+	}
+	taskID, err := taskIDArg(app, rest[0], projectSelector)
+	if err != nil {
 		return err
 	}
 
@@ -104,15 +367,15 @@ func taskCancelCommand(ctx context.Context, app *App, args []string) error {
 	}
 	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
-	resp, err := app.TaskSvc.Cancel(timeoutCtx, rest[0], headers)
+	resp, err := app.TaskSvc.Cancel(timeoutCtx, taskID, headers)
 	if err != nil {
 		return err
 	}
 	if asJSON {
-		return output.PrintJSON(resp)
+		return jsonout.Print(resp)
 	}
 	if len(resp.TaskList) == 0 {
-		fmt.Println("Task cancel request sent.")
+		output.Println("Task cancel request sent.")
 		return nil
 	}
 	output.PrintTask(&resp.TaskList[0])
@@ -125,14 +388,22 @@ func taskKillCommand(ctx context.Context, app *App, args []string) error {
 	var asJSON bool
 	fs.StringVar(&projectSelector, "project", "", "Project name or API key for auth context")
 	fs.BoolVar(&asJSON, "json", false, "JSON output")
+	showSchema := jsonSchemaFlag(fs)
 	if err := fs.Parse(args); err != nil {
 		if errors.Is(err, flag.ErrHelp) {
 			return nil
 		}
 		return err
 	}
+	if *showSchema {
+		return printJSONSchema("task kill")
+	}
 	rest := fs.Args()
-	if err := requireArgs(rest, 1, "usage: wiro task kill <taskid>"); err != nil {
+	if err := requireArgs(rest, 1, "usage: wiro task kill <taskid|@last>"); err != nil {
+		return err
+	}
+	taskID, err := taskIDArg(app, rest[0], projectSelector)
+	if err != nil {
 		return err
 	}
 
@@ -142,15 +413,15 @@ func taskKillCommand(ctx context.Context, app *App, args []string) error {
 	}
 	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
-	resp, err := app.TaskSvc.Kill(timeoutCtx, rest[0], headers)
+	resp, err := app.TaskSvc.Kill(timeoutCtx, taskID, headers)
 	if err != nil {
 		return err
 	}
 	if asJSON {
-		return output.PrintJSON(resp)
+		return jsonout.Print(resp)
 	}
 	if len(resp.TaskList) == 0 {
-		fmt.Println("Task kill request sent.")
+		output.Println("Task kill request sent.")
 		return nil
 	}
 	output.PrintTask(&resp.TaskList[0])
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/auth"
+	"github.com/wiro-ai/wiro-cli/internal/config"
+)
+
+func TestTaskTargetUsesActiveProject(t *testing.T) {
+	t.Setenv(auth.EnvAPIKey, "")
+	app := &App{
+		AuthSvc: auth.NewService(nil),
+		Config: config.Config{
+			DefaultProject: "a",
+			Projects:       []config.ProjectProfile{{Name: "a", APIKey: "key-a"}, {Name: "b", APIKey: "key-b"}},
+		},
+	}
+	app.State.SetLastTask(config.TaskContext(&app.Config.Projects[0]), config.RecentTask{TaskID: "1", TaskToken: "tok-1"})
+
+	if got, err := taskTarget(app, nil, ""); err != nil || got != "tok-1" {
+		t.Fatalf("taskTarget(default) = %q, %v", got, err)
+	}
+	if got, err := taskTarget(app, []string{"@last"}, "a"); err != nil || got != "tok-1" {
+		t.Fatalf("taskTarget(@last, a) = %q, %v", got, err)
+	}
+	if _, err := taskTarget(app, []string{"@last"}, "b"); err == nil {
+		t.Fatal("project b resolved project a's last task")
+	}
+	if got, err := taskIDArg(app, "@last", ""); err != nil || got != "1" {
+		t.Fatalf("taskIDArg(@last) = %q, %v", got, err)
+	}
+	if got, err := taskTarget(app, []string{"99"}, "b"); err != nil || got != "99" {
+		t.Fatalf("taskTarget(99) = %q, %v", got, err)
+	}
+}
+
+func TestTaskExitError(t *testing.T) {
+	if err := taskExitError(&api.Task{ID: "1", Status: "task_postprocess_end"}); err != nil {
+		t.Fatalf("success should not error: %v", err)
+	}
+	for status, code := range map[string]int{"task_cancel": exitTaskCancelled, "task_error_full": exitTaskFailed} {
+		err := withHint(taskExitError(&api.Task{ID: "1", Status: status}))
+		var exit *ExitError
+		if !errors.As(err, &exit) || exit.Code != code {
+			t.Fatalf("%s: err = %v, want exit code %d", status, err, code)
+		}
+	}
+}
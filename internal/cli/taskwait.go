@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/jsonout"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	"github.com/wiro-ai/wiro-cli/internal/task"
+)
+
+// Exit codes of `wiro task wait` besides 0 (succeeded) and 1 (CLI or API error).
+const (
+	exitTaskFailed    = 2
+	exitTaskCancelled = 3
+	// exitWaitTimeout matches timeout(1).
+	exitWaitTimeout = 124
+)
+
+// taskWaitResult is the --json output of wiro task wait.
+type taskWaitResult struct {
+	Task *api.Task `json:"task"`
+	// Paths and Manifest are set with --download.
+	Paths    []string `json:"paths,omitempty"`
+	Manifest string   `json:"manifest,omitempty"`
+}
+
+func taskWaitCommand(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("task wait", flag.ContinueOnError)
+	var projectSelector, outputDir, nameTemplate string
+	var timeout, interval time.Duration
+	var download, asJSON bool
+	var selection outputSelection
+	var notifyOpts notifyFlags
+	fs.StringVar(&projectSelector, "project", "", "Project name or API key for auth context")
+	fs.DurationVar(&timeout, "timeout", 0, "Give up after this long and exit 124, e.g. 30m (0 = no limit)")
+	fs.DurationVar(&interval, "poll-interval", 5*time.Second, "How often task detail is polled alongside the websocket")
+	fs.BoolVar(&download, "download", false, "Download the outputs when the task succeeds")
+	fs.StringVar(&outputDir, "output-dir", app.Config.Preferences.OutputDirDefault, "Directory to save outputs (with --download)")
+	fs.StringVar(&nameTemplate, "name-template", "", "Output layout below --output-dir (default preferences.outputNameTemplate)")
+	selection.register(fs)
+	notifyOpts.register(fs)
+	fs.BoolVar(&asJSON, "json", false, "Print the final task as JSON")
+	showSchema := jsonSchemaFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if *showSchema {
+		return printJSONSchema("task wait")
+	}
+	notifyOpts.parsed(fs)
+	rest := fs.Args()
+	if len(rest) > 1 {
+		return errors.New("usage: wiro task wait [taskid|tasktoken|@last] [--timeout <duration>] [--poll-interval <duration>] [--download]")
+	}
+	if timeout < 0 {
+		return errors.New("--timeout must not be negative")
+	}
+	if interval < time.Second {
+		return errors.New("--poll-interval must be at least 1s")
+	}
+	filter, err := selection.filter()
+	if err != nil {
+		return err
+	}
+	naming, err := outputNaming(app, nameTemplate)
+	if err != nil {
+		return err
+	}
+	target, err := taskTarget(app, rest, projectSelector)
+	if err != nil {
+		return err
+	}
+	headers, err := resolveRequestHeaders(app, projectSelector)
+	if err != nil {
+		return err
+	}
+
+	opts := task.WatchOptions{PollInterval: interval}
+	token := target
+	if isTaskID(target) {
+		token, opts.TaskID = "", target
+	}
+	tr := openTranscript(app, outputDir, opts.TaskID)
+	opts.OnEvent = tr.watchEvent
+	waitCtx, cancel := watchContext(ctx, timeout)
+	final, err := app.TaskSvc.WatchTask(waitCtx, token, headers, opts)
+	cancel()
+	tr.finish(final, err)
+	if err != nil {
+		if timeout > 0 && errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			return &ExitError{Code: exitWaitTimeout, Err: watchTimeoutError(ctx, err, timeout, target)}
+		}
+		return err
+	}
+
+	result := taskWaitResult{Task: final}
+	if download && final.Status == "task_postprocess_end" && len(final.Outputs) > 0 {
+		dl := runDownloadOptions(app, filter, app.Config.Preferences.DedupeOutputs, naming)
+		result.Paths, result.Manifest, err = saveTaskOutputs(ctx, app, final, outputDir, dl, task.ModelName(*final), inputsFromParameters(final.ParametersRaw), headers, nil)
+		if err != nil {
+			return err
+		}
+	}
+	notifyFinished(notifyOpts.apply(activeProfile(app, projectSelector)), final, task.ModelName(*final), result.Paths)
+	if asJSON {
+		if err := jsonout.Print(result); err != nil {
+			return err
+		}
+	} else {
+		for _, p := range result.Paths {
+			output.Println(p)
+		}
+	}
+	return taskExitError(final)
+}
+
+// taskExitError maps a final task status to the exit code of `wiro task wait`.
+func taskExitError(t *api.Task) error {
+	switch t.Status {
+	case "task_postprocess_end":
+		return nil
+	case "task_cancel":
+		return &ExitError{Code: exitTaskCancelled, Err: fmt.Errorf("task %s was cancelled", t.ID)}
+	default:
+		return &ExitError{Code: exitTaskFailed, Err: fmt.Errorf("task %s ended with status %s", t.ID, t.Status)}
+	}
+}
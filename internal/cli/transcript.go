@@ -0,0 +1,222 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/log"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	"github.com/wiro-ai/wiro-cli/internal/task"
+)
+
+// transcriptName is the file, inside <output-dir>/<taskid>, that keeps every
+// watch event of a task in the `wiro run --json-events` format.
+const transcriptName = "events.ndjson"
+
+func transcriptPath(outputDir, taskID string) string {
+	return filepath.Join(outputDir, output.SafeName(taskID), transcriptName)
+}
+
+// transcript appends a watched task's events to its transcript file. A nil
+// transcript records nothing, so callers need not check whether it opened.
+type transcript struct {
+	f *os.File
+	w *eventWriter
+}
+
+// openTranscript opens the transcript of taskID below outputDir for
+// appending, so a resumed watch continues it. It returns nil when the task id
+// is unknown or the file cannot be created; watching goes on regardless.
+func openTranscript(app *App, outputDir, taskID string) *transcript {
+	if strings.TrimSpace(taskID) == "" {
+		return nil
+	}
+	path := transcriptPath(app.ResolveOutputDir(outputDir), taskID)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		log.Verbosef("transcript: %v", err)
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		log.Verbosef("transcript: %v", err)
+		return nil
+	}
+	return &transcript{f: f, w: newEventWriter(f)}
+}
+
+func (t *transcript) watchEvent(ev task.WatchEvent) {
+	if t != nil {
+		t.w.watchEvent(ev)
+	}
+}
+
+// finish records how the watch ended, the final task or the error, and
+// closes the file.
+func (t *transcript) finish(final *api.Task, err error) {
+	if t == nil {
+		return
+	}
+	ev := jsonEvent{Source: "system", Type: "task"}
+	if final != nil {
+		ev.Payload = final
+	}
+	if err != nil {
+		ev.Type, ev.Text = "error", err.Error()
+	}
+	t.w.emit(ev)
+	if err := t.f.Close(); err != nil {
+		log.Verbosef("transcript: %v", err)
+	}
+}
+
+func taskEventsCommand(app *App, args []string) error {
+	const usage = "usage: wiro task events <taskid|@last> [--output-dir <path>]"
+	fs := flag.NewFlagSet("task events", flag.ContinueOnError)
+	var projectSelector, outputDir string
+	fs.StringVar(&projectSelector, "project", "", "Project name or API key for @last")
+	fs.StringVar(&outputDir, "output-dir", app.OutputDirDefault(), "Directory the task's outputs were saved to")
+	var rest []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		rest, args = append(rest, args[0]), args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	rest = append(rest, fs.Args()...)
+	if err := requireArgs(rest, 1, usage); err != nil {
+		return err
+	}
+	taskID := rest[0]
+	if taskID == lastTaskRef {
+		t, err := lastTask(app, projectSelector)
+		if err != nil {
+			return err
+		}
+		taskID = t.TaskID
+	}
+	if !isTaskID(taskID) {
+		return fmt.Errorf("%q is not a task id; transcripts are kept by task id", taskID)
+	}
+	path := transcriptPath(app.ResolveOutputDir(outputDir), taskID)
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no event transcript for task %s at %s; pass --output-dir if it was watched with another one", taskID, path)
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	events, err := readTranscript(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	printTranscript(os.Stdout, events)
+	return nil
+}
+
+// transcriptEvent is one stored jsonEvent with its timestamp parsed.
+type transcriptEvent struct {
+	Source    string          `json:"source"`
+	Type      string          `json:"type"`
+	Timestamp string          `json:"timestamp"`
+	Text      string          `json:"text,omitempty"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	At        time.Time       `json:"-"`
+}
+
+func readTranscript(r io.Reader) ([]transcriptEvent, error) {
+	dec := json.NewDecoder(r)
+	var events []transcriptEvent
+	for {
+		var ev transcriptEvent
+		err := dec.Decode(&ev)
+		if errors.Is(err, io.EOF) {
+			return events, nil
+		}
+		if err != nil {
+			// A watch that was killed may leave a cut-off last line.
+			if len(events) > 0 && errors.Is(err, io.ErrUnexpectedEOF) {
+				return events, nil
+			}
+			return events, err
+		}
+		ev.At, _ = time.Parse(time.RFC3339Nano, ev.Timestamp)
+		events = append(events, ev)
+	}
+}
+
+// printTranscript writes one block per event, timed from the first event. A
+// task status seen for the first time starts a phase; its line also says how
+// long the previous phase lasted.
+func printTranscript(w io.Writer, events []transcriptEvent) {
+	if len(events) == 0 {
+		fmt.Fprintln(w, "Transcript is empty.")
+		return
+	}
+	start := events[0].At
+	fmt.Fprintf(w, "Events from %s\n", start.Local().Format("2006-01-02 15:04:05"))
+	seen := map[string]bool{}
+	phase, phaseStart := "", start
+	for _, ev := range events {
+		lines := transcriptLines(ev)
+		if len(lines) == 0 {
+			continue
+		}
+		if strings.HasPrefix(ev.Type, "task_") && !seen[ev.Type] {
+			seen[ev.Type] = true
+			if phase != "" {
+				lines[0] += fmt.Sprintf("  (%s after %s)", formatElapsed(ev.At.Sub(phaseStart)), phase)
+			}
+			phase, phaseStart = ev.Type, ev.At
+		}
+		fmt.Fprintf(w, "%9s  %s\n", "+"+formatElapsed(ev.At.Sub(start)), lines[0])
+		for _, l := range lines[1:] {
+			fmt.Fprintf(w, "%9s  %s\n", "", l)
+		}
+	}
+	end := events[len(events)-1].At
+	if phases := phaseSummary(transcriptTimeline(events).Phases(end)); phases != "" {
+		fmt.Fprintf(w, "Phases: %s\n", phases)
+	}
+	fmt.Fprintf(w, "Total %s\n", formatElapsed(end.Sub(start)))
+}
+
+// transcriptLines formats a stored event the way the live watch printed it;
+// the closing event shows the final status or error.
+func transcriptLines(ev transcriptEvent) []string {
+	switch {
+	case ev.Source == "system" && ev.Type == "task":
+		var t api.Task
+		if json.Unmarshal(ev.Payload, &t) != nil || t.Status == "" {
+			return []string{"[final] watch ended"}
+		}
+		return []string{"[final] " + t.Status}
+	case ev.Source == "system" && ev.Type == "error":
+		return []string{"[final] error", "  " + short(ev.Text, 180)}
+	}
+	var raw map[string]interface{}
+	_ = json.Unmarshal(ev.Payload, &raw)
+	return watchEventLines(task.WatchEvent{Source: ev.Source, Type: ev.Type, Text: ev.Text, Raw: raw})
+}
+
+// formatElapsed prints d to a tenth of a second below a minute, else to the second.
+func formatElapsed(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	if d < time.Minute {
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	}
+	return d.Round(time.Second).String()
+}
@@ -0,0 +1,640 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/model"
+	"github.com/wiro-ai/wiro-cli/internal/task"
+	"github.com/wiro-ai/wiro-cli/internal/term"
+)
+
+type tuiPane int
+
+const (
+	paneSearch tuiPane = iota
+	paneForm
+	paneTask
+	paneOutputs
+)
+
+var tuiPaneNames = []string{"Models", "Parameters", "Task", "Outputs"}
+
+// maxTUIEvents caps the task event log kept in memory.
+const maxTUIEvents = 500
+
+// tuiField is one editable row of the parameter form.
+type tuiField struct {
+	Item  api.ToolParameterItem
+	Kind  parameterInputKind
+	Value string
+}
+
+// tui is the state of the full-screen UI. It is only touched from the event loop;
+// background work hands results back as closures on msgs.
+type tui struct {
+	ctx     context.Context
+	app     *App
+	project string
+	msgs    chan func(*tui)
+
+	pane    tuiPane
+	status  string
+	editing bool
+	editBuf string
+
+	query   string
+	models  []api.ToolSummary
+	modelLs term.List
+	loading bool
+
+	owner  string
+	slug   string
+	detail *api.ToolDetail
+	fields []tuiField
+	formLs term.List
+
+	running bool
+	taskID  string
+	events  []string
+
+	paths []string
+	outLs term.List
+}
+
+func tuiCommand(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("tui", flag.ContinueOnError)
+	project := fs.String("project", "", "project name or api key")
+	query := fs.String("query", "", "initial model search query")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return errors.New("usage: wiro tui [--project <name|apikey>] [--query <text>]")
+	}
+	if !isInteractiveSession() {
+		return errors.New("wiro tui needs an interactive terminal")
+	}
+	if err := ensureFirstRunSetup(app); err != nil {
+		return err
+	}
+	_, profile, err := resolveProject(ctx, app, *project)
+	if err != nil {
+		return err
+	}
+	if _, err := app.AuthSvc.BuildHeaders(profile); err != nil {
+		if tryErr := tryRecoverMissingProjectSecret(app, profile, err); tryErr != nil {
+			return err
+		}
+	}
+
+	restore, err := term.MakeRaw()
+	if err != nil {
+		return err
+	}
+	defer restore()
+	screen := term.NewScreen(os.Stdout)
+	screen.Enter()
+	defer screen.Exit()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	t := &tui{ctx: ctx, app: app, project: *project, msgs: make(chan func(*tui), 64), query: *query}
+	t.search()
+
+	keys := make(chan term.Key)
+	keyErr := make(chan error, 1)
+	go func() {
+		reader := bufio.NewReader(os.Stdin)
+		for {
+			k, err := term.ReadKey(reader)
+			if err != nil {
+				keyErr <- err
+				return
+			}
+			select {
+			case keys <- k:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		width, height := term.Size()
+		screen.Draw(t.render(width, height), width, height)
+		select {
+		case k := <-keys:
+			if t.handleKey(k) {
+				return nil
+			}
+		case fn := <-t.msgs:
+			fn(t)
+		case err := <-keyErr:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// post hands a state update from a background goroutine to the event loop.
+func (t *tui) post(fn func(*tui)) {
+	select {
+	case t.msgs <- fn:
+	case <-t.ctx.Done():
+	}
+}
+
+func (t *tui) search() {
+	t.loading = true
+	t.status = "Searching..."
+	query := t.query
+	go func() {
+		models, err := t.app.ModelSvc.List(t.ctx, model.ListOptions{Query: query, Limit: 60})
+		t.post(func(t *tui) {
+			t.loading = false
+			if err != nil {
+				t.status = "search failed: " + err.Error()
+				return
+			}
+			t.models = models
+			t.modelLs = term.List{}
+			t.status = fmt.Sprintf("%d model(s)", len(models))
+		})
+	}()
+}
+
+func (t *tui) openModel(m api.ToolSummary) {
+	t.loading = true
+	t.status = fmt.Sprintf("Loading %s/%s...", m.SlugOwner, m.SlugProject)
+	go func() {
+		detail, err := t.app.ModelSvc.Detail(t.ctx, m.SlugOwner, m.SlugProject)
+		t.post(func(t *tui) {
+			t.loading = false
+			if err != nil {
+				t.status = "load failed: " + err.Error()
+				return
+			}
+			t.owner, t.slug, t.detail = m.SlugOwner, m.SlugProject, detail
+			t.fields = formFields(modelItems(detail, true))
+			t.formLs = term.List{}
+			t.pane = paneForm
+			t.status = ""
+		})
+	}()
+}
+
+// formFields seeds the form from schema defaults; prompts and file inputs start empty.
+func formFields(items []api.ToolParameterItem) []tuiField {
+	fields := make([]tuiField, 0, len(items))
+	for _, item := range items {
+		f := tuiField{Item: item, Kind: mapParameterKind(item.Type)}
+		switch f.Kind {
+		case paramCombineFile:
+		case paramCheckbox:
+			def := defaultString(item.DefaultValue)
+			if strings.EqualFold(def, "true") || def == "1" {
+				f.Value = "true"
+			}
+		case paramSelect:
+			f.Value = defaultString(item.DefaultValue)
+			if f.Value == "" && len(item.Options) > 0 {
+				f.Value = fmt.Sprint(item.Options[0].Value)
+			}
+		default:
+			if !isPromptField(item) {
+				f.Value = defaultString(item.DefaultValue)
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// cycleOption moves a select field to the next or previous option.
+func (f *tuiField) cycleOption(delta int) {
+	n := len(f.Item.Options)
+	if n == 0 {
+		return
+	}
+	cur := 0
+	for i, opt := range f.Item.Options {
+		if fmt.Sprint(opt.Value) == f.Value {
+			cur = i
+			break
+		}
+	}
+	l := term.List{Selected: cur}
+	l.Move(delta, n, true)
+	f.Value = fmt.Sprint(f.Item.Options[l.Selected].Value)
+}
+
+// formJob turns the form into a runJob, validating it against the schema first.
+func formJob(owner, slug, project string, detail *api.ToolDetail, fields []tuiField) (runJob, error) {
+	job := runJob{Project: project, Owner: owner, Model: slug}
+	for _, f := range fields {
+		v := strings.TrimSpace(f.Value)
+		if v == "" {
+			continue
+		}
+		switch f.Kind {
+		case paramCombineFile:
+			for _, part := range splitCSV(v) {
+				if looksURL(part) {
+					job.SetURL = append(job.SetURL, f.Item.ID+"="+part)
+					continue
+				}
+				if _, err := os.Stat(part); err != nil {
+					return runJob{}, fmt.Errorf("file not found for %q value %q", f.Item.ID, part)
+				}
+				job.SetFile = append(job.SetFile, f.Item.ID+"="+part)
+			}
+		default:
+			job.Set = append(job.Set, f.Item.ID+"="+v)
+		}
+	}
+	setText, _ := parseKeyValuePairs(job.Set)
+	setFile, _ := parseKeyValuePairs(job.SetFile)
+	setURL, _ := parseKeyValuePairs(job.SetURL)
+	preset := mergeParamSources(setText, setFile, setURL)
+	if _, err := checkNonInteractiveInputs(modelItems(detail, true), modelItems(detail, false), preset); err != nil {
+		return runJob{}, err
+	}
+	return job, nil
+}
+
+func (t *tui) startRun() {
+	if t.running {
+		t.status = "a run is already in progress"
+		return
+	}
+	job, err := formJob(t.owner, t.slug, t.project, t.detail, t.fields)
+	if err != nil {
+		t.status = err.Error()
+		return
+	}
+	job.OutputDir = t.app.Config.Preferences.OutputDirDefault
+	job.Dedupe = t.app.Config.Preferences.DedupeOutputs
+	if job.Naming, err = outputNaming(t.app, ""); err != nil {
+		t.status = err.Error()
+		return
+	}
+	t.running = true
+	t.taskID = ""
+	t.events = []string{fmt.Sprintf("Submitting %s/%s...", t.owner, t.slug)}
+	t.pane = paneTask
+	t.status = ""
+	go func() {
+		res, err := executeRunJob(t.ctx, t.app, job, runJobHooks{
+			OnSubmitted: func(resp api.RunResponse) {
+				t.post(func(t *tui) {
+					t.taskID = resp.TaskID
+					t.addEvents(fmt.Sprintf("Task submitted: %s", resp.TaskID))
+				})
+			},
+			OnEvent: func(ev task.WatchEvent) {
+				lines := watchEventLines(ev)
+				t.post(func(t *tui) { t.addEvents(lines...) })
+			},
+		})
+		t.post(func(t *tui) {
+			t.running = false
+			if err != nil {
+				t.addEvents("error: " + err.Error())
+				t.status = "run failed"
+				return
+			}
+			if res.Task != nil {
+				t.addEvents("Final status: " + res.Task.Status)
+			}
+			t.paths = res.Paths
+			t.outLs = term.List{}
+			if len(res.Paths) > 0 {
+				t.pane = paneOutputs
+				t.status = fmt.Sprintf("%d output(s) saved", len(res.Paths))
+			} else {
+				t.status = "task finished without outputs"
+			}
+		})
+	}()
+}
+
+func (t *tui) addEvents(lines ...string) {
+	t.events = append(t.events, lines...)
+	if over := len(t.events) - maxTUIEvents; over > 0 {
+		t.events = t.events[over:]
+	}
+}
+
+// handleKey applies one key press and reports whether the UI should exit.
+func (t *tui) handleKey(k term.Key) bool {
+	if k.Type == term.KeyCtrlC {
+		return true
+	}
+	if t.editing {
+		t.handleEdit(k)
+		return false
+	}
+	switch k.Type {
+	case term.KeyTab:
+		t.pane = (t.pane + 1) % tuiPane(len(tuiPaneNames))
+		if t.pane == paneForm && t.detail == nil {
+			t.pane = paneTask
+		}
+		return false
+	case term.KeyEsc:
+		if t.pane > paneSearch {
+			t.pane--
+			if t.pane == paneForm && t.detail == nil {
+				t.pane = paneSearch
+			}
+		}
+		return false
+	case term.KeyRune:
+		if k.Rune == 'q' {
+			return true
+		}
+	}
+	switch t.pane {
+	case paneSearch:
+		t.handleSearchKey(k)
+	case paneForm:
+		t.handleFormKey(k)
+	case paneOutputs:
+		t.handleOutputsKey(k)
+	}
+	return false
+}
+
+func (t *tui) handleSearchKey(k term.Key) {
+	switch {
+	case k.Type == term.KeyUp:
+		t.modelLs.Move(-1, len(t.models), false)
+	case k.Type == term.KeyDown:
+		t.modelLs.Move(1, len(t.models), false)
+	case k.Type == term.KeyRune && k.Rune == '/':
+		t.editing, t.editBuf = true, t.query
+	case k.Type == term.KeyEnter && !t.loading && len(t.models) > 0:
+		t.openModel(t.models[t.modelLs.Selected])
+	}
+}
+
+func (t *tui) handleFormKey(k term.Key) {
+	rows := len(t.fields) + 1 // the last row is the Run button
+	switch k.Type {
+	case term.KeyUp:
+		t.formLs.Move(-1, rows, false)
+		return
+	case term.KeyDown:
+		t.formLs.Move(1, rows, false)
+		return
+	}
+	if t.formLs.Selected == len(t.fields) {
+		if k.Type == term.KeyEnter {
+			t.startRun()
+		}
+		return
+	}
+	f := &t.fields[t.formLs.Selected]
+	switch f.Kind {
+	case paramSelect:
+		switch {
+		case k.Type == term.KeyLeft:
+			f.cycleOption(-1)
+		case k.Type == term.KeyRight, k.Type == term.KeyEnter, k.Type == term.KeyRune && k.Rune == ' ':
+			f.cycleOption(1)
+		}
+	case paramCheckbox:
+		if k.Type == term.KeyEnter || (k.Type == term.KeyRune && k.Rune == ' ') {
+			if f.Value == "true" {
+				f.Value = ""
+			} else {
+				f.Value = "true"
+			}
+		}
+	default:
+		if k.Type == term.KeyEnter {
+			t.editing, t.editBuf = true, f.Value
+		}
+	}
+}
+
+// handleEdit edits the search query or the selected form field in place.
+func (t *tui) handleEdit(k term.Key) {
+	switch k.Type {
+	case term.KeyEsc:
+		t.editing = false
+	case term.KeyEnter:
+		t.editing = false
+		if t.pane == paneSearch {
+			t.query = t.editBuf
+			t.search()
+			return
+		}
+		t.fields[t.formLs.Selected].Value = t.editBuf
+	case term.KeyBackspace:
+		if r := []rune(t.editBuf); len(r) > 0 {
+			t.editBuf = string(r[:len(r)-1])
+		}
+	case term.KeyRune:
+		t.editBuf += string(k.Rune)
+	}
+}
+
+func (t *tui) handleOutputsKey(k term.Key) {
+	switch k.Type {
+	case term.KeyUp:
+		t.outLs.Move(-1, len(t.paths), false)
+	case term.KeyDown:
+		t.outLs.Move(1, len(t.paths), false)
+	case term.KeyEnter:
+		if len(t.paths) == 0 {
+			return
+		}
+		path := t.paths[t.outLs.Selected]
+		if err := openPath(path); err != nil {
+			t.status = "open failed: " + err.Error()
+		} else {
+			t.status = "opened " + path
+		}
+	}
+}
+
+// openPath opens a file with the platform's default application.
+func openPath(path string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", path)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", path)
+	default:
+		cmd = exec.Command("xdg-open", path)
+	}
+	return cmd.Start()
+}
+
+// render lays out the header, the active pane, and the footer for one frame.
+func (t *tui) render(width, height int) []string {
+	tabs := make([]string, 0, len(tuiPaneNames))
+	for i, name := range tuiPaneNames {
+		if tuiPane(i) == t.pane {
+			tabs = append(tabs, term.Reverse(" "+name+" "))
+		} else {
+			tabs = append(tabs, " "+name+" ")
+		}
+	}
+	lines := []string{"wiro  " + strings.Join(tabs, " "), ""}
+	body := height - 4
+	switch t.pane {
+	case paneSearch:
+		lines = append(lines, t.renderSearch(width, body)...)
+	case paneForm:
+		lines = append(lines, t.renderForm(width, body)...)
+	case paneTask:
+		lines = append(lines, t.renderTask(width, body)...)
+	case paneOutputs:
+		lines = append(lines, t.renderOutputs(width, body)...)
+	}
+	for len(lines) < height-2 {
+		lines = append(lines, "")
+	}
+	lines = lines[:height-2]
+	lines = append(lines, term.Fit(t.status, width-1), term.Dim(term.Fit(t.help(), width-1)))
+	return lines
+}
+
+func (t *tui) help() string {
+	if t.editing {
+		return "type to edit  enter: save  esc: cancel"
+	}
+	switch t.pane {
+	case paneSearch:
+		return "/: search  ↑/↓: move  enter: open  tab: next pane  q: quit"
+	case paneForm:
+		return "↑/↓: move  enter: edit/toggle  ←/→: change option  esc: back  q: quit"
+	case paneOutputs:
+		return "↑/↓: move  enter: open file  esc: back  q: quit"
+	default:
+		return "tab: next pane  esc: back  q: quit"
+	}
+}
+
+// cursorLine shows the edit buffer with a cursor while editing.
+func (t *tui) cursorLine(value string) string {
+	if t.editing {
+		return t.editBuf + "█"
+	}
+	return value
+}
+
+func (t *tui) renderSearch(width, height int) []string {
+	lines := []string{term.Fit("Search: "+t.cursorLine(t.query), width-1), ""}
+	start, end := t.modelLs.Window(len(t.models), height-2)
+	for i := start; i < end; i++ {
+		m := t.models[i]
+		line := term.Fit(fmt.Sprintf("%s/%s :: %s", m.SlugOwner, m.SlugProject, m.Description), width-3)
+		if i == t.modelLs.Selected {
+			line = term.Reverse("> " + line)
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func (t *tui) renderForm(width, height int) []string {
+	lines := []string{term.Fit(fmt.Sprintf("%s/%s :: %s", t.owner, t.slug, t.detail.Description), width-1), ""}
+	rows := len(t.fields) + 1
+	start, end := t.formLs.Window(rows, height-2)
+	for i := start; i < end; i++ {
+		var line string
+		if i == len(t.fields) {
+			line = "[ Run ]"
+		} else {
+			f := t.fields[i]
+			label := f.Item.ID
+			if f.Item.Required || isPromptField(f.Item) {
+				label += "*"
+			}
+			value := f.Value
+			if model.IsSecret(f.Item) {
+				value = strings.Repeat("*", utf8.RuneCountInString(f.Value))
+			}
+			switch f.Kind {
+			case paramCheckbox:
+				value = "[ ]"
+				if f.Value == "true" {
+					value = "[x]"
+				}
+			case paramSelect:
+				value = "< " + f.Value + " >"
+			}
+			if i == t.formLs.Selected {
+				value = t.cursorLine(value)
+			}
+			extra := ""
+			if f.Item.Advanced {
+				extra = " (advanced)"
+			}
+			line = fmt.Sprintf("%s %s%s", term.Pad(term.Fit(label, 24), 24), term.Fit(value, width-40), extra)
+		}
+		if i == t.formLs.Selected {
+			line = term.Reverse("> " + line)
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func (t *tui) renderTask(width, height int) []string {
+	head := "No task yet; fill the form and choose Run."
+	if t.taskID != "" {
+		head = "Task " + t.taskID
+		if t.running {
+			head += " (running)"
+		}
+	} else if t.running {
+		head = "Submitting..."
+	}
+	lines := []string{term.Fit(head, width-1), ""}
+	events := t.events
+	if over := len(events) - (height - 2); over > 0 {
+		events = events[over:]
+	}
+	for _, ev := range events {
+		lines = append(lines, term.Fit(ev, width-1))
+	}
+	return lines
+}
+
+func (t *tui) renderOutputs(width, height int) []string {
+	if len(t.paths) == 0 {
+		return []string{"No outputs yet."}
+	}
+	lines := []string{fmt.Sprintf("%d file(s)", len(t.paths)), ""}
+	start, end := t.outLs.Window(len(t.paths), height-2)
+	for i := start; i < end; i++ {
+		line := term.Fit(t.paths[i], width-3)
+		if i == t.outLs.Selected {
+			line = term.Reverse("> " + line)
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+func TestFormJob(t *testing.T) {
+	detail := &api.ToolDetail{Parameters: []api.ToolParameterGroup{{Items: []api.ToolParameterItem{
+		{ID: "prompt", Type: "textarea", Required: true},
+		{ID: "size", Type: "select", DefaultValue: "512", Options: []api.ToolOption{{Value: "512"}, {Value: "1024"}}},
+		{ID: "upscale", Type: "checkbox"},
+		{ID: "images", Type: "combinefileinput"},
+	}}}}
+	fields := formFields(modelItems(detail, true))
+	if fields[0].Value != "" || fields[1].Value != "512" {
+		t.Fatalf("seeded values = %q, %q", fields[0].Value, fields[1].Value)
+	}
+
+	if _, err := formJob("o", "m", "", detail, fields); err == nil {
+		t.Fatal("expected missing prompt error")
+	}
+
+	fields[0].Value = "a cat"
+	fields[1].cycleOption(1)
+	fields[2].Value = "true"
+	fields[3].Value = "https://x.test/a.png, https://x.test/b.png"
+	job, err := formJob("o", "m", "", detail, fields)
+	if err != nil {
+		t.Fatalf("formJob: %v", err)
+	}
+	if want := []string{"prompt=a cat", "size=1024", "upscale=true"}; !reflect.DeepEqual(job.Set, want) {
+		t.Fatalf("Set = %v, want %v", job.Set, want)
+	}
+	if want := []string{"images=https://x.test/a.png", "images=https://x.test/b.png"}; !reflect.DeepEqual(job.SetURL, want) {
+		t.Fatalf("SetURL = %v, want %v", job.SetURL, want)
+	}
+
+	fields[3].Value = "/no/such/file.png"
+	if _, err := formJob("o", "m", "", detail, fields); err == nil {
+		t.Fatal("expected missing file error")
+	}
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	projectsvc "github.com/wiro-ai/wiro-cli/internal/project"
+	"github.com/wiro-ai/wiro-cli/internal/upload"
+)
+
+const uploadUsage = "usage: wiro upload <file> [file ...] [--project name|apikey] [--force] [--json]"
+
+// uploadResult is one file of `wiro upload --json`.
+type uploadResult struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	Size   int64  `json:"size"`
+	URL    string `json:"url"`
+	Cached bool   `json:"cached"`
+}
+
+func uploadCommand(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("upload", flag.ContinueOnError)
+	var selector string
+	var force, asJSON bool
+	fs.StringVar(&selector, "project", "", "Project name or API key (default: selected project)")
+	fs.BoolVar(&force, "force", false, "Upload again even when the file is in the upload cache")
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+
+	var files []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		files, args = append(files, args[0]), args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	files = append(files, fs.Args()...)
+	if len(files) == 0 {
+		return errors.New(uploadUsage)
+	}
+
+	profile := projectsvc.ResolveSelected(app.Config, selector)
+	if selector != "" && profile == nil {
+		return fmt.Errorf("project %q not found in local config", selector)
+	}
+	headerResult, err := app.AuthSvc.BuildHeaders(profile)
+	if err != nil {
+		return err
+	}
+	project := ""
+	if profile != nil {
+		project = profile.APIKey
+	}
+	cache, err := uploadCache()
+	if err != nil {
+		return err
+	}
+
+	results := make([]uploadResult, 0, len(files))
+	for _, path := range files {
+		res, err := uploadOne(ctx, app, cache, path, project, headerResult.Headers, force)
+		if err != nil {
+			return err
+		}
+		results = append(results, res)
+		if !asJSON {
+			if res.Cached {
+				output.Notef("%s: already uploaded (pass --force to upload again)\n", path)
+			}
+			output.Println(res.URL)
+		}
+	}
+	if asJSON {
+		return output.PrintJSON(results)
+	}
+	return nil
+}
+
+func uploadOne(ctx context.Context, app *App, cache *upload.Cache, path, project string, headers map[string]string, force bool) (uploadResult, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return uploadResult{}, err
+	}
+	if info.IsDir() {
+		return uploadResult{}, fmt.Errorf("%s is a directory", path)
+	}
+	sum, err := api.FileSHA256(path)
+	if err != nil {
+		return uploadResult{}, err
+	}
+	res := uploadResult{Path: path, SHA256: sum, Size: info.Size()}
+	if !force {
+		if e, ok := cache.Lookup(sum, project); ok {
+			res.URL, res.Cached = e.URL, true
+			return res, nil
+		}
+	}
+
+	uploadCtx, cancel := context.WithTimeout(ctx, 2*time.Hour)
+	defer cancel()
+	bar := output.NewProgress("Uploading " + filepath.Base(path))
+	url, err := app.APIClient.UploadFileChunked(uploadCtx, path, headers, api.ChunkedUploadOptions{SHA256: sum, OnProgress: bar.Update})
+	bar.Done()
+	if err != nil {
+		return uploadResult{}, fmt.Errorf("upload %s: %w", path, err)
+	}
+	res.URL = url
+	cache.Put(upload.Entry{
+		SHA256:     sum,
+		Project:    project,
+		Size:       info.Size(),
+		Name:       filepath.Base(path),
+		URL:        url,
+		UploadedAt: time.Now().UTC().Format(time.RFC3339),
+	})
+	if err := cache.Save(); err != nil {
+		output.Warnf("%v", err)
+	}
+	return res, nil
+}
+
+func uploadCache() (*upload.Cache, error) {
+	st, err := localStorage()
+	if err != nil {
+		return nil, err
+	}
+	return upload.OpenCache(st), nil
+}
@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/log"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	"github.com/wiro-ai/wiro-cli/internal/upload"
+)
+
+// minReuseSize is the smallest file worth looking up in the upload cache;
+// below it the check costs about as much as the upload.
+const minReuseSize = 64 << 10
+
+// sentFile is a file input submitted as a file part.
+type sentFile struct {
+	SHA256 string
+	Size   int64
+	Name   string
+}
+
+// uploadReuse swaps file inputs for the URLs of identical files uploaded
+// before (by wiro upload or earlier runs), and learns new URLs from the
+// tasks the remaining files were sent with.
+type uploadReuse struct {
+	cache   *upload.Cache
+	project string
+	// sent maps fields submitted as a single file to that file.
+	sent map[string]sentFile
+}
+
+// newUploadReuse opens the upload cache for profile. It returns nil when
+// the cache is unavailable; a nil uploadReuse does nothing.
+func newUploadReuse(profile *config.ProjectProfile) *uploadReuse {
+	cache, err := uploadCache()
+	if err != nil {
+		log.Verbosef("upload cache: %v", err)
+		return nil
+	}
+	u := &uploadReuse{cache: cache, sent: map[string]sentFile{}}
+	if profile != nil {
+		u.project = profile.APIKey
+	}
+	return u
+}
+
+// apply returns inputs with each cached file replaced by its URL. A URL is
+// only used after a HEAD request shows it still serves a file of that size.
+func (u *uploadReuse) apply(ctx context.Context, app *App, inputs map[string][]api.MultipartValue, headers map[string]string) map[string][]api.MultipartValue {
+	if u == nil {
+		return inputs
+	}
+	out := make(map[string][]api.MultipartValue, len(inputs))
+	for k, values := range inputs {
+		swapped := make([]api.MultipartValue, len(values))
+		copy(swapped, values)
+		files := 0
+		for i, v := range values {
+			if v.FilePath == "" {
+				continue
+			}
+			files++
+			info, err := os.Stat(v.FilePath)
+			if err != nil || info.Size() < minReuseSize {
+				continue
+			}
+			sum, err := api.FileSHA256(v.FilePath)
+			if err != nil {
+				continue
+			}
+			if url, ok := u.lookup(ctx, app, sum, info.Size(), headers); ok {
+				output.Notef("%s: reusing the earlier upload of %s\n", k, v.FilePath)
+				swapped[i] = api.MultipartValue{Value: url}
+				continue
+			}
+			u.sent[k] = sentFile{SHA256: sum, Size: info.Size(), Name: filepath.Base(v.FilePath)}
+		}
+		if files > 1 {
+			// The task's values cannot be matched to several files.
+			delete(u.sent, k)
+		}
+		out[k] = swapped
+	}
+	return out
+}
+
+func (u *uploadReuse) lookup(ctx context.Context, app *App, sum string, size int64, headers map[string]string) (string, bool) {
+	e, ok := u.cache.Lookup(sum, u.project)
+	if !ok {
+		return "", false
+	}
+	headCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	got, _, err := app.APIClient.Head(headCtx, e.URL, headers)
+	if err != nil || (got >= 0 && got != size) {
+		log.Verbosef("upload cache: %s is no longer usable: size=%d err=%v", e.URL, got, err)
+		return "", false
+	}
+	return e.URL, true
+}
+
+// learn records the URLs a task stored the sent files under, so the next run
+// with the same content skips the upload.
+func (u *uploadReuse) learn(t *api.Task) {
+	if u == nil || t == nil || len(u.sent) == 0 {
+		return
+	}
+	params := inputsFromParameters(t.ParametersRaw)
+	learned := 0
+	for field, f := range u.sent {
+		vals := params[field]
+		if len(vals) != 1 {
+			continue
+		}
+		url := strings.TrimSpace(vals[0].Value)
+		if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+			continue
+		}
+		u.cache.Put(upload.Entry{
+			SHA256:     f.SHA256,
+			Project:    u.project,
+			Size:       f.Size,
+			Name:       f.Name,
+			URL:        url,
+			UploadedAt: time.Now().UTC().Format(time.RFC3339),
+		})
+		learned++
+	}
+	if learned == 0 {
+		return
+	}
+	if err := u.cache.Save(); err != nil {
+		log.Verbosef("upload cache: %v", err)
+	}
+}
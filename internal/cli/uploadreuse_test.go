@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/config"
+)
+
+func TestUploadReuse_LearnsAndSwapsFiles(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmp, ".config"))
+
+	content := make([]byte, minReuseSize)
+	path := filepath.Join(tmp, "ref.png")
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	}))
+	defer srv.Close()
+	app := &App{APIClient: api.NewClient(srv.URL)}
+	profile := &config.ProjectProfile{APIKey: "key-1"}
+	inputs := map[string][]api.MultipartValue{"image": {{FilePath: path}}, "prompt": {{Value: "a cat"}}}
+
+	// First run: nothing cached, so the file is sent and its stored URL learned.
+	reuse := newUploadReuse(profile)
+	got := reuse.apply(context.Background(), app, inputs, nil)
+	if got["image"][0].FilePath != path {
+		t.Fatalf("first run swapped the file: %+v", got["image"])
+	}
+	params, _ := json.Marshal(map[string]string{"image": srv.URL + "/ref.png", "prompt": "a cat"})
+	reuse.learn(&api.Task{ParametersRaw: params})
+
+	// Second run: the same content goes by URL.
+	got = newUploadReuse(profile).apply(context.Background(), app, inputs, nil)
+	if v := got["image"][0]; v.FilePath != "" || v.Value != srv.URL+"/ref.png" {
+		t.Fatalf("second run = %+v", v)
+	}
+	if inputs["image"][0].FilePath != path {
+		t.Fatal("apply changed the caller's inputs")
+	}
+
+	// Another project never sees the URL.
+	got = newUploadReuse(&config.ProjectProfile{APIKey: "key-2"}).apply(context.Background(), app, inputs, nil)
+	if got["image"][0].FilePath != path {
+		t.Fatalf("other project = %+v", got["image"])
+	}
+}
@@ -0,0 +1,18 @@
+package cli
+
+import "runtime/debug"
+
+// Version is stamped at release time with
+// -ldflags "-X github.com/wiro-ai/wiro-cli/internal/cli.Version=<version>".
+var Version = "dev"
+
+// cliVersion returns Version, falling back to the module version for `go install` builds.
+func cliVersion() string {
+	if Version != "dev" {
+		return Version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return Version
+}
@@ -0,0 +1,235 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/log"
+	"github.com/wiro-ai/wiro-cli/internal/metrics"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	"github.com/wiro-ai/wiro-cli/internal/task"
+	"github.com/wiro-ai/wiro-cli/internal/term"
+)
+
+const watchUsage = "usage: wiro watch <taskid|tasktoken|@last ...> | --all | --account [--project <name|apikey>] [--json]"
+
+func watchCommand(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	var projectSelector string
+	var account, all, backfill, asJSON bool
+	var interval time.Duration
+	var limit int
+	var metricsAddr string
+	fs.BoolVar(&account, "account", false, "Watch every task of the account/project")
+	fs.BoolVar(&all, "all", false, "Watch every task of the project that is still running")
+	fs.StringVar(&projectSelector, "project", "", "Project name or API key for auth context")
+	fs.DurationVar(&interval, "interval", 10*time.Second, "Task list poll interval")
+	fs.IntVar(&limit, "limit", 50, "Recent tasks fetched per poll")
+	fs.BoolVar(&backfill, "backfill", false, "Also report tasks that finished before the watch started")
+	fs.BoolVar(&asJSON, "json", false, "Print one JSON event per line")
+	fs.StringVar(&metricsAddr, "metrics-addr", "", "Serve Prometheus metrics on this address (e.g. :9464)")
+
+	var rest []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		rest, args = append(rest, args[0]), args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	rest = append(rest, fs.Args()...)
+	modes := 0
+	for _, on := range []bool{account, all, len(rest) > 0} {
+		if on {
+			modes++
+		}
+	}
+	if modes != 1 {
+		return errors.New(watchUsage)
+	}
+	if interval < time.Second {
+		return errors.New("--interval must be at least 1s")
+	}
+
+	headers, err := resolveRequestHeaders(app, projectSelector)
+	if err != nil {
+		return err
+	}
+	if err := startMetrics(ctx, metricsAddr); err != nil {
+		return err
+	}
+	if !account {
+		return watchTasks(ctx, app, rest, all, limit, projectSelector, headers, asJSON)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	if !asJSON {
+		output.Notef("Watching account tasks every %s (Ctrl+C to stop)\n", interval)
+	}
+	err = app.TaskSvc.WatchFeed(ctx, headers, task.FeedOptions{
+		Interval: interval,
+		Limit:    limit,
+		Backfill: backfill,
+		OnEvent: func(ev task.FeedEvent) {
+			if asJSON {
+				_ = enc.Encode(ev)
+				return
+			}
+			printFeedEvent(ev)
+		},
+	})
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// watchTasks follows the given tasks, or with all every unfinished task in the
+// recent task list, on one status board until each is final.
+func watchTasks(ctx context.Context, app *App, refs []string, all bool, limit int, projectSelector string, headers map[string]string, asJSON bool) error {
+	targets, err := watchTargets(ctx, app, refs, all, limit, projectSelector, headers)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		output.Println("No running tasks.")
+		return nil
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	var board *output.Board
+	if !asJSON {
+		rows := make([]string, len(targets))
+		for i, t := range targets {
+			rows[i] = boardRow(task.MultiUpdate{TaskID: t.TaskID, Status: "waiting"}, t)
+		}
+		board = output.NewBoard(rows)
+		board.Draw()
+	}
+	results, err := app.TaskSvc.WatchMany(ctx, targets, headers, task.MultiOptions{
+		OnUpdate: func(u task.MultiUpdate) {
+			if asJSON {
+				_ = enc.Encode(u)
+				return
+			}
+			board.Set(u.Index, boardRow(u, targets[u.Index]))
+		},
+		OnError: func(err error) {
+			log.Debugf("watch: %v", err)
+		},
+	})
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, t := range results {
+		if t != nil && t.Status != "task_postprocess_end" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d task(s) did not succeed", failed, len(results))
+	}
+	return nil
+}
+
+// watchTargets resolves task arguments: numbers are task ids, @last is the last
+// task of the active project, and anything else is a socket token.
+func watchTargets(ctx context.Context, app *App, refs []string, all bool, limit int, projectSelector string, headers map[string]string) ([]task.MultiTarget, error) {
+	if all {
+		tasks, err := app.TaskSvc.List(ctx, limit, headers)
+		if err != nil {
+			return nil, err
+		}
+		var targets []task.MultiTarget
+		for _, t := range tasks {
+			if !task.IsTerminal(t.Status) {
+				targets = append(targets, task.MultiTarget{TaskID: t.ID, Token: t.SocketAccessToken})
+			}
+		}
+		return targets, nil
+	}
+	targets := make([]task.MultiTarget, 0, len(refs))
+	for _, ref := range refs {
+		ref = strings.TrimSpace(ref)
+		switch {
+		case ref == lastTaskRef:
+			t, err := lastTask(app, projectSelector)
+			if err != nil {
+				return nil, err
+			}
+			targets = append(targets, task.MultiTarget{TaskID: t.TaskID, Token: t.TaskToken})
+		case isTaskID(ref):
+			targets = append(targets, task.MultiTarget{TaskID: ref})
+		case ref != "":
+			targets = append(targets, task.MultiTarget{Token: ref})
+		}
+	}
+	return targets, nil
+}
+
+func isTaskID(ref string) bool {
+	_, err := strconv.ParseUint(ref, 10, 64)
+	return err == nil
+}
+
+// boardRow is one task's line on the watch status board.
+func boardRow(u task.MultiUpdate, t task.MultiTarget) string {
+	id := u.TaskID
+	if id == "" {
+		id = short(t.Token, 12)
+	}
+	status := u.Status
+	if u.Final {
+		status += " (final)"
+	}
+	stamp := "--:--:--"
+	if !u.Time.IsZero() {
+		stamp = u.Time.Format("15:04:05")
+	}
+	return fmt.Sprintf("%-12s %s %-30s %s", id, term.Pad(short(output.Dash(u.Model), 28), 28), status, stamp)
+}
+
+func printFeedEvent(ev task.FeedEvent) {
+	stamp := ev.Time.Format("15:04:05")
+	if ev.Kind == task.FeedError {
+		output.Printf("%s [error] %s\n", stamp, short(ev.Error, 180))
+		return
+	}
+	model := ev.Model
+	if model == "" {
+		model = "-"
+	}
+	status := ev.Status
+	if ev.Previous != "" {
+		status = ev.Previous + " -> " + ev.Status
+	}
+	if ev.Final {
+		status += " (final)"
+	}
+	output.Printf("%s [%s] task %s %s %s\n", stamp, ev.Kind, ev.TaskID, model, status)
+}
+
+// startMetrics serves /metrics for the lifetime of ctx when addr is set.
+func startMetrics(ctx context.Context, addr string) error {
+	if addr == "" {
+		return nil
+	}
+	bound, err := metrics.Serve(ctx, addr)
+	if err != nil {
+		return err
+	}
+	output.Notef("Metrics: http://%s/metrics\n", bound)
+	return nil
+}
@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"time"
+	"unicode"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/log"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	"github.com/wiro-ai/wiro-cli/internal/term"
+)
+
+// taskDashboardURL is the web page of a task; the task id is appended.
+const taskDashboardURL = "https://wiro.ai/panel/tasks/"
+
+// errDetached is returned when the user stops watching with q; the task keeps running.
+var errDetached = errors.New("detached from task")
+
+// watchAction is what a keypress asks of a running watch.
+type watchAction int
+
+const (
+	watchNone watchAction = iota
+	watchCancel
+	watchKill
+	watchOpen
+	watchDetach
+)
+
+const watchKeysHelp = "Keys: c cancel task · k kill task · o open dashboard · q detach (task keeps running)"
+
+// watchKeyAction maps a keypress to its action.
+func watchKeyAction(k term.Key) watchAction {
+	if k.Type != term.KeyRune {
+		return watchNone
+	}
+	switch unicode.ToLower(k.Rune) {
+	case 'c':
+		return watchCancel
+	case 'k':
+		return watchKill
+	case 'o':
+		return watchOpen
+	case 'q':
+		return watchDetach
+	}
+	return watchNone
+}
+
+// watchWithKeys runs watch while single keypresses cancel, kill, or open the task,
+// or detach from it. Ctrl-C still interrupts the CLI as before. Without a usable
+// terminal it just runs watch.
+func watchWithKeys(ctx context.Context, app *App, taskID string, headers map[string]string, watch func(context.Context) (*api.Task, error)) (*api.Task, error) {
+	restore, err := term.MakeCbreak()
+	if err != nil {
+		log.Verbosef("watch keys unavailable: %v", err)
+		return watch(ctx)
+	}
+	defer restore()
+
+	watchCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	keys := make(chan term.Key)
+	// The reader blocks on stdin and is left behind when the watch ends; the
+	// process exits (or stops reading keys) shortly after.
+	go func() {
+		r := bufio.NewReader(os.Stdin)
+		for {
+			k, err := term.ReadKey(r)
+			if err != nil {
+				return
+			}
+			select {
+			case keys <- k:
+			case <-watchCtx.Done():
+				return
+			}
+		}
+	}()
+	type result struct {
+		task *api.Task
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		t, err := watch(watchCtx)
+		done <- result{t, err}
+	}()
+
+	output.Println(watchKeysHelp)
+	detached := false
+	for {
+		select {
+		case r := <-done:
+			if detached {
+				return nil, errDetached
+			}
+			return r.task, r.err
+		case k := <-keys:
+			switch watchKeyAction(k) {
+			case watchCancel:
+				taskControl(ctx, "cancel", taskID, func(c context.Context) error {
+					_, err := app.TaskSvc.Cancel(c, taskID, headers)
+					return err
+				})
+			case watchKill:
+				taskControl(ctx, "kill", taskID, func(c context.Context) error {
+					_, err := app.TaskSvc.Kill(c, taskID, headers)
+					return err
+				})
+			case watchOpen:
+				url := taskDashboardURL + taskID
+				if err := openPath(url); err != nil {
+					output.Printf("Open %s: %v\n", url, err)
+				} else {
+					output.Printf("Opened %s\n", url)
+				}
+			case watchDetach:
+				detached = true
+				stop()
+			}
+		}
+	}
+}
+
+// taskControl sends a cancel or kill request and reports the result; the watch
+// keeps running and shows the task's final status.
+func taskControl(ctx context.Context, verb, taskID string, send func(context.Context) error) {
+	c, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	if err := send(c); err != nil {
+		output.Printf("Task %s failed: %v\n", verb, err)
+		return
+	}
+	output.Printf("Task %s request sent for %s; waiting for the task to stop...\n", verb, taskID)
+}
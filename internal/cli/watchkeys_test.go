@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/term"
+)
+
+func TestWatchKeyAction(t *testing.T) {
+	cases := []struct {
+		key  term.Key
+		want watchAction
+	}{
+		{term.Key{Type: term.KeyRune, Rune: 'c'}, watchCancel},
+		{term.Key{Type: term.KeyRune, Rune: 'K'}, watchKill},
+		{term.Key{Type: term.KeyRune, Rune: 'o'}, watchOpen},
+		{term.Key{Type: term.KeyRune, Rune: 'q'}, watchDetach},
+		{term.Key{Type: term.KeyRune, Rune: 'x'}, watchNone},
+		{term.Key{Type: term.KeyEnter}, watchNone},
+	}
+	for _, c := range cases {
+		if got := watchKeyAction(c.key); got != c.want {
+			t.Errorf("watchKeyAction(%+v) = %d, want %d", c.key, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/jsonout"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	"github.com/wiro-ai/wiro-cli/internal/task"
+)
+
+const webhookTestUsage = "usage: wiro webhook test <url> [--task <taskid|tasktoken|@last>] [--status succeeded|failed|cancelled] [--print]"
+
+// webhookStatuses maps --status to the final task status a sample payload carries.
+var webhookStatuses = map[string]string{
+	"succeeded": "task_postprocess_end",
+	"failed":    "task_error_full",
+	"cancelled": "task_cancel",
+}
+
+func webhookCommand(ctx context.Context, app *App, args []string) error {
+	if len(args) == 0 {
+		return errors.New(webhookTestUsage)
+	}
+	switch strings.TrimSpace(args[0]) {
+	case "test":
+		return webhookTestCommand(ctx, app, args[1:])
+	case "--help", "-h", "help":
+		output.Println("Usage: " + strings.TrimPrefix(webhookTestUsage, "usage: "))
+		return nil
+	default:
+		return fmt.Errorf("unknown webhook command %q", args[0])
+	}
+}
+
+// webhookTestCommand posts a callback payload to url, as the API does for a
+// run submitted with --callback-url, so receivers can be built and checked
+// without spending credits.
+func webhookTestCommand(ctx context.Context, app *App, args []string) error {
+	fs := flag.NewFlagSet("webhook test", flag.ContinueOnError)
+	var taskRef, projectSelector, status string
+	var printOnly bool
+	var timeout time.Duration
+	fs.StringVar(&taskRef, "task", "", "Send this real task instead of a sample (taskid, tasktoken, or @last)")
+	fs.StringVar(&projectSelector, "project", "", "Project name or API key for --task")
+	fs.StringVar(&status, "status", "succeeded", "Outcome of the sample task: succeeded, failed, or cancelled")
+	fs.BoolVar(&printOnly, "print", false, "Print the payload instead of sending it")
+	fs.DurationVar(&timeout, "timeout", 10*time.Second, "Give up on the receiver after this long")
+
+	var rest []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		rest, args = append(rest, args[0]), args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	rest = append(rest, fs.Args()...)
+	if len(rest) > 1 || (len(rest) == 0 && !printOnly) {
+		return errors.New(webhookTestUsage)
+	}
+	target := ""
+	if len(rest) == 1 {
+		var err error
+		if target, err = parseCallbackURL(rest[0]); err != nil {
+			return err
+		}
+	}
+
+	var sample api.Task
+	if taskRef != "" {
+		t, err := fetchTask(ctx, app, taskRef, projectSelector)
+		if err != nil {
+			return err
+		}
+		sample = *t
+	} else {
+		final, ok := webhookStatuses[strings.ToLower(strings.TrimSpace(status))]
+		if !ok {
+			return fmt.Errorf("--status must be succeeded, failed, or cancelled, not %q", status)
+		}
+		sample = sampleCallbackTask(final, time.Now())
+	}
+	payload, err := jsonout.Marshal(task.CallbackPayload(sample))
+	if err != nil {
+		return err
+	}
+	if printOnly {
+		output.Println(string(payload))
+		return nil
+	}
+
+	postCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	code, reply, elapsed, err := postCallback(postCtx, target, payload)
+	if err != nil {
+		return fmt.Errorf("post to %s: %w", target, err)
+	}
+	output.Printf("POST %s -> %d %s (%s)\n", target, code, http.StatusText(code), elapsed.Round(time.Millisecond))
+	if reply != "" {
+		output.Println(reply)
+	}
+	if code < 200 || code > 299 {
+		return fmt.Errorf("receiver answered %d; the API treats anything but 2xx as a failed delivery", code)
+	}
+	return nil
+}
+
+// fetchTask returns the current detail of a task reference.
+func fetchTask(ctx context.Context, app *App, ref, projectSelector string) (*api.Task, error) {
+	target, err := taskTarget(app, []string{ref}, projectSelector)
+	if err != nil {
+		return nil, err
+	}
+	headers, err := resolveRequestHeaders(app, projectSelector)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := app.TaskSvc.Detail(ctx, target, headers)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.TaskList) == 0 {
+		return nil, fmt.Errorf("task %s not found", target)
+	}
+	return &resp.TaskList[0], nil
+}
+
+// sampleCallbackTask is a made-up finished task with the given status.
+func sampleCallbackTask(status string, now time.Time) api.Task {
+	start := now.Add(-20 * time.Second)
+	t := api.Task{
+		ID:                "0",
+		UUID:              "00000000-0000-0000-0000-000000000000",
+		Status:            status,
+		SocketAccessToken: "sample-socket-token",
+		CreateTime:        strconv.FormatInt(start.Add(-5*time.Second).Unix(), 10),
+		StartTime:         strconv.FormatInt(start.Unix(), 10),
+		EndTime:           strconv.FormatInt(now.Unix(), 10),
+		ParametersRaw:     []byte(`{"prompt":"a sample prompt"}`),
+		SlugOwner:         "wiro",
+		SlugProject:       "sample-model",
+	}
+	switch status {
+	case "task_postprocess_end":
+		t.Outputs = []api.TaskOutput{{ID: "0", Name: "0.png", ContentType: "image/png", URL: "https://example.com/wiro-sample/0.png"}}
+	case "task_error_full":
+		t.DebugError = "sample failure: the model stopped with an error"
+	}
+	return t
+}
+
+// postCallback sends payload to target and returns the status code and the
+// start of the reply body.
+func postCallback(ctx context.Context, target string, payload []byte) (int, string, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(payload))
+	if err != nil {
+		return 0, "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "wiro-cli/"+cliVersion()+" (webhook test)")
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, "", 0, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return resp.StatusCode, strings.TrimSpace(string(body)), time.Since(start), nil
+}
+
+// parseCallbackURL checks a --callback-url or webhook test target.
+func parseCallbackURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("callback URL %q must be an absolute http or https URL", raw)
+	}
+	return raw, nil
+}
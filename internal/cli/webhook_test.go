@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+func TestWebhookTestPostsCallbackPayload(t *testing.T) {
+	var got api.TaskDetailResponse
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("content type = %q", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	if err := webhookTestCommand(context.Background(), nil, []string{srv.URL, "--status", "failed"}); err != nil {
+		t.Fatalf("webhook test: %v", err)
+	}
+	if !got.Result || len(got.TaskList) != 1 || got.TaskList[0].Status != "task_error_full" {
+		t.Fatalf("payload = %+v", got)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	if err := webhookTestCommand(context.Background(), nil, []string{failing.URL}); err == nil {
+		t.Fatal("a 500 reply should fail the command")
+	}
+	if err := webhookTestCommand(context.Background(), nil, []string{"ftp://example.com"}); err == nil {
+		t.Fatal("a non-http URL should be rejected")
+	}
+}
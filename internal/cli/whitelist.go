@@ -0,0 +1,202 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	projectsvc "github.com/wiro-ai/wiro-cli/internal/project"
+)
+
+const whitelistUsage = "usage: wiro project whitelist <ls|add|rm> [ip|cidr ...] [--project name|apikey] [--force] [--json]"
+
+func projectWhitelistCommand(ctx context.Context, app *App, args []string) error {
+	if len(args) == 0 {
+		return errors.New(whitelistUsage)
+	}
+	sub := strings.TrimSpace(args[0])
+	if sub == "--help" || sub == "-h" || sub == "help" {
+		output.Println("Usage: " + strings.TrimPrefix(whitelistUsage, "usage: "))
+		return nil
+	}
+	if sub != "ls" && sub != "list" && sub != "add" && sub != "rm" && sub != "remove" {
+		return fmt.Errorf("unknown whitelist command %q", sub)
+	}
+
+	fs := flag.NewFlagSet("project whitelist "+sub, flag.ContinueOnError)
+	var selector string
+	var force, asJSON bool
+	fs.StringVar(&selector, "project", "", "Project name or API key (default: selected project)")
+	fs.BoolVar(&force, "force", false, "Apply even if it blocks your current public IP")
+	fs.BoolVar(&asJSON, "json", false, "JSON output")
+
+	var entries []string
+	rest := args[1:]
+	for len(rest) > 0 && !strings.HasPrefix(rest[0], "-") {
+		entries, rest = append(entries, rest[0]), rest[1:]
+	}
+	if err := fs.Parse(rest); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	entries = append(entries, fs.Args()...)
+
+	profile := projectsvc.ResolveSelected(app.Config, selector)
+	if profile == nil {
+		if selector != "" {
+			return fmt.Errorf("project %q not found in local config", selector)
+		}
+		return errors.New("no default project selected; pass --project or run `wiro project use <name|apikey>`")
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	current, err := app.ProjectSvc.Whitelist(timeoutCtx, profile)
+	if err != nil {
+		return err
+	}
+
+	if sub == "ls" || sub == "list" {
+		if len(entries) != 0 {
+			return errors.New("usage: wiro project whitelist ls [--project name|apikey] [--json]")
+		}
+		return printWhitelist(profile, current, asJSON)
+	}
+
+	if len(entries) == 0 {
+		return fmt.Errorf("usage: wiro project whitelist %s <ip|cidr> ... [--project name|apikey] [--force]", sub)
+	}
+	normalized := make([]string, 0, len(entries))
+	for _, e := range entries {
+		n, err := projectsvc.NormalizeCIDR(e)
+		if err != nil {
+			return err
+		}
+		normalized = append(normalized, n)
+	}
+
+	var next []string
+	var changed int
+	if sub == "add" {
+		next, changed = whitelistAdd(current, normalized)
+	} else {
+		next, changed = whitelistRemove(current, normalized)
+	}
+	if changed == 0 {
+		output.Println("Whitelist unchanged.")
+		return printWhitelist(profile, current, asJSON)
+	}
+	if err := checkLockout(ctx, current, next, force); err != nil {
+		return err
+	}
+	if err := app.ProjectSvc.SetWhitelist(timeoutCtx, profile, next); err != nil {
+		return err
+	}
+	if !asJSON {
+		verb := "Added"
+		if sub != "add" {
+			verb = "Removed"
+		}
+		output.Printf("%s %d whitelist entry(s) for %s.\n", verb, changed, profile.Name)
+	}
+	return printWhitelist(profile, next, asJSON)
+}
+
+// whitelistAdd appends entries not already in list.
+func whitelistAdd(list, entries []string) ([]string, int) {
+	next := append([]string(nil), list...)
+	added := 0
+	for _, e := range entries {
+		if containsEntry(next, e) {
+			continue
+		}
+		next = append(next, e)
+		added++
+	}
+	return next, added
+}
+
+// whitelistRemove drops entries from list, comparing normalized forms.
+func whitelistRemove(list, entries []string) ([]string, int) {
+	next := make([]string, 0, len(list))
+	for _, existing := range list {
+		if !containsEntry(entries, existing) {
+			next = append(next, existing)
+		}
+	}
+	return next, len(list) - len(next)
+}
+
+func containsEntry(list []string, entry string) bool {
+	key := entry
+	if n, err := projectsvc.NormalizeCIDR(entry); err == nil {
+		key = n
+	}
+	for _, e := range list {
+		if e == entry {
+			return true
+		}
+		if n, err := projectsvc.NormalizeCIDR(e); err == nil && n == key {
+			return true
+		}
+	}
+	return false
+}
+
+// checkLockout refuses a change that would stop the current public IP from
+// calling the API, unless forced or confirmed interactively.
+func checkLockout(ctx context.Context, current, next []string, force bool) error {
+	if len(next) == 0 {
+		return nil
+	}
+	lookupCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	ip, err := projectsvc.PublicIP(lookupCtx)
+	if err != nil {
+		output.Warnf("could not determine your public IP (%v); the new whitelist may lock you out", err)
+		return nil
+	}
+	if projectsvc.Allows(next, ip) || !projectsvc.Allows(current, ip) {
+		return nil
+	}
+	output.Warnf("your current public IP %s is not covered by the new whitelist; requests from this machine will be rejected", ip)
+	if force {
+		return nil
+	}
+	if !isInteractiveSession() {
+		return errors.New("refusing to lock out your current IP; pass --force to apply anyway")
+	}
+	ok, err := promptConfirm("Apply the whitelist anyway?", false)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("whitelist change aborted")
+	}
+	return nil
+}
+
+func printWhitelist(profile *config.ProjectProfile, list []string, asJSON bool) error {
+	if asJSON {
+		if list == nil {
+			list = []string{}
+		}
+		return output.PrintJSON(map[string]interface{}{"project": profile.Name, "apikey": profile.APIKey, "ipwhitelist": list})
+	}
+	if len(list) == 0 {
+		output.Printf("No IP whitelist for %s; requests are accepted from any IP.\n", profile.Name)
+		return nil
+	}
+	t := output.NewTable("ENTRY")
+	for _, e := range list {
+		t.Row(e)
+	}
+	return t.Print()
+}
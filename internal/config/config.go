@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 const legacyOutputDir = "./wiro-outputs"
@@ -16,12 +17,77 @@ type ProjectProfile struct {
 	Name           string `json:"name"`
 	APIKey         string `json:"apiKey"`
 	AuthMethodHint string `json:"authMethodHint"`
+	// Account binds the project to a signed-in account (email), whose token it
+	// falls back to instead of the active account's.
+	Account string `json:"account,omitempty"`
+	// Budget is an optional soft limit on what the CLI submits for this project.
+	Budget *Budget `json:"budget,omitempty"`
+	// Notify reports watched tasks of this project when they finish.
+	Notify *Notify `json:"notify,omitempty"`
+}
+
+// Notify selects how a finished task is reported.
+type Notify struct {
+	// Desktop shows a system notification.
+	Desktop bool `json:"desktop,omitempty"`
+	// Bell rings the terminal bell.
+	Bell bool `json:"bell,omitempty"`
+	// Command runs through the shell with the final task as JSON on stdin.
+	Command string `json:"command,omitempty"`
+}
+
+// IsZero reports whether no notification is enabled.
+func (n *Notify) IsZero() bool {
+	return n == nil || (!n.Desktop && !n.Bell && strings.TrimSpace(n.Command) == "")
+}
+
+// Budget limits daily submissions for a project. Days are local calendar days.
+type Budget struct {
+	// TasksPerDay caps submitted tasks; 0 means no cap.
+	TasksPerDay int `json:"tasksPerDay,omitempty"`
+	// CreditsPerDay caps the summed estimated cost of submitted tasks; 0 means no cap.
+	CreditsPerDay float64 `json:"creditsPerDay,omitempty"`
+	// Block refuses submissions over budget instead of warning.
+	Block bool `json:"block,omitempty"`
+}
+
+// IsZero reports whether the budget sets no limit.
+func (b *Budget) IsZero() bool {
+	return b == nil || (b.TasksPerDay <= 0 && b.CreditsPerDay <= 0)
 }
 
 // Preferences stores simple CLI defaults.
 type Preferences struct {
 	WatchDefault     bool   `json:"watchDefault"`
 	OutputDirDefault string `json:"outputDirDefault"`
+	// WatchTimeout is a Go duration such as "90m"; empty or "0" means no limit.
+	WatchTimeout string `json:"watchTimeout,omitempty"`
+	// MaxResponseMB caps API response bodies; 0 uses the built-in default.
+	MaxResponseMB int `json:"maxResponseMB,omitempty"`
+	// DedupeOutputs reuses identical, already downloaded files instead of fetching them again.
+	DedupeOutputs bool `json:"dedupeOutputs,omitempty"`
+	// RequestsPerSecond limits API requests across all workers; 0 means no limit.
+	RequestsPerSecond float64 `json:"requestsPerSecond,omitempty"`
+	// RequestBurst is how many requests may go out at once under RequestsPerSecond (default 1).
+	RequestBurst int `json:"requestBurst,omitempty"`
+	// Storage selects where history and caches are kept: jsonl (default) or sqlite.
+	Storage string `json:"storage,omitempty"`
+	// OutputNameTemplate lays out downloaded files below the output directory,
+	// e.g. "{model}/{date}/{taskid}-{index}{ext}"; empty is one folder per task.
+	OutputNameTemplate string `json:"outputNameTemplate,omitempty"`
+}
+
+// WatchTimeoutDuration parses WatchTimeout.
+func (p Preferences) WatchTimeoutDuration() (time.Duration, error) {
+	raw := strings.TrimSpace(p.WatchTimeout)
+	if raw == "" || raw == "0" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid preferences.watchTimeout %q (use a duration like 90m or 0 for no limit)", p.WatchTimeout)
+	}
+	return d, nil
 }
 
 // Config is persisted under ~/.config/wiro/config.json.
@@ -29,6 +95,23 @@ type Config struct {
 	DefaultProject string           `json:"defaultProject"`
 	Projects       []ProjectProfile `json:"projects"`
 	Preferences    Preferences      `json:"preferences"`
+	// APIBaseURL overrides the Wiro API endpoint; empty uses the public API.
+	APIBaseURL string `json:"apiBaseUrl,omitempty"`
+	// Aliases maps short names to models, managed with `wiro alias`.
+	Aliases map[string]ModelAlias `json:"aliases,omitempty"`
+	// Favorites are starred models (owner/model) in the order they were added,
+	// managed with `wiro model fav`.
+	Favorites []string `json:"favorites,omitempty"`
+	// ActiveAccount is the signed-in account (email) used by default, managed
+	// with `wiro auth switch`; empty is the token stored before accounts were tracked.
+	ActiveAccount string `json:"activeAccount,omitempty"`
+}
+
+// ModelAlias is a short name for a model plus --set values applied when the
+// alias is used; flags on the command line override them.
+type ModelAlias struct {
+	Model string   `json:"model"`
+	Set   []string `json:"set,omitempty"`
 }
 
 func defaultConfig() Config {
@@ -72,6 +155,11 @@ func configDir() (string, error) {
 	return filepath.Join(base, "wiro"), nil
 }
 
+// Dir returns the base config directory (<UserConfigDir>/wiro).
+func Dir() (string, error) {
+	return configDir()
+}
+
 // ConfigPath returns the absolute config file path.
 func ConfigPath() (string, error) {
 	dir, err := configDir()
@@ -153,8 +241,41 @@ func (c *Config) UpsertProject(p ProjectProfile) {
 			if p.AuthMethodHint != "" {
 				c.Projects[i].AuthMethodHint = p.AuthMethodHint
 			}
+			if p.Account != "" {
+				c.Projects[i].Account = p.Account
+			}
 			return
 		}
 	}
 	c.Projects = append(c.Projects, p)
 }
+
+// IsFavorite reports whether model (owner/model) is starred.
+func (c Config) IsFavorite(model string) bool {
+	for _, f := range c.Favorites {
+		if strings.EqualFold(f, model) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddFavorite stars model; it reports false when it already was.
+func (c *Config) AddFavorite(model string) bool {
+	if c.IsFavorite(model) {
+		return false
+	}
+	c.Favorites = append(c.Favorites, model)
+	return true
+}
+
+// RemoveFavorite unstars model; it reports false when it was not starred.
+func (c *Config) RemoveFavorite(model string) bool {
+	for i, f := range c.Favorites {
+		if strings.EqualFold(f, model) {
+			c.Favorites = append(c.Favorites[:i], c.Favorites[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -39,3 +39,91 @@ func TestLoadMigratesLegacyOutputDir(t *testing.T) {
 		t.Fatalf("migrated output dir invalid: %s", cfg.Preferences.OutputDirDefault)
 	}
 }
+
+func TestConfigKeysSetGetUnset(t *testing.T) {
+	cfg := defaultConfig()
+	names := map[string]string{}
+	for _, k := range Keys() {
+		names[k.Name] = k.Type
+	}
+	if names["preferences.watchDefault"] != "bool" || names["apiBaseUrl"] != "string" || names["defaultProject"] != "string" {
+		t.Fatalf("unexpected keys: %v", names)
+	}
+	if _, ok := names["projects"]; ok {
+		t.Fatal("projects should not be a settable key")
+	}
+
+	if err := cfg.Set("preferences.watchDefault", "false"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if v, _ := cfg.Get("preferences.watchDefault"); v != "false" {
+		t.Fatalf("get = %q", v)
+	}
+	if err := cfg.Set("preferences.maxResponseMB", "abc"); err == nil {
+		t.Fatal("expected type error")
+	}
+	if err := cfg.Set("preferences.watchTimeout", "soon"); err == nil || cfg.Preferences.WatchTimeout != "" {
+		t.Fatalf("invalid duration should be rejected without changing config: %v", err)
+	}
+	if err := cfg.Set("apiBaseUrl", "ftp://x"); err == nil {
+		t.Fatal("expected url error")
+	}
+	if err := cfg.Set("preferences.outputNameTemplate", "{model}/{prompt}-{index}{ext}"); err == nil || cfg.Preferences.OutputNameTemplate != "" {
+		t.Fatalf("a template without {taskid} should be rejected: %v", err)
+	}
+	// This build registers no SQLite driver, so the sqlite backend is refused.
+	if err := cfg.Set("preferences.storage", "sqlite"); err == nil || cfg.Preferences.Storage != "" {
+		t.Fatalf("sqlite storage without a driver should be rejected: %v", err)
+	}
+	if err := cfg.Set("preferences.nope", "1"); err == nil {
+		t.Fatal("expected unknown key error")
+	}
+	if err := cfg.Unset("preferences.watchDefault"); err != nil || !cfg.Preferences.WatchDefault {
+		t.Fatalf("unset should restore default: %v %v", err, cfg.Preferences.WatchDefault)
+	}
+}
+
+func TestRecentTasksPerContext(t *testing.T) {
+	tmp := t.TempDir()
+	t.Setenv("HOME", tmp)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmp, ".config"))
+
+	a := TaskContext(&ProjectProfile{Name: "a", APIKey: "key-a"})
+	b := TaskContext(&ProjectProfile{Name: "b", APIKey: "key-b"})
+	var st State
+	st.SetLastTask(a, RecentTask{TaskID: "1", TaskToken: "tok-1"})
+	st.SetLastTask(b, RecentTask{TaskID: "2"})
+	if err := SaveState(st); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := loaded.LastTask(a); !ok || got.TaskID != "1" || got.TaskToken != "tok-1" {
+		t.Fatalf("LastTask(a) = %+v, %v", got, ok)
+	}
+	if got, ok := loaded.LastTask(b); !ok || got.TaskID != "2" {
+		t.Fatalf("LastTask(b) = %+v, %v", got, ok)
+	}
+	if _, ok := loaded.LastTask(TaskContext(nil)); ok {
+		t.Fatal("account context should have no last task")
+	}
+}
+
+func TestFavorites(t *testing.T) {
+	var c Config
+	if !c.AddFavorite("wiro/flux") || c.AddFavorite("Wiro/Flux") {
+		t.Fatal("AddFavorite should add once, ignoring case")
+	}
+	c.AddFavorite("wiro/whisper")
+	if !c.IsFavorite("WIRO/flux") || c.IsFavorite("wiro/sdxl") {
+		t.Fatalf("IsFavorite wrong for %v", c.Favorites)
+	}
+	if !c.RemoveFavorite("wiro/FLUX") || c.RemoveFavorite("wiro/flux") {
+		t.Fatal("RemoveFavorite should remove once, ignoring case")
+	}
+	if len(c.Favorites) != 1 || c.Favorites[0] != "wiro/whisper" {
+		t.Fatalf("Favorites = %v", c.Favorites)
+	}
+}
@@ -0,0 +1,251 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/output"
+	"github.com/wiro-ai/wiro-cli/internal/storage"
+)
+
+// Key is one scalar setting addressable by a dotted JSON path such as
+// preferences.watchDefault.
+type Key struct {
+	Name string
+	// Type is string, bool, int, or number.
+	Type string
+}
+
+// validators check values beyond their type after Set.
+var validators = map[string]func(Config) error{
+	"preferences.watchTimeout": func(c Config) error {
+		_, err := c.Preferences.WatchTimeoutDuration()
+		return err
+	},
+	"preferences.maxResponseMB": func(c Config) error {
+		if c.Preferences.MaxResponseMB < 0 {
+			return fmt.Errorf("preferences.maxResponseMB must not be negative")
+		}
+		return nil
+	},
+	"preferences.requestsPerSecond": func(c Config) error {
+		if c.Preferences.RequestsPerSecond < 0 {
+			return fmt.Errorf("preferences.requestsPerSecond must not be negative")
+		}
+		return nil
+	},
+	"preferences.requestBurst": func(c Config) error {
+		if c.Preferences.RequestBurst < 0 {
+			return fmt.Errorf("preferences.requestBurst must not be negative")
+		}
+		return nil
+	},
+	"preferences.storage": func(c Config) error {
+		switch c.Preferences.Storage {
+		case "", storage.BackendJSONL:
+			return nil
+		case storage.BackendSQLite:
+			if storage.SQLiteAvailable() {
+				return nil
+			}
+			return fmt.Errorf("preferences.storage sqlite needs a build with a SQLite database/sql driver, and this one has none")
+		}
+		return fmt.Errorf("preferences.storage must be jsonl or sqlite, got %q", c.Preferences.Storage)
+	},
+	"preferences.outputNameTemplate": func(c Config) error {
+		_, err := output.ParseNameTemplate(c.Preferences.OutputNameTemplate)
+		return err
+	},
+	"apiBaseUrl": func(c Config) error {
+		if c.APIBaseURL == "" {
+			return nil
+		}
+		u, err := url.Parse(c.APIBaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("apiBaseUrl must be an http(s) URL, got %q", c.APIBaseURL)
+		}
+		return nil
+	},
+}
+
+// Keys lists the settable keys in file order. Lists such as projects are
+// managed by their own commands and are not included.
+func Keys() []Key {
+	var keys []Key
+	walkKeys(reflect.TypeOf(Config{}), "", func(name string, f reflect.StructField) {
+		keys = append(keys, Key{Name: name, Type: kindName(f.Type.Kind())})
+	})
+	return keys
+}
+
+// Get returns the value of key formatted as text.
+func (c Config) Get(key string) (string, error) {
+	v, err := fieldByKey(reflect.ValueOf(&c).Elem(), key)
+	if err != nil {
+		return "", err
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int:
+		return strconv.FormatInt(v.Int(), 10), nil
+	default:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	}
+}
+
+// Set parses raw as key's type and stores it.
+func (c *Config) Set(key, raw string) error {
+	next := *c
+	v, err := fieldByKey(reflect.ValueOf(&next).Elem(), key)
+	if err != nil {
+		return err
+	}
+	raw = strings.TrimSpace(raw)
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("%s expects true or false, got %q", key, raw)
+		}
+		v.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s expects an integer, got %q", key, raw)
+		}
+		v.SetInt(n)
+	default:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("%s expects a number, got %q", key, raw)
+		}
+		v.SetFloat(f)
+	}
+	if validate := validators[key]; validate != nil {
+		if err := validate(next); err != nil {
+			return err
+		}
+	}
+	*c = next
+	return nil
+}
+
+// Unset restores key to its default value.
+func (c *Config) Unset(key string) error {
+	def := defaultConfig()
+	from, err := fieldByKey(reflect.ValueOf(&def).Elem(), key)
+	if err != nil {
+		return err
+	}
+	to, err := fieldByKey(reflect.ValueOf(c).Elem(), key)
+	if err != nil {
+		return err
+	}
+	to.Set(from)
+	return nil
+}
+
+func fieldByKey(root reflect.Value, key string) (reflect.Value, error) {
+	v := root
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		if v.Kind() != reflect.Struct {
+			break
+		}
+		next, ok := fieldByTag(v, part)
+		if !ok {
+			break
+		}
+		v = next
+		if i == len(parts)-1 {
+			if kindName(v.Kind()) == "" {
+				break
+			}
+			return v, nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("unknown config key %q (see `wiro config list`)", key)
+}
+
+func fieldByTag(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if jsonName(t.Field(i)) == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+func walkKeys(t reflect.Type, prefix string, fn func(string, reflect.StructField)) {
+	var names []string
+	fields := map[string]reflect.StructField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := jsonName(f)
+		if name == "" {
+			continue
+		}
+		names = append(names, name)
+		fields[name] = f
+	}
+	// Top-level scalars first, then nested groups, so related keys stay together.
+	sort.SliceStable(names, func(i, j int) bool {
+		return fields[names[i]].Type.Kind() != reflect.Struct && fields[names[j]].Type.Kind() == reflect.Struct
+	})
+	for _, name := range names {
+		f := fields[name]
+		switch {
+		case f.Type.Kind() == reflect.Struct:
+			walkKeys(f.Type, prefix+name+".", fn)
+		case kindName(f.Type.Kind()) != "":
+			fn(prefix+name, f)
+		}
+	}
+}
+
+func jsonName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	if tag == "-" || !f.IsExported() {
+		return ""
+	}
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return f.Name
+}
+
+func kindName(k reflect.Kind) string {
+	switch k {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int:
+		return "int"
+	case reflect.Float64:
+		return "number"
+	}
+	return ""
+}
+
+// Validate runs the value checks Set applies to every key.
+func (c Config) Validate() error {
+	for _, k := range Keys() {
+		if validate := validators[k.Name]; validate != nil {
+			if err := validate(c); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
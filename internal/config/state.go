@@ -6,13 +6,102 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 )
 
 // State stores lightweight runtime state.
 type State struct {
 	PendingVerifyToken string `json:"pendingVerifyToken"`
-	LastTaskID         string `json:"lastTaskId"`
-	LastTaskToken      string `json:"lastTaskToken"`
+	// TokenIssuedAt and TokenExpiresAt (RFC 3339) describe the bearer token
+	// stored before accounts were tracked by email; the expiry is empty when
+	// the token does not carry one.
+	TokenIssuedAt  string `json:"tokenIssuedAt,omitempty"`
+	TokenExpiresAt string `json:"tokenExpiresAt,omitempty"`
+	// PendingVerifyEmail is the account a pending verification signs in.
+	PendingVerifyEmail string `json:"pendingVerifyEmail,omitempty"`
+	// Accounts describes the tokens of the accounts signed in by email.
+	Accounts map[string]AccountToken `json:"accounts,omitempty"`
+	// RecentTasks holds the last submitted task per auth context (see TaskContext),
+	// so `@last` never resolves to another project's task.
+	RecentTasks map[string]RecentTask `json:"recentTasks,omitempty"`
+}
+
+// AccountToken describes the stored bearer token of one account.
+type AccountToken struct {
+	IssuedAt  string `json:"tokenIssuedAt,omitempty"`
+	ExpiresAt string `json:"tokenExpiresAt,omitempty"`
+}
+
+// Token returns what is known about the token of account; "" is the token
+// stored before accounts were tracked.
+func (s State) Token(account string) AccountToken {
+	if account == "" {
+		return AccountToken{IssuedAt: s.TokenIssuedAt, ExpiresAt: s.TokenExpiresAt}
+	}
+	return s.Accounts[account]
+}
+
+// SetToken records the token of account.
+func (s *State) SetToken(account string, t AccountToken) {
+	if account == "" {
+		s.TokenIssuedAt, s.TokenExpiresAt = t.IssuedAt, t.ExpiresAt
+		return
+	}
+	if s.Accounts == nil {
+		s.Accounts = map[string]AccountToken{}
+	}
+	s.Accounts[account] = t
+}
+
+// ForgetToken drops what is known about the token of account.
+func (s *State) ForgetToken(account string) {
+	if account == "" {
+		s.TokenIssuedAt, s.TokenExpiresAt = "", ""
+		return
+	}
+	delete(s.Accounts, account)
+}
+
+// AccountEmails lists the accounts signed in by email, sorted.
+func (s State) AccountEmails() []string {
+	emails := make([]string, 0, len(s.Accounts))
+	for email := range s.Accounts {
+		emails = append(emails, email)
+	}
+	sort.Strings(emails)
+	return emails
+}
+
+// RecentTask is the last task submitted in one auth context.
+type RecentTask struct {
+	TaskID      string `json:"taskId"`
+	TaskToken   string `json:"taskToken,omitempty"`
+	Model       string `json:"model,omitempty"`
+	SubmittedAt string `json:"submittedAt,omitempty"`
+}
+
+// TaskContext keys recent-task state: the project's API key, or "account" for
+// bearer-only sessions without a project.
+func TaskContext(profile *ProjectProfile) string {
+	if profile == nil || strings.TrimSpace(profile.APIKey) == "" {
+		return "account"
+	}
+	return "project:" + strings.TrimSpace(profile.APIKey)
+}
+
+// SetLastTask records t as the last task of context key.
+func (s *State) SetLastTask(key string, t RecentTask) {
+	if s.RecentTasks == nil {
+		s.RecentTasks = map[string]RecentTask{}
+	}
+	s.RecentTasks[key] = t
+}
+
+// LastTask returns the last task of context key.
+func (s State) LastTask(key string) (RecentTask, bool) {
+	t, ok := s.RecentTasks[key]
+	return t, ok && t.TaskID != ""
 }
 
 func statePath() (string, error) {
@@ -0,0 +1,79 @@
+// Package gitinfo captures the state of the git checkout a run was started from.
+package gitinfo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ErrNotRepo is returned when the directory is not inside a git work tree.
+var ErrNotRepo = errors.New("not inside a git repository")
+
+// Info is the code state that produced a run. Branch is empty on a detached HEAD.
+type Info struct {
+	Commit string `json:"commit"`
+	Branch string `json:"branch,omitempty"`
+	Dirty  bool   `json:"dirty"`
+}
+
+// String renders the state as branch@shortcommit, with a dirty marker.
+func (i Info) String() string {
+	s := i.Commit
+	if len(s) > 12 {
+		s = s[:12]
+	}
+	if i.Branch != "" {
+		s = i.Branch + "@" + s
+	}
+	if i.Dirty {
+		s += " (dirty)"
+	}
+	return s
+}
+
+// Detect reads the commit, branch, and dirty state of the work tree containing dir
+// ("" for the current directory). Untracked files do not count as dirty.
+func Detect(ctx context.Context, dir string) (*Info, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("git not found: %w", err)
+	}
+	inside, err := git(ctx, dir, "rev-parse", "--is-inside-work-tree")
+	if err != nil || inside != "true" {
+		return nil, ErrNotRepo
+	}
+	commit, err := git(ctx, dir, "rev-parse", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("read HEAD: %w", err)
+	}
+	info := &Info{Commit: commit}
+	if branch, err := git(ctx, dir, "rev-parse", "--abbrev-ref", "HEAD"); err == nil && branch != "HEAD" {
+		info.Branch = branch
+	}
+	status, err := git(ctx, dir, "status", "--porcelain", "--untracked-files=no")
+	if err != nil {
+		return nil, fmt.Errorf("read status: %w", err)
+	}
+	info.Dirty = status != ""
+	return info, nil
+}
+
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
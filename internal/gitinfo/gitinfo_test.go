@@ -0,0 +1,60 @@
+package gitinfo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetect(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	ctx := context.Background()
+	dir := t.TempDir()
+	if _, err := Detect(ctx, dir); !errors.Is(err, ErrNotRepo) {
+		t.Fatalf("Detect outside repo = %v, want ErrNotRepo", err)
+	}
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=t", "-c", "user.email=t@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	run("init", "-q", "-b", "main")
+	file := filepath.Join(dir, "a.txt")
+	if err := os.WriteFile(file, []byte("one"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	run("add", "a.txt")
+	run("commit", "-q", "-m", "init")
+
+	info, err := Detect(ctx, dir)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if info.Branch != "main" || len(info.Commit) != 40 || info.Dirty {
+		t.Fatalf("clean info = %+v", info)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "untracked.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ = Detect(ctx, dir); info.Dirty {
+		t.Fatal("untracked file marked the tree dirty")
+	}
+	if err := os.WriteFile(file, []byte("two"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if info, _ = Detect(ctx, dir); !info.Dirty {
+		t.Fatal("modified file did not mark the tree dirty")
+	}
+	if got := info.String(); got != "main@"+info.Commit[:12]+" (dirty)" {
+		t.Fatalf("String = %q", got)
+	}
+}
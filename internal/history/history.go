@@ -0,0 +1,268 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/gitinfo"
+	"github.com/wiro-ai/wiro-cli/internal/storage"
+)
+
+// maxEntries is how many runs are kept when the log is compacted.
+var maxEntries = 1000
+
+// Entry is one submitted run. Updates are appended as new lines with the same ID;
+// the last line for an ID wins.
+type Entry struct {
+	ID        int      `json:"id"`
+	CreatedAt string   `json:"createdAt"`
+	UpdatedAt string   `json:"updatedAt"`
+	Project   string   `json:"project,omitempty"`
+	Owner     string   `json:"owner"`
+	Model     string   `json:"model"`
+	Set       []string `json:"set,omitempty"`
+	SetFile   []string `json:"setFile,omitempty"`
+	SetURL    []string `json:"setUrl,omitempty"`
+	Summary   string   `json:"summary,omitempty"`
+	TaskID    string   `json:"taskId,omitempty"`
+	TaskToken string   `json:"taskToken,omitempty"`
+	Status    string   `json:"status,omitempty"`
+	OutputDir string   `json:"outputDir,omitempty"`
+	Outputs   []string `json:"outputs,omitempty"`
+	Error     string   `json:"error,omitempty"`
+	// EstimatedCost is the model's price estimate for the inputs, when published.
+	EstimatedCost float64 `json:"estimatedCost,omitempty"`
+	// Git is the code state the run was started from (--git-context).
+	Git *gitinfo.Info `json:"git,omitempty"`
+	// DurationSeconds is how long the task ran, from its start to end time.
+	DurationSeconds float64 `json:"durationSeconds,omitempty"`
+	// Source is "remote" for tasks imported by `wiro history sync`, which were
+	// submitted elsewhere (the web dashboard, the API, another machine).
+	Source string `json:"source,omitempty"`
+	// SecretFields are fields whose values were recorded as model.SecretMask;
+	// a rerun needs them passed again.
+	SecretFields []string `json:"secretFields,omitempty"`
+}
+
+// SourceRemote marks entries imported from the account's task list.
+const SourceRemote = "remote"
+
+// Store persists run history in the history collection of a storage backend.
+type Store struct {
+	st storage.Storage
+	mu sync.Mutex
+}
+
+// NewStore returns a store backed by <dir>/history.jsonl.
+func NewStore(dir string) *Store {
+	return OpenStore(storage.NewJSONL(dir))
+}
+
+// OpenStore returns a store keeping runs in st.
+func OpenStore(st storage.Storage) *Store {
+	return &Store{st: st}
+}
+
+// List returns all runs, newest first.
+func (s *Store) List() ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, _, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID > entries[j].ID })
+	return entries, nil
+}
+
+// Get returns the run with id.
+func (s *Store) Get(id int) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, _, err := s.load()
+	if err != nil {
+		return Entry{}, err
+	}
+	for _, e := range entries {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return Entry{}, fmt.Errorf("history entry %d not found", id)
+}
+
+// Add records a new run and returns it with its assigned id.
+func (s *Store) Add(e Entry) (Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, lines, err := s.load()
+	if err != nil {
+		return Entry{}, err
+	}
+	for _, prev := range entries {
+		if prev.ID > e.ID {
+			e.ID = prev.ID
+		}
+	}
+	e.ID++
+	now := time.Now().UTC().Format(time.RFC3339)
+	e.CreatedAt = now
+	e.UpdatedAt = now
+	if lines+1 > 2*maxEntries {
+		return e, s.rewrite(append(entries, e))
+	}
+	return e, s.append(e)
+}
+
+// Merge imports tasks listed by the API. Tasks not recorded yet are added
+// oldest first, keeping their CreatedAt; recorded tasks take the remote status
+// and duration when those changed. Entries without a TaskID are ignored.
+func (s *Store) Merge(remote []Entry) (added, updated int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, lines, err := s.load()
+	if err != nil {
+		return 0, 0, err
+	}
+	// entries is in id order, so the newest run of a task wins.
+	byTask := map[string]int{}
+	nextID := 0
+	for i, e := range entries {
+		if e.TaskID != "" {
+			byTask[e.TaskID] = i
+		}
+		if e.ID > nextID {
+			nextID = e.ID
+		}
+	}
+	remote = append([]Entry(nil), remote...)
+	sort.SliceStable(remote, func(i, j int) bool { return remote[i].CreatedAt < remote[j].CreatedAt })
+	now := time.Now().UTC().Format(time.RFC3339)
+	var changed []Entry
+	for _, r := range remote {
+		if r.TaskID == "" {
+			continue
+		}
+		if i, ok := byTask[r.TaskID]; ok {
+			local := &entries[i]
+			if (r.Status == "" || r.Status == local.Status) && (r.DurationSeconds == 0 || r.DurationSeconds == local.DurationSeconds) {
+				continue
+			}
+			if r.Status != "" {
+				local.Status = r.Status
+			}
+			if r.DurationSeconds > 0 {
+				local.DurationSeconds = r.DurationSeconds
+			}
+			local.UpdatedAt = now
+			changed = append(changed, *local)
+			updated++
+			continue
+		}
+		nextID++
+		r.ID = nextID
+		if r.CreatedAt == "" {
+			r.CreatedAt = now
+		}
+		r.UpdatedAt = now
+		entries = append(entries, r)
+		byTask[r.TaskID] = len(entries) - 1
+		changed = append(changed, r)
+		added++
+	}
+	if len(changed) == 0 {
+		return 0, 0, nil
+	}
+	if lines+len(changed) > 2*maxEntries {
+		return added, updated, s.rewrite(entries)
+	}
+	for _, e := range changed {
+		if err := s.append(e); err != nil {
+			return added, updated, err
+		}
+	}
+	return added, updated, nil
+}
+
+// UpdateTask applies fn to the newest run for taskID. It is a no-op when the
+// task was not recorded (e.g. submitted before history existed).
+func (s *Store) UpdateTask(taskID string, fn func(e *Entry)) error {
+	if taskID == "" {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries, _, err := s.load()
+	if err != nil {
+		return err
+	}
+	var target *Entry
+	for i := range entries {
+		if entries[i].TaskID == taskID && (target == nil || entries[i].ID > target.ID) {
+			target = &entries[i]
+		}
+	}
+	if target == nil {
+		return nil
+	}
+	fn(target)
+	target.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	return s.append(*target)
+}
+
+// load returns the latest version of each entry in id order and the raw record count.
+func (s *Store) load() ([]Entry, int, error) {
+	records, err := s.st.Records(storage.History)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read history: %w", err)
+	}
+	byID := map[int]Entry{}
+	for _, raw := range records {
+		var e Entry
+		// A record that no longer parses is skipped rather than failing every command.
+		if err := json.Unmarshal(raw, &e); err != nil || e.ID == 0 {
+			continue
+		}
+		byID[e.ID] = e
+	}
+	entries := make([]Entry, 0, len(byID))
+	for _, e := range byID {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	return entries, len(records), nil
+}
+
+func (s *Store) append(e Entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("marshal history entry: %w", err)
+	}
+	if err := s.st.Put(storage.History, strconv.Itoa(e.ID), data); err != nil {
+		return fmt.Errorf("write history: %w", err)
+	}
+	return nil
+}
+
+// rewrite compacts the log to one record per entry, keeping the newest maxEntries.
+func (s *Store) rewrite(entries []Entry) error {
+	if len(entries) > maxEntries {
+		entries = entries[len(entries)-maxEntries:]
+	}
+	records := make([]storage.Record, 0, len(entries))
+	for _, e := range entries {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("marshal history entry: %w", err)
+		}
+		records = append(records, storage.Record{Key: strconv.Itoa(e.ID), Data: data})
+	}
+	if err := s.st.Replace(storage.History, records); err != nil {
+		return fmt.Errorf("rewrite history: %w", err)
+	}
+	return nil
+}
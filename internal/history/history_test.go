@@ -0,0 +1,116 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore_AddUpdateList(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+	first, err := store.Add(Entry{Owner: "o", Model: "a", TaskID: "11"})
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	second, err := store.Add(Entry{Owner: "o", Model: "b", TaskID: "12"})
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if first.ID != 1 || second.ID != 2 {
+		t.Fatalf("unexpected ids: %d %d", first.ID, second.ID)
+	}
+	if err := store.UpdateTask("11", func(e *Entry) { e.Status = "task_postprocess_end" }); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if err := store.UpdateTask("unknown", func(e *Entry) { e.Status = "x" }); err != nil {
+		t.Fatalf("update unknown: %v", err)
+	}
+
+	// A torn trailing line must not break reads.
+	f, err := os.OpenFile(filepath.Join(dir, "history.jsonl"), os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"id":3,"own`)
+	f.Close()
+
+	entries, err := store.List()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(entries) != 2 || entries[0].ID != 2 || entries[1].Status != "task_postprocess_end" {
+		t.Fatalf("unexpected entries: %#v", entries)
+	}
+	got, err := store.Get(1)
+	if err != nil || got.Model != "a" {
+		t.Fatalf("get: %#v %v", got, err)
+	}
+	if _, err := store.Get(9); err == nil {
+		t.Fatal("expected error for missing entry")
+	}
+}
+
+func TestStore_CompactsOldEntries(t *testing.T) {
+	prev := maxEntries
+	maxEntries = 5
+	defer func() { maxEntries = prev }()
+
+	store := NewStore(t.TempDir())
+	for i := 0; i < 2*maxEntries+1; i++ {
+		if _, err := store.Add(Entry{Owner: "o", Model: "m"}); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+	}
+	entries, err := store.List()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(entries) != maxEntries || entries[0].ID != 2*maxEntries+1 {
+		t.Fatalf("unexpected compaction: %d entries, newest %d", len(entries), entries[0].ID)
+	}
+}
+
+func TestStore_Merge(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if _, err := store.Add(Entry{Owner: "o", Model: "a", TaskID: "11", Status: "task_queue"}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if _, err := store.Add(Entry{Owner: "o", Model: "b", TaskID: "12", Status: "task_postprocess_end"}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	remote := []Entry{
+		{Owner: "o", Model: "c", TaskID: "14", CreatedAt: "2026-01-02T00:00:00Z", Source: SourceRemote},
+		{Owner: "o", Model: "a", TaskID: "11", Status: "task_postprocess_end", DurationSeconds: 4},
+		{Owner: "o", Model: "b", TaskID: "12", Status: "task_postprocess_end"},
+		{Owner: "o", Model: "c", TaskID: "13", CreatedAt: "2026-01-01T00:00:00Z", Source: SourceRemote},
+		{Owner: "o", Model: "x"},
+	}
+	added, updated, err := store.Merge(remote)
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if added != 2 || updated != 1 {
+		t.Fatalf("merge = %d added, %d updated", added, updated)
+	}
+
+	entries, err := store.List()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("unexpected entries: %#v", entries)
+	}
+	// New tasks are added oldest first and keep their creation time.
+	if entries[0].TaskID != "14" || entries[1].TaskID != "13" || entries[1].CreatedAt != "2026-01-01T00:00:00Z" || entries[1].Source != SourceRemote {
+		t.Fatalf("unexpected new entries: %#v", entries[:2])
+	}
+	if got, _ := store.Get(1); got.Status != "task_postprocess_end" || got.DurationSeconds != 4 {
+		t.Fatalf("entry 1 not updated: %#v", got)
+	}
+
+	added, updated, err = store.Merge(remote)
+	if err != nil || added != 0 || updated != 0 {
+		t.Fatalf("second merge = %d added, %d updated, %v", added, updated, err)
+	}
+}
@@ -0,0 +1,112 @@
+package history
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ModelStat summarizes the recorded runs of one model.
+type ModelStat struct {
+	Model     string `json:"model"`
+	Runs      int    `json:"runs"`
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+	Cancelled int    `json:"cancelled"`
+	// AvgSeconds is the mean task duration of runs with a recorded duration.
+	AvgSeconds float64 `json:"avgSeconds,omitempty"`
+	// FailureRate is failed runs over finished (succeeded or failed) runs.
+	FailureRate float64 `json:"failureRate"`
+	LastUsed    string  `json:"lastUsed"`
+}
+
+// ModelStats aggregates entries created at or after since (zero = all) per
+// owner/model, most used first.
+func ModelStats(entries []Entry, since time.Time) []ModelStat {
+	byModel := map[string]*ModelStat{}
+	durations := map[string][]float64{}
+	for _, e := range entries {
+		created, err := time.Parse(time.RFC3339, e.CreatedAt)
+		if err != nil || (!since.IsZero() && created.Before(since)) {
+			continue
+		}
+		key := e.Owner + "/" + e.Model
+		s := byModel[key]
+		if s == nil {
+			s = &ModelStat{Model: key}
+			byModel[key] = s
+		}
+		s.Runs++
+		switch e.Status {
+		case "task_postprocess_end":
+			s.Succeeded++
+		case "task_error_full":
+			s.Failed++
+		case "task_cancel":
+			s.Cancelled++
+		}
+		if e.DurationSeconds > 0 {
+			durations[key] = append(durations[key], e.DurationSeconds)
+		}
+		if e.CreatedAt > s.LastUsed {
+			s.LastUsed = e.CreatedAt
+		}
+	}
+	stats := make([]ModelStat, 0, len(byModel))
+	for key, s := range byModel {
+		if d := durations[key]; len(d) > 0 {
+			total := 0.0
+			for _, v := range d {
+				total += v
+			}
+			s.AvgSeconds = total / float64(len(d))
+		}
+		if finished := s.Succeeded + s.Failed; finished > 0 {
+			s.FailureRate = float64(s.Failed) / float64(finished)
+		}
+		stats = append(stats, *s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Runs != stats[j].Runs {
+			return stats[i].Runs > stats[j].Runs
+		}
+		return stats[i].LastUsed > stats[j].LastUsed
+	})
+	return stats
+}
+
+// TaskDuration returns the time between a task's start and end times, which the
+// API reports as unix seconds or timestamps.
+func TaskDuration(start, end string) (time.Duration, bool) {
+	s, ok := parseTaskTime(start)
+	if !ok {
+		return 0, false
+	}
+	e, ok := parseTaskTime(end)
+	if !ok || e.Before(s) {
+		return 0, false
+	}
+	return e.Sub(s), true
+}
+
+// TaskTime parses a task time as the API reports it: unix seconds or a timestamp.
+func TaskTime(v string) (time.Time, bool) {
+	return parseTaskTime(v)
+}
+
+func parseTaskTime(v string) (time.Time, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" || v == "0" {
+		return time.Time{}, false
+	}
+	if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Unix(n, 0), true
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
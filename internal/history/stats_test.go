@@ -0,0 +1,42 @@
+package history
+
+import (
+	"testing"
+	"time"
+)
+
+func TestModelStats(t *testing.T) {
+	entries := []Entry{
+		{ID: 1, CreatedAt: "2024-01-01T10:00:00Z", Owner: "o", Model: "a", Status: "task_postprocess_end", DurationSeconds: 10},
+		{ID: 2, CreatedAt: "2024-01-02T10:00:00Z", Owner: "o", Model: "a", Status: "task_error_full", DurationSeconds: 30},
+		{ID: 3, CreatedAt: "2024-01-03T10:00:00Z", Owner: "o", Model: "a", Status: "task_cancel"},
+		{ID: 4, CreatedAt: "2024-01-04T10:00:00Z", Owner: "o", Model: "b"},
+		{ID: 5, CreatedAt: "2023-12-01T10:00:00Z", Owner: "o", Model: "c", Status: "task_postprocess_end"},
+	}
+	stats := ModelStats(entries, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 models after since filter, got %+v", stats)
+	}
+	a := stats[0]
+	if a.Model != "o/a" || a.Runs != 3 || a.Failed != 1 || a.Cancelled != 1 {
+		t.Fatalf("unexpected stats: %+v", a)
+	}
+	if a.FailureRate != 0.5 || a.AvgSeconds != 20 || a.LastUsed != "2024-01-03T10:00:00Z" {
+		t.Fatalf("unexpected aggregates: %+v", a)
+	}
+	if b := stats[1]; b.Model != "o/b" || b.FailureRate != 0 || b.AvgSeconds != 0 {
+		t.Fatalf("unexpected stats: %+v", b)
+	}
+}
+
+func TestTaskDuration(t *testing.T) {
+	if d, ok := TaskDuration("1700000000", "1700000042"); !ok || d != 42*time.Second {
+		t.Fatalf("unix duration = %v %v", d, ok)
+	}
+	if d, ok := TaskDuration("2024-01-01 10:00:00", "2024-01-01 10:01:30"); !ok || d != 90*time.Second {
+		t.Fatalf("timestamp duration = %v %v", d, ok)
+	}
+	if _, ok := TaskDuration("", "1700000042"); ok {
+		t.Fatal("missing start should not yield a duration")
+	}
+}
@@ -0,0 +1,97 @@
+// Package jsonout prints the versioned documents behind --json and describes
+// them as JSON Schema for --json-schema.
+//
+// Every object a command prints carries "schemaVersion". Within one version a
+// document only gains fields; renaming or removing a field, or changing its
+// type, bumps Version.
+package jsonout
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// Version is the schema version stamped on --json output.
+const Version = 1
+
+// VersionField is the name of the version property.
+const VersionField = "schemaVersion"
+
+// Print writes v to stdout as indented JSON, stamped with the schema version.
+func Print(v interface{}) error {
+	return Write(os.Stdout, v)
+}
+
+// Write writes v to w as indented JSON, stamped with the schema version.
+func Write(w io.Writer, v interface{}) error {
+	data, err := Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// Marshal encodes v as indented JSON with schemaVersion as the first property
+// of v, or of each element when v is an array, so list output keeps its shape.
+// Other values are encoded unchanged.
+func Marshal(v interface{}) ([]byte, error) {
+	var raw bytes.Buffer
+	if err := json.NewEncoder(&raw).Encode(v); err != nil {
+		return nil, err
+	}
+	stamped, err := stamp(bytes.TrimSpace(raw.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, stamped, "", "  "); err != nil {
+		return nil, err
+	}
+	out.WriteByte('\n')
+	return out.Bytes(), nil
+}
+
+func stamp(raw []byte) ([]byte, error) {
+	if len(raw) == 0 {
+		return raw, nil
+	}
+	switch raw[0] {
+	case '{':
+		return stampObject(raw), nil
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, fmt.Errorf("stamp schema version: %w", err)
+		}
+		var b bytes.Buffer
+		b.WriteByte('[')
+		for i, item := range items {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			item = bytes.TrimSpace(item)
+			if len(item) > 0 && item[0] == '{' {
+				item = stampObject(item)
+			}
+			b.Write(item)
+		}
+		b.WriteByte(']')
+		return b.Bytes(), nil
+	}
+	return raw, nil
+}
+
+// stampObject inserts the version into a compact JSON object.
+func stampObject(obj []byte) []byte {
+	head := `{"` + VersionField + `":` + strconv.Itoa(Version)
+	body := bytes.TrimSpace(obj[1:])
+	if len(body) > 0 && body[0] == '}' {
+		return []byte(head + "}")
+	}
+	return append([]byte(head+","), body...)
+}
@@ -0,0 +1,81 @@
+package jsonout
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type inner struct {
+	Result bool `json:"result"`
+}
+
+type sample struct {
+	inner
+	Name  string            `json:"name"`
+	Note  string            `json:"note,omitempty"`
+	Count *int              `json:"count"`
+	Tags  []string          `json:"tags"`
+	Extra map[string]string `json:"extra,omitempty"`
+	Raw   json.RawMessage   `json:"raw,omitempty"`
+	skip  string
+}
+
+func TestMarshal_StampsObjectsAndArrayElements(t *testing.T) {
+	got, err := Marshal(sample{Name: "a<b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{\n  \"schemaVersion\": 1,\n  \"result\": false,\n  \"name\": \"a\\u003cb\",\n  \"count\": null,\n  \"tags\": null\n}\n"
+	if string(got) != want {
+		t.Fatalf("object:\n%s\nwant:\n%s", got, want)
+	}
+	got, err = Marshal([]interface{}{map[string]int{}, map[string]int{"n": 2}, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var items []interface{}
+	if err := json.Unmarshal(got, &items); err != nil {
+		t.Fatalf("array output is not JSON: %v\n%s", err, got)
+	}
+	wantItems := []interface{}{
+		map[string]interface{}{"schemaVersion": 1.0},
+		map[string]interface{}{"schemaVersion": 1.0, "n": 2.0},
+		3.0,
+	}
+	if !reflect.DeepEqual(items, wantItems) {
+		t.Fatalf("array = %v", items)
+	}
+	if got, _ := Marshal([]string(nil)); string(got) != "null\n" {
+		t.Fatalf("nil slice = %q", got)
+	}
+}
+
+func TestSchemaFor(t *testing.T) {
+	s := SchemaFor("wiro sample --json", Document{Values: []interface{}{[]sample{}}})
+	if s["$schema"] != DraftURI || s["title"] != "wiro sample --json" {
+		t.Fatalf("header = %v", s)
+	}
+	items := s["items"].(Schema)
+	props := items["properties"].(Schema)
+	for _, name := range []string{VersionField, "result", "name", "note", "count", "tags", "extra", "raw"} {
+		if _, ok := props[name]; !ok {
+			t.Fatalf("missing property %q in %v", name, props)
+		}
+	}
+	if _, ok := props["skip"]; ok {
+		t.Fatal("unexported field in schema")
+	}
+	wantRequired := []string{VersionField, "name", "count", "tags", "result"}
+	if !reflect.DeepEqual(items["required"], wantRequired) {
+		t.Fatalf("required = %v, want %v", items["required"], wantRequired)
+	}
+	if typ := props["count"].(Schema)["type"]; !reflect.DeepEqual(typ, []string{"integer", "null"}) {
+		t.Fatalf("pointer type = %v", typ)
+	}
+
+	two := SchemaFor("t", Document{Values: []interface{}{inner{}, sample{}}})
+	if variants, ok := two["oneOf"].([]interface{}); !ok || len(variants) != 2 {
+		t.Fatalf("oneOf = %v", two["oneOf"])
+	}
+}
@@ -0,0 +1,219 @@
+package jsonout
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Document describes what one command prints with --json.
+type Document struct {
+	Description string
+	// Values are zero values of the printed types. A command printing more
+	// than one document (wiro run --json prints the submission, then the
+	// final task) lists each; its output matches one of them.
+	Values []interface{}
+}
+
+// Schema is a JSON Schema (draft 2020-12) object.
+type Schema map[string]interface{}
+
+// DraftURI identifies the JSON Schema dialect of Schema documents.
+const DraftURI = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	rawMessageType    = reflect.TypeOf(json.RawMessage(nil))
+	numberType        = reflect.TypeOf(json.Number(""))
+	timeType          = reflect.TypeOf(time.Time{})
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// SchemaFor returns the JSON Schema of doc's output under title, derived from
+// the printed types' json tags. Fields without omitempty are required.
+func SchemaFor(title string, doc Document) Schema {
+	var g generator
+	var variants []interface{}
+	for _, v := range doc.Values {
+		variants = append(variants, g.root(reflect.TypeOf(v)))
+	}
+	s := Schema{}
+	if len(variants) == 1 {
+		s = variants[0].(Schema)
+	} else {
+		s["oneOf"] = variants
+	}
+	s["$schema"] = DraftURI
+	s["title"] = title
+	if doc.Description != "" {
+		s["description"] = doc.Description
+	}
+	return s
+}
+
+type generator struct {
+	visiting map[reflect.Type]bool
+}
+
+// root is the schema of a top-level value: objects, and the objects of a
+// top-level array, carry the version property.
+func (g *generator) root(t reflect.Type) Schema {
+	s := g.schema(t)
+	stampSchema := func(s Schema) {
+		if s["type"] != "object" {
+			return
+		}
+		props, _ := s["properties"].(Schema)
+		if props == nil {
+			props = Schema{}
+			s["properties"] = props
+		}
+		props[VersionField] = Schema{"const": Version}
+		required, _ := s["required"].([]string)
+		s["required"] = append([]string{VersionField}, required...)
+	}
+	if items, ok := s["items"].(Schema); ok {
+		stampSchema(items)
+	} else {
+		stampSchema(s)
+	}
+	return s
+}
+
+func (g *generator) schema(t reflect.Type) Schema {
+	switch t {
+	case rawMessageType:
+		return Schema{}
+	case numberType:
+		return Schema{"type": "number"}
+	case timeType:
+		return Schema{"type": "string", "format": "date-time"}
+	}
+	if t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType) {
+		return Schema{}
+	}
+	if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return Schema{"type": "string"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return nullable(g.schema(t.Elem()))
+	case reflect.Interface:
+		return Schema{}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return Schema{"type": "string", "contentEncoding": "base64"}
+		}
+		// A nil slice encodes as null.
+		return Schema{"type": []string{"array", "null"}, "items": g.schema(t.Elem())}
+	case reflect.Array:
+		return Schema{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": []string{"object", "null"}, "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		return g.object(t)
+	}
+	// Channels and funcs cannot be encoded.
+	return Schema{}
+}
+
+func (g *generator) object(t reflect.Type) Schema {
+	if g.visiting[t] {
+		// A recursive type: accept any object below the first level.
+		return Schema{"type": "object"}
+	}
+	if g.visiting == nil {
+		g.visiting = map[reflect.Type]bool{}
+	}
+	g.visiting[t] = true
+	defer delete(g.visiting, t)
+
+	props := Schema{}
+	var required []string
+	g.fields(t, props, &required)
+	s := Schema{"type": "object", "properties": props}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// fields adds t's encoded fields to props, flattening embedded structs the
+// way encoding/json does. Outer fields win over promoted ones.
+func (g *generator) fields(t reflect.Type, props Schema, required *[]string) {
+	var embedded []reflect.Type
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		ft := f.Type
+		if f.Anonymous && name == "" {
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if _, dup := props[name]; dup {
+			continue
+		}
+		s := g.schema(ft)
+		if hasOption(opts, "string") {
+			s = Schema{"type": "string"}
+		}
+		props[name] = s
+		if !hasOption(opts, "omitempty") && !hasOption(opts, "omitzero") {
+			*required = append(*required, name)
+		}
+	}
+	for _, et := range embedded {
+		g.fields(et, props, required)
+	}
+}
+
+func hasOption(opts, want string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if o == want {
+			return true
+		}
+	}
+	return false
+}
+
+// nullable lets s also match null, as a nil pointer encodes.
+func nullable(s Schema) Schema {
+	switch typ := s["type"].(type) {
+	case string:
+		s["type"] = []string{typ, "null"}
+	case []string:
+		for _, t := range typ {
+			if t == "null" {
+				return s
+			}
+		}
+		s["type"] = append(typ, "null")
+	}
+	return s
+}
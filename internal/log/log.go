@@ -0,0 +1,141 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level controls how much diagnostic output is written.
+type Level int
+
+const (
+	LevelOff Level = iota
+	LevelVerbose
+	LevelDebug
+)
+
+var (
+	mu     sync.Mutex
+	level  = LevelOff
+	writer io.Writer
+	file   *os.File
+)
+
+var sensitiveHeaders = map[string]struct{}{
+	"authorization": {},
+	"x-signature":   {},
+	"x-api-secret":  {},
+	"cookie":        {},
+	"set-cookie":    {},
+}
+
+// SetLevel sets the global log level.
+func SetLevel(l Level) {
+	mu.Lock()
+	defer mu.Unlock()
+	level = l
+}
+
+// Enabled reports whether messages at l are written.
+func Enabled(l Level) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	return l != LevelOff && level >= l
+}
+
+// OpenFile additionally writes log lines to path; an empty path uses <configDir>/logs/wiro.log.
+func OpenFile(path, configDir string) (string, error) {
+	if strings.TrimSpace(path) == "" {
+		path = filepath.Join(configDir, "logs", "wiro.log")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", fmt.Errorf("create log dir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("open log file: %w", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if file != nil {
+		_ = file.Close()
+	}
+	file = f
+	return path, nil
+}
+
+// Close flushes and closes the log file, if any.
+func Close() error {
+	mu.Lock()
+	defer mu.Unlock()
+	if file == nil {
+		return nil
+	}
+	err := file.Close()
+	file = nil
+	return err
+}
+
+// SetOutput overrides the console writer (stderr by default).
+func SetOutput(w io.Writer) {
+	mu.Lock()
+	defer mu.Unlock()
+	writer = w
+}
+
+// Verbosef writes a message when --verbose or --debug is active.
+func Verbosef(format string, args ...interface{}) {
+	logf(LevelVerbose, "verbose", format, args...)
+}
+
+// Debugf writes a message when --debug is active.
+func Debugf(format string, args ...interface{}) {
+	logf(LevelDebug, "debug", format, args...)
+}
+
+func logf(l Level, tag, format string, args ...interface{}) {
+	mu.Lock()
+	defer mu.Unlock()
+	if level < l || l == LevelOff {
+		return
+	}
+	line := fmt.Sprintf("[%s] %s %s\n", tag, time.Now().Format("15:04:05.000"), fmt.Sprintf(format, args...))
+	w := writer
+	if w == nil {
+		w = os.Stderr
+	}
+	_, _ = io.WriteString(w, line)
+	if file != nil {
+		_, _ = io.WriteString(file, line)
+	}
+}
+
+// RedactHeaders renders headers as "k=v" pairs with credentials masked.
+func RedactHeaders(headers map[string]string) string {
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+redactValue(k, headers[k]))
+	}
+	return strings.Join(parts, " ")
+}
+
+func redactValue(key, value string) string {
+	if _, ok := sensitiveHeaders[strings.ToLower(key)]; !ok {
+		return value
+	}
+	if len(value) <= 8 {
+		return "***"
+	}
+	return value[:4] + "***" + value[len(value)-2:]
+}
@@ -0,0 +1,62 @@
+package log
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRedactHeaders_MasksCredentials(t *testing.T) {
+	got := RedactHeaders(map[string]string{
+		"Authorization": "Bearer abcdefghijklmnop",
+		"x-api-key":     "public-key",
+		"x-signature":   "0123456789abcdef",
+	})
+	if strings.Contains(got, "abcdefghijklmnop") || strings.Contains(got, "0123456789abcdef") {
+		t.Fatalf("credentials leaked: %s", got)
+	}
+	if !strings.Contains(got, "x-api-key=public-key") {
+		t.Fatalf("api key should stay visible: %s", got)
+	}
+}
+
+func TestLevels(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	defer SetOutput(nil)
+	defer SetLevel(LevelOff)
+
+	SetLevel(LevelVerbose)
+	Debugf("hidden")
+	Verbosef("shown")
+	if strings.Contains(buf.String(), "hidden") || !strings.Contains(buf.String(), "shown") {
+		t.Fatalf("unexpected output: %q", buf.String())
+	}
+}
+
+func TestTimingSummary(t *testing.T) {
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	defer SetOutput(nil)
+	defer SetLevel(LevelOff)
+	defer resetTimings()
+
+	Record(PhaseAPI, "POST /ignored", time.Second)
+	SetLevel(LevelVerbose)
+	resetTimings()
+	Record(PhaseAPI, "POST /Tool/Detail", 120*time.Millisecond)
+	Record(PhaseAPI, "POST /Task/Detail", 30*time.Millisecond)
+	Record(PhaseDownload, "task t1", 2*time.Second)
+	PrintTimingSummary()
+
+	out := buf.String()
+	for _, want := range []string{"timing summary", "api", "150ms  (2)", "120ms  POST /Tool/Detail", "download", "2s  (1)"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("summary missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "ignored") || strings.Contains(out, "auth") {
+		t.Fatalf("unexpected entries:\n%s", out)
+	}
+}
@@ -0,0 +1,114 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Phases reported in the --verbose timing summary.
+const (
+	PhaseAuth     = "auth"
+	PhaseAPI      = "api"
+	PhaseUpload   = "upload"
+	PhaseWatch    = "watch"
+	PhaseDownload = "download"
+)
+
+var phaseOrder = []string{PhaseAuth, PhaseAPI, PhaseUpload, PhaseWatch, PhaseDownload}
+
+// maxTimedCalls caps how many individual measurements the summary lists per phase.
+const maxTimedCalls = 10
+
+type timing struct {
+	label string
+	d     time.Duration
+}
+
+var (
+	timingMu sync.Mutex
+	timings  = map[string][]timing{}
+	started  = time.Now()
+)
+
+// Time starts timing label within phase and returns the func that stops it.
+// Nothing is recorded unless --verbose or --debug is active.
+func Time(phase, label string) func() {
+	if !Enabled(LevelVerbose) {
+		return func() {}
+	}
+	start := time.Now()
+	return func() { Record(phase, label, time.Since(start)) }
+}
+
+// Record adds a finished measurement to phase.
+func Record(phase, label string, d time.Duration) {
+	if !Enabled(LevelVerbose) {
+		return
+	}
+	timingMu.Lock()
+	defer timingMu.Unlock()
+	timings[phase] = append(timings[phase], timing{label: label, d: d})
+}
+
+// PrintTimingSummary writes where the command spent its time: a total per
+// phase followed by the slowest measurements. Phases can overlap (API calls
+// made while watching count under both).
+func PrintTimingSummary() {
+	if !Enabled(LevelVerbose) {
+		return
+	}
+	timingMu.Lock()
+	var b strings.Builder
+	fmt.Fprintf(&b, "[verbose] timing summary: total %s\n", round(time.Since(started)))
+	for _, phase := range phaseOrder {
+		list := timings[phase]
+		if len(list) == 0 {
+			continue
+		}
+		var total time.Duration
+		for _, t := range list {
+			total += t.d
+		}
+		fmt.Fprintf(&b, "  %-9s %9s  (%d)\n", phase, round(total), len(list))
+		sorted := append([]timing(nil), list...)
+		sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].d > sorted[j].d })
+		if len(sorted) > maxTimedCalls {
+			sorted = sorted[:maxTimedCalls]
+		}
+		for _, t := range sorted {
+			fmt.Fprintf(&b, "    %9s  %s\n", round(t.d), t.label)
+		}
+	}
+	timingMu.Unlock()
+
+	mu.Lock()
+	defer mu.Unlock()
+	w := writer
+	if w == nil {
+		w = os.Stderr
+	}
+	_, _ = io.WriteString(w, b.String())
+	if file != nil {
+		_, _ = io.WriteString(file, b.String())
+	}
+}
+
+// resetTimings clears recorded measurements (tests).
+func resetTimings() {
+	timingMu.Lock()
+	defer timingMu.Unlock()
+	timings = map[string][]timing{}
+	started = time.Now()
+}
+
+func round(d time.Duration) time.Duration {
+	if d >= time.Second {
+		return d.Round(10 * time.Millisecond)
+	}
+	return d.Round(time.Millisecond)
+}
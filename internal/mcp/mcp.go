@@ -0,0 +1,257 @@
+// Package mcp implements the tools part of a Model Context Protocol server
+// over stdio: newline-delimited JSON-RPC 2.0 on stdin and stdout.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ProtocolVersion is the newest protocol revision the server speaks. A client
+// asking for one of SupportedVersions gets that one instead.
+const ProtocolVersion = "2025-06-18"
+
+// SupportedVersions are the protocol revisions the server accepts.
+var SupportedVersions = []string{"2024-11-05", "2025-03-26", ProtocolVersion}
+
+// maxMessage bounds one JSON-RPC line.
+const maxMessage = 8 << 20
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+	codeInternalError  = -32603
+)
+
+// Tool is one entry of tools/list.
+type Tool struct {
+	Name        string                 `json:"name"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+}
+
+// Content is one block of a tool result.
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// CallResult is the result of tools/call. IsError reports a failure of the
+// tool itself, which the model should see, as opposed to a protocol error.
+type CallResult struct {
+	Content           []Content   `json:"content"`
+	StructuredContent interface{} `json:"structuredContent,omitempty"`
+	IsError           bool        `json:"isError,omitempty"`
+}
+
+// TextResult is a result with a single text block.
+func TextResult(text string, isError bool) CallResult {
+	return CallResult{Content: []Content{{Type: "text", Text: text}}, IsError: isError}
+}
+
+// Handler provides the tools.
+type Handler interface {
+	ListTools(ctx context.Context) ([]Tool, error)
+	// CallTool runs a tool. An error is reported to the client as a failed
+	// call; ctx ends when the client cancels the request.
+	CallTool(ctx context.Context, name string, args map[string]interface{}) (CallResult, error)
+}
+
+// Server answers MCP requests with a Handler.
+type Server struct {
+	Name    string
+	Version string
+	Handler Handler
+
+	writeMu sync.Mutex
+	w       io.Writer
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Serve reads requests from r until it ends or ctx is done, and writes
+// responses to w. Requests run concurrently, so a long tools/call does not
+// hold up pings or other calls.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.w = w
+	s.cancels = map[string]context.CancelFunc{}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		sc := bufio.NewScanner(r)
+		sc.Buffer(make([]byte, 64*1024), maxMessage)
+		for sc.Scan() {
+			line := append([]byte(nil), sc.Bytes()...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		readErr <- sc.Err()
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-readErr:
+			// Calls still running finish before Serve returns.
+			return err
+		case line := <-lines:
+			if len(line) == 0 {
+				continue
+			}
+			var req request
+			if err := json.Unmarshal(line, &req); err != nil {
+				s.reply(nil, nil, &rpcError{Code: codeParseError, Message: "parse error: " + err.Error()})
+				continue
+			}
+			if req.JSONRPC != "2.0" || req.Method == "" {
+				s.reply(req.ID, nil, &rpcError{Code: codeInvalidRequest, Message: "invalid request"})
+				continue
+			}
+			if len(req.ID) == 0 {
+				s.notification(req)
+				continue
+			}
+			callCtx, callCancel := context.WithCancel(ctx)
+			s.track(string(req.ID), callCancel)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer s.untrack(string(req.ID))
+				result, rerr := s.handle(callCtx, req)
+				s.reply(req.ID, result, rerr)
+			}()
+		}
+	}
+}
+
+func (s *Server) handle(ctx context.Context, req request) (interface{}, *rpcError) {
+	switch req.Method {
+	case "initialize":
+		var p struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(req.Params, &p)
+		version := ProtocolVersion
+		for _, v := range SupportedVersions {
+			if v == p.ProtocolVersion {
+				version = v
+			}
+		}
+		return map[string]interface{}{
+			"protocolVersion": version,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{"listChanged": false}},
+			"serverInfo":      map[string]string{"name": s.Name, "version": s.Version},
+		}, nil
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		tools, err := s.Handler.ListTools(ctx)
+		if err != nil {
+			return nil, &rpcError{Code: codeInternalError, Message: err.Error()}
+		}
+		if tools == nil {
+			tools = []Tool{}
+		}
+		return map[string]interface{}{"tools": tools}, nil
+	case "tools/call":
+		var p struct {
+			Name      string                 `json:"name"`
+			Arguments map[string]interface{} `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &p); err != nil || p.Name == "" {
+			return nil, &rpcError{Code: codeInvalidParams, Message: "tools/call needs a tool name"}
+		}
+		result, err := s.Handler.CallTool(ctx, p.Name, p.Arguments)
+		if err != nil {
+			return TextResult(err.Error(), true), nil
+		}
+		if result.Content == nil {
+			result.Content = []Content{}
+		}
+		return result, nil
+	default:
+		return nil, &rpcError{Code: codeMethodNotFound, Message: fmt.Sprintf("method %q not found", req.Method)}
+	}
+}
+
+// notification handles messages that expect no reply.
+func (s *Server) notification(req request) {
+	if req.Method != "notifications/cancelled" {
+		return
+	}
+	var p struct {
+		RequestID json.RawMessage `json:"requestId"`
+	}
+	if json.Unmarshal(req.Params, &p) == nil {
+		s.mu.Lock()
+		cancel := s.cancels[string(p.RequestID)]
+		s.mu.Unlock()
+		if cancel != nil {
+			cancel()
+		}
+	}
+}
+
+func (s *Server) track(id string, cancel context.CancelFunc) {
+	s.mu.Lock()
+	s.cancels[id] = cancel
+	s.mu.Unlock()
+}
+
+func (s *Server) untrack(id string) {
+	s.mu.Lock()
+	if cancel := s.cancels[id]; cancel != nil {
+		cancel()
+	}
+	delete(s.cancels, id)
+	s.mu.Unlock()
+}
+
+func (s *Server) reply(id json.RawMessage, result interface{}, rerr *rpcError) {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	data, err := json.Marshal(response{JSONRPC: "2.0", ID: id, Result: result, Error: rerr})
+	if err != nil {
+		data, _ = json.Marshal(response{JSONRPC: "2.0", ID: id, Error: &rpcError{Code: codeInternalError, Message: err.Error()}})
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_, _ = s.w.Write(append(data, '\n'))
+}
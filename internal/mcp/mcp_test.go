@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type fakeHandler struct{}
+
+func (fakeHandler) ListTools(context.Context) ([]Tool, error) {
+	return []Tool{{Name: "wiro__flux", InputSchema: map[string]interface{}{"type": "object"}}}, nil
+}
+
+func (fakeHandler) CallTool(_ context.Context, name string, args map[string]interface{}) (CallResult, error) {
+	if name != "wiro__flux" {
+		return CallResult{}, errors.New("unknown tool")
+	}
+	return TextResult("ran with "+args["prompt"].(string), false), nil
+}
+
+func TestServe(t *testing.T) {
+	in := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"wiro__flux","arguments":{"prompt":"a fox"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"nope"}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"resources/list"}`,
+		`not json`,
+	}, "\n") + "\n"
+	var out strings.Builder
+	srv := &Server{Name: "wiro", Version: "test", Handler: fakeHandler{}}
+	if err := srv.Serve(context.Background(), strings.NewReader(in), &out); err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+
+	replies := map[string]map[string]interface{}{}
+	sc := bufio.NewScanner(strings.NewReader(out.String()))
+	for sc.Scan() {
+		var r map[string]interface{}
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			t.Fatalf("reply %q: %v", sc.Text(), err)
+		}
+		id, _ := json.Marshal(r["id"])
+		replies[string(id)] = r
+	}
+	if len(replies) != 6 {
+		t.Fatalf("got %d replies, want 6 (no reply to the notification):\n%s", len(replies), out.String())
+	}
+	if v := replies["1"]["result"].(map[string]interface{})["protocolVersion"]; v != "2024-11-05" {
+		t.Fatalf("negotiated version %v", v)
+	}
+	if tools := replies["2"]["result"].(map[string]interface{})["tools"].([]interface{}); len(tools) != 1 {
+		t.Fatalf("tools/list = %v", tools)
+	}
+	call := replies["3"]["result"].(map[string]interface{})
+	if text := call["content"].([]interface{})[0].(map[string]interface{})["text"]; text != "ran with a fox" || call["isError"] != nil {
+		t.Fatalf("tools/call = %v", call)
+	}
+	if failed := replies["4"]["result"].(map[string]interface{}); failed["isError"] != true {
+		t.Fatalf("a failing tool should return isError, got %v", failed)
+	}
+	if code := replies["5"]["error"].(map[string]interface{})["code"]; code != float64(codeMethodNotFound) {
+		t.Fatalf("unknown method code %v", code)
+	}
+	if code := replies["null"]["error"].(map[string]interface{})["code"]; code != float64(codeParseError) {
+		t.Fatalf("parse error code %v", code)
+	}
+}
@@ -0,0 +1,187 @@
+package mediaprep
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+)
+
+// JPEG markers this file cares about.
+const (
+	markerSOS  = 0xDA
+	markerAPP1 = 0xE1 // EXIF and XMP
+	markerAPPD = 0xED // Photoshop IRB with IPTC
+	markerCOM  = 0xFE
+)
+
+// jpegSegment is one marker segment; data includes the marker and length.
+type jpegSegment struct {
+	marker byte
+	data   []byte
+}
+
+// jpegSegments splits a JPEG into its header segments and the rest, from the
+// start-of-scan segment on. ok is false when data is not a well-formed JPEG.
+func jpegSegments(data []byte) (segs []jpegSegment, rest []byte, ok bool) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, nil, false
+	}
+	i := 2
+	for i+4 <= len(data) {
+		if data[i] != 0xFF {
+			return nil, nil, false
+		}
+		marker := data[i+1]
+		if marker == 0xFF {
+			// Fill byte before a marker.
+			i++
+			continue
+		}
+		if marker == markerSOS {
+			return segs, data[i:], true
+		}
+		n := int(binary.BigEndian.Uint16(data[i+2:]))
+		if n < 2 || i+2+n > len(data) {
+			return nil, nil, false
+		}
+		segs = append(segs, jpegSegment{marker: marker, data: data[i : i+2+n]})
+		i += 2 + n
+	}
+	return nil, nil, false
+}
+
+// exifOrientation reads the EXIF orientation tag of a JPEG, 1 when absent.
+func exifOrientation(data []byte) int {
+	segs, _, ok := jpegSegments(data)
+	if !ok {
+		return 1
+	}
+	for _, s := range segs {
+		payload := s.data[4:]
+		if s.marker != markerAPP1 || !bytes.HasPrefix(payload, []byte("Exif\x00\x00")) {
+			continue
+		}
+		tiff := payload[6:]
+		if len(tiff) < 8 {
+			return 1
+		}
+		var order binary.ByteOrder
+		switch string(tiff[:2]) {
+		case "II":
+			order = binary.LittleEndian
+		case "MM":
+			order = binary.BigEndian
+		default:
+			return 1
+		}
+		ifd := int(order.Uint32(tiff[4:]))
+		if ifd < 0 || ifd+2 > len(tiff) {
+			return 1
+		}
+		count := int(order.Uint16(tiff[ifd:]))
+		for e := 0; e < count; e++ {
+			off := ifd + 2 + e*12
+			if off+12 > len(tiff) {
+				return 1
+			}
+			if order.Uint16(tiff[off:]) == 0x0112 {
+				if v := int(order.Uint16(tiff[off+8:])); v >= 1 && v <= 8 {
+					return v
+				}
+				return 1
+			}
+		}
+		return 1
+	}
+	return 1
+}
+
+// stripMetadata removes EXIF, XMP, IPTC, and comments from a JPEG, or EXIF,
+// text, and timestamp chunks from a PNG, without re-encoding the pixels.
+// Color profiles are kept. removed is false when there was nothing to strip.
+func stripMetadata(data []byte, format string) (out []byte, removed bool) {
+	switch format {
+	case "jpeg":
+		segs, rest, ok := jpegSegments(data)
+		if !ok {
+			return data, false
+		}
+		var buf bytes.Buffer
+		buf.Write(data[:2])
+		for _, s := range segs {
+			if s.marker == markerAPP1 || s.marker == markerAPPD || s.marker == markerCOM {
+				removed = true
+				continue
+			}
+			buf.Write(s.data)
+		}
+		buf.Write(rest)
+		return buf.Bytes(), removed
+	case "png":
+		const sigLen = 8
+		if len(data) < sigLen {
+			return data, false
+		}
+		var buf bytes.Buffer
+		buf.Write(data[:sigLen])
+		for i := sigLen; i < len(data); {
+			if i+8 > len(data) {
+				return data, false
+			}
+			n := int(binary.BigEndian.Uint32(data[i:]))
+			end := i + 12 + n
+			if n < 0 || end > len(data) {
+				return data, false
+			}
+			switch string(data[i+4 : i+8]) {
+			case "eXIf", "tEXt", "zTXt", "iTXt", "tIME":
+				removed = true
+			default:
+				buf.Write(data[i:end])
+			}
+			i = end
+		}
+		return buf.Bytes(), removed
+	}
+	return data, false
+}
+
+// orient turns img upright according to an EXIF orientation value.
+func orient(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+	b := img.Bounds()
+	src := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	out := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			copy(out.Pix[y*out.Stride+x*4:y*out.Stride+x*4+4], src.Pix[sy*src.Stride+sx*4:])
+		}
+	}
+	return out
+}
@@ -0,0 +1,127 @@
+package mediaprep
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"strings"
+)
+
+// jpegQuality is used whenever an image is written as JPEG.
+const jpegQuality = 90
+
+func prepareImage(path, dir, kind string, o Options) (Result, error) {
+	unchanged := Result{Path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return unchanged, err
+	}
+	src := strings.TrimPrefix(kind, "image/")
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return unchanged, fmt.Errorf("read %s: %w", path, err)
+	}
+	orientation := 1
+	if src == "jpeg" {
+		orientation = exifOrientation(data)
+	}
+	dst := src
+	if o.Format != "" {
+		dst = o.Format
+	}
+	resize := o.MaxSide > 0 && max(cfg.Width, cfg.Height) > o.MaxSide
+	reencode := resize || dst != src || (o.StripEXIF && orientation != 1)
+
+	if !reencode {
+		if !o.StripEXIF {
+			return unchanged, nil
+		}
+		stripped, removed := stripMetadata(data, src)
+		if !removed {
+			return unchanged, nil
+		}
+		out := outputPath(path, dir, "")
+		if err := os.WriteFile(out, stripped, 0o600); err != nil {
+			return unchanged, err
+		}
+		return Result{Path: out, Changed: true, Note: "removed metadata"}, nil
+	}
+
+	var img image.Image
+	if src == "gif" {
+		anim, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return unchanged, fmt.Errorf("decode %s: %w", path, err)
+		}
+		if len(anim.Image) > 1 {
+			unchanged.Note = "animated GIF left unchanged"
+			return unchanged, nil
+		}
+		img = anim.Image[0]
+	} else if img, _, err = image.Decode(bytes.NewReader(data)); err != nil {
+		return unchanged, fmt.Errorf("decode %s: %w", path, err)
+	}
+	img = orient(img, orientation)
+
+	var notes []string
+	if resize {
+		b := img.Bounds()
+		w, h := fitWithin(b.Dx(), b.Dy(), o.MaxSide)
+		img = resample(img, w, h)
+		notes = append(notes, fmt.Sprintf("resized %dx%d to %dx%d", b.Dx(), b.Dy(), w, h))
+	}
+	if dst != src {
+		notes = append(notes, "converted to "+dst)
+	}
+	if o.StripEXIF {
+		notes = append(notes, "removed metadata")
+	}
+
+	var buf bytes.Buffer
+	switch dst {
+	case "jpeg":
+		err = jpeg.Encode(&buf, flatten(img), &jpeg.Options{Quality: jpegQuality})
+	case "png":
+		err = png.Encode(&buf, img)
+	case "gif":
+		err = gif.Encode(&buf, img, &gif.Options{NumColors: 256})
+	}
+	if err != nil {
+		return unchanged, fmt.Errorf("encode %s: %w", path, err)
+	}
+	ext := ""
+	if dst != src {
+		ext = map[string]string{"jpeg": ".jpg", "png": ".png"}[dst]
+	}
+	out := outputPath(path, dir, ext)
+	if err := os.WriteFile(out, buf.Bytes(), 0o600); err != nil {
+		return unchanged, err
+	}
+	return Result{Path: out, Changed: true, Note: strings.Join(notes, ", ")}, nil
+}
+
+// fitWithin scales w x h down to fit maxSide, keeping the aspect ratio.
+func fitWithin(w, h, maxSide int) (int, int) {
+	if w >= h {
+		return maxSide, max(1, (h*maxSide+w/2)/w)
+	}
+	return max(1, (w*maxSide+h/2)/h), maxSide
+}
+
+// flatten composites transparent pixels over white, since JPEG has no alpha.
+func flatten(img image.Image) image.Image {
+	if opaque, ok := img.(interface{ Opaque() bool }); ok && opaque.Opaque() {
+		return img
+	}
+	b := img.Bounds()
+	out := image.NewRGBA(b)
+	draw.Draw(out, b, image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(out, b, img, b.Min, draw.Over)
+	return out
+}
@@ -0,0 +1,116 @@
+// Package mediaprep shrinks and cleans local media files before they are
+// uploaded as run inputs. Images are handled in pure Go (JPEG, PNG, and
+// single-frame GIF); videos are passed to ffmpeg when it is installed.
+// Anything else is uploaded unchanged.
+package mediaprep
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Options selects the preprocessing applied to each local file input.
+type Options struct {
+	// MaxSide scales images and videos down so neither side exceeds it; 0 keeps the size.
+	MaxSide int
+	// Format re-encodes images as "jpeg" or "png"; empty keeps the format.
+	Format string
+	// StripEXIF removes EXIF, XMP, and text metadata. Pixel orientation from
+	// EXIF is applied first, so images keep their upright appearance.
+	StripEXIF bool
+}
+
+// Enabled reports whether any preprocessing is requested.
+func (o Options) Enabled() bool {
+	return o.MaxSide > 0 || o.Format != "" || o.StripEXIF
+}
+
+// Normalize validates o and canonicalizes Format ("jpg" becomes "jpeg").
+func (o Options) Normalize() (Options, error) {
+	if o.MaxSide < 0 {
+		return o, errors.New("--max-side must not be negative")
+	}
+	switch f := strings.ToLower(strings.TrimSpace(o.Format)); f {
+	case "":
+		o.Format = ""
+	case "jpeg", "jpg":
+		o.Format = "jpeg"
+	case "png":
+		o.Format = "png"
+	default:
+		return o, fmt.Errorf("unsupported --format %q (want jpeg or png)", o.Format)
+	}
+	return o, nil
+}
+
+// Result is the file to upload in place of an input.
+type Result struct {
+	// Path is the processed copy, or the original when nothing changed.
+	Path string
+	// Changed reports whether Path is a new file.
+	Changed bool
+	// Note says what was done, or why the file was left alone.
+	Note string
+}
+
+// Prepare applies o to the file at path, writing any processed copy into dir
+// under the same base name (with the extension of a new format).
+func Prepare(ctx context.Context, path, dir string, o Options) (Result, error) {
+	unchanged := Result{Path: path}
+	if !o.Enabled() {
+		return unchanged, nil
+	}
+	switch kind, err := sniff(path); {
+	case err != nil:
+		return unchanged, err
+	case kind == "image/jpeg" || kind == "image/png" || kind == "image/gif":
+		return prepareImage(path, dir, kind, o)
+	case strings.HasPrefix(kind, "image/"):
+		unchanged.Note = fmt.Sprintf("%s is not supported for preprocessing; uploaded as is", kind)
+		return unchanged, nil
+	case strings.HasPrefix(kind, "video/"):
+		return prepareVideo(ctx, path, dir, o)
+	default:
+		return unchanged, nil
+	}
+}
+
+// sniff returns the media type of the file from its content, falling back
+// to its extension for containers Go does not recognize.
+func sniff(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	kind := http.DetectContentType(head[:n])
+	if kind == "application/octet-stream" || strings.HasPrefix(kind, "text/") {
+		if byExt := mime.TypeByExtension(strings.ToLower(filepath.Ext(path))); byExt != "" {
+			kind = byExt
+		}
+	}
+	kind, _, _ = strings.Cut(kind, ";")
+	return strings.TrimSpace(kind), nil
+}
+
+// outputPath is the processed copy's path in dir, with ext replacing the
+// original extension when it is not empty.
+func outputPath(path, dir, ext string) string {
+	base := filepath.Base(path)
+	if ext != "" {
+		base = strings.TrimSuffix(base, filepath.Ext(base)) + ext
+	}
+	return filepath.Join(dir, base)
+}
@@ -0,0 +1,145 @@
+package mediaprep
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writePNG(t *testing.T, path string, w, h int) {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// jpegWithOrientation encodes a w x h JPEG carrying an EXIF orientation tag.
+func jpegWithOrientation(t *testing.T, w, h int, orientation uint16) []byte {
+	t.Helper()
+	var enc bytes.Buffer
+	if err := jpeg.Encode(&enc, image.NewGray(image.Rect(0, 0, w, h)), nil); err != nil {
+		t.Fatal(err)
+	}
+	tiff := []byte("II*\x00\x08\x00\x00\x00\x01\x00")
+	entry := make([]byte, 12)
+	binary.LittleEndian.PutUint16(entry, 0x0112)
+	binary.LittleEndian.PutUint16(entry[2:], 3)
+	binary.LittleEndian.PutUint32(entry[4:], 1)
+	binary.LittleEndian.PutUint16(entry[8:], orientation)
+	payload := append(append([]byte("Exif\x00\x00"), tiff...), append(entry, 0, 0, 0, 0)...)
+	app1 := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(app1[2:], uint16(len(payload)+2))
+	data := enc.Bytes()
+	return append(append(append([]byte{}, data[:2]...), append(app1, payload...)...), data[2:]...)
+}
+
+func TestPrepareResizesAndConverts(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "in.png")
+	writePNG(t, in, 400, 100)
+
+	out := t.TempDir()
+	res, err := Prepare(context.Background(), in, out, Options{MaxSide: 200, Format: "jpeg"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Changed || res.Path != filepath.Join(out, "in.jpg") {
+		t.Fatalf("result = %+v", res)
+	}
+	f, err := os.Open(res.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	cfg, format, err := image.DecodeConfig(f)
+	if err != nil || format != "jpeg" || cfg.Width != 200 || cfg.Height != 50 {
+		t.Fatalf("output = %s %dx%d, %v", format, cfg.Width, cfg.Height, err)
+	}
+
+	// Already small enough and in the right format: uploaded as is.
+	res, err = Prepare(context.Background(), in, t.TempDir(), Options{MaxSide: 1000})
+	if err != nil || res.Changed || res.Path != in {
+		t.Fatalf("small image = %+v, %v", res, err)
+	}
+}
+
+func TestPrepareStripsEXIF(t *testing.T) {
+	dir := t.TempDir()
+	upright := filepath.Join(dir, "upright.jpg")
+	if err := os.WriteFile(upright, jpegWithOrientation(t, 16, 8, 1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	res, err := Prepare(context.Background(), upright, t.TempDir(), Options{StripEXIF: true})
+	if err != nil || !res.Changed {
+		t.Fatalf("strip = %+v, %v", res, err)
+	}
+	data, _ := os.ReadFile(res.Path)
+	if bytes.Contains(data, []byte("Exif\x00\x00")) {
+		t.Fatal("EXIF segment still present")
+	}
+	if _, err := jpeg.Decode(bytes.NewReader(data)); err != nil {
+		t.Fatalf("stripped JPEG does not decode: %v", err)
+	}
+
+	// A rotated image is re-encoded upright, since dropping the tag would turn it.
+	rotated := filepath.Join(dir, "rotated.jpg")
+	if err := os.WriteFile(rotated, jpegWithOrientation(t, 16, 8, 6), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	res, err = Prepare(context.Background(), rotated, t.TempDir(), Options{StripEXIF: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, _ := os.Open(res.Path)
+	defer f.Close()
+	if cfg, _, err := image.DecodeConfig(f); err != nil || cfg.Width != 8 || cfg.Height != 16 {
+		t.Fatalf("rotated output = %dx%d, %v", cfg.Width, cfg.Height, err)
+	}
+}
+
+func TestPrepareVideoWithoutFFmpeg(t *testing.T) {
+	saved := lookFFmpeg
+	lookFFmpeg = func() (string, error) { return "", errors.New("not found") }
+	defer func() { lookFFmpeg = saved }()
+
+	in := filepath.Join(t.TempDir(), "clip.mp4")
+	if err := os.WriteFile(in, []byte("\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	res, err := Prepare(context.Background(), in, t.TempDir(), Options{MaxSide: 720})
+	if err != nil || res.Changed || !strings.Contains(res.Note, "ffmpeg") {
+		t.Fatalf("video = %+v, %v", res, err)
+	}
+	args := strings.Join(ffmpegArgs("in.mp4", "out.mp4", Options{MaxSide: 720, StripEXIF: true}), " ")
+	if !strings.Contains(args, "min(iw,720)") || !strings.Contains(args, "-map_metadata -1") {
+		t.Fatalf("ffmpeg args = %s", args)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	if o, err := (Options{Format: "JPG"}).Normalize(); err != nil || o.Format != "jpeg" {
+		t.Fatalf("Normalize(JPG) = %+v, %v", o, err)
+	}
+	if _, err := (Options{Format: "webp"}).Normalize(); err == nil {
+		t.Fatal("webp accepted")
+	}
+}
@@ -0,0 +1,106 @@
+package mediaprep
+
+import (
+	"image"
+	"image/draw"
+	"math"
+)
+
+// resample scales img to w x h with a triangle filter widened by the scale
+// factor, so downscaling averages every source pixel instead of skipping
+// some. Channels are filtered premultiplied, so transparent pixels do not
+// bleed their color into edges.
+func resample(img image.Image, w, h int) *image.RGBA {
+	b := img.Bounds()
+	src := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(src, src.Bounds(), img, b.Min, draw.Src)
+
+	// Horizontal pass into a float buffer of h_src rows by w columns, then vertical.
+	sw, sh := b.Dx(), b.Dy()
+	xw := filterWeights(sw, w)
+	tmp := make([]float32, w*sh*4)
+	for y := 0; y < sh; y++ {
+		row := src.Pix[y*src.Stride:]
+		for x, ws := range xw {
+			var r, g, bl, a float32
+			for _, c := range ws {
+				p := row[c.index*4:]
+				r += float32(p[0]) * c.weight
+				g += float32(p[1]) * c.weight
+				bl += float32(p[2]) * c.weight
+				a += float32(p[3]) * c.weight
+			}
+			t := tmp[(y*w+x)*4:]
+			t[0], t[1], t[2], t[3] = r, g, bl, a
+		}
+	}
+
+	yw := filterWeights(sh, h)
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y, ws := range yw {
+		for x := 0; x < w; x++ {
+			var px [4]float32
+			for _, c := range ws {
+				t := tmp[(c.index*w+x)*4:]
+				for i := range px {
+					px[i] += t[i] * c.weight
+				}
+			}
+			o := out.Pix[y*out.Stride+x*4:]
+			alpha := clamp8(px[3])
+			for i := 0; i < 3; i++ {
+				// Rounding can leave a premultiplied channel above alpha.
+				o[i] = min(clamp8(px[i]), alpha)
+			}
+			o[3] = alpha
+		}
+	}
+	return out
+}
+
+type contribution struct {
+	index  int
+	weight float32
+}
+
+// filterWeights lists, for each of the dst positions, the src positions that
+// contribute to it and their normalized weights.
+func filterWeights(src, dst int) [][]contribution {
+	scale := float64(src) / float64(dst)
+	support := math.Max(1, scale)
+	out := make([][]contribution, dst)
+	for i := range out {
+		center := (float64(i)+0.5)*scale - 0.5
+		lo := int(math.Floor(center - support))
+		hi := int(math.Ceil(center + support))
+		var sum float64
+		var cs []contribution
+		for j := max(lo, 0); j <= min(hi, src-1); j++ {
+			wt := 1 - math.Abs(float64(j)-center)/support
+			if wt <= 0 {
+				continue
+			}
+			cs = append(cs, contribution{index: j, weight: float32(wt)})
+			sum += wt
+		}
+		if len(cs) == 0 {
+			nearest := min(max(int(math.Round(center)), 0), src-1)
+			cs, sum = []contribution{{index: nearest, weight: 1}}, 1
+		}
+		for k := range cs {
+			cs[k].weight /= float32(sum)
+		}
+		out[i] = cs
+	}
+	return out
+}
+
+func clamp8(v float32) uint8 {
+	switch {
+	case v <= 0:
+		return 0
+	case v >= 255:
+		return 255
+	}
+	return uint8(v + 0.5)
+}
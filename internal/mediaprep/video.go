@@ -0,0 +1,62 @@
+package mediaprep
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// lookFFmpeg finds ffmpeg on PATH; tests replace it.
+var lookFFmpeg = func() (string, error) { return exec.LookPath("ffmpeg") }
+
+// prepareVideo re-encodes a video with ffmpeg to fit MaxSide, or copies the
+// streams without metadata for StripEXIF alone. Format only applies to images.
+func prepareVideo(ctx context.Context, path, dir string, o Options) (Result, error) {
+	unchanged := Result{Path: path}
+	if o.MaxSide == 0 && !o.StripEXIF {
+		return unchanged, nil
+	}
+	ffmpeg, err := lookFFmpeg()
+	if err != nil {
+		unchanged.Note = "ffmpeg not found; video uploaded as is"
+		return unchanged, nil
+	}
+	out := outputPath(path, dir, "")
+	args := ffmpegArgs(path, out, o)
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, ffmpeg, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return unchanged, fmt.Errorf("ffmpeg %s: %s", path, msg)
+	}
+	var notes []string
+	if o.MaxSide > 0 {
+		notes = append(notes, fmt.Sprintf("scaled to fit %dpx", o.MaxSide))
+	}
+	if o.StripEXIF {
+		notes = append(notes, "removed metadata")
+	}
+	return Result{Path: out, Changed: true, Note: strings.Join(notes, ", ")}, nil
+}
+
+// ffmpegArgs builds the command line for prepareVideo. Scaling only ever
+// shrinks, keeps the aspect ratio, and rounds to even sizes for H.264.
+func ffmpegArgs(in, out string, o Options) []string {
+	args := []string{"-hide_banner", "-loglevel", "error", "-nostdin", "-y", "-i", in}
+	if o.StripEXIF {
+		args = append(args, "-map_metadata", "-1")
+	}
+	if o.MaxSide > 0 {
+		scale := fmt.Sprintf("scale='min(iw,%[1]d)':'min(ih,%[1]d)':force_original_aspect_ratio=decrease,scale=trunc(iw/2)*2:trunc(ih/2)*2", o.MaxSide)
+		args = append(args, "-vf", scale, "-c:v", "libx264", "-crf", "20", "-preset", "medium", "-pix_fmt", "yuv420p", "-c:a", "copy")
+	} else {
+		args = append(args, "-c", "copy")
+	}
+	return append(args, out)
+}
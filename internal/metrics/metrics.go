@@ -0,0 +1,191 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Counter is a monotonically increasing value with at most one label.
+type Counter struct {
+	name   string
+	help   string
+	label  string
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []*Counter
+)
+
+// Process-wide counters exported on /metrics.
+var (
+	TasksSubmitted    = NewCounter("wiro_tasks_submitted_total", "Tasks submitted by this process.", "")
+	TasksCompleted    = NewCounter("wiro_tasks_completed_total", "Watched tasks that reached a final status.", "status")
+	TaskFailures      = NewCounter("wiro_task_failures_total", "Failed submissions, watches, and tasks.", "reason")
+	WatchReconnects   = NewCounter("wiro_watch_reconnects_total", "WebSocket reconnect attempts while watching tasks.", "")
+	DownloadBytes     = NewCounter("wiro_download_bytes_total", "Bytes of task outputs downloaded.", "")
+	AccountTaskEvents = NewCounter("wiro_account_task_events_total", "Task lifecycle events observed by account watch.", "kind")
+	APIRequests       = NewCounter("wiro_api_requests_total", "API requests sent, by response status or \"error\".", "status")
+	WebSocketSessions = NewCounter("wiro_websocket_sessions_total", "WebSocket sessions opened while watching tasks, by result.", "result")
+	Uploads           = NewCounter("wiro_uploads_total", "Chunked input file uploads, by result.", "result")
+	UploadBytes       = NewCounter("wiro_upload_bytes_total", "Bytes of input files sent in upload chunks.", "")
+	Downloads         = NewCounter("wiro_downloads_total", "Task output file downloads, by result.", "result")
+)
+
+// NewCounter registers a counter. label may be empty for an unlabeled counter.
+func NewCounter(name, help, label string) *Counter {
+	c := &Counter{name: name, help: help, label: label, values: map[string]float64{}}
+	registryMu.Lock()
+	registry = append(registry, c)
+	registryMu.Unlock()
+	return c
+}
+
+// Inc adds one. labelValue is used only when the counter has a label.
+func (c *Counter) Inc(labelValue ...string) {
+	c.Add(1, labelValue...)
+}
+
+// Add adds n (which must not be negative).
+func (c *Counter) Add(n float64, labelValue ...string) {
+	if n < 0 {
+		return
+	}
+	key := ""
+	if c.label != "" && len(labelValue) > 0 {
+		key = labelValue[0]
+	}
+	c.mu.Lock()
+	c.values[key] += n
+	c.mu.Unlock()
+}
+
+// Value returns the current value for labelValue.
+func (c *Counter) Value(labelValue ...string) float64 {
+	key := ""
+	if c.label != "" && len(labelValue) > 0 {
+		key = labelValue[0]
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[key]
+}
+
+func (c *Counter) write(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.label == "" || len(c.values) == 0 {
+		if c.label == "" {
+			fmt.Fprintf(w, "%s %s\n", c.name, formatValue(c.values[""]))
+		}
+		return
+	}
+	keys := make([]string, 0, len(c.values))
+	for k := range c.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s{%s=\"%s\"} %s\n", c.name, c.label, escapeLabel(k), formatValue(c.values[k]))
+	}
+}
+
+// Sample is one value of a counter, for exporters other than /metrics.
+type Sample struct {
+	Name  string
+	Help  string
+	Label string
+	// LabelValue is empty for unlabeled counters.
+	LabelValue string
+	Value      float64
+}
+
+// Snapshot returns the current value of every registered counter, one sample
+// per label value, in registration order.
+func Snapshot() []Sample {
+	registryMu.Lock()
+	counters := append([]*Counter(nil), registry...)
+	registryMu.Unlock()
+	var out []Sample
+	for _, c := range counters {
+		c.mu.Lock()
+		keys := make([]string, 0, len(c.values))
+		for k := range c.values {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			out = append(out, Sample{Name: c.name, Help: c.help, Label: c.label, LabelValue: k, Value: c.values[k]})
+		}
+		c.mu.Unlock()
+	}
+	return out
+}
+
+// WriteText writes every registered counter in the Prometheus text format.
+func WriteText(w io.Writer) {
+	registryMu.Lock()
+	counters := append([]*Counter(nil), registry...)
+	registryMu.Unlock()
+	for _, c := range counters {
+		c.write(w)
+	}
+}
+
+// Handler serves /metrics.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		WriteText(w)
+	})
+}
+
+// Serve exposes /metrics on addr until ctx ends. It returns the bound address
+// once listening, so callers can report it (useful with ":0").
+func Serve(ctx context.Context, addr string) (string, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("listen for metrics on %s: %w", addr, err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			// output imports this package, so the warning is written directly
+			// in the same form as output.Warnf.
+			fmt.Fprintf(os.Stderr, "warning: metrics server stopped: %v\n", err)
+		}
+	}()
+	return ln.Addr().String(), nil
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel applies the exposition format's label value escaping.
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestServe_ExposesCounters(t *testing.T) {
+	c := NewCounter("wiro_test_events_total", "Test events.", "kind")
+	c.Inc("new")
+	c.Add(2, "status")
+	TasksSubmitted.Inc()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	addr, err := Serve(ctx, "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+	resp, err := http.Get("http://" + addr + "/metrics")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	text := string(body)
+	for _, want := range []string{
+		"# TYPE wiro_test_events_total counter",
+		`wiro_test_events_total{kind="new"} 1`,
+		`wiro_test_events_total{kind="status"} 2`,
+		"wiro_tasks_submitted_total 1",
+		"wiro_download_bytes_total 0",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("missing %q in:\n%s", want, text)
+		}
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	c := NewCounter("wiro_test_snapshot_total", "Snapshot test.", "result")
+	c.Inc("ok")
+	c.Add(3, "error")
+	var got []Sample
+	for _, s := range Snapshot() {
+		if s.Name == "wiro_test_snapshot_total" {
+			got = append(got, s)
+		}
+	}
+	if len(got) != 2 || got[0].LabelValue != "error" || got[0].Value != 3 || got[1].LabelValue != "ok" || got[1].Label != "result" {
+		t.Fatalf("snapshot = %+v", got)
+	}
+}
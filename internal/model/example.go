@@ -0,0 +1,252 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/jsonout"
+)
+
+// Example value sources, in the order they are tried.
+const (
+	SourceSample      = "sample"
+	SourceDefault     = "default"
+	SourceOption      = "option"
+	SourcePlaceholder = "placeholder"
+)
+
+// ExampleValue is one field of a ready-to-run example.
+type ExampleValue struct {
+	Field string `json:"field"`
+	Value string `json:"value"`
+	// File and URL mark file inputs, passed with --set-file or --set-url.
+	File bool `json:"file,omitempty"`
+	URL  bool `json:"url,omitempty"`
+	// Source is where the value came from; a placeholder must be replaced
+	// before the example runs.
+	Source string `json:"source"`
+}
+
+// Example fills the model's quick fields, and any required advanced field,
+// with a value from its first Inspire sample, then its default, then its first
+// option. Required fields with none of those get a <label> placeholder;
+// optional ones, and fields a showif rule hides, are left out.
+func Example(detail *api.ToolDetail) []ExampleValue {
+	sample := inspireSample(detail)
+	items := FlattenItems(detail, true)
+	var out []ExampleValue
+	chosen := map[string][]api.MultipartValue{}
+	for _, item := range items {
+		required := item.Required || strings.EqualFold(strings.TrimSpace(item.ID), "prompt")
+		if item.Advanced && !required || !Visible(item, items, chosen) {
+			continue
+		}
+		file := isFileItem(item)
+		add := func(value, source string) {
+			chosen[item.ID] = append(chosen[item.ID], api.MultipartValue{Value: value})
+			v := ExampleValue{Field: item.ID, Value: value, Source: source}
+			if file {
+				v.URL = isURL(value)
+				v.File = !v.URL
+			}
+			out = append(out, v)
+		}
+		if values := sampleValues(sample[item.ID]); len(values) > 0 {
+			for _, v := range values {
+				add(v, SourceSample)
+			}
+			continue
+		}
+		if def := strings.TrimSpace(exampleString(item.DefaultValue)); def != "" {
+			add(def, SourceDefault)
+			continue
+		}
+		if len(item.Options) > 0 && !file {
+			add(exampleString(item.Options[0].Value), SourceOption)
+			continue
+		}
+		if required {
+			label := strings.TrimSpace(item.Label)
+			if label == "" {
+				label = item.ID
+			}
+			add("<"+label+">", SourcePlaceholder)
+		}
+	}
+	return out
+}
+
+// InputSchema describes the model's inputs as a JSON Schema object keyed by
+// field id, with bounds, options, defaults, and Inspire samples as examples.
+func InputSchema(name string, detail *api.ToolDetail) jsonout.Schema {
+	sample := inspireSample(detail)
+	props := jsonout.Schema{}
+	var required []string
+	for _, item := range FlattenItems(detail, true) {
+		s := fieldSchema(item)
+		if label := strings.TrimSpace(item.Label); label != "" {
+			s["title"] = label
+		}
+		desc := strings.TrimSpace(item.Note)
+		if desc == "" {
+			desc = strings.TrimSpace(item.Placeholder)
+		}
+		if desc != "" {
+			s["description"] = desc
+		}
+		if def := exampleString(item.DefaultValue); strings.TrimSpace(def) != "" {
+			s["default"] = typedValue(s, def)
+		}
+		if values := sampleValues(sample[item.ID]); len(values) > 0 {
+			s["examples"] = []interface{}{typedValue(s, values[0])}
+		}
+		if item.Advanced {
+			s["x-advanced"] = true
+		}
+		props[item.ID] = s
+		if item.Required || strings.EqualFold(strings.TrimSpace(item.ID), "prompt") {
+			required = append(required, item.ID)
+		}
+	}
+	s := jsonout.Schema{
+		"$schema":    jsonout.DraftURI,
+		"title":      name + " inputs",
+		"type":       "object",
+		"properties": props,
+	}
+	if d := strings.TrimSpace(detail.Description); d != "" {
+		s["description"] = d
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+func fieldSchema(item api.ToolParameterItem) jsonout.Schema {
+	s := jsonout.Schema{}
+	bounds := func() {
+		lo, hasLo := parseBound(item.MinValue)
+		if hasLo {
+			s["minimum"] = lo
+		}
+		if hi, ok := parseBound(item.MaxValue); ok {
+			s["maximum"] = hi
+		}
+		// Steps count from the minimum; multipleOf counts from zero, so it
+		// only says the same when the minimum is itself a multiple.
+		if step, ok := parseBound(item.IncrementBy); ok && step > 0 && math.Abs(math.Remainder(lo, step)) < 1e-9 {
+			s["multipleOf"] = step
+		}
+	}
+	switch strings.ToLower(strings.TrimSpace(item.Type)) {
+	case "number":
+		s["type"] = "integer"
+		bounds()
+	case "float":
+		s["type"] = "number"
+		bounds()
+	case "checkbox":
+		s["type"] = "boolean"
+	case "select", "selectwithcover":
+		s["type"] = "string"
+		if len(item.Options) > 0 {
+			enum := make([]string, 0, len(item.Options))
+			for _, opt := range item.Options {
+				enum = append(enum, exampleString(opt.Value))
+			}
+			s["enum"] = enum
+		}
+	case "combinefileinput":
+		s["type"] = "string"
+		s["format"] = "uri-reference"
+		s["x-file"] = true
+	default:
+		s["type"] = "string"
+		if item.MaxInputLenght > 0 {
+			s["maxLength"] = item.MaxInputLenght
+		}
+	}
+	return s
+}
+
+// typedValue converts a CLI string value to the JSON type of s.
+func typedValue(s jsonout.Schema, v string) interface{} {
+	switch s["type"] {
+	case "integer", "number":
+		if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil {
+			return b
+		}
+	}
+	return v
+}
+
+// inspireSample is the first Inspire sample's field values. A sample either
+// holds them at its top level or under "parameters" or "inputs".
+func inspireSample(detail *api.ToolDetail) map[string]interface{} {
+	if len(detail.Inspire) == 0 {
+		return nil
+	}
+	sample := detail.Inspire[0]
+	out := map[string]interface{}{}
+	for k, v := range sample {
+		out[k] = v
+	}
+	for _, key := range []string{"inputs", "parameters"} {
+		if nested, ok := sample[key].(map[string]interface{}); ok {
+			for k, v := range nested {
+				out[k] = v
+			}
+		}
+	}
+	return out
+}
+
+// sampleValues flattens a sample value to strings; lists give one value per
+// element, like a repeated --set.
+func sampleValues(v interface{}) []string {
+	switch t := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		var out []string
+		for _, e := range t {
+			out = append(out, sampleValues(e)...)
+		}
+		return out
+	case map[string]interface{}:
+		return nil
+	}
+	if s := strings.TrimSpace(exampleString(v)); s != "" {
+		return []string{s}
+	}
+	return nil
+}
+
+func exampleString(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+func isFileItem(item api.ToolParameterItem) bool {
+	return strings.EqualFold(strings.TrimSpace(item.Type), "combinefileinput")
+}
+
+func isURL(v string) bool {
+	return strings.HasPrefix(v, "https://") || strings.HasPrefix(v, "http://")
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/jsonout"
+)
+
+func exampleDetail() *api.ToolDetail {
+	return &api.ToolDetail{
+		Description: "Image to image",
+		Parameters: []api.ToolParameterGroup{{Items: []api.ToolParameterItem{
+			{ID: "prompt", Type: "textarea", Label: "Prompt"},
+			{ID: "inputImage", Type: "combinefileinput", Label: "Image", Required: true},
+			{ID: "mask", Type: "combinefileinput", Label: "Mask", Required: true},
+			{ID: "steps", Type: "number", MinValue: "1", MaxValue: "50", IncrementBy: "1", DefaultValue: 30.0},
+			{ID: "size", Type: "select", Options: []api.ToolOption{{Text: "Square", Value: "1024x1024"}, {Value: "1344x768"}}},
+			{ID: "note", Type: "text"},
+			{ID: "seed", Type: "number", Advanced: true, DefaultValue: "42"},
+			{ID: "strength", Type: "float", Advanced: true, Required: true, MinValue: "0.1", MaxValue: "1", IncrementBy: "0.25"},
+		}}},
+		Inspire: []map[string]any{{
+			"prompt":     "a red fox",
+			"parameters": map[string]any{"inputImage": "https://cdn.example/fox.png"},
+		}},
+	}
+}
+
+func TestExample(t *testing.T) {
+	got := Example(exampleDetail())
+	want := []ExampleValue{
+		{Field: "prompt", Value: "a red fox", Source: SourceSample},
+		{Field: "inputImage", Value: "https://cdn.example/fox.png", URL: true, Source: SourceSample},
+		{Field: "mask", Value: "<Mask>", File: true, Source: SourcePlaceholder},
+		{Field: "steps", Value: "30", Source: SourceDefault},
+		{Field: "size", Value: "1024x1024", Source: SourceOption},
+		{Field: "strength", Value: "<strength>", Source: SourcePlaceholder},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Example =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestInputSchema(t *testing.T) {
+	s := InputSchema("o/m", exampleDetail())
+	if s["title"] != "o/m inputs" || s["description"] != "Image to image" {
+		t.Fatalf("header = %v", s)
+	}
+	if !reflect.DeepEqual(s["required"], []string{"prompt", "inputImage", "mask", "strength"}) {
+		t.Fatalf("required = %v", s["required"])
+	}
+	props := s["properties"].(jsonout.Schema)
+	steps := props["steps"].(jsonout.Schema)
+	if steps["type"] != "integer" || steps["minimum"] != 1.0 || steps["maximum"] != 50.0 || steps["multipleOf"] != 1.0 || steps["default"] != 30.0 {
+		t.Fatalf("steps = %v", steps)
+	}
+	// Steps from 0.1 are not multiples of 0.25 from zero.
+	if _, ok := props["strength"].(jsonout.Schema)["multipleOf"]; ok {
+		t.Fatalf("strength = %v", props["strength"])
+	}
+	if enum := props["size"].(jsonout.Schema)["enum"]; !reflect.DeepEqual(enum, []string{"1024x1024", "1344x768"}) {
+		t.Fatalf("size enum = %v", enum)
+	}
+	if ex := props["prompt"].(jsonout.Schema)["examples"]; !reflect.DeepEqual(ex, []interface{}{"a red fox"}) {
+		t.Fatalf("prompt examples = %v", ex)
+	}
+	if props["inputImage"].(jsonout.Schema)["x-file"] != true || props["seed"].(jsonout.Schema)["x-advanced"] != true {
+		t.Fatalf("markers missing: %v", props)
+	}
+}
@@ -0,0 +1,216 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+// PriceRule is one entry of a model's dynamic price schema.
+// A rule applies when all of its Inputs match the resolved run values.
+type PriceRule struct {
+	Inputs map[string]string
+	Price  float64
+	Method string
+}
+
+// Price methods describe what a rule's price is multiplied by.
+const (
+	PricePerRequest   = "cpr"
+	PricePerSecond    = "cps"
+	PricePerOutput    = "cpo"
+	PricePerStep      = "cpst"
+	PricePerMegapixel = "cpmp"
+)
+
+var (
+	durationFields = []string{"duration", "seconds", "length", "video_length"}
+	countFields    = []string{"num_outputs", "num_images", "samples", "batch_size", "n"}
+	stepFields     = []string{"steps", "num_inference_steps"}
+	sizePattern    = regexp.MustCompile(`^(\d+)\s*[x×*]\s*(\d+)$`)
+	heightPattern  = regexp.MustCompile(`^(\d+)p$`)
+)
+
+// ParsePriceRules normalizes the loosely typed dynamicprice field.
+// It accepts a number, a numeric string, a JSON-encoded string, one rule object, or a list of rules.
+func ParsePriceRules(raw interface{}) ([]PriceRule, error) {
+	switch v := raw.(type) {
+	case nil:
+		return nil, nil
+	case float64:
+		return []PriceRule{{Price: v, Method: PricePerRequest}}, nil
+	case string:
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return nil, nil
+		}
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return []PriceRule{{Price: f, Method: PricePerRequest}}, nil
+		}
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(v), &decoded); err != nil {
+			return nil, fmt.Errorf("unrecognized dynamic price %q", v)
+		}
+		return ParsePriceRules(decoded)
+	case map[string]interface{}:
+		rule, err := parsePriceRule(v)
+		if err != nil {
+			return nil, err
+		}
+		return []PriceRule{rule}, nil
+	case []interface{}:
+		rules := make([]PriceRule, 0, len(v))
+		for _, entry := range v {
+			obj, ok := entry.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("unrecognized dynamic price entry %v", entry)
+			}
+			rule, err := parsePriceRule(obj)
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, rule)
+		}
+		return rules, nil
+	default:
+		return nil, fmt.Errorf("unrecognized dynamic price %v", raw)
+	}
+}
+
+func parsePriceRule(obj map[string]interface{}) (PriceRule, error) {
+	rule := PriceRule{Inputs: map[string]string{}, Method: PricePerRequest}
+	price, ok := numberValue(obj["price"])
+	if !ok {
+		return PriceRule{}, fmt.Errorf("dynamic price entry without numeric price: %v", obj)
+	}
+	rule.Price = price
+	for _, key := range []string{"priceMethod", "pricemethod", "method"} {
+		if m, ok := obj[key].(string); ok && strings.TrimSpace(m) != "" {
+			rule.Method = strings.ToLower(strings.TrimSpace(m))
+			break
+		}
+	}
+	if inputs, ok := obj["inputs"].(map[string]interface{}); ok {
+		for k, v := range inputs {
+			rule.Inputs[k] = fmt.Sprint(v)
+		}
+	}
+	return rule, nil
+}
+
+// EstimatePrice returns the expected credit cost of a run when the model publishes a price.
+// Values missing from the run fall back to schema defaults.
+func EstimatePrice(detail *api.ToolDetail, values map[string][]api.MultipartValue) (float64, bool) {
+	if detail == nil {
+		return 0, false
+	}
+	rules, err := ParsePriceRules(detail.DynamicPrice)
+	if err != nil || len(rules) == 0 {
+		return 0, false
+	}
+	resolved := resolvedValues(detail, values)
+	rule, ok := matchPriceRule(rules, resolved)
+	if !ok {
+		return 0, false
+	}
+	cost := rule.Price * priceMultiplier(rule.Method, resolved)
+	return cost, cost > 0
+}
+
+func resolvedValues(detail *api.ToolDetail, values map[string][]api.MultipartValue) map[string]string {
+	out := map[string]string{}
+	for _, group := range detail.Parameters {
+		for _, item := range group.Items {
+			if item.DefaultValue != nil {
+				out[item.ID] = fmt.Sprint(item.DefaultValue)
+			}
+		}
+	}
+	for k, vals := range values {
+		if len(vals) > 0 && vals[0].FilePath == "" {
+			out[k] = vals[0].Value
+		}
+	}
+	return out
+}
+
+// matchPriceRule picks the matching rule with the most input constraints.
+func matchPriceRule(rules []PriceRule, resolved map[string]string) (PriceRule, bool) {
+	best := -1
+	var picked PriceRule
+	for _, rule := range rules {
+		matched := true
+		for k, want := range rule.Inputs {
+			if !strings.EqualFold(strings.TrimSpace(resolved[k]), strings.TrimSpace(want)) {
+				matched = false
+				break
+			}
+		}
+		if matched && len(rule.Inputs) > best {
+			best = len(rule.Inputs)
+			picked = rule
+		}
+	}
+	return picked, best >= 0
+}
+
+func priceMultiplier(method string, resolved map[string]string) float64 {
+	switch method {
+	case PricePerSecond:
+		return firstNumber(resolved, durationFields, 1)
+	case PricePerOutput:
+		return firstNumber(resolved, countFields, 1)
+	case PricePerStep:
+		return firstNumber(resolved, stepFields, 1)
+	case PricePerMegapixel:
+		return megapixels(resolved)
+	default:
+		return 1
+	}
+}
+
+func firstNumber(resolved map[string]string, fields []string, def float64) float64 {
+	for _, f := range fields {
+		if n, ok := numberValue(resolved[f]); ok && n > 0 {
+			return n
+		}
+	}
+	return def
+}
+
+func megapixels(resolved map[string]string) float64 {
+	w, wok := numberValue(resolved["width"])
+	h, hok := numberValue(resolved["height"])
+	if wok && hok && w > 0 && h > 0 {
+		return w * h / 1e6
+	}
+	for _, key := range []string{"resolution", "size", "aspect_resolution"} {
+		raw := strings.ToLower(strings.TrimSpace(resolved[key]))
+		if m := sizePattern.FindStringSubmatch(raw); m != nil {
+			w, _ := strconv.ParseFloat(m[1], 64)
+			h, _ := strconv.ParseFloat(m[2], 64)
+			return w * h / 1e6
+		}
+		if m := heightPattern.FindStringSubmatch(raw); m != nil {
+			h, _ := strconv.ParseFloat(m[1], 64)
+			return h * h * 16 / 9 / 1e6
+		}
+	}
+	return 1
+}
+
+func numberValue(v interface{}) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(t), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+func TestEstimatePrice_RulesAndMethods(t *testing.T) {
+	detail := &api.ToolDetail{
+		Parameters: []api.ToolParameterGroup{{Items: []api.ToolParameterItem{
+			{ID: "resolution", DefaultValue: "720p"},
+			{ID: "duration", DefaultValue: "5"},
+		}}},
+		DynamicPrice: `[{"inputs":{},"price":0.1,"priceMethod":"cps"},{"inputs":{"resolution":"1080p"},"price":0.2,"priceMethod":"cps"}]`,
+	}
+
+	got, ok := EstimatePrice(detail, nil)
+	if !ok || math.Abs(got-0.5) > 1e-9 {
+		t.Fatalf("default estimate = %v (%v), want 0.5", got, ok)
+	}
+
+	got, ok = EstimatePrice(detail, map[string][]api.MultipartValue{
+		"resolution": {{Value: "1080p"}},
+		"duration":   {{Value: "10"}},
+	})
+	if !ok || math.Abs(got-2.0) > 1e-9 {
+		t.Fatalf("specific estimate = %v (%v), want 2.0", got, ok)
+	}
+}
+
+func TestEstimatePrice_FlatAndUnknown(t *testing.T) {
+	if got, ok := EstimatePrice(&api.ToolDetail{DynamicPrice: "0.03"}, nil); !ok || got != 0.03 {
+		t.Fatalf("flat estimate = %v (%v)", got, ok)
+	}
+	if _, ok := EstimatePrice(&api.ToolDetail{}, nil); ok {
+		t.Fatalf("missing price should not produce an estimate")
+	}
+	if _, err := ParsePriceRules("not-a-price"); err == nil {
+		t.Fatalf("expected parse error")
+	}
+}
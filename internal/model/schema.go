@@ -0,0 +1,269 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/storage"
+)
+
+// SchemaField is the part of a parameter definition that affects how a run is built.
+type SchemaField struct {
+	ID       string   `json:"id"`
+	Type     string   `json:"type"`
+	Required bool     `json:"required"`
+	Advanced bool     `json:"advanced"`
+	Default  string   `json:"default,omitempty"`
+	Min      string   `json:"min,omitempty"`
+	Max      string   `json:"max,omitempty"`
+	Step     string   `json:"step,omitempty"`
+	Options  []string `json:"options,omitempty"`
+}
+
+// SchemaSnapshot records a model's parameter schema at a point in time.
+type SchemaSnapshot struct {
+	Model     string        `json:"model"`
+	FetchedAt string        `json:"fetchedAt"`
+	Fields    []SchemaField `json:"fields"`
+}
+
+// SchemaChange is one difference between two snapshots.
+type SchemaChange struct {
+	Field    string `json:"field"`
+	Kind     string `json:"kind"`
+	Detail   string `json:"detail"`
+	Breaking bool   `json:"breaking"`
+}
+
+// Schema change kinds.
+const (
+	SchemaAdded   = "added"
+	SchemaRemoved = "removed"
+	SchemaChanged = "changed"
+)
+
+// SnapshotFromDetail captures the parameter schema of detail.
+func SnapshotFromDetail(model string, detail *api.ToolDetail) SchemaSnapshot {
+	snap := SchemaSnapshot{Model: model, FetchedAt: time.Now().UTC().Format(time.RFC3339)}
+	for _, group := range detail.Parameters {
+		for _, item := range group.Items {
+			field := SchemaField{
+				ID:       item.ID,
+				Type:     strings.ToLower(strings.TrimSpace(item.Type)),
+				Required: item.Required,
+				Advanced: item.Advanced,
+				Min:      strings.TrimSpace(item.MinValue),
+				Max:      strings.TrimSpace(item.MaxValue),
+				Step:     strings.TrimSpace(item.IncrementBy),
+			}
+			if item.DefaultValue != nil {
+				field.Default = fmt.Sprint(item.DefaultValue)
+			}
+			for _, opt := range item.Options {
+				field.Options = append(field.Options, fmt.Sprint(opt.Value))
+			}
+			snap.Fields = append(snap.Fields, field)
+		}
+	}
+	sort.Slice(snap.Fields, func(i, j int) bool { return snap.Fields[i].ID < snap.Fields[j].ID })
+	return snap
+}
+
+// DiffSchemas lists field changes from old to cur. Breaking changes are ones that can
+// make a previously valid invocation fail: removed fields, new required fields, type
+// changes, tighter bounds, and removed options.
+func DiffSchemas(old, cur SchemaSnapshot) []SchemaChange {
+	oldByID := map[string]SchemaField{}
+	for _, f := range old.Fields {
+		oldByID[f.ID] = f
+	}
+	curByID := map[string]SchemaField{}
+	for _, f := range cur.Fields {
+		curByID[f.ID] = f
+	}
+
+	var changes []SchemaChange
+	for _, f := range cur.Fields {
+		prev, ok := oldByID[f.ID]
+		if !ok {
+			changes = append(changes, SchemaChange{Field: f.ID, Kind: SchemaAdded, Detail: fmt.Sprintf("type=%s required=%v", f.Type, f.Required), Breaking: f.Required})
+			continue
+		}
+		changes = append(changes, diffField(prev, f)...)
+	}
+	for _, f := range old.Fields {
+		if _, ok := curByID[f.ID]; !ok {
+			changes = append(changes, SchemaChange{Field: f.ID, Kind: SchemaRemoved, Detail: fmt.Sprintf("type=%s", f.Type), Breaking: true})
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+func diffField(prev, cur SchemaField) []SchemaChange {
+	var out []SchemaChange
+	add := func(detail string, breaking bool) {
+		out = append(out, SchemaChange{Field: cur.ID, Kind: SchemaChanged, Detail: detail, Breaking: breaking})
+	}
+	if prev.Type != cur.Type {
+		add(fmt.Sprintf("type %s -> %s", prev.Type, cur.Type), true)
+	}
+	if prev.Required != cur.Required {
+		add(fmt.Sprintf("required %v -> %v", prev.Required, cur.Required), cur.Required)
+	}
+	if prev.Default != cur.Default {
+		add(fmt.Sprintf("default %q -> %q", prev.Default, cur.Default), false)
+	}
+	if prev.Min != cur.Min {
+		add(fmt.Sprintf("min %q -> %q", prev.Min, cur.Min), boundTightened(prev.Min, cur.Min, true))
+	}
+	if prev.Max != cur.Max {
+		add(fmt.Sprintf("max %q -> %q", prev.Max, cur.Max), boundTightened(prev.Max, cur.Max, false))
+	}
+	if prev.Step != cur.Step {
+		add(fmt.Sprintf("step %q -> %q", prev.Step, cur.Step), cur.Step != "")
+	}
+	removed, added := optionDelta(prev.Options, cur.Options)
+	if len(removed) > 0 {
+		add("options removed: "+strings.Join(removed, ", "), true)
+	}
+	if len(added) > 0 {
+		add("options added: "+strings.Join(added, ", "), false)
+	}
+	return out
+}
+
+// boundTightened reports whether moving a min (or max) bound rejects values it used to accept.
+func boundTightened(prev, cur string, isMin bool) bool {
+	p, pok := parseBound(prev)
+	c, cok := parseBound(cur)
+	switch {
+	case !cok:
+		return false
+	case !pok:
+		return true
+	case isMin:
+		return c > p
+	default:
+		return c < p
+	}
+}
+
+func optionDelta(prev, cur []string) (removed, added []string) {
+	curSet := map[string]bool{}
+	for _, o := range cur {
+		curSet[o] = true
+	}
+	prevSet := map[string]bool{}
+	for _, o := range prev {
+		prevSet[o] = true
+		if !curSet[o] {
+			removed = append(removed, o)
+		}
+	}
+	for _, o := range cur {
+		if !prevSet[o] {
+			added = append(added, o)
+		}
+	}
+	return removed, added
+}
+
+// SchemaCache stores the last seen schema snapshot per model in the schemas
+// collection of a storage backend.
+type SchemaCache struct {
+	st storage.Storage
+}
+
+// NewSchemaCache returns a cache backed by <dir>/schemas.jsonl.
+func NewSchemaCache(dir string) *SchemaCache {
+	return OpenSchemaCache(storage.NewJSONL(dir))
+}
+
+// OpenSchemaCache returns a cache keeping snapshots in st.
+func OpenSchemaCache(st storage.Storage) *SchemaCache {
+	return &SchemaCache{st: st}
+}
+
+// latest returns the newest snapshot of each model, in write order, and the
+// number of stored records.
+func (c *SchemaCache) latest() ([]SchemaSnapshot, int, error) {
+	records, err := c.st.Records(storage.Schemas)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read schema cache: %w", err)
+	}
+	index := map[string]int{}
+	var snaps []SchemaSnapshot
+	for _, raw := range records {
+		var snap SchemaSnapshot
+		if err := json.Unmarshal(raw, &snap); err != nil || snap.Model == "" {
+			continue
+		}
+		if i, ok := index[snap.Model]; ok {
+			snaps[i] = snap
+			continue
+		}
+		index[snap.Model] = len(snaps)
+		snaps = append(snaps, snap)
+	}
+	return snaps, len(records), nil
+}
+
+// Load returns the cached snapshot for model, or nil when none exists.
+func (c *SchemaCache) Load(model string) (*SchemaSnapshot, error) {
+	snaps, _, err := c.latest()
+	if err != nil {
+		return nil, err
+	}
+	for i := range snaps {
+		if snaps[i].Model == model {
+			return &snaps[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// Models lists the models with a cached snapshot, in the order first seen.
+func (c *SchemaCache) Models() ([]string, error) {
+	snaps, _, err := c.latest()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(snaps))
+	for _, s := range snaps {
+		out = append(out, s.Model)
+	}
+	return out, nil
+}
+
+// Save records snap, replacing any previous snapshot for the model. Superseded
+// snapshots are dropped once they outnumber the current ones.
+func (c *SchemaCache) Save(snap SchemaSnapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("marshal schema snapshot: %w", err)
+	}
+	if err := c.st.Put(storage.Schemas, snap.Model, data); err != nil {
+		return fmt.Errorf("write schema snapshot: %w", err)
+	}
+	snaps, stored, err := c.latest()
+	if err != nil || stored <= 2*len(snaps) {
+		return err
+	}
+	records := make([]storage.Record, 0, len(snaps))
+	for _, s := range snaps {
+		data, err := json.Marshal(s)
+		if err != nil {
+			return fmt.Errorf("marshal schema snapshot: %w", err)
+		}
+		records = append(records, storage.Record{Key: s.Model, Data: data})
+	}
+	if err := c.st.Replace(storage.Schemas, records); err != nil {
+		return fmt.Errorf("compact schema cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+func TestDiffSchemas_FlagsBreakingChanges(t *testing.T) {
+	old := SnapshotFromDetail("a/b", &api.ToolDetail{Parameters: []api.ToolParameterGroup{{Items: []api.ToolParameterItem{
+		{ID: "prompt", Type: "text", Required: true},
+		{ID: "steps", Type: "number", MaxValue: "50"},
+		{ID: "size", Type: "select", Options: []api.ToolOption{{Value: "512"}, {Value: "1024"}}},
+		{ID: "legacy", Type: "text"},
+	}}}})
+	cur := SnapshotFromDetail("a/b", &api.ToolDetail{Parameters: []api.ToolParameterGroup{{Items: []api.ToolParameterItem{
+		{ID: "prompt", Type: "text", Required: true},
+		{ID: "steps", Type: "number", MaxValue: "30"},
+		{ID: "size", Type: "select", Options: []api.ToolOption{{Value: "1024"}, {Value: "2048"}}},
+		{ID: "seed", Type: "number"},
+	}}}})
+
+	changes := DiffSchemas(old, cur)
+	got := map[string]bool{}
+	for _, c := range changes {
+		got[c.Field+"/"+c.Kind+"/"+c.Detail] = c.Breaking
+	}
+	want := map[string]bool{
+		"legacy/removed/type=text":              true,
+		"seed/added/type=number required=false": false,
+		`steps/changed/max "50" -> "30"`:        true,
+		"size/changed/options removed: 512":     true,
+		"size/changed/options added: 2048":      false,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected changes: %#v", changes)
+	}
+	for k, breaking := range want {
+		if b, ok := got[k]; !ok || b != breaking {
+			t.Fatalf("change %q: got breaking=%v present=%v", k, b, ok)
+		}
+	}
+	if len(DiffSchemas(cur, cur)) != 0 {
+		t.Fatalf("identical schemas should not differ")
+	}
+}
+
+func TestSchemaCache_RoundTrip(t *testing.T) {
+	cache := NewSchemaCache(t.TempDir())
+	if snap, err := cache.Load("a/b"); err != nil || snap != nil {
+		t.Fatalf("empty cache Load() = %v, %v", snap, err)
+	}
+	in := SchemaSnapshot{Model: "a/b", Fields: []SchemaField{{ID: "prompt", Type: "text"}}}
+	if err := cache.Save(in); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	out, err := cache.Load("a/b")
+	if err != nil || out == nil || len(out.Fields) != 1 || out.Fields[0].ID != "prompt" {
+		t.Fatalf("Load() = %#v, %v", out, err)
+	}
+	_ = cache.Save(SchemaSnapshot{Model: "c/d"})
+	_ = cache.Save(in)
+	if models, err := cache.Models(); err != nil || len(models) != 2 || models[0] != "a/b" || models[1] != "c/d" {
+		t.Fatalf("Models() = %v, %v", models, err)
+	}
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+// SecretMask stands in for a secret field's value wherever inputs are shown
+// or stored: console output, history entries, and manifests.
+const SecretMask = "********"
+
+// secretClass marks a field as secret, in the schema's class list or added by
+// MarkSecret for --secret-field.
+const secretClass = "secret"
+
+// IsSecret reports whether item holds sensitive data: a password field, or
+// one whose schema class includes "secret" or "password".
+func IsSecret(item api.ToolParameterItem) bool {
+	if strings.EqualFold(strings.TrimSpace(item.Type), "password") {
+		return true
+	}
+	for _, class := range strings.Fields(strings.ToLower(item.Class)) {
+		if class == secretClass || class == "password" {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkSecret adds the secret class to the named fields of detail, so they are
+// prompted for and recorded like schema-declared secrets.
+func MarkSecret(detail *api.ToolDetail, fields []string) {
+	if detail == nil || len(fields) == 0 {
+		return
+	}
+	want := map[string]bool{}
+	for _, f := range fields {
+		want[strings.TrimSpace(f)] = true
+	}
+	for gi := range detail.Parameters {
+		items := detail.Parameters[gi].Items
+		for i := range items {
+			if want[items[i].ID] && !IsSecret(items[i]) {
+				items[i].Class = strings.TrimSpace(items[i].Class + " " + secretClass)
+			}
+		}
+	}
+}
+
+// SecretFields is the set of secret field ids among items, plus extra.
+func SecretFields(items []api.ToolParameterItem, extra []string) map[string]bool {
+	out := map[string]bool{}
+	for _, item := range items {
+		if IsSecret(item) {
+			out[item.ID] = true
+		}
+	}
+	for _, f := range extra {
+		if f = strings.TrimSpace(f); f != "" {
+			out[f] = true
+		}
+	}
+	return out
+}
+
+// MaskInputs returns a copy of values with secret text values replaced by
+// SecretMask. File inputs keep their path.
+func MaskInputs(values map[string][]api.MultipartValue, secret map[string]bool) map[string][]api.MultipartValue {
+	if len(secret) == 0 {
+		return values
+	}
+	out := make(map[string][]api.MultipartValue, len(values))
+	for k, vals := range values {
+		if !secret[k] {
+			out[k] = vals
+			continue
+		}
+		masked := make([]api.MultipartValue, len(vals))
+		for i, v := range vals {
+			if v.FilePath == "" {
+				v.Value = SecretMask
+			}
+			masked[i] = v
+		}
+		out[k] = masked
+	}
+	return out
+}
+
+// MaskFlags returns a copy of key=value flag values with secret values
+// replaced by SecretMask.
+func MaskFlags(flags []string, secret map[string]bool) []string {
+	if len(secret) == 0 || len(flags) == 0 {
+		return flags
+	}
+	out := make([]string, len(flags))
+	for i, kv := range flags {
+		if key, _, ok := strings.Cut(kv, "="); ok && secret[strings.TrimSpace(key)] {
+			kv = key + "=" + SecretMask
+		}
+		out[i] = kv
+	}
+	return out
+}
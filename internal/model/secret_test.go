@@ -0,0 +1,46 @@
+package model
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+func TestSecretFieldsAndMasking(t *testing.T) {
+	detail := &api.ToolDetail{Parameters: []api.ToolParameterGroup{{Items: []api.ToolParameterItem{
+		{ID: "prompt", Type: "textarea"},
+		{ID: "apiKey", Type: "text", Class: "form-control Secret"},
+		{ID: "token", Type: "password"},
+		{ID: "email", Type: "text"},
+		{ID: "steps", Type: "number", MaxValue: "50"},
+	}}}}
+	MarkSecret(detail, []string{"email", "steps"})
+	got := SecretFields(FlattenItems(detail, true), []string{"extra"})
+	want := map[string]bool{"apiKey": true, "token": true, "email": true, "steps": true, "extra": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("SecretFields = %v", got)
+	}
+
+	inputs := map[string][]api.MultipartValue{
+		"prompt": {{Value: "a fox"}},
+		"apiKey": {{Value: "sk-123"}},
+		"email":  {{FilePath: "/tmp/list.csv"}},
+	}
+	masked := MaskInputs(inputs, got)
+	if masked["prompt"][0].Value != "a fox" || masked["apiKey"][0].Value != SecretMask || masked["email"][0].FilePath != "/tmp/list.csv" {
+		t.Fatalf("MaskInputs = %v", masked)
+	}
+	if inputs["apiKey"][0].Value != "sk-123" {
+		t.Fatal("MaskInputs changed its input")
+	}
+	if flags := MaskFlags([]string{"apiKey=sk-123", "prompt=a=b"}, got); !reflect.DeepEqual(flags, []string{"apiKey=" + SecretMask, "prompt=a=b"}) {
+		t.Fatalf("MaskFlags = %v", flags)
+	}
+
+	_, err := ValidateValues(FlattenItems(detail, true), map[string][]api.MultipartValue{"steps": {{Value: "9001"}}})
+	if err == nil || strings.Contains(err.Error(), "9001") {
+		t.Fatalf("secret value in validation error: %v", err)
+	}
+}
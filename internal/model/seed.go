@@ -0,0 +1,121 @@
+package model
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+// maxSeed bounds random seeds when the schema sets no maximum; it fits the
+// signed 32-bit seeds most backends use.
+const maxSeed = 1<<31 - 1
+
+// isSeedID reports whether a field id names a generation seed: "seed", or a
+// name ending in it such as noise_seed.
+func isSeedID(id string) bool {
+	id = strings.ToLower(strings.TrimSpace(id))
+	return id == "seed" || strings.HasSuffix(id, "_seed") || strings.HasSuffix(id, "-seed") || id == "randomseed" || id == "noiseseed"
+}
+
+// SeedField finds the field that seeds generation, preferring one named
+// exactly "seed".
+func SeedField(items []api.ToolParameterItem) (api.ToolParameterItem, bool) {
+	var found []api.ToolParameterItem
+	for _, item := range items {
+		if strings.EqualFold(strings.TrimSpace(item.ID), "seed") {
+			return item, true
+		}
+		if isSeedID(item.ID) {
+			found = append(found, item)
+		}
+	}
+	if len(found) == 0 {
+		return api.ToolParameterItem{}, false
+	}
+	return found[0], true
+}
+
+// SeedValue returns the seed a run's inputs set, or "" when they set none.
+func SeedValue(inputs map[string][]api.MultipartValue) string {
+	keys := make([]string, 0, len(inputs))
+	for k := range inputs {
+		if isSeedID(k) && len(inputs[k]) > 0 {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		// An exact "seed" wins, as in SeedField.
+		if a, b := strings.EqualFold(keys[i], "seed"), strings.EqualFold(keys[j], "seed"); a != b {
+			return a
+		}
+		return keys[i] < keys[j]
+	})
+	return strings.TrimSpace(inputs[keys[0]][0].Value)
+}
+
+// seedRange is the schema's minvalue/maxvalue for item, defaulting to
+// 0..maxSeed.
+func seedRange(item api.ToolParameterItem) (int64, int64) {
+	lo, hi := int64(0), int64(maxSeed)
+	if v, err := strconv.ParseInt(strings.TrimSpace(item.MinValue), 10, 64); err == nil && v >= 0 {
+		lo = v
+	}
+	if v, err := strconv.ParseInt(strings.TrimSpace(item.MaxValue), 10, 64); err == nil && v > lo {
+		hi = v
+	}
+	return lo, hi
+}
+
+// ParseSeed checks that s is an integer seed item accepts.
+func ParseSeed(item api.ToolParameterItem, s string) (int64, error) {
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("seed %q is not an integer", s)
+	}
+	if lo, hi := seedRange(item); n < lo || n > hi {
+		return 0, fmt.Errorf("seed %d is outside %s's range %d..%d", n, item.ID, lo, hi)
+	}
+	return n, nil
+}
+
+// Seeds returns n seeds for item: start, start+1, ... when start is given,
+// otherwise n distinct random seeds in the field's range.
+func Seeds(item api.ToolParameterItem, start string, n int) ([]string, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("seed count must be at least 1, got %d", n)
+	}
+	lo, hi := seedRange(item)
+	out := make([]string, 0, n)
+	if strings.TrimSpace(start) != "" {
+		first, err := ParseSeed(item, start)
+		if err != nil {
+			return nil, err
+		}
+		if first+int64(n-1) > hi {
+			return nil, fmt.Errorf("%d seeds from %d pass %s's maximum %d", n, first, item.ID, hi)
+		}
+		for i := 0; i < n; i++ {
+			out = append(out, strconv.FormatInt(first+int64(i), 10))
+		}
+		return out, nil
+	}
+	if span := hi - lo + 1; span < int64(n) {
+		return nil, fmt.Errorf("%s only allows %d distinct seeds", item.ID, span)
+	}
+	seen := map[int64]bool{}
+	for len(out) < n {
+		s := lo + rand.Int64N(hi-lo+1)
+		if !seen[s] {
+			seen[s] = true
+			out = append(out, strconv.FormatInt(s, 10))
+		}
+	}
+	return out, nil
+}
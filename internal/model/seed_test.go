@@ -0,0 +1,49 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+func TestSeedField(t *testing.T) {
+	items := []api.ToolParameterItem{{ID: "prompt"}, {ID: "noise_seed"}, {ID: "seed"}}
+	if item, ok := SeedField(items); !ok || item.ID != "seed" {
+		t.Fatalf("SeedField = %q, %v", item.ID, ok)
+	}
+	if item, ok := SeedField(items[:2]); !ok || item.ID != "noise_seed" {
+		t.Fatalf("SeedField suffix = %q, %v", item.ID, ok)
+	}
+	if _, ok := SeedField([]api.ToolParameterItem{{ID: "seedream_prompt"}}); ok {
+		t.Fatal("seedream_prompt taken as a seed")
+	}
+	inputs := map[string][]api.MultipartValue{"noise_seed": {{Value: "7"}}, "seed": {{Value: " 42 "}}}
+	if got := SeedValue(inputs); got != "42" {
+		t.Fatalf("SeedValue = %q", got)
+	}
+}
+
+func TestSeeds(t *testing.T) {
+	item := api.ToolParameterItem{ID: "seed", MinValue: "0", MaxValue: "100"}
+	got, err := Seeds(item, "98", 3)
+	if err != nil || len(got) != 3 || got[0] != "98" || got[2] != "100" {
+		t.Fatalf("sequential = %v, %v", got, err)
+	}
+	if _, err := Seeds(item, "99", 3); err == nil {
+		t.Fatal("seeds past the maximum accepted")
+	}
+	random, err := Seeds(item, "", 50)
+	if err != nil || len(random) != 50 {
+		t.Fatalf("random = %v, %v", random, err)
+	}
+	seen := map[string]bool{}
+	for _, s := range random {
+		if _, err := ParseSeed(item, s); err != nil || seen[s] {
+			t.Fatalf("random seed %q: err=%v duplicate=%v", s, err, seen[s])
+		}
+		seen[s] = true
+	}
+	if _, err := ParseSeed(item, "abc"); err == nil {
+		t.Fatal("non-integer seed accepted")
+	}
+}
@@ -18,27 +18,55 @@ func NewService(apiClient *api.Client) *Service {
 	return &Service{apiClient: apiClient}
 }
 
-// List returns public models from /Tool/List with optional query.
-func (s *Service) List(ctx context.Context, query string, limit int) ([]api.ToolSummary, error) {
+// ListOptions narrows /Tool/List results. Empty fields are not sent.
+type ListOptions struct {
+	Query    string
+	Category string
+	Tag      string
+	Owner    string
+	Limit    int
+	// Start skips that many models, for paging with ListPage.
+	Start int
+}
+
+// List returns public models from /Tool/List filtered by opts.
+func (s *Service) List(ctx context.Context, opts ListOptions) ([]api.ToolSummary, error) {
+	tools, _, err := s.ListPage(ctx, opts)
+	return tools, err
+}
+
+// ListPage returns one page of List from opts.Start on, together with the
+// number of models matching opts (0 when the server does not say).
+func (s *Service) ListPage(ctx context.Context, opts ListOptions) ([]api.ToolSummary, int, error) {
+	limit := opts.Limit
 	if limit <= 0 {
 		limit = 50
 	}
 	body := map[string]interface{}{
-		"start":   "0",
+		"start":   fmt.Sprintf("%d", max(opts.Start, 0)),
 		"limit":   fmt.Sprintf("%d", limit),
 		"sort":    "id",
 		"order":   "DESC",
 		"summary": true,
 	}
-	if strings.TrimSpace(query) != "" {
-		body["search"] = strings.TrimSpace(query)
+	if q := strings.TrimSpace(opts.Query); q != "" {
+		body["search"] = q
+	}
+	if c := strings.TrimSpace(opts.Category); c != "" {
+		body["categories"] = []string{c}
+	}
+	if t := strings.TrimSpace(opts.Tag); t != "" {
+		body["tags"] = []string{t}
+	}
+	if o := strings.TrimSpace(opts.Owner); o != "" {
+		body["slugowner"] = o
 	}
 	var resp api.ToolListResponse
 	if err := s.apiClient.PostJSON(ctx, "/Tool/List", body, nil, &resp); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 	if !resp.Result && len(resp.Errors) > 0 {
-		return nil, fmt.Errorf("tool list failed: %s", resp.Errors[0].Message)
+		return nil, 0, fmt.Errorf("tool list failed: %w", api.ResponseError(resp.Errors))
 	}
 
 	sort.Slice(resp.Tools, func(i, j int) bool {
@@ -46,24 +74,25 @@ func (s *Service) List(ctx context.Context, query string, limit int) ([]api.Tool
 		right := strings.ToLower(resp.Tools[j].SlugOwner + "/" + resp.Tools[j].SlugProject)
 		return left < right
 	})
-	return resp.Tools, nil
+	return resp.Tools, resp.Total, nil
 }
 
-// Detail loads full model definition and parameter schema.
+// Detail loads full model definition and parameter schema. The response is
+// revalidated with the server when the client has a response cache.
 func (s *Service) Detail(ctx context.Context, owner, slug string) (*api.ToolDetail, error) {
 	var resp api.ToolDetailResponse
 	body := map[string]interface{}{
 		"slugowner":   owner,
 		"slugproject": slug,
 	}
-	if err := s.apiClient.PostJSON(ctx, "/Tool/Detail", body, nil, &resp); err != nil {
+	if err := s.apiClient.PostJSONCached(ctx, "/Tool/Detail", body, nil, &resp); err != nil {
 		return nil, err
 	}
 	if !resp.Result && len(resp.Errors) > 0 {
-		return nil, fmt.Errorf("tool detail failed: %s", resp.Errors[0].Message)
+		return nil, fmt.Errorf("tool detail failed: %w", api.ResponseError(resp.Errors))
 	}
 	if len(resp.Tools) == 0 {
-		return nil, fmt.Errorf("tool detail not found for %s/%s", owner, slug)
+		return nil, fmt.Errorf("tool detail for %s/%s: %w", owner, slug, api.ErrModelNotFound)
 	}
 	return &resp.Tools[0], nil
 }
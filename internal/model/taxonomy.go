@@ -0,0 +1,98 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+// TermCount is a category or tag with the number of models carrying it.
+type TermCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Taxonomy lists the categories and tags used by public models.
+type Taxonomy struct {
+	Categories []TermCount `json:"categories"`
+	Tags       []TermCount `json:"tags"`
+}
+
+// Taxonomy scans up to limit public models and counts their categories and tags.
+func (s *Service) Taxonomy(ctx context.Context, limit int) (Taxonomy, error) {
+	tools, err := s.List(ctx, ListOptions{Limit: limit})
+	if err != nil {
+		return Taxonomy{}, fmt.Errorf("list models for taxonomy: %w", err)
+	}
+	return BuildTaxonomy(tools), nil
+}
+
+// BuildTaxonomy counts categories and tags across tools, most used first.
+func BuildTaxonomy(tools []api.ToolSummary) Taxonomy {
+	cats := map[string]int{}
+	tags := map[string]int{}
+	for _, t := range tools {
+		for _, c := range Terms(t.Categories) {
+			cats[c]++
+		}
+		for _, tag := range Terms(t.Tags) {
+			tags[tag]++
+		}
+	}
+	return Taxonomy{Categories: sortedTerms(cats), Tags: sortedTerms(tags)}
+}
+
+// Terms normalizes a categories/tags field, which the API returns as a string
+// list, a comma-separated string, or a list of objects with a name/value/slug.
+func Terms(v interface{}) []string {
+	var raw []string
+	switch x := v.(type) {
+	case nil:
+	case string:
+		raw = strings.Split(x, ",")
+	case []string:
+		raw = x
+	case []interface{}:
+		for _, item := range x {
+			switch e := item.(type) {
+			case string:
+				raw = append(raw, e)
+			case map[string]interface{}:
+				for _, key := range []string{"value", "slug", "name", "title"} {
+					if s, ok := e[key].(string); ok && strings.TrimSpace(s) != "" {
+						raw = append(raw, s)
+						break
+					}
+				}
+			}
+		}
+	}
+	seen := map[string]bool{}
+	out := make([]string, 0, len(raw))
+	for _, r := range raw {
+		r = strings.TrimSpace(r)
+		if r == "" || seen[r] {
+			continue
+		}
+		seen[r] = true
+		out = append(out, r)
+	}
+	return out
+}
+
+func sortedTerms(counts map[string]int) []TermCount {
+	out := make([]TermCount, 0, len(counts))
+	for name, n := range counts {
+		out = append(out, TermCount{Name: name, Count: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+func TestTerms_Shapes(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want []string
+	}{
+		{in: nil, want: []string{}},
+		{in: "image, video ,image", want: []string{"image", "video"}},
+		{in: []interface{}{"llm", " ", "llm"}, want: []string{"llm"}},
+		{in: []interface{}{map[string]interface{}{"value": "text-to-image", "name": "Text to Image"}}, want: []string{"text-to-image"}},
+	}
+	for _, tc := range tests {
+		if got := Terms(tc.in); !reflect.DeepEqual(got, tc.want) {
+			t.Fatalf("Terms(%#v) = %#v, want %#v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestBuildTaxonomy_CountsAndOrders(t *testing.T) {
+	tax := BuildTaxonomy([]api.ToolSummary{
+		{Categories: []interface{}{"image", "video"}, Tags: "fast"},
+		{Categories: []interface{}{"image"}, Tags: []interface{}{"fast", "hd"}},
+	})
+	wantCats := []TermCount{{Name: "image", Count: 2}, {Name: "video", Count: 1}}
+	if !reflect.DeepEqual(tax.Categories, wantCats) {
+		t.Fatalf("categories = %#v", tax.Categories)
+	}
+	wantTags := []TermCount{{Name: "fast", Count: 2}, {Name: "hd", Count: 1}}
+	if !reflect.DeepEqual(tax.Tags, wantTags) {
+		t.Fatalf("tags = %#v", tax.Tags)
+	}
+}
@@ -0,0 +1,221 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+// Violation describes one field value that does not satisfy the model schema.
+type Violation struct {
+	Field   string
+	Message string
+}
+
+// ValidationError collects every schema violation found in a set of inputs.
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		lines = append(lines, fmt.Sprintf("%s: %s", v.Field, v.Message))
+	}
+	if len(lines) == 1 {
+		return "invalid input " + lines[0]
+	}
+	return fmt.Sprintf("%d invalid inputs:\n  %s", len(lines), strings.Join(lines, "\n  "))
+}
+
+func (e *ValidationError) Unwrap() error { return api.ErrInvalidInput }
+
+// ValidateValues coerces preset values to the form the API expects and checks them
+// against the item constraints (numeric type, min/max/step, select options, entry count).
+// Fields without a schema item pass through untouched.
+func ValidateValues(items []api.ToolParameterItem, values map[string][]api.MultipartValue) (map[string][]api.MultipartValue, error) {
+	return CheckInputs(items, values, nil)
+}
+
+// CheckInputs is ValidateValues plus a presence check: every item in required that has
+// no value is reported as missing, together with what it expects, unless its showif
+// rule hides it. All problems are returned in one ValidationError.
+func CheckInputs(items []api.ToolParameterItem, values map[string][]api.MultipartValue, required []api.ToolParameterItem) (map[string][]api.MultipartValue, error) {
+	byID := make(map[string]api.ToolParameterItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+
+	ids := make([]string, 0, len(values))
+	for id := range values {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	out := make(map[string][]api.MultipartValue, len(values))
+	var violations []Violation
+	for _, id := range ids {
+		vals := values[id]
+		item, ok := byID[id]
+		if !ok {
+			out[id] = vals
+			continue
+		}
+		if item.MaxInputLenght > 0 && len(vals) > item.MaxInputLenght {
+			violations = append(violations, Violation{Field: id, Message: fmt.Sprintf("accepts at most %d entries, got %d", item.MaxInputLenght, len(vals))})
+		}
+		coerced := make([]api.MultipartValue, 0, len(vals))
+		for _, v := range vals {
+			if v.FilePath != "" {
+				coerced = append(coerced, v)
+				continue
+			}
+			normalized, msg := coerceValue(item, v.Value)
+			if msg != "" && IsSecret(item) && v.Value != "" {
+				msg = strings.ReplaceAll(msg, v.Value, SecretMask)
+			}
+			if msg != "" {
+				violations = append(violations, Violation{Field: id, Message: msg})
+			}
+			coerced = append(coerced, api.MultipartValue{Value: normalized})
+		}
+		out[id] = coerced
+	}
+	for _, item := range required {
+		if len(values[item.ID]) == 0 && Visible(item, items, values) {
+			violations = append(violations, Violation{Field: item.ID, Message: "required field is missing (expects " + Expected(item) + ")"})
+		}
+	}
+	if len(violations) > 0 {
+		return nil, &ValidationError{Violations: violations}
+	}
+	return out, nil
+}
+
+func coerceValue(item api.ToolParameterItem, raw string) (string, string) {
+	value := strings.TrimSpace(raw)
+	switch strings.ToLower(strings.TrimSpace(item.Type)) {
+	case "number":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil || n != math.Trunc(n) {
+			return raw, fmt.Sprintf("expects an integer, got %q", raw)
+		}
+		return strconv.FormatInt(int64(n), 10), checkRange(item, n)
+	case "float":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return raw, fmt.Sprintf("expects a number, got %q", raw)
+		}
+		return value, checkRange(item, n)
+	case "checkbox":
+		switch strings.ToLower(value) {
+		case "true", "1", "yes", "y", "on":
+			return "true", ""
+		case "false", "0", "no", "n", "off":
+			return "false", ""
+		}
+		return raw, fmt.Sprintf("expects true or false, got %q", raw)
+	case "select", "selectwithcover":
+		return coerceOption(item, value, raw)
+	default:
+		return raw, ""
+	}
+}
+
+// Expected describes the value a field accepts, e.g. "integer 1..50" or "one of 512, 1024".
+func Expected(item api.ToolParameterItem) string {
+	bounds := ""
+	if lo, hi := strings.TrimSpace(item.MinValue), strings.TrimSpace(item.MaxValue); lo != "" || hi != "" {
+		bounds = " " + lo + ".." + hi
+	}
+	if step := strings.TrimSpace(item.IncrementBy); step != "" && bounds != "" {
+		bounds += " step " + step
+	}
+	switch strings.ToLower(strings.TrimSpace(item.Type)) {
+	case "number":
+		return "integer" + bounds
+	case "float":
+		return "number" + bounds
+	case "checkbox":
+		return "true or false"
+	case "select", "selectwithcover":
+		if len(item.Options) == 0 {
+			return "an option value"
+		}
+		opts := make([]string, 0, len(item.Options))
+		for _, opt := range item.Options {
+			opts = append(opts, fmt.Sprint(opt.Value))
+		}
+		return "one of " + strings.Join(opts, ", ")
+	case "combinefileinput":
+		return "file path or URL via --set-file/--set-url"
+	case "text", "textarea":
+		return "text"
+	default:
+		if t := strings.TrimSpace(item.Type); t != "" {
+			return t
+		}
+		return "a value"
+	}
+}
+
+func checkRange(item api.ToolParameterItem, n float64) string {
+	lo, hasLo := parseBound(item.MinValue)
+	hi, hasHi := parseBound(item.MaxValue)
+	if hasLo && n < lo {
+		return fmt.Sprintf("must be >= %s, got %s", formatBound(lo), formatBound(n))
+	}
+	if hasHi && n > hi {
+		return fmt.Sprintf("must be <= %s, got %s", formatBound(hi), formatBound(n))
+	}
+	step, hasStep := parseBound(item.IncrementBy)
+	if hasStep && step > 0 {
+		base := 0.0
+		if hasLo {
+			base = lo
+		}
+		steps := (n - base) / step
+		if math.Abs(steps-math.Round(steps)) > 1e-6 {
+			return fmt.Sprintf("must be a multiple of %s from %s, got %s", formatBound(step), formatBound(base), formatBound(n))
+		}
+	}
+	return ""
+}
+
+func coerceOption(item api.ToolParameterItem, value, raw string) (string, string) {
+	if len(item.Options) == 0 {
+		return raw, ""
+	}
+	allowed := make([]string, 0, len(item.Options))
+	for _, opt := range item.Options {
+		v := fmt.Sprint(opt.Value)
+		if v == value {
+			return v, ""
+		}
+		allowed = append(allowed, v)
+	}
+	for _, opt := range item.Options {
+		v := fmt.Sprint(opt.Value)
+		if strings.EqualFold(v, value) || (strings.TrimSpace(opt.Text) != "" && strings.EqualFold(strings.TrimSpace(opt.Text), value)) {
+			return v, ""
+		}
+	}
+	return raw, fmt.Sprintf("must be one of %s, got %q", strings.Join(allowed, ", "), raw)
+}
+
+func parseBound(v string) (float64, bool) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	return f, err == nil
+}
+
+func formatBound(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
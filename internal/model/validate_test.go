@@ -0,0 +1,49 @@
+package model
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+func TestValidateValues_CoercesAndReportsAll(t *testing.T) {
+	items := []api.ToolParameterItem{
+		{ID: "steps", Type: "number", MinValue: "1", MaxValue: "50"},
+		{ID: "scale", Type: "float", MinValue: "0", MaxValue: "2", IncrementBy: "0.5"},
+		{ID: "hd", Type: "checkbox"},
+		{ID: "size", Type: "select", Options: []api.ToolOption{{Text: "Square", Value: "1024x1024"}, {Text: "Wide", Value: "1344x768"}}},
+	}
+
+	out, err := ValidateValues(items, map[string][]api.MultipartValue{
+		"steps": {{Value: "20.0"}},
+		"scale": {{Value: "1.5"}},
+		"hd":    {{Value: "yes"}},
+		"size":  {{Value: "wide"}},
+		"extra": {{Value: "kept"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out["steps"][0].Value != "20" || out["hd"][0].Value != "true" || out["size"][0].Value != "1344x768" || out["extra"][0].Value != "kept" {
+		t.Fatalf("unexpected coercion: %#v", out)
+	}
+
+	_, err = ValidateValues(items, map[string][]api.MultipartValue{
+		"steps": {{Value: "80"}},
+		"scale": {{Value: "0.7"}},
+		"hd":    {{Value: "maybe"}},
+		"size":  {{Value: "tall"}},
+	})
+	var verr *ValidationError
+	if !errors.As(err, &verr) || len(verr.Violations) != 4 {
+		t.Fatalf("expected 4 violations, got %v", err)
+	}
+	if !errors.Is(err, api.ErrInvalidInput) {
+		t.Fatalf("validation error should match api.ErrInvalidInput")
+	}
+	if !strings.Contains(err.Error(), "steps: must be <= 50") {
+		t.Fatalf("unexpected message: %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+// Condition is one way for a field to apply: every listed field must have one
+// of its values.
+type Condition map[string][]string
+
+// ParseConditions normalizes the loosely typed showif field of an item. It
+// accepts "mode=inpaint", "mode=inpaint,outpaint", {"mode": "inpaint"},
+// {"mode": ["inpaint", "outpaint"]}, a JSON-encoded string of those, or a list
+// of them. The field applies when any condition holds; none means always.
+func ParseConditions(raw interface{}) ([]Condition, error) {
+	switch v := raw.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return nil, nil
+		}
+		if strings.HasPrefix(v, "{") || strings.HasPrefix(v, "[") {
+			var decoded interface{}
+			if err := json.Unmarshal([]byte(v), &decoded); err != nil {
+				return nil, fmt.Errorf("unrecognized showif %q", v)
+			}
+			return ParseConditions(decoded)
+		}
+		field, values, ok := strings.Cut(v, "=")
+		if !ok || strings.TrimSpace(field) == "" {
+			return nil, fmt.Errorf("unrecognized showif %q (want field=value)", v)
+		}
+		return []Condition{{strings.TrimSpace(field): splitConditionValues(values)}}, nil
+	case map[string]interface{}:
+		cond := Condition{}
+		for field, want := range v {
+			values, err := conditionValues(want)
+			if err != nil {
+				return nil, fmt.Errorf("showif %s: %w", field, err)
+			}
+			cond[field] = values
+		}
+		return []Condition{cond}, nil
+	case []interface{}:
+		var out []Condition
+		for _, entry := range v {
+			conds, err := ParseConditions(entry)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, conds...)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unrecognized showif %v", raw)
+	}
+}
+
+func conditionValues(v interface{}) ([]string, error) {
+	switch x := v.(type) {
+	case string:
+		return splitConditionValues(x), nil
+	case float64, bool:
+		return []string{fmt.Sprint(x)}, nil
+	case []interface{}:
+		out := make([]string, 0, len(x))
+		for _, e := range x {
+			vals, err := conditionValues(e)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, vals...)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unrecognized value %v", v)
+	}
+}
+
+func splitConditionValues(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		out = append(out, strings.TrimSpace(part))
+	}
+	return out
+}
+
+// Visible reports whether item applies given values. A field without a value
+// counts as its schema default, and a field that is itself hidden counts as
+// empty. Rules that cannot be parsed leave the item visible, so a schema the
+// CLI does not understand never hides a field.
+func Visible(item api.ToolParameterItem, items []api.ToolParameterItem, values map[string][]api.MultipartValue) bool {
+	byID := make(map[string]api.ToolParameterItem, len(items))
+	for _, it := range items {
+		byID[it.ID] = it
+	}
+	return visible(item, byID, values, 0)
+}
+
+// maxConditionDepth stops cycles of fields that depend on each other.
+const maxConditionDepth = 8
+
+func visible(item api.ToolParameterItem, byID map[string]api.ToolParameterItem, values map[string][]api.MultipartValue, depth int) bool {
+	conds, err := ParseConditions(item.ShowIf)
+	if err != nil || len(conds) == 0 || depth > maxConditionDepth {
+		return true
+	}
+	for _, cond := range conds {
+		if conditionHolds(cond, byID, values, depth) {
+			return true
+		}
+	}
+	return false
+}
+
+func conditionHolds(cond Condition, byID map[string]api.ToolParameterItem, values map[string][]api.MultipartValue, depth int) bool {
+	fields := make([]string, 0, len(cond))
+	for field := range cond {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		ctrl, known := byID[field]
+		got := ""
+		switch {
+		case known && !visible(ctrl, byID, values, depth+1):
+		case len(values[field]) > 0:
+			got = values[field][0].Value
+		case known:
+			got = exampleString(ctrl.DefaultValue)
+		}
+		got = normalizeConditionValue(ctrl, got)
+		match := false
+		for _, want := range cond[field] {
+			if normalizeConditionValue(ctrl, want) == got {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeConditionValue compares case-insensitively and reads checkboxes
+// as true or false, with an unset box being false.
+func normalizeConditionValue(ctrl api.ToolParameterItem, v string) string {
+	v = strings.ToLower(strings.TrimSpace(v))
+	if strings.EqualFold(strings.TrimSpace(ctrl.Type), "checkbox") {
+		switch v {
+		case "true", "1", "yes", "y", "on":
+			return "true"
+		case "", "false", "0", "no", "n", "off":
+			return "false"
+		}
+	}
+	return v
+}
+
+// VisibleItems filters items to those that apply given values.
+func VisibleItems(items, schema []api.ToolParameterItem, values map[string][]api.MultipartValue) []api.ToolParameterItem {
+	out := make([]api.ToolParameterItem, 0, len(items))
+	for _, item := range items {
+		if Visible(item, schema, values) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
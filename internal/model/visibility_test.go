@@ -0,0 +1,91 @@
+package model
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+func TestParseConditions(t *testing.T) {
+	cases := []struct {
+		raw  interface{}
+		want int
+	}{
+		{nil, 0},
+		{"", 0},
+		{"mode=inpaint", 1},
+		{"mode=inpaint,outpaint", 1},
+		{map[string]interface{}{"mode": []interface{}{"inpaint", "outpaint"}}, 1},
+		{`[{"mode":"inpaint"},{"upscale":true}]`, 2},
+	}
+	for _, tc := range cases {
+		got, err := ParseConditions(tc.raw)
+		if err != nil {
+			t.Fatalf("ParseConditions(%v): %v", tc.raw, err)
+		}
+		if len(got) != tc.want {
+			t.Fatalf("ParseConditions(%v) = %v, want %d conditions", tc.raw, got, tc.want)
+		}
+	}
+	if _, err := ParseConditions("inpaint"); err == nil {
+		t.Fatal("accepted a rule without a field")
+	}
+}
+
+func TestVisible(t *testing.T) {
+	items := []api.ToolParameterItem{
+		{ID: "mode", Type: "select", DefaultValue: "txt2img"},
+		{ID: "mask", Type: "combinefileinput", Required: true, ShowIf: "mode=inpaint,outpaint"},
+		{ID: "feather", Type: "number", ShowIf: map[string]interface{}{"mask": "yes"}},
+		{ID: "hires", Type: "checkbox"},
+		{ID: "hires_steps", Type: "number", ShowIf: "hires=true"},
+		{ID: "odd", Type: "text", ShowIf: 42.0},
+	}
+	vals := func(kv ...string) map[string][]api.MultipartValue {
+		out := map[string][]api.MultipartValue{}
+		for i := 0; i+1 < len(kv); i += 2 {
+			out[kv[i]] = []api.MultipartValue{{Value: kv[i+1]}}
+		}
+		return out
+	}
+	cases := []struct {
+		field  string
+		values map[string][]api.MultipartValue
+		want   bool
+	}{
+		{"mask", nil, false}, // the default mode is txt2img
+		{"mask", vals("mode", "Inpaint"), true},
+		{"mask", vals("mode", "outpaint"), true},
+		{"feather", vals("mode", "txt2img", "mask", "yes"), false}, // mask itself is hidden
+		{"hires_steps", nil, false},
+		{"hires_steps", vals("hires", "1"), true},
+		{"odd", nil, true}, // rules the CLI cannot read never hide a field
+	}
+	for _, tc := range cases {
+		var item api.ToolParameterItem
+		for _, it := range items {
+			if it.ID == tc.field {
+				item = it
+			}
+		}
+		if got := Visible(item, items, tc.values); got != tc.want {
+			t.Errorf("Visible(%s, %v) = %v, want %v", tc.field, tc.values, got, tc.want)
+		}
+	}
+}
+
+func TestCheckInputs_SkipsHiddenRequired(t *testing.T) {
+	items := []api.ToolParameterItem{
+		{ID: "mode", Type: "select", Options: []api.ToolOption{{Value: "txt2img"}, {Value: "inpaint"}}},
+		{ID: "mask", Type: "combinefileinput", Required: true, ShowIf: "mode=inpaint"},
+	}
+	values := map[string][]api.MultipartValue{"mode": {{Value: "txt2img"}}}
+	if _, err := CheckInputs(items, values, items[1:]); err != nil {
+		t.Fatalf("hidden required field rejected: %v", err)
+	}
+	values["mode"] = []api.MultipartValue{{Value: "inpaint"}}
+	if _, err := CheckInputs(items, values, items[1:]); !errors.Is(err, api.ErrInvalidInput) {
+		t.Fatalf("visible required field not reported: %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+// Package notify tells the user a watched task has finished: a desktop
+// notification, a terminal bell, or a user command fed the task as JSON.
+package notify
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/jsonout"
+)
+
+// commandTimeout bounds a --notify-cmd command and the desktop helper.
+var commandTimeout = 30 * time.Second
+
+// Event is a finished task to report.
+type Event struct {
+	Task *api.Task
+	// Model is owner/slug.
+	Model string
+	// Paths are the downloaded outputs, if any.
+	Paths []string
+}
+
+// Title is the notification headline, e.g. "wiro: task 123 succeeded".
+func (e Event) Title() string {
+	return fmt.Sprintf("wiro: task %s %s", e.Task.ID, outcome(e.Task.Status))
+}
+
+// Body is the notification text: the model and what was saved.
+func (e Event) Body() string {
+	parts := []string{}
+	if e.Model != "" {
+		parts = append(parts, e.Model)
+	}
+	if len(e.Paths) > 0 {
+		parts = append(parts, fmt.Sprintf("%d file(s) saved", len(e.Paths)))
+	}
+	if len(parts) == 0 {
+		return e.Task.Status
+	}
+	return strings.Join(parts, ", ")
+}
+
+func outcome(status string) string {
+	switch status {
+	case "task_postprocess_end":
+		return "succeeded"
+	case "task_cancel":
+		return "was cancelled"
+	default:
+		return "failed"
+	}
+}
+
+// Send reports ev through every channel enabled in n. Bell output goes to
+// stderr, as does anything the command prints, so stdout stays parseable. The
+// returned error joins the failures of each channel.
+func Send(n config.Notify, ev Event) error {
+	if ev.Task == nil {
+		return nil
+	}
+	var errs []error
+	if n.Bell {
+		fmt.Fprint(os.Stderr, "\a")
+	}
+	if n.Desktop {
+		if err := run(desktopCommand(runtime.GOOS, ev.Title(), ev.Body()), nil); err != nil {
+			errs = append(errs, fmt.Errorf("desktop notification: %w", err))
+		}
+	}
+	if cmd := strings.TrimSpace(n.Command); cmd != "" {
+		data, err := jsonout.Marshal(ev.Task)
+		if err != nil {
+			return err
+		}
+		c := shellCommand(runtime.GOOS, cmd)
+		c.Env = append(os.Environ(),
+			"WIRO_TASK_ID="+ev.Task.ID,
+			"WIRO_TASK_STATUS="+ev.Task.Status,
+			"WIRO_MODEL="+ev.Model,
+		)
+		if err := run(c, data); err != nil {
+			errs = append(errs, fmt.Errorf("notify command: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// desktopCommand builds the platform's notification helper: osascript on
+// macOS, a PowerShell balloon tip on Windows, and notify-send elsewhere.
+func desktopCommand(goos, title, body string) *exec.Cmd {
+	switch goos {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(body), appleScriptString(title))
+		return exec.Command("osascript", "-e", script)
+	case "windows":
+		// Title and body travel in the environment so no quoting is needed.
+		c := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", windowsBalloon)
+		c.Env = append(os.Environ(), "WIRO_NOTIFY_TITLE="+title, "WIRO_NOTIFY_BODY="+body)
+		return c
+	default:
+		return exec.Command("notify-send", "--app-name=wiro", title, body)
+	}
+}
+
+const windowsBalloon = `Add-Type -AssemblyName System.Windows.Forms
+$n = New-Object System.Windows.Forms.NotifyIcon
+$n.Icon = [System.Drawing.SystemIcons]::Information
+$n.Visible = $true
+$n.ShowBalloonTip(10000, $env:WIRO_NOTIFY_TITLE, $env:WIRO_NOTIFY_BODY, 'Info')
+Start-Sleep -Seconds 5
+$n.Dispose()`
+
+func appleScriptString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// shellCommand runs cmd through the platform shell.
+func shellCommand(goos, cmd string) *exec.Cmd {
+	if goos == "windows" {
+		return exec.Command("cmd", "/C", cmd)
+	}
+	return exec.Command("sh", "-c", cmd)
+}
+
+// run starts c with stdin and waits up to commandTimeout. It takes no context:
+// a notification for a finished task should still go out while the CLI is
+// shutting down after Ctrl-C.
+func run(c *exec.Cmd, stdin []byte) error {
+	c.Stdin = bytes.NewReader(stdin)
+	c.Stdout = os.Stderr
+	c.Stderr = os.Stderr
+	if err := c.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- c.Wait() }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(commandTimeout):
+		_ = c.Process.Kill()
+		<-done
+		return fmt.Errorf("timed out after %s", commandTimeout)
+	}
+}
@@ -0,0 +1,57 @@
+package notify
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/config"
+)
+
+func TestDesktopCommand(t *testing.T) {
+	mac := desktopCommand("darwin", `task "1"`, `a\b`)
+	if got := strings.Join(mac.Args, " "); got != `osascript -e display notification "a\\b" with title "task \"1\""` {
+		t.Fatalf("darwin args = %s", got)
+	}
+	linux := desktopCommand("linux", "title", "body")
+	if got := strings.Join(linux.Args, "|"); got != "notify-send|--app-name=wiro|title|body" {
+		t.Fatalf("linux args = %s", got)
+	}
+	win := desktopCommand("windows", "title", "body")
+	if win.Args[0] != "powershell" || !strings.Contains(strings.Join(win.Env, "\n"), "WIRO_NOTIFY_TITLE=title") {
+		t.Fatalf("windows command = %v", win.Args)
+	}
+}
+
+func TestEventText(t *testing.T) {
+	ev := Event{Task: &api.Task{ID: "9", Status: "task_cancel"}, Model: "wiro/flux", Paths: []string{"a"}}
+	if ev.Title() != "wiro: task 9 was cancelled" || ev.Body() != "wiro/flux, 1 file(s) saved" {
+		t.Fatalf("title=%q body=%q", ev.Title(), ev.Body())
+	}
+}
+
+func TestSendRunsCommandWithTaskJSON(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	out := filepath.Join(t.TempDir(), "out")
+	n := config.Notify{Command: `cat > "` + out + `"; echo "$WIRO_TASK_STATUS" >> "` + out + `"`}
+	err := Send(n, Event{Task: &api.Task{ID: "42", Status: "task_postprocess_end"}, Model: "wiro/flux"})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"id": "42"`) || !strings.HasSuffix(string(data), "task_postprocess_end\n") {
+		t.Fatalf("command saw %s", data)
+	}
+
+	if err := Send(config.Notify{Command: "exit 3"}, Event{Task: &api.Task{ID: "1"}}); err == nil {
+		t.Fatal("a failing command should be reported")
+	}
+}
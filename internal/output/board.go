@@ -0,0 +1,67 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// Board shows one status line per row on stdout. On a terminal the rows are
+// redrawn in place; otherwise each changed row is printed as a new line.
+type Board struct {
+	w   io.Writer
+	tty bool
+
+	mu    sync.Mutex
+	rows  []string
+	drawn int
+}
+
+// NewBoard returns a board with rows initial lines.
+func NewBoard(rows []string) *Board {
+	return newBoard(os.Stdout, stdoutIsTerminal(), rows)
+}
+
+func newBoard(w io.Writer, tty bool, rows []string) *Board {
+	return &Board{w: w, tty: tty, rows: append([]string(nil), rows...)}
+}
+
+// Set replaces row i and shows the change.
+func (b *Board) Set(i int, line string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if i < 0 || i >= len(b.rows) || b.rows[i] == line {
+		return
+	}
+	b.rows[i] = line
+	if !b.tty {
+		fmt.Fprintln(b.w, line)
+		return
+	}
+	b.redraw()
+}
+
+// Draw prints every row; on a terminal it reserves the board's lines.
+func (b *Board) Draw() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tty {
+		b.redraw()
+		return
+	}
+	for _, row := range b.rows {
+		fmt.Fprintln(b.w, row)
+	}
+}
+
+func (b *Board) redraw() {
+	if b.drawn > 0 {
+		// Move back to the board's first line.
+		fmt.Fprintf(b.w, "\x1b[%dA", b.drawn)
+	}
+	for _, row := range b.rows {
+		fmt.Fprintf(b.w, "\r\x1b[2K%s\n", row)
+	}
+	b.drawn = len(b.rows)
+}
@@ -0,0 +1,218 @@
+package output
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+// DedupeStats summarizes what a DedupeIndex saved.
+type DedupeStats struct {
+	Downloaded int   `json:"downloaded"`
+	Reused     int   `json:"reused"`
+	BytesSaved int64 `json:"bytesSaved"`
+	Verified   int   `json:"verified"`
+}
+
+// dedupeEntry is one known file, addressed by its SHA256.
+type dedupeEntry struct {
+	SHA256     string `json:"sha256"`
+	Size       int64  `json:"size"`
+	Path       string `json:"path"`
+	ETag       string `json:"etag,omitempty"`
+	ContentMD5 string `json:"contentMd5,omitempty"`
+}
+
+// DedupeIndex is a local content index of downloaded outputs. Before fetching an
+// output, its ETag/Content-MD5 (from a HEAD request) is looked up; a matching
+// local file whose SHA256 still checks out is linked or copied instead.
+type DedupeIndex struct {
+	path    string
+	mu      sync.Mutex
+	entries []dedupeEntry
+	stats   DedupeStats
+}
+
+// OpenDedupeIndex loads the index at path, starting empty when it is missing or unreadable.
+func OpenDedupeIndex(path string) *DedupeIndex {
+	idx := &DedupeIndex{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return idx
+	}
+	var doc struct {
+		Files []dedupeEntry `json:"files"`
+	}
+	// A corrupt index is only a cache; start over rather than failing downloads.
+	if json.Unmarshal(data, &doc) == nil {
+		idx.entries = doc.Files
+	}
+	return idx
+}
+
+// Stats returns counters accumulated since the index was opened.
+func (d *DedupeIndex) Stats() DedupeStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.stats
+}
+
+// Save writes the index atomically, dropping entries whose files are gone.
+func (d *DedupeIndex) Save() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	kept := d.entries[:0]
+	for _, e := range d.entries {
+		if _, err := os.Stat(longPath(e.Path)); err == nil {
+			kept = append(kept, e)
+		}
+	}
+	d.entries = kept
+	data, err := json.MarshalIndent(map[string]interface{}{"files": d.entries}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal dedupe index: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(d.path), 0o755); err != nil {
+		return fmt.Errorf("create dedupe index dir: %w", err)
+	}
+	tmpPath := d.path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("write tmp dedupe index: %w", err)
+	}
+	if err := os.Rename(tmpPath, d.path); err != nil {
+		return fmt.Errorf("rename tmp dedupe index: %w", err)
+	}
+	return nil
+}
+
+// reuse places a known identical file at target. It reports false whenever the
+// remote file cannot be matched, and the caller then downloads normally.
+func (d *DedupeIndex) reuse(ctx context.Context, client *api.Client, fileURL string, headers map[string]string, target string) bool {
+	size, etag, contentMD5, err := client.HeadDigest(ctx, fileURL, headers)
+	if err != nil {
+		return false
+	}
+	etag = strongETag(etag)
+	if etag == "" && contentMD5 == "" {
+		return false
+	}
+	d.mu.Lock()
+	var candidates []dedupeEntry
+	for _, e := range d.entries {
+		if (etag != "" && e.ETag == etag) || (contentMD5 != "" && e.ContentMD5 == contentMD5) {
+			if size < 0 || e.Size == size {
+				candidates = append(candidates, e)
+			}
+		}
+	}
+	d.mu.Unlock()
+
+	for _, e := range candidates {
+		sum, _, n, err := digestFile(e.Path)
+		if err != nil || sum != e.SHA256 || n != e.Size {
+			continue
+		}
+		if err := linkOrCopy(e.Path, target); err != nil {
+			continue
+		}
+		d.mu.Lock()
+		d.stats.Reused++
+		d.stats.BytesSaved += e.Size
+		d.entries = append(d.entries, dedupeEntry{SHA256: e.SHA256, Size: e.Size, Path: target, ETag: etag, ContentMD5: contentMD5})
+		d.mu.Unlock()
+		return true
+	}
+	return false
+}
+
+// record hashes a freshly downloaded file, checks it against Content-MD5 when
+// the header has one (downloadFile drops it for resumed downloads), and adds
+// it to the index.
+func (d *DedupeIndex) record(target string, header http.Header) error {
+	sum, md5sum, size, err := digestFile(target)
+	if err != nil {
+		return err
+	}
+	contentMD5 := header.Get("Content-MD5")
+	if contentMD5 != "" {
+		if contentMD5 != md5sum {
+			_ = os.Remove(longPath(target))
+			return fmt.Errorf("checksum mismatch for %s: Content-MD5 %s, got %s", target, contentMD5, md5sum)
+		}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stats.Downloaded++
+	if contentMD5 != "" {
+		d.stats.Verified++
+	}
+	d.entries = append(d.entries, dedupeEntry{SHA256: sum, Size: size, Path: target, ETag: strongETag(header.Get("ETag")), ContentMD5: md5sum})
+	return nil
+}
+
+// digestFile returns the hex SHA256, base64 MD5 (Content-MD5 form), and size of path.
+func digestFile(path string) (string, string, int64, error) {
+	f, err := os.Open(longPath(path))
+	if err != nil {
+		return "", "", 0, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+	sh, mh := sha256.New(), md5.New()
+	n, err := io.Copy(io.MultiWriter(sh, mh), f)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("hash %s: %w", path, err)
+	}
+	return hex.EncodeToString(sh.Sum(nil)), base64.StdEncoding.EncodeToString(mh.Sum(nil)), n, nil
+}
+
+// strongETag drops weak validators, which do not promise byte-identical content.
+func strongETag(etag string) string {
+	etag = strings.TrimSpace(etag)
+	if strings.HasPrefix(etag, "W/") {
+		return ""
+	}
+	return etag
+}
+
+// linkOrCopy hard-links src to dst, falling back to a copy across filesystems.
+func linkOrCopy(src, dst string) error {
+	if err := os.Link(longPath(src), longPath(dst)); err == nil {
+		return nil
+	}
+	in, err := os.Open(longPath(src))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	tmpPath := dst + tempSuffix
+	out, err := os.Create(longPath(tmpPath))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		_ = os.Remove(longPath(tmpPath))
+		return err
+	}
+	if err := out.Close(); err != nil {
+		_ = os.Remove(longPath(tmpPath))
+		return err
+	}
+	if err := os.Rename(longPath(tmpPath), longPath(dst)); err != nil {
+		_ = os.Remove(longPath(tmpPath))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package output
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+func TestDownloadOutputs_DedupeReusesIdenticalFiles(t *testing.T) {
+	body := []byte("watermark-bytes")
+	sum := md5.Sum(body)
+	contentMD5 := base64.StdEncoding.EncodeToString(sum[:])
+	gets := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"abc"`)
+		w.Header().Set("Content-MD5", contentMD5)
+		if r.Method == http.MethodGet {
+			gets++
+			_, _ = w.Write(body)
+			return
+		}
+		w.Header().Set("Content-Length", "15")
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	index := OpenDedupeIndex(filepath.Join(dir, "index.json"))
+	opts := DownloadOptions{Dedupe: index}
+	first := &api.Task{ID: "1", Outputs: []api.TaskOutput{{URL: srv.URL + "/a/logo.png"}}}
+	second := &api.Task{ID: "2", Outputs: []api.TaskOutput{{URL: srv.URL + "/b/logo.png"}}}
+	if _, err := DownloadOutputs(context.Background(), first, dir, "x", opts); err != nil {
+		t.Fatalf("first download: %v", err)
+	}
+	if err := index.Save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	// A fresh index loaded from disk must still recognize the file.
+	opts.Dedupe = OpenDedupeIndex(filepath.Join(dir, "index.json"))
+	paths, err := DownloadOutputs(context.Background(), second, dir, "x", opts)
+	if err != nil {
+		t.Fatalf("second download: %v", err)
+	}
+	if gets != 1 {
+		t.Fatalf("expected a single GET, got %d", gets)
+	}
+	data, err := os.ReadFile(paths[0])
+	if err != nil || string(data) != string(body) {
+		t.Fatalf("reused file content = %q, %v", data, err)
+	}
+	if st := opts.Dedupe.Stats(); st.Reused != 1 || st.BytesSaved != int64(len(body)) {
+		t.Fatalf("unexpected stats %+v", st)
+	}
+	if st := index.Stats(); st.Downloaded != 1 || st.Verified != 1 {
+		t.Fatalf("unexpected first-run stats %+v", st)
+	}
+}
+
+func TestDownloadOutputs_DedupeRejectsChecksumMismatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-MD5", "AAAAAAAAAAAAAAAAAAAAAA==")
+		_, _ = w.Write([]byte("corrupt"))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	task := &api.Task{ID: "1", Outputs: []api.TaskOutput{{URL: srv.URL + "/a.png"}}}
+	_, err := DownloadOutputs(context.Background(), task, dir, "x", DownloadOptions{Dedupe: OpenDedupeIndex(filepath.Join(dir, "i.json"))})
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected checksum mismatch, got %v", err)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(dir, "1", "*.png")); len(matches) != 0 {
+		t.Fatalf("corrupt file should be removed, found %v", matches)
+	}
+}
+
+func TestDownloadOutputs_DedupeResumeSkipsRangeDigest(t *testing.T) {
+	content := []byte("image-bytes")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			return
+		}
+		if r.Header.Get("Range") != "bytes=6-" {
+			t.Errorf("Range = %q, want bytes=6-", r.Header.Get("Range"))
+		}
+		// A 206 Content-MD5 is the digest of the range, not of the file.
+		sum := md5.Sum(content[6:])
+		w.Header().Set("Content-MD5", base64.StdEncoding.EncodeToString(sum[:]))
+		w.Header().Set("Content-Range", "bytes 6-10/11")
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write(content[6:])
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "1"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "1", "x-1.png"+tempSuffix), content[:6], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	index := OpenDedupeIndex(filepath.Join(dir, "i.json"))
+	task := &api.Task{ID: "1", Outputs: []api.TaskOutput{{URL: srv.URL + "/a.png"}}}
+	paths, err := DownloadOutputs(context.Background(), task, dir, "x", DownloadOptions{Dedupe: index})
+	if err != nil {
+		t.Fatalf("resumed download: %v", err)
+	}
+	if data, _ := os.ReadFile(paths[0]); string(data) != string(content) {
+		t.Fatalf("content = %q", data)
+	}
+	if st := index.Stats(); st.Downloaded != 1 || st.Verified != 0 {
+		t.Fatalf("stats = %+v, want one unverified download", st)
+	}
+}
@@ -0,0 +1,70 @@
+package output
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// lockFileName guards a task folder while one process downloads into it.
+const lockFileName = ".wiro-download.lock"
+
+var (
+	// lockRefreshInterval is how often the holder touches the lock file.
+	lockRefreshInterval = 30 * time.Second
+	// lockStaleAfter treats a lock that has not been touched as abandoned.
+	lockStaleAfter = 2 * time.Minute
+	// lockPollInterval is how often a waiting process retries.
+	lockPollInterval = 500 * time.Millisecond
+)
+
+// lockTaskDir takes the per-task download lock, waiting while another live process holds it.
+// The returned release func stops the refresher and removes the lock file.
+func lockTaskDir(ctx context.Context, dir string) (func(), error) {
+	path := longPath(filepath.Join(dir, lockFileName))
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_, _ = f.WriteString(strconv.Itoa(os.Getpid()) + "\n")
+			_ = f.Close()
+			return startLockRefresh(path), nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("create download lock: %w", err)
+		}
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > lockStaleAfter {
+			_ = os.Remove(path)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for another process downloading into %s: %w", dir, ctx.Err())
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+func startLockRefresh(path string) func() {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(lockRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				now := time.Now()
+				_ = os.Chtimes(path, now, now)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		_ = os.Remove(path)
+	}
+}
@@ -0,0 +1,126 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/gitinfo"
+)
+
+const manifestFilename = "manifest.json"
+
+// Manifest is the machine-readable record written next to downloaded outputs.
+type Manifest struct {
+	TaskID     string         `json:"taskId"`
+	Model      string         `json:"model"`
+	Status     string         `json:"status"`
+	InputsHash string         `json:"inputsHash"`
+	CreatedAt  string         `json:"createdAt"`
+	Files      []ManifestFile `json:"files"`
+	// Seed is the value of the model's seed field, when the run set one.
+	Seed string `json:"seed,omitempty"`
+	// Git is the code state the run was started from (--git-context).
+	Git *gitinfo.Info `json:"git,omitempty"`
+}
+
+// ManifestFile describes one downloaded output.
+type ManifestFile struct {
+	Name        string `json:"name"`
+	Path        string `json:"path"`
+	URL         string `json:"url"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+}
+
+// BuildManifest describes the files returned by DownloadOutputFiles.
+func BuildManifest(task *api.Task, model, inputsHash string, files []DownloadedFile) Manifest {
+	m := Manifest{
+		Model:      model,
+		InputsHash: inputsHash,
+		CreatedAt:  time.Now().UTC().Format(time.RFC3339),
+		Files:      make([]ManifestFile, 0, len(files)),
+	}
+	if task != nil {
+		m.TaskID = task.ID
+		m.Status = task.Status
+	}
+	for _, file := range files {
+		f := ManifestFile{
+			Name:        filepath.Base(file.Path),
+			Path:        file.Path,
+			URL:         file.Output.URL,
+			ContentType: file.Output.ContentType,
+		}
+		if st, err := os.Stat(file.Path); err == nil {
+			f.Size = st.Size()
+		}
+		m.Files = append(m.Files, f)
+	}
+	return m
+}
+
+// WriteManifest stores the manifest inside dir, named by naming, and returns its path.
+func WriteManifest(dir string, naming NameTemplate, m Manifest) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create output dir: %w", err)
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshal manifest: %w", err)
+	}
+	path := filepath.Join(dir, naming.ManifestName(m.TaskID))
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return "", fmt.Errorf("write tmp manifest: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return "", fmt.Errorf("rename tmp manifest: %w", err)
+	}
+	return path, nil
+}
+
+// HashInputs returns a stable SHA256 over field ids, values, and file contents.
+func HashInputs(values map[string][]api.MultipartValue) (string, error) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	for _, k := range keys {
+		for _, v := range values[k] {
+			fmt.Fprintf(h, "%s\x00", k)
+			if v.FilePath == "" {
+				fmt.Fprintf(h, "value\x00%s\x00", v.Value)
+				continue
+			}
+			fmt.Fprintf(h, "file\x00")
+			if err := hashFile(h, v.FilePath); err != nil {
+				return "", err
+			}
+			h.Write([]byte{0})
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open file %q: %w", path, err)
+	}
+	defer f.Close()
+	if _, err := io.Copy(w, f); err != nil {
+		return fmt.Errorf("hash file %q: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+// DefaultNameTemplate is the built-in layout: one directory per task, files
+// named after the first two words of the prompt and their position.
+const DefaultNameTemplate = "{taskid}/{prompt:2}-{index}{ext}"
+
+// maxPromptWords caps {prompt:N}.
+const maxPromptWords = 12
+
+// namePlaceholders are the template placeholders and whether they may appear
+// in directory segments; {index} and {ext} differ per file.
+var namePlaceholders = map[string]bool{
+	"owner":     true,
+	"model":     true,
+	"taskid":    true,
+	"date":      true,
+	"time":      true,
+	"prompt":    true,
+	"workspace": true,
+	"index":     false,
+	"ext":       false,
+}
+
+// placeholderNames lists the placeholders for error messages.
+const placeholderNames = "{owner}, {model}, {taskid}, {date}, {time}, {prompt}, {prompt:N}, {workspace}, {index}, {ext}"
+
+var placeholderPattern = regexp.MustCompile(`\{([a-z]+)(?::([0-9]+))?\}`)
+
+// NameTemplate lays out downloaded outputs below the output directory. Its
+// last "/"-separated segment names each file and earlier segments are
+// directories, so "{model}/{date}/{taskid}-{index}{ext}" groups files by model
+// and day. The zero value is DefaultNameTemplate.
+type NameTemplate struct {
+	raw  string
+	dirs []string
+	file string
+	// workspace fills {workspace}; see WithWorkspace.
+	workspace string
+}
+
+// ParseNameTemplate checks s and returns its template; empty s is the default.
+// The file segment must contain {index} and the template {taskid}, so outputs
+// never overwrite each other and an interrupted download resumes into the
+// files it started.
+func ParseNameTemplate(s string) (NameTemplate, error) {
+	s = strings.TrimSpace(strings.ReplaceAll(s, `\`, "/"))
+	if s == "" {
+		s = DefaultNameTemplate
+	}
+	if strings.HasPrefix(s, "/") {
+		return NameTemplate{}, fmt.Errorf("name template %q must be relative to the output directory", s)
+	}
+	segments := strings.Split(s, "/")
+	t := NameTemplate{raw: s, dirs: segments[:len(segments)-1], file: segments[len(segments)-1]}
+	hasTaskID := false
+	for i, seg := range segments {
+		if seg == "" || seg == "." || seg == ".." {
+			return NameTemplate{}, fmt.Errorf("name template %q has an empty, \".\", or \"..\" path segment", s)
+		}
+		isFile := i == len(segments)-1
+		for _, m := range placeholderPattern.FindAllStringSubmatch(seg, -1) {
+			inDir, known := namePlaceholders[m[1]]
+			switch {
+			case !known:
+				return NameTemplate{}, fmt.Errorf("unknown placeholder {%s} in name template (use %s)", m[1], placeholderNames)
+			case !inDir && !isFile:
+				return NameTemplate{}, fmt.Errorf("{%s} can only be used in the file name, not in directories", m[1])
+			case m[2] != "" && m[1] != "prompt":
+				return NameTemplate{}, fmt.Errorf("{%s} takes no argument", m[1])
+			case m[2] != "":
+				if n, err := strconv.Atoi(m[2]); err != nil || n < 1 || n > maxPromptWords {
+					return NameTemplate{}, fmt.Errorf("{prompt:%s}: word count must be 1-%d", m[2], maxPromptWords)
+				}
+			}
+			hasTaskID = hasTaskID || m[1] == "taskid"
+		}
+		if strings.ContainsAny(placeholderPattern.ReplaceAllString(seg, ""), "{}") {
+			return NameTemplate{}, fmt.Errorf("name template %q has an unmatched brace", s)
+		}
+	}
+	if !strings.Contains(t.file, "{index}") {
+		return NameTemplate{}, errors.New("name template file name must include {index}")
+	}
+	if !hasTaskID {
+		return NameTemplate{}, errors.New("name template must include {taskid} so different tasks never share a file")
+	}
+	return t, nil
+}
+
+// WithWorkspace returns t with {workspace} expanding to name, the workspace
+// the run belongs to.
+func (t NameTemplate) WithWorkspace(name string) NameTemplate {
+	t.workspace = name
+	return t
+}
+
+// String returns the template text.
+func (t NameTemplate) String() string {
+	return t.orDefault().raw
+}
+
+// PerTask reports whether every task gets its own directory, which then holds
+// manifest.json; otherwise manifests are named <taskid>.manifest.json.
+func (t NameTemplate) PerTask() bool {
+	for _, seg := range t.orDefault().dirs {
+		if strings.Contains(seg, "{taskid}") {
+			return true
+		}
+	}
+	return false
+}
+
+// Dir is the directory the task's outputs are written to.
+func (t NameTemplate) Dir(outputDir string, task *api.Task) string {
+	t = t.orDefault()
+	v := t.vars(task, "")
+	parts := []string{outputDir}
+	for _, seg := range t.dirs {
+		parts = append(parts, v.expand(seg, api.TaskOutput{}, 0))
+	}
+	return filepath.Join(parts...)
+}
+
+// ManifestName is the manifest's file name inside Dir.
+func (t NameTemplate) ManifestName(taskID string) string {
+	if t.PerTask() {
+		return manifestFilename
+	}
+	return SafeName(taskID + "." + manifestFilename)
+}
+
+// filename names output index (1-based) of task.
+func (t NameTemplate) filename(task *api.Task, prompt string, out api.TaskOutput, index int) string {
+	return t.vars(task, prompt).expand(t.orDefault().file, out, index)
+}
+
+func (t NameTemplate) orDefault() NameTemplate {
+	if t.raw == "" {
+		def, _ := ParseNameTemplate(DefaultNameTemplate)
+		return def.WithWorkspace(t.workspace)
+	}
+	return t
+}
+
+type nameVars struct {
+	owner, model, taskID, prompt, workspace string
+	at                                      time.Time
+}
+
+func (t NameTemplate) vars(task *api.Task, prompt string) nameVars {
+	v := nameVars{prompt: prompt, workspace: t.workspace, at: time.Now()}
+	if task != nil {
+		v.owner, v.model, v.taskID = task.SlugOwner, task.SlugProject, task.ID
+		// The task's creation time keeps {date} stable when a download is retried later.
+		if at, ok := parseCreateTime(task.CreateTime); ok {
+			v.at = at
+		}
+	}
+	return v
+}
+
+// expand fills the placeholders of one path segment and makes it a safe name.
+func (v nameVars) expand(seg string, out api.TaskOutput, index int) string {
+	if index < 1 {
+		index = 1
+	}
+	name := placeholderPattern.ReplaceAllStringFunc(seg, func(m string) string {
+		sub := placeholderPattern.FindStringSubmatch(m)
+		switch sub[1] {
+		case "owner":
+			return orUnknown(v.owner)
+		case "model":
+			return orUnknown(v.model)
+		case "taskid":
+			return orUnknown(v.taskID)
+		case "workspace":
+			return orUnknown(v.workspace)
+		case "date":
+			return v.at.Local().Format("2006-01-02")
+		case "time":
+			return v.at.Local().Format("150405")
+		case "prompt":
+			words := 2
+			if sub[2] != "" {
+				words, _ = strconv.Atoi(sub[2])
+			}
+			if slug := promptSlug(v.prompt, words); slug != "" {
+				return slug
+			}
+			return "output"
+		case "index":
+			return strconv.Itoa(index)
+		case "ext":
+			return outputExt(out)
+		}
+		return m
+	})
+	return sanitizeFilename(name, runtime.GOOS)
+}
+
+func orUnknown(s string) string {
+	if s = strings.TrimSpace(s); s != "" {
+		return s
+	}
+	return "unknown"
+}
+
+// parseCreateTime reads a task time in unix seconds or RFC 3339.
+func parseCreateTime(v string) (time.Time, bool) {
+	v = strings.TrimSpace(v)
+	if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+		return time.Unix(n, 0), true
+	}
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
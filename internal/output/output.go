@@ -1,19 +1,28 @@
 package output
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"unicode"
 
 	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/log"
+	"github.com/wiro-ai/wiro-cli/internal/metrics"
+	"github.com/wiro-ai/wiro-cli/internal/model"
+	"github.com/wiro-ai/wiro-cli/internal/telemetry"
+	"github.com/wiro-ai/wiro-cli/internal/term"
 )
 
 func PrintJSON(v interface{}) error {
@@ -24,105 +33,471 @@ func PrintJSON(v interface{}) error {
 
 func PrintErrors(errors []api.APIError) {
 	for _, e := range errors {
-		fmt.Fprintf(os.Stderr, "error: %s (code=%v)\n", e.Message, e.Code)
+		fmt.Fprintf(os.Stderr, "error: %s (code=%s)\n", e.Message, e.CodeString())
 	}
 }
 
 func PrintProjects(projects []api.Project) {
-	fmt.Println("PROJECTS")
+	t := NewTable("NAME", "API KEY", "AUTH", "REQUESTS")
 	for _, p := range projects {
-		fmt.Printf("- %s (%s) auth=%s requests=%s\n", p.Name, p.APIKey, p.AuthMethod, p.RequestCount)
+		t.Row(p.Name, p.APIKey, Dash(p.AuthMethod), Dash(p.RequestCount))
 	}
+	_ = t.Print()
 }
 
 func PrintTools(tools []api.ToolSummary) {
-	for _, t := range tools {
-		fmt.Printf("- %s/%s\t%s\n", t.SlugOwner, t.SlugProject, compact(t.Description, 110))
+	t := NewTable("MODEL", "DESCRIPTION")
+	t.StyleColumn("DESCRIPTION", std.Dim)
+	for _, tool := range tools {
+		t.Row(tool.SlugOwner+"/"+tool.SlugProject, clip(tool.Description, 110))
 	}
+	_ = t.Print()
+}
+
+// PrintTerms prints a titled list of categories or tags with model counts.
+func PrintTerms(title string, terms []model.TermCount) {
+	if len(terms) == 0 {
+		return
+	}
+	t := NewTable(strings.ToUpper(title), "MODELS")
+	for _, term := range terms {
+		t.Row(term.Name, fmt.Sprint(term.Count))
+	}
+	_ = t.Print()
 }
 
 func PrintToolDetail(tool *api.ToolDetail) {
-	fmt.Printf("Model: %s/%s\n", tool.SlugOwner, tool.SlugProject)
-	fmt.Printf("Description: %s\n", compact(tool.Description, 220))
-	fmt.Println("Inputs:")
+	Printf("Model: %s/%s\n", tool.SlugOwner, tool.SlugProject)
+	printWrapped("Description: ", tool.Description, "  ")
+	Println("Inputs:")
 	for _, group := range tool.Parameters {
 		for _, item := range group.Items {
 			adv := "quick"
 			if item.Advanced {
 				adv = "advanced"
 			}
-			fmt.Printf("- %s (%s, %s, required=%v)\n", item.ID, item.Type, adv, item.Required)
+			Printf("- %s (%s, %s, required=%v)\n", item.ID, item.Type, adv, item.Required)
+			if note := strings.TrimSpace(item.Note); note != "" {
+				printWrapped("    ", note, "    ")
+			}
+			for _, opt := range item.Options {
+				if cover := strings.TrimSpace(opt.Cover); cover != "" {
+					Printf("    %s: %s\n", optionLabel(opt), coverLink(cover))
+				}
+			}
 		}
 	}
 }
 
+// optionLabel names a select option by its text and value.
+func optionLabel(opt api.ToolOption) string {
+	val := fmt.Sprint(opt.Value)
+	text := strings.TrimSpace(opt.Text)
+	if text == "" || text == val {
+		return val
+	}
+	return text + " (" + val + ")"
+}
+
+// coverLink shows a cover image as a clickable "cover" on terminals that
+// render OSC 8 links, and as the bare URL elsewhere.
+func coverLink(url string) string {
+	if stdoutIsTerminal() && term.HyperlinksSupported() {
+		return term.Hyperlink(url, "cover")
+	}
+	return url
+}
+
 func PrintTask(task *api.Task) {
-	fmt.Printf("Task ID: %s\n", task.ID)
-	fmt.Printf("Status: %s\n", task.Status)
-	fmt.Printf("Created: %s\n", task.CreateTime)
+	Printf("Task ID: %s\n", task.ID)
+	Printf("Status: %s\n", std.Status(task.Status))
+	Printf("Created: %s\n", task.CreateTime)
+	if task.Priority != "" {
+		Printf("Priority: %s\n", task.Priority)
+	}
+	if hw := Hardware(task); hw != "" {
+		Printf("Hardware: %s\n", hw)
+	}
 	if len(task.Outputs) > 0 {
-		fmt.Println("Outputs:")
+		Println("Outputs:")
 		for _, o := range task.Outputs {
-			fmt.Printf("- %s\n", o.URL)
+			Printf("- %s\n", o.URL)
 		}
 	}
 	if strings.TrimSpace(task.DebugError) != "" {
-		fmt.Printf("DebugError: %s\n", compact(task.DebugError, 400))
+		Printf("DebugError: %s\n", clip(task.DebugError, 400))
+	}
+}
+
+// Hardware describes the GPU and worker that ran task, or "" when the API did not say.
+func Hardware(task *api.Task) string {
+	gpu := strings.TrimSpace(task.GPUType)
+	worker := strings.TrimSpace(task.WorkerID)
+	switch {
+	case gpu != "" && worker != "":
+		return gpu + " (worker " + worker + ")"
+	case gpu != "":
+		return gpu
+	case worker != "":
+		return "worker " + worker
 	}
+	return ""
 }
 
+// compact trims v and cuts it to n columns, ending in "..." when cut.
 func compact(v string, n int) string {
-	v = strings.TrimSpace(v)
-	if len(v) <= n {
-		return v
+	return term.Truncate(strings.TrimSpace(v), n, "...")
+}
+
+// DownloadOptions configures how outputs are fetched.
+type DownloadOptions struct {
+	// Client performs the requests; a default client is used when nil.
+	Client *api.Client
+	// Headers are auth headers forwarded to Wiro-hosted output URLs.
+	Headers map[string]string
+	// OnProgress, when set, is called as each output file is written.
+	OnProgress func(DownloadProgress)
+	// Filter limits which outputs are fetched; the zero value fetches all.
+	Filter OutputFilter
+	// Overwrite downloads again even when a complete file is already present.
+	Overwrite bool
+	// Dedupe, when set, reuses identical files already downloaded elsewhere and
+	// verifies Content-MD5 of new downloads.
+	Dedupe *DedupeIndex
+	// Naming lays out the files; the zero value is DefaultNameTemplate.
+	Naming NameTemplate
+}
+
+// OutputFilter selects outputs by position and/or name. When both are set an
+// output must match one index and one pattern.
+type OutputFilter struct {
+	// Indexes are 1-based output positions.
+	Indexes []int
+	// Patterns are shell globs matched case-insensitively against the output name.
+	Patterns []string
+}
+
+// IsZero reports whether the filter selects every output.
+func (f OutputFilter) IsZero() bool {
+	return len(f.Indexes) == 0 && len(f.Patterns) == 0
+}
+
+// Validate rejects non-positive indexes and malformed patterns.
+func (f OutputFilter) Validate() error {
+	for _, i := range f.Indexes {
+		if i < 1 {
+			return fmt.Errorf("invalid output index %d (indexes start at 1)", i)
+		}
 	}
-	if n <= 3 {
-		return v[:n]
+	for _, p := range f.Patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid output pattern %q: %w", p, err)
+		}
 	}
-	return v[:n-3] + "..."
+	return nil
 }
 
-// DownloadOutputs downloads task output URLs into outputDir/taskID.
-// Files are named with prompt-based slug for easier browsing.
-func DownloadOutputs(task *api.Task, outputDir, prompt string) ([]string, error) {
+// Match reports whether the output at 1-based index is selected.
+func (f OutputFilter) Match(index int, out api.TaskOutput) bool {
+	if len(f.Indexes) > 0 {
+		found := false
+		for _, i := range f.Indexes {
+			if i == index {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.Patterns) == 0 {
+		return true
+	}
+	name := strings.ToLower(outputName(out))
+	for _, p := range f.Patterns {
+		if ok, _ := path.Match(strings.ToLower(p), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// DownloadProgress describes the state of one output file download.
+type DownloadProgress struct {
+	// Index is 1-based within Count outputs.
+	Index int
+	Count int
+	URL   string
+	Path  string
+	// Bytes written so far; Size is -1 when the server sent no length.
+	Bytes int64
+	Size  int64
+	Done  bool
+}
+
+// DownloadOutputs downloads task output URLs into outputDir, laid out by
+// opts.Naming (by default outputDir/taskID, files named with a prompt slug).
+func DownloadOutputs(ctx context.Context, task *api.Task, outputDir, prompt string, opts DownloadOptions) ([]string, error) {
+	files, err := DownloadOutputFiles(ctx, task, outputDir, prompt, opts)
+	return DownloadedPaths(files), err
+}
+
+// DownloadedFile is one output saved by DownloadOutputFiles.
+type DownloadedFile struct {
+	Path string
+	// Index is the output's 1-based position in the task, which a filter
+	// may leave gaps in.
+	Index  int
+	Output api.TaskOutput
+}
+
+// DownloadedPaths lists the paths of files.
+func DownloadedPaths(files []DownloadedFile) []string {
+	paths := make([]string, 0, len(files))
+	for _, f := range files {
+		paths = append(paths, f.Path)
+	}
+	return paths
+}
+
+// DownloadOutputFiles is DownloadOutputs, reporting which output each path holds.
+func DownloadOutputFiles(ctx context.Context, task *api.Task, outputDir, prompt string, opts DownloadOptions) ([]DownloadedFile, error) {
 	if task == nil || len(task.Outputs) == 0 {
 		return nil, nil
 	}
-	base := filepath.Join(outputDir, task.ID)
-	if err := os.MkdirAll(base, 0o755); err != nil {
+	base := opts.Naming.Dir(outputDir, task)
+	if err := os.MkdirAll(longPath(base), 0o755); err != nil {
 		return nil, fmt.Errorf("create output dir: %w", err)
 	}
-	paths := make([]string, 0, len(task.Outputs))
+	// Another process watching the same task may be writing here; wait for it
+	// and reuse whatever it finished instead of downloading twice.
+	release, err := lockTaskDir(ctx, base)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	// A directory shared by several tasks may hold their partial downloads too.
+	if opts.Naming.PerTask() {
+		parts := make(map[string]bool, len(task.Outputs))
+		for idx, out := range task.Outputs {
+			parts[opts.Naming.filename(task, prompt, out, idx+1)+tempSuffix] = true
+		}
+		removeOrphanTempFiles(base, parts)
+	}
+	files := make([]DownloadedFile, 0, len(task.Outputs))
+
+	if !opts.Filter.IsZero() {
+		matched := 0
+		for idx, out := range task.Outputs {
+			if opts.Filter.Match(idx+1, out) {
+				matched++
+			}
+		}
+		if matched == 0 {
+			return nil, fmt.Errorf("no outputs match the selection (task has %d outputs; see `wiro task outputs %s`)", len(task.Outputs), task.ID)
+		}
+	}
 
 	for idx, out := range task.Outputs {
-		filename := outputFilename(out, prompt, idx+1)
+		if !opts.Filter.Match(idx+1, out) {
+			continue
+		}
+		// Names keep the original index so selective and full downloads agree.
+		filename := opts.Naming.filename(task, prompt, out, idx+1)
 		target := filepath.Join(base, filename)
-		if err := downloadFile(out.URL, target); err != nil {
-			return paths, err
+		progress := DownloadProgress{Index: idx + 1, Count: len(task.Outputs), URL: out.URL, Path: target}
+		if info, err := os.Stat(longPath(target)); err == nil && info.Size() > 0 && !opts.Overwrite {
+			files = append(files, DownloadedFile{Path: target, Index: idx + 1, Output: out})
+			if opts.OnProgress != nil {
+				progress.Bytes, progress.Size, progress.Done = info.Size(), info.Size(), true
+				opts.OnProgress(progress)
+			}
+			continue
+		}
+		var onBytes api.ProgressFunc
+		if opts.OnProgress != nil {
+			onBytes = func(done, total int64) {
+				progress.Bytes, progress.Size = done, total
+				opts.OnProgress(progress)
+			}
+		}
+		if opts.Dedupe != nil && opts.Dedupe.reuse(ctx, downloadClient(opts), out.URL, opts.Headers, target) {
+			files = append(files, DownloadedFile{Path: target, Index: idx + 1, Output: out})
+			if opts.OnProgress != nil {
+				progress.Done = true
+				opts.OnProgress(progress)
+			}
+			continue
+		}
+		header, err := downloadFile(ctx, opts, out.URL, target, onBytes)
+		if err != nil {
+			return files, err
+		}
+		if opts.Dedupe != nil {
+			if err := opts.Dedupe.record(target, header); err != nil {
+				return files, err
+			}
+		}
+		files = append(files, DownloadedFile{Path: target, Index: idx + 1, Output: out})
+		if opts.OnProgress != nil {
+			progress.Done = true
+			opts.OnProgress(progress)
 		}
-		paths = append(paths, target)
 	}
-	return paths, nil
+	return files, nil
+}
+
+func downloadClient(opts DownloadOptions) *api.Client {
+	if opts.Client == nil {
+		return api.NewClient("")
+	}
+	return opts.Client
 }
 
-func downloadFile(fileURL, targetPath string) error {
-	resp, err := http.Get(fileURL)
+// downloadFile fetches fileURL into targetPath and returns the response headers.
+// It writes to targetPath.part, fsyncs, and renames, so an interrupted download
+// never looks complete; a .part left by an earlier attempt is resumed with a
+// range request, or replaced when the server sends the whole file again. A
+// resumed download's headers carry no Content-MD5, since a 206 digest covers
+// only the range that was sent.
+func downloadFile(ctx context.Context, opts DownloadOptions, fileURL, targetPath string, onBytes api.ProgressFunc) (header http.Header, err error) {
+	ctx, span := telemetry.StartSpan(ctx, "download", telemetry.KindClient, telemetry.String("wiro.download.file", filepath.Base(targetPath)))
+	if u, perr := url.Parse(fileURL); perr == nil {
+		span.SetAttributes(telemetry.String("server.address", u.Hostname()))
+	}
+	defer func() {
+		if err != nil {
+			metrics.Downloads.Inc("error")
+		} else {
+			metrics.Downloads.Inc("ok")
+		}
+		span.End(err)
+	}()
+	partPath := targetPath + tempSuffix
+	var offset int64
+	if info, err := os.Stat(longPath(partPath)); err == nil && info.Mode().IsRegular() {
+		offset = info.Size()
+	}
+	resp, err := downloadClient(opts).DownloadFrom(ctx, fileURL, opts.Headers, offset)
+	var apiErr *api.Error
+	if offset > 0 && errors.As(err, &apiErr) && apiErr.Status == http.StatusRequestedRangeNotSatisfiable {
+		// The part no longer matches the remote file; start over.
+		log.Debugf("download %s: range %d not satisfiable, restarting", targetPath, offset)
+		offset = 0
+		resp, err = downloadClient(opts).DownloadFrom(ctx, fileURL, opts.Headers, 0)
+	}
 	if err != nil {
-		return fmt.Errorf("download %s: %w", fileURL, err)
+		return nil, fmt.Errorf("download %s: %w", fileURL, err)
 	}
 	defer resp.Body.Close()
-	if resp.StatusCode >= 400 {
-		return fmt.Errorf("download %s failed with status %d", fileURL, resp.StatusCode)
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	total := resp.ContentLength
+	if offset > 0 && resp.StatusCode == http.StatusPartialContent && rangeStart(resp.Header.Get("Content-Range")) == offset {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		log.Debugf("download %s: resuming at %d bytes", targetPath, offset)
+		if total >= 0 {
+			total += offset
+		}
+	} else {
+		offset = 0
 	}
-	f, err := os.Create(targetPath)
+	f, err := os.OpenFile(longPath(partPath), flags, 0o644)
 	if err != nil {
-		return fmt.Errorf("create output file %s: %w", targetPath, err)
+		return nil, fmt.Errorf("create output file %s: %w", partPath, err)
 	}
-	defer f.Close()
-	if _, err := io.Copy(f, resp.Body); err != nil {
-		return fmt.Errorf("write output file %s: %w", targetPath, err)
+	var dst io.Writer = f
+	if onBytes != nil {
+		dst = &progressWriter{w: f, done: offset, total: total, fn: onBytes}
+	}
+	n, err := io.Copy(dst, resp.Body)
+	metrics.DownloadBytes.Add(float64(n))
+	span.SetAttributes(telemetry.Int("wiro.download.bytes", n), telemetry.Int("wiro.download.resumed_at", offset))
+	if err != nil {
+		// Keep what arrived so the next attempt can resume it.
+		f.Close()
+		return nil, fmt.Errorf("write output file %s: %w", targetPath, err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		_ = os.Remove(longPath(partPath))
+		return nil, fmt.Errorf("sync output file %s: %w", targetPath, err)
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(longPath(partPath))
+		return nil, fmt.Errorf("close output file %s: %w", targetPath, err)
+	}
+	if err := os.Rename(longPath(partPath), longPath(targetPath)); err != nil {
+		_ = os.Remove(longPath(partPath))
+		return nil, fmt.Errorf("finalize output file %s: %w", targetPath, err)
+	}
+	header = resp.Header
+	if offset > 0 {
+		header = header.Clone()
+		header.Del("Content-MD5")
+	}
+	return header, nil
+}
+
+// rangeStart returns the first byte of a "bytes start-end/size" Content-Range,
+// or -1.
+func rangeStart(contentRange string) int64 {
+	spec, ok := strings.CutPrefix(strings.TrimSpace(contentRange), "bytes ")
+	if !ok {
+		return -1
+	}
+	start, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(start), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// progressWriter reports cumulative writes to fn.
+type progressWriter struct {
+	w     io.Writer
+	done  int64
+	total int64
+	fn    api.ProgressFunc
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	if n > 0 {
+		p.done += int64(n)
+		p.fn(p.done, p.total)
+	}
+	return n, err
+}
+
+// tempSuffix marks in-progress downloads, which are resumed on the next attempt.
+const tempSuffix = ".part"
+
+// legacyTempSuffix marked in-progress downloads before they could be resumed.
+const legacyTempSuffix = ".tmp"
+
+// removeOrphanTempFiles deletes temp files left behind by interrupted downloads,
+// except the .part files of keep, which the download resumes.
+func removeOrphanTempFiles(dir string, keep map[string]bool) {
+	entries, err := os.ReadDir(longPath(dir))
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || keep[name] {
+			continue
+		}
+		if !strings.HasSuffix(name, tempSuffix) && !strings.HasSuffix(name, legacyTempSuffix) {
+			continue
+		}
+		_ = os.Remove(longPath(filepath.Join(dir, name)))
 	}
-	return nil
 }
 
 func outputExt(out api.TaskOutput) string {
@@ -144,18 +519,23 @@ func outputExt(out api.TaskOutput) string {
 	return ".bin"
 }
 
-func outputFilename(out api.TaskOutput, prompt string, index int) string {
-	if index < 1 {
-		index = 1
-	}
-	slug := promptSlug(prompt, 2)
-	if slug == "" {
-		slug = "output"
-	}
-	return fmt.Sprintf("%s-%d%s", slug, index, outputExt(out))
-}
+var nonWordRun = regexp.MustCompile(`[^\p{L}\p{N}]+`)
 
-var nonWordRun = regexp.MustCompile(`[^a-z0-9]+`)
+// maxSlugWordRunes caps words from scripts that do not separate words with spaces.
+const maxSlugWordRunes = 16
+
+// latinFold transliterates common accented Latin letters (Turkish, European) to ASCII.
+var latinFold = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c", 'ď': "d", 'đ': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g", 'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ı': "i", 'ī': "i",
+	'ł': "l", 'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ő': "o", 'œ': "oe",
+	'ř': "r", 'ß': "ss", 'ś': "s", 'ş': "s", 'š': "s", 'ș': "s", 'ť': "t", 'ț': "t",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u",
+	'ý': "y", 'ÿ': "y", 'ź': "z", 'ż': "z", 'ž': "z",
+}
 
 func promptSlug(prompt string, maxWords int) string {
 	prompt = strings.TrimSpace(prompt)
@@ -168,25 +548,42 @@ func promptSlug(prompt string, maxWords int) string {
 
 	words := make([]string, 0, maxWords)
 	current := strings.Builder{}
-	for _, r := range prompt {
-		if unicode.IsLetter(r) || unicode.IsNumber(r) {
-			current.WriteRune(unicode.ToLower(r))
-			continue
-		}
+	runes := 0
+	flush := func() {
 		if current.Len() > 0 {
 			words = append(words, current.String())
 			current.Reset()
-			if len(words) >= maxWords {
-				break
+			runes = 0
+		}
+	}
+	for _, r := range prompt {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			r = unicode.ToLower(r)
+			if runes >= maxSlugWordRunes {
+				continue
 			}
+			if folded, ok := latinFold[r]; ok {
+				current.WriteString(folded)
+			} else {
+				current.WriteRune(r)
+			}
+			runes++
+			continue
+		}
+		// Combining marks (e.g. decomposed accents) carry no filename meaning.
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		flush()
+		if len(words) >= maxWords {
+			break
 		}
 	}
-	if len(words) < maxWords && current.Len() > 0 {
-		words = append(words, current.String())
+	if len(words) < maxWords {
+		flush()
 	}
 
 	slug := strings.Join(words, "-")
-	slug = strings.ToLower(slug)
 	slug = nonWordRun.ReplaceAllString(slug, "-")
 	slug = strings.Trim(slug, "-")
 	return slug
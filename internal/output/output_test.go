@@ -1,7 +1,16 @@
 package output
 
 import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/wiro-ai/wiro-cli/internal/api"
 )
@@ -28,8 +37,387 @@ func TestOutputExtFallbacks(t *testing.T) {
 
 func TestOutputFilename_UsesPromptSlug(t *testing.T) {
 	out := api.TaskOutput{Name: "server-name.jpg", ContentType: "image/jpeg"}
-	got := outputFilename(out, "simple cat", 1)
+	got := NameTemplate{}.filename(nil, "simple cat", out, 1)
 	if got != "simple-cat-1.jpg" {
 		t.Fatalf("unexpected filename: %s", got)
 	}
 }
+
+func TestHashInputs_StableAcrossOrder(t *testing.T) {
+	a := map[string][]api.MultipartValue{
+		"prompt": {{Value: "a cat"}},
+		"steps":  {{Value: "30"}},
+	}
+	b := map[string][]api.MultipartValue{
+		"steps":  {{Value: "30"}},
+		"prompt": {{Value: "a cat"}},
+	}
+	ha, err := HashInputs(a)
+	if err != nil {
+		t.Fatalf("hash a: %v", err)
+	}
+	hb, err := HashInputs(b)
+	if err != nil {
+		t.Fatalf("hash b: %v", err)
+	}
+	if ha != hb {
+		t.Fatalf("hash should not depend on map order: %s != %s", ha, hb)
+	}
+	b["steps"] = []api.MultipartValue{{Value: "31"}}
+	if hc, _ := HashInputs(b); hc == ha {
+		t.Fatalf("hash should change with values")
+	}
+}
+
+func TestSanitizeFilename_Windows(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"what?-1.png", "what--1.png"},
+		{"a:b|c-1.jpg", "a-b-c-1.jpg"},
+		{"con.txt", "_con.txt"},
+		{"trailing. ", "trailing"},
+	}
+	for _, tc := range cases {
+		if got := sanitizeFilename(tc.in, "windows"); got != tc.want {
+			t.Fatalf("sanitizeFilename(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+	if got := sanitizeFilename("what?-1.png", "linux"); got != "what?-1.png" {
+		t.Fatalf("linux should keep '?', got %q", got)
+	}
+	long := strings.Repeat("a", 300) + ".png"
+	if got := sanitizeFilename(long, "linux"); len(got) > maxFilenameBytes || !strings.HasSuffix(got, ".png") {
+		t.Fatalf("long name not truncated correctly: %d %q", len(got), got[len(got)-8:])
+	}
+}
+
+func TestPromptSlug_Unicode(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"Şehir ışıkları gece", "sehir-isiklari"},
+		{"Kedi ağaçta uyuyor", "kedi-agacta"},
+		{"猫 テーブル の上", "猫-テーブル"},
+		{"Привет мир", "привет-мир"},
+		{"İstanbul Boğazı", "istanbul-bogazi"},
+	}
+	for _, tc := range cases {
+		if got := promptSlug(tc.in, 2); got != tc.want {
+			t.Fatalf("promptSlug(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestDownloadOutputs_AtomicAndCleansOrphans(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("image-bytes"))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	taskDir := filepath.Join(dir, "42")
+	if err := os.MkdirAll(taskDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	orphan := filepath.Join(taskDir, "old-1.png"+tempSuffix)
+	if err := os.WriteFile(orphan, []byte("partial"), 0o644); err != nil {
+		t.Fatalf("write orphan: %v", err)
+	}
+
+	task := &api.Task{ID: "42", Outputs: []api.TaskOutput{{URL: srv.URL + "/a.png"}}}
+	paths, err := DownloadOutputs(context.Background(), task, dir, "a cat", DownloadOptions{})
+	if err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	if len(paths) != 1 {
+		t.Fatalf("unexpected paths: %v", paths)
+	}
+	data, err := os.ReadFile(paths[0])
+	if err != nil || string(data) != "image-bytes" {
+		t.Fatalf("unexpected content %q err=%v", data, err)
+	}
+	if _, err := os.Stat(orphan); !os.IsNotExist(err) {
+		t.Fatalf("orphan temp file should be removed")
+	}
+	if _, err := os.Stat(paths[0] + tempSuffix); !os.IsNotExist(err) {
+		t.Fatalf("temp file should not remain after rename")
+	}
+}
+
+func TestDownloadOutputs_ResumesPartFile(t *testing.T) {
+	content := []byte("image-bytes")
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		http.ServeContent(w, r, "a.png", time.Time{}, bytes.NewReader(content))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	taskDir := filepath.Join(dir, "42")
+	if err := os.MkdirAll(taskDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	part := filepath.Join(taskDir, "a-cat-1.png"+tempSuffix)
+	if err := os.WriteFile(part, content[:6], 0o644); err != nil {
+		t.Fatalf("write part: %v", err)
+	}
+
+	var last DownloadProgress
+	task := &api.Task{ID: "42", Outputs: []api.TaskOutput{{URL: srv.URL + "/a.png"}}}
+	paths, err := DownloadOutputs(context.Background(), task, dir, "a cat", DownloadOptions{
+		OnProgress: func(p DownloadProgress) {
+			if !p.Done {
+				last = p
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	if len(ranges) != 1 || ranges[0] != "bytes=6-" {
+		t.Fatalf("expected one range request from byte 6, got %q", ranges)
+	}
+	data, err := os.ReadFile(paths[0])
+	if err != nil || string(data) != string(content) {
+		t.Fatalf("unexpected content %q err=%v", data, err)
+	}
+	if last.Bytes != int64(len(content)) || last.Size != int64(len(content)) {
+		t.Fatalf("progress should count the resumed bytes, got %d/%d", last.Bytes, last.Size)
+	}
+	if _, err := os.Stat(part); !os.IsNotExist(err) {
+		t.Fatalf("part file should not remain after rename")
+	}
+}
+
+func TestDownloadOutputs_WaitsForLockAndSkipsFinishedFiles(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		_, _ = w.Write([]byte("image-bytes"))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	taskDir := filepath.Join(dir, "7")
+	if err := os.MkdirAll(taskDir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	release, err := lockTaskDir(context.Background(), taskDir)
+	if err != nil {
+		t.Fatalf("lock: %v", err)
+	}
+
+	task := &api.Task{ID: "7", Outputs: []api.TaskOutput{{URL: srv.URL + "/a.png"}}}
+	done := make(chan error, 1)
+	go func() {
+		_, err := DownloadOutputs(context.Background(), task, dir, "a cat", DownloadOptions{})
+		done <- err
+	}()
+
+	// The first "process" finishes the file while the second waits on the lock.
+	if err := os.WriteFile(filepath.Join(taskDir, "a-cat-1.png"), []byte("done"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	release()
+
+	if err := <-done; err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	if hits != 0 {
+		t.Fatalf("finished file was downloaded again (%d requests)", hits)
+	}
+	if _, err := os.Stat(filepath.Join(taskDir, lockFileName)); !os.IsNotExist(err) {
+		t.Fatalf("lock file should be removed")
+	}
+}
+
+func TestDownloadOutputs_ReportsProgress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("0123456789"))
+	}))
+	defer srv.Close()
+
+	var events []DownloadProgress
+	task := &api.Task{ID: "9", Outputs: []api.TaskOutput{{URL: srv.URL + "/a.png"}}}
+	_, err := DownloadOutputs(context.Background(), task, t.TempDir(), "a cat", DownloadOptions{
+		OnProgress: func(p DownloadProgress) { events = append(events, p) },
+	})
+	if err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	if len(events) < 2 {
+		t.Fatalf("expected byte and completion events, got %#v", events)
+	}
+	last := events[len(events)-1]
+	if !last.Done || last.Bytes != 10 || last.Index != 1 || last.Count != 1 {
+		t.Fatalf("unexpected final progress: %#v", last)
+	}
+}
+
+func TestDownloadOutputs_FilterByIndexAndPattern(t *testing.T) {
+	var fetched []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetched = append(fetched, r.URL.Path)
+		_, _ = w.Write([]byte("x"))
+	}))
+	defer srv.Close()
+
+	task := &api.Task{ID: "5", Outputs: []api.TaskOutput{
+		{URL: srv.URL + "/one.png"},
+		{URL: srv.URL + "/two.MP4"},
+		{URL: srv.URL + "/three.mp4"},
+	}}
+	paths, err := DownloadOutputs(context.Background(), task, t.TempDir(), "a cat", DownloadOptions{
+		Filter: OutputFilter{Indexes: []int{2, 3}, Patterns: []string{"*.mp4"}},
+	})
+	if err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	if len(paths) != 2 || strings.Join(fetched, ",") != "/two.MP4,/three.mp4" {
+		t.Fatalf("unexpected selection: paths=%v fetched=%v", paths, fetched)
+	}
+	if filepath.Base(paths[0]) != "a-cat-2.MP4" {
+		t.Fatalf("selected output should keep its original index: %s", paths[0])
+	}
+
+	_, err = DownloadOutputs(context.Background(), task, t.TempDir(), "", DownloadOptions{
+		Filter: OutputFilter{Patterns: []string{"*.gif"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "no outputs match") {
+		t.Fatalf("expected no-match error, got %v", err)
+	}
+	if err := (OutputFilter{Patterns: []string{"["}}).Validate(); err == nil {
+		t.Fatal("expected bad pattern error")
+	}
+}
+
+func TestBuildManifest_SelectiveDownload(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("data"))
+	}))
+	defer srv.Close()
+
+	task := &api.Task{ID: "5", Status: "task_postprocess_end", Outputs: []api.TaskOutput{
+		{URL: srv.URL + "/one.png", ContentType: "image/png"},
+		{URL: srv.URL + "/two.png", ContentType: "image/png"},
+		{URL: srv.URL + "/three.mp4", ContentType: "video/mp4"},
+	}}
+	files, err := DownloadOutputFiles(context.Background(), task, t.TempDir(), "a cat", DownloadOptions{
+		Filter: OutputFilter{Indexes: []int{3}},
+	})
+	if err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	if len(files) != 1 || files[0].Index != 3 {
+		t.Fatalf("files = %+v", files)
+	}
+	m := BuildManifest(task, "wiro/flux", "h", files)
+	if len(m.Files) != 1 {
+		t.Fatalf("manifest files = %+v", m.Files)
+	}
+	if f := m.Files[0]; f.URL != srv.URL+"/three.mp4" || f.ContentType != "video/mp4" || f.Name != "a-cat-3.mp4" || f.Size != 4 {
+		t.Fatalf("manifest entry = %+v", f)
+	}
+}
+
+func TestParseNameTemplate(t *testing.T) {
+	for _, bad := range []string{
+		"/abs/{taskid}-{index}{ext}",
+		"{model}/../{taskid}-{index}{ext}",
+		"{model}/{prompt}{ext}-{taskid}",
+		"{model}/{prompt}-{index}{ext}",
+		"{index}/{taskid}{ext}",
+		"{taskid}/{bogus}-{index}",
+		"{taskid}/{date:3}-{index}",
+		"{taskid}/{prompt:0}-{index}",
+		"{taskid}/{prompt-{index}",
+	} {
+		if _, err := ParseNameTemplate(bad); err == nil {
+			t.Fatalf("ParseNameTemplate(%q) should fail", bad)
+		}
+	}
+	def, err := ParseNameTemplate("")
+	if err != nil || def.String() != DefaultNameTemplate || !def.PerTask() {
+		t.Fatalf("default template = %q per-task=%v err=%v", def.String(), def.PerTask(), err)
+	}
+	if (NameTemplate{}).ManifestName("42") != "manifest.json" {
+		t.Fatalf("per-task manifest should be manifest.json")
+	}
+}
+
+func TestNameTemplateWorkspace(t *testing.T) {
+	naming, err := ParseNameTemplate("{workspace}/{model}/{taskid}-{index}{ext}")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	task := &api.Task{ID: "42", SlugProject: "flux"}
+	out := api.TaskOutput{URL: "https://cdn.example/a.png"}
+	if got := naming.WithWorkspace("renders").Dir("out", task); got != filepath.Join("out", "renders", "flux") {
+		t.Fatalf("Dir = %q", got)
+	}
+	if got := naming.Dir("out", task); got != filepath.Join("out", "unknown", "flux") {
+		t.Fatalf("Dir without a workspace = %q", got)
+	}
+	if got := naming.WithWorkspace("renders").filename(task, "", out, 2); got != "42-2.png" {
+		t.Fatalf("filename = %q", got)
+	}
+	if _, err := ParseNameTemplate("{taskid}/{workspace:2}-{index}"); err == nil {
+		t.Fatal("{workspace} takes no argument")
+	}
+}
+
+func TestDownloadOutputs_NameTemplate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("image-bytes"))
+	}))
+	defer srv.Close()
+
+	naming, err := ParseNameTemplate(`{owner}\{model}/{date}/{prompt:3}-{taskid}-{index}{ext}`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if naming.PerTask() || naming.ManifestName("42") != "42.manifest.json" {
+		t.Fatalf("shared layout: per-task=%v manifest=%q", naming.PerTask(), naming.ManifestName("42"))
+	}
+	created := time.Date(2026, 3, 4, 12, 0, 0, 0, time.Local)
+	task := &api.Task{
+		ID:          "42",
+		SlugOwner:   "wiro",
+		SlugProject: "flux",
+		CreateTime:  strconv.FormatInt(created.Unix(), 10),
+		Outputs:     []api.TaskOutput{{URL: srv.URL + "/a.png"}, {URL: srv.URL + "/b.jpg"}},
+	}
+	dir := t.TempDir()
+	paths, err := DownloadOutputs(context.Background(), task, dir, "a red fox jumps", DownloadOptions{Naming: naming})
+	if err != nil {
+		t.Fatalf("download: %v", err)
+	}
+	want := []string{
+		filepath.Join(dir, "wiro", "flux", "2026-03-04", "a-red-fox-42-1.png"),
+		filepath.Join(dir, "wiro", "flux", "2026-03-04", "a-red-fox-42-2.jpg"),
+	}
+	if strings.Join(paths, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("paths = %v, want %v", paths, want)
+	}
+	if got := naming.Dir(dir, task); got != filepath.Dir(want[0]) {
+		t.Fatalf("Dir = %q", got)
+	}
+}
+
+func TestOptionLabelAndCoverLink(t *testing.T) {
+	if got := optionLabel(api.ToolOption{Text: "Anime", Value: "anime-v2"}); got != "Anime (anime-v2)" {
+		t.Fatalf("optionLabel = %q", got)
+	}
+	if got := optionLabel(api.ToolOption{Text: "7", Value: 7.0}); got != "7" {
+		t.Fatalf("optionLabel = %q", got)
+	}
+	// Test output is not a terminal, so the URL is printed as is.
+	t.Setenv("FORCE_HYPERLINK", "1")
+	if got := coverLink("https://cdn.example.com/anime.webp"); got != "https://cdn.example.com/anime.webp" {
+		t.Fatalf("coverLink = %q", got)
+	}
+}
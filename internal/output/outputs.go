@@ -0,0 +1,103 @@
+package output
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+// headConcurrency bounds parallel HEAD requests when listing outputs.
+const headConcurrency = 4
+
+// OutputInfo describes a task output without downloading it.
+type OutputInfo struct {
+	Index       int    `json:"index"`
+	Name        string `json:"name"`
+	ContentType string `json:"contentType"`
+	// Size is in bytes; -1 when unknown.
+	Size  int64  `json:"size"`
+	URL   string `json:"url"`
+	Error string `json:"error,omitempty"`
+}
+
+// InspectOutputs lists task outputs, filling size and missing content types with HEAD requests.
+func InspectOutputs(ctx context.Context, task *api.Task, opts DownloadOptions) []OutputInfo {
+	client := opts.Client
+	if client == nil {
+		client = api.NewClient("")
+	}
+	infos := make([]OutputInfo, len(task.Outputs))
+	sem := make(chan struct{}, headConcurrency)
+	var wg sync.WaitGroup
+	for i, out := range task.Outputs {
+		infos[i] = OutputInfo{Index: i + 1, Name: outputName(out), ContentType: out.ContentType, Size: -1, URL: out.URL}
+		if strings.TrimSpace(out.URL) == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(info *OutputInfo) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			size, ctype, err := client.Head(ctx, info.URL, opts.Headers)
+			if err != nil {
+				info.Error = err.Error()
+				return
+			}
+			info.Size = size
+			if info.ContentType == "" {
+				info.ContentType = ctype
+			}
+		}(&infos[i])
+	}
+	wg.Wait()
+	return infos
+}
+
+// outputName prefers the API name and falls back to the last URL path segment.
+func outputName(out api.TaskOutput) string {
+	if name := strings.TrimSpace(out.Name); name != "" {
+		return name
+	}
+	if u, err := url.Parse(out.URL); err == nil {
+		if base := path.Base(u.Path); base != "." && base != "/" {
+			return base
+		}
+	}
+	return "-"
+}
+
+// PrintOutputs prints outputs as an aligned table.
+func PrintOutputs(infos []OutputInfo) {
+	t := NewTable("#", "NAME", "TYPE", "SIZE", "URL")
+	for _, info := range infos {
+		size := FormatBytes(info.Size)
+		if info.Error != "" {
+			size = "? (" + info.Error + ")"
+		}
+		t.Row(fmt.Sprint(info.Index), info.Name, Dash(info.ContentType), size, info.URL)
+	}
+	_ = t.Print()
+}
+
+// FormatBytes renders a byte count in binary units; negative means unknown.
+func FormatBytes(n int64) string {
+	if n < 0 {
+		return "-"
+	}
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,49 @@
+package output
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+func TestInspectOutputs_HeadSizes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead {
+			t.Errorf("expected HEAD, got %s", r.Method)
+		}
+		if r.URL.Path == "/missing.png" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("Content-Length", "2048")
+	}))
+	defer srv.Close()
+
+	task := &api.Task{Outputs: []api.TaskOutput{
+		{URL: srv.URL + "/a/cat.png"},
+		{Name: "dog.png", ContentType: "image/webp", URL: srv.URL + "/missing.png"},
+	}}
+	infos := InspectOutputs(context.Background(), task, DownloadOptions{})
+	if len(infos) != 2 {
+		t.Fatalf("expected 2 outputs, got %d", len(infos))
+	}
+	if infos[0].Name != "cat.png" || infos[0].ContentType != "image/png" || infos[0].Size != 2048 {
+		t.Fatalf("unexpected first output %+v", infos[0])
+	}
+	if infos[1].Error == "" || infos[1].Size != -1 || infos[1].ContentType != "image/webp" {
+		t.Fatalf("unexpected second output %+v", infos[1])
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	cases := map[int64]string{-1: "-", 0: "0 B", 1023: "1023 B", 2048: "2.0 KiB", 5 << 20: "5.0 MiB"}
+	for in, want := range cases {
+		if got := FormatBytes(in); got != want {
+			t.Fatalf("FormatBytes(%d) = %q, want %q", in, got, want)
+		}
+	}
+}
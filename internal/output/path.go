@@ -0,0 +1,90 @@
+package output
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxFilenameBytes keeps names under the 255-byte component limit of common filesystems.
+const maxFilenameBytes = 200
+
+// windowsMaxPath is the classic MAX_PATH limit; longer paths need the \\?\ prefix.
+const windowsMaxPath = 260
+
+var windowsReservedNames = map[string]struct{}{
+	"CON": {}, "PRN": {}, "AUX": {}, "NUL": {},
+	"COM1": {}, "COM2": {}, "COM3": {}, "COM4": {}, "COM5": {}, "COM6": {}, "COM7": {}, "COM8": {}, "COM9": {},
+	"LPT1": {}, "LPT2": {}, "LPT3": {}, "LPT4": {}, "LPT5": {}, "LPT6": {}, "LPT7": {}, "LPT8": {}, "LPT9": {},
+}
+
+// sanitizeFilename makes a single path component safe to create on goos.
+func sanitizeFilename(name, goos string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r == '/' || r == 0:
+			b.WriteRune('-')
+		case goos == "windows" && (r < 32 || strings.ContainsRune(`<>:"\|?*`, r)):
+			b.WriteRune('-')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	out := b.String()
+	if goos == "windows" {
+		out = strings.TrimRight(out, ". ")
+		stem := strings.ToUpper(strings.TrimSuffix(out, filepath.Ext(out)))
+		if _, reserved := windowsReservedNames[stem]; reserved {
+			out = "_" + out
+		}
+	}
+	out = truncateFilename(out, maxFilenameBytes)
+	if out == "" || out == "." || out == ".." {
+		return "output"
+	}
+	return out
+}
+
+// SafeName makes name usable as a single file or directory name on this OS.
+func SafeName(name string) string {
+	return sanitizeFilename(name, runtime.GOOS)
+}
+
+// truncateFilename shortens the stem so the whole name fits in max bytes, keeping the extension.
+func truncateFilename(name string, max int) string {
+	if len(name) <= max {
+		return name
+	}
+	ext := filepath.Ext(name)
+	if len(ext) > max/2 {
+		ext = ""
+	}
+	stem := strings.TrimSuffix(name, ext)
+	limit := max - len(ext)
+	for len(stem) > limit {
+		_, size := utf8.DecodeLastRuneInString(stem)
+		stem = stem[:len(stem)-size]
+	}
+	return stem + ext
+}
+
+// longPath opts into Windows extended-length paths when a path exceeds MAX_PATH.
+func longPath(path string) string {
+	return longPathFor(path, runtime.GOOS)
+}
+
+func longPathFor(path, goos string) string {
+	if goos != "windows" || len(path) < windowsMaxPath || strings.HasPrefix(path, `\\?\`) {
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	if strings.HasPrefix(abs, `\\`) {
+		return `\\?\UNC\` + strings.TrimPrefix(abs, `\\`)
+	}
+	return `\\?\` + abs
+}
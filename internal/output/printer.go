@@ -0,0 +1,140 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Printer writes human-facing output. Results (Print*) are always written to
+// Out; status messages (Info*) are chatter that Quiet hides; warnings go to
+// Err. Notef is status for Err when Out carries data, and Alertf is for what
+// the user must see even with Quiet. Styles only apply when Color is set.
+type Printer struct {
+	// Out and Err default to os.Stdout and os.Stderr at the time of writing.
+	Out io.Writer
+	Err io.Writer
+	// Quiet hides status messages, leaving results and warnings.
+	Quiet bool
+	// Color styles text with ANSI escapes.
+	Color bool
+}
+
+// std is the printer behind the package-level functions, set up from the
+// global flags by SetQuiet and SetColor.
+var std = &Printer{Color: stdoutIsTerminal() && colorEnabled()}
+
+// Std returns the printer the global flags configure.
+func Std() *Printer {
+	return std
+}
+
+// SetColor turns styling off with --no-color; otherwise it is on when stdout
+// is a terminal and NO_COLOR is unset.
+func SetColor(noColor bool) {
+	std.Color = !noColor && stdoutIsTerminal() && colorEnabled()
+}
+
+func (p *Printer) out() io.Writer {
+	if p.Out != nil {
+		return p.Out
+	}
+	return os.Stdout
+}
+
+func (p *Printer) err() io.Writer {
+	if p.Err != nil {
+		return p.Err
+	}
+	return os.Stderr
+}
+
+// Printf writes a result.
+func (p *Printer) Printf(format string, a ...interface{}) {
+	fmt.Fprintf(p.out(), format, a...)
+}
+
+// Println writes a result line.
+func (p *Printer) Println(a ...interface{}) {
+	fmt.Fprintln(p.out(), a...)
+}
+
+// Print writes a result without a newline, e.g. a prompt.
+func (p *Printer) Print(a ...interface{}) {
+	fmt.Fprint(p.out(), a...)
+}
+
+// Infof writes a status message unless Quiet is set.
+func (p *Printer) Infof(format string, a ...interface{}) {
+	if !p.Quiet {
+		fmt.Fprintf(p.out(), format, a...)
+	}
+}
+
+// Infoln writes a status line unless Quiet is set.
+func (p *Printer) Infoln(a ...interface{}) {
+	if !p.Quiet {
+		fmt.Fprintln(p.out(), a...)
+	}
+}
+
+// Notef writes a status message to Err unless Quiet is set, for commands
+// whose Out is JSON, an export, or a protocol stream.
+func (p *Printer) Notef(format string, a ...interface{}) {
+	if !p.Quiet {
+		fmt.Fprintf(p.err(), format, a...)
+	}
+}
+
+// Alertf writes a message to Err even when Quiet is set, for what the user has
+// to act on, such as a sign-in code or tasks left running.
+func (p *Printer) Alertf(format string, a ...interface{}) {
+	fmt.Fprintf(p.err(), format, a...)
+}
+
+// Warnf writes "warning: " and the message to Err; a newline is added. The
+// label is only styled when Err is the terminal.
+func (p *Printer) Warnf(format string, a ...interface{}) {
+	label := "warning:"
+	if p.Err == nil && stderrIsTerminal() {
+		label = p.Yellow(label)
+	}
+	fmt.Fprintf(p.err(), "%s %s\n", label, fmt.Sprintf(format, a...))
+}
+
+func (p *Printer) style(code, s string) string {
+	if !p.Color || s == "" {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+// Bold, Dim, Green, Red, and Yellow style s when Color is set.
+func (p *Printer) Bold(s string) string   { return p.style("1", s) }
+func (p *Printer) Dim(s string) string    { return p.style("2", s) }
+func (p *Printer) Green(s string) string  { return p.style("32", s) }
+func (p *Printer) Red(s string) string    { return p.style("31", s) }
+func (p *Printer) Yellow(s string) string { return p.style("33", s) }
+
+// Status colors a task or job status: green when it succeeded, red when it
+// failed or was cancelled, and as is while it is pending or runs.
+func (p *Printer) Status(status string) string {
+	switch status {
+	case "task_postprocess_end", "done":
+		return p.Green(status)
+	case "task_cancel", "task_error_full", "failed":
+		return p.Red(status)
+	}
+	return status
+}
+
+// Printf, Println, Print, Infof, Infoln, Notef, Alertf, and Warnf write
+// through Std.
+func Printf(format string, a ...interface{}) { std.Printf(format, a...) }
+func Println(a ...interface{})               { std.Println(a...) }
+func Print(a ...interface{})                 { std.Print(a...) }
+func Infof(format string, a ...interface{})  { std.Infof(format, a...) }
+func Infoln(a ...interface{})                { std.Infoln(a...) }
+func Notef(format string, a ...interface{})  { std.Notef(format, a...) }
+func Alertf(format string, a ...interface{}) { std.Alertf(format, a...) }
+func Warnf(format string, a ...interface{})  { std.Warnf(format, a...) }
@@ -0,0 +1,52 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPrinterQuiet(t *testing.T) {
+	var out, errOut bytes.Buffer
+	p := &Printer{Out: &out, Err: &errOut, Quiet: true}
+	p.Infof("Task started: %s\n", "t1")
+	p.Infoln("Watching task...")
+	p.Println("https://cdn.example/out.png")
+	p.Notef("Dedupe: reused %d file(s)\n", 2)
+	p.Alertf("Token: %s\n", "abc")
+	p.Warnf("budget check skipped: %v", "offline")
+	if got := out.String(); got != "https://cdn.example/out.png\n" {
+		t.Fatalf("out = %q", got)
+	}
+	if got := errOut.String(); got != "Token: abc\nwarning: budget check skipped: offline\n" {
+		t.Fatalf("err = %q", got)
+	}
+
+	out.Reset()
+	errOut.Reset()
+	p.Quiet = false
+	p.Infof("Task started: %s\n", "t1")
+	p.Notef("Dedupe: reused %d file(s)\n", 2)
+	if out.String() != "Task started: t1\n" {
+		t.Fatalf("out = %q", out.String())
+	}
+	if errOut.String() != "Dedupe: reused 2 file(s)\n" {
+		t.Fatalf("err = %q", errOut.String())
+	}
+}
+
+func TestPrinterColor(t *testing.T) {
+	p := &Printer{}
+	if p.Bold("x") != "x" || p.Status("task_cancel") != "task_cancel" {
+		t.Fatal("styled without Color")
+	}
+	p.Color = true
+	if got := p.Status("task_postprocess_end"); got != "\x1b[32mtask_postprocess_end\x1b[0m" {
+		t.Fatalf("status = %q", got)
+	}
+	if got := p.Status("task_start"); got != "task_start" {
+		t.Fatalf("running status = %q", got)
+	}
+	if p.Red("") != "" {
+		t.Fatal("empty text should stay empty")
+	}
+}
@@ -0,0 +1,200 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/term"
+)
+
+// quiet suppresses progress output and status messages (the global --quiet flag).
+var quiet bool
+
+// SetQuiet turns --quiet on or off.
+func SetQuiet(on bool) {
+	quiet = on
+	std.Quiet = on
+}
+
+const (
+	// progressMinBytes keeps small transfers, like a prompt-only run request, silent.
+	progressMinBytes = 1 << 20
+	// progressRedraw limits how often a terminal bar is redrawn.
+	progressRedraw = 100 * time.Millisecond
+	// progressLineStep is how many percent a non-terminal line report waits for.
+	progressLineStep = 10
+	// progressLineInterval spaces line reports when the total size is unknown.
+	progressLineInterval = 5 * time.Second
+	progressBarWidth     = 24
+)
+
+// Progress renders one transfer to stderr: an in-place bar on a terminal, or a
+// percentage line every progressLineStep percent when stderr is redirected.
+// A nil *Progress (from --quiet) ignores every call.
+type Progress struct {
+	w     io.Writer
+	tty   bool
+	label string
+	now   func() time.Time
+
+	mu       sync.Mutex
+	started  time.Time
+	lastDraw time.Time
+	lastPct  int
+	done     int64
+	total    int64
+	shown    bool
+	finished bool
+}
+
+// NewProgress returns a progress display for label on stderr, or nil with --quiet.
+func NewProgress(label string) *Progress {
+	if quiet {
+		return nil
+	}
+	return newProgress(os.Stderr, stderrIsTerminal(), label)
+}
+
+func newProgress(w io.Writer, tty bool, label string) *Progress {
+	return &Progress{w: w, tty: tty, label: label, now: time.Now, lastPct: -1}
+}
+
+// Update records done of total bytes; total is -1 when unknown. It has the
+// signature of api.ProgressFunc.
+func (p *Progress) Update(done, total int64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.finished {
+		return
+	}
+	now := p.now()
+	if p.started.IsZero() {
+		p.started = now
+	}
+	p.done, p.total = done, total
+	if !p.shown && total < progressMinBytes && done < progressMinBytes {
+		return
+	}
+	complete := total > 0 && done >= total
+	if p.tty {
+		if complete || now.Sub(p.lastDraw) >= progressRedraw {
+			p.draw(now)
+		}
+		return
+	}
+	if total > 0 {
+		pct := int(done * 100 / total)
+		if step := pct / progressLineStep * progressLineStep; step > p.lastPct && (step > 0 || !p.shown) {
+			p.lastPct = step
+			p.line(now)
+		}
+		return
+	}
+	if !p.shown || now.Sub(p.lastDraw) >= progressLineInterval {
+		p.line(now)
+	}
+}
+
+// Done ends the display; a terminal bar is completed and moved past.
+func (p *Progress) Done() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.finished {
+		return
+	}
+	p.finished = true
+	if !p.shown {
+		return
+	}
+	if p.tty {
+		if p.total > 0 {
+			p.done = p.total
+		}
+		p.draw(p.now())
+		fmt.Fprintln(p.w)
+		return
+	}
+	if p.lastPct < 100 {
+		p.lastPct = 100
+		if p.total > 0 {
+			p.done = p.total
+		}
+		p.line(p.now())
+	}
+}
+
+func (p *Progress) draw(now time.Time) {
+	p.shown, p.lastDraw = true, now
+	line := p.label + " " + p.status(now)
+	if p.total > 0 {
+		filled := int(p.done * progressBarWidth / p.total)
+		if filled > progressBarWidth {
+			filled = progressBarWidth
+		}
+		bar := "[" + strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled) + "]"
+		line = p.label + " " + bar + " " + p.status(now)
+	}
+	if width := term.Width(); width > 1 {
+		line = truncate(line, width-1)
+	}
+	fmt.Fprintf(p.w, "\r\x1b[2K%s", line)
+}
+
+func (p *Progress) line(now time.Time) {
+	p.shown, p.lastDraw = true, now
+	fmt.Fprintf(p.w, "%s %s\n", p.label, p.status(now))
+}
+
+// status is "45% 12.0 MiB/26.7 MiB 3.1 MiB/s", or bytes and rate when the size is unknown.
+func (p *Progress) status(now time.Time) string {
+	parts := make([]string, 0, 3)
+	if p.total > 0 {
+		parts = append(parts, fmt.Sprintf("%3d%%", p.done*100/p.total), FormatBytes(p.done)+"/"+FormatBytes(p.total))
+	} else {
+		parts = append(parts, FormatBytes(p.done))
+	}
+	if elapsed := now.Sub(p.started).Seconds(); elapsed >= 1 {
+		parts = append(parts, FormatBytes(int64(float64(p.done)/elapsed))+"/s")
+	}
+	return strings.Join(parts, " ")
+}
+
+// DownloadProgressBars returns a DownloadOptions.OnProgress that shows one
+// Progress per output file, or nil with --quiet.
+func DownloadProgressBars() func(DownloadProgress) {
+	if quiet {
+		return nil
+	}
+	var mu sync.Mutex
+	bars := map[int]*Progress{}
+	return func(dp DownloadProgress) {
+		mu.Lock()
+		bar := bars[dp.Index]
+		if bar == nil {
+			bar = NewProgress(fmt.Sprintf("[%d/%d] %s", dp.Index, dp.Count, filepath.Base(dp.Path)))
+			bars[dp.Index] = bar
+		}
+		mu.Unlock()
+		if dp.Done {
+			bar.Done()
+			return
+		}
+		bar.Update(dp.Bytes, dp.Size)
+	}
+}
+
+func stderrIsTerminal() bool {
+	info, err := os.Stderr.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
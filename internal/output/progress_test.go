@@ -0,0 +1,56 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgress_LinesEveryStep(t *testing.T) {
+	var buf bytes.Buffer
+	p := newProgress(&buf, false, "Uploading a.bin")
+	start := time.Unix(0, 0)
+	p.now = func() time.Time { return start }
+	total := int64(10 << 20)
+	for done := int64(0); done <= total; done += 1 << 19 {
+		p.Update(done, total)
+	}
+	p.Done()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 11 {
+		t.Fatalf("got %d lines, want 11:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "Uploading a.bin   0%") {
+		t.Fatalf("first line = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[10], "Uploading a.bin 100% 10.0 MiB/10.0 MiB") {
+		t.Fatalf("last line = %q", lines[10])
+	}
+}
+
+func TestProgress_SmallTransferSilent(t *testing.T) {
+	var buf bytes.Buffer
+	p := newProgress(&buf, false, "Uploading inputs")
+	p.Update(512, 1024)
+	p.Update(1024, 1024)
+	p.Done()
+	if buf.Len() != 0 {
+		t.Fatalf("small transfer printed %q", buf.String())
+	}
+}
+
+func TestProgress_QuietIsNil(t *testing.T) {
+	SetQuiet(true)
+	defer SetQuiet(false)
+	p := NewProgress("x")
+	if p != nil {
+		t.Fatal("NewProgress returned a display with --quiet")
+	}
+	p.Update(1, 2)
+	p.Done()
+	if DownloadProgressBars() != nil {
+		t.Fatal("DownloadProgressBars returned a callback with --quiet")
+	}
+}
@@ -0,0 +1,188 @@
+package output
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/term"
+)
+
+// columnGap separates table columns.
+const columnGap = "  "
+
+// minColumnWidth is as narrow as truncation makes a column.
+const minColumnWidth = 8
+
+// Table renders rows as aligned columns. On a terminal the header is bold and
+// rows are truncated to the terminal width unless --wide is set; piped output
+// keeps every byte.
+type Table struct {
+	// Headers is optional; no header line is printed when empty.
+	Headers []string
+	// MaxWidth truncates the widest columns until each line fits; 0 disables it.
+	MaxWidth int
+	// Color styles the header line and the columns given a StyleColumn.
+	Color bool
+
+	rows   [][]string
+	styles map[int]func(string) string
+}
+
+// NewTable returns a table with headers, sized for stdout.
+func NewTable(headers ...string) *Table {
+	t := &Table{Headers: headers, Color: std.Color}
+	if stdoutIsTerminal() && !wide {
+		t.MaxWidth = term.Width()
+	}
+	return t
+}
+
+// Row appends a row; missing cells render empty and extra cells are kept.
+func (t *Table) Row(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// StyleColumn styles the cells under header with style, e.g. Std().Status,
+// when Color is set. Cells are measured and cut before styling.
+func (t *Table) StyleColumn(header string, style func(string) string) {
+	for i, h := range t.Headers {
+		if h == header {
+			if t.styles == nil {
+				t.styles = map[int]func(string) string{}
+			}
+			t.styles[i] = style
+		}
+	}
+}
+
+// Len returns the number of rows added so far.
+func (t *Table) Len() int {
+	return len(t.rows)
+}
+
+// Print renders the table to stdout.
+func (t *Table) Print() error {
+	return t.Render(std.out())
+}
+
+// Render writes the table to w.
+func (t *Table) Render(w io.Writer) error {
+	widths := t.widths()
+	var b strings.Builder
+	if len(t.Headers) > 0 {
+		line := formatRow(t.Headers, widths, nil)
+		if t.Color {
+			line = "\x1b[1m" + line + "\x1b[0m"
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	var styles map[int]func(string) string
+	if t.Color {
+		styles = t.styles
+	}
+	for _, row := range t.rows {
+		b.WriteString(formatRow(row, widths, styles))
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// widths sizes each column to its widest cell, then narrows the widest columns
+// while the line is wider than MaxWidth.
+func (t *Table) widths() []int {
+	var widths []int
+	measure := func(cells []string) {
+		for i, c := range cells {
+			for len(widths) <= i {
+				widths = append(widths, 0)
+			}
+			if n := term.StringWidth(cellText(c)); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	measure(t.Headers)
+	for _, row := range t.rows {
+		measure(row)
+	}
+	if t.MaxWidth <= 0 || len(widths) == 0 {
+		return widths
+	}
+	total := func() int {
+		n := len(columnGap) * (len(widths) - 1)
+		for _, w := range widths {
+			n += w
+		}
+		return n
+	}
+	for total() > t.MaxWidth {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			break
+		}
+		widths[widest]--
+	}
+	return widths
+}
+
+func formatRow(cells []string, widths []int, styles map[int]func(string) string) string {
+	parts := make([]string, len(widths))
+	for i, width := range widths {
+		c := ""
+		if i < len(cells) {
+			c = cellText(cells[i])
+		}
+		c = truncate(c, width)
+		pad := ""
+		if i < len(widths)-1 {
+			pad = term.Pad(c, width)[len(c):]
+		}
+		if style := styles[i]; style != nil {
+			c = style(c)
+		}
+		parts[i] = c + pad
+	}
+	return strings.TrimRight(strings.Join(parts, columnGap), " ")
+}
+
+// cellText keeps a cell on one line.
+func cellText(s string) string {
+	if !strings.ContainsAny(s, "\n\r\t") {
+		return s
+	}
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// truncate shortens s to width columns, ending in an ellipsis when cut.
+func truncate(s string, width int) string {
+	return term.Truncate(s, width, "…")
+}
+
+func stdoutIsTerminal() bool {
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorEnabled honors NO_COLOR (https://no-color.org) and dumb terminals.
+func colorEnabled() bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	return os.Getenv("TERM") != "dumb"
+}
+
+// Dash renders empty values as "-" in table cells.
+func Dash(s string) string {
+	if strings.TrimSpace(s) == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,100 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTableRender(t *testing.T) {
+	tbl := &Table{Headers: []string{"ID", "NAME", "NOTE"}}
+	tbl.Row("1", "short", "multi\nline")
+	tbl.Row("22", "a much longer name")
+	var b strings.Builder
+	if err := tbl.Render(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := "ID  NAME                NOTE\n" +
+		"1   short               multi line\n" +
+		"22  a much longer name\n"
+	if b.String() != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
+
+func TestTableTruncatesToMaxWidth(t *testing.T) {
+	tbl := &Table{MaxWidth: 30}
+	tbl.Row("abc", strings.Repeat("x", 40))
+	var b strings.Builder
+	if err := tbl.Render(&b); err != nil {
+		t.Fatal(err)
+	}
+	line := strings.TrimSuffix(b.String(), "\n")
+	if n := len([]rune(line)); n != 30 {
+		t.Fatalf("line is %d runes, want 30: %q", n, line)
+	}
+	if !strings.HasSuffix(line, "…") || !strings.HasPrefix(line, "abc  ") {
+		t.Fatalf("unexpected line %q", line)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	got := Wrap("Description: a fast model for photoreal images", 24, "  ")
+	want := []string{"Description: a fast", "  model for photoreal", "  images"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("Wrap = %q, want %q", got, want)
+	}
+	if got := Wrap("one\n two ", 0, "  "); len(got) != 1 || got[0] != "one two" {
+		t.Fatalf("Wrap width 0 = %q", got)
+	}
+	if got := Wrap("abcdefghijklmnopqrstuvwxyz", 12, ""); strings.Join(got, "|") != "abcdefghijkl|mnopqrstuvwx|yz" {
+		t.Fatalf("long word = %q", got)
+	}
+}
+
+func TestTableAlignsWideCharacters(t *testing.T) {
+	tbl := &Table{Headers: []string{"NAME", "NOTE"}}
+	tbl.Row("漢字", "a")
+	tbl.Row("ab", "b")
+	var b strings.Builder
+	if err := tbl.Render(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := "NAME  NOTE\n" +
+		"漢字  a\n" +
+		"ab    b\n"
+	if b.String() != want {
+		t.Fatalf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+	if got := compact("  çok güzel bir açıklama ", 8); got != "çok g..." {
+		t.Fatalf("compact = %q", got)
+	}
+	if got := Wrap("漢字漢字漢字漢字漢字漢字", 10, ""); strings.Join(got, "|") != "漢字漢字漢|字漢字漢字|漢字" {
+		t.Fatalf("wide Wrap = %q", got)
+	}
+}
+
+func TestTableStyledColumns(t *testing.T) {
+	tbl := &Table{Headers: []string{"ID", "STATUS", "ERROR"}, Color: true}
+	tbl.StyleColumn("STATUS", (&Printer{Color: true}).Status)
+	tbl.Row("1", "done", "")
+	tbl.Row("2", "failed", "timeout")
+	var b strings.Builder
+	if err := tbl.Render(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := "\x1b[1mID  STATUS  ERROR\x1b[0m\n" +
+		"1   \x1b[32mdone\x1b[0m\n" +
+		"2   \x1b[31mfailed\x1b[0m  timeout\n"
+	if b.String() != want {
+		t.Fatalf("got %q\nwant %q", b.String(), want)
+	}
+
+	tbl.Color = false
+	b.Reset()
+	if err := tbl.Render(&b); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(b.String(), "\x1b") {
+		t.Fatalf("styled without Color: %q", b.String())
+	}
+}
@@ -0,0 +1,95 @@
+package output
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/wiro-ai/wiro-cli/internal/term"
+)
+
+// wide disables truncation and wrapping of long text (the global --wide flag).
+var wide bool
+
+// SetWide turns --wide on or off.
+func SetWide(on bool) {
+	wide = on
+}
+
+// textWidth is the width long text wraps at: the terminal width, or 0 (no
+// wrapping) with --wide or when stdout is not a terminal.
+func textWidth() int {
+	if wide || !stdoutIsTerminal() {
+		return 0
+	}
+	return term.Width()
+}
+
+// clip shortens v to n columns like compact, unless --wide is set.
+func clip(v string, n int) string {
+	if wide {
+		return strings.TrimSpace(v)
+	}
+	return compact(v, n)
+}
+
+// Wrap breaks text into lines of at most width columns at spaces; every line but
+// the first starts with indent. Words longer than a line are split. Width 0
+// keeps the whole text on one line. Existing line breaks are kept.
+func Wrap(text string, width int, indent string) []string {
+	text = strings.TrimSpace(text)
+	if width <= 0 {
+		return []string{strings.Join(strings.Fields(text), " ")}
+	}
+	if min := term.StringWidth(indent) + 10; width < min {
+		width = min
+	}
+	var lines []string
+	line := ""
+	prefix := ""
+	room := func() int { return width - term.StringWidth(prefix) }
+	flush := func() {
+		lines = append(lines, prefix+line)
+		line = ""
+		prefix = indent
+	}
+	for i, para := range strings.Split(text, "\n") {
+		if i > 0 {
+			flush()
+		}
+		for _, word := range strings.Fields(para) {
+			for room() > 0 && term.StringWidth(word) > room() {
+				if line != "" {
+					flush()
+					continue
+				}
+				head := term.Head(word, room())
+				if head == "" {
+					// A wide character in a one-column room still has to go somewhere.
+					_, size := utf8.DecodeRuneInString(word)
+					head = word[:size]
+				}
+				line = head
+				word = word[len(head):]
+				flush()
+			}
+			switch {
+			case line == "":
+				line = word
+			case term.StringWidth(line)+1+term.StringWidth(word) > room():
+				flush()
+				line = word
+			default:
+				line += " " + word
+			}
+		}
+	}
+	flush()
+	return lines
+}
+
+// printWrapped prints label and text wrapped to the terminal, continuing under indent.
+func printWrapped(label, text, indent string) {
+	for _, l := range Wrap(label+text, textWidth(), indent) {
+		Println(l)
+	}
+}
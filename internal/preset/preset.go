@@ -0,0 +1,306 @@
+// Package preset installs shared run specs ("presets") fetched from a URL or a
+// git repository, so teams can distribute standard generation configs through
+// version control.
+package preset
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/config"
+	"github.com/wiro-ai/wiro-cli/internal/runspec"
+)
+
+// maxPresetBytes caps a downloaded preset file.
+const maxPresetBytes = 1 << 20
+
+// originPrefix starts the comment line that records where a preset came from.
+const originPrefix = "# wiro preset imported from "
+
+// ErrExists is returned when installing over a preset without force.
+var ErrExists = errors.New("preset already exists")
+
+var (
+	repoShorthand = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+	validName     = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+)
+
+// File is one fetched preset before it is installed.
+type File struct {
+	Name   string
+	Origin string
+	Data   []byte
+}
+
+// Preset is an installed preset.
+type Preset struct {
+	Name   string `json:"name"`
+	Model  string `json:"model"`
+	Origin string `json:"origin,omitempty"`
+	Path   string `json:"path"`
+}
+
+// Dir returns <config>/presets.
+func Dir() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "presets"), nil
+}
+
+// RepoURL returns the git URL for src, or "" when src is a single file URL.
+// owner/repo is shorthand for a GitHub repository.
+func RepoURL(src string) string {
+	switch {
+	case repoShorthand.MatchString(src):
+		return "https://github.com/" + strings.TrimSuffix(src, ".git") + ".git"
+	case strings.HasPrefix(src, "git@"), strings.HasSuffix(src, ".git"):
+		return src
+	case strings.HasPrefix(src, "http://"), strings.HasPrefix(src, "https://"):
+		if u, err := url.Parse(src); err == nil && isPresetFile(u.Path) {
+			return ""
+		}
+		return src
+	}
+	return ""
+}
+
+// Fetch downloads the presets at src: a YAML file URL, or a git repository whose
+// presets/ directory (or root, when it has none) holds YAML specs. ref picks a
+// branch or tag of a repository.
+func Fetch(ctx context.Context, src, ref string) ([]File, error) {
+	repo := RepoURL(src)
+	if repo == "" {
+		if ref != "" {
+			return nil, errors.New("--ref only applies to git repositories")
+		}
+		if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+			return nil, fmt.Errorf("%q is not a URL or owner/repo", src)
+		}
+		f, err := fetchURL(ctx, src)
+		if err != nil {
+			return nil, err
+		}
+		return []File{f}, nil
+	}
+	if strings.HasPrefix(ref, "-") {
+		return nil, fmt.Errorf("invalid --ref %q", ref)
+	}
+	tmp, err := os.MkdirTemp("", "wiro-presets-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+	if err := clone(ctx, repo, ref, tmp); err != nil {
+		return nil, err
+	}
+	origin := repo
+	if ref != "" {
+		origin += "@" + ref
+	}
+	return collect(tmp, origin)
+}
+
+func fetchURL(ctx context.Context, rawURL string) (File, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return File{}, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return File{}, fmt.Errorf("fetch %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return File{}, fmt.Errorf("fetch %s: %s", rawURL, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPresetBytes+1))
+	if err != nil {
+		return File{}, fmt.Errorf("fetch %s: %w", rawURL, err)
+	}
+	if len(data) > maxPresetBytes {
+		return File{}, fmt.Errorf("fetch %s: preset is larger than %d bytes", rawURL, maxPresetBytes)
+	}
+	return File{Name: trimExt(path.Base(req.URL.Path)), Origin: rawURL, Data: data}, nil
+}
+
+func clone(ctx context.Context, repo, ref, dir string) error {
+	args := []string{"clone", "--depth", "1", "--quiet"}
+	if ref != "" {
+		args = append(args, "--branch", ref)
+	}
+	// "--" keeps a repository URL that starts with "-" from being read as an option.
+	cmd := exec.CommandContext(ctx, "git", append(args, "--", repo, dir)...)
+	// Fail instead of waiting for credentials on a private repository.
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("git clone %s: %w: %s", repo, err, msg)
+		}
+		return fmt.Errorf("git clone %s: %w", repo, err)
+	}
+	return nil
+}
+
+// collect reads the YAML files under root/presets, or root itself when there is
+// no presets directory. Nested files are named by their path, dir-name.
+func collect(root, origin string) ([]File, error) {
+	base := filepath.Join(root, "presets")
+	if info, err := os.Stat(base); err != nil || !info.IsDir() {
+		base = root
+	}
+	var files []File
+	err := filepath.WalkDir(base, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != base && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !isPresetFile(path) {
+			return nil
+		}
+		rel, err := filepath.Rel(base, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		name := strings.ReplaceAll(trimExt(filepath.ToSlash(rel)), "/", "-")
+		files = append(files, File{Name: name, Origin: origin + ":" + filepath.ToSlash(rel), Data: data})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no .yaml presets found in %s", origin)
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+	return files, nil
+}
+
+// Validate checks f against the run spec schema. Shared presets must reference
+// input files by URL, since local paths only exist on the author's machine.
+func Validate(f File) (*runspec.Spec, error) {
+	if !validName.MatchString(f.Name) {
+		return nil, fmt.Errorf("preset name %q may only contain letters, digits, '.', '_', and '-'", f.Name)
+	}
+	spec, err := runspec.Check(f.Data, f.Name)
+	if err != nil {
+		return nil, err
+	}
+	for field, files := range spec.Files {
+		for _, file := range files {
+			if file.Path != "" {
+				return nil, fmt.Errorf("spec %s: file %q uses local path %s; shared presets must use a url (see `wiro upload`)", f.Name, field, file.Path)
+			}
+		}
+	}
+	return spec, nil
+}
+
+// Install writes f to dir/<name>.yaml, recording its origin. An existing preset
+// is kept unless force is set.
+func Install(dir string, f File, force bool) (string, error) {
+	path := filepath.Join(dir, f.Name+".yaml")
+	if _, err := os.Stat(path); err == nil && !force {
+		return "", fmt.Errorf("%w: %s", ErrExists, f.Name)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create preset dir: %w", err)
+	}
+	data := append([]byte(originPrefix+f.Origin+"\n"), f.Data...)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// Save writes spec as the preset name, for presets made on this machine rather
+// than imported. An existing preset is kept unless force is set.
+func Save(dir, name string, spec runspec.Spec, force bool) (string, error) {
+	if !validName.MatchString(name) {
+		return "", fmt.Errorf("preset name %q may only contain letters, digits, '.', '_', and '-'", name)
+	}
+	path := filepath.Join(dir, name+".yaml")
+	if _, err := os.Stat(path); err == nil && !force {
+		return "", fmt.Errorf("%w: %s", ErrExists, name)
+	}
+	if err := runspec.Save(path, spec); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// List returns the installed presets sorted by name. Unreadable files are listed
+// with an empty model so they can still be found and removed.
+func List(dir string) ([]Preset, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []Preset
+	for _, e := range entries {
+		if e.IsDir() || !isPresetFile(e.Name()) {
+			continue
+		}
+		p := Preset{Name: trimExt(e.Name()), Path: filepath.Join(dir, e.Name())}
+		if data, err := os.ReadFile(p.Path); err == nil {
+			if first, _, _ := strings.Cut(string(data), "\n"); strings.HasPrefix(first, originPrefix) {
+				p.Origin = strings.TrimPrefix(first, originPrefix)
+			}
+			if spec, err := runspec.Parse(data, p.Name); err == nil {
+				p.Model = spec.Model
+			}
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// Path returns the file of the installed preset name.
+func Path(dir, name string) (string, error) {
+	if !validName.MatchString(name) {
+		return "", fmt.Errorf("invalid preset name %q", name)
+	}
+	for _, ext := range []string{".yaml", ".yml"} {
+		path := filepath.Join(dir, name+ext)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("preset %q is not installed; see `wiro preset ls`", name)
+}
+
+func isPresetFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+func trimExt(name string) string {
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
@@ -0,0 +1,161 @@
+package preset
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/runspec"
+)
+
+const portrait = `specVersion: 1
+model: wiro/portrait
+params:
+  prompt: a lighthouse
+  steps: 30
+files:
+  image:
+    - url: https://example.com/ref.png
+`
+
+func TestRepoURL(t *testing.T) {
+	cases := map[string]string{
+		"acme/presets":                      "https://github.com/acme/presets.git",
+		"git@github.com:acme/presets.git":   "git@github.com:acme/presets.git",
+		"https://git.example.com/team/p":    "https://git.example.com/team/p",
+		"https://example.com/a/fox.yaml":    "",
+		"https://example.com/a/fox.yml?x=1": "",
+	}
+	for src, want := range cases {
+		if got := RepoURL(src); got != want {
+			t.Errorf("RepoURL(%q) = %q, want %q", src, got, want)
+		}
+	}
+}
+
+func TestFetchRejectsOptionRef(t *testing.T) {
+	_, err := Fetch(context.Background(), "acme/presets", "--upload-pack=touch pwned")
+	if err == nil || !strings.Contains(err.Error(), "invalid --ref") {
+		t.Fatalf("err = %v, want an invalid --ref error", err)
+	}
+}
+
+func TestFetchURLAndInstall(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(portrait))
+	}))
+	defer srv.Close()
+
+	files, err := Fetch(context.Background(), srv.URL+"/presets/portrait.yaml", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Name != "portrait" {
+		t.Fatalf("files = %+v", files)
+	}
+	spec, err := Validate(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.Model != "wiro/portrait" {
+		t.Fatalf("model = %q", spec.Model)
+	}
+
+	dir := t.TempDir()
+	if _, err := Install(dir, files[0], false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Install(dir, files[0], false); !errors.Is(err, ErrExists) {
+		t.Fatalf("second install err = %v, want ErrExists", err)
+	}
+	list, err := List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Model != "wiro/portrait" || list[0].Origin != srv.URL+"/presets/portrait.yaml" {
+		t.Fatalf("list = %+v", list)
+	}
+	if _, err := Path(dir, "portrait"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Path(dir, "../config"); err == nil {
+		t.Fatal("Path accepted a name outside the preset dir")
+	}
+}
+
+func TestSave(t *testing.T) {
+	dir := t.TempDir()
+	spec := runspec.Spec{SpecVersion: runspec.CurrentVersion, Model: "wiro/portrait", Params: map[string]interface{}{"prompt": "a fox"}}
+	if _, err := Save(dir, "fox", spec, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Save(dir, "fox", spec, false); !errors.Is(err, ErrExists) {
+		t.Fatalf("second save err = %v, want ErrExists", err)
+	}
+	if _, err := Save(dir, "../fox", spec, true); err == nil {
+		t.Fatal("Save accepted a name outside the preset dir")
+	}
+	list, err := List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].Model != "wiro/portrait" || list[0].Origin != "" {
+		t.Fatalf("list = %+v", list)
+	}
+}
+
+func TestCollectUsesPresetsDir(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, body string) {
+		t.Helper()
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("presets/portrait.yaml", portrait)
+	write("presets/video/fast.yml", portrait)
+	write("presets/README.md", "docs")
+	write(".github/workflows/ci.yaml", "on: push")
+	write("other.yaml", portrait)
+
+	files, err := collect(root, "acme/presets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name)
+	}
+	if got := strings.Join(names, ","); got != "portrait,video-fast" {
+		t.Fatalf("names = %s", got)
+	}
+	if files[1].Origin != "acme/presets:video/fast.yml" {
+		t.Fatalf("origin = %q", files[1].Origin)
+	}
+}
+
+func TestValidateRejects(t *testing.T) {
+	cases := map[string]string{
+		"unknown key": "specVersion: 1\nmodel: wiro/x\nsteps: 3\n",
+		"local path":  "specVersion: 1\nmodel: wiro/x\nfiles:\n  image:\n    - path: ref.png\n",
+		"no model":    "specVersion: 1\n",
+		"nested":      "specVersion: 1\nmodel: wiro/x\nparams:\n  size:\n    w: 1\n",
+	}
+	for name, body := range cases {
+		if _, err := Validate(File{Name: "p", Data: []byte(body)}); err == nil {
+			t.Errorf("%s: Validate accepted %q", name, body)
+		}
+	}
+	if _, err := Validate(File{Name: "bad name", Data: []byte(portrait)}); err == nil {
+		t.Error("Validate accepted a name with a space")
+	}
+}
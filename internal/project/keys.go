@@ -0,0 +1,67 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/config"
+)
+
+// NewSecret asks the API to issue another secret for profile's project. The
+// current secret keeps working until it is revoked.
+func (s *Service) NewSecret(ctx context.Context, profile *config.ProjectProfile) (string, error) {
+	headers, err := s.headers(profile)
+	if err != nil {
+		return "", err
+	}
+	var resp api.ProjectSecretResponse
+	if err := s.apiClient.PostJSON(ctx, "/Project/SecretCreate", map[string]interface{}{"apikey": profile.APIKey}, headers, &resp); err != nil {
+		return "", err
+	}
+	if !resp.Result && len(resp.Errors) > 0 {
+		return "", fmt.Errorf("create secret: %w", api.ResponseError(resp.Errors))
+	}
+	if strings.TrimSpace(resp.APISecret) == "" {
+		return "", errors.New("create secret: the API returned no secret")
+	}
+	return strings.TrimSpace(resp.APISecret), nil
+}
+
+// VerifySecret makes a request signed with apiSecret and checks that the API
+// accepts it for profile's project.
+func (s *Service) VerifySecret(ctx context.Context, profile *config.ProjectProfile, apiSecret string) error {
+	headers := s.authSvc.SignatureHeaders(profile.APIKey, apiSecret)
+	var resp api.ProjectListResponse
+	if err := s.apiClient.PostJSON(ctx, "/Project/List", map[string]interface{}{"uuid": "me", "apikey": profile.APIKey}, headers, &resp); err != nil {
+		return fmt.Errorf("signed test request: %w", err)
+	}
+	for _, p := range resp.Projects {
+		if p.APIKey == profile.APIKey {
+			return nil
+		}
+	}
+	if len(resp.Errors) > 0 {
+		return fmt.Errorf("signed test request: %w", api.ResponseError(resp.Errors))
+	}
+	return errors.New("signed test request: the project was not returned")
+}
+
+// RevokeSecret invalidates apiSecret for profile's project.
+func (s *Service) RevokeSecret(ctx context.Context, profile *config.ProjectProfile, apiSecret string) error {
+	headers, err := s.headers(profile)
+	if err != nil {
+		return err
+	}
+	var resp api.GenericResponse
+	body := map[string]interface{}{"apikey": profile.APIKey, "apisecret": apiSecret}
+	if err := s.apiClient.PostJSON(ctx, "/Project/SecretRevoke", body, headers, &resp); err != nil {
+		return err
+	}
+	if !resp.Result && len(resp.Errors) > 0 {
+		return fmt.Errorf("revoke secret: %w", api.ResponseError(resp.Errors))
+	}
+	return nil
+}
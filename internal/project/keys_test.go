@@ -0,0 +1,88 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/auth"
+	"github.com/wiro-ai/wiro-cli/internal/config"
+)
+
+type secretStore map[string]string
+
+func (s secretStore) SetBearerToken(string, string) error   { return nil }
+func (s secretStore) GetBearerToken(string) (string, error) { return "", errors.New("none") }
+func (s secretStore) DeleteBearerToken(string) error        { return nil }
+func (s secretStore) DeleteProjectSecret(k string) error    { delete(s, k); return nil }
+func (s secretStore) SetProjectSecret(k, v string) error    { s[k] = v; return nil }
+func (s secretStore) GetProjectSecret(k string) (string, error) {
+	if v, ok := s[k]; ok {
+		return v, nil
+	}
+	return "", errors.New("not found")
+}
+
+func TestRotateSecret(t *testing.T) {
+	valid := map[string]bool{"old-secret": true}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		key, nonce := r.Header.Get("x-api-key"), r.Header.Get("x-nonce")
+		signed := false
+		for secret, ok := range valid {
+			if ok && r.Header.Get("x-signature") == auth.ComputeSignature(key, secret, nonce) {
+				signed = true
+			}
+		}
+		if !signed {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/Project/SecretCreate":
+			valid["new-secret"] = true
+			_ = json.NewEncoder(w).Encode(api.ProjectSecretResponse{GenericResponse: api.GenericResponse{Result: true}, APISecret: "new-secret"})
+		case "/Project/SecretRevoke":
+			valid[body["apisecret"]] = false
+			_ = json.NewEncoder(w).Encode(api.GenericResponse{Result: true})
+		case "/Project/List":
+			_ = json.NewEncoder(w).Encode(api.ProjectListResponse{GenericResponse: api.GenericResponse{Result: true}, Projects: []api.Project{{APIKey: key}}})
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv(auth.EnvAPIKey, "")
+	t.Setenv(auth.EnvAPISecret, "")
+	t.Setenv(auth.EnvToken, "")
+	client := api.NewClient(srv.URL)
+	store := secretStore{"key-1": "old-secret"}
+	authSvc := auth.NewServiceWithStore(client, store)
+	svc := NewService(client, authSvc)
+	profile := &config.ProjectProfile{Name: "team", APIKey: "key-1", AuthMethodHint: "signature"}
+	ctx := context.Background()
+
+	secret, err := svc.NewSecret(ctx, profile)
+	if err != nil || secret != "new-secret" {
+		t.Fatalf("NewSecret = %q, %v", secret, err)
+	}
+	if err := svc.VerifySecret(ctx, profile, secret); err != nil {
+		t.Fatalf("VerifySecret(new) = %v", err)
+	}
+	if err := svc.VerifySecret(ctx, profile, "wrong"); err == nil {
+		t.Fatal("VerifySecret accepted a wrong secret")
+	}
+	if err := authSvc.SaveProjectSecret(profile.APIKey, secret); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.RevokeSecret(ctx, profile, "old-secret"); err != nil {
+		t.Fatalf("RevokeSecret = %v", err)
+	}
+	if err := svc.VerifySecret(ctx, profile, "old-secret"); err == nil {
+		t.Fatal("old secret still verifies after revocation")
+	}
+}
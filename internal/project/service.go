@@ -21,23 +21,38 @@ func NewService(apiClient *api.Client, authSvc *auth.Service) *Service {
 	return &Service{apiClient: apiClient, authSvc: authSvc}
 }
 
-// ListHybrid loads projects from account token first, then falls back to local profile-based calls.
+// ListHybrid loads projects from account tokens first, then falls back to
+// local profile-based calls. The active account lists all of its projects;
+// another account only those local profiles are bound to.
 func (s *Service) ListHybrid(ctx context.Context, cfg config.Config) ([]api.Project, error) {
 	projects := make([]api.Project, 0)
 	seen := map[string]struct{}{}
+	add := func(p api.Project) {
+		if _, ok := seen[p.APIKey]; ok {
+			return
+		}
+		seen[p.APIKey] = struct{}{}
+		projects = append(projects, p)
+	}
 
-	// Priority 1: account token
-	if token := s.authSvc.LoadBearerToken(); token != "" {
-		var resp api.ProjectListResponse
-		headers := map[string]string{"Authorization": "Bearer " + token}
-		err := s.apiClient.PostJSON(ctx, "/Project/List", map[string]interface{}{"uuid": "me", "apikey": ""}, headers, &resp)
-		if err == nil && len(resp.Projects) > 0 {
-			for _, p := range resp.Projects {
-				if _, ok := seen[p.APIKey]; ok {
-					continue
-				}
-				seen[p.APIKey] = struct{}{}
-				projects = append(projects, p)
+	// Priority 1: account tokens
+	for _, p := range s.accountProjects(ctx, s.authSvc.LoadBearerToken()) {
+		add(p)
+	}
+	bound := map[string][]api.Project{}
+	for i := range cfg.Projects {
+		profile := &cfg.Projects[i]
+		if strings.TrimSpace(profile.Account) == "" {
+			continue
+		}
+		list, ok := bound[profile.Account]
+		if !ok {
+			list = s.accountProjects(ctx, s.authSvc.BearerTokenFor(profile))
+			bound[profile.Account] = list
+		}
+		for _, p := range list {
+			if p.APIKey == profile.APIKey {
+				add(p)
 			}
 		}
 	}
@@ -79,6 +94,20 @@ func (s *Service) ListHybrid(ctx context.Context, cfg config.Config) ([]api.Proj
 	return projects, nil
 }
 
+// accountProjects lists the projects of the account token belongs to; errors
+// are left to the local fallback.
+func (s *Service) accountProjects(ctx context.Context, token string) []api.Project {
+	if token == "" {
+		return nil
+	}
+	var resp api.ProjectListResponse
+	headers := map[string]string{"Authorization": "Bearer " + token}
+	if err := s.apiClient.PostJSON(ctx, "/Project/List", map[string]interface{}{"uuid": "me", "apikey": ""}, headers, &resp); err != nil {
+		return nil
+	}
+	return resp.Projects
+}
+
 // ResolveSelected returns explicit project or default project from config.
 func ResolveSelected(cfg config.Config, selector string) *config.ProjectProfile {
 	if strings.TrimSpace(selector) != "" {
@@ -0,0 +1,134 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/config"
+)
+
+// publicIPURL returns the caller's public IP as plain text.
+var publicIPURL = "https://api.ipify.org"
+
+// Whitelist returns the IP whitelist of profile's project; empty means any IP is allowed.
+func (s *Service) Whitelist(ctx context.Context, profile *config.ProjectProfile) ([]string, error) {
+	p, err := s.find(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	return p.IPWhitelist, nil
+}
+
+// SetWhitelist replaces the IP whitelist of profile's project.
+func (s *Service) SetWhitelist(ctx context.Context, profile *config.ProjectProfile, list []string) error {
+	headers, err := s.headers(profile)
+	if err != nil {
+		return err
+	}
+	if list == nil {
+		list = []string{}
+	}
+	var resp api.GenericResponse
+	body := map[string]interface{}{"apikey": profile.APIKey, "ipwhitelist": list}
+	if err := s.apiClient.PostJSON(ctx, "/Project/Update", body, headers, &resp); err != nil {
+		return err
+	}
+	if !resp.Result && len(resp.Errors) > 0 {
+		return fmt.Errorf("update whitelist: %w", api.ResponseError(resp.Errors))
+	}
+	return nil
+}
+
+func (s *Service) find(ctx context.Context, profile *config.ProjectProfile) (api.Project, error) {
+	headers, err := s.headers(profile)
+	if err != nil {
+		return api.Project{}, err
+	}
+	var resp api.ProjectListResponse
+	if err := s.apiClient.PostJSON(ctx, "/Project/List", map[string]interface{}{"uuid": "me", "apikey": profile.APIKey}, headers, &resp); err != nil {
+		return api.Project{}, err
+	}
+	for _, p := range resp.Projects {
+		if p.APIKey == profile.APIKey {
+			return p, nil
+		}
+	}
+	return api.Project{}, fmt.Errorf("project %s not found on the account", profile.APIKey)
+}
+
+// headers prefers the token of the project's account, which can manage every
+// project, over project keys.
+func (s *Service) headers(profile *config.ProjectProfile) (map[string]string, error) {
+	if token := s.authSvc.BearerTokenFor(profile); token != "" {
+		return map[string]string{"Authorization": "Bearer " + token}, nil
+	}
+	res, err := s.authSvc.BuildHeaders(profile)
+	if err != nil {
+		return nil, err
+	}
+	return res.Headers, nil
+}
+
+// NormalizeCIDR validates an IP or CIDR entry; a bare IP is returned unchanged
+// and a CIDR is returned in canonical network form.
+func NormalizeCIDR(entry string) (string, error) {
+	entry = strings.TrimSpace(entry)
+	if ip := net.ParseIP(entry); ip != nil {
+		return ip.String(), nil
+	}
+	_, network, err := net.ParseCIDR(entry)
+	if err != nil {
+		return "", fmt.Errorf("invalid IP or CIDR %q", entry)
+	}
+	return network.String(), nil
+}
+
+// Allows reports whether ip may call the API under list. An empty list allows everyone.
+func Allows(list []string, ip net.IP) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, entry := range list {
+		if other := net.ParseIP(strings.TrimSpace(entry)); other != nil {
+			if other.Equal(ip) {
+				return true
+			}
+			continue
+		}
+		if _, network, err := net.ParseCIDR(strings.TrimSpace(entry)); err == nil && network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// PublicIP asks an external service for the caller's public IP.
+func PublicIP(ctx context.Context) (net.IP, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, publicIPURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("public ip lookup: http %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 256))
+	if err != nil {
+		return nil, err
+	}
+	ip := net.ParseIP(strings.TrimSpace(string(body)))
+	if ip == nil {
+		return nil, errors.New("public ip lookup returned no address")
+	}
+	return ip, nil
+}
@@ -0,0 +1,40 @@
+package project
+
+import (
+	"net"
+	"testing"
+)
+
+func TestNormalizeCIDR(t *testing.T) {
+	cases := map[string]string{
+		"203.0.113.7":     "203.0.113.7",
+		" 10.1.2.3/8 ":    "10.0.0.0/8",
+		"2001:db8::1/64":  "2001:db8::/64",
+		"198.51.100.0/24": "198.51.100.0/24",
+	}
+	for in, want := range cases {
+		got, err := NormalizeCIDR(in)
+		if err != nil || got != want {
+			t.Errorf("NormalizeCIDR(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := NormalizeCIDR("not-an-ip"); err == nil {
+		t.Fatal("expected error for invalid entry")
+	}
+}
+
+func TestAllows(t *testing.T) {
+	ip := net.ParseIP("198.51.100.20")
+	if !Allows(nil, ip) {
+		t.Fatal("empty whitelist should allow every IP")
+	}
+	if !Allows([]string{"10.0.0.1", "198.51.100.0/24"}, ip) {
+		t.Fatal("CIDR should cover ip")
+	}
+	if !Allows([]string{"198.51.100.20"}, ip) {
+		t.Fatal("exact IP should match")
+	}
+	if Allows([]string{"10.0.0.0/8"}, ip) {
+		t.Fatal("ip outside whitelist should be blocked")
+	}
+}
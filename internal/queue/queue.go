@@ -0,0 +1,310 @@
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/gitinfo"
+)
+
+// Status is the lifecycle state of a queued job.
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusRunning Status = "running"
+	StatusDone    Status = "done"
+	StatusFailed  Status = "failed"
+)
+
+// Job is one queued run with everything needed to submit it non-interactively.
+type Job struct {
+	ID        string   `json:"id"`
+	Owner     string   `json:"owner"`
+	Model     string   `json:"model"`
+	Project   string   `json:"project,omitempty"`
+	Set       []string `json:"set,omitempty"`
+	SetFile   []string `json:"setFile,omitempty"`
+	SetURL    []string `json:"setUrl,omitempty"`
+	OutputDir string   `json:"outputDir"`
+	// OutputIndex and OutputMatch limit which outputs are downloaded.
+	OutputIndex []int    `json:"outputIndex,omitempty"`
+	OutputMatch []string `json:"outputMatch,omitempty"`
+	Status      Status   `json:"status"`
+	TaskID      string   `json:"taskId,omitempty"`
+	TaskToken   string   `json:"taskToken,omitempty"`
+	Outputs     []string `json:"outputs,omitempty"`
+	Error       string   `json:"error,omitempty"`
+	CreatedAt   string   `json:"createdAt"`
+	UpdatedAt   string   `json:"updatedAt"`
+	// Git is the code state captured when the job was added (--git-context).
+	Git *gitinfo.Info `json:"git,omitempty"`
+	// SecretFields are masked in history and the manifest (--secret-field).
+	// The queue file itself keeps the values, since they are still to be submitted.
+	SecretFields []string `json:"secretFields,omitempty"`
+	// NameTemplate lays out the downloaded files (--name-template); empty uses
+	// preferences.outputNameTemplate when the job runs.
+	NameTemplate string `json:"nameTemplate,omitempty"`
+	// Workspace is what {workspace} expands to in the layout, pinned when the
+	// job was added since workers may run from another directory.
+	Workspace string `json:"workspace,omitempty"`
+	// RequireGPU and Priority are the scheduling hints of `wiro run --detach`.
+	RequireGPU string `json:"requireGpu,omitempty"`
+	Priority   string `json:"priority,omitempty"`
+	// Timeout limits watching, as a Go duration ("0s" = no limit); empty uses
+	// the worker's --timeout.
+	Timeout string `json:"timeout,omitempty"`
+	// Dedupe reuses identical downloaded files; nil uses preferences.dedupeOutputs.
+	Dedupe *bool `json:"dedupe,omitempty"`
+	// Reupload and NoBudgetCheck carry the `wiro run` flags of the same name.
+	Reupload      bool `json:"reupload,omitempty"`
+	NoBudgetCheck bool `json:"noBudgetCheck,omitempty"`
+}
+
+// Queue is the persisted queue document.
+type Queue struct {
+	NextID int   `json:"nextId"`
+	Jobs   []Job `json:"jobs"`
+}
+
+var (
+	// updateLockStaleAfter treats an update lock left by a crashed process as
+	// abandoned; an update only holds it for one read and write.
+	updateLockStaleAfter = 10 * time.Second
+	// updateLockPollInterval is how often a waiting update retries.
+	updateLockPollInterval = 10 * time.Millisecond
+)
+
+// Store persists the queue as JSON. Updates are serialized in the process by
+// a mutex and across processes by a lock file next to the queue.
+type Store struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewStore returns a store backed by <dir>/queue.json.
+func NewStore(dir string) *Store {
+	return &Store{path: filepath.Join(dir, "queue.json")}
+}
+
+// NewStoreAt returns a store backed by the file at path, for job lists kept
+// apart from the queue such as the daemon's.
+func NewStoreAt(path string) *Store {
+	return &Store{path: path}
+}
+
+// Load reads the queue or returns an empty one if missing.
+func (s *Store) Load() (Queue, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// Update applies fn to the current queue and saves the result atomically.
+func (s *Store) Update(fn func(q *Queue) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	unlock, err := s.lockUpdate()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	q, err := s.load()
+	if err != nil {
+		return err
+	}
+	if err := fn(&q); err != nil {
+		return err
+	}
+	return s.save(q)
+}
+
+// Add appends a pending job and returns it with its assigned id.
+func (s *Store) Add(job Job) (Job, error) {
+	err := s.Update(func(q *Queue) error {
+		q.NextID++
+		now := time.Now().UTC().Format(time.RFC3339)
+		job.ID = strconv.Itoa(q.NextID)
+		job.Status = StatusPending
+		job.CreatedAt = now
+		job.UpdatedAt = now
+		q.Jobs = append(q.Jobs, job)
+		return nil
+	})
+	return job, err
+}
+
+// Claim marks the first pending job as running and returns it.
+func (s *Store) Claim() (*Job, error) {
+	var claimed *Job
+	err := s.Update(func(q *Queue) error {
+		for i := range q.Jobs {
+			if q.Jobs[i].Status != StatusPending {
+				continue
+			}
+			q.Jobs[i].Status = StatusRunning
+			q.Jobs[i].UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+			job := q.Jobs[i]
+			claimed = &job
+			return nil
+		}
+		return nil
+	})
+	return claimed, err
+}
+
+// Set applies fn to the job with id and persists the change.
+func (s *Store) Set(id string, fn func(j *Job)) error {
+	return s.Update(func(q *Queue) error {
+		for i := range q.Jobs {
+			if q.Jobs[i].ID == id {
+				fn(&q.Jobs[i])
+				q.Jobs[i].UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+				return nil
+			}
+		}
+		return fmt.Errorf("queue job %q not found", id)
+	})
+}
+
+// Remove deletes jobs for which match returns true.
+func (s *Store) Remove(match func(j Job) bool) (int, error) {
+	removed := 0
+	err := s.Update(func(q *Queue) error {
+		kept := q.Jobs[:0]
+		for _, j := range q.Jobs {
+			if match(j) {
+				removed++
+				continue
+			}
+			kept = append(kept, j)
+		}
+		q.Jobs = kept
+		return nil
+	})
+	return removed, err
+}
+
+// Recover returns interrupted jobs to a resumable state after a restart.
+// Jobs that already have a task token or id keep running status so they can be re-watched.
+func (s *Store) Recover() ([]Job, error) {
+	var resumable []Job
+	err := s.Update(func(q *Queue) error {
+		for i := range q.Jobs {
+			if q.Jobs[i].Status != StatusRunning {
+				continue
+			}
+			if q.Jobs[i].TaskToken == "" && q.Jobs[i].TaskID == "" {
+				q.Jobs[i].Status = StatusPending
+				continue
+			}
+			resumable = append(resumable, q.Jobs[i])
+		}
+		return nil
+	})
+	return resumable, err
+}
+
+// Counts returns the number of jobs per status.
+func (q Queue) Counts() map[Status]int {
+	out := map[Status]int{}
+	for _, j := range q.Jobs {
+		out[j.Status]++
+	}
+	return out
+}
+
+// Lock prevents two `queue start` processes from working the same queue.
+func (s *Store) Lock() (func(), error) {
+	lockPath := s.path + ".lock"
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o755); err != nil {
+		return nil, fmt.Errorf("create queue dir: %w", err)
+	}
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("queue is already being processed (remove %s if no other `wiro queue start` is running)", lockPath)
+		}
+		return nil, fmt.Errorf("create queue lock: %w", err)
+	}
+	fmt.Fprintf(f, "%d\n", os.Getpid())
+	f.Close()
+	return func() { _ = os.Remove(lockPath) }, nil
+}
+
+// lockUpdate takes the cross-process update lock, so another process's
+// read-modify-write cannot interleave with this one and lose its change.
+func (s *Store) lockUpdate() (func(), error) {
+	lockPath := s.path + ".update.lock"
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0o755); err != nil {
+		return nil, fmt.Errorf("create queue dir: %w", err)
+	}
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("create queue update lock: %w", err)
+		}
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > updateLockStaleAfter {
+			_ = os.Remove(lockPath)
+			continue
+		}
+		time.Sleep(updateLockPollInterval)
+	}
+}
+
+func (s *Store) load() (Queue, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return Queue{Jobs: []Job{}}, nil
+		}
+		return Queue{}, fmt.Errorf("read queue: %w", err)
+	}
+	var q Queue
+	if err := json.Unmarshal(data, &q); err != nil {
+		return Queue{}, fmt.Errorf("parse queue json: %w", err)
+	}
+	return q, nil
+}
+
+func (s *Store) save(q Queue) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("create queue dir: %w", err)
+	}
+	data, err := json.MarshalIndent(q, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal queue: %w", err)
+	}
+	// A unique temp file in the same directory, so the rename is atomic and
+	// readers see either the old queue or the new one.
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create tmp queue: %w", err)
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("write tmp queue: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("write tmp queue: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("rename tmp queue: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package queue
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestStore_ClaimAndRecover(t *testing.T) {
+	store := NewStore(t.TempDir())
+	first, err := store.Add(Job{Owner: "o", Model: "a"})
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	second, err := store.Add(Job{Owner: "o", Model: "b"})
+	if err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	if first.ID != "1" || second.ID != "2" {
+		t.Fatalf("unexpected ids: %s %s", first.ID, second.ID)
+	}
+
+	claimed, err := store.Claim()
+	if err != nil || claimed == nil || claimed.ID != "1" {
+		t.Fatalf("claim: %#v %v", claimed, err)
+	}
+	if err := store.Set("1", func(j *Job) { j.TaskToken = "tok" }); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if _, err := store.Claim(); err != nil {
+		t.Fatalf("claim second: %v", err)
+	}
+
+	// Simulate a restart: job 1 was submitted and should be resumed, job 2 was not.
+	resumable, err := store.Recover()
+	if err != nil {
+		t.Fatalf("recover: %v", err)
+	}
+	if len(resumable) != 1 || resumable[0].ID != "1" {
+		t.Fatalf("unexpected resumable jobs: %#v", resumable)
+	}
+	q, err := store.Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if q.Jobs[1].Status != StatusPending {
+		t.Fatalf("unsubmitted job should return to pending, got %s", q.Jobs[1].Status)
+	}
+}
+
+func TestStore_UpdatesFromSeparateStoresDoNotLoseJobs(t *testing.T) {
+	// Each store stands in for another process: they share only the file.
+	path := filepath.Join(t.TempDir(), "queue.json")
+	const stores, perStore = 4, 25
+	var wg sync.WaitGroup
+	errs := make(chan error, stores*perStore)
+	for i := 0; i < stores; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			store := NewStoreAt(path)
+			for j := 0; j < perStore; j++ {
+				if _, err := store.Add(Job{Owner: "o", Model: "m"}); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("add: %v", err)
+	}
+	q, err := NewStoreAt(path).Load()
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	ids := map[string]bool{}
+	for _, j := range q.Jobs {
+		ids[j.ID] = true
+	}
+	if len(q.Jobs) != stores*perStore || len(ids) != stores*perStore || q.NextID != stores*perStore {
+		t.Fatalf("got %d jobs with %d distinct ids, nextId %d; want %d", len(q.Jobs), len(ids), q.NextID, stores*perStore)
+	}
+	if matches, _ := filepath.Glob(path + "*.tmp"); len(matches) != 0 {
+		t.Fatalf("temp files left behind: %v", matches)
+	}
+}
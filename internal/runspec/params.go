@@ -0,0 +1,87 @@
+package runspec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/yaml"
+)
+
+// Prefixes that mark parameter file values as file inputs.
+const (
+	filePrefix = "file:"
+	urlPrefix  = "url:"
+)
+
+// LoadParams reads a parameter file (--set-json / --set-yaml): a mapping from
+// field id to a scalar or a list of scalars. A string starting with file: is a
+// local file input, resolved against the parameter file's directory, and one
+// starting with url: is a file input by URL. format is "json" or "yaml".
+func LoadParams(path, format string) (set, setFile, setURL []string, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return ParseParams(data, format, path, filepath.Dir(path))
+}
+
+// ParseParams is LoadParams for data already read; name labels errors.
+func ParseParams(data []byte, format, name, baseDir string) (set, setFile, setURL []string, err error) {
+	var doc interface{}
+	switch format {
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		// Keep numbers as written, so 1000000 does not become 1e+06.
+		dec.UseNumber()
+		err = dec.Decode(&doc)
+	case "yaml":
+		doc, err = yaml.Parse(data)
+	default:
+		return nil, nil, nil, fmt.Errorf("unknown parameter file format %q", format)
+	}
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("parse %s: %w", name, err)
+	}
+	params, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("%s: expected a mapping of field ids to values", name)
+	}
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		values, err := paramValues(params[k])
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%s: field %q: %w", name, k, err)
+		}
+		for _, v := range values {
+			switch {
+			case strings.HasPrefix(v, filePrefix):
+				path := filepath.FromSlash(strings.TrimSpace(strings.TrimPrefix(v, filePrefix)))
+				if path == "" {
+					return nil, nil, nil, fmt.Errorf("%s: field %q: file: needs a path", name, k)
+				}
+				if !filepath.IsAbs(path) {
+					path = filepath.Join(baseDir, path)
+				}
+				setFile = append(setFile, k+"="+path)
+			case strings.HasPrefix(v, urlPrefix):
+				u := strings.TrimSpace(strings.TrimPrefix(v, urlPrefix))
+				if u == "" {
+					return nil, nil, nil, fmt.Errorf("%s: field %q: url: needs a URL", name, k)
+				}
+				setURL = append(setURL, k+"="+u)
+			default:
+				set = append(set, k+"="+v)
+			}
+		}
+	}
+	return set, setFile, setURL, nil
+}
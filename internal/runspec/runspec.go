@@ -0,0 +1,322 @@
+// Package runspec reads and writes reproducible run specifications: a model
+// slug, every parameter value, and input files pinned by SHA256, stored as YAML.
+package runspec
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/yaml"
+)
+
+// CurrentVersion is the spec format written by this build.
+const CurrentVersion = 1
+
+// ErrFileChanged is returned when a referenced file no longer matches its recorded hash.
+var ErrFileChanged = errors.New("input file changed since the spec was exported")
+
+// Spec is a fully resolved run.
+type Spec struct {
+	SpecVersion int    `json:"specVersion"`
+	CLIVersion  string `json:"cliVersion,omitempty"`
+	CreatedAt   string `json:"createdAt,omitempty"`
+	// Model is owner/slug.
+	Model string `json:"model"`
+	// Params holds non-file fields. Values are strings, or lists for repeated fields;
+	// hand-written numbers and booleans are accepted too.
+	Params map[string]interface{} `json:"params,omitempty"`
+	// Files holds file inputs by field id, in order.
+	Files map[string][]File `json:"files,omitempty"`
+}
+
+// File is one file input: a local path pinned by hash, or a URL.
+type File struct {
+	// Path is relative to the spec file when it was inside the spec's directory tree.
+	Path   string `json:"path,omitempty"`
+	SHA256 string `json:"sha256,omitempty"`
+	Size   int64  `json:"size,omitempty"`
+	URL    string `json:"url,omitempty"`
+}
+
+// Build records inputs as a spec. fileFields names the fields that take files;
+// their URL values are kept as URLs. Local paths are hashed and stored relative
+// to baseDir when they are inside it.
+func Build(model string, inputs map[string][]api.MultipartValue, fileFields map[string]bool, baseDir, cliVersion string) (Spec, error) {
+	s := Spec{
+		SpecVersion: CurrentVersion,
+		CLIVersion:  cliVersion,
+		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+		Model:       model,
+		Params:      map[string]interface{}{},
+		Files:       map[string][]File{},
+	}
+	keys := make([]string, 0, len(inputs))
+	for k := range inputs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		values := inputs[k]
+		if len(values) == 0 {
+			continue
+		}
+		if !fileFields[k] && values[0].FilePath == "" {
+			if len(values) == 1 {
+				s.Params[k] = values[0].Value
+				continue
+			}
+			list := make([]string, 0, len(values))
+			for _, v := range values {
+				list = append(list, v.Value)
+			}
+			s.Params[k] = list
+			continue
+		}
+		for _, v := range values {
+			if v.FilePath == "" {
+				s.Files[k] = append(s.Files[k], File{URL: v.Value})
+				continue
+			}
+			sum, size, err := HashFile(v.FilePath)
+			if err != nil {
+				return Spec{}, err
+			}
+			s.Files[k] = append(s.Files[k], File{Path: relPath(baseDir, v.FilePath), SHA256: sum, Size: size})
+		}
+	}
+	return s, nil
+}
+
+func relPath(baseDir, p string) string {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return p
+	}
+	base, err := filepath.Abs(baseDir)
+	if err != nil {
+		return abs
+	}
+	rel, err := filepath.Rel(base, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(abs)
+	}
+	return filepath.ToSlash(rel)
+}
+
+// HashFile returns the hex SHA256 and size of the file at path.
+func HashFile(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// Encode renders s as commented YAML.
+func Encode(s Spec) ([]byte, error) {
+	body, err := yaml.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	header := "# wiro run spec: run it with `wiro run --spec <file>`\n"
+	return append([]byte(header), body...), nil
+}
+
+// Save writes s to path.
+func Save(path string, s Spec) error {
+	data, err := Encode(s)
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("create spec dir: %w", err)
+		}
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Load reads and checks the spec at path.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read spec: %w", err)
+	}
+	return Parse(data, path)
+}
+
+// Parse decodes and checks a spec; name labels errors.
+func Parse(data []byte, name string) (*Spec, error) {
+	var s Spec
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse spec %s: %w", name, err)
+	}
+	switch {
+	case s.SpecVersion == 0:
+		return nil, fmt.Errorf("spec %s: missing specVersion", name)
+	case s.SpecVersion > CurrentVersion:
+		return nil, fmt.Errorf("spec %s: specVersion %d is newer than this CLI supports (%d); upgrade wiro", name, s.SpecVersion, CurrentVersion)
+	}
+	if parts := strings.Split(s.Model, "/"); len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("spec %s: model must be owner/model, got %q", name, s.Model)
+	}
+	return &s, nil
+}
+
+// specKeys are the top-level keys a spec may contain.
+var specKeys = map[string]bool{
+	"specVersion": true,
+	"cliVersion":  true,
+	"createdAt":   true,
+	"model":       true,
+	"params":      true,
+	"files":       true,
+}
+
+// Check validates a spec document strictly, for specs shared with others: it
+// rejects unknown keys, nested param values, and files without a path or url,
+// on top of what Parse checks.
+func Check(data []byte, name string) (*Spec, error) {
+	s, err := Parse(data, name)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := yaml.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse spec %s: %w", name, err)
+	}
+	top, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("spec %s: expected a mapping at the top level", name)
+	}
+	for k := range top {
+		if !specKeys[k] {
+			return nil, fmt.Errorf("spec %s: unknown key %q", name, k)
+		}
+	}
+	for k, v := range s.Params {
+		if _, err := paramValues(v); err != nil {
+			return nil, fmt.Errorf("spec %s: param %q: %w", name, k, err)
+		}
+	}
+	for k, files := range s.Files {
+		for _, f := range files {
+			if (f.URL == "") == (f.Path == "") {
+				return nil, fmt.Errorf("spec %s: file %q: set exactly one of path or url", name, k)
+			}
+		}
+	}
+	return s, nil
+}
+
+// Flags converts the spec into --set, --set-file, and --set-url values. Relative
+// paths resolve against baseDir, and every hashed file is checked.
+func (s *Spec) Flags(baseDir string) (set, setFile, setURL []string, err error) {
+	keys := make([]string, 0, len(s.Params))
+	for k := range s.Params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		values, err := paramValues(s.Params[k])
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("param %q: %w", k, err)
+		}
+		for _, v := range values {
+			set = append(set, k+"="+v)
+		}
+	}
+	keys = keys[:0]
+	for k := range s.Files {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, f := range s.Files[k] {
+			switch {
+			case f.URL != "" && f.Path != "":
+				return nil, nil, nil, fmt.Errorf("file %q: set either path or url, not both", k)
+			case f.URL != "":
+				setURL = append(setURL, k+"="+f.URL)
+			case f.Path != "":
+				path := filepath.FromSlash(f.Path)
+				if !filepath.IsAbs(path) {
+					path = filepath.Join(baseDir, path)
+				}
+				if err := verify(k, path, f.SHA256); err != nil {
+					return nil, nil, nil, err
+				}
+				setFile = append(setFile, k+"="+path)
+			default:
+				return nil, nil, nil, fmt.Errorf("file %q: path or url is required", k)
+			}
+		}
+	}
+	return set, setFile, setURL, nil
+}
+
+func verify(field, path, want string) error {
+	sum, _, err := HashFile(path)
+	if err != nil {
+		return fmt.Errorf("file %q: %w", field, err)
+	}
+	if want != "" && !strings.EqualFold(sum, want) {
+		return fmt.Errorf("%w: %s (field %q) has sha256 %s, spec expects %s", ErrFileChanged, path, field, sum, want)
+	}
+	return nil
+}
+
+// paramValues accepts a scalar or a list of scalars.
+func paramValues(v interface{}) ([]string, error) {
+	switch t := v.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		out := make([]string, 0, len(t))
+		for _, item := range t {
+			s, err := scalarString(item)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, s)
+		}
+		return out, nil
+	}
+	s, err := scalarString(v)
+	if err != nil {
+		return nil, err
+	}
+	return []string{s}, nil
+}
+
+func scalarString(v interface{}) (string, error) {
+	switch t := v.(type) {
+	case string:
+		return t, nil
+	case bool:
+		if t {
+			return "true", nil
+		}
+		return "false", nil
+	case float64:
+		return fmt.Sprintf("%v", t), nil
+	case map[string]interface{}:
+		return "", errors.New("nested mappings are not supported")
+	}
+	return fmt.Sprint(v), nil
+}
@@ -0,0 +1,141 @@
+package runspec
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+func TestBuildSaveLoadRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	img := filepath.Join(dir, "inputs", "ref.png")
+	if err := os.MkdirAll(filepath.Dir(img), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(img, []byte("png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	inputs := map[string][]api.MultipartValue{
+		"prompt": {{Value: "a red fox\non snow"}},
+		"steps":  {{Value: "30"}},
+		"tags":   {{Value: "a"}, {Value: "b"}},
+		"image":  {{FilePath: img}},
+		"mask":   {{Value: "https://example.com/mask.png"}},
+	}
+	spec, err := Build("wiro/example", inputs, map[string]bool{"image": true, "mask": true}, dir, "1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := spec.Files["image"][0].Path; got != "inputs/ref.png" {
+		t.Fatalf("image path = %q, want relative", got)
+	}
+	path := filepath.Join(dir, "run.yaml")
+	if err := Save(path, spec); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Model != "wiro/example" || loaded.CLIVersion != "1.2.3" {
+		t.Fatalf("loaded = %+v", loaded)
+	}
+	set, setFile, setURL, err := loaded.Flags(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantSet := []string{"prompt=a red fox\non snow", "steps=30", "tags=a", "tags=b"}
+	if !reflect.DeepEqual(set, wantSet) {
+		t.Fatalf("set = %q, want %q", set, wantSet)
+	}
+	if !reflect.DeepEqual(setFile, []string{"image=" + img}) {
+		t.Fatalf("setFile = %q", setFile)
+	}
+	if !reflect.DeepEqual(setURL, []string{"mask=https://example.com/mask.png"}) {
+		t.Fatalf("setURL = %q", setURL)
+	}
+
+	if err := os.WriteFile(img, []byte("changed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := loaded.Flags(dir); !errors.Is(err, ErrFileChanged) {
+		t.Fatalf("err = %v, want ErrFileChanged", err)
+	}
+}
+
+func TestLoadHandWritten(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run.yaml")
+	doc := "specVersion: 1\nmodel: wiro/example\nparams:\n  steps: 30\n  guidance: 7.5\n  upscale: true\n"
+	if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, _, _, err := spec.Flags(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"guidance=7.5", "steps=30", "upscale=true"}
+	if !reflect.DeepEqual(set, want) {
+		t.Fatalf("set = %q, want %q", set, want)
+	}
+}
+
+func TestLoadRejects(t *testing.T) {
+	cases := map[string]string{
+		"specVersion: 2\nmodel: wiro/example\n": "newer",
+		"model: wiro/example\n":                 "missing specVersion",
+		"specVersion: 1\nmodel: example\n":      "owner/model",
+	}
+	for doc, want := range cases {
+		path := filepath.Join(t.TempDir(), "run.yaml")
+		if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := Load(path)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Load(%q) err = %v, want %q", doc, err, want)
+		}
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	base := filepath.FromSlash("/specs")
+	want := struct{ set, setFile, setURL []string }{
+		set:     []string{"prompt=a cat", "seed=1000000", "tags=a", "tags=b", "upscale=true"},
+		setFile: []string{"image=" + filepath.Join(base, "in.png")},
+		setURL:  []string{"mask=https://cdn.example/m.png"},
+	}
+	docs := map[string]string{
+		"json": `{"prompt": "a cat", "seed": 1000000, "upscale": true, "tags": ["a", "b"],
+			"image": "file:in.png", "mask": "url:https://cdn.example/m.png"}`,
+		"yaml": "prompt: a cat\nseed: 1000000\nupscale: true\ntags:\n  - a\n  - b\nimage: file:in.png\nmask: url:https://cdn.example/m.png\n",
+	}
+	for format, doc := range docs {
+		set, setFile, setURL, err := ParseParams([]byte(doc), format, "params."+format, base)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if !reflect.DeepEqual(set, want.set) || !reflect.DeepEqual(setFile, want.setFile) || !reflect.DeepEqual(setURL, want.setURL) {
+			t.Errorf("%s: got %q %q %q", format, set, setFile, setURL)
+		}
+	}
+
+	for doc, wantErr := range map[string]string{
+		`["a"]`:                "expected a mapping",
+		`{"opts": {"a": 1}}`:   "nested mappings",
+		`{"image": "file:"}`:   "needs a path",
+		`{"prompt": "unclosed`: "parse",
+	} {
+		if _, _, _, err := ParseParams([]byte(doc), "json", "p.json", base); err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("ParseParams(%s) err = %v, want %q", doc, err, wantErr)
+		}
+	}
+}
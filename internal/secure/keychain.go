@@ -20,27 +20,32 @@ var (
 	macKeychainUsable    bool
 )
 
-func bearerKey() string {
-	return "bearer-token"
+// bearerKey names the token of account. The empty account is the token
+// stored before accounts were tracked by email.
+func bearerKey(account string) string {
+	if account == "" {
+		return "bearer-token"
+	}
+	return fmt.Sprintf("account/%s/bearer-token", account)
 }
 
 func projectSecretKey(apiKey string) string {
 	return fmt.Sprintf("project/%s/api-secret", apiKey)
 }
 
-// SetBearerToken stores account bearer token in OS keychain.
-func SetBearerToken(token string) error {
-	return setSecret(bearerKey(), token)
+// SetBearerToken stores the bearer token of account in OS keychain.
+func SetBearerToken(account, token string) error {
+	return setSecret(bearerKey(account), token)
 }
 
-// GetBearerToken reads account bearer token from OS keychain.
-func GetBearerToken() (string, error) {
-	return getSecret(bearerKey())
+// GetBearerToken reads the bearer token of account from OS keychain.
+func GetBearerToken(account string) (string, error) {
+	return getSecret(bearerKey(account))
 }
 
-// DeleteBearerToken deletes account bearer token.
-func DeleteBearerToken() error {
-	return deleteSecret(bearerKey())
+// DeleteBearerToken deletes the bearer token of account.
+func DeleteBearerToken(account string) error {
+	return deleteSecret(bearerKey(account))
 }
 
 // SetProjectSecret stores API secret for a project API key.
@@ -58,6 +63,40 @@ func DeleteProjectSecret(apiKey string) error {
 	return deleteSecret(projectSecretKey(apiKey))
 }
 
+// Backend names where secrets are stored: "macOS keychain" or "file".
+func Backend() string {
+	if shouldUseMacKeychain() {
+		return "macOS keychain"
+	}
+	return "file"
+}
+
+// SecretsPath returns the fallback secrets file used when no keychain is available.
+func SecretsPath() (string, error) {
+	return secretsPath()
+}
+
+// Probe writes, reads back, and deletes a throwaway secret to check that the
+// backend works.
+func Probe() error {
+	const account = "doctor-probe"
+	const value = "ok"
+	if err := setSecret(account, value); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+	got, err := getSecret(account)
+	if err != nil {
+		return fmt.Errorf("read: %w", err)
+	}
+	if got != value {
+		return errors.New("read back a different value")
+	}
+	if err := deleteSecret(account); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+	return nil
+}
+
 func setSecret(account, value string) error {
 	if shouldUseMacKeychain() {
 		if err := macKeychainSet(account, value); err == nil {
@@ -0,0 +1,107 @@
+package secure
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// SealedFormat names the envelope Seal writes.
+const SealedFormat = "wiro-sealed"
+
+// sealIterations is the PBKDF2-HMAC-SHA256 work factor for new envelopes
+// (the OWASP recommendation). Open reads the count from the envelope.
+const sealIterations = 600_000
+
+// minSealIterations rejects envelopes edited down to a weak work factor.
+const minSealIterations = 100_000
+
+// maxSealIterations rejects envelopes whose work factor would stall Open.
+const maxSealIterations = 10_000_000
+
+// ErrWrongPassphrase is returned by Open when the passphrase does not decrypt
+// the envelope, which is also how tampering shows up.
+var ErrWrongPassphrase = errors.New("wrong passphrase, or the file was modified")
+
+// sealed is a passphrase-encrypted payload: AES-256-GCM under a key derived
+// with PBKDF2. The header fields are authenticated along with the ciphertext.
+type sealed struct {
+	Format     string `json:"format"`
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func (s sealed) additionalData() []byte {
+	return fmt.Appendf(nil, "%s/%d/%s/%d", s.Format, s.Version, s.KDF, s.Iterations)
+}
+
+// Seal encrypts plaintext with passphrase and returns the JSON envelope.
+func Seal(plaintext []byte, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, errors.New("passphrase is empty")
+	}
+	s := sealed{Format: SealedFormat, Version: 1, KDF: "pbkdf2-sha256", Iterations: sealIterations, Salt: make([]byte, 16)}
+	if _, err := rand.Read(s.Salt); err != nil {
+		return nil, err
+	}
+	aead, err := s.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	s.Nonce = make([]byte, aead.NonceSize())
+	if _, err := rand.Read(s.Nonce); err != nil {
+		return nil, err
+	}
+	s.Ciphertext = aead.Seal(nil, s.Nonce, plaintext, s.additionalData())
+	return json.MarshalIndent(s, "", "  ")
+}
+
+// Open decrypts an envelope written by Seal.
+func Open(data []byte, passphrase string) ([]byte, error) {
+	var s sealed
+	if err := json.Unmarshal(data, &s); err != nil || s.Format != SealedFormat {
+		return nil, errors.New("not an encrypted wiro export")
+	}
+	if s.Version != 1 || s.KDF != "pbkdf2-sha256" {
+		return nil, fmt.Errorf("unsupported export version %d (%s); update wiro", s.Version, s.KDF)
+	}
+	if s.Iterations < minSealIterations {
+		return nil, fmt.Errorf("export uses only %d key derivation iterations; refusing it", s.Iterations)
+	}
+	if s.Iterations > maxSealIterations {
+		return nil, fmt.Errorf("export asks for %d key derivation iterations (limit %d); refusing it", s.Iterations, maxSealIterations)
+	}
+	aead, err := s.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Nonce) != aead.NonceSize() {
+		return nil, ErrWrongPassphrase
+	}
+	plaintext, err := aead.Open(nil, s.Nonce, s.Ciphertext, s.additionalData())
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plaintext, nil
+}
+
+func (s sealed) aead(passphrase string) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, s.Salt, s.Iterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,47 @@
+package secure
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestSealRoundTrip(t *testing.T) {
+	plain := []byte(`{"projectSecrets":{"key":"s3cret"}}`)
+	data, err := Seal(plain, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("s3cret")) {
+		t.Fatal("envelope contains the plaintext")
+	}
+	got, err := Open(data, "correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, plain) {
+		t.Fatalf("Open = %s", got)
+	}
+	if _, err := Open(data, "wrong horse"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("wrong passphrase err = %v", err)
+	}
+
+	// Lowering the work factor in the header must not go unnoticed.
+	var s sealed
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+	s.Iterations = minSealIterations
+	edited, _ := json.Marshal(s)
+	if _, err := Open(edited, "correct horse"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("edited header err = %v", err)
+	}
+
+	// A huge work factor is refused before any key derivation.
+	s.Iterations = maxSealIterations + 1
+	edited, _ = json.Marshal(s)
+	if _, err := Open(edited, "correct horse"); err == nil || errors.Is(err, ErrWrongPassphrase) {
+		t.Fatalf("oversized work factor err = %v", err)
+	}
+}
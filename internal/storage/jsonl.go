@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// JSONL stores each collection as <dir>/<collection>.jsonl, one record per
+// line. Put appends, so a key's older records stay until the collection is
+// replaced.
+type JSONL struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewJSONL returns a JSONL backend under dir.
+func NewJSONL(dir string) *JSONL {
+	return &JSONL{dir: dir}
+}
+
+// Path returns the file that holds collection.
+func (s *JSONL) Path(collection string) string {
+	return filepath.Join(s.dir, collection+".jsonl")
+}
+
+// Records reads the collection's lines. A torn final line from a crashed
+// writer, or any other line that is not JSON, is skipped rather than failing.
+func (s *JSONL) Records(collection string) ([]json.RawMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.Open(s.Path(collection))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read %s: %w", collection, err)
+	}
+	defer f.Close()
+
+	var records []json.RawMessage
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 4<<20)
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 || !json.Valid(line) {
+			continue
+		}
+		records = append(records, append(json.RawMessage(nil), line...))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", collection, err)
+	}
+	return records, nil
+}
+
+// Put appends data as a new line.
+func (s *JSONL) Put(collection, key string, data json.RawMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	line, err := compact(data)
+	if err != nil {
+		return fmt.Errorf("store %s %s: %w", collection, key, err)
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("create %s dir: %w", collection, err)
+	}
+	f, err := os.OpenFile(s.Path(collection), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", collection, err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("write %s: %w", collection, err)
+	}
+	return f.Close()
+}
+
+// Replace rewrites the collection atomically.
+func (s *JSONL) Replace(collection string, records []Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var buf bytes.Buffer
+	for _, r := range records {
+		line, err := compact(r.Data)
+		if err != nil {
+			return fmt.Errorf("store %s %s: %w", collection, r.Key, err)
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("create %s dir: %w", collection, err)
+	}
+	path := s.Path(collection)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("write tmp %s: %w", collection, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("rename tmp %s: %w", collection, err)
+	}
+	return nil
+}
+
+// Close is a no-op; every call opens and closes its file.
+func (s *JSONL) Close() error {
+	return nil
+}
+
+// compact puts data on one line, since a record must not span lines.
+func compact(data json.RawMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,145 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// sqliteDrivers are the database/sql driver names SQLite drivers register.
+var sqliteDrivers = []string{"sqlite", "sqlite3"}
+
+// sqliteTimeout bounds each statement so a locked database cannot hang a command.
+const sqliteTimeout = 30 * time.Second
+
+const sqliteSchema = `CREATE TABLE IF NOT EXISTS records (
+	seq INTEGER PRIMARY KEY AUTOINCREMENT,
+	collection TEXT NOT NULL,
+	key TEXT NOT NULL,
+	data TEXT NOT NULL,
+	UNIQUE (collection, key)
+)`
+
+// SQLite stores every collection in one table of <dir>/wiro.db, one row per
+// key, so lookups do not read whole files. The CLI ships without a SQLite
+// driver; the backend works in builds that register one.
+type SQLite struct {
+	db *sql.DB
+}
+
+// SQLiteAvailable reports whether this build registers a SQLite driver, so
+// the sqlite backend can be selected.
+func SQLiteAvailable() bool {
+	return sqliteDriver() != ""
+}
+
+// sqliteDriver returns the first registered SQLite driver name, or "".
+func sqliteDriver() string {
+	registered := sql.Drivers()
+	for _, want := range sqliteDrivers {
+		for _, name := range registered {
+			if name == want {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// OpenSQLite opens (creating if needed) <dir>/wiro.db.
+func OpenSQLite(dir string) (*SQLite, error) {
+	driver := sqliteDriver()
+	if driver == "" {
+		return nil, errors.New("the sqlite storage backend needs a SQLite database/sql driver, and this build has none; set preferences.storage to jsonl")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create storage dir: %w", err)
+	}
+	db, err := sql.Open(driver, filepath.Join(dir, "wiro.db"))
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite storage: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sqliteTimeout)
+	defer cancel()
+	if _, err := db.ExecContext(ctx, sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create sqlite storage: %w", err)
+	}
+	return &SQLite{db: db}, nil
+}
+
+// Records returns the current record of each key in write order.
+func (s *SQLite) Records(collection string) ([]json.RawMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), sqliteTimeout)
+	defer cancel()
+	rows, err := s.db.QueryContext(ctx, `SELECT data FROM records WHERE collection = ? ORDER BY seq`, collection)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", collection, err)
+	}
+	defer rows.Close()
+	var records []json.RawMessage
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("read %s: %w", collection, err)
+		}
+		records = append(records, json.RawMessage(data))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", collection, err)
+	}
+	return records, nil
+}
+
+// Put replaces the key's row; the new row sorts last, like an appended line.
+func (s *SQLite) Put(collection, key string, data json.RawMessage) error {
+	return s.inTx(collection, func(ctx context.Context, tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM records WHERE collection = ? AND key = ?`, collection, key); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `INSERT INTO records (collection, key, data) VALUES (?, ?, ?)`, collection, key, string(data))
+		return err
+	})
+}
+
+// Replace swaps the collection's rows in one transaction.
+func (s *SQLite) Replace(collection string, records []Record) error {
+	return s.inTx(collection, func(ctx context.Context, tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM records WHERE collection = ?`, collection); err != nil {
+			return err
+		}
+		for _, r := range records {
+			if _, err := tx.ExecContext(ctx, `INSERT OR REPLACE INTO records (collection, key, data) VALUES (?, ?, ?)`, collection, r.Key, string(r.Data)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Close closes the database.
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLite) inTx(collection string, fn func(context.Context, *sql.Tx) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), sqliteTimeout)
+	defer cancel()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("write %s: %w", collection, err)
+	}
+	if err := fn(ctx, tx); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("write %s: %w", collection, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("write %s: %w", collection, err)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package storage
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeSQLDriver understands exactly the statements SQLite sends, over an
+// in-memory records table per data source name.
+type fakeSQLDriver struct {
+	mu  sync.Mutex
+	dbs map[string]*fakeTable
+}
+
+type fakeRow struct {
+	seq                   int64
+	collection, key, data string
+}
+
+type fakeTable struct {
+	rows    []fakeRow
+	nextSeq int64
+}
+
+var fakeSQLite = &fakeSQLDriver{dbs: map[string]*fakeTable{}}
+
+func init() {
+	sql.Register("wiro-fake-sqlite", fakeSQLite)
+}
+
+func (d *fakeSQLDriver) Open(name string) (driver.Conn, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.dbs[name] == nil {
+		d.dbs[name] = &fakeTable{}
+	}
+	return &fakeConn{d: d, table: d.dbs[name]}, nil
+}
+
+type fakeConn struct {
+	d     *fakeSQLDriver
+	table *fakeTable
+	// saved is the table before the open transaction, restored on rollback.
+	saved *fakeTable
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{c: c, query: strings.Join(strings.Fields(query), " ")}, nil
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	saved := *c.table
+	saved.rows = append([]fakeRow(nil), c.table.rows...)
+	c.saved = &saved
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	c.saved = nil
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	*c.table = *c.saved
+	c.saved = nil
+	return nil
+}
+
+type fakeStmt struct {
+	c     *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.c.d.mu.Lock()
+	defer s.c.d.mu.Unlock()
+	t := s.c.table
+	str := func(i int) string { return fmt.Sprint(args[i]) }
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE IF NOT EXISTS records"):
+	case s.query == "DELETE FROM records WHERE collection = ? AND key = ?":
+		t.remove(func(r fakeRow) bool { return r.collection == str(0) && r.key == str(1) })
+	case s.query == "DELETE FROM records WHERE collection = ?":
+		t.remove(func(r fakeRow) bool { return r.collection == str(0) })
+	case s.query == "INSERT OR REPLACE INTO records (collection, key, data) VALUES (?, ?, ?)":
+		t.remove(func(r fakeRow) bool { return r.collection == str(0) && r.key == str(1) })
+		fallthrough
+	case s.query == "INSERT INTO records (collection, key, data) VALUES (?, ?, ?)":
+		for _, r := range t.rows {
+			if r.collection == str(0) && r.key == str(1) {
+				return nil, errors.New("UNIQUE constraint failed")
+			}
+		}
+		t.nextSeq++
+		t.rows = append(t.rows, fakeRow{seq: t.nextSeq, collection: str(0), key: str(1), data: str(2)})
+	default:
+		return nil, fmt.Errorf("fake sqlite: unexpected statement %q", s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if s.query != "SELECT data FROM records WHERE collection = ? ORDER BY seq" {
+		return nil, fmt.Errorf("fake sqlite: unexpected query %q", s.query)
+	}
+	s.c.d.mu.Lock()
+	defer s.c.d.mu.Unlock()
+	// Rows are kept in seq order, since inserts always take the next seq.
+	var data []string
+	for _, r := range s.c.table.rows {
+		if r.collection == fmt.Sprint(args[0]) {
+			data = append(data, r.data)
+		}
+	}
+	return &fakeRows{data: data}, nil
+}
+
+func (t *fakeTable) remove(match func(fakeRow) bool) {
+	kept := t.rows[:0]
+	for _, r := range t.rows {
+		if !match(r) {
+			kept = append(kept, r)
+		}
+	}
+	t.rows = kept
+}
+
+type fakeRows struct {
+	data []string
+}
+
+func (r *fakeRows) Columns() []string { return []string{"data"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.data) == 0 {
+		return io.EOF
+	}
+	dest[0], r.data = r.data[0], r.data[1:]
+	return nil
+}
+
+func TestSQLite_PutRecordsReplace(t *testing.T) {
+	prev := sqliteDrivers
+	sqliteDrivers = []string{"wiro-fake-sqlite"}
+	defer func() { sqliteDrivers = prev }()
+	if !SQLiteAvailable() {
+		t.Fatal("SQLiteAvailable() = false with a registered driver")
+	}
+
+	dir := t.TempDir()
+	st, err := Open(BackendSQLite, dir)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	for _, put := range []struct{ key, data string }{{"a", `{"n":1}`}, {"b", `{"n":2}`}, {"a", `{"n":3}`}} {
+		if err := st.Put("things", put.key, json.RawMessage(put.data)); err != nil {
+			t.Fatalf("Put %s: %v", put.key, err)
+		}
+	}
+	if err := st.Put("other", "a", json.RawMessage(`{"n":9}`)); err != nil {
+		t.Fatalf("Put other: %v", err)
+	}
+	// A rewritten key moves to the end, like an appended JSONL line.
+	records, err := st.Records("things")
+	if err != nil || len(records) != 2 || string(records[0]) != `{"n":2}` || string(records[1]) != `{"n":3}` {
+		t.Fatalf("Records() = %q, %v", records, err)
+	}
+
+	if err := st.Replace("things", []Record{{Key: "c", Data: json.RawMessage(`{"n":4}`)}, {Key: "c", Data: json.RawMessage(`{"n":5}`)}}); err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+	if err := st.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Reopening the same file sees what was written.
+	st, err = Open(BackendSQLite, dir)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer st.Close()
+	records, err = st.Records("things")
+	if err != nil || len(records) != 1 || string(records[0]) != `{"n":5}` {
+		t.Fatalf("after Replace Records() = %q, %v", records, err)
+	}
+	if records, err := st.Records("other"); err != nil || len(records) != 1 {
+		t.Fatalf("Replace touched another collection: %q, %v", records, err)
+	}
+}
@@ -0,0 +1,57 @@
+// Package storage persists the CLI's local records (run history, the schema
+// cache, the upload cache, cached API responses) behind one interface. The
+// default backend writes JSONL files and needs nothing beyond the standard
+// library; heavy users can select SQLite in builds that register a SQLite
+// database/sql driver.
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Backend names accepted by Open and preferences.storage.
+const (
+	BackendJSONL  = "jsonl"
+	BackendSQLite = "sqlite"
+)
+
+// Collections used by the CLI.
+const (
+	History = "history"
+	Schemas = "schemas"
+	Uploads = "uploads"
+	// Responses holds API responses kept for conditional requests.
+	Responses = "responses"
+)
+
+// Record is one value of a collection and the key it is stored under.
+type Record struct {
+	Key  string
+	Data json.RawMessage
+}
+
+// Storage keeps named collections of JSON records.
+type Storage interface {
+	// Records returns the collection's records in write order. A backend may
+	// also return superseded records of a key; the last one for a key wins.
+	Records(collection string) ([]json.RawMessage, error)
+	// Put stores data under key, superseding earlier records of the key.
+	Put(collection, key string, data json.RawMessage) error
+	// Replace makes records the whole content of the collection.
+	Replace(collection string, records []Record) error
+	Close() error
+}
+
+// Open returns the backend named backend ("" is jsonl) storing under dir.
+func Open(backend, dir string) (Storage, error) {
+	switch strings.ToLower(strings.TrimSpace(backend)) {
+	case "", BackendJSONL:
+		return NewJSONL(dir), nil
+	case BackendSQLite:
+		return OpenSQLite(dir)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q (use %s or %s)", backend, BackendJSONL, BackendSQLite)
+	}
+}
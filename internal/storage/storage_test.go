@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestJSONL_PutRecordsReplace(t *testing.T) {
+	st, err := Open("", t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	s := st.(*JSONL)
+	if records, err := s.Records("things"); err != nil || len(records) != 0 {
+		t.Fatalf("empty collection Records() = %v, %v", records, err)
+	}
+	if err := s.Put("things", "a", json.RawMessage("{\n  \"n\": 1\n}")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := s.Put("things", "a", json.RawMessage(`{"n":2}`)); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	// A torn trailing line must not break reads.
+	f, err := os.OpenFile(s.Path("things"), os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"n":`)
+	f.Close()
+
+	records, err := s.Records("things")
+	if err != nil {
+		t.Fatalf("Records: %v", err)
+	}
+	if len(records) != 2 || string(records[0]) != `{"n":1}` || string(records[1]) != `{"n":2}` {
+		t.Fatalf("unexpected records %q", records)
+	}
+
+	if err := s.Replace("things", []Record{{Key: "a", Data: json.RawMessage(`{"n":3}`)}}); err != nil {
+		t.Fatalf("Replace: %v", err)
+	}
+	records, err = s.Records("things")
+	if err != nil || len(records) != 1 || string(records[0]) != `{"n":3}` {
+		t.Fatalf("after Replace Records() = %q, %v", records, err)
+	}
+}
+
+func TestOpen_Backends(t *testing.T) {
+	if _, err := Open("postgres", t.TempDir()); err == nil || !strings.Contains(err.Error(), "unknown storage backend") {
+		t.Fatalf("expected unknown backend error, got %v", err)
+	}
+	// The CLI build registers no SQLite driver.
+	if _, err := Open(BackendSQLite, t.TempDir()); err == nil || !strings.Contains(err.Error(), "driver") {
+		t.Fatalf("expected missing driver error, got %v", err)
+	}
+}
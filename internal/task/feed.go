@@ -0,0 +1,154 @@
+package task
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/metrics"
+)
+
+// Feed event kinds.
+const (
+	FeedNew    = "new"
+	FeedStatus = "status"
+	FeedError  = "error"
+)
+
+// defaultFeedInterval is how often the account task list is polled.
+const defaultFeedInterval = 10 * time.Second
+
+// FeedEvent is a lifecycle change of any task in the account/project.
+type FeedEvent struct {
+	Time     time.Time `json:"time"`
+	Kind     string    `json:"kind"`
+	TaskID   string    `json:"taskId,omitempty"`
+	Model    string    `json:"model,omitempty"`
+	Status   string    `json:"status,omitempty"`
+	Previous string    `json:"previous,omitempty"`
+	Final    bool      `json:"final,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// FeedOptions configures WatchFeed.
+type FeedOptions struct {
+	// Interval between list polls; defaults to 10s.
+	Interval time.Duration
+	// Limit is how many recent tasks each poll fetches.
+	Limit int
+	// Backfill reports tasks that already existed when the feed started.
+	Backfill bool
+	// OnEvent receives every feed event; required.
+	OnEvent func(FeedEvent)
+}
+
+// List returns the newest tasks visible to headers, most recent first.
+func (s *Service) List(ctx context.Context, limit int, headers map[string]string) ([]api.Task, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	body := map[string]interface{}{
+		"start": "0",
+		"limit": fmt.Sprintf("%d", limit),
+		"sort":  "id",
+		"order": "DESC",
+	}
+	var resp api.TaskDetailResponse
+	if err := s.apiClient.PostJSON(ctx, "/Task/List", body, headers, &resp); err != nil {
+		return nil, err
+	}
+	if !resp.Result && len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("task list failed: %w", api.ResponseError(resp.Errors))
+	}
+	return resp.TaskList, nil
+}
+
+// WatchFeed polls the account task list until ctx ends and reports new tasks and
+// status changes. Poll failures are reported as error events and retried.
+func (s *Service) WatchFeed(ctx context.Context, headers map[string]string, opts FeedOptions) error {
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = defaultFeedInterval
+	}
+	feed := newFeedTracker(opts.Backfill)
+	for {
+		tasks, err := s.List(ctx, opts.Limit, headers)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			metrics.AccountTaskEvents.Inc(FeedError)
+			opts.OnEvent(FeedEvent{Time: time.Now(), Kind: FeedError, Error: err.Error()})
+		} else {
+			for _, ev := range feed.update(tasks, time.Now()) {
+				metrics.AccountTaskEvents.Inc(ev.Kind)
+				opts.OnEvent(ev)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// feedTracker diffs successive task list snapshots.
+type feedTracker struct {
+	status   map[string]string
+	seeded   bool
+	backfill bool
+}
+
+func newFeedTracker(backfill bool) *feedTracker {
+	return &feedTracker{status: map[string]string{}, backfill: backfill}
+}
+
+func (f *feedTracker) update(tasks []api.Task, now time.Time) []FeedEvent {
+	var events []FeedEvent
+	// Forget tasks that scrolled out of the window so a long-running feed stays small.
+	current := make(map[string]bool, len(tasks))
+	for _, t := range tasks {
+		current[t.ID] = true
+	}
+	for id := range f.status {
+		if !current[id] {
+			delete(f.status, id)
+		}
+	}
+	// The list is newest first; report oldest first so output reads chronologically.
+	for i := len(tasks) - 1; i >= 0; i-- {
+		t := tasks[i]
+		if t.ID == "" {
+			continue
+		}
+		prev, known := f.status[t.ID]
+		f.status[t.ID] = t.Status
+		ev := FeedEvent{Time: now, TaskID: t.ID, Model: ModelName(t), Status: t.Status, Final: isTerminal(t.Status)}
+		switch {
+		case !known:
+			// On the first poll only still-running tasks are interesting unless backfilling.
+			if !f.seeded && !f.backfill && ev.Final {
+				continue
+			}
+			ev.Kind = FeedNew
+		case prev != t.Status:
+			ev.Kind = FeedStatus
+			ev.Previous = prev
+		default:
+			continue
+		}
+		events = append(events, ev)
+	}
+	f.seeded = true
+	return events
+}
+
+// ModelName returns "owner/model" for a task, or "" when the API omitted it.
+func ModelName(t api.Task) string {
+	if t.SlugOwner == "" || t.SlugProject == "" {
+		return ""
+	}
+	return t.SlugOwner + "/" + t.SlugProject
+}
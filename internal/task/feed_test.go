@@ -0,0 +1,40 @@
+package task
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+func TestFeedTracker_ReportsNewAndChangedTasks(t *testing.T) {
+	f := newFeedTracker(false)
+	now := time.Now()
+
+	first := f.update([]api.Task{
+		{ID: "2", Status: "task_start", SlugOwner: "o", SlugProject: "m"},
+		{ID: "1", Status: "task_postprocess_end"},
+	}, now)
+	if len(first) != 1 || first[0].TaskID != "2" || first[0].Kind != FeedNew || first[0].Model != "o/m" {
+		t.Fatalf("first poll should report only running tasks: %#v", first)
+	}
+
+	second := f.update([]api.Task{
+		{ID: "3", Status: "task_queue"},
+		{ID: "2", Status: "task_postprocess_end"},
+		{ID: "1", Status: "task_postprocess_end"},
+	}, now)
+	if len(second) != 2 {
+		t.Fatalf("expected 2 events, got %#v", second)
+	}
+	if second[0].TaskID != "2" || second[0].Kind != FeedStatus || second[0].Previous != "task_start" || !second[0].Final {
+		t.Fatalf("unexpected status event %#v", second[0])
+	}
+	if second[1].TaskID != "3" || second[1].Kind != FeedNew {
+		t.Fatalf("unexpected new event %#v", second[1])
+	}
+
+	if again := f.update([]api.Task{{ID: "3", Status: "task_queue"}}, now); len(again) != 0 {
+		t.Fatalf("unchanged poll should be silent: %#v", again)
+	}
+}
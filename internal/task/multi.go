@@ -0,0 +1,383 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/log"
+	"github.com/wiro-ai/wiro-cli/internal/metrics"
+)
+
+// multiSocketBatch caps how many task tokens share one websocket connection.
+const multiSocketBatch = 16
+
+// multiListMin is the smallest task list page the pooled poll fetches.
+const multiListMin = 50
+
+// MultiTarget is one task of WatchMany: its socket token, its id, or both.
+type MultiTarget struct {
+	TaskID string
+	Token  string
+}
+
+// MultiUpdate reports a status change of one task in WatchMany.
+type MultiUpdate struct {
+	Time time.Time `json:"time"`
+	// Index is the task's position in the targets passed to WatchMany.
+	Index  int    `json:"index"`
+	TaskID string `json:"taskId,omitempty"`
+	Model  string `json:"model,omitempty"`
+	Status string `json:"status"`
+	// Source is ws or poll.
+	Source string `json:"source"`
+	Final  bool   `json:"final,omitempty"`
+}
+
+// MultiOptions configures WatchMany.
+type MultiOptions struct {
+	// OnUpdate receives every status change; it is called from one goroutine at a time.
+	OnUpdate func(MultiUpdate)
+	// OnError receives poll and socket errors; the watch keeps going.
+	OnError func(error)
+}
+
+// WatchMany watches several tasks until every one is final or ctx ends. Task
+// tokens share a few websocket connections, and one pooled poll per interval
+// lists the account's recent tasks, falling back to task detail for tasks the
+// list does not include. It returns the final task of each target in order;
+// entries stay nil for tasks that were not final when ctx ended.
+func (s *Service) WatchMany(ctx context.Context, targets []MultiTarget, headers map[string]string, opts MultiOptions) ([]*api.Task, error) {
+	if len(targets) == 0 {
+		return nil, errors.New("no tasks to watch")
+	}
+	for i, t := range targets {
+		if strings.TrimSpace(t.TaskID) == "" && strings.TrimSpace(t.Token) == "" {
+			return nil, fmt.Errorf("task %d: a task id or token is required", i+1)
+		}
+	}
+	st := newMultiState(targets, opts)
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var tokens []string
+	for _, t := range targets {
+		if t.Token != "" {
+			tokens = append(tokens, t.Token)
+		}
+	}
+	for start := 0; start < len(tokens); start += multiSocketBatch {
+		end := start + multiSocketBatch
+		if end > len(tokens) {
+			end = len(tokens)
+		}
+		go s.streamManyWithReconnect(ctx, st, tokens[start:end], headers)
+	}
+
+	for {
+		s.pollMany(ctx, st, headers)
+		select {
+		case <-st.done:
+			return st.results(), nil
+		case <-ctx.Done():
+			return st.results(), ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// multiState tracks every task of a WatchMany.
+type multiState struct {
+	mu      sync.Mutex
+	targets []MultiTarget
+	status  []string
+	model   []string
+	final   []*api.Task
+	left    int
+	done    chan struct{}
+	opts    MultiOptions
+}
+
+func newMultiState(targets []MultiTarget, opts MultiOptions) *multiState {
+	n := len(targets)
+	return &multiState{
+		targets: append([]MultiTarget(nil), targets...),
+		status:  make([]string, n),
+		model:   make([]string, n),
+		final:   make([]*api.Task, n),
+		left:    n,
+		done:    make(chan struct{}),
+		opts:    opts,
+	}
+}
+
+// find returns the index of the target with this id or token.
+func (m *multiState) find(id, token string) (int, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, t := range m.targets {
+		if (id != "" && t.TaskID == id) || (token != "" && t.Token == token) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// pending returns the targets that are not final yet.
+func (m *multiState) pending() []int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []int
+	for i, f := range m.final {
+		if f == nil {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// set records a status seen for target i and reports it when it changed.
+func (m *multiState) set(i int, t api.Task, source string) {
+	m.mu.Lock()
+	if m.final[i] != nil || t.Status == "" {
+		m.mu.Unlock()
+		return
+	}
+	if m.targets[i].TaskID == "" {
+		m.targets[i].TaskID = t.ID
+	}
+	if name := ModelName(t); name != "" {
+		m.model[i] = name
+	}
+	changed := m.status[i] != t.Status
+	m.status[i] = t.Status
+	update := MultiUpdate{Time: time.Now(), Index: i, TaskID: m.targets[i].TaskID, Model: m.model[i], Status: t.Status, Source: source, Final: isTerminal(t.Status)}
+	if update.Final {
+		final := t
+		m.final[i] = &final
+		m.left--
+		metrics.TasksCompleted.Inc(t.Status)
+		if m.left == 0 {
+			close(m.done)
+		}
+	}
+	// Report under the lock so updates reach the board in order.
+	if changed && m.opts.OnUpdate != nil {
+		m.opts.OnUpdate(update)
+	}
+	m.mu.Unlock()
+}
+
+func (m *multiState) token(i int) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.targets[i].Token
+}
+
+func (m *multiState) key(i int) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.targets[i].Token != "" {
+		return m.targets[i].Token
+	}
+	return m.targets[i].TaskID
+}
+
+func (m *multiState) results() []*api.Task {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*api.Task(nil), m.final...)
+}
+
+func (m *multiState) report(err error) {
+	if m.opts.OnError != nil {
+		m.opts.OnError(err)
+	}
+}
+
+// pollMany lists the account's recent tasks once and fetches detail only for
+// watched tasks the list did not include.
+func (s *Service) pollMany(ctx context.Context, st *multiState, headers map[string]string) {
+	pending := st.pending()
+	if len(pending) == 0 {
+		return
+	}
+	limit := 2 * len(st.targets)
+	if limit < multiListMin {
+		limit = multiListMin
+	}
+	seen := map[int]bool{}
+	tasks, err := s.List(ctx, limit, headers)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		st.report(err)
+	}
+	for _, t := range tasks {
+		if i, ok := st.find(t.ID, t.SocketAccessToken); ok {
+			seen[i] = true
+			st.set(i, t, "poll")
+		}
+	}
+	for _, i := range pending {
+		if seen[i] {
+			continue
+		}
+		detail, err := s.Detail(ctx, st.key(i), headers)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			st.report(err)
+			continue
+		}
+		if len(detail.TaskList) > 0 {
+			st.set(i, detail.TaskList[0], "poll")
+		}
+	}
+}
+
+// streamManyWithReconnect keeps one websocket carrying tokens open until their
+// tasks are final, redialing with backoff. Polling covers the gaps.
+func (s *Service) streamManyWithReconnect(ctx context.Context, st *multiState, tokens []string, headers map[string]string) {
+	backoff := wsReconnectMin
+	for {
+		err := s.streamManyWS(ctx, st, tokens, headers)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+		st.report(err)
+		metrics.WatchReconnects.Inc()
+		select {
+		case <-ctx.Done():
+			return
+		case <-st.done:
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > wsReconnectMax {
+			backoff = wsReconnectMax
+		}
+	}
+}
+
+// streamManyWS registers every token on one connection and routes frames to
+// their task by the token or id they carry. It returns nil once the tokens'
+// tasks are all final.
+func (s *Service) streamManyWS(ctx context.Context, st *multiState, tokens []string, headers map[string]string) (err error) {
+	ctx, endSession := wsSession(ctx, len(tokens))
+	defer func() { endSession(err) }()
+	ws, err := dialWS(ctx, wsURL)
+	if err != nil {
+		return fmt.Errorf("websocket connect failed (polling fallback active): %w", err)
+	}
+	defer ws.Close()
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		ticker := time.NewTicker(wsPingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ctx.Done():
+				ws.Close()
+				return
+			case <-st.done:
+				ws.Close()
+				return
+			case <-ticker.C:
+				if err := ws.Ping(); err != nil {
+					log.Debugf("ws -> ping failed: %v", err)
+					ws.Close()
+					return
+				}
+			}
+		}
+	}()
+
+	live := map[int]bool{}
+	for _, token := range tokens {
+		i, ok := st.find("", token)
+		if !ok {
+			continue
+		}
+		live[i] = true
+		if err := ws.WriteJSON(map[string]string{"type": "task_info", "tasktoken": token}); err != nil {
+			return fmt.Errorf("websocket register failed: %w", err)
+		}
+	}
+	for len(live) > 0 {
+		rawMsg, err := ws.ReadText()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			select {
+			case <-st.done:
+				return nil
+			default:
+			}
+			return fmt.Errorf("websocket read failed (reconnecting, polling fallback active): %w", err)
+		}
+		msg := map[string]interface{}{}
+		if err := json.Unmarshal(rawMsg, &msg); err != nil {
+			continue
+		}
+		typeVal, _ := msg["type"].(string)
+		i, ok := frameTarget(st, msg)
+		if !ok && len(tokens) == 1 {
+			// A lone token needs no routing.
+			i, ok = st.find("", tokens[0])
+		}
+		if !ok || typeVal == "" || !strings.HasPrefix(typeVal, "task_") || typeVal == "task_output" || registrationRejected(typeVal, "") {
+			continue
+		}
+		if !isTerminal(typeVal) {
+			st.set(i, api.Task{Status: typeVal}, "ws")
+			continue
+		}
+		task, err := s.fetchTerminalDetail(ctx, st.token(i), headers, 6)
+		if err != nil || task == nil {
+			continue
+		}
+		st.set(i, *task, "ws")
+		delete(live, i)
+	}
+	return nil
+}
+
+// frameTarget finds the watched task a frame belongs to from a token or id
+// field at its top level or inside its message.
+func frameTarget(st *multiState, msg map[string]interface{}) (int, bool) {
+	for _, m := range []interface{}{msg, msg["message"]} {
+		fields, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, key := range []string{"tasktoken", "socketaccesstoken", "token"} {
+			if v, ok := fields[key].(string); ok && v != "" {
+				if i, ok := st.find("", v); ok {
+					return i, true
+				}
+			}
+		}
+		for _, key := range []string{"taskid", "id"} {
+			if id := fmt.Sprint(fields[key]); fields[key] != nil && id != "" {
+				if i, ok := st.find(id, ""); ok {
+					return i, true
+				}
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,100 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+func TestWatchMany_PoolsPolling(t *testing.T) {
+	prev := pollInterval
+	pollInterval = 10 * time.Millisecond
+	defer func() { pollInterval = prev }()
+
+	var mu sync.Mutex
+	lists, details := 0, 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		var tasks []api.Task
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/Task/List"):
+			lists++
+			status := "task_start"
+			if lists > 1 {
+				status = "task_postprocess_end"
+			}
+			tasks = []api.Task{{ID: "1", Status: status}, {ID: "9", Status: "task_start"}}
+		case strings.HasSuffix(r.URL.Path, "/Task/Detail"):
+			if !strings.Contains(string(body), `"taskid":"2"`) {
+				t.Errorf("detail polled for a listed task: %s", body)
+			}
+			details++
+			tasks = []api.Task{{ID: "2", Status: "task_cancel"}}
+		}
+		_ = json.NewEncoder(w).Encode(api.TaskDetailResponse{
+			GenericResponse: api.GenericResponse{Result: true},
+			TaskList:        tasks,
+		})
+	}))
+	defer srv.Close()
+
+	var updates []MultiUpdate
+	svc := NewService(api.NewClient(srv.URL))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	final, err := svc.WatchMany(ctx, []MultiTarget{{TaskID: "1"}, {TaskID: "2"}}, nil, MultiOptions{
+		OnUpdate: func(u MultiUpdate) { updates = append(updates, u) },
+	})
+	if err != nil {
+		t.Fatalf("WatchMany: %v", err)
+	}
+	if final[0] == nil || final[0].Status != "task_postprocess_end" || final[1] == nil || final[1].Status != "task_cancel" {
+		t.Fatalf("unexpected final tasks %+v %+v", final[0], final[1])
+	}
+	if details != 1 {
+		t.Fatalf("expected one detail poll for the unlisted task, got %d", details)
+	}
+	// task 1 start, task 2 cancel, task 1 end; repeated statuses are not reported.
+	if len(updates) != 3 || !updates[2].Final || updates[2].Index != 0 {
+		t.Fatalf("unexpected updates %+v", updates)
+	}
+
+	if _, err := svc.WatchMany(ctx, nil, nil, MultiOptions{}); err == nil {
+		t.Fatal("expected error without targets")
+	}
+}
+
+func TestFrameTarget_RoutesByTokenOrID(t *testing.T) {
+	st := newMultiState([]MultiTarget{{Token: "tok-a"}, {TaskID: "7", Token: "tok-b"}}, MultiOptions{})
+	cases := []struct {
+		frame string
+		want  int
+		ok    bool
+	}{
+		{`{"type":"task_start","tasktoken":"tok-b"}`, 1, true},
+		{`{"type":"task_start","message":{"token":"tok-a"}}`, 0, true},
+		{`{"type":"task_start","message":{"id":7}}`, 1, true},
+		{`{"type":"task_start","tasktoken":"other"}`, 0, false},
+		{`{"type":"task_start"}`, 0, false},
+	}
+	for _, tc := range cases {
+		msg := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(tc.frame), &msg); err != nil {
+			t.Fatal(err)
+		}
+		got, ok := frameTarget(st, msg)
+		if ok != tc.ok || (ok && got != tc.want) {
+			t.Errorf("frameTarget(%s) = %d, %v; want %d, %v", tc.frame, got, ok, tc.want, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package task
+
+import (
+	"sync"
+	"time"
+)
+
+// Phase is a stretch of a task's life marked out by its status events.
+type Phase string
+
+const (
+	// PhaseQueue is waiting to be accepted and for a worker, before and after preprocessing.
+	PhaseQueue Phase = "queue"
+	// PhasePreprocess is preparing the inputs.
+	PhasePreprocess Phase = "preprocess"
+	// PhaseRun is the model running on a worker.
+	PhaseRun Phase = "run"
+	// PhasePostprocess is storing the outputs.
+	PhasePostprocess Phase = "postprocess"
+)
+
+// phaseOrder is the order phases are reported in.
+var phaseOrder = []Phase{PhaseQueue, PhasePreprocess, PhaseRun, PhasePostprocess}
+
+// statusPhase is the phase each task_* status starts.
+var statusPhase = map[string]Phase{
+	"task_queue":             PhaseQueue,
+	"task_accept":            PhaseQueue,
+	"task_preprocess_start":  PhasePreprocess,
+	"task_preprocess_end":    PhaseQueue,
+	"task_assign":            PhaseQueue,
+	"task_start":             PhaseRun,
+	"task_output":            PhaseRun,
+	"task_error":             PhaseRun,
+	"task_output_full":       PhaseRun,
+	"task_error_full":        PhaseRun,
+	"task_end":               PhasePostprocess,
+	"task_postprocess_start": PhasePostprocess,
+}
+
+// PhaseTime is how long a task spent in one phase.
+type PhaseTime struct {
+	Phase    Phase
+	Duration time.Duration
+}
+
+// Timeline adds up the time a task spends in each phase from the status
+// transitions a watch observes. Each status counts the first time it is
+// seen, so a poll repeating an earlier status does not move the task back.
+// It is safe for concurrent use by watch callbacks.
+type Timeline struct {
+	mu    sync.Mutex
+	seen  map[string]bool
+	cur   Phase
+	since time.Time
+	end   time.Time
+	spent map[Phase]time.Duration
+}
+
+// NewTimeline returns an empty timeline.
+func NewTimeline() *Timeline {
+	return &Timeline{seen: map[string]bool{}, spent: map[Phase]time.Duration{}}
+}
+
+// Observe records that the task reported status at the given time. Statuses
+// that are not task_* transitions are ignored.
+func (t *Timeline) Observe(status string, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.seen[status] || !t.end.IsZero() {
+		return
+	}
+	phase, ok := statusPhase[status]
+	if !ok && !isTerminal(status) {
+		return
+	}
+	t.seen[status] = true
+	if t.cur != "" {
+		t.spent[t.cur] += at.Sub(t.since)
+	}
+	if !ok {
+		t.cur, t.end = "", at
+		return
+	}
+	t.cur, t.since = phase, at
+}
+
+// Phases returns the time spent in each phase seen, in the order a task goes
+// through them. A phase still open is counted up to now.
+func (t *Timeline) Phases(now time.Time) []PhaseTime {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	spent := make(map[Phase]time.Duration, len(t.spent)+1)
+	for p, d := range t.spent {
+		spent[p] = d
+	}
+	if t.cur != "" && now.After(t.since) {
+		spent[t.cur] += now.Sub(t.since)
+	}
+	var out []PhaseTime
+	for _, p := range phaseOrder {
+		if d, ok := spent[p]; ok {
+			out = append(out, PhaseTime{Phase: p, Duration: d})
+		}
+	}
+	return out
+}
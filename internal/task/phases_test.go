@@ -0,0 +1,43 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeline(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(s int) time.Time { return start.Add(time.Duration(s) * time.Second) }
+	tl := NewTimeline()
+	tl.Observe("task_queue", at(0))
+	tl.Observe("connection", at(1))
+	tl.Observe("task_preprocess_start", at(5))
+	tl.Observe("task_preprocess_end", at(7))
+	tl.Observe("task_assign", at(8))
+	tl.Observe("task_start", at(10))
+	tl.Observe("task_output", at(20))
+	tl.Observe("task_start", at(25)) // a poll repeating an earlier status
+	tl.Observe("task_end", at(40))
+
+	if got := tl.Phases(at(42)); len(got) != 4 || got[3].Duration != 2*time.Second {
+		t.Fatalf("open postprocess phase: %+v", got)
+	}
+	tl.Observe("task_postprocess_end", at(43))
+	tl.Observe("task_output", at(50))
+
+	want := []PhaseTime{
+		{PhaseQueue, 8 * time.Second},
+		{PhasePreprocess, 2 * time.Second},
+		{PhaseRun, 30 * time.Second},
+		{PhasePostprocess, 3 * time.Second},
+	}
+	got := tl.Phases(at(60))
+	if len(got) != len(want) {
+		t.Fatalf("phases = %+v", got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("phase %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
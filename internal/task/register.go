@@ -0,0 +1,48 @@
+package task
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Registration retry timing; vars so tests can shorten them.
+var (
+	// wsRegisterWindow is how long after the watch starts a rejected task token
+	// is retried. Right after Run the socket may not know the token yet.
+	wsRegisterWindow = 30 * time.Second
+	// wsRegisterRetryMin and wsRegisterRetryMax bound the backoff between attempts.
+	wsRegisterRetryMin = 500 * time.Millisecond
+	wsRegisterRetryMax = 4 * time.Second
+)
+
+// errRegisterRejected is returned when the socket refuses the task_info registration.
+var errRegisterRejected = errors.New("websocket rejected the task token")
+
+// registrationRejected reports whether a frame received before any task event
+// refuses the registration, as in {"type":"error","message":"unknown token"}.
+func registrationRejected(msgType, text string) bool {
+	switch strings.ToLower(msgType) {
+	case "error", "task_info_error", "unknown_token", "invalid_token", "token_error":
+		return true
+	}
+	m := strings.ToLower(text)
+	return strings.Contains(m, "token") &&
+		(strings.Contains(m, "unknown") || strings.Contains(m, "invalid") || strings.Contains(m, "not found"))
+}
+
+// registerRetryDelay returns how long to wait before registering again, doubling
+// backoff up to wsRegisterRetryMax; ok is false once elapsed has left the window.
+func registerRetryDelay(elapsed, backoff time.Duration) (wait, next time.Duration, ok bool) {
+	if elapsed >= wsRegisterWindow {
+		return 0, backoff, false
+	}
+	if backoff < wsRegisterRetryMin {
+		backoff = wsRegisterRetryMin
+	}
+	next = backoff * 2
+	if next > wsRegisterRetryMax {
+		next = wsRegisterRetryMax
+	}
+	return backoff, next, true
+}
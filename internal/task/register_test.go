@@ -0,0 +1,46 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRegistrationRejected(t *testing.T) {
+	cases := []struct {
+		typ, text string
+		want      bool
+	}{
+		{"error", `"unknown token"`, true},
+		{"task_info", `"Unknown task token"`, true},
+		{"", `"token not found"`, true},
+		{"task_queue", `"waiting for a worker"`, false},
+		{"task_start", "", false},
+	}
+	for _, c := range cases {
+		if got := registrationRejected(c.typ, c.text); got != c.want {
+			t.Errorf("registrationRejected(%q, %q) = %v, want %v", c.typ, c.text, got, c.want)
+		}
+	}
+}
+
+func TestRegisterRetryDelay_BacksOffWithinWindow(t *testing.T) {
+	var waits []time.Duration
+	backoff := time.Duration(0)
+	for i := 0; i < 6; i++ {
+		wait, next, ok := registerRetryDelay(time.Second, backoff)
+		if !ok {
+			t.Fatalf("attempt %d: retry refused inside the window", i)
+		}
+		waits = append(waits, wait)
+		backoff = next
+	}
+	want := []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second, 4 * time.Second}
+	for i := range want {
+		if waits[i] != want[i] {
+			t.Fatalf("waits = %v, want %v", waits, want)
+		}
+	}
+	if _, _, ok := registerRetryDelay(wsRegisterWindow, backoff); ok {
+		t.Fatal("retry allowed after the window")
+	}
+}
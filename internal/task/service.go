@@ -16,13 +16,47 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/log"
+	"github.com/wiro-ai/wiro-cli/internal/metrics"
+	"github.com/wiro-ai/wiro-cli/internal/telemetry"
 )
 
 const (
 	wsURL = "wss://socket.wiro.ai/v1"
+
+	// wsHeartbeatTimeout marks the socket stale when no frame arrived for this long.
+	wsHeartbeatTimeout = 45 * time.Second
+
+	wsReconnectMin = 1 * time.Second
+	wsReconnectMax = 30 * time.Second
+)
+
+// SocketURL returns the WebSocket endpoint task events are streamed from.
+func SocketURL() string {
+	return wsURL
+}
+
+// Keep-alive timing; vars so tests can shorten them.
+var (
+	// wsPingInterval is how often the client pings; each ping draws a pong, so a
+	// healthy socket never stays silent long enough to hit the read deadline.
+	wsPingInterval = 20 * time.Second
+	// pollInterval is how often task detail is polled alongside the websocket.
+	pollInterval = 5 * time.Second
+	// wsReadTimeout is the longest a read may wait for any frame before the connection is declared dead.
+	wsReadTimeout = wsHeartbeatTimeout
+	// wsWriteTimeout bounds each frame write.
+	wsWriteTimeout = 10 * time.Second
+)
+
+// Connection states reported through "connection" system events.
+const (
+	ConnStateLive     = "live"
+	ConnStateDegraded = "degraded"
 )
 
 // Service manages run/detail/cancel/kill and watch operations.
@@ -51,15 +85,75 @@ func isTerminal(status string) bool {
 	}
 }
 
-func (s *Service) Run(ctx context.Context, owner, model string, values map[string][]api.MultipartValue, headers map[string]string) (api.RunResponse, error) {
+// IsTerminal reports whether a task status is final.
+func IsTerminal(status string) bool {
+	return isTerminal(status)
+}
+
+// CallbackURLField is the Run field that names the URL the API posts the
+// finished task to.
+const CallbackURLField = "callbackUrl"
+
+// RunOptions carries optional callbacks for Run.
+type RunOptions struct {
+	// OnUploadProgress receives bytes sent and total payload size while inputs upload.
+	OnUploadProgress api.ProgressFunc
+	// CallbackURL asks the API to post the finished task there; empty sends nothing.
+	CallbackURL string
+}
+
+// CallbackPayload is the body posted to a callback URL for a finished task:
+// the same shape as the task detail response.
+func CallbackPayload(t api.Task) api.TaskDetailResponse {
+	return api.TaskDetailResponse{
+		GenericResponse: api.GenericResponse{Result: true},
+		Total:           "1",
+		TaskList:        []api.Task{t},
+	}
+}
+
+// WatchOptions carries optional callbacks for WatchTask.
+type WatchOptions struct {
+	// OnEvent receives websocket, poll, and connection events as they happen.
+	OnEvent func(WatchEvent)
+	// TaskID is polled when the run returned no socket token.
+	TaskID string
+	// PollInterval is how often task detail is polled alongside the websocket;
+	// zero uses the default.
+	PollInterval time.Duration
+}
+
+func (s *Service) Run(ctx context.Context, owner, model string, values map[string][]api.MultipartValue, headers map[string]string, opts RunOptions) (api.RunResponse, error) {
 	path := fmt.Sprintf("/Run/%s/%s", owner, model)
+	values, uploaded, err := s.uploadLargeFiles(ctx, values, headers, opts.OnUploadProgress)
+	if err != nil {
+		metrics.TaskFailures.Inc("submit")
+		return api.RunResponse{}, err
+	}
+	onProgress := opts.OnUploadProgress
+	if onProgress != nil && uploaded > 0 {
+		// One count across the chunked uploads and the run request.
+		onProgress = func(done, total int64) { opts.OnUploadProgress(uploaded+done, uploaded+total) }
+	}
+	if opts.CallbackURL != "" {
+		// Copied so the caller's inputs stay as they were for history and manifests.
+		withCallback := make(map[string][]api.MultipartValue, len(values)+1)
+		for k, v := range values {
+			withCallback[k] = v
+		}
+		withCallback[CallbackURLField] = []api.MultipartValue{{Value: opts.CallbackURL}}
+		values = withCallback
+	}
 	var resp api.RunResponse
-	if err := s.apiClient.PostMultipart(ctx, path, values, headers, &resp); err != nil {
+	if err := s.apiClient.PostMultipartProgress(ctx, path, values, headers, onProgress, &resp); err != nil {
+		metrics.TaskFailures.Inc("submit")
 		return api.RunResponse{}, err
 	}
 	if !resp.Result && len(resp.Errors) > 0 {
-		return api.RunResponse{}, fmt.Errorf("run failed: %s", resp.Errors[0].Message)
+		metrics.TaskFailures.Inc("submit")
+		return api.RunResponse{}, fmt.Errorf("run failed: %w", api.ResponseError(resp.Errors))
 	}
+	metrics.TasksSubmitted.Inc()
 	return resp, nil
 }
 
@@ -75,7 +169,7 @@ func (s *Service) Detail(ctx context.Context, idOrToken string, headers map[stri
 		return api.TaskDetailResponse{}, err
 	}
 	if !resp.Result && len(resp.Errors) > 0 {
-		return resp, fmt.Errorf("task detail failed: %s", resp.Errors[0].Message)
+		return resp, fmt.Errorf("task detail failed: %w", api.ResponseError(resp.Errors))
 	}
 	return resp, nil
 }
@@ -86,7 +180,7 @@ func (s *Service) Cancel(ctx context.Context, taskID string, headers map[string]
 		return api.TaskDetailResponse{}, err
 	}
 	if !resp.Result && len(resp.Errors) > 0 {
-		return resp, fmt.Errorf("task cancel failed: %s", resp.Errors[0].Message)
+		return resp, fmt.Errorf("task cancel failed: %w", api.ResponseError(resp.Errors))
 	}
 	return resp, nil
 }
@@ -97,102 +191,113 @@ func (s *Service) Kill(ctx context.Context, taskID string, headers map[string]st
 		return api.TaskDetailResponse{}, err
 	}
 	if !resp.Result && len(resp.Errors) > 0 {
-		return resp, fmt.Errorf("task kill failed: %s", resp.Errors[0].Message)
+		return resp, fmt.Errorf("task kill failed: %w", api.ResponseError(resp.Errors))
 	}
 	return resp, nil
 }
 
 // WatchTask combines websocket stream and polling fallback. It returns final task detail.
-func (s *Service) WatchTask(ctx context.Context, taskToken string, headers map[string]string, onEvent func(WatchEvent)) (*api.Task, error) {
-	if strings.TrimSpace(taskToken) == "" {
-		return nil, errors.New("task token is required for watch")
+// Without a socket token it polls by opts.TaskID only. It is Subscribe with
+// opts.OnEvent consuming the events, followed by Wait.
+func (s *Service) WatchTask(ctx context.Context, taskToken string, headers map[string]string, opts WatchOptions) (*api.Task, error) {
+	sub, err := s.subscribe(ctx, taskToken, headers, opts.TaskID, opts.PollInterval)
+	if err != nil {
+		return nil, err
+	}
+	for ev := range sub.Events() {
+		if opts.OnEvent != nil {
+			opts.OnEvent(ev)
+		}
+	}
+	return sub.Wait()
+}
+
+// watch runs the websocket stream and polling fallback until the task is final
+// or ctx ends. Background goroutines stop once done is closed. interval is the
+// poll period; zero uses pollInterval.
+func (s *Service) watch(ctx context.Context, done <-chan struct{}, taskToken, pollKey string, headers map[string]string, interval time.Duration, onEvent func(WatchEvent)) (*api.Task, error) {
+	if interval <= 0 {
+		interval = pollInterval
 	}
 	finalTaskCh := make(chan *api.Task, 1)
 	errCh := make(chan error, 2)
 	var once sync.Once
+	conn := newConnTracker(onEvent)
+	wake := newWakeSignal()
+	go watchForSleep(ctx, done, wake, onEvent)
+
+	// report forwards background errors without blocking once the watch has returned.
+	report := func(err error) {
+		select {
+		case errCh <- err:
+		case <-done:
+		}
+	}
 
 	signalFinal := func(task *api.Task) {
 		if task == nil {
 			return
 		}
 		once.Do(func() {
+			metrics.TasksCompleted.Inc(task.Status)
+			switch task.Status {
+			case "task_cancel":
+				metrics.TaskFailures.Inc("cancel")
+			case "task_error_full":
+				metrics.TaskFailures.Inc("error")
+			}
 			finalTaskCh <- task
 		})
 	}
 
 	// Polling fallback (always on, low-frequency).
 	go func() {
-		ticker := time.NewTicker(5 * time.Second)
+		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
 		for {
 			select {
 			case <-ctx.Done():
 				return
+			case <-wake.C():
+				// Poll right away after sleep instead of waiting for the next tick.
 			case <-ticker.C:
-				detail, err := s.Detail(ctx, taskToken, headers)
-				if err != nil {
-					errCh <- err
-					continue
-				}
-				if len(detail.TaskList) == 0 {
-					continue
-				}
-				task := detail.TaskList[0]
-				if onEvent != nil {
-					onEvent(WatchEvent{Source: "poll", Type: task.Status, Text: "polled status", Raw: map[string]interface{}{"status": task.Status}})
-				}
-				if isTerminal(task.Status) {
-					signalFinal(&task)
-					return
-				}
+				conn.checkStale(time.Now())
 			}
-		}
-	}()
-
-	// Websocket stream
-	go func() {
-		conn, err := dialWS(ctx, wsURL)
-		if err != nil {
-			errCh <- fmt.Errorf("websocket connect failed (polling fallback active): %w", err)
-			return
-		}
-		defer conn.Close()
-
-		register := map[string]string{"type": "task_info", "tasktoken": taskToken}
-		if err := conn.WriteJSON(register); err != nil {
-			errCh <- fmt.Errorf("websocket register failed: %w", err)
-			return
-		}
-
-		for {
-			rawMsg, err := conn.ReadText()
+			detail, err := s.Detail(ctx, pollKey, headers)
 			if err != nil {
-				errCh <- fmt.Errorf("websocket read failed (polling fallback active): %w", err)
-				return
-			}
-			msg := map[string]interface{}{}
-			if err := json.Unmarshal(rawMsg, &msg); err != nil {
+				report(err)
 				continue
 			}
-			typeVal, _ := msg["type"].(string)
-			text := ""
-			if m, ok := msg["message"]; ok {
-				b, _ := json.Marshal(m)
-				text = string(b)
+			if len(detail.TaskList) == 0 {
+				continue
 			}
+			task := detail.TaskList[0]
 			if onEvent != nil {
-				onEvent(WatchEvent{Source: "ws", Type: typeVal, Text: text, Raw: msg})
-			}
-			if isTerminal(typeVal) {
-				task, termErr := s.fetchTerminalDetail(ctx, taskToken, headers, 6)
-				if termErr == nil && task != nil {
-					signalFinal(task)
-					return
+				raw := map[string]interface{}{"status": task.Status}
+				if task.Priority != "" {
+					raw["priority"] = task.Priority
+				}
+				if task.QueuePosition != "" {
+					raw["queueposition"] = task.QueuePosition.String()
 				}
+				onEvent(WatchEvent{Source: "poll", Type: task.Status, Text: "polled status", Raw: raw})
+			}
+			if isTerminal(task.Status) {
+				signalFinal(&task)
+				return
 			}
 		}
 	}()
 
+	if taskToken == "" {
+		conn.markDown()
+		if onEvent != nil {
+			onEvent(WatchEvent{Source: "system", Type: "warning", Text: "run returned no socket token; watching by polling task id " + pollKey})
+		}
+	} else {
+		go s.streamWithReconnect(ctx, done, wake, taskToken, headers, conn, onEvent, signalFinal, report)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -207,6 +312,218 @@ func (s *Service) WatchTask(ctx context.Context, taskToken string, headers map[s
 	}
 }
 
+// streamWithReconnect keeps a websocket session open, redialing with backoff whenever it dies.
+// A wake from sleep drops the (likely dead) session and redials immediately.
+func (s *Service) streamWithReconnect(ctx context.Context, done <-chan struct{}, wake *wakeSignal, taskToken string, headers map[string]string, conn *connTracker, onEvent func(WatchEvent), signalFinal func(*api.Task), report func(error)) {
+	backoff := wsReconnectMin
+	start := time.Now()
+	var registerBackoff time.Duration
+	for {
+		gotFrames, err := s.streamWS(ctx, done, wake, taskToken, headers, conn, onEvent, signalFinal)
+		if err == nil {
+			return
+		}
+		if errors.Is(err, errRegisterRejected) {
+			wait, next, ok := registerRetryDelay(time.Since(start), registerBackoff)
+			if !ok {
+				// The token is still unknown; polling carries the rest of the watch.
+				conn.markDown()
+				report(fmt.Errorf("%w for %s; watching by polling", err, wsRegisterWindow))
+				return
+			}
+			log.Debugf("ws register rejected, retrying in %s: %v", wait, err)
+			registerBackoff = next
+			select {
+			case <-ctx.Done():
+				return
+			case <-done:
+				return
+			case <-time.After(wait):
+			}
+			continue
+		}
+		conn.markDown()
+		if errors.Is(err, errResync) {
+			metrics.WatchReconnects.Inc()
+			backoff = wsReconnectMin
+			continue
+		}
+		report(err)
+		metrics.WatchReconnects.Inc()
+		if gotFrames {
+			backoff = wsReconnectMin
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-done:
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > wsReconnectMax {
+			backoff = wsReconnectMax
+		}
+	}
+}
+
+// streamWS runs one websocket session until the task finishes (nil error) or the
+// connection fails. gotFrames reports whether the session received anything.
+func (s *Service) streamWS(ctx context.Context, done <-chan struct{}, wake *wakeSignal, taskToken string, headers map[string]string, conn *connTracker, onEvent func(WatchEvent), signalFinal func(*api.Task)) (gotFrames bool, err error) {
+	ctx, endSession := wsSession(ctx, 1)
+	defer func() { endSession(err) }()
+	ws, err := dialWS(ctx, wsURL)
+	if err != nil {
+		return false, fmt.Errorf("websocket connect failed (polling fallback active): %w", err)
+	}
+	defer ws.Close()
+	ws.onFrame = func() {
+		gotFrames = true
+		conn.touch()
+	}
+
+	// Unblock the reader when the watch ends; keep the socket warm meanwhile.
+	stop := make(chan struct{})
+	defer close(stop)
+	var resync atomic.Bool
+	wakeCh := wake.C()
+	go func() {
+		ticker := time.NewTicker(wsPingInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ctx.Done():
+				ws.Close()
+				return
+			case <-done:
+				ws.Close()
+				return
+			case <-wakeCh:
+				resync.Store(true)
+				ws.Close()
+				return
+			case <-ticker.C:
+				if err := ws.Ping(); err != nil {
+					log.Debugf("ws -> ping failed: %v", err)
+					ws.Close()
+					return
+				}
+			}
+		}
+	}()
+
+	register := map[string]string{"type": "task_info", "tasktoken": taskToken}
+	if err := ws.WriteJSON(register); err != nil {
+		return gotFrames, fmt.Errorf("websocket register failed: %w", err)
+	}
+	conn.touch()
+
+	registered := false
+	for {
+		rawMsg, err := ws.ReadText()
+		if err != nil {
+			select {
+			case <-done:
+				return gotFrames, nil
+			default:
+			}
+			if ctx.Err() != nil {
+				return gotFrames, nil
+			}
+			if resync.Load() {
+				return gotFrames, errResync
+			}
+			return gotFrames, fmt.Errorf("websocket read failed (reconnecting, polling fallback active): %w", err)
+		}
+		msg := map[string]interface{}{}
+		if err := json.Unmarshal(rawMsg, &msg); err != nil {
+			continue
+		}
+		typeVal, _ := msg["type"].(string)
+		text := ""
+		if m, ok := msg["message"]; ok {
+			b, _ := json.Marshal(m)
+			text = string(b)
+		}
+		if !registered {
+			if registrationRejected(typeVal, text) {
+				return gotFrames, fmt.Errorf("%w: %s", errRegisterRejected, strings.TrimSpace(typeVal+" "+text))
+			}
+			registered = true
+		}
+		if onEvent != nil {
+			onEvent(WatchEvent{Source: "ws", Type: typeVal, Text: text, Raw: msg})
+		}
+		if isTerminal(typeVal) {
+			task, termErr := s.fetchTerminalDetail(ctx, taskToken, headers, 6)
+			if termErr == nil && task != nil {
+				signalFinal(task)
+				return gotFrames, nil
+			}
+		}
+	}
+}
+
+// connTracker derives live/degraded state from websocket heartbeats and reports changes.
+type connTracker struct {
+	mu       sync.Mutex
+	state    string
+	lastSeen time.Time
+	down     bool
+	onEvent  func(WatchEvent)
+}
+
+func newConnTracker(onEvent func(WatchEvent)) *connTracker {
+	return &connTracker{onEvent: onEvent}
+}
+
+// touch records a websocket frame and flips the state back to live.
+func (c *connTracker) touch() {
+	c.mu.Lock()
+	c.lastSeen = time.Now()
+	c.down = false
+	changed := c.setLocked(ConnStateLive)
+	c.mu.Unlock()
+	c.emit(changed)
+}
+
+// markDown reports that the websocket is gone and only polling remains.
+func (c *connTracker) markDown() {
+	c.mu.Lock()
+	c.down = true
+	changed := c.setLocked(ConnStateDegraded)
+	c.mu.Unlock()
+	c.emit(changed)
+}
+
+// checkStale degrades the state when the socket has been silent past the heartbeat timeout.
+func (c *connTracker) checkStale(now time.Time) {
+	c.mu.Lock()
+	changed := ""
+	if !c.down && !c.lastSeen.IsZero() && now.Sub(c.lastSeen) > wsHeartbeatTimeout {
+		changed = c.setLocked(ConnStateDegraded)
+	}
+	c.mu.Unlock()
+	c.emit(changed)
+}
+
+func (c *connTracker) setLocked(state string) string {
+	if c.state == state {
+		return ""
+	}
+	c.state = state
+	return state
+}
+
+func (c *connTracker) emit(state string) {
+	if state == "" || c.onEvent == nil {
+		return
+	}
+	c.onEvent(WatchEvent{Source: "system", Type: "connection", Text: state})
+}
+
 func looksLikeNumeric(v string) bool {
 	if v == "" {
 		return false
@@ -247,11 +564,42 @@ func (s *Service) fetchTerminalDetail(ctx context.Context, taskToken string, hea
 }
 
 type wsConn struct {
-	conn   net.Conn
-	reader *bufio.Reader
+	conn    net.Conn
+	reader  *bufio.Reader
+	writeMu sync.Mutex
+	onFrame func()
+}
+
+// wsSession starts the span covering one websocket session that watches the
+// given number of tasks and returns the function that ends it. A resync after
+// sleep is not a failure.
+func wsSession(ctx context.Context, tasks int) (context.Context, func(error)) {
+	host := ""
+	if u, err := url.Parse(wsURL); err == nil {
+		host = u.Hostname()
+	}
+	ctx, span := telemetry.StartSpan(ctx, "websocket session", telemetry.KindClient,
+		telemetry.String("server.address", host), telemetry.Int("wiro.ws.tasks", int64(tasks)))
+	return ctx, func(err error) {
+		if errors.Is(err, errResync) {
+			err = nil
+		}
+		span.End(err)
+	}
 }
 
+// dialWS opens a websocket and counts the attempt in WebSocketSessions.
 func dialWS(ctx context.Context, endpoint string) (*wsConn, error) {
+	ws, err := dialWSConn(ctx, endpoint)
+	if err != nil {
+		metrics.WebSocketSessions.Inc("failed")
+		return nil, err
+	}
+	metrics.WebSocketSessions.Inc("connected")
+	return ws, nil
+}
+
+func dialWSConn(ctx context.Context, endpoint string) (*wsConn, error) {
 	u, err := url.Parse(endpoint)
 	if err != nil {
 		return nil, err
@@ -265,6 +613,7 @@ func dialWS(ctx context.Context, endpoint string) (*wsConn, error) {
 		}
 	}
 
+	log.Debugf("ws -> dial %s", endpoint)
 	dialer := &net.Dialer{}
 	rawConn, err := dialer.DialContext(ctx, "tcp", host)
 	if err != nil {
@@ -334,6 +683,7 @@ func dialWS(ctx context.Context, endpoint string) (*wsConn, error) {
 		return nil, errors.New("websocket accept key mismatch")
 	}
 
+	log.Debugf("ws <- handshake ok %s", strings.TrimSpace(statusLine))
 	return &wsConn{conn: conn, reader: br}, nil
 }
 
@@ -362,6 +712,7 @@ func (w *wsConn) WriteJSON(v interface{}) error {
 	if err != nil {
 		return err
 	}
+	log.Debugf("ws -> text %s", payload)
 	return w.writeFrame(0x1, payload)
 }
 
@@ -369,8 +720,10 @@ func (w *wsConn) ReadText() ([]byte, error) {
 	for {
 		opcode, payload, err := w.readFrame()
 		if err != nil {
+			log.Debugf("ws <- read error: %v", err)
 			return nil, err
 		}
+		log.Debugf("ws <- frame opcode=0x%x bytes=%d", opcode, len(payload))
 		switch opcode {
 		case 0x1:
 			return payload, nil
@@ -388,7 +741,18 @@ func (w *wsConn) ReadText() ([]byte, error) {
 	}
 }
 
+// Ping sends a control ping; the server's pong counts as a heartbeat frame.
+func (w *wsConn) Ping() error {
+	log.Debugf("ws -> ping")
+	return w.writeFrame(0x9, nil)
+}
+
 func (w *wsConn) writeFrame(opcode byte, payload []byte) error {
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+	if err := w.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout)); err != nil {
+		return err
+	}
 	head := make([]byte, 0, 14)
 	head = append(head, 0x80|(opcode&0x0F))
 
@@ -427,10 +791,16 @@ func (w *wsConn) writeFrame(opcode byte, payload []byte) error {
 }
 
 func (w *wsConn) readFrame() (byte, []byte, error) {
+	if err := w.conn.SetReadDeadline(time.Now().Add(wsReadTimeout)); err != nil {
+		return 0, nil, err
+	}
 	header := make([]byte, 2)
 	if _, err := io.ReadFull(w.reader, header); err != nil {
 		return 0, nil, err
 	}
+	if w.onFrame != nil {
+		w.onFrame()
+	}
 	opcode := header[0] & 0x0F
 	masked := (header[1] & 0x80) != 0
 	length := int64(header[1] & 0x7F)
@@ -1,6 +1,22 @@
 package task
 
-import "testing"
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
 
 func TestIsTerminal_Statuses(t *testing.T) {
 	tests := []struct {
@@ -21,3 +37,217 @@ func TestIsTerminal_Statuses(t *testing.T) {
 		}
 	}
 }
+
+func TestConnTracker_ReportsStateChanges(t *testing.T) {
+	var states []string
+	tracker := newConnTracker(func(ev WatchEvent) {
+		states = append(states, ev.Text)
+	})
+
+	tracker.touch()
+	tracker.touch()
+	tracker.checkStale(time.Now().Add(wsHeartbeatTimeout + time.Second))
+	tracker.touch()
+	tracker.markDown()
+	tracker.checkStale(time.Now().Add(2 * wsHeartbeatTimeout))
+
+	want := []string{ConnStateLive, ConnStateDegraded, ConnStateLive, ConnStateDegraded}
+	if len(states) != len(want) {
+		t.Fatalf("unexpected states: %v", states)
+	}
+	for i := range want {
+		if states[i] != want[i] {
+			t.Fatalf("state[%d] = %q, want %q", i, states[i], want[i])
+		}
+	}
+}
+
+func TestWSConn_PingAndReadDeadline(t *testing.T) {
+	prev := wsReadTimeout
+	wsReadTimeout = 100 * time.Millisecond
+	defer func() { wsReadTimeout = prev }()
+
+	client, server := net.Pipe()
+	defer server.Close()
+	ws := &wsConn{conn: client, reader: bufio.NewReader(client)}
+	defer ws.Close()
+	peer := &wsConn{conn: server, reader: bufio.NewReader(server)}
+
+	go func() { _ = ws.Ping() }()
+	opcode, _, err := peer.readFrame()
+	if err != nil || opcode != 0x9 {
+		t.Fatalf("expected ping frame, got opcode=0x%x err=%v", opcode, err)
+	}
+
+	start := time.Now()
+	if _, err := ws.ReadText(); err == nil {
+		t.Fatalf("expected read deadline error on a silent connection")
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Fatalf("read did not honor the deadline")
+	}
+}
+
+func TestWatchTask_PollsByIDWithoutSocketToken(t *testing.T) {
+	prev := pollInterval
+	pollInterval = 10 * time.Millisecond
+	defer func() { pollInterval = prev }()
+
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"taskid":"42"`) {
+			t.Errorf("expected poll by task id, got %s", body)
+		}
+		polls++
+		status := "task_start"
+		if polls > 1 {
+			status = "task_postprocess_end"
+		}
+		_ = json.NewEncoder(w).Encode(api.TaskDetailResponse{
+			GenericResponse: api.GenericResponse{Result: true},
+			TaskList:        []api.Task{{ID: "42", Status: status}},
+		})
+	}))
+	defer srv.Close()
+
+	var warned bool
+	svc := NewService(api.NewClient(srv.URL))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	final, err := svc.WatchTask(ctx, "", nil, WatchOptions{
+		TaskID: "42",
+		OnEvent: func(ev WatchEvent) {
+			if ev.Source == "system" && ev.Type == "warning" && strings.Contains(ev.Text, "no socket token") {
+				warned = true
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("WatchTask: %v", err)
+	}
+	if final == nil || final.Status != "task_postprocess_end" {
+		t.Fatalf("unexpected final task %+v", final)
+	}
+	if !warned {
+		t.Fatal("expected a system warning about degraded mode")
+	}
+
+	if _, err := svc.WatchTask(ctx, "", nil, WatchOptions{}); err == nil {
+		t.Fatal("expected error without token or task id")
+	}
+}
+
+func TestWatchTask_PollIntervalOption(t *testing.T) {
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		status := "task_start"
+		if polls > 2 {
+			status = "task_error_full"
+		}
+		_ = json.NewEncoder(w).Encode(api.TaskDetailResponse{
+			GenericResponse: api.GenericResponse{Result: true},
+			TaskList:        []api.Task{{ID: "7", Status: status}},
+		})
+	}))
+	defer srv.Close()
+
+	svc := NewService(api.NewClient(srv.URL))
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	// The default interval would outlast the context.
+	final, err := svc.WatchTask(ctx, "", nil, WatchOptions{TaskID: "7", PollInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("WatchTask: %v", err)
+	}
+	if final.Status != "task_error_full" {
+		t.Fatalf("final status = %q", final.Status)
+	}
+}
+
+func TestRun_SendsCallbackURL(t *testing.T) {
+	var form map[string][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Errorf("parse form: %v", err)
+		}
+		form = r.MultipartForm.Value
+		_ = json.NewEncoder(w).Encode(api.RunResponse{GenericResponse: api.GenericResponse{Result: true}, TaskID: "7"})
+	}))
+	defer srv.Close()
+
+	svc := NewService(api.NewClient(srv.URL))
+	inputs := map[string][]api.MultipartValue{"prompt": {{Value: "a cat"}}}
+	if _, err := svc.Run(context.Background(), "wiro", "flux", inputs, nil, RunOptions{CallbackURL: "https://example.com/hook"}); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if got := form[CallbackURLField]; len(got) != 1 || got[0] != "https://example.com/hook" {
+		t.Fatalf("callback field = %v", got)
+	}
+	if _, ok := inputs[CallbackURLField]; ok {
+		t.Fatal("Run must not add the callback to the caller's inputs")
+	}
+}
+
+func TestRun_LargeFileUpload(t *testing.T) {
+	defer func(v int64) { chunkedUploadThreshold = v }(chunkedUploadThreshold)
+	chunkedUploadThreshold = 10
+
+	dir := t.TempDir()
+	big := filepath.Join(dir, "big.bin")
+	small := filepath.Join(dir, "small.txt")
+	_ = os.WriteFile(big, []byte(strings.Repeat("b", 64)), 0o600)
+	_ = os.WriteFile(small, []byte("s"), 0o600)
+	inputs := map[string][]api.MultipartValue{"video": {{FilePath: big}}, "mask": {{FilePath: small}}}
+
+	for _, chunked := range []bool{true, false} {
+		var form *multipart.Form
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/File/Upload/Init":
+				if !chunked {
+					http.NotFound(w, r)
+					return
+				}
+				_ = json.NewEncoder(w).Encode(api.UploadInitResponse{GenericResponse: api.GenericResponse{Result: true}, UploadID: "u1"})
+			case "/File/Upload/Chunk":
+				_, _ = io.Copy(io.Discard, r.Body)
+				_ = json.NewEncoder(w).Encode(api.GenericResponse{Result: true})
+			case "/File/Upload/Complete":
+				_ = json.NewEncoder(w).Encode(api.UploadCompleteResponse{GenericResponse: api.GenericResponse{Result: true}, URL: "https://cdn.example/big.bin"})
+			default:
+				if err := r.ParseMultipartForm(1 << 20); err != nil {
+					t.Errorf("parse form: %v", err)
+				}
+				form = r.MultipartForm
+				_ = json.NewEncoder(w).Encode(api.RunResponse{GenericResponse: api.GenericResponse{Result: true}, TaskID: "7"})
+			}
+		}))
+
+		var last, lastTotal int64
+		progress := func(done, total int64) {
+			if done < last || total < lastTotal {
+				t.Errorf("chunked=%v: progress went back from %d/%d to %d/%d", chunked, last, lastTotal, done, total)
+			}
+			last, lastTotal = done, total
+		}
+		svc := NewService(api.NewClient(srv.URL))
+		if _, err := svc.Run(context.Background(), "wiro", "flux", inputs, nil, RunOptions{OnUploadProgress: progress}); err != nil {
+			t.Fatalf("chunked=%v: Run: %v", chunked, err)
+		}
+		srv.Close()
+		if len(form.File["mask"]) != 1 {
+			t.Fatalf("chunked=%v: small file not sent inline: %+v", chunked, form.File)
+		}
+		if chunked && (form.Value["video"][0] != "https://cdn.example/big.bin" || len(form.File["video"]) != 0) {
+			t.Fatalf("large file should be sent by URL: %+v", form)
+		}
+		if !chunked && len(form.File["video"]) != 1 {
+			t.Fatalf("without chunked upload the large file should go inline: %+v", form.File)
+		}
+		if last != lastTotal || last == 0 {
+			t.Fatalf("chunked=%v: progress ended at %d/%d", chunked, last, lastTotal)
+		}
+	}
+}
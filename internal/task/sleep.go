@@ -0,0 +1,88 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Sleep detection timing; vars so tests can shorten them.
+var (
+	// sleepCheckInterval is how often the watch checks the clocks for a gap.
+	sleepCheckInterval = 5 * time.Second
+	// sleepGapThreshold is how much longer than sleepCheckInterval a tick may take
+	// before the machine is assumed to have slept.
+	sleepGapThreshold = 30 * time.Second
+)
+
+// errResync closes a websocket session so it is redialed right away after sleep.
+var errResync = errors.New("resync after sleep")
+
+// wakeSignal broadcasts resume events to every goroutine of a watch.
+type wakeSignal struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+func newWakeSignal() *wakeSignal {
+	return &wakeSignal{ch: make(chan struct{})}
+}
+
+// C returns a channel that is closed on the next wake.
+func (w *wakeSignal) C() <-chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.ch
+}
+
+func (w *wakeSignal) fire() {
+	w.mu.Lock()
+	close(w.ch)
+	w.ch = make(chan struct{})
+	w.mu.Unlock()
+}
+
+// sleepGap returns how much longer than interval passed between prev and now.
+// The monotonic clock stops during suspend on Linux and macOS but not on
+// Windows, so the larger of the monotonic and wall-clock readings is used.
+func sleepGap(prev, now time.Time, interval time.Duration) time.Duration {
+	elapsed := now.Sub(prev)
+	if wall := now.Round(0).Sub(prev.Round(0)); wall > elapsed {
+		elapsed = wall
+	}
+	return elapsed - interval
+}
+
+// watchForSleep fires wake and reports the gap whenever a check tick arrives
+// far later than scheduled, which means the machine was suspended.
+func watchForSleep(ctx context.Context, done <-chan struct{}, wake *wakeSignal, onEvent func(WatchEvent)) {
+	ticker := time.NewTicker(sleepCheckInterval)
+	defer ticker.Stop()
+	prev := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-done:
+			return
+		case now := <-ticker.C:
+			now = time.Now()
+			gap := sleepGap(prev, now, sleepCheckInterval)
+			prev = now
+			if gap < sleepGapThreshold {
+				continue
+			}
+			if onEvent != nil {
+				onEvent(WatchEvent{
+					Source: "system",
+					Type:   "resume",
+					Text:   fmt.Sprintf("clock jumped %s (machine slept?); polling and reconnecting now", gap.Round(time.Second)),
+					Raw:    map[string]interface{}{"gapSeconds": gap.Seconds()},
+				})
+			}
+			wake.fire()
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+func TestSleepGap(t *testing.T) {
+	now := time.Now()
+	if gap := sleepGap(now, now.Add(5*time.Second), 5*time.Second); gap != 0 {
+		t.Fatalf("on-time tick gap = %v", gap)
+	}
+	// Wall-clock readings only, as after a suspend that paused the monotonic clock.
+	wall := now.Round(0)
+	if gap := sleepGap(wall, wall.Add(10*time.Minute), 5*time.Second); gap != 10*time.Minute-5*time.Second {
+		t.Fatalf("sleep gap = %v", gap)
+	}
+}
+
+func TestWakeSignal_FiresOnce(t *testing.T) {
+	w := newWakeSignal()
+	first := w.C()
+	w.fire()
+	select {
+	case <-first:
+	default:
+		t.Fatal("fire should close the current channel")
+	}
+	select {
+	case <-w.C():
+		t.Fatal("next channel should stay open until the next wake")
+	default:
+	}
+}
+
+func TestWatchTask_PollsImmediatelyAfterSleep(t *testing.T) {
+	prevPoll, prevCheck, prevThreshold := pollInterval, sleepCheckInterval, sleepGapThreshold
+	// Polling alone would never fire; every check tick counts as a wake.
+	pollInterval = time.Hour
+	sleepCheckInterval = 10 * time.Millisecond
+	sleepGapThreshold = -time.Hour
+	defer func() { pollInterval, sleepCheckInterval, sleepGapThreshold = prevPoll, prevCheck, prevThreshold }()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(api.TaskDetailResponse{
+			GenericResponse: api.GenericResponse{Result: true},
+			TaskList:        []api.Task{{ID: "7", Status: "task_postprocess_end"}},
+		})
+	}))
+	defer srv.Close()
+
+	var resumed atomic.Bool
+	svc := NewService(api.NewClient(srv.URL))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	final, err := svc.WatchTask(ctx, "", nil, WatchOptions{
+		TaskID: "7",
+		OnEvent: func(ev WatchEvent) {
+			if ev.Source == "system" && ev.Type == "resume" {
+				resumed.Store(true)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("WatchTask: %v", err)
+	}
+	if final == nil || final.Status != "task_postprocess_end" {
+		t.Fatalf("unexpected final task %+v", final)
+	}
+	if !resumed.Load() {
+		t.Fatal("expected a resume event in the timeline")
+	}
+}
@@ -0,0 +1,112 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+// subscriptionBuffer is how many events a Subscription holds for a slow reader
+// before the watch waits for it.
+const subscriptionBuffer = 64
+
+// Subscription is a running task watch. Its events arrive on Events, which is
+// closed when the watch ends; Wait then returns the final task. Background
+// errors (socket drops, failed polls) are delivered as system warning events
+// rather than ending the watch.
+type Subscription struct {
+	events chan WatchEvent
+	stop   chan struct{}
+	ended  chan struct{}
+
+	stopOnce sync.Once
+	mu       sync.Mutex
+	closed   bool
+
+	final *api.Task
+	err   error
+}
+
+// Subscribe starts watching a task and returns at once. Without a socket token
+// it polls by taskID only. The caller must drain Events (or call Close) so the
+// watch is not held up by a full buffer; cancelling ctx ends the watch.
+func (s *Service) Subscribe(ctx context.Context, taskToken string, headers map[string]string, taskID string) (*Subscription, error) {
+	return s.subscribe(ctx, taskToken, headers, taskID, 0)
+}
+
+func (s *Service) subscribe(ctx context.Context, taskToken string, headers map[string]string, taskID string, interval time.Duration) (*Subscription, error) {
+	taskToken = strings.TrimSpace(taskToken)
+	pollKey := taskToken
+	if pollKey == "" {
+		pollKey = strings.TrimSpace(taskID)
+	}
+	if pollKey == "" {
+		return nil, errors.New("task token or task id is required for watch")
+	}
+	sub := &Subscription{
+		events: make(chan WatchEvent, subscriptionBuffer),
+		stop:   make(chan struct{}),
+		ended:  make(chan struct{}),
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	go func() {
+		defer cancel()
+		done := make(chan struct{})
+		go func() {
+			select {
+			case <-sub.stop:
+				cancel()
+			case <-done:
+			}
+		}()
+		sub.final, sub.err = s.watch(ctx, done, taskToken, pollKey, headers, interval, sub.emit)
+		close(done)
+		sub.mu.Lock()
+		sub.closed = true
+		close(sub.events)
+		sub.mu.Unlock()
+		close(sub.ended)
+	}()
+	return sub, nil
+}
+
+// Events returns the watch's event stream.
+func (sub *Subscription) Events() <-chan WatchEvent {
+	return sub.events
+}
+
+// Wait blocks until the watch ends and returns the final task, or the error
+// that ended it (ctx.Err() when it was cancelled or closed).
+func (sub *Subscription) Wait() (*api.Task, error) {
+	<-sub.ended
+	return sub.final, sub.err
+}
+
+// Close stops the watch without waiting for the task to finish, and discards
+// events that were not read.
+func (sub *Subscription) Close() {
+	sub.stopOnce.Do(func() { close(sub.stop) })
+	go func() {
+		for range sub.events {
+		}
+	}()
+	<-sub.ended
+}
+
+// emit queues ev for the reader. It gives up when the subscription is closed,
+// so background goroutines never block on a reader that went away.
+func (sub *Subscription) emit(ev WatchEvent) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	if sub.closed {
+		return
+	}
+	select {
+	case sub.events <- ev:
+	case <-sub.stop:
+	}
+}
@@ -0,0 +1,90 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+)
+
+func TestSubscribe_EventsThenFinalTask(t *testing.T) {
+	prev := pollInterval
+	pollInterval = 10 * time.Millisecond
+	defer func() { pollInterval = prev }()
+
+	polls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		status := "task_start"
+		if polls > 2 {
+			status = "task_postprocess_end"
+		}
+		_ = json.NewEncoder(w).Encode(api.TaskDetailResponse{
+			GenericResponse: api.GenericResponse{Result: true},
+			TaskList:        []api.Task{{ID: "42", Status: status}},
+		})
+	}))
+	defer srv.Close()
+
+	svc := NewService(api.NewClient(srv.URL))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	sub, err := svc.Subscribe(ctx, "", nil, "42")
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	var polled []string
+	for ev := range sub.Events() {
+		if ev.Source == "poll" {
+			polled = append(polled, ev.Type)
+		}
+	}
+	final, err := sub.Wait()
+	if err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if final == nil || final.Status != "task_postprocess_end" {
+		t.Fatalf("unexpected final task %+v", final)
+	}
+	if len(polled) != 3 || polled[2] != "task_postprocess_end" {
+		t.Fatalf("unexpected poll events %v", polled)
+	}
+}
+
+func TestSubscribe_CloseStopsUnreadWatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(api.TaskDetailResponse{
+			GenericResponse: api.GenericResponse{Result: true},
+			TaskList:        []api.Task{{ID: "42", Status: "task_start"}},
+		})
+	}))
+	defer srv.Close()
+
+	svc := NewService(api.NewClient(srv.URL))
+	sub, err := svc.Subscribe(context.Background(), "", nil, "42")
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+	closed := make(chan struct{})
+	go func() {
+		sub.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close did not stop the watch")
+	}
+	if _, err := sub.Wait(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled after Close, got %v", err)
+	}
+
+	if _, err := svc.Subscribe(context.Background(), "", nil, ""); err == nil {
+		t.Fatal("expected error without token or task id")
+	}
+}
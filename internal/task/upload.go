@@ -0,0 +1,85 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/wiro-ai/wiro-cli/internal/api"
+	"github.com/wiro-ai/wiro-cli/internal/log"
+)
+
+// chunkedUploadThreshold is the file size above which inputs are uploaded in
+// resumable chunks instead of inside the run's multipart request.
+var chunkedUploadThreshold int64 = 100 << 20
+
+// uploadLargeFiles uploads file inputs over the threshold through a chunked
+// session and returns values with those files replaced by their URLs, and the
+// bytes uploaded that way. Small files stay in the multipart request, and so
+// do large ones when the server has no chunked upload. values is not modified.
+//
+// onProgress is called with the total of every file, so the multipart request
+// that follows can continue the count from uploaded.
+func (s *Service) uploadLargeFiles(ctx context.Context, values map[string][]api.MultipartValue, headers map[string]string, onProgress api.ProgressFunc) (out map[string][]api.MultipartValue, uploaded int64, err error) {
+	var total int64
+	large := map[string]int64{}
+	var order []string
+	seen := map[string]bool{}
+	for _, arr := range values {
+		for _, v := range arr {
+			if v.FilePath == "" || seen[v.FilePath] {
+				continue
+			}
+			seen[v.FilePath] = true
+			info, err := os.Stat(v.FilePath)
+			if err != nil {
+				continue
+			}
+			total += info.Size()
+			if info.Size() > chunkedUploadThreshold {
+				large[v.FilePath] = info.Size()
+				order = append(order, v.FilePath)
+			}
+		}
+	}
+	if len(large) == 0 {
+		return values, 0, nil
+	}
+
+	urls := map[string]string{}
+	for _, path := range order {
+		opts := api.ChunkedUploadOptions{}
+		if onProgress != nil {
+			offset := uploaded
+			opts.OnProgress = func(done, _ int64) { onProgress(offset+done, total) }
+		}
+		log.Verbosef("upload: %s is %d bytes; using chunked upload", path, large[path])
+		url, err := s.apiClient.UploadFileChunked(ctx, path, headers, opts)
+		if errors.Is(err, api.ErrChunkedUploadUnsupported) {
+			log.Verbosef("upload: %v; sending the remaining files inside the run request", err)
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("upload %s: %w", path, err)
+		}
+		urls[path] = url
+		uploaded += large[path]
+	}
+	if len(urls) == 0 {
+		return values, 0, nil
+	}
+
+	out = make(map[string][]api.MultipartValue, len(values))
+	for key, arr := range values {
+		next := make([]api.MultipartValue, len(arr))
+		for i, v := range arr {
+			if url, ok := urls[v.FilePath]; ok && v.FilePath != "" {
+				v = api.MultipartValue{Value: url}
+			}
+			next[i] = v
+		}
+		out[key] = next
+	}
+	return out, uploaded, nil
+}
@@ -0,0 +1,295 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/wiro-ai/wiro-cli/internal/log"
+	"github.com/wiro-ai/wiro-cli/internal/metrics"
+)
+
+// maxQueuedSpans caps the spans held between exports; later ones are dropped.
+const maxQueuedSpans = 2048
+
+const scopeName = "github.com/wiro-ai/wiro-cli"
+
+type spanData struct {
+	traceID  [16]byte
+	spanID   [8]byte
+	parentID [8]byte
+	name     string
+	kind     SpanKind
+	start    time.Time
+	end      time.Time
+	attrs    []Attr
+	errMsg   string
+}
+
+// exporter batches spans and posts them, with the process counters, to the
+// OTLP endpoints. Failed exports are logged and dropped.
+type exporter struct {
+	cfg     Config
+	client  *http.Client
+	started time.Time
+
+	mu      sync.Mutex
+	spans   []spanData
+	dropped int
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newExporter(cfg Config) *exporter {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.SpanInterval <= 0 {
+		cfg.SpanInterval = 5 * time.Second
+	}
+	if cfg.MetricInterval <= 0 {
+		cfg.MetricInterval = 60 * time.Second
+	}
+	return &exporter{
+		cfg:     cfg,
+		client:  &http.Client{Timeout: cfg.Timeout},
+		started: time.Now(),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+}
+
+func (e *exporter) addSpan(s spanData) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.spans) >= maxQueuedSpans {
+		e.dropped++
+		return
+	}
+	e.spans = append(e.spans, s)
+}
+
+// run exports on the configured intervals until shutdown.
+func (e *exporter) run() {
+	go func() {
+		defer close(e.done)
+		spans := time.NewTicker(e.cfg.SpanInterval)
+		defer spans.Stop()
+		counters := time.NewTicker(e.cfg.MetricInterval)
+		defer counters.Stop()
+		for {
+			select {
+			case <-e.stop:
+				return
+			case <-spans.C:
+				e.exportSpans()
+			case <-counters.C:
+				e.exportMetrics()
+			}
+		}
+	}()
+}
+
+func (e *exporter) shutdown() {
+	close(e.stop)
+	<-e.done
+	e.exportSpans()
+	e.exportMetrics()
+}
+
+func (e *exporter) exportSpans() {
+	if e.cfg.TracesURL == "" {
+		return
+	}
+	e.mu.Lock()
+	batch, dropped := e.spans, e.dropped
+	e.spans, e.dropped = nil, 0
+	e.mu.Unlock()
+	if dropped > 0 {
+		log.Verbosef("telemetry: dropped %d span(s) over the queue limit", dropped)
+	}
+	if len(batch) == 0 {
+		return
+	}
+	spans := make([]otlpSpan, 0, len(batch))
+	for _, s := range batch {
+		spans = append(spans, s.otlp())
+	}
+	e.post(e.cfg.TracesURL, e.cfg.TracesHeaders, map[string]interface{}{
+		"resourceSpans": []interface{}{map[string]interface{}{
+			"resource":   map[string]interface{}{"attributes": keyValues(e.cfg.Resource)},
+			"scopeSpans": []interface{}{map[string]interface{}{"scope": map[string]string{"name": scopeName}, "spans": spans}},
+		}},
+	})
+}
+
+// exportMetrics sends every counter as a cumulative monotonic sum.
+func (e *exporter) exportMetrics() {
+	if e.cfg.MetricsURL == "" {
+		return
+	}
+	now := nanos(time.Now())
+	start := nanos(e.started)
+	var order []string
+	byName := map[string]*otlpMetric{}
+	for _, s := range metrics.Snapshot() {
+		m, ok := byName[s.Name]
+		if !ok {
+			m = &otlpMetric{Name: s.Name, Description: s.Help}
+			m.Sum.AggregationTemporality = 2 // cumulative
+			m.Sum.IsMonotonic = true
+			byName[s.Name] = m
+			order = append(order, s.Name)
+		}
+		point := otlpDataPoint{StartTimeUnixNano: start, TimeUnixNano: now, AsDouble: s.Value}
+		if s.Label != "" {
+			point.Attributes = keyValues([]Attr{String(s.Label, s.LabelValue)})
+		}
+		m.Sum.DataPoints = append(m.Sum.DataPoints, point)
+	}
+	if len(order) == 0 {
+		return
+	}
+	list := make([]*otlpMetric, 0, len(order))
+	for _, name := range order {
+		list = append(list, byName[name])
+	}
+	e.post(e.cfg.MetricsURL, e.cfg.MetricsHeaders, map[string]interface{}{
+		"resourceMetrics": []interface{}{map[string]interface{}{
+			"resource":     map[string]interface{}{"attributes": keyValues(e.cfg.Resource)},
+			"scopeMetrics": []interface{}{map[string]interface{}{"scope": map[string]string{"name": scopeName}, "metrics": list}},
+		}},
+	})
+}
+
+func (e *exporter) post(endpoint string, headers map[string]string, payload interface{}) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Verbosef("telemetry: encode: %v", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), e.cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		log.Verbosef("telemetry: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := e.client.Do(req)
+	if err != nil {
+		log.Verbosef("telemetry: export to %s: %v", endpoint, err)
+		return
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode >= 300 {
+		log.Verbosef("telemetry: export to %s: status %d", endpoint, resp.StatusCode)
+	}
+}
+
+// The OTLP/JSON shapes: ids are hex, 64-bit integers are decimal strings.
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            *otlpStatus    `json:"status,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpMetric struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Sum         struct {
+		DataPoints             []otlpDataPoint `json:"dataPoints"`
+		AggregationTemporality int             `json:"aggregationTemporality"`
+		IsMonotonic            bool            `json:"isMonotonic"`
+	} `json:"sum"`
+}
+
+type otlpDataPoint struct {
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	TimeUnixNano      string         `json:"timeUnixNano"`
+	AsDouble          float64        `json:"asDouble"`
+}
+
+type otlpKeyValue struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+func (s spanData) otlp() otlpSpan {
+	out := otlpSpan{
+		TraceID:           hex.EncodeToString(s.traceID[:]),
+		SpanID:            hex.EncodeToString(s.spanID[:]),
+		Name:              s.name,
+		Kind:              int(s.kind),
+		StartTimeUnixNano: nanos(s.start),
+		EndTimeUnixNano:   nanos(s.end),
+		Attributes:        keyValues(s.attrs),
+	}
+	if s.parentID != [8]byte{} {
+		out.ParentSpanID = hex.EncodeToString(s.parentID[:])
+	}
+	if s.errMsg != "" {
+		out.Status = &otlpStatus{Code: 2, Message: s.errMsg} // STATUS_CODE_ERROR
+	}
+	return out
+}
+
+func keyValues(attrs []Attr) []otlpKeyValue {
+	out := make([]otlpKeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		var v map[string]interface{}
+		switch x := a.Value.(type) {
+		case string:
+			v = map[string]interface{}{"stringValue": x}
+		case int64:
+			v = map[string]interface{}{"intValue": strconv.FormatInt(x, 10)}
+		case bool:
+			v = map[string]interface{}{"boolValue": x}
+		case float64:
+			v = map[string]interface{}{"doubleValue": x}
+		default:
+			v = map[string]interface{}{"stringValue": fmt.Sprint(x)}
+		}
+		out = append(out, otlpKeyValue{Key: a.Key, Value: v})
+	}
+	return out
+}
+
+func nanos(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,310 @@
+// Package telemetry exports traces and metrics over OTLP/HTTP with JSON
+// encoding when the standard OpenTelemetry environment variables configure an
+// endpoint. Without one, every function here is a cheap no-op.
+package telemetry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config is what the OTEL_* environment variables ask for.
+type Config struct {
+	// TracesURL and MetricsURL are the full OTLP/HTTP endpoints; an empty one
+	// turns that signal off.
+	TracesURL  string
+	MetricsURL string
+	// TracesHeaders and MetricsHeaders are sent with each export request.
+	TracesHeaders  map[string]string
+	MetricsHeaders map[string]string
+	// Resource describes this process: service.name, service.version, and
+	// OTEL_RESOURCE_ATTRIBUTES.
+	Resource []Attr
+	// Timeout bounds one export request.
+	Timeout time.Duration
+	// SpanInterval and MetricInterval are how often the buffered spans and
+	// the counters are exported; both are also exported at shutdown.
+	SpanInterval   time.Duration
+	MetricInterval time.Duration
+	// Parent is the W3C traceparent of the caller (TRACEPARENT), so a
+	// pipeline's trace continues into the CLI.
+	Parent string
+}
+
+// ConfigFromEnv reads the OpenTelemetry environment variables that apply to
+// an OTLP/HTTP exporter. ok is false when no endpoint is set or the SDK is
+// disabled. Only the http/json protocol is supported.
+func ConfigFromEnv(getenv func(string) string, version string) (cfg Config, ok bool, err error) {
+	if strings.EqualFold(strings.TrimSpace(getenv("OTEL_SDK_DISABLED")), "true") {
+		return Config{}, false, nil
+	}
+	base := strings.TrimRight(strings.TrimSpace(getenv("OTEL_EXPORTER_OTLP_ENDPOINT")), "/")
+	endpoint := func(signal, path string) string {
+		if strings.EqualFold(strings.TrimSpace(getenv("OTEL_"+signal+"_EXPORTER")), "none") {
+			return ""
+		}
+		if v := strings.TrimSpace(getenv("OTEL_EXPORTER_OTLP_" + signal + "_ENDPOINT")); v != "" {
+			return v
+		}
+		if base == "" {
+			return ""
+		}
+		return base + path
+	}
+	cfg.TracesURL = endpoint("TRACES", "/v1/traces")
+	cfg.MetricsURL = endpoint("METRICS", "/v1/metrics")
+	if cfg.TracesURL == "" && cfg.MetricsURL == "" {
+		return Config{}, false, nil
+	}
+	for _, signal := range []string{"", "TRACES_", "METRICS_"} {
+		if p := strings.TrimSpace(getenv("OTEL_EXPORTER_OTLP_" + signal + "PROTOCOL")); p != "" && p != "http/json" {
+			return Config{}, false, fmt.Errorf("OTEL_EXPORTER_OTLP_%sPROTOCOL=%s is not supported; use http/json", signal, p)
+		}
+	}
+	for _, u := range []string{cfg.TracesURL, cfg.MetricsURL} {
+		if u == "" {
+			continue
+		}
+		if parsed, err := url.Parse(u); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return Config{}, false, fmt.Errorf("invalid OTLP endpoint %q", u)
+		}
+	}
+
+	common := parseList(getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+	cfg.TracesHeaders = mergeMaps(common, parseList(getenv("OTEL_EXPORTER_OTLP_TRACES_HEADERS")))
+	cfg.MetricsHeaders = mergeMaps(common, parseList(getenv("OTEL_EXPORTER_OTLP_METRICS_HEADERS")))
+
+	resource := parseList(getenv("OTEL_RESOURCE_ATTRIBUTES"))
+	name := strings.TrimSpace(getenv("OTEL_SERVICE_NAME"))
+	if name == "" {
+		name = resource["service.name"]
+	}
+	if name == "" {
+		name = "wiro-cli"
+	}
+	cfg.Resource = []Attr{String("service.name", name), String("service.version", version)}
+	for _, k := range sortedKeys(resource) {
+		if k != "service.name" && k != "service.version" {
+			cfg.Resource = append(cfg.Resource, String(k, resource[k]))
+		}
+	}
+
+	cfg.Timeout = envMillis(getenv, "OTEL_EXPORTER_OTLP_TIMEOUT", 10*time.Second)
+	cfg.SpanInterval = envMillis(getenv, "OTEL_BSP_SCHEDULE_DELAY", 5*time.Second)
+	cfg.MetricInterval = envMillis(getenv, "OTEL_METRIC_EXPORT_INTERVAL", 60*time.Second)
+	cfg.Parent = strings.TrimSpace(getenv("TRACEPARENT"))
+	return cfg, true, nil
+}
+
+// parseList reads "k1=v1,k2=v2" with percent-encoded values, as the OTEL
+// header and resource variables use.
+func parseList(s string) map[string]string {
+	out := map[string]string{}
+	for _, part := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(part, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			continue
+		}
+		if dec, err := url.QueryUnescape(strings.TrimSpace(v)); err == nil {
+			v = dec
+		}
+		out[k] = strings.TrimSpace(v)
+	}
+	return out
+}
+
+func mergeMaps(a, b map[string]string) map[string]string {
+	out := make(map[string]string, len(a)+len(b))
+	for k, v := range a {
+		out[k] = v
+	}
+	for k, v := range b {
+		out[k] = v
+	}
+	return out
+}
+
+func envMillis(getenv func(string) string, key string, def time.Duration) time.Duration {
+	n, err := strconv.Atoi(strings.TrimSpace(getenv(key)))
+	if err != nil || n <= 0 {
+		return def
+	}
+	return time.Duration(n) * time.Millisecond
+}
+
+var (
+	mu     sync.Mutex
+	active *exporter
+)
+
+func current() *exporter {
+	mu.Lock()
+	defer mu.Unlock()
+	return active
+}
+
+// Setup starts exporting when the environment configures an endpoint. The
+// returned shutdown exports what is left and must be called before exit; it
+// is safe to call when nothing was started.
+func Setup(version string) (shutdown func(), err error) {
+	cfg, ok, err := ConfigFromEnv(os.Getenv, version)
+	if err != nil || !ok {
+		return func() {}, err
+	}
+	return Start(cfg), nil
+}
+
+// Start exports with cfg until the returned shutdown is called.
+func Start(cfg Config) (shutdown func()) {
+	e := newExporter(cfg)
+	mu.Lock()
+	active = e
+	mu.Unlock()
+	e.run()
+	return func() {
+		mu.Lock()
+		if active == e {
+			active = nil
+		}
+		mu.Unlock()
+		e.shutdown()
+	}
+}
+
+// Enabled reports whether spans are being exported.
+func Enabled() bool {
+	e := current()
+	return e != nil && e.cfg.TracesURL != ""
+}
+
+// SpanKind says which side of an exchange a span describes.
+type SpanKind int
+
+const (
+	KindInternal SpanKind = 1
+	KindClient   SpanKind = 3
+)
+
+// Attr is a span or resource attribute.
+type Attr struct {
+	Key   string
+	Value interface{}
+}
+
+func String(key, v string) Attr    { return Attr{key, v} }
+func Int(key string, v int64) Attr { return Attr{key, v} }
+func Bool(key string, v bool) Attr { return Attr{key, v} }
+
+// Span is one timed operation. A nil span, returned while tracing is off,
+// accepts every call and records nothing.
+type Span struct {
+	exp      *exporter
+	traceID  [16]byte
+	spanID   [8]byte
+	parentID [8]byte
+	name     string
+	kind     SpanKind
+	start    time.Time
+
+	mu    sync.Mutex
+	attrs []Attr
+	ended bool
+}
+
+type spanKey struct{}
+
+// StartSpan begins a span that is a child of the span in ctx, or of the
+// caller's TRACEPARENT, and returns ctx carrying it.
+func StartSpan(ctx context.Context, name string, kind SpanKind, attrs ...Attr) (context.Context, *Span) {
+	e := current()
+	if e == nil || e.cfg.TracesURL == "" {
+		return ctx, nil
+	}
+	s := &Span{exp: e, name: name, kind: kind, start: time.Now(), attrs: attrs}
+	_, _ = rand.Read(s.spanID[:])
+	if parent, ok := ctx.Value(spanKey{}).(*Span); ok && parent != nil {
+		s.traceID, s.parentID = parent.traceID, parent.spanID
+	} else if trace, span, ok := parseTraceparent(e.cfg.Parent); ok {
+		s.traceID, s.parentID = trace, span
+	} else {
+		_, _ = rand.Read(s.traceID[:])
+	}
+	return context.WithValue(ctx, spanKey{}, s), s
+}
+
+// SetAttributes adds attributes to the span.
+func (s *Span) SetAttributes(attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.attrs = append(s.attrs, attrs...)
+	s.mu.Unlock()
+}
+
+// End finishes the span, marking it failed when err is not nil, and queues it
+// for export. Only the first call counts.
+func (s *Span) End(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	data := spanData{
+		traceID:  s.traceID,
+		spanID:   s.spanID,
+		parentID: s.parentID,
+		name:     s.name,
+		kind:     s.kind,
+		start:    s.start,
+		end:      time.Now(),
+		attrs:    append([]Attr(nil), s.attrs...),
+	}
+	s.mu.Unlock()
+	if err != nil {
+		data.errMsg = err.Error()
+		if errors.Is(err, context.Canceled) {
+			data.errMsg = "canceled"
+		}
+	}
+	s.exp.addSpan(data)
+}
+
+// Traceparent returns the W3C traceparent header for the span in ctx, or ""
+// when there is none, so requests can be joined to the trace server-side.
+func Traceparent(ctx context.Context) string {
+	s, ok := ctx.Value(spanKey{}).(*Span)
+	if !ok || s == nil {
+		return ""
+	}
+	return "00-" + hex.EncodeToString(s.traceID[:]) + "-" + hex.EncodeToString(s.spanID[:]) + "-01"
+}
+
+// parseTraceparent reads "00-<32 hex trace id>-<16 hex span id>-<flags>".
+func parseTraceparent(v string) (trace [16]byte, span [8]byte, ok bool) {
+	parts := strings.Split(strings.TrimSpace(v), "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return trace, span, false
+	}
+	if _, err := hex.Decode(trace[:], []byte(parts[1])); err != nil {
+		return trace, span, false
+	}
+	if _, err := hex.Decode(span[:], []byte(parts[2])); err != nil {
+		return trace, span, false
+	}
+	return trace, span, trace != [16]byte{} && span != [8]byte{}
+}
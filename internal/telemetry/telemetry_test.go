@@ -0,0 +1,137 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/metrics"
+)
+
+func envFunc(vars map[string]string) func(string) string {
+	return func(k string) string { return vars[k] }
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	if _, ok, err := ConfigFromEnv(envFunc(nil), "1.0"); ok || err != nil {
+		t.Fatalf("no endpoint: ok=%v err=%v", ok, err)
+	}
+	cfg, ok, err := ConfigFromEnv(envFunc(map[string]string{
+		"OTEL_EXPORTER_OTLP_ENDPOINT":        "http://collector:4318/",
+		"OTEL_EXPORTER_OTLP_HEADERS":         "x-api-key=abc%3D,team=ml",
+		"OTEL_EXPORTER_OTLP_METRICS_HEADERS": "team=infra",
+		"OTEL_TRACES_EXPORTER":               "none",
+		"OTEL_RESOURCE_ATTRIBUTES":           "deployment.environment=ci,service.name=pipeline",
+		"OTEL_METRIC_EXPORT_INTERVAL":        "1500",
+	}), "1.0")
+	if err != nil || !ok {
+		t.Fatalf("ok=%v err=%v", ok, err)
+	}
+	if cfg.TracesURL != "" || cfg.MetricsURL != "http://collector:4318/v1/metrics" {
+		t.Fatalf("urls = %q, %q", cfg.TracesURL, cfg.MetricsURL)
+	}
+	if cfg.MetricsHeaders["x-api-key"] != "abc=" || cfg.MetricsHeaders["team"] != "infra" {
+		t.Fatalf("headers = %v", cfg.MetricsHeaders)
+	}
+	if cfg.Resource[0].Value != "pipeline" || cfg.Resource[2].Key != "deployment.environment" {
+		t.Fatalf("resource = %v", cfg.Resource)
+	}
+	if cfg.MetricInterval.Milliseconds() != 1500 {
+		t.Fatalf("interval = %s", cfg.MetricInterval)
+	}
+
+	if _, ok, err := ConfigFromEnv(envFunc(map[string]string{
+		"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4317",
+		"OTEL_EXPORTER_OTLP_PROTOCOL": "grpc",
+	}), "1.0"); ok || err == nil {
+		t.Fatal("grpc should be rejected")
+	}
+	if _, ok, _ := ConfigFromEnv(envFunc(map[string]string{
+		"OTEL_EXPORTER_OTLP_ENDPOINT": "http://collector:4318",
+		"OTEL_SDK_DISABLED":           "true",
+	}), "1.0"); ok {
+		t.Fatal("OTEL_SDK_DISABLED should turn export off")
+	}
+}
+
+func TestExportSpansAndCounters(t *testing.T) {
+	var mu sync.Mutex
+	bodies := map[string][]byte{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies[r.URL.Path] = b
+		mu.Unlock()
+		if r.Header.Get("team") != "ml" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("headers = %v", r.Header)
+		}
+	}))
+	defer srv.Close()
+
+	headers := map[string]string{"team": "ml"}
+	stop := Start(Config{
+		TracesURL:      srv.URL + "/v1/traces",
+		MetricsURL:     srv.URL + "/v1/metrics",
+		TracesHeaders:  headers,
+		MetricsHeaders: headers,
+		Resource:       []Attr{String("service.name", "wiro-cli")},
+		Parent:         "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+	})
+	ctx, root := StartSpan(context.Background(), "wiro run", KindInternal)
+	_, child := StartSpan(ctx, "POST /Run/wiro/flux", KindClient, String("http.request.method", "POST"))
+	if tp := Traceparent(ctx); !strings.HasPrefix(tp, "00-0af7651916cd43dd8448eb211c80319c-") {
+		t.Fatalf("traceparent = %q", tp)
+	}
+	child.SetAttributes(Int("http.response.status_code", 500))
+	child.End(errors.New("server error"))
+	root.End(nil)
+	root.End(nil)
+	metrics.APIRequests.Inc("500")
+	stop()
+
+	if Enabled() {
+		t.Fatal("still enabled after shutdown")
+	}
+	var traces struct {
+		ResourceSpans []struct {
+			ScopeSpans []struct {
+				Spans []otlpSpan `json:"spans"`
+			} `json:"scopeSpans"`
+		} `json:"resourceSpans"`
+	}
+	if err := json.Unmarshal(bodies["/v1/traces"], &traces); err != nil {
+		t.Fatalf("traces: %v\n%s", err, bodies["/v1/traces"])
+	}
+	spans := traces.ResourceSpans[0].ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans", len(spans))
+	}
+	c, r := spans[0], spans[1]
+	if c.TraceID != "0af7651916cd43dd8448eb211c80319c" || c.TraceID != r.TraceID || c.ParentSpanID != r.SpanID || r.ParentSpanID != "b7ad6b7169203331" {
+		t.Fatalf("span links: child %+v root %+v", c, r)
+	}
+	if c.Status == nil || c.Status.Code != 2 || r.Status != nil || c.Kind != int(KindClient) {
+		t.Fatalf("status/kind: child %+v root %+v", c, r)
+	}
+	if got := c.Attributes[1]; got.Key != "http.response.status_code" || got.Value["intValue"] != "500" {
+		t.Fatalf("attribute = %+v", got)
+	}
+	if !strings.Contains(string(bodies["/v1/metrics"]), `"name":"wiro_api_requests_total"`) || !strings.Contains(string(bodies["/v1/metrics"]), `"isMonotonic":true`) {
+		t.Fatalf("metrics body: %s", bodies["/v1/metrics"])
+	}
+}
+
+func TestSpansOffWithoutExporter(t *testing.T) {
+	ctx, span := StartSpan(context.Background(), "idle", KindInternal)
+	if span != nil || Traceparent(ctx) != "" {
+		t.Fatal("spans should be nil without an exporter")
+	}
+	span.SetAttributes(String("k", "v"))
+	span.End(nil)
+}
@@ -0,0 +1,53 @@
+package term
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FuzzyMatch reports whether the runes of pattern appear in s in order,
+// ignoring case, and scores the match for ranking: runes that follow the
+// previous match or start a word score higher, gaps lower, and s containing
+// pattern as is scores highest. An empty pattern matches with score 0.
+func FuzzyMatch(pattern, s string) (int, bool) {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == "" {
+		return 0, true
+	}
+	lower := strings.ToLower(s)
+	want := []rune(pattern)
+	score, next, last := 0, 0, -1
+	prev := ' '
+	i := 0
+	for _, r := range lower {
+		if next < len(want) && r == want[next] {
+			score++
+			switch {
+			case last == i-1:
+				score += 5
+			case isWordBoundary(prev):
+				score += 3
+			case last >= 0:
+				score -= min(i-last-1, 3)
+			}
+			last = i
+			next++
+		}
+		prev = r
+		i++
+	}
+	if next < len(want) {
+		return 0, false
+	}
+	if strings.Contains(lower, pattern) {
+		score += 10
+	}
+	// Shorter candidates are closer matches for the same pattern.
+	score -= utf8.RuneCountInString(s) / 16
+	return score, true
+}
+
+func isWordBoundary(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune("/-_.:", r)
+}
@@ -0,0 +1,34 @@
+package term
+
+import (
+	"os"
+	"strconv"
+)
+
+// Hyperlink wraps text in an OSC 8 escape so supporting terminals make it a
+// link to url. Terminals without support show text alone.
+func Hyperlink(url, text string) string {
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// HyperlinksSupported reports whether the terminal is known to render OSC 8
+// links. There is no way to ask, so it goes by the environment;
+// FORCE_HYPERLINK=1 or 0 overrides the guess.
+func HyperlinksSupported() bool {
+	if v, ok := os.LookupEnv("FORCE_HYPERLINK"); ok {
+		return v != "" && v != "0"
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "Hyper", "ghostty", "Tabby":
+		return true
+	}
+	if os.Getenv("WT_SESSION") != "" || os.Getenv("KITTY_WINDOW_ID") != "" || os.Getenv("KONSOLE_VERSION") != "" {
+		return true
+	}
+	// VTE-based terminals (GNOME Terminal, Tilix) render links since 0.50.
+	v, err := strconv.Atoi(os.Getenv("VTE_VERSION"))
+	return err == nil && v >= 5000
+}
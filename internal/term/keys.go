@@ -0,0 +1,125 @@
+package term
+
+import (
+	"bufio"
+	"unicode/utf8"
+)
+
+// KeyType identifies a decoded key press.
+type KeyType int
+
+const (
+	KeyRune KeyType = iota
+	KeyEnter
+	KeyEsc
+	KeyBackspace
+	KeyTab
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyPageUp
+	KeyPageDown
+	KeyHome
+	KeyEnd
+	KeyCtrlC
+	KeyCtrlR
+	KeyUnknown
+)
+
+// Key is one key press; Rune is set for KeyRune.
+type Key struct {
+	Type KeyType
+	Rune rune
+}
+
+// ReadKey decodes one key from raw-mode input, including CSI arrow sequences.
+// A lone ESC is reported when no '[' or 'O' follows in the same read.
+func ReadKey(r *bufio.Reader) (Key, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+	switch b {
+	case '\r', '\n':
+		return Key{Type: KeyEnter}, nil
+	case '\t':
+		return Key{Type: KeyTab}, nil
+	case 3:
+		return Key{Type: KeyCtrlC}, nil
+	case 18:
+		return Key{Type: KeyCtrlR}, nil
+	case 127, 8:
+		return Key{Type: KeyBackspace}, nil
+	case 27:
+		return readEscape(r)
+	}
+	if b < 32 {
+		return Key{Type: KeyUnknown}, nil
+	}
+	if b < utf8.RuneSelf {
+		return Key{Type: KeyRune, Rune: rune(b)}, nil
+	}
+	if err := r.UnreadByte(); err != nil {
+		return Key{}, err
+	}
+	ru, _, err := r.ReadRune()
+	if err != nil {
+		return Key{}, err
+	}
+	return Key{Type: KeyRune, Rune: ru}, nil
+}
+
+func readEscape(r *bufio.Reader) (Key, error) {
+	if r.Buffered() == 0 {
+		return Key{Type: KeyEsc}, nil
+	}
+	b, err := r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+	if b != '[' && b != 'O' {
+		return Key{Type: KeyEsc}, nil
+	}
+	// Read parameter bytes up to the final byte of the sequence.
+	var params []byte
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			return Key{}, err
+		}
+		if c >= 0x40 && c <= 0x7e {
+			return csiKey(c, string(params)), nil
+		}
+		params = append(params, c)
+	}
+}
+
+func csiKey(final byte, params string) Key {
+	switch final {
+	case 'A':
+		return Key{Type: KeyUp}
+	case 'B':
+		return Key{Type: KeyDown}
+	case 'C':
+		return Key{Type: KeyRight}
+	case 'D':
+		return Key{Type: KeyLeft}
+	case 'H':
+		return Key{Type: KeyHome}
+	case 'F':
+		return Key{Type: KeyEnd}
+	case '~':
+		switch params {
+		case "1", "7":
+			return Key{Type: KeyHome}
+		case "4", "8":
+			return Key{Type: KeyEnd}
+		case "5":
+			return Key{Type: KeyPageUp}
+		case "6":
+			return Key{Type: KeyPageDown}
+		}
+	}
+	return Key{Type: KeyUnknown}
+}
@@ -0,0 +1,56 @@
+package term
+
+// List tracks the selection and scroll offset of a vertical list.
+type List struct {
+	Selected int
+	Offset   int
+}
+
+// Move shifts the selection by delta within n items. With wrap, moving past
+// either end continues from the other.
+func (l *List) Move(delta, n int, wrap bool) {
+	if n <= 0 {
+		l.Selected, l.Offset = 0, 0
+		return
+	}
+	l.Selected += delta
+	switch {
+	case wrap:
+		l.Selected = ((l.Selected % n) + n) % n
+	case l.Selected < 0:
+		l.Selected = 0
+	case l.Selected >= n:
+		l.Selected = n - 1
+	}
+}
+
+// Window returns the [start, end) range of n items visible in height rows,
+// scrolling just enough to keep the selection on screen.
+func (l *List) Window(n, height int) (int, int) {
+	if height < 1 {
+		height = 1
+	}
+	if l.Selected >= n {
+		l.Selected = n - 1
+	}
+	if l.Selected < 0 {
+		l.Selected = 0
+	}
+	if l.Selected < l.Offset {
+		l.Offset = l.Selected
+	}
+	if l.Selected >= l.Offset+height {
+		l.Offset = l.Selected - height + 1
+	}
+	if l.Offset > n-height {
+		l.Offset = n - height
+	}
+	if l.Offset < 0 {
+		l.Offset = 0
+	}
+	end := l.Offset + height
+	if end > n {
+		end = n
+	}
+	return l.Offset, end
+}
@@ -0,0 +1,293 @@
+package term
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PickerItem is one choice in a Picker.
+type PickerItem struct {
+	// Label is shown first and fuzzy-matched against the filter.
+	Label string
+	// Detail follows the label; it matches the filter only as a substring.
+	Detail string
+	// Facets are the groups the item belongs to, e.g. its categories.
+	Facets []string
+	// Pinned items, such as favorites, are starred and listed before the rest.
+	Pinned bool
+}
+
+// PickerAction is what a key press asks of the caller.
+type PickerAction int
+
+const (
+	PickerNone PickerAction = iota
+	PickerChosen
+	PickerCancelled
+)
+
+// Picker is a filterable, paged choice list: typing narrows the items with
+// FuzzyMatch, ←/→ and tab step through facets, and ↑/↓ move the selection;
+// changing the filter or facet selects the best match again.
+// Items can be added while it is shown; the caller asks WantsMore after each
+// key and loads the next page when it returns true.
+type Picker struct {
+	Title string
+	// More is set by the caller while further items can be loaded.
+	More bool
+	// Loading is set by the caller while a page is being fetched.
+	Loading bool
+	// Total is the number of items the source has, 0 when unknown.
+	Total  int
+	Status string
+
+	items   []PickerItem
+	query   string
+	facets  []facetCount
+	facet   string
+	matches []int
+	list    List
+}
+
+type facetCount struct {
+	name  string
+	count int
+}
+
+// NewPicker returns an empty picker titled title.
+func NewPicker(title string) *Picker {
+	return &Picker{Title: title}
+}
+
+// Add appends items, keeping the filter, facet, and selected item.
+func (p *Picker) Add(items ...PickerItem) {
+	selected, ok := p.Selected()
+	p.items = append(p.items, items...)
+	p.countFacets()
+	p.refilter()
+	if ok {
+		for i, idx := range p.matches {
+			if idx == selected {
+				p.list.Selected = i
+				break
+			}
+		}
+	}
+}
+
+// Len returns how many items have been added.
+func (p *Picker) Len() int {
+	return len(p.items)
+}
+
+// Query returns the current filter text.
+func (p *Picker) Query() string {
+	return p.query
+}
+
+// Selected returns the index, in the order added, of the highlighted item.
+func (p *Picker) Selected() (int, bool) {
+	if len(p.matches) == 0 || p.list.Selected >= len(p.matches) {
+		return 0, false
+	}
+	return p.matches[p.list.Selected], true
+}
+
+// WantsMore reports whether the next page should be loaded: more items exist,
+// none are loading, and fewer than a screen of matches lie below the selection.
+func (p *Picker) WantsMore(height int) bool {
+	return p.More && !p.Loading && len(p.matches)-p.list.Selected < max(height, 1)
+}
+
+// HandleKey applies one key press.
+func (p *Picker) HandleKey(k Key, height int) PickerAction {
+	page := max(height-1, 1)
+	switch k.Type {
+	case KeyEnter:
+		if _, ok := p.Selected(); ok {
+			return PickerChosen
+		}
+	case KeyEsc, KeyCtrlC:
+		return PickerCancelled
+	case KeyUp:
+		p.list.Move(-1, len(p.matches), false)
+	case KeyDown:
+		p.list.Move(1, len(p.matches), false)
+	case KeyPageUp:
+		p.list.Move(-page, len(p.matches), false)
+	case KeyPageDown:
+		p.list.Move(page, len(p.matches), false)
+	case KeyHome:
+		p.list.Move(-len(p.matches), len(p.matches), false)
+	case KeyEnd:
+		p.list.Move(len(p.matches), len(p.matches), false)
+	case KeyLeft:
+		p.stepFacet(-1)
+	case KeyRight, KeyTab:
+		p.stepFacet(1)
+	case KeyBackspace:
+		if p.query != "" {
+			r := []rune(p.query)
+			p.query = string(r[:len(r)-1])
+			p.list.Selected = 0
+			p.refilter()
+		}
+	case KeyRune:
+		p.query += string(k.Rune)
+		p.list.Selected = 0
+		p.refilter()
+	}
+	return PickerNone
+}
+
+// stepFacet moves through "all" followed by the facets, most used first.
+func (p *Picker) stepFacet(delta int) {
+	n := len(p.facets) + 1
+	cur := 0
+	for i, f := range p.facets {
+		if f.name == p.facet {
+			cur = i + 1
+		}
+	}
+	cur = ((cur+delta)%n + n) % n
+	p.facet = ""
+	if cur > 0 {
+		p.facet = p.facets[cur-1].name
+	}
+	p.list.Selected = 0
+	p.refilter()
+}
+
+func (p *Picker) countFacets() {
+	counts := map[string]int{}
+	for _, it := range p.items {
+		for _, f := range it.Facets {
+			counts[f]++
+		}
+	}
+	p.facets = p.facets[:0]
+	for name, c := range counts {
+		p.facets = append(p.facets, facetCount{name: name, count: c})
+	}
+	sort.Slice(p.facets, func(i, j int) bool {
+		if p.facets[i].count != p.facets[j].count {
+			return p.facets[i].count > p.facets[j].count
+		}
+		return p.facets[i].name < p.facets[j].name
+	})
+}
+
+// refilter recomputes the matches: pinned items first, then label matches
+// ranked by score, then detail-only matches, each in the order added.
+func (p *Picker) refilter() {
+	type scored struct {
+		idx   int
+		score int
+	}
+	needle := strings.ToLower(strings.TrimSpace(p.query))
+	var ranked []scored
+	for i, it := range p.items {
+		if p.facet != "" && !containsString(it.Facets, p.facet) {
+			continue
+		}
+		if score, ok := FuzzyMatch(needle, it.Label); ok {
+			ranked = append(ranked, scored{i, score})
+		} else if needle != "" && strings.Contains(strings.ToLower(it.Detail), needle) {
+			ranked = append(ranked, scored{i, -1 << 20})
+		}
+	}
+	sort.SliceStable(ranked, func(a, b int) bool {
+		pa, pb := p.items[ranked[a].idx].Pinned, p.items[ranked[b].idx].Pinned
+		if pa != pb {
+			return pa
+		}
+		return ranked[a].score > ranked[b].score
+	})
+	p.matches = p.matches[:0]
+	for _, r := range ranked {
+		p.matches = append(p.matches, r.idx)
+	}
+	p.list.Move(0, len(p.matches), false)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Render draws the picker in width x height.
+func (p *Picker) Render(width, height int) []string {
+	count := fmt.Sprintf("%d loaded", len(p.items))
+	if p.Total > 0 {
+		count = fmt.Sprintf("%d of %d loaded", len(p.items), p.Total)
+	}
+	lines := []string{
+		p.Title + "  " + Dim(count),
+		"Filter: " + p.query + "█",
+		p.renderFacets(width - 1),
+		"",
+	}
+	rows := max(height-len(lines)-2, 1)
+	start, end := p.list.Window(len(p.matches), rows)
+	for i := start; i < end; i++ {
+		it := p.items[p.matches[i]]
+		label := it.Label
+		if it.Pinned {
+			label = "★ " + label
+		}
+		line := Fit(label, width-3)
+		if rest := width - 3 - StringWidth(line) - 4; it.Detail != "" && rest > 8 {
+			line += " :: " + Fit(it.Detail, rest)
+		}
+		if i == p.list.Selected {
+			line = Reverse("> " + line)
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+	}
+	if len(p.matches) == 0 {
+		lines = append(lines, Dim("  no matches"))
+	}
+	for len(lines) < height-2 {
+		lines = append(lines, "")
+	}
+	status := p.Status
+	if p.Loading {
+		status = "Loading more..."
+	}
+	lines = append(lines[:height-2], Fit(status, width-1), Dim(Fit("type to filter  ↑/↓ pgup/pgdn: move  ←/→ tab: category  enter: choose  esc: cancel", width-1)))
+	return lines
+}
+
+// renderFacets shows "all" and the facets with counts, the active one reversed.
+func (p *Picker) renderFacets(width int) string {
+	if len(p.facets) == 0 {
+		return ""
+	}
+	parts := []string{facetLabel("all", len(p.items), p.facet == "")}
+	used := StringWidth("all") + 8
+	for _, f := range p.facets {
+		label := facetLabel(f.name, f.count, f.name == p.facet)
+		w := StringWidth(f.name) + 8
+		if used+w > width && f.name != p.facet {
+			continue
+		}
+		parts = append(parts, label)
+		used += w
+	}
+	return strings.Join(parts, " ")
+}
+
+func facetLabel(name string, count int, active bool) string {
+	s := fmt.Sprintf(" %s %d ", name, count)
+	if active {
+		return Reverse(s)
+	}
+	return s
+}
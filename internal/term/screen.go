@@ -0,0 +1,89 @@
+package term
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Screen draws whole frames on the alternate screen buffer.
+type Screen struct {
+	w *bufio.Writer
+}
+
+// NewScreen wraps w (usually os.Stdout).
+func NewScreen(w io.Writer) *Screen {
+	return &Screen{w: bufio.NewWriter(w)}
+}
+
+// Enter switches to the alternate screen and hides the cursor.
+func (s *Screen) Enter() {
+	s.w.WriteString("\033[?1049h\033[?25l\033[2J")
+	s.w.Flush()
+}
+
+// Exit restores the cursor and the main screen.
+func (s *Screen) Exit() {
+	s.w.WriteString("\033[?25h\033[?1049l")
+	s.w.Flush()
+}
+
+// Draw replaces the screen with lines, each cut to width columns. In raw mode
+// lines end with \r\n since the terminal no longer translates newlines.
+func (s *Screen) Draw(lines []string, width, height int) {
+	s.w.WriteString("\033[H")
+	for i := 0; i < height; i++ {
+		line := ""
+		if i < len(lines) {
+			line = lines[i]
+		}
+		s.w.WriteString("\033[2K")
+		s.w.WriteString(cut(line, width))
+		if i < height-1 {
+			s.w.WriteString("\r\n")
+		}
+	}
+	s.w.Flush()
+}
+
+// cut truncates s to width visible columns, skipping ANSI sequences when counting.
+func cut(s string, width int) string {
+	var b strings.Builder
+	visible := 0
+	inEsc := false
+	for _, r := range s {
+		switch {
+		case inEsc:
+			b.WriteRune(r)
+			if r >= 0x40 && r <= 0x7e && r != '[' {
+				inEsc = false
+			}
+			continue
+		case r == '\033':
+			inEsc = true
+			b.WriteRune(r)
+			continue
+		}
+		w := RuneWidth(r)
+		if visible+w > width {
+			break
+		}
+		b.WriteRune(r)
+		visible += w
+	}
+	if inEsc || strings.Contains(s, "\033[") {
+		b.WriteString("\033[0m")
+	}
+	return b.String()
+}
+
+// Reverse renders s in reverse video, used for the highlighted row.
+func Reverse(s string) string {
+	return fmt.Sprintf("\033[7m%s\033[0m", s)
+}
+
+// Dim renders s faint.
+func Dim(s string) string {
+	return fmt.Sprintf("\033[2m%s\033[0m", s)
+}
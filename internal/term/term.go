@@ -0,0 +1,79 @@
+// Package term is a small terminal layer: raw mode, size, key decoding, and
+// frame rendering shared by interactive prompts and the full-screen TUI.
+package term
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupported is returned where raw terminal control is unavailable.
+var ErrUnsupported = errors.New("raw terminal mode is not available on this platform")
+
+// MakeRaw switches stdin to raw, no-echo mode and returns a func restoring the previous state.
+func MakeRaw() (func(), error) {
+	return setMode(modeRaw)
+}
+
+// MakeCbreak delivers keys one at a time without echo while keeping output
+// processing and Ctrl-C signals, so normal printing continues underneath.
+func MakeCbreak() (func(), error) {
+	return setMode(modeCbreak)
+}
+
+// DisableEcho hides typed input (line editing still works) until restore is called.
+func DisableEcho() (func(), error) {
+	return setMode(modeNoEcho)
+}
+
+// mode is a terminal input mode; each platform file maps it to its own settings.
+type mode int
+
+const (
+	modeRaw mode = iota
+	modeCbreak
+	modeNoEcho
+)
+
+// Size returns the terminal width and height, defaulting to 100x30.
+func Size() (width, height int) {
+	width, height = 100, 30
+	if w, h, ok := consoleSize(); ok {
+		if h >= 10 {
+			height = h
+		}
+		if w >= 40 {
+			width = w
+		}
+	}
+	if raw := strings.TrimSpace(os.Getenv("COLUMNS")); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n >= 40 {
+			width = n
+		}
+	}
+	if raw := strings.TrimSpace(os.Getenv("LINES")); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n >= 10 {
+			height = n
+		}
+	}
+	return width, height
+}
+
+// Width returns the terminal width.
+func Width() int {
+	w, _ := Size()
+	return w
+}
+
+// Fit collapses whitespace and truncates s to width columns with an ellipsis.
+func Fit(s string, width int) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	s = strings.ReplaceAll(s, "\r", " ")
+	s = strings.Join(strings.Fields(s), " ")
+	if width < 8 {
+		width = 8
+	}
+	return Truncate(s, width, "...")
+}
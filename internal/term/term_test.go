@@ -0,0 +1,198 @@
+package term
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestReadKey(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("a\r\x1b[A\x1b[B\x1b[5~\x7f\x03é\t"))
+	want := []Key{
+		{Type: KeyRune, Rune: 'a'},
+		{Type: KeyEnter},
+		{Type: KeyUp},
+		{Type: KeyDown},
+		{Type: KeyPageUp},
+		{Type: KeyBackspace},
+		{Type: KeyCtrlC},
+		{Type: KeyRune, Rune: 'é'},
+		{Type: KeyTab},
+	}
+	for i, w := range want {
+		got, err := ReadKey(r)
+		if err != nil {
+			t.Fatalf("key %d: %v", i, err)
+		}
+		if got != w {
+			t.Fatalf("key %d = %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+func TestReadKey_LoneEsc(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("\x1b"))
+	got, err := ReadKey(r)
+	if err != nil || got.Type != KeyEsc {
+		t.Fatalf("got %+v, %v; want Esc", got, err)
+	}
+}
+
+func TestListWindow(t *testing.T) {
+	var l List
+	for i := 0; i < 7; i++ {
+		l.Move(1, 10, false)
+	}
+	start, end := l.Window(10, 4)
+	if l.Selected != 7 || start != 4 || end != 8 {
+		t.Fatalf("selected=%d window=[%d,%d), want 7 [4,8)", l.Selected, start, end)
+	}
+	l.Move(5, 10, false)
+	if l.Selected != 9 {
+		t.Fatalf("clamped selection = %d, want 9", l.Selected)
+	}
+	l.Move(1, 10, true)
+	if l.Selected != 0 {
+		t.Fatalf("wrapped selection = %d, want 0", l.Selected)
+	}
+	if start, end := l.Window(3, 4); start != 0 || end != 3 {
+		t.Fatalf("short list window = [%d,%d), want [0,3)", start, end)
+	}
+}
+
+func TestFuzzyMatch(t *testing.T) {
+	if _, ok := FuzzyMatch("fxd", "black-forest/flux-dev"); !ok {
+		t.Fatal("fxd should match flux-dev")
+	}
+	if _, ok := FuzzyMatch("xf", "flux"); ok {
+		t.Fatal("out-of-order runes should not match")
+	}
+	exact, _ := FuzzyMatch("flux", "owner/flux-schnell")
+	scattered, _ := FuzzyMatch("flux", "owner/fal-lux-upscale")
+	if exact <= scattered {
+		t.Fatalf("contiguous match scored %d, scattered %d", exact, scattered)
+	}
+}
+
+func TestPicker(t *testing.T) {
+	p := NewPicker("Select")
+	p.Add(
+		PickerItem{Label: "a/flux-dev", Facets: []string{"image"}},
+		PickerItem{Label: "a/whisper", Detail: "speech to text", Facets: []string{"audio"}},
+		PickerItem{Label: "b/flux-video", Facets: []string{"video", "image"}},
+	)
+	for _, r := range "flux" {
+		p.HandleKey(Key{Type: KeyRune, Rune: r}, 10)
+	}
+	if len(p.matches) != 2 {
+		t.Fatalf("flux matches = %v", p.matches)
+	}
+	p.HandleKey(Key{Type: KeyRight}, 10) // image, the most used facet
+	p.HandleKey(Key{Type: KeyRight}, 10) // audio
+	if len(p.matches) != 0 {
+		t.Fatalf("flux in audio = %v", p.matches)
+	}
+	p.HandleKey(Key{Type: KeyLeft}, 10)
+	p.HandleKey(Key{Type: KeyLeft}, 10) // all
+	for range "flux" {
+		p.HandleKey(Key{Type: KeyBackspace}, 10)
+	}
+	for _, r := range "speech" {
+		p.HandleKey(Key{Type: KeyRune, Rune: r}, 10)
+	}
+	if idx, ok := p.Selected(); !ok || idx != 1 {
+		t.Fatalf("detail match selected %d, %v", idx, ok)
+	}
+	if p.HandleKey(Key{Type: KeyEnter}, 10) != PickerChosen {
+		t.Fatal("enter should choose")
+	}
+
+	p.More = true
+	if !p.WantsMore(10) {
+		t.Fatal("a short list should ask for more")
+	}
+	p.Add(PickerItem{Label: "c/speech-tts"})
+	if idx, _ := p.Selected(); idx != 1 {
+		t.Fatalf("adding items moved the selection to %d", idx)
+	}
+	if lines := p.Render(60, 12); len(lines) != 12 {
+		t.Fatalf("render gave %d lines", len(lines))
+	}
+}
+
+func TestPickerPinnedFirst(t *testing.T) {
+	p := NewPicker("Select")
+	p.Add(PickerItem{Label: "a/flux"}, PickerItem{Label: "b/flux-pro"})
+	p.Add(PickerItem{Label: "c/fluxish", Pinned: true})
+	for _, r := range "flux" {
+		p.HandleKey(Key{Type: KeyRune, Rune: r}, 10)
+	}
+	if idx, _ := p.Selected(); idx != 2 {
+		t.Fatalf("pinned item should lead, selected %d", idx)
+	}
+	if !strings.Contains(strings.Join(p.Render(60, 12), "\n"), "★ c/fluxish") {
+		t.Fatal("pinned item should be starred")
+	}
+}
+
+func TestFitAndCut(t *testing.T) {
+	if got := Fit("hello\n  wide   world", 11); got != "hello wi..." {
+		t.Fatalf("Fit = %q", got)
+	}
+	if got := cut("\033[7mabcdef\033[0m", 3); got != "\033[7mabc\033[0m" {
+		t.Fatalf("cut = %q", got)
+	}
+}
+
+func TestWidthAndTruncate(t *testing.T) {
+	if n := StringWidth("açık 漢字 🎨é"); n != 13 {
+		t.Fatalf("StringWidth = %d, want 13", n)
+	}
+	if got := Truncate("Türkçe açıklama", 9, "..."); got != "Türkçe..." {
+		t.Fatalf("Truncate Turkish = %q", got)
+	}
+	// A wide character that would straddle the limit is dropped, not split.
+	if got := Truncate("漢字漢字", 7, "..."); got != "漢字..." {
+		t.Fatalf("Truncate CJK = %q", got)
+	}
+	if got := Truncate("🎨🎨🎨", 3, "..."); got != "🎨" {
+		t.Fatalf("Truncate below tail width = %q", got)
+	}
+	if got := Fit("漢字漢字漢字漢字", 10); got != "漢字漢..." || StringWidth(got) > 10 {
+		t.Fatalf("Fit = %q", got)
+	}
+	if got := cut("漢字x", 3); got != "漢" {
+		t.Fatalf("cut = %q", got)
+	}
+	if got := Pad("字", 4); got != "字  " {
+		t.Fatalf("Pad = %q", got)
+	}
+}
+
+func TestHyperlinksSupported(t *testing.T) {
+	for _, k := range []string{"TERM_PROGRAM", "WT_SESSION", "KITTY_WINDOW_ID", "KONSOLE_VERSION", "VTE_VERSION", "TERM"} {
+		t.Setenv(k, "")
+	}
+	t.Setenv("FORCE_HYPERLINK", "0")
+	t.Setenv("TERM_PROGRAM", "iTerm.app")
+	if HyperlinksSupported() {
+		t.Fatal("FORCE_HYPERLINK=0 did not win")
+	}
+	os.Unsetenv("FORCE_HYPERLINK")
+	if !HyperlinksSupported() {
+		t.Fatal("iTerm2 not detected")
+	}
+	t.Setenv("TERM_PROGRAM", "")
+	t.Setenv("VTE_VERSION", "4800")
+	if HyperlinksSupported() {
+		t.Fatal("old VTE detected")
+	}
+	t.Setenv("VTE_VERSION", "6003")
+	if !HyperlinksSupported() {
+		t.Fatal("VTE 0.60 not detected")
+	}
+	if got := Hyperlink("https://x.io/a.png", "cover"); got != "\x1b]8;;https://x.io/a.png\x1b\\cover\x1b]8;;\x1b\\" {
+		t.Fatalf("Hyperlink = %q", got)
+	}
+}
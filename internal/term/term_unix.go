@@ -0,0 +1,65 @@
+//go:build !windows
+
+package term
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+var sttyModes = map[mode][]string{
+	modeRaw:    {"raw", "-echo", "min", "1", "time", "0"},
+	modeCbreak: {"-icanon", "-echo", "min", "1", "time", "0"},
+	modeNoEcho: {"-echo"},
+}
+
+func setMode(m mode) (func(), error) {
+	state, err := sttyState()
+	if err != nil {
+		return nil, err
+	}
+	if err := stty(sttyModes[m]...); err != nil {
+		return nil, err
+	}
+	return func() { _ = stty(state) }, nil
+}
+
+// consoleSize asks stty for the size of the terminal on stdin.
+func consoleSize() (width, height int, ok bool) {
+	cmd := exec.Command("stty", "size")
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, 0, false
+	}
+	parts := strings.Fields(strings.TrimSpace(string(out)))
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+	h, herr := strconv.Atoi(parts[0])
+	w, werr := strconv.Atoi(parts[1])
+	if herr != nil || werr != nil {
+		return 0, 0, false
+	}
+	return w, h, true
+}
+
+func sttyState() (string, error) {
+	cmd := exec.Command("stty", "-g")
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func stty(args ...string) error {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
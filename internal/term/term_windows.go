@@ -0,0 +1,96 @@
+package term
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// Console mode flags from wincon.h.
+const (
+	enableProcessedInput       = 0x0001
+	enableLineInput            = 0x0002
+	enableEchoInput            = 0x0004
+	enableVirtualTerminalInput = 0x0200
+
+	enableProcessedOutput           = 0x0001
+	enableVirtualTerminalProcessing = 0x0004
+)
+
+var (
+	kernel32                       = syscall.NewLazyDLL("kernel32.dll")
+	procSetConsoleMode             = kernel32.NewProc("SetConsoleMode")
+	procGetConsoleScreenBufferInfo = kernel32.NewProc("GetConsoleScreenBufferInfo")
+)
+
+// setMode switches the console with SetConsoleMode. Raw and cbreak modes turn
+// on virtual terminal input, so arrow keys arrive as the same escape sequences
+// ReadKey decodes on Unix, and virtual terminal processing on stdout, so the
+// menus' cursor movement renders. Consoles older than Windows 10 reject those
+// flags and the caller falls back to line input.
+func setMode(m mode) (func(), error) {
+	in := syscall.Handle(os.Stdin.Fd())
+	var inMode uint32
+	if err := syscall.GetConsoleMode(in, &inMode); err != nil {
+		return nil, ErrUnsupported
+	}
+	raw := inMode
+	switch m {
+	case modeRaw:
+		raw &^= enableEchoInput | enableLineInput | enableProcessedInput
+		raw |= enableVirtualTerminalInput
+	case modeCbreak:
+		raw &^= enableEchoInput | enableLineInput
+		raw |= enableVirtualTerminalInput | enableProcessedInput
+	case modeNoEcho:
+		raw &^= enableEchoInput
+	}
+	if err := setConsoleMode(in, raw); err != nil {
+		return nil, err
+	}
+	out := syscall.Handle(os.Stdout.Fd())
+	var outMode uint32
+	outSet := false
+	if m != modeNoEcho && syscall.GetConsoleMode(out, &outMode) == nil {
+		if err := setConsoleMode(out, outMode|enableProcessedOutput|enableVirtualTerminalProcessing); err != nil {
+			_ = setConsoleMode(in, inMode)
+			return nil, err
+		}
+		outSet = true
+	}
+	return func() {
+		_ = setConsoleMode(in, inMode)
+		if outSet {
+			_ = setConsoleMode(out, outMode)
+		}
+	}, nil
+}
+
+func setConsoleMode(h syscall.Handle, mode uint32) error {
+	if r, _, err := procSetConsoleMode.Call(uintptr(h), uintptr(mode)); r == 0 {
+		return err
+	}
+	return nil
+}
+
+type coord struct{ x, y int16 }
+
+type smallRect struct{ left, top, right, bottom int16 }
+
+type consoleScreenBufferInfo struct {
+	size              coord
+	cursorPosition    coord
+	attributes        uint16
+	window            smallRect
+	maximumWindowSize coord
+}
+
+// consoleSize is the visible window of the console on stdout.
+func consoleSize() (width, height int, ok bool) {
+	var info consoleScreenBufferInfo
+	r, _, _ := procGetConsoleScreenBufferInfo.Call(os.Stdout.Fd(), uintptr(unsafe.Pointer(&info)))
+	if r == 0 {
+		return 0, 0, false
+	}
+	return int(info.window.right-info.window.left) + 1, int(info.window.bottom-info.window.top) + 1, true
+}
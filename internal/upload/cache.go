@@ -0,0 +1,93 @@
+// Package upload remembers files already pushed to Wiro storage so they can be
+// reused by URL instead of uploaded again.
+package upload
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/wiro-ai/wiro-cli/internal/storage"
+)
+
+// Entry is one uploaded file, addressed by its SHA256 and the project it was uploaded with.
+type Entry struct {
+	SHA256     string `json:"sha256"`
+	Project    string `json:"project,omitempty"`
+	Size       int64  `json:"size"`
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+	UploadedAt string `json:"uploadedAt"`
+}
+
+// Cache maps file hashes to previously uploaded URLs.
+type Cache struct {
+	st      storage.Storage
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// OpenCache loads the uploads collection of st, starting empty when it is
+// missing or unreadable.
+func OpenCache(st storage.Storage) *Cache {
+	c := &Cache{st: st}
+	records, err := st.Records(storage.Uploads)
+	if err != nil {
+		return c
+	}
+	// A corrupt record only costs a re-upload; skip it rather than failing.
+	for _, raw := range records {
+		var e Entry
+		if json.Unmarshal(raw, &e) == nil && e.SHA256 != "" {
+			c.put(e)
+		}
+	}
+	return c
+}
+
+// Lookup returns the upload of the file with sum for project.
+func (c *Cache) Lookup(sum, project string) (Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, e := range c.entries {
+		if e.SHA256 == sum && e.Project == project {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Put records an upload, replacing an older one of the same file and project.
+func (c *Cache) Put(e Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.put(e)
+}
+
+func (c *Cache) put(e Entry) {
+	for i := range c.entries {
+		if c.entries[i].SHA256 == e.SHA256 && c.entries[i].Project == e.Project {
+			c.entries[i] = e
+			return
+		}
+	}
+	c.entries = append(c.entries, e)
+}
+
+// Save writes the cache, replacing what was stored.
+func (c *Cache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	records := make([]storage.Record, 0, len(c.entries))
+	for _, e := range c.entries {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("marshal upload cache: %w", err)
+		}
+		records = append(records, storage.Record{Key: e.SHA256 + " " + e.Project, Data: data})
+	}
+	if err := c.st.Replace(storage.Uploads, records); err != nil {
+		return fmt.Errorf("write upload cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package upload
+
+import (
+	"os"
+	"testing"
+
+	"github.com/wiro-ai/wiro-cli/internal/storage"
+)
+
+func TestCache_PutLookupSave(t *testing.T) {
+	st := storage.NewJSONL(t.TempDir())
+	c := OpenCache(st)
+	if _, ok := c.Lookup("abc", "p1"); ok {
+		t.Fatal("empty cache should miss")
+	}
+	c.Put(Entry{SHA256: "abc", Project: "p1", URL: "https://cdn/1"})
+	c.Put(Entry{SHA256: "abc", Project: "p2", URL: "https://cdn/2"})
+	c.Put(Entry{SHA256: "abc", Project: "p1", URL: "https://cdn/3"})
+	if err := c.Save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	reopened := OpenCache(st)
+	if e, ok := reopened.Lookup("abc", "p1"); !ok || e.URL != "https://cdn/3" {
+		t.Fatalf("lookup p1 = %+v %v", e, ok)
+	}
+	if e, ok := reopened.Lookup("abc", "p2"); !ok || e.URL != "https://cdn/2" {
+		t.Fatalf("lookup p2 = %+v %v", e, ok)
+	}
+	if len(reopened.entries) != 2 {
+		t.Fatalf("expected replaced entry, got %d entries", len(reopened.entries))
+	}
+
+	if err := os.WriteFile(st.Path(storage.Uploads), []byte("{not json"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, ok := OpenCache(st).Lookup("abc", "p1"); ok {
+		t.Fatal("corrupt cache should start empty")
+	}
+}
@@ -0,0 +1,126 @@
+package workspace
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/wiro-ai/wiro-cli/internal/runspec"
+	"github.com/wiro-ai/wiro-cli/internal/yaml"
+)
+
+// Config is the team-shared part of .wiro.yaml. It is meant to be committed,
+// so it names things the user config holds (a project by name, a model) and
+// never carries credentials.
+type Config struct {
+	// Project is the name or API key of a project in the user config.
+	Project string `json:"project,omitempty"`
+	// Model is an owner/model or alias that `wiro run` uses without a model argument.
+	Model string `json:"model,omitempty"`
+	// OutputDir replaces preferences.outputDirDefault; relative paths are
+	// anchored at the workspace root.
+	OutputDir string `json:"outputDir,omitempty"`
+	// Params are default field values in the --set-yaml format, applied
+	// before flags.
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// secretKeys are settings that must stay in the user config and keychain.
+var secretKeys = []string{"apisecret", "secret", "token", "bearertoken", "password"}
+
+// Config reads the workspace file. A nil workspace or an empty file is an
+// empty config.
+func (w *Workspace) Config() (Config, error) {
+	var cfg Config
+	if w == nil {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(w.File)
+	if err != nil {
+		return cfg, err
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return cfg, nil
+	}
+	doc, err := yaml.Parse(data)
+	if err != nil {
+		return cfg, fmt.Errorf("%s: %w", w.File, err)
+	}
+	top, ok := doc.(map[string]interface{})
+	if !ok {
+		if doc == nil {
+			return cfg, nil
+		}
+		return cfg, fmt.Errorf("%s: expected a mapping", w.File)
+	}
+	var found []string
+	for k := range top {
+		for _, s := range secretKeys {
+			if strings.EqualFold(k, s) {
+				found = append(found, k)
+			}
+		}
+	}
+	if len(found) > 0 {
+		sort.Strings(found)
+		return cfg, fmt.Errorf("%s: %s must not be committed to the workspace; keep credentials in the user config (wiro auth login)", w.File, strings.Join(found, ", "))
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %w", w.File, err)
+	}
+	cfg.Project = strings.TrimSpace(cfg.Project)
+	cfg.Model = strings.TrimSpace(cfg.Model)
+	cfg.OutputDir = strings.TrimSpace(cfg.OutputDir)
+	return cfg, nil
+}
+
+// ParamValues converts Params to --set, --set-file, and --set-url values.
+// file: paths are resolved against the workspace root and must stay inside
+// it: the file comes with a cloned repository, so it must not be able to
+// upload files from elsewhere on the machine.
+func (w *Workspace) ParamValues(cfg Config) (set, setFile, setURL []string, err error) {
+	if w == nil || len(cfg.Params) == 0 || string(cfg.Params) == "null" {
+		return nil, nil, nil, nil
+	}
+	set, files, setURL, err := runspec.ParseParams(cfg.Params, "json", w.File+": params", "")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	for _, kv := range files {
+		field, path, _ := strings.Cut(kv, "=")
+		resolved, err := w.insidePath(path)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("%s: params: field %q: %w", w.File, field, err)
+		}
+		setFile = append(setFile, field+"="+resolved)
+	}
+	return set, setFile, setURL, nil
+}
+
+// insidePath resolves a relative path against the root and refuses it when it,
+// or a symlink along it, leads outside the workspace.
+func (w *Workspace) insidePath(path string) (string, error) {
+	if filepath.IsAbs(path) {
+		return "", fmt.Errorf("file: %s is absolute; workspace files must be relative to the workspace root", path)
+	}
+	resolved := filepath.Join(w.Root, path)
+	if !within(w.Root, resolved) {
+		return "", fmt.Errorf("file: %s is outside the workspace", path)
+	}
+	if real, err := filepath.EvalSymlinks(resolved); err == nil {
+		root, err := filepath.EvalSymlinks(w.Root)
+		if err != nil || !within(root, real) {
+			return "", fmt.Errorf("file: %s links outside the workspace", path)
+		}
+	}
+	return resolved, nil
+}
+
+func within(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,72 @@
+package workspace
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileName marks the root of a wiro workspace.
+const FileName = ".wiro.yaml"
+
+// Placeholder expands to the workspace root inside path settings.
+const Placeholder = "{workspace}"
+
+// Workspace is a directory tree rooted at a .wiro.yaml file.
+type Workspace struct {
+	Root string
+	File string
+}
+
+// Find walks up from start until it finds a .wiro.yaml. It returns nil when none exists.
+func Find(start string) (*Workspace, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		candidate := filepath.Join(dir, FileName)
+		info, err := os.Stat(candidate)
+		if err == nil && !info.IsDir() {
+			return &Workspace{Root: dir, File: candidate}, nil
+		}
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// Discover finds the workspace containing the current directory.
+func Discover() (*Workspace, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	return Find(wd)
+}
+
+// ResolvePath expands {workspace} and anchors relative paths at the workspace root.
+// Without a workspace, {workspace} means the current directory and relative paths are left as-is.
+func (w *Workspace) ResolvePath(p string) string {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return p
+	}
+	root := "."
+	if w != nil {
+		root = w.Root
+	}
+	if strings.Contains(p, Placeholder) {
+		p = filepath.Clean(strings.ReplaceAll(p, Placeholder, root))
+	}
+	if w == nil || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(w.Root, p)
+}
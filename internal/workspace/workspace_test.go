@@ -0,0 +1,114 @@
+package workspace
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindAndResolvePath(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, FileName), []byte("{}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sub := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	ws, err := Find(sub)
+	if err != nil || ws == nil {
+		t.Fatalf("Find() = %v, %v", ws, err)
+	}
+	if ws.Root != root {
+		t.Fatalf("root = %q, want %q", ws.Root, root)
+	}
+	if got := ws.ResolvePath("renders"); got != filepath.Join(root, "renders") {
+		t.Fatalf("relative path = %q", got)
+	}
+	if got := ws.ResolvePath("{workspace}/out/x"); got != filepath.Join(root, "out", "x") {
+		t.Fatalf("placeholder path = %q", got)
+	}
+	abs := filepath.Join(t.TempDir(), "abs")
+	if got := ws.ResolvePath(abs); got != abs {
+		t.Fatalf("absolute path = %q", got)
+	}
+
+	var none *Workspace
+	if got := none.ResolvePath("renders"); got != "renders" {
+		t.Fatalf("no workspace path = %q", got)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	root := t.TempDir()
+	ws := &Workspace{Root: root, File: filepath.Join(root, FileName)}
+	write := func(s string) {
+		t.Helper()
+		if err := os.WriteFile(ws.File, []byte(s), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("project: team\nmodel: wiro/flux\noutputDir: renders\nparams:\n  steps: 30\n  image: file:assets/ref.png\n")
+	cfg, err := ws.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Project != "team" || cfg.Model != "wiro/flux" || cfg.OutputDir != "renders" {
+		t.Fatalf("config = %+v", cfg)
+	}
+	set, setFile, setURL, err := ws.ParamValues(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(set) != 1 || set[0] != "steps=30" || len(setURL) != 0 {
+		t.Fatalf("set = %v, setURL = %v", set, setURL)
+	}
+	if want := "image=" + filepath.Join(root, "assets", "ref.png"); len(setFile) != 1 || setFile[0] != want {
+		t.Fatalf("setFile = %v, want %s", setFile, want)
+	}
+
+	write("project: team\napiSecret: hunter2\n")
+	if _, err := ws.Config(); err == nil {
+		t.Fatal("secret in workspace config accepted")
+	}
+
+	write("")
+	if cfg, err := ws.Config(); err != nil || cfg.Model != "" {
+		t.Fatalf("empty file = %+v, %v", cfg, err)
+	}
+	var none *Workspace
+	if cfg, err := none.Config(); err != nil || cfg.Project != "" {
+		t.Fatalf("no workspace = %+v, %v", cfg, err)
+	}
+}
+
+func TestParamValuesStayInsideWorkspace(t *testing.T) {
+	root := t.TempDir()
+	ws := &Workspace{Root: root, File: filepath.Join(root, FileName)}
+	outside := t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "id_rsa"), []byte("key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "keys")); err != nil {
+		t.Skipf("symlink: %v", err)
+	}
+	for _, hostile := range []string{
+		"params:\n  image: file:" + filepath.ToSlash(filepath.Join(outside, "id_rsa")) + "\n",
+		"params:\n  image: file:../../.ssh/id_rsa\n",
+		"params:\n  image: file:assets/../../id_rsa\n",
+		"params:\n  image: file:keys/id_rsa\n",
+	} {
+		if err := os.WriteFile(ws.File, []byte(hostile), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := ws.Config()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, setFile, _, err := ws.ParamValues(cfg); err == nil {
+			t.Fatalf("%q accepted: %v", hostile, setFile)
+		}
+	}
+}
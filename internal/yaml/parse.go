@@ -0,0 +1,522 @@
+package yaml
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parse decodes a YAML document into map[string]interface{}, []interface{},
+// string, int64, float64, bool, or nil values. An empty document is nil.
+func Parse(data []byte) (interface{}, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.TrimPrefix(text, "\ufeff")
+	p := &parser{lines: strings.Split(text, "\n")}
+	if err := p.checkTabs(); err != nil {
+		return nil, err
+	}
+	p.skipDirectives()
+	v, err := p.block(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.next() {
+		if strings.TrimSpace(p.content()) == "---" {
+			return nil, p.errorf("multiple documents are not supported")
+		}
+		return nil, p.errorf("unexpected content %q", p.content())
+	}
+	return v, nil
+}
+
+// SyntaxError reports the 1-based line of a parse failure.
+type SyntaxError struct {
+	Line int
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("yaml: line %d: %s", e.Line, e.Msg)
+}
+
+type parser struct {
+	lines []string
+	pos   int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &SyntaxError{Line: p.pos + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) checkTabs() error {
+	for i, line := range p.lines {
+		trimmed := strings.TrimLeft(line, " ")
+		if strings.HasPrefix(trimmed, "\t") && strings.TrimSpace(trimmed) != "" {
+			return &SyntaxError{Line: i + 1, Msg: "tabs are not allowed in indentation"}
+		}
+	}
+	return nil
+}
+
+// skipDirectives drops a leading "---" document marker.
+func (p *parser) skipDirectives() {
+	if p.next() && strings.HasPrefix(strings.TrimSpace(p.lines[p.pos]), "---") && indentOf(p.lines[p.pos]) == 0 {
+		rest := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(p.lines[p.pos]), "---"))
+		if rest == "" || strings.HasPrefix(rest, "#") {
+			p.pos++
+		} else {
+			p.lines[p.pos] = "    " + rest
+		}
+	}
+}
+
+// next advances past blank and comment lines and reports whether content remains.
+func (p *parser) next() bool {
+	for p.pos < len(p.lines) {
+		t := strings.TrimSpace(p.lines[p.pos])
+		if t != "" && !strings.HasPrefix(t, "#") {
+			return true
+		}
+		p.pos++
+	}
+	return false
+}
+
+func (p *parser) indent() int {
+	return indentOf(p.lines[p.pos])
+}
+
+// content is the current line without indentation or trailing comment.
+func (p *parser) content() string {
+	line := p.lines[p.pos]
+	return stripComment(line[indentOf(line):])
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// block parses the node starting at the next content line if it is indented at least min.
+func (p *parser) block(min int) (interface{}, error) {
+	if !p.next() || p.indent() < min {
+		return nil, nil
+	}
+	ind := p.indent()
+	c := p.content()
+	switch {
+	case c == "-" || strings.HasPrefix(c, "- "):
+		return p.sequence(ind)
+	case mapColon(c) >= 0:
+		return p.mapping(ind)
+	}
+	p.pos++
+	return p.value(c, ind-1)
+}
+
+func (p *parser) sequence(ind int) (interface{}, error) {
+	list := []interface{}{}
+	for p.next() && p.indent() == ind {
+		c := p.content()
+		if c != "-" && !strings.HasPrefix(c, "- ") {
+			// Ends a sequence nested at its parent key's indentation.
+			break
+		}
+		rest := strings.TrimLeft(c[1:], " ")
+		var item interface{}
+		var err error
+		if rest == "" {
+			p.pos++
+			item, err = p.block(ind + 1)
+		} else {
+			// Re-read the item body as if it started on its own, more indented line.
+			line := p.lines[p.pos]
+			col := ind + 1 + (len(line[ind+1:]) - len(strings.TrimLeft(line[ind+1:], " ")))
+			p.lines[p.pos] = strings.Repeat(" ", col) + line[col:]
+			item, err = p.block(ind + 1)
+		}
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, item)
+	}
+	if p.next() && p.indent() > ind {
+		return nil, p.errorf("unexpected indentation")
+	}
+	return list, nil
+}
+
+func (p *parser) mapping(ind int) (interface{}, error) {
+	m := map[string]interface{}{}
+	for p.next() && p.indent() == ind {
+		c := p.content()
+		colon := mapColon(c)
+		if colon < 0 {
+			return nil, p.errorf("expected a mapping key")
+		}
+		k, err := p.key(strings.TrimSpace(c[:colon]))
+		if err != nil {
+			return nil, err
+		}
+		if _, dup := m[k]; dup {
+			return nil, p.errorf("duplicate key %q", k)
+		}
+		rest := strings.TrimSpace(c[colon+1:])
+		p.pos++
+		var v interface{}
+		switch {
+		case rest != "":
+			v, err = p.value(rest, ind)
+		case p.next() && p.indent() == ind && (p.content() == "-" || strings.HasPrefix(p.content(), "- ")):
+			// A sequence may sit at its key's indentation.
+			v, err = p.sequence(ind)
+		default:
+			v, err = p.block(ind + 1)
+		}
+		if err != nil {
+			return nil, err
+		}
+		m[k] = v
+	}
+	if p.next() && p.indent() > ind {
+		return nil, p.errorf("unexpected indentation")
+	}
+	return m, nil
+}
+
+func (p *parser) key(raw string) (string, error) {
+	if raw == "" {
+		return "", p.errorf("empty mapping key")
+	}
+	if raw[0] == '"' || raw[0] == '\'' {
+		s, n, err := quoted(raw)
+		if err != nil || n != len(raw) {
+			return "", p.errorf("invalid quoted key %s", raw)
+		}
+		return s, nil
+	}
+	return raw, nil
+}
+
+// value parses an inline value that began on the previous line; parent is the
+// indentation of its key, which continuation lines must exceed.
+func (p *parser) value(s string, parent int) (interface{}, error) {
+	switch s[0] {
+	case '|', '>':
+		return p.blockScalar(s, parent)
+	case '[', '{':
+		// Flow collections may continue on following lines until balanced.
+		for !balanced(s) && p.pos < len(p.lines) {
+			s += " " + strings.TrimSpace(stripComment(p.lines[p.pos]))
+			p.pos++
+		}
+		f := &flow{s: s}
+		v, err := f.value()
+		if err == nil {
+			f.space()
+			if f.i < len(f.s) {
+				err = fmt.Errorf("unexpected %q after flow collection", f.s[f.i:])
+			}
+		}
+		if err != nil {
+			p.pos--
+			return nil, p.errorf("%v", err)
+		}
+		return v, nil
+	case '"', '\'':
+		str, n, err := quoted(s)
+		if err != nil {
+			p.pos--
+			return nil, p.errorf("%v", err)
+		}
+		if rest := strings.TrimSpace(s[n:]); rest != "" {
+			p.pos--
+			return nil, p.errorf("unexpected %q after quoted scalar", rest)
+		}
+		return str, nil
+	case '&', '*', '!':
+		p.pos--
+		return nil, p.errorf("anchors, aliases, and tags are not supported")
+	}
+	// Plain scalars may continue on more-indented lines, folded with spaces.
+	for p.next() && p.indent() > parent && mapColon(p.content()) < 0 && !strings.HasPrefix(p.content(), "- ") {
+		s += " " + strings.TrimSpace(p.content())
+		p.pos++
+	}
+	return resolvePlain(s), nil
+}
+
+// blockScalar reads a literal (|) or folded (>) scalar whose lines are indented past parent.
+func (p *parser) blockScalar(header string, parent int) (interface{}, error) {
+	style, chomp := header[0], byte(0)
+	for _, c := range strings.TrimSpace(header[1:]) {
+		switch c {
+		case '-', '+':
+			chomp = byte(c)
+		default:
+			p.pos--
+			return nil, p.errorf("unsupported block scalar header %q", header)
+		}
+	}
+	var lines []string
+	contentIndent := -1
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if strings.TrimSpace(line) == "" {
+			lines = append(lines, "")
+			p.pos++
+			continue
+		}
+		ind := indentOf(line)
+		if ind <= parent {
+			break
+		}
+		if contentIndent < 0 {
+			contentIndent = ind
+		}
+		if ind < contentIndent {
+			return nil, p.errorf("block scalar line is less indented than its first line")
+		}
+		lines = append(lines, line[contentIndent:])
+		p.pos++
+	}
+	// Trailing blank lines belong to chomping, not content.
+	trailing := 0
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+		trailing++
+	}
+	var body string
+	if style == '|' {
+		body = strings.Join(lines, "\n")
+	} else {
+		body = fold(lines)
+	}
+	switch chomp {
+	case '-':
+	case '+':
+		if len(lines) > 0 {
+			body += "\n"
+		}
+		body += strings.Repeat("\n", trailing)
+	default:
+		if len(lines) > 0 {
+			body += "\n"
+		}
+	}
+	return body, nil
+}
+
+// fold joins lines with spaces; blank lines and more-indented lines keep their breaks.
+func fold(lines []string) string {
+	var b strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			prev := lines[i-1]
+			switch {
+			case line == "":
+				b.WriteByte('\n')
+			case prev == "":
+				// The blank line before already became the break.
+			case strings.HasPrefix(line, " ") || strings.HasPrefix(prev, " "):
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// mapColon returns the index of the ':' that ends a mapping key, or -1.
+func mapColon(s string) int {
+	if s == "" || s[0] == '[' || s[0] == '{' || s[0] == '|' || s[0] == '>' {
+		return -1
+	}
+	start := 0
+	if s[0] == '"' || s[0] == '\'' {
+		_, n, err := quoted(s)
+		if err != nil {
+			return -1
+		}
+		start = n
+	}
+	for i := start; i < len(s); i++ {
+		if s[i] == ':' && (i+1 == len(s) || s[i+1] == ' ') {
+			return i
+		}
+		if start > 0 && s[i] != ' ' {
+			return -1
+		}
+	}
+	return -1
+}
+
+// stripComment removes a trailing "# comment" outside quotes.
+func stripComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			if i == 0 || strings.ContainsRune(" [{,:-", rune(s[i-1])) {
+				quote = c
+			}
+		case c == '#' && (i == 0 || s[i-1] == ' '):
+			return strings.TrimRight(s[:i], " ")
+		}
+	}
+	return strings.TrimRight(s, " ")
+}
+
+func balanced(s string) bool {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+	return depth <= 0
+}
+
+// quoted decodes the single- or double-quoted scalar at the start of s and
+// returns it with the number of bytes consumed.
+func quoted(s string) (string, int, error) {
+	q := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case q == '"' && s[i] == '\\':
+			i++
+		case s[i] == q:
+			if q == '\'' {
+				if i+1 < len(s) && s[i+1] == '\'' {
+					i++
+					continue
+				}
+				return strings.ReplaceAll(s[1:i], "''", "'"), i + 1, nil
+			}
+			v, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", 0, fmt.Errorf("invalid double-quoted scalar %s", s[:i+1])
+			}
+			return v, i + 1, nil
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated quoted scalar")
+}
+
+// flow parses flow collections such as [a, b] and {k: v}.
+type flow struct {
+	s string
+	i int
+}
+
+func (f *flow) space() {
+	for f.i < len(f.s) && f.s[f.i] == ' ' {
+		f.i++
+	}
+}
+
+func (f *flow) value() (interface{}, error) {
+	f.space()
+	if f.i >= len(f.s) {
+		return nil, fmt.Errorf("unexpected end of flow collection")
+	}
+	switch f.s[f.i] {
+	case '[':
+		f.i++
+		list := []interface{}{}
+		for {
+			f.space()
+			if f.i < len(f.s) && f.s[f.i] == ']' {
+				f.i++
+				return list, nil
+			}
+			v, err := f.value()
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+			if err := f.sep(']'); err != nil {
+				return nil, err
+			}
+		}
+	case '{':
+		f.i++
+		m := map[string]interface{}{}
+		for {
+			f.space()
+			if f.i < len(f.s) && f.s[f.i] == '}' {
+				f.i++
+				return m, nil
+			}
+			k, err := f.value()
+			if err != nil {
+				return nil, err
+			}
+			f.space()
+			var v interface{}
+			if f.i < len(f.s) && f.s[f.i] == ':' {
+				f.i++
+				if v, err = f.value(); err != nil {
+					return nil, err
+				}
+			}
+			m[fmt.Sprint(k)] = v
+			if err := f.sep('}'); err != nil {
+				return nil, err
+			}
+		}
+	case '"', '\'':
+		v, n, err := quoted(f.s[f.i:])
+		if err != nil {
+			return nil, err
+		}
+		f.i += n
+		return v, nil
+	}
+	start := f.i
+	for f.i < len(f.s) {
+		c := f.s[f.i]
+		if c == ',' || c == ']' || c == '}' || (c == ':' && (f.i+1 == len(f.s) || strings.ContainsRune(" ,]}", rune(f.s[f.i+1])))) {
+			break
+		}
+		f.i++
+	}
+	return resolvePlain(strings.TrimSpace(f.s[start:f.i])), nil
+}
+
+// sep consumes a ',' or peeks the closing delimiter.
+func (f *flow) sep(end byte) error {
+	f.space()
+	if f.i >= len(f.s) {
+		return fmt.Errorf("unterminated flow collection")
+	}
+	switch f.s[f.i] {
+	case ',':
+		f.i++
+		return nil
+	case end:
+		return nil
+	}
+	return fmt.Errorf("expected ',' or %q in flow collection", end)
+}
@@ -0,0 +1,288 @@
+// Package yaml reads and writes the YAML subset used by wiro's config and spec
+// files: block mappings and sequences, flow collections, plain and quoted
+// scalars, literal (|) and folded (>) block scalars, and comments. Anchors,
+// tags, and multi-document streams are not supported.
+//
+// Values map onto Go types through encoding/json, so struct fields use their
+// json tags and key order follows the struct definition.
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Unmarshal parses YAML into v using v's json tags.
+func Unmarshal(data []byte, v interface{}) error {
+	doc, err := Parse(data)
+	if err != nil {
+		return err
+	}
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+// Marshal renders v as YAML. v is first encoded with encoding/json, so json
+// tags and omitempty apply; object keys keep their JSON order.
+func Marshal(v interface{}) ([]byte, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	node, err := decodeOrdered(dec)
+	if err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	switch n := node.(type) {
+	case orderedMap:
+		if len(n) == 0 {
+			b.WriteString("{}\n")
+		} else {
+			writeMap(&b, n, 0)
+		}
+	case []interface{}:
+		if len(n) == 0 {
+			b.WriteString("[]\n")
+		} else {
+			writeSeq(&b, n, 0)
+		}
+	default:
+		b.WriteString(scalar(n, 0))
+		b.WriteByte('\n')
+	}
+	return []byte(b.String()), nil
+}
+
+// orderedMap keeps JSON object keys in their encoded order.
+type orderedMap []struct {
+	Key   string
+	Value interface{}
+}
+
+func decodeOrdered(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			m := orderedMap{}
+			for dec.More() {
+				kt, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				val, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				m = append(m, struct {
+					Key   string
+					Value interface{}
+				}{kt.(string), val})
+			}
+			_, err := dec.Token()
+			return m, err
+		case '[':
+			list := []interface{}{}
+			for dec.More() {
+				val, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, val)
+			}
+			_, err := dec.Token()
+			return list, err
+		}
+	}
+	return tok, nil
+}
+
+// canInline reports whether v renders on the same line as its key or dash.
+func canInline(v interface{}) bool {
+	switch n := v.(type) {
+	case orderedMap:
+		return len(n) == 0
+	case []interface{}:
+		return len(n) == 0
+	}
+	return true
+}
+
+func writeMap(b *strings.Builder, m orderedMap, indent int) {
+	pad := strings.Repeat("  ", indent)
+	for _, kv := range m {
+		b.WriteString(pad)
+		b.WriteString(key(kv.Key))
+		b.WriteByte(':')
+		writeValue(b, kv.Value, indent)
+	}
+}
+
+func writeSeq(b *strings.Builder, list []interface{}, indent int) {
+	pad := strings.Repeat("  ", indent)
+	for _, item := range list {
+		b.WriteString(pad)
+		b.WriteByte('-')
+		if m, ok := item.(orderedMap); ok && len(m) > 0 {
+			// The first key shares the dash line; the rest align under it.
+			var nested strings.Builder
+			writeMap(&nested, m, indent+1)
+			b.WriteByte(' ')
+			b.WriteString(strings.TrimPrefix(nested.String(), pad+"  "))
+			continue
+		}
+		writeValue(b, item, indent)
+	}
+}
+
+// writeValue writes what follows "key:" or "-".
+func writeValue(b *strings.Builder, v interface{}, indent int) {
+	if canInline(v) {
+		b.WriteByte(' ')
+		b.WriteString(inlineValue(v, indent+1))
+		b.WriteByte('\n')
+		return
+	}
+	b.WriteByte('\n')
+	switch n := v.(type) {
+	case orderedMap:
+		writeMap(b, n, indent+1)
+	case []interface{}:
+		writeSeq(b, n, indent+1)
+	}
+}
+
+func inlineValue(v interface{}, indent int) string {
+	switch v.(type) {
+	case orderedMap:
+		return "{}"
+	case []interface{}:
+		return "[]"
+	}
+	return scalar(v, indent)
+}
+
+func scalar(v interface{}, indent int) string {
+	switch n := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(n)
+	case json.Number:
+		return n.String()
+	case float64:
+		return strconv.FormatFloat(n, 'g', -1, 64)
+	case string:
+		return str(n, indent)
+	}
+	return str(fmt.Sprint(v), indent)
+}
+
+func key(k string) string {
+	if plainSafe(k) {
+		return k
+	}
+	return strconv.Quote(k)
+}
+
+// str renders a string plainly when it reads back unchanged, as a literal block
+// when it spans lines, and double-quoted otherwise.
+func str(s string, indent int) string {
+	if plainSafe(s) {
+		return s
+	}
+	if strings.Contains(s, "\n") && literalSafe(s) {
+		pad := strings.Repeat("  ", indent)
+		body := strings.TrimSuffix(s, "\n")
+		header := "|-"
+		if strings.HasSuffix(s, "\n") {
+			header = "|"
+		}
+		lines := strings.Split(body, "\n")
+		for i, line := range lines {
+			if line != "" {
+				lines[i] = pad + line
+			}
+		}
+		return header + "\n" + strings.Join(lines, "\n")
+	}
+	return strconv.Quote(s)
+}
+
+func literalSafe(s string) bool {
+	if strings.HasPrefix(s, " ") || strings.HasSuffix(s, "\n\n") || strings.ContainsAny(s, "\r\t") {
+		return false
+	}
+	for _, r := range s {
+		if r < 0x20 && r != '\n' {
+			return false
+		}
+	}
+	return true
+}
+
+func plainSafe(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s {
+		return false
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		// "-x" and similar are fine as long as no indicator space follows.
+		if !(len(s) > 1 && strings.ContainsAny(s[:1], "-?:") && s[1] != ' ') {
+			return false
+		}
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return false
+	}
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f {
+			return false
+		}
+	}
+	switch strings.ToLower(s) {
+	case "yes", "no", "on", "off", "y", "n":
+		// Booleans in YAML 1.1; quoted so older parsers read them back as strings.
+		return false
+	}
+	_, isString := resolvePlain(s).(string)
+	return isString
+}
+
+// resolvePlain types an unquoted scalar the way YAML 1.2's core schema does.
+func resolvePlain(s string) interface{} {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n
+	}
+	if strings.HasPrefix(s, "0x") {
+		if n, err := strconv.ParseInt(s[2:], 16, 64); err == nil {
+			return n
+		}
+	}
+	if strings.ContainsAny(s, "0123456789") && !strings.ContainsAny(s, "_xX") {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return s
+}
@@ -0,0 +1,139 @@
+package yaml
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	src := `# team defaults
+---
+model: wiro/flux   # pinned
+project: "design team"
+outputDir: '{workspace}/renders'
+count: 3
+scale: 1.5
+hdr: false
+empty:
+nothing: ~
+tags: [cat, "a, b", 7]
+opts: {steps: 30, name: x}
+prompt: |
+  a cat
+    on a mat
+
+  # not a comment
+folded: >-
+  one
+  two
+
+  three
+long: first part
+  second part
+files:
+  - cat.png
+  - path: dog.png
+    sha256: abc
+  -
+    - nested
+list_at_key_indent:
+- a
+- b
+"quoted key": yes
+`
+	got, err := Parse([]byte(src))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := map[string]interface{}{
+		"model":              "wiro/flux",
+		"project":            "design team",
+		"outputDir":          "{workspace}/renders",
+		"count":              int64(3),
+		"scale":              1.5,
+		"hdr":                false,
+		"empty":              nil,
+		"nothing":            nil,
+		"tags":               []interface{}{"cat", "a, b", int64(7)},
+		"opts":               map[string]interface{}{"steps": int64(30), "name": "x"},
+		"prompt":             "a cat\n  on a mat\n\n# not a comment\n",
+		"folded":             "one two\nthree",
+		"long":               "first part second part",
+		"files":              []interface{}{"cat.png", map[string]interface{}{"path": "dog.png", "sha256": "abc"}, []interface{}{"nested"}},
+		"list_at_key_indent": []interface{}{"a", "b"},
+		"quoted key":         "yes",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Parse mismatch\n got: %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]string{
+		"tab":       "a:\n\tb: 1\n",
+		"dup":       "a: 1\na: 2\n",
+		"indent":    "a: 1\n  b: 2\n",
+		"unclosed":  "a: [1, 2\n",
+		"anchor":    "a: &x 1\n",
+		"documents": "a: 1\n---\nb: 2\n",
+		"quote":     "a: \"open\n",
+	}
+	for name, src := range cases {
+		_, err := Parse([]byte(src))
+		var syn *SyntaxError
+		if !errors.As(err, &syn) {
+			t.Errorf("%s: err = %v, want SyntaxError", name, err)
+		}
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	type file struct {
+		Path   string `json:"path,omitempty"`
+		URL    string `json:"url,omitempty"`
+		SHA256 string `json:"sha256,omitempty"`
+	}
+	type spec struct {
+		Version int                    `json:"specVersion"`
+		Model   string                 `json:"model"`
+		Params  map[string]interface{} `json:"params"`
+		Files   map[string][]file      `json:"files,omitempty"`
+		Tags    []string               `json:"tags"`
+		Empty   map[string]string      `json:"empty"`
+	}
+	in := spec{
+		Version: 1,
+		Model:   "wiro/flux",
+		Params: map[string]interface{}{
+			"prompt": "line one\nline two: with colon\n",
+			"steps":  "30",
+			"flag":   "true",
+			"neg":    "-x",
+			"hash":   "a #b",
+			"note":   "yes",
+			"quote":  `say "hi"`,
+		},
+		Files: map[string][]file{"image": {{Path: "cat.png", SHA256: "ab12"}, {URL: "https://x.test/a.png"}}},
+		Tags:  []string{},
+		Empty: map[string]string{},
+	}
+	out, err := Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if !strings.HasPrefix(string(out), "specVersion: 1\nmodel: wiro/flux\nparams:\n") {
+		t.Fatalf("field order not kept:\n%s", out)
+	}
+	if !strings.Contains(string(out), "    - path: cat.png\n      sha256: ab12\n") {
+		t.Fatalf("sequence of mappings not compact:\n%s", out)
+	}
+	var back spec
+	if err := Unmarshal(out, &back); err != nil {
+		t.Fatalf("Unmarshal: %v\n%s", err, out)
+	}
+	if !reflect.DeepEqual(back, in) {
+		t.Fatalf("round trip mismatch\n got: %#v\nwant: %#v\nyaml:\n%s", back, in, out)
+	}
+}
@@ -3,6 +3,7 @@ set -euo pipefail
 
 ROOT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")/.." && pwd)"
 OUT_DIR="${1:-${ROOT_DIR}/dist}"
+VERSION="${VERSION:-dev}"
 
 mkdir -p "${OUT_DIR}"
 
@@ -16,7 +17,7 @@ build_one() {
   local out="${OUT_DIR}/wiro-${asset_os}-${arch_label}${ext}"
   echo "Building ${out}"
   CGO_ENABLED=0 GOOS="${goos}" GOARCH="${goarch}" \
-    go build -trimpath -o "${out}" "${ROOT_DIR}/cmd/wiro"
+    go build -trimpath -ldflags "-X github.com/wiro-ai/wiro-cli/internal/cli.Version=${VERSION}" -o "${out}" "${ROOT_DIR}/cmd/wiro"
 
   if [[ "${ext}" != ".exe" ]]; then
     chmod +x "${out}"
@@ -59,7 +59,7 @@ echo "==> Running tests"
 
 echo "==> Building release assets in ${OUT_DIR}"
 rm -rf "${OUT_DIR}"
-"${ROOT_DIR}/scripts/build-release-assets.sh" "${OUT_DIR}"
+VERSION="${VERSION}" "${ROOT_DIR}/scripts/build-release-assets.sh" "${OUT_DIR}"
 
 echo "==> Verifying assets"
 for asset in "${ASSETS[@]}"; do